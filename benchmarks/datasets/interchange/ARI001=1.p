@@ -0,0 +1,11 @@
+%------------------------------------------------------------------------------
+% File     : ARI001=1
+% Domain   : Arithmetic
+% Problem  : A replica count between 1 and 4 at half a core each fits in 2 cores
+% Status   : Theorem
+%------------------------------------------------------------------------------
+tff(replicas_type, type, replicas: $int).
+tff(cores_type, type, cores: $rat).
+tff(replica_range, axiom, $greatereq(replicas, 1) & $lesseq(replicas, 4)).
+tff(core_usage, axiom, cores = $quotient($to_rat(replicas), 2/1)).
+tff(fits, conjecture, $lesseq(cores, 2/1)).
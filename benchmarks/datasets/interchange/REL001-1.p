@@ -0,0 +1,10 @@
+%------------------------------------------------------------------------------
+% File     : REL001-1
+% Domain   : Relations
+% Problem  : A transitive relation over a chain of three elements
+% Status   : Unsatisfiable
+%------------------------------------------------------------------------------
+cnf(transitivity, axiom, ~ less(X, Y) | ~ less(Y, Z) | less(X, Z)).
+cnf(a_below_b, axiom, less(c1, c2)).
+cnf(b_below_c, axiom, less(c2, c3)).
+cnf(not_a_below_c, negated_conjecture, ~ less(c1, c3)).
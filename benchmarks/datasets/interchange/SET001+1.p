@@ -0,0 +1,9 @@
+%------------------------------------------------------------------------------
+% File     : SET001+1
+% Domain   : Set theory
+% Problem  : Every element of a subset of a subset is an element of the superset
+% Status   : Theorem
+%------------------------------------------------------------------------------
+fof(subset, axiom, ! [A, B] : (subset(A, B) <=> ! [X] : (member(X, A) => member(X, B)))).
+fof(subset_transitive, conjecture,
+    ! [A, B, C] : ((subset(A, B) & subset(B, C)) => subset(A, C))).
@@ -0,0 +1,9 @@
+%------------------------------------------------------------------------------
+% File     : SYL001+1
+% Domain   : Syllogistic
+% Problem  : Barbara: all men are mortal, Socrates is a man
+% Status   : Theorem
+%------------------------------------------------------------------------------
+fof(men_are_mortal, axiom, ! [X] : (man(X) => mortal(X))).
+fof(socrates_is_a_man, axiom, man(socrates)).
+fof(socrates_is_mortal, conjecture, mortal(socrates)).
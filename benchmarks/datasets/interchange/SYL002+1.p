@@ -0,0 +1,9 @@
+%------------------------------------------------------------------------------
+% File     : SYL002+1
+% Domain   : Syllogistic
+% Problem  : Undistributed middle: cats and dogs are both mammals
+% Status   : CounterSatisfiable
+%------------------------------------------------------------------------------
+fof(cats_are_mammals, axiom, ! [X] : (cat(X) => mammal(X))).
+fof(dogs_are_mammals, axiom, ! [X] : (dog(X) => mammal(X))).
+fof(cats_are_dogs, conjecture, ! [X] : (cat(X) => dog(X))).
@@ -0,0 +1,11 @@
+; Two pools share ten slots; the API pool needs six to eight, workers at least five
+(set-logic QF_LIA)
+(set-info :status unsat)
+(declare-const api Int)
+(declare-const worker Int)
+(assert (! (<= (+ api worker) 10) :named capacity))
+(assert (! (<= 6 api 8) :named api_pool))
+(assert (! (>= worker 5) :named worker_pool))
+(check-sat)
+(get-unsat-core)
+(exit)
@@ -0,0 +1,10 @@
+; An even number strictly between 4 and 6 does not exist over the integers
+(set-logic QF_LIA)
+(set-info :status unsat)
+(declare-const n Int)
+(declare-const k Int)
+(define-fun even () Bool (= n (* 2 k)))
+(assert even)
+(assert (and (> n 4) (< n 6)))
+(check-sat)
+(exit)
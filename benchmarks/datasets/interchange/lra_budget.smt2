@@ -0,0 +1,11 @@
+; A budget split between compute and storage at a 3:1 ratio
+(set-logic QF_LRA)
+(set-info :status sat)
+(declare-const compute Real)
+(declare-const storage Real)
+(assert (let ((total (+ compute storage))) (and (<= total 1000.0) (>= total 800.0))))
+(assert (= compute (* 3.0 storage)))
+(assert (>= storage 150.5))
+(check-sat)
+(get-model)
+(exit)
@@ -0,0 +1,12 @@
+; Affirming the consequent: TLS does not imply calling auth
+(set-logic UF)
+(set-info :status sat)
+(declare-sort Service 0)
+(declare-fun calls_auth (Service) Bool)
+(declare-fun uses_tls (Service) Bool)
+(declare-const billing Service)
+(assert (forall ((s Service)) (=> (calls_auth s) (uses_tls s))))
+(assert (uses_tls billing))
+(assert (not (calls_auth billing)))
+(check-sat)
+(exit)
@@ -0,0 +1,12 @@
+; Every service that calls auth uses TLS; payments calls auth
+(set-logic UF)
+(set-info :status unsat)
+(declare-sort Service 0)
+(declare-fun calls_auth (Service) Bool)
+(declare-fun uses_tls (Service) Bool)
+(declare-const payments Service)
+(assert (forall ((s Service)) (=> (calls_auth s) (uses_tls s))))
+(assert (calls_auth payments))
+(assert (not (uses_tls payments)))
+(check-sat)
+(exit)
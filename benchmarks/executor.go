@@ -49,6 +49,8 @@ func (e *DirectExecutor) Execute(problem *Problem, evaluator Evaluator) (*Result
 		response, err = e.executeProbabilistic(ctx, problem)
 	case "causal":
 		response, err = e.executeCausal(ctx, problem)
+	case "constraints":
+		response, err = e.executeConstraints(ctx, problem)
 	default:
 		// Fallback: use generic thought processing
 		response = problem.Description
@@ -89,6 +91,11 @@ func (e *DirectExecutor) Execute(problem *Problem, evaluator Evaluator) (*Result
 
 // executeLogic handles logic and reasoning problems using validation
 func (e *DirectExecutor) executeLogic(ctx context.Context, problem *Problem) (string, error) {
+	// Problems in TPTP or SMT-LIB carry their premises and conclusion
+	if format, ok := problem.Input["format"].(string); ok {
+		return e.executeTheoremProblem(format, problem)
+	}
+
 	// Extract premises and conclusion
 	premises, ok := problem.Input["premises"].([]interface{})
 	if !ok {
@@ -147,6 +154,79 @@ func (e *DirectExecutor) executeLogic(ctx context.Context, problem *Problem) (st
 	return "invalid", nil
 }
 
+// executeTheoremProblem proves a theorem read from a TPTP or SMT-LIB problem
+func (e *DirectExecutor) executeTheoremProblem(format string, problem *Problem) (string, error) {
+	text, _ := problem.Input["problem"].(string)
+
+	var imported *validation.ImportedTheorem
+	var err error
+	switch format {
+	case "tptp":
+		imported, err = validation.ParseTPTPTheorem(text)
+	case "smtlib":
+		imported, err = validation.ParseSMTLIBTheorem(text)
+	default:
+		return "", fmt.Errorf("unknown problem format %q", format)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	result := e.validator.Prove(imported.Theorem.Premises, imported.Theorem.Conclusion)
+	if result.IsProvable {
+		return "valid", nil
+	}
+	return "invalid", nil
+}
+
+// executeConstraints decides a constraint set read from a TPTP or SMT-LIB
+// problem, answering "sat", "unsat", or "unknown" when constraints outside
+// linear arithmetic leave a consistent verdict unconfirmed
+func (e *DirectExecutor) executeConstraints(ctx context.Context, problem *Problem) (string, error) {
+	format, _ := problem.Input["format"].(string)
+	text, _ := problem.Input["problem"].(string)
+
+	var constraints *validation.ConstraintProblem
+	var err error
+	switch format {
+	case "tptp":
+		constraints, err = validation.ParseTPTPConstraints(text)
+	case "smtlib":
+		constraints, err = validation.ParseSMTLIBConstraints(text)
+	default:
+		return "", fmt.Errorf("unknown problem format %q", format)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// A fresh reasoner keeps constraint IDs independent across problems
+	reasoner := validation.NewSymbolicReasoner()
+	for _, symbol := range constraints.Symbols {
+		reasoner.AddSymbol(symbol.Name, symbol.Type, symbol.Domain)
+	}
+	ids := make([]string, 0, len(constraints.Constraints))
+	for _, c := range constraints.Constraints {
+		added, err := reasoner.AddConstraint(c.Type, c.Expression, c.Symbols)
+		if err != nil {
+			return "", err
+		}
+		ids = append(ids, added.ID)
+	}
+
+	result, err := reasoner.CheckConstraintConsistency(ids)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case !result.IsConsistent:
+		return "unsat", nil
+	case len(result.Unchecked) > 0:
+		return "unknown", nil
+	}
+	return "sat", nil
+}
+
 // executeProbabilistic handles Bayesian reasoning problems
 func (e *DirectExecutor) executeProbabilistic(ctx context.Context, problem *Problem) (string, error) {
 	// Check if this is a medical test problem (prior, sensitivity, specificity)
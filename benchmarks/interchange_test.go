@@ -0,0 +1,63 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"unified-thinking/benchmarks/evaluators"
+	"unified-thinking/internal/storage"
+)
+
+func TestInterchangeProblems(t *testing.T) {
+	suite, err := LoadProblemDirectory("datasets/interchange")
+	if err != nil {
+		t.Fatalf("Failed to load problems: %v", err)
+	}
+
+	t.Logf("Loaded suite: %s with %d problems", suite.Name, len(suite.Problems))
+
+	executor := NewDirectExecutor(storage.NewMemoryStorage())
+	run, err := RunSuite(suite, evaluators.NewExactMatchEvaluator(), executor)
+	if err != nil {
+		t.Fatalf("Failed to run suite: %v", err)
+	}
+
+	for _, result := range run.Results {
+		if !result.Correct {
+			t.Errorf("%s: response %q, error %q", result.ProblemID, result.Response, result.Error)
+		}
+	}
+	t.Logf("Accuracy: %.2f%% (%d/%d)", run.OverallAccuracy*100, run.CorrectProblems, run.TotalProblems)
+}
+
+func TestLoadProblemDirectory(t *testing.T) {
+	suite, err := LoadProblemDirectory("datasets/interchange")
+	if err != nil {
+		t.Fatalf("Failed to load problems: %v", err)
+	}
+	expected := map[string]string{
+		"SYL001+1":     "valid",
+		"SYL002+1":     "invalid",
+		"ARI001=1":     "unsat",
+		"uf_converse":  "invalid",
+		"lia_capacity": "unsat",
+		"lra_budget":   "sat",
+	}
+	found := 0
+	for _, problem := range suite.Problems {
+		if want, ok := expected[problem.ID]; ok {
+			found++
+			if problem.Expected != want {
+				t.Errorf("%s: expected %v, want %s", problem.ID, problem.Expected, want)
+			}
+		}
+	}
+	if found != len(expected) {
+		t.Errorf("found %d of %d problems", found, len(expected))
+	}
+
+	for _, path := range []string{"/tmp", "datasets/../..", "datasets/reasoning"} {
+		if _, err := LoadProblemDirectory(path); err == nil {
+			t.Errorf("LoadProblemDirectory(%q) succeeded", path)
+		}
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
+
+	"unified-thinking/internal/validation"
 )
 
 // LoadSuite loads a benchmark suite from a JSON file
@@ -44,6 +47,115 @@ func LoadSuite(path string) (*BenchmarkSuite, error) {
 	return &suite, nil
 }
 
+// LoadProblemDirectory loads a suite from a directory of TPTP (.p, .tptp) and
+// SMT-LIB v2 (.smt2) problem files. Each file is a problem whose expected
+// answer is the status it declares: "valid" or "invalid" for theorems,
+// "sat" or "unsat" for arithmetic constraint sets (TPTP TFF, SMT-LIB
+// arithmetic logics). Files that declare no status are skipped.
+func LoadProblemDirectory(path string) (*BenchmarkSuite, error) {
+	cleanPath := filepath.Clean(path)
+	if filepath.IsAbs(cleanPath) {
+		return nil, fmt.Errorf("absolute paths not allowed")
+	}
+	if strings.Contains(cleanPath, "..") {
+		return nil, fmt.Errorf("path traversal not allowed")
+	}
+
+	entries, err := os.ReadDir(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read problem directory: %w", err)
+	}
+
+	suite := &BenchmarkSuite{
+		Name:        filepath.Base(cleanPath),
+		Description: fmt.Sprintf("TPTP and SMT-LIB problems in %s", cleanPath),
+		Category:    "interchange",
+	}
+	for _, entry := range entries {
+		format := problemFormats[strings.ToLower(filepath.Ext(entry.Name()))]
+		if entry.IsDir() || format == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(cleanPath, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read problem file: %w", err)
+		}
+		problem, err := interchangeProblem(entry.Name(), format, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if problem != nil {
+			suite.Problems = append(suite.Problems, problem)
+		}
+	}
+	if len(suite.Problems) == 0 {
+		return nil, fmt.Errorf("no problem files with a declared status in %s", cleanPath)
+	}
+	return suite, nil
+}
+
+// Problem file extensions and their formats
+var problemFormats = map[string]string{".p": "tptp", ".tptp": "tptp", ".smt2": "smtlib"}
+
+var smtArithmeticLogic = regexp.MustCompile(`\(set-logic\s+\w*(LIA|LRA|LIRA|IDL|RDL)\s*\)`)
+
+// interchangeProblem reads a problem file; nil if it declares no status
+func interchangeProblem(file, format, text string) (*Problem, error) {
+	problem := &Problem{
+		ID:          strings.TrimSuffix(file, filepath.Ext(file)),
+		Description: file,
+		Input:       map[string]interface{}{"format": format, "problem": text},
+		Category:    "reasoning",
+		Metadata:    map[string]interface{}{"file": file, "format": format},
+	}
+
+	arithmetic := strings.Contains(text, "tff(")
+	if format == "smtlib" {
+		arithmetic = smtArithmeticLogic.MatchString(text)
+	}
+	if arithmetic {
+		var constraints *validation.ConstraintProblem
+		var err error
+		if format == "tptp" {
+			constraints, err = validation.ParseTPTPConstraints(text)
+		} else {
+			constraints, err = validation.ParseSMTLIBConstraints(text)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if constraints.Status == "" {
+			return nil, nil
+		}
+		problem.Category = "constraints"
+		problem.Expected = constraints.Status
+		return problem, nil
+	}
+
+	var imported *validation.ImportedTheorem
+	var err error
+	if format == "tptp" {
+		imported, err = validation.ParseTPTPTheorem(text)
+	} else {
+		imported, err = validation.ParseSMTLIBTheorem(text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	expected, known := imported.ExpectsProof()
+	if !known {
+		return nil, nil
+	}
+	problem.Expected = "invalid"
+	if expected {
+		problem.Expected = "valid"
+	}
+	if imported.Theorem.Name != "" {
+		problem.Description = fmt.Sprintf("%s (%s)", file, imported.Theorem.Name)
+	}
+	return problem, nil
+}
+
 // RunSuite executes all problems in a suite
 func RunSuite(suite *BenchmarkSuite, evaluator Evaluator, executor ProblemExecutor) (*BenchmarkRun, error) {
 	run := &BenchmarkRun{
@@ -10,6 +10,7 @@ import (
 	"unified-thinking/internal/knowledge"
 	"unified-thinking/internal/modes"
 	"unified-thinking/internal/orchestration"
+	"unified-thinking/internal/replay"
 	"unified-thinking/internal/server"
 	"unified-thinking/internal/similarity"
 	"unified-thinking/internal/storage"
@@ -81,7 +82,11 @@ func InitializeServer() (*ServerComponents, error) {
 	if model == "" {
 		model = "voyage-3-lite"
 	}
-	components.Embedder = embeddings.NewVoyageEmbedder(apiKey, model)
+	replayStore, err := replay.StoreFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize LLM replay: %w", err)
+	}
+	components.Embedder = replayStore.WrapEmbedder(embeddings.NewVoyageEmbedder(apiKey, model))
 	components.AutoMode.SetEmbedder(components.Embedder)
 	log.Printf("Initialized Voyage AI embedder (model: %s)", model)
 
@@ -127,6 +132,13 @@ func InitializeServer() (*ServerComponents, error) {
 	}
 	components.Server = unifiedServer
 	components.Server.SetContextBridge(components.ContextBridge)
+
+	// Account the embedder and reranker usage against the server's budget
+	for _, client := range []any{components.Embedder, components.Reranker} {
+		if tracked, ok := client.(embeddings.UsageTracked); ok {
+			tracked.SetUsageTracker(unifiedServer.UsageTracker())
+		}
+	}
 	log.Println("Created unified server")
 
 	// Initialize knowledge graph - ALWAYS enabled, will FAIL if requirements not met
@@ -153,6 +165,19 @@ func InitializeServer() (*ServerComponents, error) {
 	// Register predefined workflows
 	registerPredefinedWorkflows(components.Orchestrator)
 
+	// Stored workflows replace predefined ones of the same ID
+	if err := components.Orchestrator.SetWorkflowStore(sqliteStore); err != nil {
+		return nil, fmt.Errorf("failed to initialize workflow registry: %w", err)
+	}
+	components.Orchestrator.SetRunStore(sqliteStore)
+	if dir := os.Getenv("WORKFLOWS_DIR"); dir != "" {
+		loaded, err := components.Orchestrator.LoadWorkflowDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load workflows from WORKFLOWS_DIR: %w", err)
+		}
+		log.Printf("Loaded %d workflow definitions from %s", len(loaded), dir)
+	}
+
 	success = true // Mark success to prevent defer from closing storage
 	return components, nil
 }
@@ -318,7 +343,7 @@ func registerPredefinedWorkflows(orchestrator *orchestration.Orchestrator) {
 				ID:   "check-syntax",
 				Tool: "check-syntax",
 				Input: types.Metadata{
-					"statement": "{{content}}",
+					"statements": "{{content}}",
 				},
 				DependsOn: []string{"detect-biases"},
 				StoreAs:   "syntax_check",
@@ -369,10 +394,9 @@ func registerPredefinedWorkflows(orchestrator *orchestration.Orchestrator) {
 				ID:   "make-decision",
 				Tool: "make-decision",
 				Input: types.Metadata{
-					"situation":    "{{situation}}",
-					"criteria":     "{{criteria}}",
-					"perspectives": "{{perspectives}}",
-					"sensitivity":  "{{sensitivity}}",
+					"question": "{{situation}}",
+					"options":  "{{options}}",
+					"criteria": "{{criteria}}",
 				},
 				DependsOn: []string{"analyze-perspectives", "sensitivity-analysis"},
 			},
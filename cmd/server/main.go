@@ -1,21 +1,30 @@
 // Package main provides the entry point for the Unified Thinking MCP server.
 //
-// This server is designed to be spawned as a child process by Claude Desktop
-// and communicates via stdio using the Model Context Protocol. It should not
-// be run manually by users.
+// By default the server is spawned as a child process by Claude Desktop and
+// communicates via stdio using the Model Context Protocol. With
+// --transport=http it instead runs as a long-lived Streamable HTTP server
+// (with an SSE fallback) that several editors and agents can share.
 //
 // The server consolidates multiple cognitive thinking patterns (linear, tree,
 // divergent, and auto modes) into a single Go-based MCP server, providing
 // 10 tools for thought processing, validation, search, and metrics.
 //
+// Flags:
+//   - --transport: "stdio" (default) or "http"
+//   - --addr: HTTP listen address (default 127.0.0.1:8080)
+//   - --session-timeout: idle HTTP session timeout (default 30m)
+//
 // Environment variables:
 //   - DEBUG: Set to "true" to enable debug logging
+//   - MCP_TRANSPORT, MCP_HTTP_ADDR, MCP_HTTP_SESSION_TIMEOUT: defaults for the flags above
 package main
 
 import (
 	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -27,6 +36,11 @@ func main() {
 		log.Println("Starting Unified Thinking Server in debug mode...")
 	}
 
+	transportCfg, err := ParseTransportConfig(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid transport configuration: %v", err)
+	}
+
 	// Initialize all server components
 	components, err := InitializeServer()
 	if err != nil {
@@ -49,14 +63,11 @@ func main() {
 	components.Server.RegisterTools(mcpServer)
 	log.Println("Registered tools: think, history, list-branches, focus-branch, branch-history, validate, prove, check-syntax, search, get-metrics, execute-workflow, list-workflows, register-workflow, and 21 additional reasoning tools")
 
-	// Create stdio transport
-	transport := &mcp.StdioTransport{}
-	log.Println("Created stdio transport")
-
-	// Run server
-	ctx := context.Background()
-	log.Println("Starting MCP server...")
-	if err := mcpServer.Run(ctx, transport); err != nil {
+	// Run server on the selected transport until interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("Starting MCP server (transport: %s)...", transportCfg.Transport)
+	if err := runServer(ctx, mcpServer, transportCfg); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Transport names accepted by --transport / MCP_TRANSPORT
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+)
+
+// Default HTTP settings
+const (
+	DefaultHTTPAddr           = "127.0.0.1:8080"
+	DefaultStreamablePath     = "/mcp"
+	DefaultSSEPath            = "/sse"
+	DefaultHTTPSessionTimeout = 30 * time.Minute
+	httpShutdownTimeout       = 10 * time.Second
+)
+
+// TransportConfig selects how the MCP server is exposed to clients
+type TransportConfig struct {
+	// Transport is either "stdio" (default) or "http"
+	Transport string
+
+	// Addr is the HTTP listen address (http transport only)
+	Addr string
+
+	// StreamablePath is the route serving the Streamable HTTP transport
+	StreamablePath string
+
+	// SSEPath is the route serving the legacy SSE transport for older clients
+	SSEPath string
+
+	// SessionTimeout closes idle Streamable HTTP sessions (0 = never)
+	SessionTimeout time.Duration
+}
+
+// ParseTransportConfig reads transport settings from command-line arguments,
+// falling back to environment variables and then defaults.
+//
+// Flags:
+//   - --transport: stdio or http (env MCP_TRANSPORT)
+//   - --addr: HTTP listen address (env MCP_HTTP_ADDR)
+//   - --session-timeout: idle HTTP session timeout (env MCP_HTTP_SESSION_TIMEOUT)
+func ParseTransportConfig(args []string) (*TransportConfig, error) {
+	cfg := &TransportConfig{
+		Transport:      envOrDefault("MCP_TRANSPORT", TransportStdio),
+		Addr:           envOrDefault("MCP_HTTP_ADDR", DefaultHTTPAddr),
+		StreamablePath: DefaultStreamablePath,
+		SSEPath:        DefaultSSEPath,
+		SessionTimeout: DefaultHTTPSessionTimeout,
+	}
+
+	if v := os.Getenv("MCP_HTTP_SESSION_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP_HTTP_SESSION_TIMEOUT: %w", err)
+		}
+		cfg.SessionTimeout = d
+	}
+
+	fs := flag.NewFlagSet("unified-thinking", flag.ContinueOnError)
+	fs.StringVar(&cfg.Transport, "transport", cfg.Transport, "MCP transport: stdio or http")
+	fs.StringVar(&cfg.Addr, "addr", cfg.Addr, "listen address for the http transport")
+	fs.DurationVar(&cfg.SessionTimeout, "session-timeout", cfg.SessionTimeout, "idle session timeout for the http transport (0 disables)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the transport configuration
+func (c *TransportConfig) Validate() error {
+	switch c.Transport {
+	case TransportStdio:
+		return nil
+	case TransportHTTP:
+		if c.Addr == "" {
+			return fmt.Errorf("http transport requires a listen address")
+		}
+		if c.SessionTimeout < 0 {
+			return fmt.Errorf("session timeout must be non-negative")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown transport %q (expected %q or %q)", c.Transport, TransportStdio, TransportHTTP)
+	}
+}
+
+// NewHTTPHandler builds an HTTP handler serving the given MCP server over
+// Streamable HTTP, with the legacy SSE transport mounted as a fallback for
+// clients that predate Streamable HTTP. All sessions share the same server,
+// so tools registered via RegisterTools see a single storage backend.
+func NewHTTPHandler(mcpServer *mcp.Server, cfg *TransportConfig) http.Handler {
+	getServer := func(*http.Request) *mcp.Server { return mcpServer }
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.StreamablePath, mcp.NewStreamableHTTPHandler(getServer, &mcp.StreamableHTTPOptions{
+		SessionTimeout: cfg.SessionTimeout,
+	}))
+	mux.Handle(cfg.SSEPath, mcp.NewSSEHandler(getServer, nil))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+// runServer runs the MCP server on the configured transport until ctx is
+// cancelled or the transport fails.
+func runServer(ctx context.Context, mcpServer *mcp.Server, cfg *TransportConfig) error {
+	if cfg.Transport != TransportHTTP {
+		log.Println("Created stdio transport")
+		return mcpServer.Run(ctx, &mcp.StdioTransport{})
+	}
+
+	httpServer := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           NewHTTPHandler(mcpServer, cfg),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Serving MCP over HTTP on %s (streamable: %s, sse: %s)", cfg.Addr, cfg.StreamablePath, cfg.SSEPath)
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+		log.Println("Shutting down HTTP transport...")
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
+
+// envOrDefault returns the environment variable value or the fallback if unset
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
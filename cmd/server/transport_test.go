@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseTransportConfig_Defaults(t *testing.T) {
+	t.Setenv("MCP_TRANSPORT", "")
+	t.Setenv("MCP_HTTP_ADDR", "")
+	t.Setenv("MCP_HTTP_SESSION_TIMEOUT", "")
+
+	cfg, err := ParseTransportConfig(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Transport != TransportStdio {
+		t.Errorf("Transport = %q, want %q", cfg.Transport, TransportStdio)
+	}
+	if cfg.Addr != DefaultHTTPAddr {
+		t.Errorf("Addr = %q, want %q", cfg.Addr, DefaultHTTPAddr)
+	}
+	if cfg.SessionTimeout != DefaultHTTPSessionTimeout {
+		t.Errorf("SessionTimeout = %v, want %v", cfg.SessionTimeout, DefaultHTTPSessionTimeout)
+	}
+}
+
+func TestParseTransportConfig_FlagsOverrideEnv(t *testing.T) {
+	t.Setenv("MCP_TRANSPORT", "stdio")
+	t.Setenv("MCP_HTTP_ADDR", "127.0.0.1:1111")
+	t.Setenv("MCP_HTTP_SESSION_TIMEOUT", "5m")
+
+	cfg, err := ParseTransportConfig([]string{"--transport=http", "--addr=0.0.0.0:9090"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Transport != TransportHTTP {
+		t.Errorf("Transport = %q, want %q", cfg.Transport, TransportHTTP)
+	}
+	if cfg.Addr != "0.0.0.0:9090" {
+		t.Errorf("Addr = %q, want 0.0.0.0:9090", cfg.Addr)
+	}
+	if cfg.SessionTimeout != 5*time.Minute {
+		t.Errorf("SessionTimeout = %v, want 5m", cfg.SessionTimeout)
+	}
+}
+
+func TestParseTransportConfig_Invalid(t *testing.T) {
+	t.Setenv("MCP_TRANSPORT", "")
+	t.Setenv("MCP_HTTP_SESSION_TIMEOUT", "")
+
+	if _, err := ParseTransportConfig([]string{"--transport=websocket"}); err == nil {
+		t.Error("expected error for unknown transport")
+	}
+
+	t.Setenv("MCP_HTTP_SESSION_TIMEOUT", "soon")
+	if _, err := ParseTransportConfig(nil); err == nil {
+		t.Error("expected error for invalid session timeout")
+	}
+}
+
+type echoInput struct {
+	Text string `json:"text"`
+}
+
+type echoOutput struct {
+	Text string `json:"text"`
+}
+
+func newEchoServer() *mcp.Server {
+	s := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mcp.AddTool(s, &mcp.Tool{Name: "echo", Description: "echo input"},
+		func(ctx context.Context, req *mcp.CallToolRequest, in echoInput) (*mcp.CallToolResult, *echoOutput, error) {
+			return nil, &echoOutput{Text: in.Text}, nil
+		})
+	return s
+}
+
+func TestNewHTTPHandler_SharedServerAcrossTransports(t *testing.T) {
+	cfg := &TransportConfig{
+		Transport:      TransportHTTP,
+		Addr:           "127.0.0.1:0",
+		StreamablePath: DefaultStreamablePath,
+		SSEPath:        DefaultSSEPath,
+	}
+	ts := httptest.NewServer(NewHTTPHandler(newEchoServer(), cfg))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	transports := map[string]mcp.Transport{
+		"streamable": &mcp.StreamableClientTransport{Endpoint: ts.URL + DefaultStreamablePath},
+		"sse":        &mcp.SSEClientTransport{Endpoint: ts.URL + DefaultSSEPath},
+	}
+
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			client := mcp.NewClient(&mcp.Implementation{Name: "client-" + name, Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, transport, nil)
+			if err != nil {
+				t.Fatalf("connect failed: %v", err)
+			}
+			defer session.Close()
+
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "echo",
+				Arguments: map[string]any{"text": name},
+			})
+			if err != nil {
+				t.Fatalf("CallTool failed: %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("tool returned error: %+v", result.Content)
+			}
+		})
+	}
+}
+
+func TestRunServer_HTTPShutdown(t *testing.T) {
+	cfg := &TransportConfig{
+		Transport:      TransportHTTP,
+		Addr:           "127.0.0.1:0",
+		StreamablePath: DefaultStreamablePath,
+		SSEPath:        DefaultSSEPath,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- runServer(ctx, newEchoServer(), cfg) }()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("runServer returned error on shutdown: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runServer did not stop after context cancellation")
+	}
+}
@@ -108,6 +108,8 @@ const (
 	ErrMaxIterationsReached = "ERR_5005_MAX_ITERATIONS_REACHED"
 	// ErrQuotaExceeded indicates a quota has been exceeded
 	ErrQuotaExceeded = "ERR_5006_QUOTA_EXCEEDED"
+	// ErrBudgetExhausted indicates the token or cost budget has been used up
+	ErrBudgetExhausted = "ERR_5007_BUDGET_EXHAUSTED"
 )
 
 // ErrorCategory returns the category name for an error code
@@ -258,6 +258,16 @@ func (g *RecoveryGenerator) registerDefaults() {
 		[]string{"synthesize-insights", "got-aggregate"},
 		nil,
 	)
+
+	g.register(ErrBudgetExhausted,
+		[]string{
+			"Use 'get-metrics' to see which tools and sessions consumed the budget",
+			"Complete the reasoning session and start a new one if the session budget is exhausted",
+			"Raise the USAGE_BUDGET_* limits and restart the server",
+		},
+		[]string{"get-metrics", "complete-reasoning-session"},
+		map[string]any{"tool": "get-metrics", "params": map[string]any{}},
+	)
 }
 
 // register adds recovery information for an error code
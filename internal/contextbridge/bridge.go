@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"unified-thinking/internal/embeddings"
+	"unified-thinking/internal/storage"
 	"unified-thinking/internal/types"
 )
 
@@ -95,6 +96,9 @@ func (cb *ContextBridge) EnrichResponse(
 
 	// Check cache
 	cacheKey := sig.Fingerprint
+	if sig.Workspace != "" {
+		cacheKey = storage.NormalizeWorkspace(sig.Workspace) + ":" + cacheKey
+	}
 	if cached := cb.cache.Get(cacheKey); cached != nil {
 		cb.metrics.RecordCacheHit()
 		elapsed := time.Since(start).Milliseconds()
@@ -11,6 +11,12 @@ type SignatureStorage interface {
 	FindCandidatesWithSignatures(domain string, fingerprintPrefix string, limit int) ([]*CandidateWithSignature, error)
 }
 
+// WorkspaceSignatureStorage is implemented by storage that can restrict
+// candidates to a single workspace
+type WorkspaceSignatureStorage interface {
+	FindCandidatesInWorkspace(workspace string, domain string, fingerprintPrefix string, limit int) ([]*CandidateWithSignature, error)
+}
+
 // Matcher finds similar trajectories based on signatures
 type Matcher struct {
 	storage    SignatureStorage
@@ -48,7 +54,13 @@ func (m *Matcher) FindMatches(sig *Signature, minSimilarity float64, maxMatches
 	}
 
 	// Single query returns candidates WITH their signatures
-	candidates, err := m.storage.FindCandidatesWithSignatures(sig.Domain, prefix, 50)
+	var candidates []*CandidateWithSignature
+	var err error
+	if scoped, ok := m.storage.(WorkspaceSignatureStorage); ok && sig.Workspace != "" {
+		candidates, err = scoped.FindCandidatesInWorkspace(sig.Workspace, sig.Domain, prefix, 50)
+	} else {
+		candidates, err = m.storage.FindCandidatesWithSignatures(sig.Domain, prefix, 50)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find candidates: %w", err)
 	}
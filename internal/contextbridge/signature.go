@@ -101,6 +101,11 @@ func ExtractSignature(toolName string, params map[string]interface{}, extractor
 		sig.Domain = domain
 	}
 
+	// Extract workspace if present
+	if workspace, ok := params["workspace"].(string); ok {
+		sig.Workspace = workspace
+	}
+
 	// Estimate complexity based on content length and concept count
 	wordCount := len(strings.Fields(textContent))
 	conceptCount := len(sig.KeyConcepts)
@@ -16,7 +16,16 @@ func NewStorageAdapter(sqlite *storage.SQLiteStorage) *StorageAdapter {
 
 // FindCandidatesWithSignatures implements SignatureStorage interface
 func (a *StorageAdapter) FindCandidatesWithSignatures(domain string, fingerprintPrefix string, limit int) ([]*CandidateWithSignature, error) {
-	storageCandidates, err := a.sqlite.FindCandidatesWithSignatures(domain, fingerprintPrefix, limit)
+	return a.findCandidates(a.sqlite, domain, fingerprintPrefix, limit)
+}
+
+// FindCandidatesInWorkspace implements WorkspaceSignatureStorage interface
+func (a *StorageAdapter) FindCandidatesInWorkspace(workspace string, domain string, fingerprintPrefix string, limit int) ([]*CandidateWithSignature, error) {
+	return a.findCandidates(a.sqlite.WorkspaceView(workspace), domain, fingerprintPrefix, limit)
+}
+
+func (a *StorageAdapter) findCandidates(sqlite *storage.SQLiteStorage, domain string, fingerprintPrefix string, limit int) ([]*CandidateWithSignature, error) {
+	storageCandidates, err := sqlite.FindCandidatesWithSignatures(domain, fingerprintPrefix, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +41,7 @@ func (a *StorageAdapter) FindCandidatesWithSignatures(domain string, fingerprint
 				KeyConcepts:  sc.Signature.KeyConcepts,
 				ToolSequence: sc.Signature.ToolSequence,
 				Complexity:   sc.Signature.Complexity,
+				Workspace:    sc.Signature.Workspace,
 			}
 		}
 
@@ -57,6 +67,7 @@ func (a *StorageAdapter) StoreContextSignature(trajectoryID string, sig *Signatu
 		KeyConcepts:  sig.KeyConcepts,
 		ToolSequence: sig.ToolSequence,
 		Complexity:   sig.Complexity,
+		Workspace:    sig.Workspace,
 	}
 
 	return a.sqlite.StoreContextSignature(trajectoryID, storageSig)
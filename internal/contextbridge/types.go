@@ -9,6 +9,7 @@ type Signature struct {
 	ToolSequence []string  `json:"tool_sequence"`
 	Complexity   float64   `json:"complexity"`
 	Embedding    []float32 `json:"embedding,omitempty"` // Semantic embedding for similarity
+	Workspace    string    `json:"workspace,omitempty"` // Only match trajectories from this workspace
 }
 
 // CandidateWithSignature combines trajectory metadata with its signature
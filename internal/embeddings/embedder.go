@@ -6,6 +6,8 @@ import (
 	"os"
 	"strconv"
 	"time"
+
+	"unified-thinking/internal/usage"
 )
 
 // Embedder generates vector embeddings from text
@@ -26,6 +28,12 @@ type Embedder interface {
 	Provider() string
 }
 
+// UsageTracked is implemented by embedders and rerankers that report their
+// token usage
+type UsageTracked interface {
+	SetUsageTracker(tracker *usage.Tracker)
+}
+
 // MultimodalInputType defines the type of multimodal content
 type MultimodalInputType string
 
@@ -1,10 +1,8 @@
 package embeddings
 
 import (
-	"context"
 	"math"
 	"os"
-	"sync"
 	"testing"
 	"time"
 )
@@ -414,111 +412,6 @@ func BenchmarkDeserializeFloat32(b *testing.B) {
 	}
 }
 
-// Test token bucket rate limiter
-
-func TestTokenBucketLimiter_Basic(t *testing.T) {
-	// Create limiter with 10 tokens/sec, burst of 5
-	limiter := newTokenBucketLimiter(10, 5)
-
-	ctx := context.Background()
-
-	// Should be able to get 5 tokens immediately (burst)
-	for i := 0; i < 5; i++ {
-		err := limiter.Wait(ctx)
-		if err != nil {
-			t.Errorf("expected no error on token %d, got %v", i, err)
-		}
-	}
-}
-
-func TestTokenBucketLimiter_ContextCancellation(t *testing.T) {
-	// Create limiter with very low rate
-	limiter := newTokenBucketLimiter(1, 0)
-
-	// Use a cancelled context
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
-
-	err := limiter.Wait(ctx)
-	if err != context.Canceled {
-		t.Errorf("expected context.Canceled, got %v", err)
-	}
-}
-
-func TestTokenBucketLimiter_Timeout(t *testing.T) {
-	// Create limiter with very low rate
-	limiter := newTokenBucketLimiter(1, 0)
-
-	// Use a context with short timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
-	defer cancel()
-
-	err := limiter.Wait(ctx)
-	if err != context.DeadlineExceeded {
-		t.Errorf("expected context.DeadlineExceeded, got %v", err)
-	}
-}
-
-func TestTokenBucketLimiter_Refill(t *testing.T) {
-	// Create limiter with 100 tokens/sec, burst of 1
-	limiter := newTokenBucketLimiter(100, 1)
-
-	ctx := context.Background()
-
-	// Use the initial token
-	err := limiter.Wait(ctx)
-	if err != nil {
-		t.Errorf("expected no error on first token, got %v", err)
-	}
-
-	// Wait a bit for refill
-	time.Sleep(20 * time.Millisecond)
-
-	// Should be able to get another token after refill
-	start := time.Now()
-	err = limiter.Wait(ctx)
-	elapsed := time.Since(start)
-
-	if err != nil {
-		t.Errorf("expected no error after refill, got %v", err)
-	}
-
-	// Should have been nearly instant (< 20ms) since we waited for refill
-	if elapsed > 20*time.Millisecond {
-		t.Errorf("expected fast response after refill, took %v", elapsed)
-	}
-}
-
-func TestTokenBucketLimiter_Concurrent(t *testing.T) {
-	// Create limiter with high rate for concurrent test
-	limiter := newTokenBucketLimiter(1000, 100)
-
-	ctx := context.Background()
-	var wg sync.WaitGroup
-	errCount := 0
-	var mu sync.Mutex
-
-	// Launch 50 concurrent waiters
-	for i := 0; i < 50; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			err := limiter.Wait(ctx)
-			if err != nil {
-				mu.Lock()
-				errCount++
-				mu.Unlock()
-			}
-		}()
-	}
-
-	wg.Wait()
-
-	if errCount > 0 {
-		t.Errorf("expected no errors, got %d", errCount)
-	}
-}
-
 func TestNewVoyageEmbedder_HasRateLimiter(t *testing.T) {
 	embedder := NewVoyageEmbedder("test-key", "voyage-3-lite")
 
@@ -526,13 +419,3 @@ func TestNewVoyageEmbedder_HasRateLimiter(t *testing.T) {
 		t.Error("expected rate limiter to be initialized")
 	}
 }
-
-func BenchmarkTokenBucketLimiter(b *testing.B) {
-	limiter := newTokenBucketLimiter(10000, 1000)
-	ctx := context.Background()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		limiter.Wait(ctx)
-	}
-}
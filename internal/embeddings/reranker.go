@@ -9,6 +9,9 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"unified-thinking/internal/ratelimit"
+	"unified-thinking/internal/usage"
 )
 
 // Voyage Rerank API constants
@@ -36,7 +39,8 @@ type VoyageReranker struct {
 	client      *http.Client
 	apiKey      string
 	model       string
-	rateLimiter *tokenBucketLimiter
+	rateLimiter *ratelimit.TokenBucket
+	usage       *usage.Tracker
 }
 
 // NewVoyageReranker creates a new Voyage AI reranker
@@ -51,10 +55,16 @@ func NewVoyageReranker(apiKey, model string) *VoyageReranker {
 		},
 		apiKey:      apiKey,
 		model:       model,
-		rateLimiter: newTokenBucketLimiter(defaultRateLimit, defaultBurstLimit),
+		rateLimiter: ratelimit.NewTokenBucket(defaultRateLimit, defaultBurstLimit),
 	}
 }
 
+// SetUsageTracker records the tokens of every request and rejects requests
+// once the tracker's budget is exhausted
+func (r *VoyageReranker) SetUsageTracker(tracker *usage.Tracker) {
+	r.usage = tracker
+}
+
 // rerankRequest represents the API request
 type rerankRequest struct {
 	Model           string   `json:"model"`
@@ -83,6 +93,10 @@ func (r *VoyageReranker) Rerank(ctx context.Context, query string, documents []s
 		return nil, fmt.Errorf("query is required for reranking")
 	}
 
+	if err := r.usage.Check(ctx); err != nil {
+		return nil, err
+	}
+
 	// Rate limiting
 	if err := r.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
@@ -133,6 +147,7 @@ func (r *VoyageReranker) Rerank(ctx context.Context, query string, documents []s
 	if err := json.Unmarshal(body, &rerankResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	r.usage.Record(ctx, r.model, rerankResp.Usage.TotalTokens, 0)
 
 	return rerankResp.Results, nil
 }
@@ -9,6 +9,8 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"unified-thinking/internal/ratelimit"
 )
 
 func TestNewVoyageReranker(t *testing.T) {
@@ -180,7 +182,7 @@ func TestVoyageReranker_Rerank_MockSuccess(t *testing.T) {
 		},
 		apiKey:      "test-key",
 		model:       "rerank-2",
-		rateLimiter: newTokenBucketLimiter(defaultRateLimit, defaultBurstLimit),
+		rateLimiter: ratelimit.NewTokenBucket(defaultRateLimit, defaultBurstLimit),
 	}
 
 	results, err := reranker.Rerank(context.Background(), "test query", []string{"doc1", "doc2", "doc3"}, 3)
@@ -219,7 +221,7 @@ func TestVoyageReranker_Rerank_APIError(t *testing.T) {
 		},
 		apiKey:      "invalid-key",
 		model:       "rerank-2",
-		rateLimiter: newTokenBucketLimiter(defaultRateLimit, defaultBurstLimit),
+		rateLimiter: ratelimit.NewTokenBucket(defaultRateLimit, defaultBurstLimit),
 	}
 
 	_, err := reranker.Rerank(context.Background(), "test query", []string{"doc1"}, 1)
@@ -254,7 +256,7 @@ func TestVoyageReranker_RerankWithDocuments(t *testing.T) {
 		},
 		apiKey:      "test-key",
 		model:       "rerank-2",
-		rateLimiter: newTokenBucketLimiter(defaultRateLimit, defaultBurstLimit),
+		rateLimiter: ratelimit.NewTokenBucket(defaultRateLimit, defaultBurstLimit),
 	}
 
 	results, err := reranker.RerankWithDocuments(context.Background(), "query", documents, 2)
@@ -295,7 +297,7 @@ func TestVoyageReranker_ContextCancellation(t *testing.T) {
 		},
 		apiKey:      "test-key",
 		model:       "rerank-2",
-		rateLimiter: newTokenBucketLimiter(defaultRateLimit, defaultBurstLimit),
+		rateLimiter: ratelimit.NewTokenBucket(defaultRateLimit, defaultBurstLimit),
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
@@ -382,7 +384,7 @@ func BenchmarkVoyageReranker_Rerank(b *testing.B) {
 		},
 		apiKey:      "test-key",
 		model:       "rerank-2",
-		rateLimiter: newTokenBucketLimiter(1000, 100), // High limits for benchmark
+		rateLimiter: ratelimit.NewTokenBucket(1000, 100), // High limits for benchmark
 	}
 
 	documents := []string{"doc1", "doc2", "doc3", "doc4", "doc5"}
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"sync"
 	"time"
+
+	"unified-thinking/internal/ratelimit"
+	"unified-thinking/internal/usage"
 )
 
 // VoyageAI API constants
@@ -29,60 +31,10 @@ type VoyageEmbedder struct {
 	timeout   time.Duration
 
 	// Rate limiting
-	rateLimiter *tokenBucketLimiter
-}
+	rateLimiter *ratelimit.TokenBucket
 
-// tokenBucketLimiter implements a simple token bucket rate limiter
-type tokenBucketLimiter struct {
-	mu         sync.Mutex
-	tokens     float64
-	maxTokens  float64
-	refillRate float64 // tokens per second
-	lastRefill time.Time
-}
-
-// newTokenBucketLimiter creates a new token bucket rate limiter
-func newTokenBucketLimiter(ratePerSecond, burst int) *tokenBucketLimiter {
-	return &tokenBucketLimiter{
-		tokens:     float64(burst),
-		maxTokens:  float64(burst),
-		refillRate: float64(ratePerSecond),
-		lastRefill: time.Now(),
-	}
-}
-
-// Wait blocks until a token is available or context is cancelled
-func (l *tokenBucketLimiter) Wait(ctx context.Context) error {
-	for {
-		l.mu.Lock()
-		// Refill tokens based on elapsed time
-		now := time.Now()
-		elapsed := now.Sub(l.lastRefill).Seconds()
-		l.tokens += elapsed * l.refillRate
-		if l.tokens > l.maxTokens {
-			l.tokens = l.maxTokens
-		}
-		l.lastRefill = now
-
-		// Check if we have a token
-		if l.tokens >= 1 {
-			l.tokens--
-			l.mu.Unlock()
-			return nil
-		}
-
-		// Calculate wait time for next token
-		waitTime := time.Duration((1.0 - l.tokens) / l.refillRate * float64(time.Second))
-		l.mu.Unlock()
-
-		// Wait for token or context cancellation
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(waitTime):
-			// Continue loop to try again
-		}
-	}
+	// Token usage accounting, may be nil
+	usage *usage.Tracker
 }
 
 // NewVoyageEmbedder creates a new Voyage AI embedder
@@ -110,7 +62,7 @@ func NewVoyageEmbedder(apiKey, model string) *VoyageEmbedder {
 		model:       model,
 		dimension:   dim,
 		timeout:     30 * time.Second,
-		rateLimiter: newTokenBucketLimiter(defaultRateLimit, defaultBurstLimit),
+		rateLimiter: ratelimit.NewTokenBucket(defaultRateLimit, defaultBurstLimit),
 	}
 }
 
@@ -132,6 +84,12 @@ type voyageResponse struct {
 	} `json:"usage"`
 }
 
+// SetUsageTracker records the tokens of every request and rejects requests
+// once the tracker's budget is exhausted
+func (e *VoyageEmbedder) SetUsageTracker(tracker *usage.Tracker) {
+	e.usage = tracker
+}
+
 // Embed generates embedding for single text
 func (e *VoyageEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
 	embeddings, err := e.EmbedBatch(ctx, []string{text})
@@ -150,6 +108,10 @@ func (e *VoyageEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]fl
 		return nil, fmt.Errorf("no texts provided")
 	}
 
+	if err := e.usage.Check(ctx); err != nil {
+		return nil, err
+	}
+
 	// Wait for rate limiter before making request
 	if err := e.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
@@ -252,6 +214,7 @@ func (e *VoyageEmbedder) embedBatchOnce(ctx context.Context, texts []string) ([]
 	if err := json.Unmarshal(body, &voyageResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	e.usage.Record(ctx, e.model, voyageResp.Usage.TotalTokens, 0)
 
 	// Extract embeddings
 	embeddings := make([][]float32, len(voyageResp.Data))
@@ -70,6 +70,10 @@ func (e *VoyageMultimodalEmbedder) EmbedMultimodal(ctx context.Context, inputs [
 		return nil, fmt.Errorf("no inputs provided")
 	}
 
+	if err := e.usage.Check(ctx); err != nil {
+		return nil, err
+	}
+
 	// Wait for rate limiter
 	if err := e.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
@@ -162,6 +166,7 @@ func (e *VoyageMultimodalEmbedder) doMultimodalRequest(req *http.Request) ([]flo
 	if err := json.Unmarshal(body, &mmResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	e.usage.Record(req.Context(), e.multimodalModel, mmResp.Usage.TotalTokens, 0)
 
 	if len(mmResp.Data) == 0 {
 		return nil, fmt.Errorf("no embedding returned")
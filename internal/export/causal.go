@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+
+	"unified-thinking/internal/types"
+)
+
+// causalLinkColors colors causal links by direction of influence
+var causalLinkColors = map[string]string{
+	"positive":  "#2e7d32",
+	"negative":  "#c62828",
+	"nonlinear": "#1565c0",
+}
+
+// causalLinkSigns prefixes link strengths in edge labels
+var causalLinkSigns = map[string]string{
+	"positive":  "+",
+	"negative":  "-",
+	"nonlinear": "~",
+}
+
+// FromCausalGraph converts a causal graph into an exportable graph, laid out
+// left to right.
+//
+// Variables are drawn as ellipses; unobservable variables are dashed. Links
+// are colored by type (green positive, red negative, blue dashed nonlinear),
+// labeled with their signed strength, and drawn thick when strength >= 0.7.
+func FromCausalGraph(graph *types.CausalGraph) *Graph {
+	g := &Graph{ID: graph.ID, Title: graph.Description, LeftRight: true}
+	if g.Title == "" {
+		g.Title = graph.ID
+	}
+
+	known := make(map[string]bool, len(graph.Variables))
+	for _, v := range graph.Variables {
+		known[v.ID] = true
+
+		style := Style{Shape: ShapeEllipse, Fill: "#e3f2fd", Stroke: "#1565c0"}
+		if !v.Observable {
+			style.Fill, style.Stroke, style.Dashed = "#ffffff", "#757575", true
+		}
+
+		g.Nodes = append(g.Nodes, &Node{
+			ID:    v.ID,
+			Label: v.Name,
+			Style: style,
+			Data: map[string]interface{}{
+				"name":       v.Name,
+				"type":       v.Type,
+				"observable": v.Observable,
+			},
+		})
+	}
+
+	for _, l := range graph.Links {
+		if !known[l.From] || !known[l.To] {
+			continue
+		}
+
+		style := Style{Stroke: causalLinkColors[l.Type], Bold: l.Strength >= 0.7}
+		if l.Type == "nonlinear" {
+			style.Dashed = true
+		}
+
+		g.Edges = append(g.Edges, &Edge{
+			From:  l.From,
+			To:    l.To,
+			Label: fmt.Sprintf("%s%.2f", causalLinkSigns[l.Type], l.Strength),
+			Style: style,
+			Data: map[string]interface{}{
+				"type":       l.Type,
+				"strength":   l.Strength,
+				"confidence": l.Confidence,
+			},
+		})
+	}
+
+	return g
+}
+
+// RenderCausalGraph renders a causal graph in the given format
+func RenderCausalGraph(graph *types.CausalGraph, format Format) (string, error) {
+	if graph == nil {
+		return "", fmt.Errorf("causal graph is required")
+	}
+	return Render(FromCausalGraph(graph), format)
+}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderDOT renders a graph as a Graphviz digraph
+func renderDOT(g *Graph) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.ID))
+	if g.Title != "" {
+		fmt.Fprintf(&b, "  label=%s;\n  labelloc=t;\n", dotQuote(g.Title))
+	}
+	if g.LeftRight {
+		b.WriteString("  rankdir=LR;\n")
+	} else {
+		b.WriteString("  rankdir=TB;\n")
+	}
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(n.Label)}
+		if n.Style.Shape == ShapeEllipse {
+			attrs = append(attrs, "shape=ellipse")
+		}
+		styles := []string{"filled"}
+		if n.Style.Shape != ShapeEllipse {
+			styles = append([]string{"rounded"}, styles...)
+		}
+		if n.Style.Dashed {
+			styles = append(styles, "dashed")
+		}
+		if n.Style.Dashed || n.Style.Shape == ShapeEllipse {
+			attrs = append(attrs, "style="+dotQuote(strings.Join(styles, ",")))
+		}
+		if n.Style.Fill != "" {
+			attrs = append(attrs, "fillcolor="+dotQuote(n.Style.Fill))
+		}
+		if n.Style.Stroke != "" {
+			attrs = append(attrs, "color="+dotQuote(n.Style.Stroke))
+		}
+		if n.Style.Bold {
+			attrs = append(attrs, "penwidth=2")
+		}
+		if n.Style.Double {
+			attrs = append(attrs, "peripheries=2")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+
+	for _, e := range g.Edges {
+		attrs := []string{}
+		if e.Label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.Label))
+		}
+		if e.Style.Stroke != "" {
+			attrs = append(attrs, "color="+dotQuote(e.Style.Stroke), "fontcolor="+dotQuote(e.Style.Stroke))
+		}
+		if e.Style.Dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+		if e.Style.Bold {
+			attrs = append(attrs, "penwidth=2")
+		}
+		line := fmt.Sprintf("  %s -> %s", dotQuote(e.From), dotQuote(e.To))
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		b.WriteString(line + ";\n")
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes a DOT identifier or label, turning newlines into line breaks
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
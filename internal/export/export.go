@@ -0,0 +1,98 @@
+// Package export renders reasoning graphs as Graphviz DOT, Mermaid flowcharts
+// and GraphML so they can be pasted into pull requests and design documents.
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format identifies an export format
+type Format string
+
+const (
+	FormatDOT     Format = "dot"     // Graphviz DOT
+	FormatMermaid Format = "mermaid" // Mermaid flowchart
+	FormatGraphML Format = "graphml" // GraphML XML
+)
+
+// Formats lists the supported export formats
+var Formats = []Format{FormatDOT, FormatMermaid, FormatGraphML}
+
+// ParseFormat resolves a format name, case-insensitively. "graphviz" is
+// accepted as an alias for DOT.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "dot", "graphviz":
+		return FormatDOT, nil
+	case "mermaid":
+		return FormatMermaid, nil
+	case "graphml":
+		return FormatGraphML, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q (supported: dot, mermaid, graphml)", name)
+	}
+}
+
+// Shape is the outline used to draw a node
+type Shape string
+
+const (
+	ShapeBox     Shape = "box"     // Rounded rectangle
+	ShapeEllipse Shape = "ellipse" // Ellipse (stadium in Mermaid)
+)
+
+// Style describes how a node or edge is drawn. Renderers translate it into
+// the closest equivalent their format supports.
+type Style struct {
+	Shape  Shape  // Node outline, ShapeBox when empty
+	Fill   string // Node fill color (#rrggbb)
+	Stroke string // Border or line color (#rrggbb)
+	Dashed bool   // Dashed border or line
+	Bold   bool   // Thick border or line
+	Double bool   // Double border, used for terminal nodes
+}
+
+// Node is a vertex in an exportable graph
+type Node struct {
+	ID    string
+	Label string // May contain newlines
+	Style Style
+	Data  map[string]interface{} // Exported as GraphML data
+}
+
+// Edge is a directed edge in an exportable graph
+type Edge struct {
+	From  string
+	To    string
+	Label string
+	Style Style
+	Data  map[string]interface{} // Exported as GraphML data
+}
+
+// Graph is a format-neutral view of a reasoning graph
+type Graph struct {
+	ID        string
+	Title     string
+	LeftRight bool // Lay out left to right instead of top to bottom
+	Nodes     []*Node
+	Edges     []*Edge
+}
+
+// Render renders a graph in the given format
+func Render(g *Graph, format Format) (string, error) {
+	if g == nil {
+		return "", fmt.Errorf("graph is required")
+	}
+
+	switch format {
+	case FormatDOT:
+		return renderDOT(g), nil
+	case FormatMermaid:
+		return renderMermaid(g), nil
+	case FormatGraphML:
+		return renderGraphML(g)
+	default:
+		return "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
@@ -0,0 +1,221 @@
+package export
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"unified-thinking/internal/modes"
+	"unified-thinking/internal/types"
+)
+
+func newTestGraphState() *modes.GraphState {
+	now := time.Now()
+	root := modes.NewThoughtVertex("g-vertex-0", "Sort \"[3,1,2]\"", modes.ThoughtTypeInitial, 0.8)
+	good := modes.NewThoughtVertex("g-vertex-1", "Use merge sort", modes.ThoughtTypeGenerated, 0.9)
+	good.Depth, good.Score, good.CreatedAt = 1, 0.85, now
+	weak := modes.NewThoughtVertex("g-vertex-2", "Use bogosort", modes.ThoughtTypeGenerated, 0.4)
+	weak.Depth, weak.Score, weak.CreatedAt = 1, 0.2, now.Add(time.Millisecond)
+	final := modes.NewThoughtVertex("g-vertex-3", "Merge sort gives <O(n log n)>", modes.ThoughtTypeRefined, 0.9)
+	final.Depth, final.Score = 2, 0.9
+
+	return &modes.GraphState{
+		ID: "sorting",
+		Vertices: map[string]*modes.ThoughtVertex{
+			root.ID: root, good.ID: good, weak.ID: weak, final.ID: final,
+		},
+		Edges: map[string]*modes.ThoughtEdge{
+			"e1": modes.NewThoughtEdge("e1", root.ID, good.ID, modes.EdgeTypeDerivesFrom, 1),
+			"e2": modes.NewThoughtEdge("e2", root.ID, weak.ID, modes.EdgeTypeDerivesFrom, 1),
+			"e3": modes.NewThoughtEdge("e3", good.ID, final.ID, modes.EdgeTypeRefines, 1),
+			// Edge to a removed vertex is skipped
+			"e4": modes.NewThoughtEdge("e4", good.ID, "g-vertex-9", modes.EdgeTypeSupports, 1),
+		},
+		RootIDs:     []string{root.ID},
+		ActiveIDs:   []string{final.ID},
+		TerminalIDs: []string{final.ID},
+		Config:      modes.DefaultGraphConfig(),
+	}
+}
+
+func newTestCausalGraph() *types.CausalGraph {
+	return &types.CausalGraph{
+		ID:          "causal-1",
+		Description: "Marketing impact on sales",
+		Variables: []*types.CausalVariable{
+			{ID: "v1", Name: "marketing spend", Observable: true},
+			{ID: "v2", Name: "sales", Observable: true},
+			{ID: "v3", Name: "brand trust", Observable: false},
+		},
+		Links: []*types.CausalLink{
+			{ID: "l1", From: "v1", To: "v2", Type: "positive", Strength: 0.8},
+			{ID: "l2", From: "v3", To: "v2", Type: "negative", Strength: 0.3},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{"dot": FormatDOT, "Graphviz": FormatDOT, " mermaid ": FormatMermaid, "GRAPHML": FormatGraphML}
+	for name, want := range tests {
+		got, err := ParseFormat(name)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("svg"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestFromGraphState_Styling(t *testing.T) {
+	g := FromGraphState(newTestGraphState())
+
+	if len(g.Nodes) != 4 || len(g.Edges) != 3 {
+		t.Fatalf("got %d nodes and %d edges, want 4 and 3", len(g.Nodes), len(g.Edges))
+	}
+	if g.Nodes[0].ID != "g-vertex-0" || g.Nodes[3].ID != "g-vertex-3" {
+		t.Errorf("nodes not ordered by depth: %s ... %s", g.Nodes[0].ID, g.Nodes[3].ID)
+	}
+
+	byID := make(map[string]*Node)
+	for _, n := range g.Nodes {
+		byID[n.ID] = n
+	}
+	if s := byID["g-vertex-3"]; !s.Style.Double || s.Data["status"] != "terminal" {
+		t.Errorf("terminal vertex style = %+v, status %v", s.Style, s.Data["status"])
+	}
+	if s := byID["g-vertex-2"]; !s.Style.Dashed || s.Data["status"] != "pruned" || s.Style.Fill != colorLowScore {
+		t.Errorf("pruned vertex style = %+v, status %v", s.Style, s.Data["status"])
+	}
+	if s := byID["g-vertex-1"]; s.Style.Fill != colorHighScore || !strings.Contains(s.Label, "score 0.85") {
+		t.Errorf("scored vertex = %+v, label %q", s.Style, s.Label)
+	}
+	if s := byID["g-vertex-0"]; s.Style.Fill != colorUnscored || !s.Style.Bold {
+		t.Errorf("root vertex style = %+v", s.Style)
+	}
+}
+
+func TestRenderGraphState_DOT(t *testing.T) {
+	out, err := RenderGraphState(newTestGraphState(), FormatDOT)
+	if err != nil {
+		t.Fatalf("RenderGraphState() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`digraph "sorting" {`,
+		`label="[initial] Sort \"[3,1,2]\""`,
+		`peripheries=2`,
+		`style="rounded,filled,dashed"`,
+		`"g-vertex-1" -> "g-vertex-3" [label="refines", color="#1565c0", fontcolor="#1565c0", style=dashed]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "g-vertex-9") {
+		t.Error("DOT output contains an edge to a removed vertex")
+	}
+}
+
+func TestRenderGraphState_Mermaid(t *testing.T) {
+	out, err := RenderGraphState(newTestGraphState(), FormatMermaid)
+	if err != nil {
+		t.Fatalf("RenderGraphState() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"flowchart TD",
+		`n0("[initial] Sort #quot;[3,1,2]#quot;")`,
+		`n3("[refined] Merge sort gives #lt;O(n log n)#gt;<br/>score 0.90")`,
+		`n1 -.->|"refines"| n3`,
+		"style n3 fill:#c8e6c9,stroke:#1b5e20,stroke-width:4px",
+		"stroke-dasharray:5 5",
+		"linkStyle 2 stroke:#1565c0",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderCausalGraph_GraphML(t *testing.T) {
+	out, err := RenderCausalGraph(newTestCausalGraph(), FormatGraphML)
+	if err != nil {
+		t.Fatalf("RenderCausalGraph() error = %v", err)
+	}
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Data   []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("GraphML is not valid XML: %v\n%s", err, out)
+	}
+
+	if len(doc.Graph.Nodes) != 3 || len(doc.Graph.Edges) != 2 {
+		t.Fatalf("got %d nodes and %d edges, want 3 and 2", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	edge := doc.Graph.Edges[0]
+	if edge.Source != "v1" || edge.Target != "v2" {
+		t.Errorf("edge = %s -> %s, want v1 -> v2", edge.Source, edge.Target)
+	}
+	data := make(map[string]string)
+	for _, d := range edge.Data {
+		data[d.Key] = d.Value
+	}
+	if data["e_strength"] != "0.8" || data["e_color"] != "#2e7d32" || data["e_label"] != "+0.80" {
+		t.Errorf("edge data = %v", data)
+	}
+}
+
+func TestRenderCausalGraph_DOTAndMermaid(t *testing.T) {
+	dot, err := RenderCausalGraph(newTestCausalGraph(), FormatDOT)
+	if err != nil {
+		t.Fatalf("RenderCausalGraph(dot) error = %v", err)
+	}
+	for _, want := range []string{"rankdir=LR", `label="Marketing impact on sales"`, `style="filled,dashed"`, `label="-0.30"`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot)
+		}
+	}
+
+	mermaid, err := RenderCausalGraph(newTestCausalGraph(), FormatMermaid)
+	if err != nil {
+		t.Fatalf("RenderCausalGraph(mermaid) error = %v", err)
+	}
+	for _, want := range []string{`title: "Marketing impact on sales"`, "flowchart LR", `n0(["marketing spend"])`, `n0 ==>|"+0.80"| n1`} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, mermaid)
+		}
+	}
+
+	if _, err := RenderCausalGraph(nil, FormatDOT); err == nil {
+		t.Error("expected error for nil graph")
+	}
+}
+
+func TestRenderCausalGraph_MermaidTitleQuoted(t *testing.T) {
+	graph := newTestCausalGraph()
+	graph.Description = "Q3 review: spend vs sales #2\nfinal"
+
+	out, err := RenderCausalGraph(graph, FormatMermaid)
+	if err != nil {
+		t.Fatalf("RenderCausalGraph() error = %v", err)
+	}
+	want := "---\ntitle: \"Q3 review: spend vs sales #2 final\"\n---\n"
+	if !strings.HasPrefix(out, want) {
+		t.Errorf("Mermaid front matter = %q, want prefix %q", out, want)
+	}
+}
@@ -0,0 +1,180 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+
+	"unified-thinking/internal/modes"
+)
+
+// maxLabelLength caps the thought content shown in a node label
+const maxLabelLength = 80
+
+// Score bands used to color Graph-of-Thoughts vertices
+const (
+	colorHighScore = "#c8e6c9" // Score >= 0.7
+	colorMidScore  = "#fff9c4" // Score >= 0.4
+	colorLowScore  = "#ffcdd2" // Score > 0
+	colorUnscored  = "#eeeeee"
+)
+
+// gotEdgeStyles styles edges by relationship type
+var gotEdgeStyles = map[modes.EdgeType]Style{
+	modes.EdgeTypeDerivesFrom: {Stroke: "#616161"},
+	modes.EdgeTypeAggregates:  {Stroke: "#6a1b9a", Bold: true},
+	modes.EdgeTypeRefines:     {Stroke: "#1565c0", Dashed: true},
+	modes.EdgeTypeContradicts: {Stroke: "#c62828"},
+	modes.EdgeTypeSupports:    {Stroke: "#2e7d32"},
+}
+
+// FromGraphState converts a Graph-of-Thoughts state into an exportable graph.
+//
+// Vertices are filled by score band. Terminal vertices get a double border,
+// roots and active vertices a thick one. Vertices scored below the graph's
+// prune threshold, which got-prune would remove, get a dashed grey border.
+func FromGraphState(state *modes.GraphState) *Graph {
+	threshold := modes.DefaultGraphConfig().PruneThreshold
+	if state.Config != nil {
+		threshold = state.Config.PruneThreshold
+	}
+
+	vertices := make([]*modes.ThoughtVertex, 0, len(state.Vertices))
+	for _, v := range state.Vertices {
+		vertices = append(vertices, v)
+	}
+	sort.Slice(vertices, func(i, j int) bool {
+		if vertices[i].Depth != vertices[j].Depth {
+			return vertices[i].Depth < vertices[j].Depth
+		}
+		if !vertices[i].CreatedAt.Equal(vertices[j].CreatedAt) {
+			return vertices[i].CreatedAt.Before(vertices[j].CreatedAt)
+		}
+		return vertices[i].ID < vertices[j].ID
+	})
+
+	g := &Graph{ID: state.ID, Title: state.ID}
+	for _, v := range vertices {
+		status := vertexStatus(state, v, threshold)
+
+		label := fmt.Sprintf("[%s] %s", v.Type, truncateLabel(v.Content))
+		if v.Score > 0 {
+			label += fmt.Sprintf("\nscore %.2f", v.Score)
+		}
+
+		style := Style{Fill: scoreColor(v.Score)}
+		switch status {
+		case "terminal":
+			style.Stroke, style.Double = "#1b5e20", true
+		case "root":
+			style.Stroke, style.Bold = "#37474f", true
+		case "active":
+			style.Stroke, style.Bold = "#1565c0", true
+		case "pruned":
+			style.Stroke, style.Dashed = "#9e9e9e", true
+		}
+
+		g.Nodes = append(g.Nodes, &Node{
+			ID:    v.ID,
+			Label: label,
+			Style: style,
+			Data: map[string]interface{}{
+				"type":       string(v.Type),
+				"content":    v.Content,
+				"score":      v.Score,
+				"confidence": v.Confidence,
+				"depth":      v.Depth,
+				"status":     status,
+			},
+		})
+	}
+
+	edges := make([]*modes.ThoughtEdge, 0, len(state.Edges))
+	for _, e := range state.Edges {
+		if _, ok := state.Vertices[e.FromID]; !ok {
+			continue
+		}
+		if _, ok := state.Vertices[e.ToID]; !ok {
+			continue
+		}
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].FromID != edges[j].FromID {
+			return edges[i].FromID < edges[j].FromID
+		}
+		if edges[i].ToID != edges[j].ToID {
+			return edges[i].ToID < edges[j].ToID
+		}
+		return edges[i].ID < edges[j].ID
+	})
+
+	for _, e := range edges {
+		g.Edges = append(g.Edges, &Edge{
+			From:  e.FromID,
+			To:    e.ToID,
+			Label: string(e.Type),
+			Style: gotEdgeStyles[e.Type],
+			Data: map[string]interface{}{
+				"type":   string(e.Type),
+				"weight": e.Weight,
+			},
+		})
+	}
+
+	return g
+}
+
+// RenderGraphState renders a Graph-of-Thoughts state in the given format
+func RenderGraphState(state *modes.GraphState, format Format) (string, error) {
+	if state == nil {
+		return "", fmt.Errorf("graph state is required")
+	}
+	return Render(FromGraphState(state), format)
+}
+
+// vertexStatus classifies a vertex for styling. Terminal takes precedence
+// over root, root over active, and active over pruned.
+func vertexStatus(state *modes.GraphState, v *modes.ThoughtVertex, threshold float64) string {
+	switch {
+	case containsID(state.TerminalIDs, v.ID):
+		return "terminal"
+	case containsID(state.RootIDs, v.ID):
+		return "root"
+	case containsID(state.ActiveIDs, v.ID):
+		return "active"
+	case v.Score > 0 && v.Score < threshold:
+		return "pruned"
+	default:
+		return "normal"
+	}
+}
+
+func scoreColor(score float64) string {
+	switch {
+	case score >= 0.7:
+		return colorHighScore
+	case score >= 0.4:
+		return colorMidScore
+	case score > 0:
+		return colorLowScore
+	default:
+		return colorUnscored
+	}
+}
+
+func truncateLabel(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxLabelLength {
+		return s
+	}
+	return string(runes[:maxLabelLength-3]) + "..."
+}
+
+func containsID(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// graphMLKey declares a GraphML data attribute
+type graphMLKey struct {
+	id     string
+	domain string // "node" or "edge"
+	name   string
+	kind   string // GraphML attr.type
+}
+
+// renderGraphML renders a graph as GraphML. Labels and styling are exported
+// as data attributes next to each element's own data, so tools like yEd and
+// Gephi can map them.
+func renderGraphML(g *Graph) (string, error) {
+	nodeData := g.nodeData()
+	edgeData := g.edgeData()
+	nodeKeys := collectGraphMLKeys("node", nodeData)
+	edgeKeys := collectGraphMLKeys("edge", edgeData)
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">` + "\n")
+	for _, keys := range [][]graphMLKey{nodeKeys, edgeKeys} {
+		for _, k := range keys {
+			fmt.Fprintf(&b, "  <key id=%s for=%s attr.name=%s attr.type=%s/>\n",
+				xmlAttr(k.id), xmlAttr(k.domain), xmlAttr(k.name), xmlAttr(k.kind))
+		}
+	}
+
+	fmt.Fprintf(&b, "  <graph id=%s edgedefault=\"directed\">\n", xmlAttr(g.ID))
+	for i, n := range g.Nodes {
+		fmt.Fprintf(&b, "    <node id=%s>\n", xmlAttr(n.ID))
+		if err := writeGraphMLData(&b, nodeKeys, nodeData[i]); err != nil {
+			return "", err
+		}
+		b.WriteString("    </node>\n")
+	}
+	for i, e := range g.Edges {
+		fmt.Fprintf(&b, "    <edge id=\"e%d\" source=%s target=%s>\n", i, xmlAttr(e.From), xmlAttr(e.To))
+		if err := writeGraphMLData(&b, edgeKeys, edgeData[i]); err != nil {
+			return "", err
+		}
+		b.WriteString("    </edge>\n")
+	}
+	b.WriteString("  </graph>\n</graphml>\n")
+
+	return b.String(), nil
+}
+
+func (g *Graph) nodeData() []map[string]interface{} {
+	data := make([]map[string]interface{}, len(g.Nodes))
+	for i, n := range g.Nodes {
+		data[i] = graphMLData(n.Label, n.Style, n.Data)
+	}
+	return data
+}
+
+func (g *Graph) edgeData() []map[string]interface{} {
+	data := make([]map[string]interface{}, len(g.Edges))
+	for i, e := range g.Edges {
+		data[i] = graphMLData(e.Label, e.Style, e.Data)
+	}
+	return data
+}
+
+// graphMLData merges an element's label and style into its data attributes
+func graphMLData(label string, style Style, data map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(data)+4)
+	for k, v := range data {
+		merged[k] = v
+	}
+	if label != "" {
+		merged["label"] = label
+	}
+	if style.Fill != "" {
+		merged["fill"] = style.Fill
+	}
+	if style.Stroke != "" {
+		merged["color"] = style.Stroke
+	}
+	if style.Dashed {
+		merged["dashed"] = true
+	}
+	return merged
+}
+
+// collectGraphMLKeys declares one key per attribute name, typed by the first
+// value seen
+func collectGraphMLKeys(domain string, elements []map[string]interface{}) []graphMLKey {
+	kinds := make(map[string]string)
+	for _, data := range elements {
+		for name, value := range data {
+			if _, ok := kinds[name]; !ok {
+				kinds[name] = graphMLType(value)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(kinds))
+	for name := range kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	keys := make([]graphMLKey, len(names))
+	for i, name := range names {
+		keys[i] = graphMLKey{id: domain[:1] + "_" + name, domain: domain, name: name, kind: kinds[name]}
+	}
+	return keys
+}
+
+func graphMLType(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case int, int32, int64:
+		return "int"
+	case float32, float64:
+		return "double"
+	default:
+		return "string"
+	}
+}
+
+func writeGraphMLData(b *strings.Builder, keys []graphMLKey, data map[string]interface{}) error {
+	for _, k := range keys {
+		value, ok := data[k.name]
+		if !ok {
+			continue
+		}
+		var text bytes.Buffer
+		if err := xml.EscapeText(&text, []byte(fmt.Sprint(value))); err != nil {
+			return fmt.Errorf("failed to escape GraphML data %s: %w", k.name, err)
+		}
+		fmt.Fprintf(b, "      <data key=%s>%s</data>\n", xmlAttr(k.id), text.String())
+	}
+	return nil
+}
+
+// xmlAttr quotes an XML attribute value
+func xmlAttr(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return `"` + b.String() + `"`
+}
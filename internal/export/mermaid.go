@@ -0,0 +1,104 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// renderMermaid renders a graph as a Mermaid flowchart. Node IDs are
+// replaced by short aliases because Mermaid restricts identifier characters.
+func renderMermaid(g *Graph) string {
+	var b strings.Builder
+
+	if g.Title != "" {
+		// The title is YAML front matter; quoting keeps ":" and "#" literal
+		fmt.Fprintf(&b, "---\ntitle: %s\n---\n", strconv.Quote(strings.ReplaceAll(g.Title, "\n", " ")))
+	}
+	if g.LeftRight {
+		b.WriteString("flowchart LR\n")
+	} else {
+		b.WriteString("flowchart TD\n")
+	}
+
+	aliases := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		alias := fmt.Sprintf("n%d", i)
+		aliases[n.ID] = alias
+
+		label := mermaidLabel(n.Label)
+		if n.Style.Shape == ShapeEllipse {
+			fmt.Fprintf(&b, "  %s([%s])\n", alias, label)
+		} else {
+			fmt.Fprintf(&b, "  %s(%s)\n", alias, label)
+		}
+	}
+
+	linkStyles := []string{}
+	link := 0
+	for _, e := range g.Edges {
+		from, ok := aliases[e.From]
+		if !ok {
+			continue
+		}
+		to, ok := aliases[e.To]
+		if !ok {
+			continue
+		}
+
+		arrow := "-->"
+		if e.Style.Bold {
+			arrow = "==>"
+		} else if e.Style.Dashed {
+			arrow = "-.->"
+		}
+		if e.Label != "" {
+			fmt.Fprintf(&b, "  %s %s|%s| %s\n", from, arrow, mermaidLabel(e.Label), to)
+		} else {
+			fmt.Fprintf(&b, "  %s %s %s\n", from, arrow, to)
+		}
+
+		if e.Style.Stroke != "" {
+			linkStyles = append(linkStyles, fmt.Sprintf("  linkStyle %d stroke:%s", link, e.Style.Stroke))
+		}
+		link++
+	}
+
+	for i, n := range g.Nodes {
+		if css := mermaidNodeStyle(n.Style); css != "" {
+			fmt.Fprintf(&b, "  style n%d %s\n", i, css)
+		}
+	}
+	for _, style := range linkStyles {
+		b.WriteString(style + "\n")
+	}
+
+	return b.String()
+}
+
+func mermaidNodeStyle(s Style) string {
+	props := []string{}
+	if s.Fill != "" {
+		props = append(props, "fill:"+s.Fill)
+	}
+	if s.Stroke != "" {
+		props = append(props, "stroke:"+s.Stroke)
+	}
+	switch {
+	case s.Double:
+		props = append(props, "stroke-width:4px")
+	case s.Bold:
+		props = append(props, "stroke-width:2px")
+	}
+	if s.Dashed {
+		props = append(props, "stroke-dasharray:5 5")
+	}
+	return strings.Join(props, ",")
+}
+
+// mermaidLabel quotes a label, escaping characters Mermaid would otherwise
+// interpret
+func mermaidLabel(s string) string {
+	r := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\r", "", "\n", "<br/>")
+	return `"` + r.Replace(s) + `"`
+}
@@ -33,6 +33,12 @@ func (ei *EmbeddingIntegration) GetEmbedder() embeddings.Embedder {
 	return ei.embedder
 }
 
+// SetEmbedder replaces the embedder, e.g. with a recording wrapper. It must
+// be called before embeddings are generated.
+func (ei *EmbeddingIntegration) SetEmbedder(embedder embeddings.Embedder) {
+	ei.embedder = embedder
+}
+
 // NewEmbeddingIntegration creates a new embedding integration.
 // Embeddings are ALWAYS enabled - VOYAGE_API_KEY is REQUIRED.
 // Returns an error if configuration is invalid - NO FALLBACKS.
@@ -177,7 +183,7 @@ func (ei *EmbeddingIntegration) vectorSearch(ctx context.Context, problem *Probl
 	embeddingCount := 0
 	for _, traj := range ei.store.trajectories {
 		trajCount++
-		if traj.Problem == nil || traj.Problem.Embedding == nil {
+		if traj.Problem == nil || traj.Problem.Embedding == nil || !traj.InWorkspace(problem.Workspace) {
 			continue
 		}
 		embeddingCount++
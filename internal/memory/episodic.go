@@ -24,6 +24,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"unified-thinking/internal/storage"
 )
 
 // ReasoningTrajectory represents a complete reasoning session from problem to solution
@@ -44,6 +46,7 @@ type ReasoningTrajectory struct {
 	Complexity   float64                `json:"complexity"`
 	SuccessScore float64                `json:"success_score"`
 	Metadata     map[string]interface{} `json:"metadata"`
+	Workspace    string                 `json:"workspace,omitempty"`
 }
 
 // ProblemDescription describes the initial problem
@@ -56,6 +59,7 @@ type ProblemDescription struct {
 	ProblemType  string                 `json:"problem_type"`
 	Complexity   float64                `json:"complexity"`
 	Domain       string                 `json:"domain"`
+	Workspace    string                 `json:"workspace,omitempty"` // Retrieval only matches trajectories from this workspace
 
 	// Embedding support (optional)
 	Embedding     []float32          `json:"embedding,omitempty"`      // Vector representation
@@ -258,6 +262,10 @@ func (s *EpisodicMemoryStore) StoreTrajectory(ctx context.Context, trajectory *R
 	if trajectory.ID == "" {
 		trajectory.ID = generateTrajectoryID(trajectory)
 	}
+	if trajectory.Workspace == "" && trajectory.Problem != nil {
+		trajectory.Workspace = trajectory.Problem.Workspace
+	}
+	trajectory.Workspace = storage.NormalizeWorkspace(trajectory.Workspace)
 
 	// Store the trajectory in memory
 	s.trajectories[trajectory.ID] = trajectory
@@ -352,7 +360,7 @@ func (s *EpisodicMemoryStore) RetrieveSimilarHashBased(problem *ProblemDescripti
 	// Calculate similarity scores
 	for _, id := range uniqueIDs {
 		trajectory, exists := s.trajectories[id]
-		if !exists {
+		if !exists || !trajectory.InWorkspace(problem.Workspace) {
 			continue
 		}
 
@@ -380,6 +388,12 @@ func (s *EpisodicMemoryStore) RetrieveSimilarHashBased(problem *ProblemDescripti
 	return matches, nil
 }
 
+// InWorkspace reports whether the trajectory belongs to workspace.
+// Trajectories recorded before workspaces existed belong to the default one.
+func (t *ReasoningTrajectory) InWorkspace(workspace string) bool {
+	return storage.NormalizeWorkspace(t.Workspace) == storage.NormalizeWorkspace(workspace)
+}
+
 // GetRecommendations generates adaptive recommendations based on context
 // Enhanced in Phase 2.1 to provide specific tool sequences with success rates
 func (s *EpisodicMemoryStore) GetRecommendations(ctx context.Context, recCtx *RecommendationContext) ([]*Recommendation, error) {
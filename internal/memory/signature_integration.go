@@ -25,6 +25,7 @@ type ContextSignature struct {
 	ToolSequence []string
 	Complexity   float64
 	Embedding    []float32 // Semantic embedding for similarity matching
+	Workspace    string    // Workspace of the source trajectory
 }
 
 // SignatureIntegration handles generating and storing context signatures for trajectories
@@ -182,6 +183,7 @@ func (si *SignatureIntegration) generateSignature(trajectory *ReasoningTrajector
 		TrajectoryID: trajectory.ID,
 		KeyConcepts:  []string{},
 		ToolSequence: []string{},
+		Workspace:    trajectory.Workspace,
 	}
 
 	// Generate fingerprint from problem description
@@ -27,6 +27,7 @@ func (a *SQLiteSignatureAdapter) StoreContextSignature(trajectoryID string, sig
 		ToolSequence: sig.ToolSequence,
 		Complexity:   sig.Complexity,
 		Embedding:    sig.Embedding, // Include embedding if present
+		Workspace:    sig.Workspace,
 	}
 
 	return a.store.StoreContextSignature(trajectoryID, storageSig)
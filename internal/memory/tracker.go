@@ -202,6 +202,7 @@ func (t *SessionTracker) CompleteSession(ctx context.Context, sessionID string,
 		Complexity:   session.Problem.Complexity,
 		SuccessScore: successScore,
 		Metadata:     session.Metadata,
+		Workspace:    session.Problem.Workspace,
 	}
 
 	// Store in episodic memory (store is guaranteed non-nil by constructor)
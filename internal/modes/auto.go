@@ -360,6 +360,7 @@ func (m *AutoMode) recordOutcome(input ThoughtInput, result *ThoughtResult, exec
 		ExecutionTimeNs:    executionTimeNs,
 		TokenCount:         0, // Could estimate from content length
 		Timestamp:          time.Now().Unix(),
+		Workspace:          input.Workspace,
 	}
 
 	if err := m.storage.RecordRLOutcome(outcome); err != nil {
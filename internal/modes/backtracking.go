@@ -4,20 +4,46 @@ package modes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
+	"sort"
+	"sync"
 	"time"
 
 	"unified-thinking/internal/storage"
 	"unified-thinking/internal/types"
 )
 
+// CheckpointStorage persists checkpoints together with the snapshots and
+// deltas needed to restore them
+type CheckpointStorage interface {
+	StoreCheckpoint(checkpoint *types.CheckpointRecord) error
+	GetCheckpoint(id string) (*types.CheckpointRecord, error)
+	ListCheckpoints(branchID string) ([]*types.CheckpointRecord, error)
+	StoreBranchSnapshot(snapshot *types.BranchSnapshotRecord) error
+	GetBranchSnapshot(id string) (*types.BranchSnapshotRecord, error)
+	StoreBranchDelta(delta *types.BranchDeltaRecord) error
+	GetBranchDeltas(snapshotID string) ([]*types.BranchDeltaRecord, error)
+}
+
 // BacktrackingManager manages branch checkpoints and history
 type BacktrackingManager struct {
+	mu                sync.Mutex
 	storage           storage.Storage
-	snapshots         map[string]*BranchSnapshot // branchID -> latest snapshot
-	deltas            map[string][]*BranchDelta  // branchID -> deltas since last snapshot
-	checkpoints       map[string]*Checkpoint     // checkpointID -> checkpoint
-	checkpointCounter int                        // Counter for unique checkpoint IDs
+	checkpointStore   CheckpointStorage
+	snapshots         map[string]*BranchSnapshot  // branchID -> latest snapshot
+	deltas            map[string][]*BranchDelta   // branchID -> deltas since last snapshot
+	history           map[string]*snapshotHistory // snapshotID -> superseded or reloaded snapshot
+	checkpoints       map[string]*Checkpoint      // checkpointID -> checkpoint
+	checkpointCounter int                         // Counter for unique checkpoint IDs
+}
+
+// snapshotHistory keeps an older snapshot and its deltas so checkpoints
+// taken before the latest snapshot remain restorable
+type snapshotHistory struct {
+	snapshot *BranchSnapshot
+	deltas   []*BranchDelta
 }
 
 // NewBacktrackingManager creates a new backtracking manager
@@ -26,10 +52,19 @@ func NewBacktrackingManager(store storage.Storage) *BacktrackingManager {
 		storage:     store,
 		snapshots:   make(map[string]*BranchSnapshot),
 		deltas:      make(map[string][]*BranchDelta),
+		history:     make(map[string]*snapshotHistory),
 		checkpoints: make(map[string]*Checkpoint),
 	}
 }
 
+// SetCheckpointStorage enables durable checkpoints. Checkpoints, snapshots and
+// deltas are written through to the store and loaded from it on demand.
+func (bm *BacktrackingManager) SetCheckpointStorage(store CheckpointStorage) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.checkpointStore = store
+}
+
 // BranchSnapshot represents a full branch state at a point in time
 type BranchSnapshot struct {
 	ID            string
@@ -84,15 +119,19 @@ type Checkpoint struct {
 
 // CreateCheckpoint creates a checkpoint for the current branch state
 func (bm *BacktrackingManager) CreateCheckpoint(ctx context.Context, branchID, name, description string) (*Checkpoint, error) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
 	// Get current branch
 	branch, err := bm.storage.GetBranch(branchID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get branch: %w", err)
 	}
 
-	// Check if we need a new snapshot (every 10 deltas)
+	// Check if we need a new snapshot (every 10 deltas, or when the branch
+	// changed without the change being recorded as a delta)
 	deltaCount := len(bm.deltas[branchID])
-	if deltaCount >= 10 || bm.snapshots[branchID] == nil {
+	if deltaCount >= 10 || bm.snapshots[branchID] == nil || !bm.matchesHistory(branchID, branch) {
 		if err := bm.createSnapshot(branchID, branch); err != nil {
 			return nil, fmt.Errorf("failed to create snapshot: %w", err)
 		}
@@ -125,6 +164,11 @@ func (bm *BacktrackingManager) CreateCheckpoint(ctx context.Context, branchID, n
 	checkpoint.Metadata["thought_ids"] = thoughtIDs
 	checkpoint.Metadata["insight_ids"] = insightIDs
 
+	if bm.checkpointStore != nil {
+		if err := bm.checkpointStore.StoreCheckpoint(checkpointToRecord(checkpoint)); err != nil {
+			return nil, fmt.Errorf("failed to persist checkpoint: %w", err)
+		}
+	}
 	bm.checkpoints[checkpoint.ID] = checkpoint
 
 	return checkpoint, nil
@@ -132,30 +176,17 @@ func (bm *BacktrackingManager) CreateCheckpoint(ctx context.Context, branchID, n
 
 // RestoreCheckpoint restores a branch to a checkpoint state
 func (bm *BacktrackingManager) RestoreCheckpoint(ctx context.Context, checkpointID string) (*types.Branch, error) {
-	checkpoint, exists := bm.checkpoints[checkpointID]
-	if !exists {
-		return nil, fmt.Errorf("checkpoint not found: %s", checkpointID)
-	}
-
-	// Get snapshot
-	snapshot := bm.snapshots[checkpoint.BranchID]
-	if snapshot == nil || snapshot.ID != checkpoint.SnapshotID {
-		return nil, fmt.Errorf("snapshot not found for checkpoint: %s", checkpointID)
-	}
-
-	// Deep copy the snapshot branch
-	restoredBranch := bm.deepCopyBranch(snapshot.Branch)
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
 
-	// Apply deltas up to checkpoint
-	deltas := bm.deltas[checkpoint.BranchID]
-	if checkpoint.DeltaCount > len(deltas) {
-		return nil, fmt.Errorf("delta count mismatch: checkpoint expects %d, have %d", checkpoint.DeltaCount, len(deltas))
+	checkpoint, err := bm.lookupCheckpoint(checkpointID)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := 0; i < checkpoint.DeltaCount; i++ {
-		if err := bm.applyDelta(restoredBranch, deltas[i]); err != nil {
-			return nil, fmt.Errorf("failed to apply delta %d: %w", i, err)
-		}
+	restoredBranch, err := bm.materialize(checkpoint)
+	if err != nil {
+		return nil, err
 	}
 
 	// Store restored branch
@@ -169,6 +200,9 @@ func (bm *BacktrackingManager) RestoreCheckpoint(ctx context.Context, checkpoint
 
 // RecordChange records a change to track in deltas
 func (bm *BacktrackingManager) RecordChange(branchID string, operation DeltaOperation, entityType EntityType, entityID string, entity interface{}) error {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
 	delta := &BranchDelta{
 		ID:         fmt.Sprintf("delta-%d-%s", time.Now().UnixNano(), branchID),
 		BranchID:   branchID,
@@ -179,23 +213,43 @@ func (bm *BacktrackingManager) RecordChange(branchID string, operation DeltaOper
 		CreatedAt:  time.Now(),
 	}
 
+	// Deltas only matter relative to a snapshot; without one the next
+	// checkpoint takes a fresh snapshot and discards them
+	if snapshot := bm.snapshots[branchID]; snapshot != nil && bm.checkpointStore != nil {
+		record, err := deltaToRecord(delta, snapshot.ID)
+		if err != nil {
+			return err
+		}
+		if err := bm.checkpointStore.StoreBranchDelta(record); err != nil {
+			return fmt.Errorf("failed to persist delta: %w", err)
+		}
+	}
+
 	bm.deltas[branchID] = append(bm.deltas[branchID], delta)
 
 	return nil
 }
 
-// ForkFromCheckpoint creates a new branch from a checkpoint
+// ForkFromCheckpoint creates a new branch from a checkpoint. The source branch
+// is left untouched, so several forks can explore alternatives from the same
+// checkpoint.
 func (bm *BacktrackingManager) ForkFromCheckpoint(ctx context.Context, checkpointID, newBranchName string) (*types.Branch, error) {
-	// Restore to checkpoint state
-	restoredBranch, err := bm.RestoreCheckpoint(ctx, checkpointID)
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	checkpoint, err := bm.lookupCheckpoint(checkpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore checkpoint: %w", err)
+	}
+
+	newBranch, err := bm.materialize(checkpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to restore checkpoint: %w", err)
 	}
 
-	// Create new branch from restored state
-	newBranch := bm.deepCopyBranch(restoredBranch)
-	newBranch.ID = fmt.Sprintf("branch-%d", time.Now().Unix())
-	newBranch.ParentBranchID = restoredBranch.ID
+	// Let storage assign a unique ID to the new branch
+	newBranch.ID = ""
+	newBranch.ParentBranchID = checkpoint.BranchID
 	newBranch.CreatedAt = time.Now()
 	newBranch.UpdatedAt = time.Now()
 
@@ -207,6 +261,9 @@ func (bm *BacktrackingManager) ForkFromCheckpoint(ctx context.Context, checkpoin
 		}
 		lastThought.Metadata["forked_from_checkpoint"] = checkpointID
 		lastThought.Metadata["fork_time"] = time.Now()
+		if newBranchName != "" {
+			lastThought.Metadata["fork_name"] = newBranchName
+		}
 	}
 
 	// Store new branch
@@ -217,86 +274,109 @@ func (bm *BacktrackingManager) ForkFromCheckpoint(ctx context.Context, checkpoin
 	return newBranch, nil
 }
 
-// ListCheckpoints lists all checkpoints for a branch (or all if branchID is empty)
+// ListCheckpoints lists all checkpoints for a branch (or all if branchID is
+// empty) in creation order
 func (bm *BacktrackingManager) ListCheckpoints(branchID string) []*Checkpoint {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	if bm.checkpointStore != nil {
+		records, err := bm.checkpointStore.ListCheckpoints(branchID)
+		if err == nil {
+			checkpoints := make([]*Checkpoint, len(records))
+			for i, record := range records {
+				checkpoints[i] = checkpointFromRecord(record)
+				bm.checkpoints[record.ID] = checkpoints[i]
+			}
+			return checkpoints
+		}
+		log.Printf("Warning: failed to list stored checkpoints, using in-memory checkpoints: %v", err)
+	}
+
 	checkpoints := make([]*Checkpoint, 0)
 	for _, cp := range bm.checkpoints {
 		if branchID == "" || cp.BranchID == branchID {
 			checkpoints = append(checkpoints, cp)
 		}
 	}
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].CreatedAt.Before(checkpoints[j].CreatedAt)
+	})
 	return checkpoints
 }
 
-// GetCheckpointDiff compares two checkpoints and returns differences
+// GetCheckpointDiff compares two checkpoints and returns differences. The
+// checkpoints may belong to different branches, e.g. a branch and a fork of it.
 func (bm *BacktrackingManager) GetCheckpointDiff(checkpoint1ID, checkpoint2ID string) (*CheckpointDiff, error) {
-	cp1, exists1 := bm.checkpoints[checkpoint1ID]
-	cp2, exists2 := bm.checkpoints[checkpoint2ID]
-
-	if !exists1 || !exists2 {
-		return nil, fmt.Errorf("one or both checkpoints not found")
-	}
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
 
-	if cp1.BranchID != cp2.BranchID {
-		return nil, fmt.Errorf("checkpoints are from different branches")
+	cp1, err := bm.lookupCheckpoint(checkpoint1ID)
+	if err != nil {
+		return nil, err
 	}
-
-	diff := &CheckpointDiff{
-		Checkpoint1:     cp1,
-		Checkpoint2:     cp2,
-		ThoughtsAdded:   make([]string, 0),
-		ThoughtsRemoved: make([]string, 0),
-		InsightsAdded:   make([]string, 0),
-		InsightsRemoved: make([]string, 0),
+	cp2, err := bm.lookupCheckpoint(checkpoint2ID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get IDs from checkpoint metadata
-	cp1Thoughts := cp1.Metadata["thought_ids"].([]string)
-	cp2Thoughts := cp2.Metadata["thought_ids"].([]string)
-	cp1Insights := cp1.Metadata["insight_ids"].([]string)
-	cp2Insights := cp2.Metadata["insight_ids"].([]string)
-
-	// Find thoughts added (in cp2 but not in cp1)
-	cp1ThoughtMap := make(map[string]bool)
-	for _, id := range cp1Thoughts {
-		cp1ThoughtMap[id] = true
+	branch1, err := bm.materialize(cp1)
+	if err != nil {
+		return nil, err
 	}
-	for _, id := range cp2Thoughts {
-		if !cp1ThoughtMap[id] {
-			diff.ThoughtsAdded = append(diff.ThoughtsAdded, id)
-		}
+	branch2, err := bm.materialize(cp2)
+	if err != nil {
+		return nil, err
 	}
 
-	// Find thoughts removed (in cp1 but not in cp2)
-	cp2ThoughtMap := make(map[string]bool)
-	for _, id := range cp2Thoughts {
-		cp2ThoughtMap[id] = true
+	diff := &CheckpointDiff{
+		Checkpoint1:      cp1,
+		Checkpoint2:      cp2,
+		Reversed:         cp2.CreatedAt.Before(cp1.CreatedAt),
+		ThoughtsAdded:    make([]string, 0),
+		ThoughtsRemoved:  make([]string, 0),
+		ThoughtsModified: make([]string, 0),
+		InsightsAdded:    make([]string, 0),
+		InsightsRemoved:  make([]string, 0),
+	}
+
+	// Compare thoughts by ID, and by content for thoughts present in both
+	cp1Thoughts := make(map[string]*types.Thought, len(branch1.Thoughts))
+	for _, t := range branch1.Thoughts {
+		cp1Thoughts[t.ID] = t
+	}
+	cp2Thoughts := make(map[string]bool, len(branch2.Thoughts))
+	for _, t := range branch2.Thoughts {
+		cp2Thoughts[t.ID] = true
+		before, existed := cp1Thoughts[t.ID]
+		switch {
+		case !existed:
+			diff.ThoughtsAdded = append(diff.ThoughtsAdded, t.ID)
+		case before.Content != t.Content || before.Confidence != t.Confidence:
+			diff.ThoughtsModified = append(diff.ThoughtsModified, t.ID)
+		}
 	}
-	for _, id := range cp1Thoughts {
-		if !cp2ThoughtMap[id] {
-			diff.ThoughtsRemoved = append(diff.ThoughtsRemoved, id)
+	for _, t := range branch1.Thoughts {
+		if !cp2Thoughts[t.ID] {
+			diff.ThoughtsRemoved = append(diff.ThoughtsRemoved, t.ID)
 		}
 	}
 
-	// Find insights added
-	cp1InsightMap := make(map[string]bool)
-	for _, id := range cp1Insights {
-		cp1InsightMap[id] = true
+	// Compare insights by ID
+	cp1Insights := make(map[string]bool, len(branch1.Insights))
+	for _, ins := range branch1.Insights {
+		cp1Insights[ins.ID] = true
 	}
-	for _, id := range cp2Insights {
-		if !cp1InsightMap[id] {
-			diff.InsightsAdded = append(diff.InsightsAdded, id)
+	cp2Insights := make(map[string]bool, len(branch2.Insights))
+	for _, ins := range branch2.Insights {
+		cp2Insights[ins.ID] = true
+		if !cp1Insights[ins.ID] {
+			diff.InsightsAdded = append(diff.InsightsAdded, ins.ID)
 		}
 	}
-
-	// Find insights removed
-	cp2InsightMap := make(map[string]bool)
-	for _, id := range cp2Insights {
-		cp2InsightMap[id] = true
-	}
-	for _, id := range cp1Insights {
-		if !cp2InsightMap[id] {
-			diff.InsightsRemoved = append(diff.InsightsRemoved, id)
+	for _, ins := range branch1.Insights {
+		if !cp2Insights[ins.ID] {
+			diff.InsightsRemoved = append(diff.InsightsRemoved, ins.ID)
 		}
 	}
 
@@ -305,13 +385,14 @@ func (bm *BacktrackingManager) GetCheckpointDiff(checkpoint1ID, checkpoint2ID st
 
 // CheckpointDiff represents differences between two checkpoints
 type CheckpointDiff struct {
-	Checkpoint1     *Checkpoint
-	Checkpoint2     *Checkpoint
-	Reversed        bool
-	ThoughtsAdded   []string
-	ThoughtsRemoved []string
-	InsightsAdded   []string
-	InsightsRemoved []string
+	Checkpoint1      *Checkpoint
+	Checkpoint2      *Checkpoint
+	Reversed         bool // Checkpoint2 was created before Checkpoint1
+	ThoughtsAdded    []string
+	ThoughtsRemoved  []string
+	ThoughtsModified []string
+	InsightsAdded    []string
+	InsightsRemoved  []string
 }
 
 // PruneBranch marks a branch as abandoned (failed exploration)
@@ -342,7 +423,7 @@ func (bm *BacktrackingManager) PruneBranch(branchID string, reason string) error
 
 func (bm *BacktrackingManager) createSnapshot(branchID string, branch *types.Branch) error {
 	snapshot := &BranchSnapshot{
-		ID:            fmt.Sprintf("snapshot-%d-%s", time.Now().Unix(), branchID),
+		ID:            fmt.Sprintf("snapshot-%d-%s", time.Now().UnixNano(), branchID),
 		BranchID:      branchID,
 		Branch:        bm.deepCopyBranch(branch),
 		CreatedAt:     time.Now(),
@@ -351,6 +432,16 @@ func (bm *BacktrackingManager) createSnapshot(branchID string, branch *types.Bra
 		CrossRefCount: len(branch.CrossRefs),
 	}
 
+	if bm.checkpointStore != nil {
+		if err := bm.checkpointStore.StoreBranchSnapshot(snapshotToRecord(snapshot)); err != nil {
+			return fmt.Errorf("failed to persist snapshot: %w", err)
+		}
+	}
+
+	// Keep the previous snapshot for checkpoints that still reference it
+	if previous := bm.snapshots[branchID]; previous != nil {
+		bm.history[previous.ID] = &snapshotHistory{snapshot: previous, deltas: bm.deltas[branchID]}
+	}
 	bm.snapshots[branchID] = snapshot
 
 	// Clear old deltas since we have a new snapshot
@@ -359,6 +450,115 @@ func (bm *BacktrackingManager) createSnapshot(branchID string, branch *types.Bra
 	return nil
 }
 
+// lookupCheckpoint returns a checkpoint from memory, falling back to the
+// checkpoint store
+func (bm *BacktrackingManager) lookupCheckpoint(checkpointID string) (*Checkpoint, error) {
+	if checkpoint, exists := bm.checkpoints[checkpointID]; exists {
+		return checkpoint, nil
+	}
+	if bm.checkpointStore != nil {
+		if record, err := bm.checkpointStore.GetCheckpoint(checkpointID); err == nil {
+			checkpoint := checkpointFromRecord(record)
+			bm.checkpoints[checkpoint.ID] = checkpoint
+			return checkpoint, nil
+		}
+	}
+	return nil, fmt.Errorf("checkpoint not found: %s", checkpointID)
+}
+
+// snapshotState returns a snapshot and the deltas recorded on top of it
+func (bm *BacktrackingManager) snapshotState(snapshotID, branchID string) (*BranchSnapshot, []*BranchDelta, error) {
+	if snapshot := bm.snapshots[branchID]; snapshot != nil && snapshot.ID == snapshotID {
+		return snapshot, bm.deltas[branchID], nil
+	}
+	if h, exists := bm.history[snapshotID]; exists {
+		return h.snapshot, h.deltas, nil
+	}
+	if bm.checkpointStore == nil {
+		return nil, nil, fmt.Errorf("snapshot not found: %s", snapshotID)
+	}
+
+	record, err := bm.checkpointStore.GetBranchSnapshot(snapshotID)
+	if err != nil {
+		return nil, nil, err
+	}
+	deltaRecords, err := bm.checkpointStore.GetBranchDeltas(snapshotID)
+	if err != nil {
+		return nil, nil, err
+	}
+	deltas := make([]*BranchDelta, len(deltaRecords))
+	for i, dr := range deltaRecords {
+		if deltas[i], err = deltaFromRecord(dr); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	h := &snapshotHistory{snapshot: snapshotFromRecord(record), deltas: deltas}
+	bm.history[snapshotID] = h
+	return h.snapshot, h.deltas, nil
+}
+
+// materialize rebuilds the branch state captured by a checkpoint
+func (bm *BacktrackingManager) materialize(checkpoint *Checkpoint) (*types.Branch, error) {
+	snapshot, deltas, err := bm.snapshotState(checkpoint.SnapshotID, checkpoint.BranchID)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot not found for checkpoint: %s", checkpoint.ID)
+	}
+
+	// Deep copy the snapshot branch
+	branch := bm.deepCopyBranch(snapshot.Branch)
+
+	// Apply deltas up to checkpoint
+	if checkpoint.DeltaCount > len(deltas) {
+		return nil, fmt.Errorf("delta count mismatch: checkpoint expects %d, have %d", checkpoint.DeltaCount, len(deltas))
+	}
+	for i := 0; i < checkpoint.DeltaCount; i++ {
+		if err := bm.applyDelta(branch, deltas[i]); err != nil {
+			return nil, fmt.Errorf("failed to apply delta %d: %w", i, err)
+		}
+	}
+
+	return branch, nil
+}
+
+// matchesHistory reports whether replaying the recorded deltas on the latest
+// snapshot reproduces the thoughts, insights and cross-refs of branch
+func (bm *BacktrackingManager) matchesHistory(branchID string, branch *types.Branch) bool {
+	snapshot := bm.snapshots[branchID]
+	if snapshot == nil {
+		return false
+	}
+
+	replayed := bm.deepCopyBranch(snapshot.Branch)
+	for _, delta := range bm.deltas[branchID] {
+		if err := bm.applyDelta(replayed, delta); err != nil {
+			return false
+		}
+	}
+
+	if len(replayed.Thoughts) != len(branch.Thoughts) ||
+		len(replayed.Insights) != len(branch.Insights) ||
+		len(replayed.CrossRefs) != len(branch.CrossRefs) {
+		return false
+	}
+	for i := range branch.Thoughts {
+		if replayed.Thoughts[i].ID != branch.Thoughts[i].ID {
+			return false
+		}
+	}
+	for i := range branch.Insights {
+		if replayed.Insights[i].ID != branch.Insights[i].ID {
+			return false
+		}
+	}
+	for i := range branch.CrossRefs {
+		if replayed.CrossRefs[i].ID != branch.CrossRefs[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
 func (bm *BacktrackingManager) deepCopyBranch(branch *types.Branch) *types.Branch {
 	// Deep copy branch
 	copied := &types.Branch{
@@ -502,7 +702,10 @@ func (bm *BacktrackingManager) applyDelta(branch *types.Branch, delta *BranchDel
 func (bm *BacktrackingManager) applyThoughtDelta(branch *types.Branch, delta *BranchDelta) error {
 	switch delta.Operation {
 	case DeltaAdd:
-		thought := delta.Entity.(*types.Thought)
+		thought, ok := delta.Entity.(*types.Thought)
+		if !ok {
+			return fmt.Errorf("delta %s has no thought entity", delta.ID)
+		}
 		branch.Thoughts = append(branch.Thoughts, bm.deepCopyThought(thought))
 	case DeltaRemove:
 		for i, t := range branch.Thoughts {
@@ -512,7 +715,10 @@ func (bm *BacktrackingManager) applyThoughtDelta(branch *types.Branch, delta *Br
 			}
 		}
 	case DeltaModify:
-		thought := delta.Entity.(*types.Thought)
+		thought, ok := delta.Entity.(*types.Thought)
+		if !ok {
+			return fmt.Errorf("delta %s has no thought entity", delta.ID)
+		}
 		for i, t := range branch.Thoughts {
 			if t.ID == delta.EntityID {
 				branch.Thoughts[i] = bm.deepCopyThought(thought)
@@ -526,7 +732,10 @@ func (bm *BacktrackingManager) applyThoughtDelta(branch *types.Branch, delta *Br
 func (bm *BacktrackingManager) applyInsightDelta(branch *types.Branch, delta *BranchDelta) error {
 	switch delta.Operation {
 	case DeltaAdd:
-		insight := delta.Entity.(*types.Insight)
+		insight, ok := delta.Entity.(*types.Insight)
+		if !ok {
+			return fmt.Errorf("delta %s has no insight entity", delta.ID)
+		}
 		branch.Insights = append(branch.Insights, bm.deepCopyInsight(insight))
 	case DeltaRemove:
 		for i, ins := range branch.Insights {
@@ -542,7 +751,10 @@ func (bm *BacktrackingManager) applyInsightDelta(branch *types.Branch, delta *Br
 func (bm *BacktrackingManager) applyCrossRefDelta(branch *types.Branch, delta *BranchDelta) error {
 	switch delta.Operation {
 	case DeltaAdd:
-		ref := delta.Entity.(*types.CrossRef)
+		ref, ok := delta.Entity.(*types.CrossRef)
+		if !ok {
+			return fmt.Errorf("delta %s has no cross-ref entity", delta.ID)
+		}
 		branch.CrossRefs = append(branch.CrossRefs, bm.deepCopyCrossRef(ref))
 	case DeltaRemove:
 		for i, r := range branch.CrossRefs {
@@ -554,3 +766,110 @@ func (bm *BacktrackingManager) applyCrossRefDelta(branch *types.Branch, delta *B
 	}
 	return nil
 }
+
+func checkpointToRecord(cp *Checkpoint) *types.CheckpointRecord {
+	return &types.CheckpointRecord{
+		ID:          cp.ID,
+		Name:        cp.Name,
+		Description: cp.Description,
+		BranchID:    cp.BranchID,
+		SnapshotID:  cp.SnapshotID,
+		DeltaCount:  cp.DeltaCount,
+		Metadata:    cp.Metadata,
+		CreatedAt:   cp.CreatedAt,
+	}
+}
+
+func checkpointFromRecord(record *types.CheckpointRecord) *Checkpoint {
+	metadata := record.Metadata
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	return &Checkpoint{
+		ID:          record.ID,
+		Name:        record.Name,
+		Description: record.Description,
+		BranchID:    record.BranchID,
+		SnapshotID:  record.SnapshotID,
+		DeltaCount:  record.DeltaCount,
+		CreatedAt:   record.CreatedAt,
+		Metadata:    metadata,
+	}
+}
+
+func snapshotToRecord(snapshot *BranchSnapshot) *types.BranchSnapshotRecord {
+	return &types.BranchSnapshotRecord{
+		ID:            snapshot.ID,
+		BranchID:      snapshot.BranchID,
+		Branch:        snapshot.Branch,
+		ThoughtCount:  snapshot.ThoughtCount,
+		InsightCount:  snapshot.InsightCount,
+		CrossRefCount: snapshot.CrossRefCount,
+		CreatedAt:     snapshot.CreatedAt,
+	}
+}
+
+func snapshotFromRecord(record *types.BranchSnapshotRecord) *BranchSnapshot {
+	return &BranchSnapshot{
+		ID:            record.ID,
+		BranchID:      record.BranchID,
+		Branch:        record.Branch,
+		CreatedAt:     record.CreatedAt,
+		ThoughtCount:  record.ThoughtCount,
+		InsightCount:  record.InsightCount,
+		CrossRefCount: record.CrossRefCount,
+	}
+}
+
+func deltaToRecord(delta *BranchDelta, snapshotID string) (*types.BranchDeltaRecord, error) {
+	record := &types.BranchDeltaRecord{
+		ID:         delta.ID,
+		SnapshotID: snapshotID,
+		BranchID:   delta.BranchID,
+		Operation:  string(delta.Operation),
+		EntityType: string(delta.EntityType),
+		EntityID:   delta.EntityID,
+		CreatedAt:  delta.CreatedAt,
+	}
+	if delta.Entity != nil {
+		data, err := json.Marshal(delta.Entity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal delta entity: %w", err)
+		}
+		record.Entity = data
+	}
+	return record, nil
+}
+
+// deltaFromRecord decodes a stored delta, restoring the concrete entity type
+// that applyDelta expects
+func deltaFromRecord(record *types.BranchDeltaRecord) (*BranchDelta, error) {
+	delta := &BranchDelta{
+		ID:         record.ID,
+		BranchID:   record.BranchID,
+		Operation:  DeltaOperation(record.Operation),
+		EntityType: EntityType(record.EntityType),
+		EntityID:   record.EntityID,
+		CreatedAt:  record.CreatedAt,
+	}
+	if len(record.Entity) == 0 || string(record.Entity) == "null" {
+		return delta, nil
+	}
+
+	var entity interface{}
+	switch delta.EntityType {
+	case EntityThought:
+		entity = &types.Thought{}
+	case EntityInsight:
+		entity = &types.Insight{}
+	case EntityCrossRef:
+		entity = &types.CrossRef{}
+	default:
+		return nil, fmt.Errorf("unknown entity type: %s", delta.EntityType)
+	}
+	if err := json.Unmarshal(record.Entity, entity); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal delta %s: %w", record.ID, err)
+	}
+	delta.Entity = entity
+	return delta, nil
+}
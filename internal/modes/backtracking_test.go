@@ -2,6 +2,7 @@ package modes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -399,3 +400,216 @@ func TestBacktrackingManager_ApplyDelta(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, branch.Thoughts, 0)
 }
+
+// memoryCheckpointStorage is an in-memory CheckpointStorage that round-trips
+// records through JSON, like the SQLite backend
+type memoryCheckpointStorage struct {
+	checkpoints map[string][]byte
+	order       []string
+	snapshots   map[string][]byte
+	deltas      map[string][][]byte
+}
+
+func newMemoryCheckpointStorage() *memoryCheckpointStorage {
+	return &memoryCheckpointStorage{
+		checkpoints: make(map[string][]byte),
+		snapshots:   make(map[string][]byte),
+		deltas:      make(map[string][][]byte),
+	}
+}
+
+func (m *memoryCheckpointStorage) StoreCheckpoint(cp *types.CheckpointRecord) error {
+	if _, exists := m.checkpoints[cp.ID]; !exists {
+		m.order = append(m.order, cp.ID)
+	}
+	data, err := json.Marshal(cp)
+	m.checkpoints[cp.ID] = data
+	return err
+}
+
+func (m *memoryCheckpointStorage) GetCheckpoint(id string) (*types.CheckpointRecord, error) {
+	data, exists := m.checkpoints[id]
+	if !exists {
+		return nil, fmt.Errorf("checkpoint not found: %s", id)
+	}
+	var cp types.CheckpointRecord
+	err := json.Unmarshal(data, &cp)
+	return &cp, err
+}
+
+func (m *memoryCheckpointStorage) ListCheckpoints(branchID string) ([]*types.CheckpointRecord, error) {
+	result := make([]*types.CheckpointRecord, 0)
+	for _, id := range m.order {
+		cp, err := m.GetCheckpoint(id)
+		if err != nil {
+			return nil, err
+		}
+		if branchID == "" || cp.BranchID == branchID {
+			result = append(result, cp)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryCheckpointStorage) StoreBranchSnapshot(snapshot *types.BranchSnapshotRecord) error {
+	data, err := json.Marshal(snapshot)
+	m.snapshots[snapshot.ID] = data
+	return err
+}
+
+func (m *memoryCheckpointStorage) GetBranchSnapshot(id string) (*types.BranchSnapshotRecord, error) {
+	data, exists := m.snapshots[id]
+	if !exists {
+		return nil, fmt.Errorf("branch snapshot not found: %s", id)
+	}
+	var snapshot types.BranchSnapshotRecord
+	err := json.Unmarshal(data, &snapshot)
+	return &snapshot, err
+}
+
+func (m *memoryCheckpointStorage) StoreBranchDelta(delta *types.BranchDeltaRecord) error {
+	data, err := json.Marshal(delta)
+	m.deltas[delta.SnapshotID] = append(m.deltas[delta.SnapshotID], data)
+	return err
+}
+
+func (m *memoryCheckpointStorage) GetBranchDeltas(snapshotID string) ([]*types.BranchDeltaRecord, error) {
+	result := make([]*types.BranchDeltaRecord, 0, len(m.deltas[snapshotID]))
+	for _, data := range m.deltas[snapshotID] {
+		var delta types.BranchDeltaRecord
+		if err := json.Unmarshal(data, &delta); err != nil {
+			return nil, err
+		}
+		result = append(result, &delta)
+	}
+	return result, nil
+}
+
+func TestBacktrackingManager_CheckpointsSurviveRestart(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	checkpointStore := newMemoryCheckpointStorage()
+	ctx := context.Background()
+
+	branch := &types.Branch{
+		ID:       "branch-1",
+		State:    types.StateActive,
+		Thoughts: []*types.Thought{{ID: "thought-1", Content: "Initial thought"}},
+	}
+	assert.NoError(t, store.StoreBranch(branch))
+
+	bm := NewBacktrackingManager(store)
+	bm.SetCheckpointStorage(checkpointStore)
+
+	cp1, err := bm.CreateCheckpoint(ctx, "branch-1", "cp1", "Before changes")
+	assert.NoError(t, err)
+
+	thought2 := &types.Thought{ID: "thought-2", Content: "Second thought"}
+	assert.NoError(t, bm.RecordChange("branch-1", DeltaAdd, EntityThought, thought2.ID, thought2))
+	branch.Thoughts = append(branch.Thoughts, thought2)
+	assert.NoError(t, store.StoreBranch(branch))
+
+	cp2, err := bm.CreateCheckpoint(ctx, "branch-1", "cp2", "After changes")
+	assert.NoError(t, err)
+	assert.Equal(t, cp1.SnapshotID, cp2.SnapshotID, "recorded change should be stored as a delta")
+	assert.Equal(t, 1, cp2.DeltaCount)
+
+	// A new manager over the same stores sees the checkpoints
+	restarted := NewBacktrackingManager(store)
+	restarted.SetCheckpointStorage(checkpointStore)
+
+	checkpoints := restarted.ListCheckpoints("branch-1")
+	assert.Len(t, checkpoints, 2)
+	assert.Equal(t, cp1.ID, checkpoints[0].ID)
+
+	diff, err := restarted.GetCheckpointDiff(cp1.ID, cp2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"thought-2"}, diff.ThoughtsAdded)
+	assert.Empty(t, diff.ThoughtsRemoved)
+
+	restored, err := restarted.RestoreCheckpoint(ctx, cp2.ID)
+	assert.NoError(t, err)
+	assert.Len(t, restored.Thoughts, 2)
+
+	restored, err = restarted.RestoreCheckpoint(ctx, cp1.ID)
+	assert.NoError(t, err)
+	assert.Len(t, restored.Thoughts, 1)
+}
+
+func TestBacktrackingManager_UnrecordedChangesTakeNewSnapshot(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	bm := NewBacktrackingManager(store)
+	ctx := context.Background()
+
+	branch := &types.Branch{
+		ID:       "branch-1",
+		State:    types.StateActive,
+		Thoughts: []*types.Thought{{ID: "thought-1", Content: "Initial thought"}},
+	}
+	assert.NoError(t, store.StoreBranch(branch))
+
+	cp1, err := bm.CreateCheckpoint(ctx, "branch-1", "cp1", "")
+	assert.NoError(t, err)
+
+	// Change the branch without RecordChange
+	branch.Thoughts = append(branch.Thoughts, &types.Thought{ID: "thought-2", Content: "Second thought"})
+	assert.NoError(t, store.StoreBranch(branch))
+
+	cp2, err := bm.CreateCheckpoint(ctx, "branch-1", "cp2", "")
+	assert.NoError(t, err)
+	assert.NotEqual(t, cp1.SnapshotID, cp2.SnapshotID)
+
+	restored, err := bm.RestoreCheckpoint(ctx, cp2.ID)
+	assert.NoError(t, err)
+	assert.Len(t, restored.Thoughts, 2)
+
+	// The superseded snapshot still backs the first checkpoint
+	restored, err = bm.RestoreCheckpoint(ctx, cp1.ID)
+	assert.NoError(t, err)
+	assert.Len(t, restored.Thoughts, 1)
+}
+
+func TestBacktrackingManager_ForkLeavesSourceBranchAndDiffsAcrossBranches(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	bm := NewBacktrackingManager(store)
+	ctx := context.Background()
+
+	branch := &types.Branch{
+		ID:       "branch-1",
+		State:    types.StateActive,
+		Thoughts: []*types.Thought{{ID: "thought-1", Content: "Initial thought"}},
+	}
+	assert.NoError(t, store.StoreBranch(branch))
+
+	cp, err := bm.CreateCheckpoint(ctx, "branch-1", "fork point", "")
+	assert.NoError(t, err)
+
+	// The source branch moves on after the checkpoint
+	branch.Thoughts = append(branch.Thoughts, &types.Thought{ID: "thought-2", Content: "Approach A"})
+	assert.NoError(t, store.StoreBranch(branch))
+
+	forked, err := bm.ForkFromCheckpoint(ctx, cp.ID, "approach B")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, forked.ID)
+	assert.NotEqual(t, "branch-1", forked.ID)
+	assert.Equal(t, "approach B", forked.Thoughts[0].Metadata["fork_name"])
+
+	source, err := store.GetBranch("branch-1")
+	assert.NoError(t, err)
+	assert.Len(t, source.Thoughts, 2, "forking must not reset the source branch")
+
+	// Diverge the fork and compare its checkpoint with the source
+	forked.Thoughts[0].Content = "Initial thought, revised"
+	forked.Thoughts = append(forked.Thoughts, &types.Thought{ID: "thought-3", Content: "Approach B"})
+	assert.NoError(t, store.StoreBranch(forked))
+
+	sourceCP, err := bm.CreateCheckpoint(ctx, "branch-1", "approach A", "")
+	assert.NoError(t, err)
+	forkCP, err := bm.CreateCheckpoint(ctx, forked.ID, "approach B", "")
+	assert.NoError(t, err)
+
+	diff, err := bm.GetCheckpointDiff(sourceCP.ID, forkCP.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"thought-3"}, diff.ThoughtsAdded)
+	assert.Equal(t, []string{"thought-2"}, diff.ThoughtsRemoved)
+	assert.Equal(t, []string{"thought-1"}, diff.ThoughtsModified)
+}
@@ -52,6 +52,8 @@ func NewDivergentMode(storage storage.Storage) *DivergentMode {
 
 // ProcessThought generates creative/unconventional thoughts
 func (m *DivergentMode) ProcessThought(ctx context.Context, input ThoughtInput) (*ThoughtResult, error) {
+	store := scopedStorage(m.storage, input.Workspace)
+
 	// Generate creative thought
 	creativeContent := m.generateCreativeThought(input.Content, input.ForceRebellion)
 
@@ -69,7 +71,7 @@ func (m *DivergentMode) ProcessThought(ctx context.Context, input ThoughtInput)
 		thought.ParentID = input.PreviousThoughtID
 	}
 
-	if err := m.storage.StoreThought(thought); err != nil {
+	if err := store.StoreThought(thought); err != nil {
 		return nil, err
 	}
 
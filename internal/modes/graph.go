@@ -2,25 +2,155 @@
 package modes
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/dominikbraun/graph"
 	"unified-thinking/internal/storage"
+	"unified-thinking/internal/types"
 )
 
-// GraphController manages Graph-of-Thoughts reasoning
+// DefaultGraphStateTTL is how long an untouched graph is kept before cleanup
+const DefaultGraphStateTTL = 7 * 24 * time.Hour
+
+// graphCleanupInterval limits how often expired graphs are swept
+const graphCleanupInterval = time.Hour
+
+// GraphStateStorage persists Graph-of-Thoughts states across restarts
+type GraphStateStorage interface {
+	StoreGraphState(record *types.GraphStateRecord) error
+	GetGraphState(id string) (*types.GraphStateRecord, error)
+	ListGraphStates(limit, offset int) ([]*types.GraphStateSummary, error)
+	DeleteGraphState(id string) error
+	DeleteGraphStatesBefore(cutoff time.Time) (int, error)
+}
+
+// GraphController manages Graph-of-Thoughts reasoning.
+//
+// The operations (Initialize, Generate, Aggregate, Refine, Score, Prune,
+// Explore) and the active/terminal setters write the state through to the
+// state store. The building blocks AddVertex, AddEdge and RemoveVertex do
+// not; the operations built on them persist once they complete.
+//
+// Graphs are partitioned by workspace: the root controller serves the
+// workspace of its storage and owns one child controller per additional
+// workspace (see ForWorkspace). Children share the root's scorers, TTL and
+// cleanup.
 type GraphController struct {
-	storage storage.Storage
-	states  map[string]*GraphState // Active graph states
+	mu          sync.RWMutex
+	storage     storage.Storage
+	stateStore  GraphStateStorage
+	states      map[string]*GraphState // Active graph states
+	stateTTL    time.Duration
+	lastCleanup time.Time
+	scorers     map[string]VertexScorer
+
+	workspace   string
+	root        *GraphController // nil for the root controller
+	workspaceMu sync.Mutex
+	children    map[string]*GraphController
 }
 
 // NewGraphController creates a new graph controller
 func NewGraphController(store storage.Storage) *GraphController {
 	return &GraphController{
-		storage: store,
-		states:  make(map[string]*GraphState),
+		storage:   store,
+		states:    make(map[string]*GraphState),
+		stateTTL:  DefaultGraphStateTTL,
+		workspace: storage.WorkspaceOf(store),
+	}
+}
+
+// base returns the root controller
+func (gc *GraphController) base() *GraphController {
+	if gc.root != nil {
+		return gc.root
 	}
+	return gc
+}
+
+// Workspace returns the workspace whose graphs this controller manages
+func (gc *GraphController) Workspace() string {
+	return gc.workspace
+}
+
+// ForWorkspace returns the controller for the given workspace, creating it
+// on first use. Its graphs persist through the workspace's view of the state
+// store, so graph IDs, listings and deletions are scoped to the workspace.
+func (gc *GraphController) ForWorkspace(workspace string) *GraphController {
+	workspace = storage.NormalizeWorkspace(workspace)
+	root := gc.base()
+	if workspace == root.workspace {
+		return root
+	}
+
+	root.workspaceMu.Lock()
+	defer root.workspaceMu.Unlock()
+	if child, exists := root.children[workspace]; exists {
+		return child
+	}
+
+	root.mu.RLock()
+	child := &GraphController{
+		storage:   storage.ForWorkspace(root.storage, workspace),
+		states:    make(map[string]*GraphState),
+		stateTTL:  root.stateTTL,
+		workspace: workspace,
+		root:      root,
+	}
+	if viewer, ok := root.stateStore.(storage.Storage); ok {
+		if view, ok := storage.ForWorkspace(viewer, workspace).(GraphStateStorage); ok {
+			child.stateStore = view
+		}
+	}
+	root.mu.RUnlock()
+
+	if root.children == nil {
+		root.children = make(map[string]*GraphController)
+	}
+	root.children[workspace] = child
+	return child
+}
+
+// SetStateStorage enables persistence of graph states. Graphs missing from
+// memory are loaded from the store, so they can be continued after a restart.
+// Set it on the root controller before ForWorkspace is used.
+func (gc *GraphController) SetStateStorage(store GraphStateStorage) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.stateStore = store
+}
+
+// SetStateTTL sets how long a graph may go without updates before it is
+// removed. A zero or negative TTL disables cleanup.
+func (gc *GraphController) SetStateTTL(ttl time.Duration) {
+	root := gc.base()
+	root.mu.Lock()
+	root.stateTTL = ttl
+	root.mu.Unlock()
+
+	for _, child := range root.workspaceControllers() {
+		child.mu.Lock()
+		child.stateTTL = ttl
+		child.mu.Unlock()
+	}
+}
+
+// workspaceControllers returns the child controllers of the root
+func (gc *GraphController) workspaceControllers() []*GraphController {
+	root := gc.base()
+	root.workspaceMu.Lock()
+	defer root.workspaceMu.Unlock()
+	children := make([]*GraphController, 0, len(root.children))
+	for _, child := range root.children {
+		children = append(children, child)
+	}
+	return children
 }
 
 // Initialize creates a new graph with an initial thought
@@ -28,6 +158,9 @@ func (gc *GraphController) Initialize(id, initialContent string, config *GraphCo
 	if config == nil {
 		config = DefaultGraphConfig()
 	}
+	if err := gc.validateScoringWeights(config.ScoringWeights); err != nil {
+		return nil, err
+	}
 
 	// Create directed graph
 	g := graph.New(VertexHash, graph.Directed())
@@ -61,18 +194,175 @@ func (gc *GraphController) Initialize(id, initialContent string, config *GraphCo
 	}
 
 	// Store state
+	gc.maybeCleanup()
+	gc.mu.Lock()
 	gc.states[id] = state
+	gc.mu.Unlock()
+
+	if err := gc.persist(state); err != nil {
+		return nil, err
+	}
 
 	return state, nil
 }
 
-// GetState retrieves a graph state
+// GetState retrieves a graph state, loading it from the state store when it
+// is not in memory
 func (gc *GraphController) GetState(id string) (*GraphState, error) {
+	gc.mu.RLock()
 	state, ok := gc.states[id]
-	if !ok {
-		return nil, fmt.Errorf("graph state not found: %s", id)
+	stateStore := gc.stateStore
+	gc.mu.RUnlock()
+	if ok {
+		return state, nil
+	}
+
+	if stateStore != nil {
+		if record, err := stateStore.GetGraphState(id); err == nil {
+			loaded, err := restoreGraphState(record.State)
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore graph state %s: %w", id, err)
+			}
+
+			gc.mu.Lock()
+			defer gc.mu.Unlock()
+			// Another caller may have loaded it meanwhile
+			if existing, ok := gc.states[id]; ok {
+				return existing, nil
+			}
+			gc.states[id] = loaded
+			return loaded, nil
+		}
 	}
-	return state, nil
+
+	return nil, fmt.Errorf("graph state not found: %s", id)
+}
+
+// SaveState writes a graph state through to the state store
+func (gc *GraphController) SaveState(stateID string) error {
+	state, err := gc.GetState(stateID)
+	if err != nil {
+		return err
+	}
+	return gc.persist(state)
+}
+
+// ListStates returns summaries of known graphs, most recently updated first.
+// A limit of zero or less returns all graphs.
+func (gc *GraphController) ListStates(limit, offset int) ([]*types.GraphStateSummary, error) {
+	gc.maybeCleanup()
+
+	gc.mu.RLock()
+	stateStore := gc.stateStore
+	gc.mu.RUnlock()
+	if stateStore != nil {
+		return stateStore.ListGraphStates(limit, offset)
+	}
+
+	gc.mu.RLock()
+	summaries := make([]*types.GraphStateSummary, 0, len(gc.states))
+	for _, state := range gc.states {
+		summaries = append(summaries, summarizeGraphState(state))
+	}
+	gc.mu.RUnlock()
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].UpdatedAt.Equal(summaries[j].UpdatedAt) {
+			return summaries[i].ID < summaries[j].ID
+		}
+		return summaries[i].UpdatedAt.After(summaries[j].UpdatedAt)
+	})
+
+	if offset >= len(summaries) {
+		return []*types.GraphStateSummary{}, nil
+	}
+	summaries = summaries[offset:]
+	if limit > 0 && limit < len(summaries) {
+		summaries = summaries[:limit]
+	}
+	return summaries, nil
+}
+
+// CleanupExpired removes graphs that have not been updated within the state
+// TTL, in every workspace, and returns how many were removed
+func (gc *GraphController) CleanupExpired() (int, error) {
+	root := gc.base()
+	root.mu.Lock()
+	root.lastCleanup = time.Now()
+	ttl := root.stateTTL
+	stateStore := root.stateStore
+	root.mu.Unlock()
+
+	if ttl <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().Add(-ttl)
+
+	removed := root.removeStatesBefore(cutoff)
+	for _, child := range root.workspaceControllers() {
+		removed += child.removeStatesBefore(cutoff)
+	}
+
+	// Every in-memory graph is also in the store, so its count is authoritative
+	if stateStore != nil {
+		return stateStore.DeleteGraphStatesBefore(cutoff)
+	}
+	return removed, nil
+}
+
+// removeStatesBefore drops in-memory graphs last updated before cutoff
+func (gc *GraphController) removeStatesBefore(cutoff time.Time) int {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	removed := 0
+	for id, state := range gc.states {
+		if state.UpdatedAt.Before(cutoff) {
+			delete(gc.states, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// maybeCleanup sweeps expired graphs at most once per cleanup interval
+func (gc *GraphController) maybeCleanup() {
+	root := gc.base()
+	root.mu.RLock()
+	due := root.stateTTL > 0 && time.Since(root.lastCleanup) >= graphCleanupInterval
+	root.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	if removed, err := root.CleanupExpired(); err != nil {
+		log.Printf("Warning: failed to clean up expired graph states: %v", err)
+	} else if removed > 0 {
+		log.Printf("Removed %d expired graph states", removed)
+	}
+}
+
+// persist writes a state to the state store, if one is configured
+func (gc *GraphController) persist(state *GraphState) error {
+	gc.mu.RLock()
+	stateStore := gc.stateStore
+	gc.mu.RUnlock()
+	if stateStore == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal graph state: %w", err)
+	}
+	record := &types.GraphStateRecord{
+		GraphStateSummary: *summarizeGraphState(state),
+		State:             data,
+	}
+	if err := stateStore.StoreGraphState(record); err != nil {
+		return fmt.Errorf("failed to persist graph state: %w", err)
+	}
+	return nil
 }
 
 // AddVertex adds a new thought vertex to the graph
@@ -164,7 +454,7 @@ func (gc *GraphController) SetActiveVertices(stateID string, vertexIDs []string)
 	state.ActiveIDs = vertexIDs
 	state.UpdatedAt = time.Now()
 
-	return nil
+	return gc.persist(state)
 }
 
 // SetTerminalVertices marks final conclusions
@@ -184,7 +474,7 @@ func (gc *GraphController) SetTerminalVertices(stateID string, vertexIDs []strin
 	state.TerminalIDs = vertexIDs
 	state.UpdatedAt = time.Now()
 
-	return nil
+	return gc.persist(state)
 }
 
 // RemoveVertex removes a vertex and its edges
@@ -282,6 +572,59 @@ func (gc *GraphController) GetChildVertices(stateID, vertexID string) ([]*Though
 
 // Helper functions
 
+// summarizeGraphState describes a state for listing
+func summarizeGraphState(state *GraphState) *types.GraphStateSummary {
+	summary := &types.GraphStateSummary{
+		ID:            state.ID,
+		VertexCount:   len(state.Vertices),
+		EdgeCount:     len(state.Edges),
+		ActiveCount:   len(state.ActiveIDs),
+		TerminalCount: len(state.TerminalIDs),
+		CreatedAt:     state.CreatedAt,
+		UpdatedAt:     state.UpdatedAt,
+	}
+	if len(state.RootIDs) > 0 {
+		if root, ok := state.Vertices[state.RootIDs[0]]; ok {
+			summary.InitialThought = root.Content
+		}
+	}
+	return summary
+}
+
+// restoreGraphState decodes a serialized state and rebuilds its graph from
+// the vertex and edge maps
+func restoreGraphState(data []byte) (*GraphState, error) {
+	state := &GraphState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+
+	if state.Vertices == nil {
+		state.Vertices = make(map[string]*ThoughtVertex)
+	}
+	if state.Edges == nil {
+		state.Edges = make(map[string]*ThoughtEdge)
+	}
+	if state.Config == nil {
+		state.Config = DefaultGraphConfig()
+	}
+
+	g := graph.New(VertexHash, graph.Directed())
+	for _, vertex := range state.Vertices {
+		if err := g.AddVertex(vertex); err != nil {
+			return nil, fmt.Errorf("failed to add vertex %s: %w", vertex.ID, err)
+		}
+	}
+	for _, edge := range state.Edges {
+		if err := g.AddEdge(edge.FromID, edge.ToID); err != nil && !errors.Is(err, graph.ErrEdgeAlreadyExists) {
+			return nil, fmt.Errorf("failed to add edge %s: %w", edge.ID, err)
+		}
+	}
+	state.Graph = g
+
+	return state, nil
+}
+
 func containsString(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -0,0 +1,388 @@
+// Package modes - Monte Carlo Tree Search exploration for Graph-of-Thoughts
+package modes
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MCTSConfig controls the MCTS exploration strategy and its budget
+type MCTSConfig struct {
+	Iterations          int     `json:"iterations"`           // Select → expand → evaluate → backpropagate cycles (default: 20)
+	MaxLLMCalls         int     `json:"max_llm_calls"`        // Generate and Score calls allowed (default: 40)
+	ExplorationConstant float64 `json:"exploration_constant"` // UCT exploration weight c (default: √2)
+	ExpansionWidth      int     `json:"expansion_width"`      // Children generated per expansion (default: 3)
+	MaxDepth            int     `json:"max_depth"`            // Deepest expandable vertex (default: graph MaxDepth)
+}
+
+// MCTSVertexStats reports the search statistics of a vertex
+type MCTSVertexStats struct {
+	VertexID   string  `json:"vertex_id"`
+	Visits     int     `json:"visits"`
+	TotalValue float64 `json:"total_value"`
+	MeanValue  float64 `json:"mean_value"` // Value estimate: TotalValue / Visits
+}
+
+// DefaultMCTSConfig returns the default MCTS budget
+func DefaultMCTSConfig() *MCTSConfig {
+	return &MCTSConfig{
+		Iterations:          20,
+		MaxLLMCalls:         40,
+		ExplorationConstant: math.Sqrt2,
+		ExpansionWidth:      3,
+	}
+}
+
+// mctsSearch holds the state of one MCTS run
+type mctsSearch struct {
+	gc       *GraphController
+	llm      LLMClient
+	graphID  string
+	problem  string
+	config   *MCTSConfig
+	state    *GraphState
+	rootID   string
+	stats    map[string]*MCTSVertexStats
+	expanded map[string]bool
+	llmCalls int
+}
+
+// exploreMCTS runs Monte Carlo Tree Search from the graph's root.
+//
+// Each iteration selects a leaf by UCT over visited vertices. An unvisited
+// leaf is evaluated with LLMClient.Score; a visited one is expanded with
+// LLMClient.Generate and its first new child is evaluated. The value is then
+// backpropagated along the selected ThoughtEdge path. The search stops when
+// the iteration or LLM call budget is exhausted. Visit counts and value
+// estimates are stored in vertex metadata ("mcts_visits", "mcts_value") and
+// returned in the result.
+func (gc *GraphController) exploreMCTS(ctx context.Context, graphID string, llm LLMClient, req ExploreRequest, result *ExploreResult) (*ExploreResult, error) {
+	config := resolveMCTSConfig(req.MCTS)
+
+	state, err := gc.GetState(graphID)
+	if err != nil {
+		return nil, err
+	}
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = state.Config.MaxDepth
+	}
+
+	search := &mctsSearch{
+		gc:       gc,
+		llm:      llm,
+		graphID:  graphID,
+		problem:  req.Problem,
+		config:   config,
+		state:    state,
+		rootID:   state.RootIDs[0],
+		stats:    make(map[string]*MCTSVertexStats),
+		expanded: make(map[string]bool),
+	}
+
+	stepNum := len(result.ExplorationPath)
+	for iteration := 0; iteration < config.Iterations; iteration++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		path := search.selectPath()
+		leafID := path[len(path)-1]
+
+		// A leaf that was already evaluated is expanded, and its first new
+		// child is evaluated instead
+		action := "evaluate"
+		var children []*ThoughtVertex
+		if search.visits(leafID) > 0 {
+			var ok bool
+			children, ok, err = search.expand(ctx, leafID)
+			if err != nil {
+				return nil, fmt.Errorf("expansion failed at iteration %d: %w", iteration, err)
+			}
+			if !ok {
+				break // LLM call budget exhausted
+			}
+		}
+		if len(children) > 0 {
+			action = "expand"
+			result.TotalGenerated += len(children)
+			path = append(path, children[0].ID)
+		}
+
+		evaluatedID := path[len(path)-1]
+		value, ok, err := search.evaluate(ctx, evaluatedID)
+		if err != nil {
+			return nil, fmt.Errorf("evaluation failed at iteration %d: %w", iteration, err)
+		}
+		if !ok {
+			break // LLM call budget exhausted
+		}
+
+		search.backpropagate(path, value)
+		result.Iterations = iteration + 1
+
+		stepNum++
+		details := fmt.Sprintf("Iteration %d: evaluated %s (depth %d) = %.2f", iteration+1, evaluatedID, state.Vertices[evaluatedID].Depth, value)
+		if len(children) > 0 {
+			details = fmt.Sprintf("Iteration %d: expanded %s into %d children, evaluated %s = %.2f", iteration+1, leafID, len(children), evaluatedID, value)
+		}
+		result.ExplorationPath = append(result.ExplorationPath, ExplorationStep{
+			Step:        stepNum,
+			Action:      action,
+			VertexCount: len(children),
+			Details:     details,
+		})
+	}
+
+	// Finalize - the best value estimates become conclusions
+	bestVertices := search.bestVertices(3)
+	terminalIDs := make([]string, len(bestVertices))
+	for i, v := range bestVertices {
+		terminalIDs[i] = v.ID
+	}
+
+	search.recordStats()
+	if len(terminalIDs) > 0 {
+		if err := gc.SetTerminalVertices(graphID, terminalIDs); err != nil {
+			return nil, err
+		}
+	} else if err := gc.persist(state); err != nil {
+		return nil, err
+	}
+
+	stepNum++
+	result.ExplorationPath = append(result.ExplorationPath, ExplorationStep{
+		Step:        stepNum,
+		Action:      "finalize",
+		VertexCount: len(bestVertices),
+		Details:     fmt.Sprintf("Finalized with %d best conclusions after %d LLM calls", len(bestVertices), search.llmCalls),
+	})
+
+	result.LLMCalls = search.llmCalls
+	result.BestVertices = bestVertices
+	result.Conclusions = bestVertices
+	result.VertexStats = search.sortedStats()
+
+	return result, nil
+}
+
+// resolveMCTSConfig fills unset fields with defaults
+func resolveMCTSConfig(config *MCTSConfig) *MCTSConfig {
+	defaults := DefaultMCTSConfig()
+	if config == nil {
+		return defaults
+	}
+
+	resolved := *config
+	if resolved.Iterations <= 0 {
+		resolved.Iterations = defaults.Iterations
+	}
+	if resolved.MaxLLMCalls <= 0 {
+		resolved.MaxLLMCalls = defaults.MaxLLMCalls
+	}
+	if resolved.ExplorationConstant <= 0 {
+		resolved.ExplorationConstant = defaults.ExplorationConstant
+	}
+	if resolved.ExpansionWidth <= 0 {
+		resolved.ExpansionWidth = defaults.ExpansionWidth
+	}
+	if resolved.ExpansionWidth > 10 {
+		resolved.ExpansionWidth = 10 // Generate limit
+	}
+	return &resolved
+}
+
+// selectPath descends from the root by UCT until it reaches a vertex that
+// has not been expanded or has no children left
+func (m *mctsSearch) selectPath() []string {
+	path := []string{m.rootID}
+	current := m.rootID
+
+	for m.expanded[current] {
+		children := m.children(current)
+		if len(children) == 0 {
+			break
+		}
+
+		parentVisits := m.visits(current)
+		bestID := ""
+		bestUCT := math.Inf(-1)
+		for _, childID := range children {
+			uct := m.uct(childID, parentVisits)
+			if uct > bestUCT {
+				bestID, bestUCT = childID, uct
+			}
+		}
+
+		path = append(path, bestID)
+		current = bestID
+	}
+
+	return path
+}
+
+// uct is the upper confidence bound of a child. Unvisited children are
+// always tried first.
+func (m *mctsSearch) uct(vertexID string, parentVisits int) float64 {
+	stats, ok := m.stats[vertexID]
+	if !ok || stats.Visits == 0 {
+		return math.Inf(1)
+	}
+	exploration := m.config.ExplorationConstant * math.Sqrt(math.Log(float64(parentVisits))/float64(stats.Visits))
+	return stats.MeanValue + exploration
+}
+
+// expand generates children for a leaf, within the depth and vertex limits.
+// It returns no children when the leaf cannot be expanded, and ok is false
+// when it could be but the LLM call budget is exhausted.
+func (m *mctsSearch) expand(ctx context.Context, vertexID string) ([]*ThoughtVertex, bool, error) {
+	vertex := m.state.Vertices[vertexID]
+	if m.expanded[vertexID] || vertex.Depth >= m.config.MaxDepth {
+		return nil, true, nil
+	}
+
+	k := m.config.ExpansionWidth
+	if remaining := m.state.Config.MaxVertices - len(m.state.Vertices); remaining < k {
+		k = remaining
+	}
+	if k <= 0 {
+		return nil, true, nil
+	}
+
+	// Expansion costs a Generate call, and its child needs a Score call
+	if m.llmCalls+2 > m.config.MaxLLMCalls {
+		return nil, false, nil
+	}
+
+	m.llmCalls++
+	children, err := m.gc.Generate(ctx, m.graphID, m.llm, GenerateRequest{
+		SourceVertexIDs: []string{vertexID},
+		K:               k,
+		Problem:         m.problem,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	m.expanded[vertexID] = true
+
+	return children, true, nil
+}
+
+// evaluate scores a vertex with the LLM. Vertices scored earlier reuse their
+// score without a call. ok is false when the call budget is exhausted.
+func (m *mctsSearch) evaluate(ctx context.Context, vertexID string) (float64, bool, error) {
+	vertex := m.state.Vertices[vertexID]
+	if vertex.Score > 0 {
+		return vertex.Score, true, nil
+	}
+	if m.llmCalls >= m.config.MaxLLMCalls {
+		return 0, false, nil
+	}
+
+	m.llmCalls++
+	breakdown, err := m.gc.scoreVertex(ctx, m.graphID, m.llm, ScoreRequest{
+		VertexID: vertexID,
+		Problem:  m.problem,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return breakdown.Overall, true, nil
+}
+
+// backpropagate adds a value to every vertex on the selected path
+func (m *mctsSearch) backpropagate(path []string, value float64) {
+	for _, vertexID := range path {
+		stats, ok := m.stats[vertexID]
+		if !ok {
+			stats = &MCTSVertexStats{VertexID: vertexID}
+			m.stats[vertexID] = stats
+		}
+		stats.Visits++
+		stats.TotalValue += value
+		stats.MeanValue = stats.TotalValue / float64(stats.Visits)
+	}
+}
+
+// children returns the vertices derived from a vertex that are still in the
+// graph
+func (m *mctsSearch) children(vertexID string) []string {
+	children := []string{}
+	for _, childID := range m.state.Vertices[vertexID].ChildIDs {
+		if _, ok := m.state.Vertices[childID]; ok {
+			children = append(children, childID)
+		}
+	}
+	return children
+}
+
+func (m *mctsSearch) visits(vertexID string) int {
+	if stats, ok := m.stats[vertexID]; ok {
+		return stats.Visits
+	}
+	return 0
+}
+
+// bestVertices returns up to n visited non-root vertices with the highest
+// value estimates, breaking ties by visit count
+func (m *mctsSearch) bestVertices(n int) []*ThoughtVertex {
+	candidates := make([]*MCTSVertexStats, 0, len(m.stats))
+	for vertexID, stats := range m.stats {
+		if vertexID != m.rootID && stats.Visits > 0 {
+			candidates = append(candidates, stats)
+		}
+	}
+	sortMCTSStats(candidates)
+
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	best := make([]*ThoughtVertex, n)
+	for i := 0; i < n; i++ {
+		best[i] = m.state.Vertices[candidates[i].VertexID]
+	}
+	return best
+}
+
+// recordStats stores visit counts and value estimates in vertex metadata
+func (m *mctsSearch) recordStats() {
+	for vertexID, stats := range m.stats {
+		vertex, ok := m.state.Vertices[vertexID]
+		if !ok {
+			continue
+		}
+		if vertex.Metadata == nil {
+			vertex.Metadata = make(map[string]interface{})
+		}
+		vertex.Metadata["mcts_visits"] = stats.Visits
+		vertex.Metadata["mcts_value"] = stats.MeanValue
+	}
+}
+
+// sortedStats returns the statistics of every visited vertex, best first
+func (m *mctsSearch) sortedStats() []MCTSVertexStats {
+	all := make([]*MCTSVertexStats, 0, len(m.stats))
+	for _, stats := range m.stats {
+		all = append(all, stats)
+	}
+	sortMCTSStats(all)
+
+	sorted := make([]MCTSVertexStats, len(all))
+	for i, stats := range all {
+		sorted[i] = *stats
+	}
+	return sorted
+}
+
+func sortMCTSStats(stats []*MCTSVertexStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].MeanValue != stats[j].MeanValue {
+			return stats[i].MeanValue > stats[j].MeanValue
+		}
+		if stats[i].Visits != stats[j].Visits {
+			return stats[i].Visits > stats[j].Visits
+		}
+		return stats[i].VertexID < stats[j].VertexID
+	})
+}
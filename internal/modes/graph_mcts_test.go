@@ -0,0 +1,143 @@
+package modes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"unified-thinking/internal/storage"
+)
+
+// mctsTestLLM scores thoughts descending from a second continuation poorly
+// and all others highly, and counts LLM calls
+type mctsTestLLM struct {
+	testLLMClient
+	generateCalls int
+	scoreCalls    int
+}
+
+func (m *mctsTestLLM) Generate(ctx context.Context, prompt string, k int) ([]string, error) {
+	m.generateCalls++
+	return m.testLLMClient.Generate(ctx, prompt, k)
+}
+
+func (m *mctsTestLLM) Score(ctx context.Context, thought string, problem string, criteria map[string]float64) (float64, map[string]float64, error) {
+	m.scoreCalls++
+	value := 0.9
+	if strings.Contains(thought, "continuation 2") {
+		value = 0.3
+	}
+	breakdown := map[string]float64{
+		"confidence":   value,
+		"validity":     value,
+		"relevance":    value,
+		"novelty":      value,
+		"depth_factor": value,
+	}
+	return value, breakdown, nil
+}
+
+func TestExplore_MCTS(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+	llm := &mctsTestLLM{}
+
+	result, err := gc.Explore(context.Background(), "mcts-graph", llm, ExploreRequest{
+		InitialThought: "Design a cache",
+		Problem:        "Choose a cache invalidation strategy",
+		Strategy:       ExploreStrategyMCTS,
+		MCTS:           &MCTSConfig{Iterations: 12, MaxLLMCalls: 30, ExpansionWidth: 2},
+	})
+	if err != nil {
+		t.Fatalf("Explore failed: %v", err)
+	}
+
+	if result.Strategy != ExploreStrategyMCTS {
+		t.Errorf("Strategy = %q, want mcts", result.Strategy)
+	}
+	if result.LLMCalls != llm.generateCalls+llm.scoreCalls {
+		t.Errorf("LLMCalls = %d, client saw %d", result.LLMCalls, llm.generateCalls+llm.scoreCalls)
+	}
+	if result.LLMCalls > 30 {
+		t.Errorf("LLMCalls = %d exceeds budget 30", result.LLMCalls)
+	}
+	if result.Iterations == 0 || result.TotalGenerated == 0 {
+		t.Fatalf("expected iterations and generated vertices, got %+v", result)
+	}
+
+	stats := make(map[string]MCTSVertexStats)
+	for _, s := range result.VertexStats {
+		stats[s.VertexID] = s
+	}
+	root := stats["mcts-graph-vertex-0"]
+	if root.Visits != result.Iterations {
+		t.Errorf("root visits = %d, want %d", root.Visits, result.Iterations)
+	}
+
+	// The search should favor the highly scored first continuation
+	good := stats["mcts-graph-gen-mcts-graph-vertex-0-0"]
+	poor := stats["mcts-graph-gen-mcts-graph-vertex-0-1"]
+	if good.Visits <= poor.Visits {
+		t.Errorf("good child visits = %d, poor child visits = %d; want good > poor", good.Visits, poor.Visits)
+	}
+	if good.MeanValue <= poor.MeanValue {
+		t.Errorf("good child value = %.2f, poor child value = %.2f", good.MeanValue, poor.MeanValue)
+	}
+
+	if len(result.BestVertices) == 0 || strings.Contains(result.BestVertices[0].Content, "continuation 2") {
+		t.Errorf("best vertex = %+v, want one outside the poor branch", result.BestVertices)
+	}
+
+	state, _ := gc.GetState("mcts-graph")
+	vertex := state.Vertices[good.VertexID]
+	if vertex.Metadata["mcts_visits"] != good.Visits {
+		t.Errorf("vertex metadata mcts_visits = %v, want %d", vertex.Metadata["mcts_visits"], good.Visits)
+	}
+	if len(state.TerminalIDs) != len(result.BestVertices) {
+		t.Errorf("terminal vertices = %v, want best vertices", state.TerminalIDs)
+	}
+}
+
+func TestExplore_MCTSBudget(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+	llm := &mctsTestLLM{}
+
+	result, err := gc.Explore(context.Background(), "mcts-budget", llm, ExploreRequest{
+		InitialThought: "Design a cache",
+		Problem:        "Choose a cache invalidation strategy",
+		Strategy:       ExploreStrategyMCTS,
+		MCTS:           &MCTSConfig{Iterations: 50, MaxLLMCalls: 5},
+	})
+	if err != nil {
+		t.Fatalf("Explore failed: %v", err)
+	}
+
+	if calls := llm.generateCalls + llm.scoreCalls; calls > 5 {
+		t.Errorf("made %d LLM calls, budget is 5", calls)
+	}
+	if result.Iterations >= 50 {
+		t.Errorf("Iterations = %d, expected the call budget to stop the search first", result.Iterations)
+	}
+}
+
+func TestExplore_UnknownStrategy(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+
+	_, err := gc.Explore(context.Background(), "bad-strategy", &testLLMClient{}, ExploreRequest{
+		InitialThought: "Start",
+		Problem:        "Problem",
+		Strategy:       "beam",
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown exploration strategy") {
+		t.Errorf("expected unknown strategy error, got %v", err)
+	}
+}
+
+func TestResolveMCTSConfig(t *testing.T) {
+	config := resolveMCTSConfig(&MCTSConfig{Iterations: 5, ExpansionWidth: 20})
+	if config.Iterations != 5 || config.MaxLLMCalls != 40 || config.ExpansionWidth != 10 || config.ExplorationConstant == 0 {
+		t.Errorf("resolveMCTSConfig() = %+v", config)
+	}
+	if defaults := resolveMCTSConfig(nil); defaults.Iterations != 20 {
+		t.Errorf("default iterations = %d, want 20", defaults.Iterations)
+	}
+}
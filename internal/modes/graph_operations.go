@@ -82,6 +82,10 @@ func (gc *GraphController) Generate(ctx context.Context, stateID string, llm LLM
 		_ = gc.SetActiveVertices(stateID, newActiveIDs)
 	}
 
+	if err := gc.persist(state); err != nil {
+		return nil, err
+	}
+
 	return newVertices, nil
 }
 
@@ -136,6 +140,10 @@ func (gc *GraphController) Aggregate(ctx context.Context, stateID string, llm LL
 		}
 	}
 
+	if err := gc.persist(state); err != nil {
+		return nil, err
+	}
+
 	return vertex, nil
 }
 
@@ -180,51 +188,49 @@ func (gc *GraphController) Refine(ctx context.Context, stateID string, llm LLMCl
 		return nil, fmt.Errorf("failed to add refinement edge: %w", err)
 	}
 
+	if err := gc.persist(state); err != nil {
+		return nil, err
+	}
+
 	return vertex, nil
 }
 
 // Score evaluates vertex quality with multi-criteria breakdown
 func (gc *GraphController) Score(ctx context.Context, stateID string, llm LLMClient, req ScoreRequest) (*ScoreBreakdown, error) {
-	state, err := gc.GetState(stateID)
+	result, err := gc.scoreVertex(ctx, stateID, llm, req)
 	if err != nil {
 		return nil, err
 	}
 
-	vertex, ok := state.Vertices[req.VertexID]
-	if !ok {
-		return nil, fmt.Errorf("vertex not found: %s", req.VertexID)
+	state, err := gc.GetState(stateID)
+	if err != nil {
+		return nil, err
 	}
-
-	// Score criteria weights
-	criteria := map[string]float64{
-		"confidence":   0.25,
-		"validity":     0.30,
-		"relevance":    0.25,
-		"novelty":      0.10,
-		"depth_factor": 0.10,
+	state.UpdatedAt = time.Now()
+	if err := gc.persist(state); err != nil {
+		return nil, err
 	}
 
-	// Get LLM-based scores
-	_, breakdown, err := llm.Score(ctx, vertex.Content, req.Problem, criteria)
+	return result, nil
+}
+
+// scoreVertex scores a vertex without persisting the state, so Explore can
+// score vertices concurrently
+func (gc *GraphController) scoreVertex(ctx context.Context, stateID string, llm LLMClient, req ScoreRequest) (*ScoreBreakdown, error) {
+	state, err := gc.GetState(stateID)
 	if err != nil {
-		return nil, fmt.Errorf("LLM scoring failed: %w", err)
+		return nil, err
 	}
 
-	// Build score breakdown
-	result := &ScoreBreakdown{
-		Confidence:  breakdown["confidence"],
-		Validity:    breakdown["validity"],
-		Relevance:   breakdown["relevance"],
-		Novelty:     breakdown["novelty"],
-		DepthFactor: breakdown["depth_factor"],
+	vertex, ok := state.Vertices[req.VertexID]
+	if !ok {
+		return nil, fmt.Errorf("vertex not found: %s", req.VertexID)
 	}
 
-	// Calculate weighted sum
-	result.Overall = (result.Confidence * criteria["confidence"]) +
-		(result.Validity * criteria["validity"]) +
-		(result.Relevance * criteria["relevance"]) +
-		(result.Novelty * criteria["novelty"]) +
-		(result.DepthFactor * criteria["depth_factor"])
+	result, err := gc.combineScores(ctx, llm, vertex, req.Problem, state.Config)
+	if err != nil {
+		return nil, err
+	}
 
 	// Update vertex score
 	vertex.Score = result.Overall
@@ -266,14 +272,28 @@ func (gc *GraphController) Prune(ctx context.Context, stateID string, threshold
 		}
 	}
 
+	if err := gc.persist(state); err != nil {
+		return removed, err
+	}
+
 	return removed, nil
 }
 
+// ExploreStrategy selects how Explore searches the graph
+type ExploreStrategy string
+
+const (
+	ExploreStrategyIterative ExploreStrategy = "iterative" // Generate → score → prune → refine loop (default)
+	ExploreStrategyMCTS      ExploreStrategy = "mcts"      // Monte Carlo Tree Search with UCT selection
+)
+
 // ExploreRequest encapsulates the auto-orchestrated exploration parameters
 type ExploreRequest struct {
-	InitialThought string         `json:"initial_thought"`
-	Problem        string         `json:"problem"`
-	Config         *ExploreConfig `json:"config,omitempty"`
+	InitialThought string          `json:"initial_thought"`
+	Problem        string          `json:"problem"`
+	Strategy       ExploreStrategy `json:"strategy,omitempty"` // Default: iterative
+	Config         *ExploreConfig  `json:"config,omitempty"`   // Iterative strategy settings
+	MCTS           *MCTSConfig     `json:"mcts,omitempty"`     // MCTS strategy settings and budget
 }
 
 // ExploreConfig controls the exploration workflow
@@ -286,25 +306,32 @@ type ExploreConfig struct {
 	UseFastScoring  bool    `json:"use_fast_scoring"` // Use local heuristics instead of LLM for scoring (default: true)
 	SkipRefine      bool    `json:"skip_refine"`      // Skip the refinement step to save LLM calls (default: false)
 	ParallelScoring bool    `json:"parallel_scoring"` // Parallelize LLM scoring calls (default: true)
+
+	// ScoringWeights overrides the graph's scoring weights (see GraphConfig).
+	// Fast scoring ignores them.
+	ScoringWeights map[string]float64 `json:"scoring_weights,omitempty"`
 }
 
 // ExploreResult contains the orchestrated exploration results
 type ExploreResult struct {
 	GraphID         string            `json:"graph_id"`
 	Problem         string            `json:"problem"`
+	Strategy        ExploreStrategy   `json:"strategy"`
 	Iterations      int               `json:"iterations"`
 	TotalGenerated  int               `json:"total_generated"`
 	TotalPruned     int               `json:"total_pruned"`
 	TotalRefined    int               `json:"total_refined"`
+	LLMCalls        int               `json:"llm_calls,omitempty"` // MCTS only
 	BestVertices    []*ThoughtVertex  `json:"best_vertices"`
 	Conclusions     []*ThoughtVertex  `json:"conclusions"`
 	ExplorationPath []ExplorationStep `json:"exploration_path"`
+	VertexStats     []MCTSVertexStats `json:"vertex_stats,omitempty"` // MCTS only
 }
 
 // ExplorationStep records a single step in the exploration workflow
 type ExplorationStep struct {
 	Step        int    `json:"step"`
-	Action      string `json:"action"`       // "generate", "score", "prune", "refine", "finalize"
+	Action      string `json:"action"`       // "generate", "score", "prune", "refine", "expand", "evaluate", "finalize"
 	VertexCount int    `json:"vertex_count"` // Vertices affected
 	Details     string `json:"details"`      // Human-readable description
 }
@@ -358,9 +385,18 @@ func (gc *GraphController) Explore(ctx context.Context, graphID string, llm LLMC
 		return nil, fmt.Errorf("problem is required")
 	}
 
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = ExploreStrategyIterative
+	}
+	if strategy != ExploreStrategyIterative && strategy != ExploreStrategyMCTS {
+		return nil, fmt.Errorf("unknown exploration strategy %q (supported: iterative, mcts)", req.Strategy)
+	}
+
 	result := &ExploreResult{
 		GraphID:         graphID,
 		Problem:         req.Problem,
+		Strategy:        strategy,
 		ExplorationPath: []ExplorationStep{},
 	}
 	stepNum := 0
@@ -368,6 +404,9 @@ func (gc *GraphController) Explore(ctx context.Context, graphID string, llm LLMC
 	// Step 1: Initialize the graph
 	graphConfig := DefaultGraphConfig()
 	graphConfig.PruneThreshold = config.PruneThreshold
+	if len(config.ScoringWeights) > 0 {
+		graphConfig.ScoringWeights = config.ScoringWeights
+	}
 
 	state, err := gc.Initialize(graphID, req.InitialThought, graphConfig)
 	if err != nil {
@@ -382,6 +421,10 @@ func (gc *GraphController) Explore(ctx context.Context, graphID string, llm LLMC
 		Details:     fmt.Sprintf("Created graph with initial thought: %s...", truncateStr(req.InitialThought, 50)),
 	})
 
+	if strategy == ExploreStrategyMCTS {
+		return gc.exploreMCTS(ctx, graphID, llm, req, result)
+	}
+
 	// Exploration loop
 	for iteration := 0; iteration < config.MaxIterations; iteration++ {
 		// Step 2: Generate k diverse continuations
@@ -445,7 +488,7 @@ func (gc *GraphController) Explore(ctx context.Context, graphID string, llm LLMC
 						VertexID: vID,
 						Problem:  req.Problem,
 					}
-					_, err := gc.Score(ctx, graphID, llm, scoreReq)
+					_, err := gc.scoreVertex(ctx, graphID, llm, scoreReq)
 					mu.Lock()
 					results[vID] = (err == nil)
 					mu.Unlock()
@@ -465,7 +508,7 @@ func (gc *GraphController) Explore(ctx context.Context, graphID string, llm LLMC
 					VertexID: vertexID,
 					Problem:  req.Problem,
 				}
-				if _, err := gc.Score(ctx, graphID, llm, scoreReq); err == nil {
+				if _, err := gc.scoreVertex(ctx, graphID, llm, scoreReq); err == nil {
 					scoredCount++
 				}
 			}
@@ -0,0 +1,131 @@
+// Package modes - Built-in vertex scorers for Graph-of-Thoughts
+package modes
+
+import (
+	"context"
+	"fmt"
+
+	"unified-thinking/internal/embeddings"
+	"unified-thinking/internal/types"
+	"unified-thinking/internal/validation"
+)
+
+// Names of the built-in vertex scorers
+const (
+	ScorerLogic              = "logic"
+	ScorerFallacy            = "fallacy"
+	ScorerEmbeddingRelevance = "embedding_relevance"
+	ScorerHallucination      = "hallucination"
+)
+
+// vertexThought adapts a vertex to the validators, which work on thoughts
+func vertexThought(vertex *ThoughtVertex) *types.Thought {
+	return &types.Thought{
+		ID:         vertex.ID,
+		Content:    vertex.Content,
+		Confidence: vertex.Confidence,
+		KeyPoints:  vertex.KeyPoints,
+	}
+}
+
+// LogicScorer scores 1.0 for logically consistent vertices and 0.0 for
+// vertices with contradictions or invalid inferences
+type LogicScorer struct {
+	validator *validation.LogicValidator
+}
+
+// NewLogicScorer creates a scorer backed by a LogicValidator
+func NewLogicScorer(validator *validation.LogicValidator) *LogicScorer {
+	return &LogicScorer{validator: validator}
+}
+
+// Name implements VertexScorer
+func (s *LogicScorer) Name() string { return ScorerLogic }
+
+// Score implements VertexScorer
+func (s *LogicScorer) Score(ctx context.Context, vertex *ThoughtVertex, problem string) (float64, error) {
+	result, err := s.validator.ValidateThought(vertexThought(vertex))
+	if err != nil {
+		return 0, err
+	}
+	if !result.IsValid {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// FallacyScorer lowers the score for each detected fallacy in proportion to
+// the detector's confidence. A vertex without fallacies scores 1.0.
+type FallacyScorer struct {
+	detector *validation.FallacyDetector
+}
+
+// NewFallacyScorer creates a scorer backed by a FallacyDetector
+func NewFallacyScorer(detector *validation.FallacyDetector) *FallacyScorer {
+	return &FallacyScorer{detector: detector}
+}
+
+// Name implements VertexScorer
+func (s *FallacyScorer) Name() string { return ScorerFallacy }
+
+// Score implements VertexScorer
+func (s *FallacyScorer) Score(ctx context.Context, vertex *ThoughtVertex, problem string) (float64, error) {
+	score := 1.0
+	for _, fallacy := range s.detector.DetectFallacies(vertex.Content, true, true) {
+		score *= 1 - 0.5*clampUnit(fallacy.Confidence)
+	}
+	return score, nil
+}
+
+// EmbeddingRelevanceScorer scores the cosine similarity between a vertex
+// and the problem, using the configured embedder
+type EmbeddingRelevanceScorer struct {
+	embedder embeddings.Embedder
+}
+
+// NewEmbeddingRelevanceScorer creates a scorer backed by an Embedder
+func NewEmbeddingRelevanceScorer(embedder embeddings.Embedder) *EmbeddingRelevanceScorer {
+	return &EmbeddingRelevanceScorer{embedder: embedder}
+}
+
+// Name implements VertexScorer
+func (s *EmbeddingRelevanceScorer) Name() string { return ScorerEmbeddingRelevance }
+
+// Score implements VertexScorer
+func (s *EmbeddingRelevanceScorer) Score(ctx context.Context, vertex *ThoughtVertex, problem string) (float64, error) {
+	if problem == "" {
+		return 0, fmt.Errorf("problem is required for embedding relevance")
+	}
+
+	vectors, err := s.embedder.EmbedBatch(ctx, []string{problem, vertex.Content})
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed vertex: %w", err)
+	}
+	if len(vectors) != 2 {
+		return 0, fmt.Errorf("expected 2 embeddings, got %d", len(vectors))
+	}
+
+	return clampUnit(embeddings.CosineSimilarity(vectors[0], vectors[1])), nil
+}
+
+// HallucinationScorer scores 1.0 minus the hallucination risk of a vertex
+type HallucinationScorer struct {
+	detector *validation.HallucinationDetector
+}
+
+// NewHallucinationScorer creates a scorer backed by a HallucinationDetector
+func NewHallucinationScorer(detector *validation.HallucinationDetector) *HallucinationScorer {
+	return &HallucinationScorer{detector: detector}
+}
+
+// Name implements VertexScorer
+func (s *HallucinationScorer) Name() string { return ScorerHallucination }
+
+// Score implements VertexScorer
+func (s *HallucinationScorer) Score(ctx context.Context, vertex *ThoughtVertex, problem string) (float64, error) {
+	report, err := s.detector.VerifyThought(ctx, vertexThought(vertex))
+	if err != nil {
+		return 0, err
+	}
+	return 1 - clampUnit(report.OverallRisk), nil
+}
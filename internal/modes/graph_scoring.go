@@ -0,0 +1,209 @@
+// Package modes - Configurable vertex scoring for Graph-of-Thoughts
+package modes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Criteria scored by LLMClient.Score
+const (
+	CriterionConfidence  = "confidence"
+	CriterionValidity    = "validity"
+	CriterionRelevance   = "relevance"
+	CriterionNovelty     = "novelty"
+	CriterionDepthFactor = "depth_factor"
+)
+
+// llmCriteria lists the criteria scored by the LLM, in reporting order
+var llmCriteria = []string{
+	CriterionConfidence,
+	CriterionValidity,
+	CriterionRelevance,
+	CriterionNovelty,
+	CriterionDepthFactor,
+}
+
+// VertexScorer contributes an additional scoring signal for vertices. A
+// scorer is used by graphs whose ScoringWeights give its name a positive
+// weight.
+type VertexScorer interface {
+	// Name is the criterion key used in GraphConfig.ScoringWeights
+	Name() string
+
+	// Score rates a vertex from 0.0 (poor) to 1.0 (good)
+	Score(ctx context.Context, vertex *ThoughtVertex, problem string) (float64, error)
+}
+
+// DefaultScoringWeights returns the default LLM criteria weights
+func DefaultScoringWeights() map[string]float64 {
+	return map[string]float64{
+		CriterionConfidence:  0.25,
+		CriterionValidity:    0.30,
+		CriterionRelevance:   0.25,
+		CriterionNovelty:     0.10,
+		CriterionDepthFactor: 0.10,
+	}
+}
+
+// RegisterScorer adds a vertex scorer, replacing any scorer with the same
+// name. Scorers are shared by the controllers of every workspace.
+func (gc *GraphController) RegisterScorer(scorer VertexScorer) {
+	root := gc.base()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	if root.scorers == nil {
+		root.scorers = make(map[string]VertexScorer)
+	}
+	root.scorers[scorer.Name()] = scorer
+}
+
+// ScoringCriteria returns every criterion that can be weighted: the LLM
+// criteria followed by the registered scorers
+func (gc *GraphController) ScoringCriteria() []string {
+	root := gc.base()
+	root.mu.RLock()
+	names := make([]string, 0, len(root.scorers))
+	for name := range root.scorers {
+		names = append(names, name)
+	}
+	root.mu.RUnlock()
+	sort.Strings(names)
+
+	return append(append([]string{}, llmCriteria...), names...)
+}
+
+// validateScoringWeights rejects negative weights, weights for unknown
+// criteria and weights that sum to zero
+func (gc *GraphController) validateScoringWeights(weights map[string]float64) error {
+	if len(weights) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	for _, name := range gc.ScoringCriteria() {
+		known[name] = true
+	}
+
+	total := 0.0
+	for name, weight := range weights {
+		if !known[name] {
+			return fmt.Errorf("unknown scoring criterion %q (available: %s)", name, strings.Join(gc.ScoringCriteria(), ", "))
+		}
+		if weight < 0 {
+			return fmt.Errorf("scoring weight for %s must be non-negative, got %.2f", name, weight)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("scoring weights must not all be zero")
+	}
+	return nil
+}
+
+// normalizedWeights returns the positive weights of a config scaled to sum
+// to 1, falling back to DefaultScoringWeights
+func normalizedWeights(config *GraphConfig) map[string]float64 {
+	weights := DefaultScoringWeights()
+	if config != nil && len(config.ScoringWeights) > 0 {
+		weights = config.ScoringWeights
+	}
+
+	total := 0.0
+	for _, weight := range weights {
+		if weight > 0 {
+			total += weight
+		}
+	}
+
+	normalized := make(map[string]float64, len(weights))
+	for name, weight := range weights {
+		if weight > 0 && total > 0 {
+			normalized[name] = weight / total
+		}
+	}
+	return normalized
+}
+
+// combineScores fills a breakdown from the LLM criteria and the registered
+// scorers named in the graph's weights, and computes the overall score
+func (gc *GraphController) combineScores(ctx context.Context, llm LLMClient, vertex *ThoughtVertex, problem string, config *GraphConfig) (*ScoreBreakdown, error) {
+	weights := normalizedWeights(config)
+	result := &ScoreBreakdown{Weights: weights}
+
+	// LLM criteria are scored in a single call, skipped when none is weighted
+	criteria := make(map[string]float64)
+	for _, name := range llmCriteria {
+		if weight, ok := weights[name]; ok {
+			criteria[name] = weight
+		}
+	}
+	if len(criteria) > 0 {
+		_, breakdown, err := llm.Score(ctx, vertex.Content, problem, criteria)
+		if err != nil {
+			return nil, fmt.Errorf("LLM scoring failed: %w", err)
+		}
+		result.Confidence = breakdown[CriterionConfidence]
+		result.Validity = breakdown[CriterionValidity]
+		result.Relevance = breakdown[CriterionRelevance]
+		result.Novelty = breakdown[CriterionNovelty]
+		result.DepthFactor = breakdown[CriterionDepthFactor]
+	}
+	values := map[string]float64{
+		CriterionConfidence:  result.Confidence,
+		CriterionValidity:    result.Validity,
+		CriterionRelevance:   result.Relevance,
+		CriterionNovelty:     result.Novelty,
+		CriterionDepthFactor: result.DepthFactor,
+	}
+
+	// Additional signals, in name order so errors are deterministic
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		if _, isLLM := criteria[name]; !isLLM {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	root := gc.base()
+	for _, name := range names {
+		root.mu.RLock()
+		scorer, ok := root.scorers[name]
+		root.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("no scorer registered for criterion %q", name)
+		}
+
+		value, err := scorer.Score(ctx, vertex, problem)
+		if err != nil {
+			return nil, fmt.Errorf("%s scoring failed: %w", name, err)
+		}
+		value = clampUnit(value)
+
+		if result.Signals == nil {
+			result.Signals = make(map[string]float64)
+		}
+		result.Signals[name] = value
+		values[name] = value
+	}
+
+	for name, weight := range weights {
+		result.Overall += values[name] * weight
+	}
+
+	return result, nil
+}
+
+// clampUnit limits a score to [0, 1]
+func clampUnit(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
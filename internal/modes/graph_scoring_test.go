@@ -0,0 +1,171 @@
+package modes
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"unified-thinking/internal/embeddings"
+	"unified-thinking/internal/storage"
+	"unified-thinking/internal/validation"
+)
+
+// fixedScorer returns a constant signal
+type fixedScorer struct {
+	name  string
+	value float64
+}
+
+func (s *fixedScorer) Name() string { return s.name }
+
+func (s *fixedScorer) Score(ctx context.Context, vertex *ThoughtVertex, problem string) (float64, error) {
+	return s.value, nil
+}
+
+// noScoreLLM fails if asked to score, to check the LLM is skipped
+type noScoreLLM struct {
+	testLLMClient
+}
+
+func (n *noScoreLLM) Score(ctx context.Context, thought string, problem string, criteria map[string]float64) (float64, map[string]float64, error) {
+	return 0, nil, errors.New("LLM scoring should not be called")
+}
+
+func TestScore_DefaultWeights(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+	state, _ := gc.Initialize("default-weights", "Initial", nil)
+
+	breakdown, err := gc.Score(context.Background(), state.ID, &testLLMClient{}, ScoreRequest{VertexID: state.RootIDs[0], Problem: "p"})
+	if err != nil {
+		t.Fatalf("Score failed: %v", err)
+	}
+
+	// 0.8*0.25 + 0.9*0.30 + 0.7*0.25 + 0.6*0.10 + 0.8*0.10
+	if math.Abs(breakdown.Overall-0.785) > 1e-9 {
+		t.Errorf("Overall = %v, want 0.785", breakdown.Overall)
+	}
+	if len(breakdown.Weights) != 5 || len(breakdown.Signals) != 0 {
+		t.Errorf("weights = %v, signals = %v", breakdown.Weights, breakdown.Signals)
+	}
+}
+
+func TestScore_CustomWeightsAndScorers(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+	gc.RegisterScorer(&fixedScorer{name: "team_signal", value: 0.5})
+
+	config := DefaultGraphConfig()
+	config.ScoringWeights = map[string]float64{CriterionValidity: 3, "team_signal": 1}
+	state, err := gc.Initialize("custom-weights", "Initial", config)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	breakdown, err := gc.Score(context.Background(), state.ID, &testLLMClient{}, ScoreRequest{VertexID: state.RootIDs[0], Problem: "p"})
+	if err != nil {
+		t.Fatalf("Score failed: %v", err)
+	}
+
+	// Validity 0.9 at 75%, team signal 0.5 at 25%
+	if math.Abs(breakdown.Overall-0.8) > 1e-9 {
+		t.Errorf("Overall = %v, want 0.8", breakdown.Overall)
+	}
+	if breakdown.Weights[CriterionValidity] != 0.75 || breakdown.Weights["team_signal"] != 0.25 {
+		t.Errorf("normalized weights = %v", breakdown.Weights)
+	}
+	if breakdown.Signals["team_signal"] != 0.5 {
+		t.Errorf("signals = %v", breakdown.Signals)
+	}
+	if state.Vertices[state.RootIDs[0]].Score != breakdown.Overall {
+		t.Error("vertex score not updated")
+	}
+}
+
+func TestScore_ScorersOnlySkipsLLM(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+	gc.RegisterScorer(NewLogicScorer(validation.NewLogicValidator()))
+
+	config := DefaultGraphConfig()
+	config.ScoringWeights = map[string]float64{ScorerLogic: 1}
+	state, err := gc.Initialize("logic-only", "This always happens but never occurs", config)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	breakdown, err := gc.Score(context.Background(), state.ID, &noScoreLLM{}, ScoreRequest{VertexID: state.RootIDs[0], Problem: "p"})
+	if err != nil {
+		t.Fatalf("Score failed: %v", err)
+	}
+	if breakdown.Overall != 0 || breakdown.Signals[ScorerLogic] != 0 {
+		t.Errorf("contradictory vertex scored %v (signals %v), want 0", breakdown.Overall, breakdown.Signals)
+	}
+}
+
+func TestInitialize_InvalidScoringWeights(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+
+	tests := []struct {
+		name    string
+		weights map[string]float64
+		wantErr string
+	}{
+		{"unknown criterion", map[string]float64{"vibes": 1}, "unknown scoring criterion"},
+		{"negative weight", map[string]float64{CriterionValidity: -1}, "non-negative"},
+		{"all zero", map[string]float64{CriterionValidity: 0}, "must not all be zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultGraphConfig()
+			config.ScoringWeights = tt.weights
+			_, err := gc.Initialize("invalid-"+tt.name, "Initial", config)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Initialize error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuiltInScorers(t *testing.T) {
+	ctx := context.Background()
+	clean := NewThoughtVertex("clean", "Profile the pipeline first, then parallelize the slowest tests.", ThoughtTypeGenerated, 0.7)
+	attack := NewThoughtVertex("attack", "Your argument is wrong because you're an idiot who doesn't understand logic.", ThoughtTypeGenerated, 0.7)
+
+	fallacy := NewFallacyScorer(validation.NewFallacyDetector())
+	cleanScore, _ := fallacy.Score(ctx, clean, "")
+	attackScore, _ := fallacy.Score(ctx, attack, "")
+	if cleanScore != 1 || attackScore >= 1 {
+		t.Errorf("fallacy scores: clean %v, ad hominem %v", cleanScore, attackScore)
+	}
+
+	relevance := NewEmbeddingRelevanceScorer(embeddings.NewMockEmbedder(64))
+	same, err := relevance.Score(ctx, clean, clean.Content)
+	if err != nil {
+		t.Fatalf("embedding relevance failed: %v", err)
+	}
+	if math.Abs(same-1) > 1e-6 {
+		t.Errorf("identical text relevance = %v, want 1", same)
+	}
+	if _, err := relevance.Score(ctx, clean, ""); err == nil {
+		t.Error("expected error without a problem")
+	}
+
+	hallucination := NewHallucinationScorer(validation.NewHallucinationDetector())
+	score, err := hallucination.Score(ctx, clean, "")
+	if err != nil || score < 0 || score > 1 {
+		t.Errorf("hallucination score = %v, %v", score, err)
+	}
+}
+
+func TestScoringCriteria(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+	gc.RegisterScorer(&fixedScorer{name: "b_signal"})
+	gc.RegisterScorer(&fixedScorer{name: "a_signal"})
+
+	criteria := gc.ScoringCriteria()
+	want := []string{CriterionConfidence, CriterionValidity, CriterionRelevance, CriterionNovelty, CriterionDepthFactor, "a_signal", "b_signal"}
+	if strings.Join(criteria, ",") != strings.Join(want, ",") {
+		t.Errorf("ScoringCriteria() = %v, want %v", criteria, want)
+	}
+}
@@ -2,9 +2,14 @@
 package modes
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"unified-thinking/internal/storage"
+	"unified-thinking/internal/types"
 )
 
 func TestGraphController_Initialize(t *testing.T) {
@@ -338,3 +343,220 @@ func TestNewThoughtEdge(t *testing.T) {
 		t.Errorf("Expected weight 0.8, got %f", edge.Weight)
 	}
 }
+
+// memoryGraphStateStorage is an in-memory GraphStateStorage for tests
+type memoryGraphStateStorage struct {
+	records map[string]*types.GraphStateRecord
+}
+
+func newMemoryGraphStateStorage() *memoryGraphStateStorage {
+	return &memoryGraphStateStorage{records: make(map[string]*types.GraphStateRecord)}
+}
+
+func (m *memoryGraphStateStorage) StoreGraphState(record *types.GraphStateRecord) error {
+	copied := *record
+	m.records[record.ID] = &copied
+	return nil
+}
+
+func (m *memoryGraphStateStorage) GetGraphState(id string) (*types.GraphStateRecord, error) {
+	record, ok := m.records[id]
+	if !ok {
+		return nil, fmt.Errorf("graph state not found: %s", id)
+	}
+	return record, nil
+}
+
+func (m *memoryGraphStateStorage) ListGraphStates(limit, offset int) ([]*types.GraphStateSummary, error) {
+	summaries := make([]*types.GraphStateSummary, 0, len(m.records))
+	for _, record := range m.records {
+		summary := record.GraphStateSummary
+		summaries = append(summaries, &summary)
+	}
+	return summaries, nil
+}
+
+func (m *memoryGraphStateStorage) DeleteGraphState(id string) error {
+	delete(m.records, id)
+	return nil
+}
+
+func (m *memoryGraphStateStorage) DeleteGraphStatesBefore(cutoff time.Time) (int, error) {
+	removed := 0
+	for id, record := range m.records {
+		if record.UpdatedAt.Before(cutoff) {
+			delete(m.records, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func TestGraphController_ResumeFromStateStorage(t *testing.T) {
+	ctx := context.Background()
+	stateStore := newMemoryGraphStateStorage()
+	llm := &testLLMClient{}
+
+	gc := NewGraphController(storage.NewMemoryStorage())
+	gc.SetStateStorage(stateStore)
+
+	if _, err := gc.Initialize("resume-graph", "Initial thought", nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	generated, err := gc.Generate(ctx, "resume-graph", llm, GenerateRequest{K: 2, Problem: "test"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if stateStore.records["resume-graph"].VertexCount != 3 {
+		t.Errorf("stored vertex count = %d, want 3", stateStore.records["resume-graph"].VertexCount)
+	}
+
+	// A new controller, as after a restart, continues the same graph
+	resumed := NewGraphController(storage.NewMemoryStorage())
+	resumed.SetStateStorage(stateStore)
+
+	state, err := resumed.GetState("resume-graph")
+	if err != nil {
+		t.Fatalf("GetState after restart failed: %v", err)
+	}
+	if len(state.Vertices) != 3 || len(state.Edges) != 2 || len(state.ActiveIDs) != 2 {
+		t.Fatalf("restored state has %d vertices, %d edges, %d active", len(state.Vertices), len(state.Edges), len(state.ActiveIDs))
+	}
+
+	aggregated, err := resumed.Aggregate(ctx, "resume-graph", llm, AggregateRequest{
+		VertexIDs: []string{generated[0].ID, generated[1].ID},
+		Problem:   "test",
+	})
+	if err != nil {
+		t.Fatalf("Aggregate after restart failed: %v", err)
+	}
+	if aggregated.Depth != 2 {
+		t.Errorf("aggregated depth = %d, want 2", aggregated.Depth)
+	}
+	if _, err := resumed.Refine(ctx, "resume-graph", llm, RefineRequest{VertexID: aggregated.ID, Problem: "test"}); err != nil {
+		t.Fatalf("Refine after restart failed: %v", err)
+	}
+	if stateStore.records["resume-graph"].VertexCount != 5 {
+		t.Errorf("stored vertex count = %d, want 5", stateStore.records["resume-graph"].VertexCount)
+	}
+
+	summaries, err := resumed.ListStates(0, 0)
+	if err != nil || len(summaries) != 1 || summaries[0].InitialThought != "Initial thought" {
+		t.Errorf("ListStates() = %+v, %v", summaries, err)
+	}
+}
+
+func TestGraphController_CleanupExpired(t *testing.T) {
+	stateStore := newMemoryGraphStateStorage()
+	gc := NewGraphController(storage.NewMemoryStorage())
+	gc.SetStateStorage(stateStore)
+	gc.SetStateTTL(time.Hour)
+
+	stale, _ := gc.Initialize("stale-graph", "Old thought", nil)
+	if _, err := gc.Initialize("fresh-graph", "New thought", nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	stale.UpdatedAt = time.Now().Add(-2 * time.Hour)
+	if err := gc.SaveState("stale-graph"); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	removed, err := gc.CleanupExpired()
+	if err != nil {
+		t.Fatalf("CleanupExpired failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("CleanupExpired removed %d, want 1", removed)
+	}
+	if _, err := gc.GetState("stale-graph"); err == nil {
+		t.Error("expected stale graph to be removed")
+	}
+	if _, err := gc.GetState("fresh-graph"); err != nil {
+		t.Errorf("fresh graph should be kept: %v", err)
+	}
+
+	gc.SetStateTTL(0)
+	if removed, _ := gc.CleanupExpired(); removed != 0 {
+		t.Errorf("CleanupExpired with TTL disabled removed %d", removed)
+	}
+}
+
+func TestGraphController_ListStatesInMemory(t *testing.T) {
+	gc := NewGraphController(storage.NewMemoryStorage())
+	for _, id := range []string{"graph-a", "graph-b", "graph-c"} {
+		if _, err := gc.Initialize(id, "thought "+id, nil); err != nil {
+			t.Fatalf("Initialize(%s) failed: %v", id, err)
+		}
+	}
+
+	page, err := gc.ListStates(2, 0)
+	if err != nil {
+		t.Fatalf("ListStates failed: %v", err)
+	}
+	if len(page) != 2 {
+		t.Errorf("ListStates(2, 0) returned %d, want 2", len(page))
+	}
+	if page, _ := gc.ListStates(2, 2); len(page) != 1 {
+		t.Errorf("ListStates(2, 2) returned %d, want 1", len(page))
+	}
+	if page, _ := gc.ListStates(0, 5); len(page) != 0 {
+		t.Errorf("ListStates(0, 5) returned %d, want 0", len(page))
+	}
+}
+
+func TestGraphController_ForWorkspace(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "got.db"), 5000)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	gc := NewGraphController(store)
+	gc.SetStateStorage(store)
+
+	alpha := gc.ForWorkspace("alpha")
+	if alpha.Workspace() != "alpha" || gc.ForWorkspace("alpha") != alpha {
+		t.Fatalf("ForWorkspace(alpha) returned workspace %q", alpha.Workspace())
+	}
+	if gc.ForWorkspace("") != gc {
+		t.Error("ForWorkspace of the default workspace should return the root controller")
+	}
+
+	if _, err := alpha.Initialize("shared-id", "Alpha thought", nil); err != nil {
+		t.Fatalf("Initialize in alpha failed: %v", err)
+	}
+	if _, err := gc.GetState("shared-id"); err == nil {
+		t.Error("expected alpha graph to be hidden from the default workspace")
+	}
+	if summaries, _ := gc.ListStates(0, 0); len(summaries) != 0 {
+		t.Errorf("default ListStates() = %+v", summaries)
+	}
+	if summaries, _ := alpha.ListStates(0, 0); len(summaries) != 1 {
+		t.Errorf("alpha ListStates() = %+v", summaries)
+	}
+
+	// A restarted controller finds the graph only in its workspace
+	resumed := NewGraphController(store)
+	resumed.SetStateStorage(store)
+	if _, err := resumed.ForWorkspace("alpha").GetState("shared-id"); err != nil {
+		t.Errorf("GetState in alpha after restart failed: %v", err)
+	}
+	if _, err := resumed.GetState("shared-id"); err == nil {
+		t.Error("expected alpha graph to stay hidden after restart")
+	}
+
+	// Cleanup through any controller covers every workspace
+	gc.SetStateTTL(time.Hour)
+	state, _ := alpha.GetState("shared-id")
+	state.UpdatedAt = time.Now().Add(-2 * time.Hour)
+	if err := alpha.SaveState("shared-id"); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+	if removed, err := gc.CleanupExpired(); err != nil || removed != 1 {
+		t.Errorf("CleanupExpired() = %d, %v; want 1", removed, err)
+	}
+	if _, err := alpha.GetState("shared-id"); err == nil {
+		t.Error("expected expired alpha graph to be removed")
+	}
+}
@@ -76,6 +76,13 @@ type GraphConfig struct {
 	MaxRefinements    int     `json:"max_refinements"`     // 3 - self-improvement iterations
 	PruneThreshold    float64 `json:"prune_threshold"`     // 0.3 - minimum quality score
 	AggregateMinPaths int     `json:"aggregate_min_paths"` // 2 - minimum paths to aggregate
+
+	// ScoringWeights weights the criteria combined into a vertex's overall
+	// score. Keys are the LLM criteria (confidence, validity, relevance,
+	// novelty, depth_factor) or the names of registered VertexScorers.
+	// Weights are normalized, so they need not sum to 1. Empty means
+	// DefaultScoringWeights.
+	ScoringWeights map[string]float64 `json:"scoring_weights,omitempty"`
 }
 
 // DefaultGraphConfig returns sensible defaults
@@ -87,17 +94,20 @@ func DefaultGraphConfig() *GraphConfig {
 		MaxRefinements:    3,
 		PruneThreshold:    0.3,
 		AggregateMinPaths: 2,
+		ScoringWeights:    DefaultScoringWeights(),
 	}
 }
 
 // ScoreBreakdown provides detailed quality metrics
 type ScoreBreakdown struct {
-	Confidence  float64 `json:"confidence"`   // 25% weight - LLM self-assessment
-	Validity    float64 `json:"validity"`     // 30% weight - logical consistency
-	Relevance   float64 `json:"relevance"`    // 25% weight - semantic similarity to problem
-	Novelty     float64 `json:"novelty"`      // 10% weight - uniqueness vs siblings
-	DepthFactor float64 `json:"depth_factor"` // 10% weight - penalty for very deep thoughts
-	Overall     float64 `json:"overall"`      // Weighted sum
+	Confidence  float64            `json:"confidence"`        // Default 25% weight - LLM self-assessment
+	Validity    float64            `json:"validity"`          // Default 30% weight - logical consistency
+	Relevance   float64            `json:"relevance"`         // Default 25% weight - semantic similarity to problem
+	Novelty     float64            `json:"novelty"`           // Default 10% weight - uniqueness vs siblings
+	DepthFactor float64            `json:"depth_factor"`      // Default 10% weight - penalty for very deep thoughts
+	Signals     map[string]float64 `json:"signals,omitempty"` // Scores from registered VertexScorers
+	Weights     map[string]float64 `json:"weights"`           // Normalized weights applied
+	Overall     float64            `json:"overall"`           // Weighted sum
 }
 
 // VertexHash is the hash function for graph vertices
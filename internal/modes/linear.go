@@ -20,6 +20,8 @@ func NewLinearMode(storage storage.Storage) *LinearMode {
 
 // ProcessThought processes a thought in linear mode
 func (m *LinearMode) ProcessThought(ctx context.Context, input ThoughtInput) (*ThoughtResult, error) {
+	store := scopedStorage(m.storage, input.Workspace)
+
 	// If ChallengeAssumptions is true, modify content to question assumptions
 	content := input.Content
 	challengesAssumption := false
@@ -57,7 +59,7 @@ func (m *LinearMode) ProcessThought(ctx context.Context, input ThoughtInput) (*T
 	}
 
 	// Store the thought
-	if err := m.storage.StoreThought(thought); err != nil {
+	if err := store.StoreThought(thought); err != nil {
 		return nil, err
 	}
 
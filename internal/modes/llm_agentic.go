@@ -30,15 +30,21 @@ func DefaultAgenticConfig() AgenticConfig {
 	}
 }
 
-// AgenticClient wraps Claude API with tool-calling loop
+// AgenticClient wraps an LLM provider with tool-calling loop
 type AgenticClient struct {
-	*AnthropicBaseClient
+	MessageSender
 	registry *ToolRegistry
 	config   AgenticConfig
 }
 
-// NewAgenticClient creates an agentic client
+// NewAgenticClient creates an agentic client backed by the Anthropic API
 func NewAgenticClient(apiKey string, registry *ToolRegistry, config AgenticConfig) *AgenticClient {
+	return NewAgenticClientWithProvider(ProviderConfig{Provider: ProviderAnthropic, APIKey: apiKey}, registry, config)
+}
+
+// NewAgenticClientWithProvider creates an agentic client for any provider.
+// An empty config.Model falls back to the provider's default model.
+func NewAgenticClientWithProvider(provider ProviderConfig, registry *ToolRegistry, config AgenticConfig) *AgenticClient {
 	if config.MaxIterations <= 0 {
 		config.MaxIterations = 10
 	}
@@ -46,15 +52,14 @@ func NewAgenticClient(apiKey string, registry *ToolRegistry, config AgenticConfi
 		config.MaxToolsPerTurn = 5
 	}
 	if config.Model == "" {
-		config.Model = "claude-sonnet-4-5-20250929"
+		config.Model = provider.DefaultModel()
 	}
 	if config.MaxTokens <= 0 {
 		config.MaxTokens = 4096
 	}
 
 	return &AgenticClient{
-		AnthropicBaseClient: NewAnthropicBaseClient(BaseClientConfig{
-			APIKey:      apiKey,
+		MessageSender: provider.NewSender(BaseClientConfig{
 			Model:       config.Model,
 			MaxTokens:   config.MaxTokens,
 			Temperature: config.Temperature,
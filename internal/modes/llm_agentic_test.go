@@ -42,7 +42,7 @@ func TestNewAgenticClient(t *testing.T) {
 	if client == nil {
 		t.Fatal("expected non-nil client")
 	}
-	if client.apiKey != "test-key" {
+	if client.APIKey() != "test-key" {
 		t.Error("apiKey not set correctly")
 	}
 	if client.registry != registry {
@@ -9,9 +9,11 @@ import (
 	"strings"
 )
 
-// AnthropicLLMClient implements LLMClient using Anthropic's Claude API
+// AnthropicLLMClient implements LLMClient using Anthropic's Claude API.
+// Requests go through a MessageSender, which OpenAILLMClient swaps for an
+// OpenAI-compatible transport.
 type AnthropicLLMClient struct {
-	*AnthropicBaseClient
+	MessageSender
 	useStructured bool
 }
 
@@ -29,7 +31,7 @@ func NewAnthropicLLMClient() (*AnthropicLLMClient, error) {
 	useStructured := os.Getenv("GOT_STRUCTURED_OUTPUT") != "false"
 
 	return &AnthropicLLMClient{
-		AnthropicBaseClient: NewAnthropicBaseClient(BaseClientConfig{
+		MessageSender: NewAnthropicBaseClient(BaseClientConfig{
 			APIKey: apiKey,
 			Model:  model,
 		}),
@@ -40,8 +42,8 @@ func NewAnthropicLLMClient() (*AnthropicLLMClient, error) {
 // WithoutStructured returns a copy with structured outputs disabled
 func (a *AnthropicLLMClient) WithoutStructured() *AnthropicLLMClient {
 	return &AnthropicLLMClient{
-		AnthropicBaseClient: a.AnthropicBaseClient,
-		useStructured:       false,
+		MessageSender: a.MessageSender,
+		useStructured: false,
 	}
 }
 
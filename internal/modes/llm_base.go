@@ -2,94 +2,163 @@
 package modes
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+
+	"unified-thinking/internal/streaming"
+	"unified-thinking/internal/usage"
 )
 
 const (
-	anthropicAPIURL  = "https://api.anthropic.com/v1/messages"
+	anthropicBaseURL = "https://api.anthropic.com/v1"
 	anthropicVersion = "2023-06-01"
 	defaultTimeout   = 120 * time.Second
 )
 
+// MessageSender sends Messages API requests to an LLM provider. Anthropic
+// speaks the format natively; other providers translate it to their own
+// protocol, so prompts and tool schemas are shared across providers.
+type MessageSender interface {
+	SendRequest(ctx context.Context, req *APIRequest) (*APIResponse, error)
+	APIKey() string
+	Model() string
+	MaxTokens() int
+	Temperature() float64
+}
+
 // BaseClientConfig configures the base client
 type BaseClientConfig struct {
 	APIKey      string
+	BaseURL     string // API root, defaults to the provider's public endpoint
 	Model       string
 	MaxTokens   int
 	Temperature float64
 	Timeout     time.Duration
+	Transport   *Transport     // Shared transport; a new one with Timeout is created if nil
+	Usage       *usage.Tracker // Records token usage and enforces budgets, may be nil
+}
+
+// resolveTransport returns the configured transport or a new default one
+func (c BaseClientConfig) resolveTransport() *Transport {
+	if c.Transport != nil {
+		return c.Transport
+	}
+	return NewTransport(TransportConfig{Timeout: c.Timeout})
 }
 
 // AnthropicBaseClient provides shared HTTP infrastructure for Anthropic API
 type AnthropicBaseClient struct {
 	apiKey      string
+	baseURL     string
 	model       string
 	maxTokens   int
 	temperature float64
-	httpClient  *http.Client
+	transport   *Transport
+	usage       *usage.Tracker
 }
 
 // NewAnthropicBaseClient creates a base client
 func NewAnthropicBaseClient(config BaseClientConfig) *AnthropicBaseClient {
-	timeout := config.Timeout
-	if timeout == 0 {
-		timeout = defaultTimeout
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = anthropicBaseURL
 	}
 
 	return &AnthropicBaseClient{
 		apiKey:      config.APIKey,
+		baseURL:     strings.TrimRight(baseURL, "/"),
 		model:       config.Model,
 		maxTokens:   config.MaxTokens,
 		temperature: config.Temperature,
-		httpClient:  &http.Client{Timeout: timeout},
+		transport:   config.resolveTransport(),
+		usage:       config.Usage,
 	}
 }
 
-// SendRequest sends a request to the Anthropic API
+// SendRequest sends a request to the Anthropic API. It fails without
+// calling the API once the usage budget is exhausted. When the context
+// carries an enabled progress reporter, the response is streamed and its
+// text forwarded as partial results while it is generated.
 func (c *AnthropicBaseClient) SendRequest(ctx context.Context, req *APIRequest) (*APIResponse, error) {
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
+	if err := c.usage.Check(ctx); err != nil {
+		return nil, err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", anthropicAPIURL, bytes.NewBuffer(jsonData))
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("x-api-key", c.apiKey)
+	header.Set("anthropic-version", anthropicVersion)
+
+	var apiResp *APIResponse
+	var err error
+	if reporter := streaming.GetReporter(ctx); reporter.IsEnabled() {
+		apiResp, err = c.stream(ctx, req, header, reporter, streaming.GetConfig(ctx).MinInterval)
+	} else {
+		apiResp, err = c.send(ctx, req, header)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
+	c.usage.Record(ctx, req.Model, apiResp.Usage.InputTokens, apiResp.Usage.OutputTokens)
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-api-key", c.apiKey)
-	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	return apiResp, nil
+}
 
-	resp, err := c.httpClient.Do(httpReq)
+// send posts a request and decodes the complete response
+func (c *AnthropicBaseClient) send(ctx context.Context, req *APIRequest, header http.Header) (*APIResponse, error) {
+	jsonData, err := json.Marshal(req)
 	if err != nil {
-		return nil, fmt.Errorf("API request: %w", err)
+		return nil, fmt.Errorf("marshal request: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.transport.Post(ctx, c.baseURL+"/messages", header, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, body)
+		return nil, err
 	}
 
 	var apiResp APIResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
-
 	return &apiResp, nil
 }
 
+// stream posts a streaming request and assembles the response from its
+// events, forwarding deltas to the reporter. Cancelling ctx aborts it.
+func (c *AnthropicBaseClient) stream(ctx context.Context, req *APIRequest, header http.Header, reporter streaming.ProgressReporter, interval time.Duration) (*APIResponse, error) {
+	jsonData, err := json.Marshal(struct {
+		*APIRequest
+		Stream bool `json:"stream"`
+	}{req, true})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	header.Set("Accept", "text/event-stream")
+
+	var apiResp *APIResponse
+	err = c.transport.PostStream(ctx, c.baseURL+"/messages", header, jsonData, func(body io.Reader) error {
+		forwarder := &deltaForwarder{reporter: reporter, interval: interval}
+		resp, err := readMessageStream(body, forwarder.add)
+		forwarder.flush()
+		apiResp = resp
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apiResp, nil
+}
+
+// Transport returns the transport used for requests
+func (c *AnthropicBaseClient) Transport() *Transport {
+	return c.transport
+}
+
 // APIKey returns the API key
 func (c *AnthropicBaseClient) APIKey() string {
 	return c.apiKey
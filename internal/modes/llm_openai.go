@@ -0,0 +1,376 @@
+// Package modes - OpenAI-compatible chat-completions client
+package modes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"unified-thinking/internal/usage"
+)
+
+const (
+	openAIBaseURL      = "https://api.openai.com/v1"
+	defaultOpenAIModel = "gpt-4o-mini"
+)
+
+// OpenAIBaseClient sends Messages API requests to an OpenAI-compatible
+// chat-completions endpoint (OpenAI, Ollama, llama.cpp, vLLM, ...),
+// translating requests and responses so the shared prompts work unchanged
+type OpenAIBaseClient struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	maxTokens   int
+	temperature float64
+	transport   *Transport
+	usage       *usage.Tracker
+}
+
+// NewOpenAIBaseClient creates an OpenAI-compatible base client. The API key
+// is optional, since local servers usually do not require one.
+func NewOpenAIBaseClient(config BaseClientConfig) *OpenAIBaseClient {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = openAIBaseURL
+	}
+
+	return &OpenAIBaseClient{
+		apiKey:      config.APIKey,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		model:       config.Model,
+		maxTokens:   config.MaxTokens,
+		temperature: config.Temperature,
+		transport:   config.resolveTransport(),
+		usage:       config.Usage,
+	}
+}
+
+// chatRequest is an OpenAI chat-completions request
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature,omitempty"`
+	Tools       []chatTool    `json:"tools,omitempty"`
+	ToolChoice  any           `json:"tool_choice,omitempty"`
+}
+
+// chatMessage is a chat-completions message
+type chatMessage struct {
+	Role       string         `json:"role"`
+	Content    *string        `json:"content"`
+	ToolCalls  []chatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+// chatTool is a function tool definition
+type chatTool struct {
+	Type     string       `json:"type"`
+	Function chatFunction `json:"function"`
+}
+
+// chatFunction describes a callable function
+type chatFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+// chatToolCall is a function call made by the model. Arguments is a JSON
+// encoded object.
+type chatToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// chatResponse is a chat-completions response
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// SendRequest translates a Messages API request to chat completions, sends
+// it, and translates the response back. It fails without calling the API
+// once the usage budget is exhausted.
+func (c *OpenAIBaseClient) SendRequest(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+	if err := c.usage.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	chatReq, err := toChatRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	body, err := c.transport.Post(ctx, c.baseURL+"/chat/completions", header, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	var chatResp chatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	c.usage.Record(ctx, req.Model, chatResp.Usage.PromptTokens, chatResp.Usage.CompletionTokens)
+
+	return fromChatResponse(&chatResp, forcedToolName(req.ToolChoice))
+}
+
+// Transport returns the transport used for requests
+func (c *OpenAIBaseClient) Transport() *Transport {
+	return c.transport
+}
+
+// APIKey returns the API key
+func (c *OpenAIBaseClient) APIKey() string {
+	return c.apiKey
+}
+
+// Model returns the model ID
+func (c *OpenAIBaseClient) Model() string {
+	return c.model
+}
+
+// MaxTokens returns the max tokens setting
+func (c *OpenAIBaseClient) MaxTokens() int {
+	return c.maxTokens
+}
+
+// Temperature returns the temperature setting
+func (c *OpenAIBaseClient) Temperature() float64 {
+	return c.temperature
+}
+
+// toChatRequest converts a Messages API request. Server-side tools such as
+// web search have no chat-completions equivalent and are dropped.
+func toChatRequest(req *APIRequest) (*chatRequest, error) {
+	chatReq := &chatRequest{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	}
+
+	if req.System != "" {
+		chatReq.Messages = append(chatReq.Messages, chatMessage{Role: "system", Content: stringPtr(req.System)})
+	}
+
+	for _, msg := range req.Messages {
+		converted, err := toChatMessages(msg)
+		if err != nil {
+			return nil, err
+		}
+		chatReq.Messages = append(chatReq.Messages, converted...)
+	}
+
+	for _, tool := range req.Tools {
+		if t, ok := tool.(Tool); ok {
+			chatReq.Tools = append(chatReq.Tools, chatTool{
+				Type:     "function",
+				Function: chatFunction{Name: t.Name, Description: t.Description, Parameters: t.InputSchema},
+			})
+		}
+	}
+
+	if len(chatReq.Tools) > 0 {
+		if name := forcedToolName(req.ToolChoice); name != "" {
+			chatReq.ToolChoice = map[string]any{
+				"type":     "function",
+				"function": map[string]string{"name": name},
+			}
+		}
+	}
+
+	return chatReq, nil
+}
+
+// toChatMessages converts one Messages API message. Tool results become
+// separate "tool" messages, which chat completions requires to directly
+// follow the assistant message that made the calls.
+func toChatMessages(msg Message) ([]chatMessage, error) {
+	var blocks []ContentBlock
+	switch content := msg.Content.(type) {
+	case string:
+		return []chatMessage{{Role: msg.Role, Content: stringPtr(content)}}, nil
+	case []ContentBlock:
+		blocks = content
+	default:
+		return nil, fmt.Errorf("unsupported content type %T in %s message", msg.Content, msg.Role)
+	}
+
+	var messages []chatMessage
+	var texts []string
+	var toolCalls []chatToolCall
+	for _, block := range blocks {
+		switch block.Type {
+		case "text":
+			texts = append(texts, block.Text)
+		case "tool_use":
+			args, err := json.Marshal(block.Input)
+			if err != nil {
+				return nil, fmt.Errorf("marshal tool input: %w", err)
+			}
+			call := chatToolCall{ID: block.ID, Type: "function"}
+			call.Function.Name = block.Name
+			call.Function.Arguments = string(args)
+			toolCalls = append(toolCalls, call)
+		case "tool_result":
+			result, ok := block.Content.(string)
+			if !ok {
+				data, err := json.Marshal(block.Content)
+				if err != nil {
+					return nil, fmt.Errorf("marshal tool result: %w", err)
+				}
+				result = string(data)
+			}
+			messages = append(messages, chatMessage{Role: "tool", ToolCallID: block.ToolUseID, Content: stringPtr(result)})
+		}
+	}
+
+	if len(texts) > 0 || len(toolCalls) > 0 {
+		message := chatMessage{Role: msg.Role, ToolCalls: toolCalls}
+		if len(texts) > 0 {
+			message.Content = stringPtr(strings.Join(texts, "\n"))
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// fromChatResponse converts a chat-completions response. When a tool was
+// forced but the model answered in plain text, as some local servers do, a
+// JSON object in the text is accepted as the tool input.
+func fromChatResponse(resp *chatResponse, forcedTool string) (*APIResponse, error) {
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in response")
+	}
+	choice := resp.Choices[0]
+
+	apiResp := &APIResponse{
+		Usage: Usage{
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+		},
+	}
+
+	text := ""
+	if choice.Message.Content != nil {
+		text = *choice.Message.Content
+	}
+	if text != "" {
+		apiResp.Content = append(apiResp.Content, ResponseBlock{Type: "text", Text: text})
+	}
+
+	for _, call := range choice.Message.ToolCalls {
+		input := map[string]any{}
+		if call.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &input); err != nil {
+				return nil, fmt.Errorf("parse arguments of %s call: %w", call.Function.Name, err)
+			}
+		}
+		apiResp.Content = append(apiResp.Content, ResponseBlock{
+			Type:  "tool_use",
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Input: input,
+		})
+	}
+
+	if len(choice.Message.ToolCalls) == 0 && forcedTool != "" {
+		var input map[string]any
+		if err := json.Unmarshal([]byte(extractJSON(stripMarkdownCodeBlocks(text))), &input); err == nil {
+			apiResp.Content = append(apiResp.Content, ResponseBlock{Type: "tool_use", Name: forcedTool, Input: input})
+		}
+	}
+
+	// Some servers report "stop" even when the model called tools
+	switch {
+	case len(choice.Message.ToolCalls) > 0 || choice.FinishReason == "tool_calls":
+		apiResp.StopReason = "tool_use"
+	case choice.FinishReason == "length":
+		apiResp.StopReason = "max_tokens"
+	default:
+		apiResp.StopReason = "end_turn"
+	}
+
+	return apiResp, nil
+}
+
+// forcedToolName returns the tool a tool_choice forces, or "" if none
+func forcedToolName(choice any) string {
+	switch c := choice.(type) {
+	case map[string]string:
+		if c["type"] == "tool" {
+			return c["name"]
+		}
+	case ToolChoice:
+		if c.Type == "tool" {
+			return c.Name
+		}
+	case *ToolChoice:
+		if c != nil && c.Type == "tool" {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// OpenAILLMClient implements LLMClient against an OpenAI-compatible
+// chat-completions endpoint, reusing the Anthropic prompts and tool schemas
+type OpenAILLMClient struct {
+	*AnthropicLLMClient
+}
+
+// NewOpenAILLMClient creates an OpenAI-compatible LLM client. With
+// useStructured, generation, scoring, key points and novelty use forced
+// function calls; otherwise the model is asked to answer in JSON.
+func NewOpenAILLMClient(config BaseClientConfig, useStructured bool) *OpenAILLMClient {
+	if config.Model == "" {
+		config.Model = defaultOpenAIModel
+	}
+	return &OpenAILLMClient{
+		AnthropicLLMClient: &AnthropicLLMClient{
+			MessageSender: NewOpenAIBaseClient(config),
+			useStructured: useStructured,
+		},
+	}
+}
+
+// WithoutStructured returns a copy with structured outputs disabled
+func (o *OpenAILLMClient) WithoutStructured() *OpenAILLMClient {
+	return &OpenAILLMClient{AnthropicLLMClient: o.AnthropicLLMClient.WithoutStructured()}
+}
+
+// ResearchWithSearch is not supported: web search is an Anthropic
+// server-side tool with no chat-completions equivalent
+func (o *OpenAILLMClient) ResearchWithSearch(ctx context.Context, query string, problem string) (*ResearchResult, error) {
+	return nil, fmt.Errorf("research-with-search requires LLM_PROVIDER=anthropic: web search is not available through the openai provider")
+}
@@ -0,0 +1,266 @@
+package modes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	ccerrors "unified-thinking/internal/claudecode/errors"
+	"unified-thinking/internal/usage"
+)
+
+// chatServer serves canned chat-completions responses in order and records
+// the requests it receives
+type chatServer struct {
+	t         *testing.T
+	mu        sync.Mutex
+	responses []map[string]any
+	requests  []map[string]any
+	headers   []http.Header
+}
+
+func newChatServer(t *testing.T, responses ...map[string]any) (*chatServer, *httptest.Server) {
+	cs := &chatServer{t: t, responses: responses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+
+		cs.mu.Lock()
+		defer cs.mu.Unlock()
+		cs.requests = append(cs.requests, body)
+		cs.headers = append(cs.headers, r.Header.Clone())
+		if len(cs.responses) == 0 {
+			http.Error(w, "no more responses", http.StatusInternalServerError)
+			return
+		}
+		resp := cs.responses[0]
+		cs.responses = cs.responses[1:]
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return cs, server
+}
+
+func chatToolCallResponse(id, name string, args any) map[string]any {
+	encoded, _ := json.Marshal(args)
+	return map[string]any{
+		"choices": []any{map[string]any{
+			"message": map[string]any{
+				"role":    "assistant",
+				"content": nil,
+				"tool_calls": []any{map[string]any{
+					"id":       id,
+					"type":     "function",
+					"function": map[string]any{"name": name, "arguments": string(encoded)},
+				}},
+			},
+			"finish_reason": "tool_calls",
+		}},
+		"usage": map[string]any{"prompt_tokens": 10, "completion_tokens": 5},
+	}
+}
+
+func chatTextResponse(text, finishReason string) map[string]any {
+	return map[string]any{
+		"choices": []any{map[string]any{
+			"message":       map[string]any{"role": "assistant", "content": text},
+			"finish_reason": finishReason,
+		}},
+		"usage": map[string]any{"prompt_tokens": 10, "completion_tokens": 5},
+	}
+}
+
+func TestOpenAILLMClient_GenerateStructured(t *testing.T) {
+	cs, server := newChatServer(t, chatToolCallResponse("call_1", "generate_continuations", map[string]any{
+		"continuations": []string{"first", "second"},
+	}))
+
+	client := NewOpenAILLMClient(BaseClientConfig{APIKey: "sk-test", BaseURL: server.URL + "/v1/", Model: "llama3.1"}, true)
+	continuations, err := client.Generate(context.Background(), "Start", 2)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(continuations) != 2 || continuations[0] != "first" {
+		t.Errorf("continuations = %v", continuations)
+	}
+
+	if got := cs.headers[0].Get("Authorization"); got != "Bearer sk-test" {
+		t.Errorf("Authorization = %q", got)
+	}
+	req := cs.requests[0]
+	if req["model"] != "llama3.1" {
+		t.Errorf("model = %v", req["model"])
+	}
+	messages := req["messages"].([]any)
+	if first := messages[0].(map[string]any); first["role"] != "system" {
+		t.Errorf("first message role = %v, want system", first["role"])
+	}
+	tools := req["tools"].([]any)
+	function := tools[0].(map[string]any)["function"].(map[string]any)
+	if function["name"] != "generate_continuations" || function["parameters"] == nil {
+		t.Errorf("tool function = %v", function)
+	}
+	choice := req["tool_choice"].(map[string]any)
+	if choice["type"] != "function" || choice["function"].(map[string]any)["name"] != "generate_continuations" {
+		t.Errorf("tool_choice = %v", choice)
+	}
+}
+
+func TestOpenAILLMClient_ForcedToolAnsweredAsText(t *testing.T) {
+	// Local servers that ignore tool_choice answer in JSON text
+	_, server := newChatServer(t, chatTextResponse(
+		"```json\n{\"confidence\": 0.8, \"validity\": 0.6, \"relevance\": 1, \"novelty\": 0.5, \"depth_factor\": 0.4}\n```", "stop"))
+
+	client := NewOpenAILLMClient(BaseClientConfig{BaseURL: server.URL + "/v1"}, true)
+	overall, scores, err := client.Score(context.Background(), "thought", "problem", map[string]float64{"validity": 1})
+	if err != nil {
+		t.Fatalf("Score: %v", err)
+	}
+	if overall != 0.6 || scores["confidence"] != 0.8 {
+		t.Errorf("overall = %v, scores = %v", overall, scores)
+	}
+}
+
+func TestOpenAILLMClient_GenerateText(t *testing.T) {
+	cs, server := newChatServer(t, chatTextResponse("plain answer", "stop"))
+
+	client := NewOpenAILLMClient(BaseClientConfig{BaseURL: server.URL + "/v1"}, true)
+	text, err := client.GenerateText(context.Background(), "question")
+	if err != nil {
+		t.Fatalf("GenerateText: %v", err)
+	}
+	if text != "plain answer" {
+		t.Errorf("text = %q", text)
+	}
+	if got := cs.headers[0].Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want none without an API key", got)
+	}
+	if cs.requests[0]["model"] != defaultOpenAIModel {
+		t.Errorf("model = %v, want default", cs.requests[0]["model"])
+	}
+}
+
+func TestOpenAILLMClient_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewOpenAILLMClient(BaseClientConfig{BaseURL: server.URL}, false)
+	_, err := client.Aggregate(context.Background(), []string{"a", "b"}, "problem")
+	if err == nil || !strings.Contains(err.Error(), "API error 404") {
+		t.Errorf("err = %v, want API error 404", err)
+	}
+}
+
+func TestOpenAILLMClient_ResearchUnsupported(t *testing.T) {
+	client := NewOpenAILLMClient(BaseClientConfig{}, true)
+	if _, err := client.ResearchWithSearch(context.Background(), "query", ""); err == nil {
+		t.Error("expected research to be unsupported")
+	}
+}
+
+func TestOpenAIBaseClient_UsageBudget(t *testing.T) {
+	cs, server := newChatServer(t, chatTextResponse("one", "stop"), chatTextResponse("two", "stop"))
+	tracker := usage.NewTracker(usage.Budget{MaxTokens: 15})
+	client := NewOpenAIBaseClient(BaseClientConfig{BaseURL: server.URL + "/v1", Model: "llama3.1", Usage: tracker})
+	ctx, _ := usage.WithCall(context.Background(), "got-generate", "s1")
+
+	if _, err := client.SendRequest(ctx, &APIRequest{Model: "llama3.1", Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	summary := tracker.Summary()
+	if got := summary.ByTool["got-generate"]; got.InputTokens != 10 || got.OutputTokens != 5 {
+		t.Errorf("by_tool = %+v", summary.ByTool)
+	}
+
+	// The budget is used up, so the second request never reaches the server
+	_, err := client.SendRequest(ctx, &APIRequest{Model: "llama3.1", Messages: []Message{{Role: "user", Content: "again"}}})
+	if se, ok := ccerrors.AsStructuredError(err); !ok || se.Code != ccerrors.ErrBudgetExhausted {
+		t.Errorf("err = %v, want budget exhausted", err)
+	}
+	if len(cs.requests) != 1 {
+		t.Errorf("requests = %d, want 1", len(cs.requests))
+	}
+}
+
+func TestAgenticClient_OpenAIProvider(t *testing.T) {
+	cs, server := newChatServer(t,
+		chatToolCallResponse("call_1", "echo", map[string]any{"message": "hi"}),
+		chatTextResponse("The tool echoed hi", "stop"),
+	)
+
+	registry := NewToolRegistry()
+	_ = registry.Register(ToolSpec{
+		Name:        "echo",
+		Description: "Returns the input message",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"echoed": input["message"]}, nil
+		},
+	})
+
+	provider := ProviderConfig{Provider: ProviderOpenAI, BaseURL: server.URL + "/v1"}
+	client := NewAgenticClientWithProvider(provider, registry, AgenticConfig{})
+	result, err := client.Run(context.Background(), "Echo hi")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Status != "completed" || result.FinalAnswer != "The tool echoed hi" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Trace.TotalToolCalls() != 1 || result.Trace.TotalTokens != 30 {
+		t.Errorf("trace = %+v", result.Trace)
+	}
+
+	// The follow-up request replays the call and its result
+	messages := cs.requests[1]["messages"].([]any)
+	assistant := messages[len(messages)-2].(map[string]any)
+	if calls, _ := assistant["tool_calls"].([]any); len(calls) != 1 {
+		t.Errorf("assistant message = %v", assistant)
+	}
+	tool := messages[len(messages)-1].(map[string]any)
+	if tool["role"] != "tool" || tool["tool_call_id"] != "call_1" || !strings.Contains(tool["content"].(string), "echoed") {
+		t.Errorf("tool message = %v", tool)
+	}
+}
+
+func TestProviderConfigFromEnv(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "")
+	t.Setenv("ANTHROPIC_API_KEY", "ant-key")
+	provider, err := ProviderConfigFromEnv()
+	if err != nil || provider.Provider != ProviderAnthropic || provider.APIKey != "ant-key" {
+		t.Errorf("default provider = %+v, %v", provider, err)
+	}
+
+	t.Setenv("LLM_PROVIDER", "OpenAI")
+	t.Setenv("OPENAI_BASE_URL", "http://localhost:11434/v1")
+	t.Setenv("OPENAI_API_KEY", "")
+	provider, err = ProviderConfigFromEnv()
+	if err != nil || provider.Provider != ProviderOpenAI || provider.BaseURL != "http://localhost:11434/v1" {
+		t.Errorf("openai provider = %+v, %v", provider, err)
+	}
+	if _, ok := provider.NewSender(BaseClientConfig{}).(*OpenAIBaseClient); !ok {
+		t.Error("expected an OpenAI sender")
+	}
+
+	t.Setenv("GOT_MODEL", "qwen2.5")
+	client := NewLLMClient(provider)
+	if openai, ok := client.(*OpenAILLMClient); !ok || openai.Model() != "qwen2.5" {
+		t.Errorf("client = %T", client)
+	}
+
+	t.Setenv("LLM_PROVIDER", "gemini")
+	if _, err := ProviderConfigFromEnv(); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
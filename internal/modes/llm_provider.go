@@ -0,0 +1,131 @@
+// Package modes - LLM provider selection
+package modes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"unified-thinking/internal/usage"
+)
+
+// Supported LLM providers
+const (
+	ProviderAnthropic = "anthropic"
+	ProviderOpenAI    = "openai"
+)
+
+// TextLLMClient is an LLMClient that also generates free-form text, as used
+// by the hypothesis, perspective and decomposition generators
+type TextLLMClient interface {
+	LLMClient
+	GenerateText(ctx context.Context, prompt string) (string, error)
+}
+
+// ProviderConfig selects the LLM provider and its endpoint
+type ProviderConfig struct {
+	Provider  string
+	APIKey    string
+	BaseURL   string
+	Transport *Transport     // Shared by every client created from this config, may be nil
+	Usage     *usage.Tracker // Shared usage tracker, may be nil
+
+	// WrapSender decorates every sender created by NewSender, e.g. to
+	// record or replay requests. May be nil.
+	WrapSender func(MessageSender) MessageSender
+}
+
+// ProviderConfigFromEnv reads LLM_PROVIDER (default anthropic). The
+// anthropic provider uses ANTHROPIC_API_KEY; the openai provider uses
+// OPENAI_BASE_URL and the optional OPENAI_API_KEY. LLM_MAX_RETRIES and
+// LLM_REQUESTS_PER_SECOND tune the shared transport.
+func ProviderConfigFromEnv() (ProviderConfig, error) {
+	var config ProviderConfig
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("LLM_PROVIDER")))
+	switch provider {
+	case "", ProviderAnthropic:
+		config = ProviderConfig{
+			Provider: ProviderAnthropic,
+			APIKey:   os.Getenv("ANTHROPIC_API_KEY"),
+		}
+	case ProviderOpenAI:
+		config = ProviderConfig{
+			Provider: ProviderOpenAI,
+			APIKey:   os.Getenv("OPENAI_API_KEY"),
+			BaseURL:  os.Getenv("OPENAI_BASE_URL"),
+		}
+	default:
+		return ProviderConfig{}, fmt.Errorf("unknown LLM_PROVIDER %q (supported: %s, %s)", provider, ProviderAnthropic, ProviderOpenAI)
+	}
+
+	transportConfig := DefaultTransportConfig()
+	if v := os.Getenv("LLM_MAX_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return ProviderConfig{}, fmt.Errorf("invalid LLM_MAX_RETRIES %q: must be a non-negative integer", v)
+		}
+		transportConfig.MaxRetries = n
+		if n == 0 {
+			transportConfig.MaxRetries = -1
+		}
+	}
+	if v := os.Getenv("LLM_REQUESTS_PER_SECOND"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 {
+			return ProviderConfig{}, fmt.Errorf("invalid LLM_REQUESTS_PER_SECOND %q: must be a non-negative number", v)
+		}
+		transportConfig.RequestsPerSecond = rate
+		if rate == 0 {
+			transportConfig.RequestsPerSecond = -1
+		}
+	}
+	config.Transport = NewTransport(transportConfig)
+
+	return config, nil
+}
+
+// DefaultModel returns the model used when none is configured
+func (p ProviderConfig) DefaultModel() string {
+	if p.Provider == ProviderOpenAI {
+		return defaultOpenAIModel
+	}
+	return "claude-sonnet-4-5-20250929"
+}
+
+// NewSender creates a MessageSender for the provider. The API key, base URL,
+// transport and usage tracker of the provider override those in config.
+func (p ProviderConfig) NewSender(config BaseClientConfig) MessageSender {
+	config.APIKey = p.APIKey
+	config.BaseURL = p.BaseURL
+	config.Transport = p.Transport
+	config.Usage = p.Usage
+	if config.Model == "" {
+		config.Model = p.DefaultModel()
+	}
+	var sender MessageSender
+	if p.Provider == ProviderOpenAI {
+		sender = NewOpenAIBaseClient(config)
+	} else {
+		sender = NewAnthropicBaseClient(config)
+	}
+	if p.WrapSender != nil {
+		sender = p.WrapSender(sender)
+	}
+	return sender
+}
+
+// NewLLMClient creates the LLM client for a provider. GOT_MODEL and
+// GOT_STRUCTURED_OUTPUT apply to every provider.
+func NewLLMClient(provider ProviderConfig) TextLLMClient {
+	sender := provider.NewSender(BaseClientConfig{Model: os.Getenv("GOT_MODEL")})
+	client := &AnthropicLLMClient{
+		MessageSender: sender,
+		useStructured: os.Getenv("GOT_STRUCTURED_OUTPUT") != "false",
+	}
+	if provider.Provider == ProviderOpenAI {
+		return &OpenAILLMClient{AnthropicLLMClient: client}
+	}
+	return client
+}
@@ -0,0 +1,188 @@
+// Package modes - Server-sent event streaming for the Anthropic Messages API
+package modes
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"unified-thinking/internal/streaming"
+)
+
+// maxStreamEvent bounds a single server-sent event; web search results
+// arrive as one event and can be large
+const maxStreamEvent = 8 * 1024 * 1024
+
+// streamEvent is one server-sent event of a streamed Messages API response
+type streamEvent struct {
+	Type         string         `json:"type"`
+	Index        int            `json:"index"`
+	Message      *APIResponse   `json:"message,omitempty"`       // message_start
+	ContentBlock *ResponseBlock `json:"content_block,omitempty"` // content_block_start
+	Delta        struct {
+		Type        string    `json:"type"`
+		Text        string    `json:"text,omitempty"`         // text_delta
+		PartialJSON string    `json:"partial_json,omitempty"` // input_json_delta
+		Citation    *Citation `json:"citation,omitempty"`     // citations_delta
+		StopReason  string    `json:"stop_reason,omitempty"`  // message_delta
+	} `json:"delta"`
+	Usage *Usage `json:"usage,omitempty"` // message_delta
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// messageStream assembles a streamed response from its events
+type messageStream struct {
+	resp    APIResponse
+	inputs  map[int]*strings.Builder // Partial JSON of tool inputs by block index
+	onDelta func(step, text string)
+	done    bool
+}
+
+// readMessageStream assembles a Messages API response from server-sent
+// events, calling onDelta with each piece of text or tool input JSON as it
+// arrives. It fails if the stream ends before message_stop.
+func readMessageStream(r io.Reader, onDelta func(step, text string)) (*APIResponse, error) {
+	stream := &messageStream{inputs: make(map[int]*strings.Builder), onDelta: onDelta}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamEvent)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				if err := stream.handle(data.String()); err != nil {
+					return nil, err
+				}
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		if stream.done {
+			return &stream.resp, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read stream: %w", err)
+	}
+	if data.Len() > 0 {
+		if err := stream.handle(data.String()); err != nil {
+			return nil, err
+		}
+	}
+	if !stream.done {
+		return nil, fmt.Errorf("read stream: %w", io.ErrUnexpectedEOF)
+	}
+	return &stream.resp, nil
+}
+
+// handle applies one event to the response
+func (s *messageStream) handle(data string) error {
+	var event streamEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return fmt.Errorf("unmarshal stream event: %w", err)
+	}
+
+	switch event.Type {
+	case "message_start":
+		if event.Message != nil {
+			s.resp.Usage = event.Message.Usage
+		}
+	case "content_block_start":
+		if event.ContentBlock == nil || event.Index != len(s.resp.Content) {
+			return fmt.Errorf("unexpected content block %d", event.Index)
+		}
+		s.resp.Content = append(s.resp.Content, *event.ContentBlock)
+	case "content_block_delta":
+		if event.Index < 0 || event.Index >= len(s.resp.Content) {
+			return fmt.Errorf("delta for unknown content block %d", event.Index)
+		}
+		block := &s.resp.Content[event.Index]
+		switch event.Delta.Type {
+		case "text_delta":
+			block.Text += event.Delta.Text
+			s.onDelta(streaming.TextStep, event.Delta.Text)
+		case "input_json_delta":
+			if s.inputs[event.Index] == nil {
+				s.inputs[event.Index] = &strings.Builder{}
+			}
+			s.inputs[event.Index].WriteString(event.Delta.PartialJSON)
+			s.onDelta(streaming.ToolInputStep, event.Delta.PartialJSON)
+		case "citations_delta":
+			if event.Delta.Citation != nil {
+				block.Citations = append(block.Citations, *event.Delta.Citation)
+			}
+		}
+	case "content_block_stop":
+		if input := s.inputs[event.Index]; input != nil && input.Len() > 0 && event.Index < len(s.resp.Content) {
+			var parsed map[string]any
+			if err := json.Unmarshal([]byte(input.String()), &parsed); err != nil {
+				return fmt.Errorf("unmarshal tool input: %w", err)
+			}
+			s.resp.Content[event.Index].Input = parsed
+		}
+	case "message_delta":
+		if event.Delta.StopReason != "" {
+			s.resp.StopReason = event.Delta.StopReason
+		}
+		if event.Usage != nil {
+			// Counts in message_delta are cumulative
+			s.resp.Usage.OutputTokens = event.Usage.OutputTokens
+			if event.Usage.InputTokens > 0 {
+				s.resp.Usage.InputTokens = event.Usage.InputTokens
+			}
+		}
+	case "message_stop":
+		s.done = true
+	case "error":
+		if event.Error != nil {
+			return fmt.Errorf("stream error (%s): %s", event.Error.Type, event.Error.Message)
+		}
+		return fmt.Errorf("stream error")
+	}
+	return nil
+}
+
+// deltaForwarder reports streamed output as TextDelta partial results,
+// coalescing deltas so at most one notification is sent per interval
+type deltaForwarder struct {
+	reporter streaming.ProgressReporter
+	interval time.Duration
+	step     string
+	pending  strings.Builder
+	last     time.Time
+}
+
+// add buffers a delta and sends the buffer once the interval has passed.
+// A change of step sends the previous step's text first.
+func (f *deltaForwarder) add(step, text string) {
+	if step != f.step {
+		f.flush()
+		f.step = step
+	}
+	f.pending.WriteString(text)
+	if time.Since(f.last) >= f.interval {
+		f.flush()
+	}
+}
+
+// flush sends the buffered text
+func (f *deltaForwarder) flush() {
+	if f.pending.Len() == 0 {
+		return
+	}
+	_ = f.reporter.ReportPartialResult(f.step, streaming.TextDelta{Text: f.pending.String()})
+	f.pending.Reset()
+	f.last = time.Now()
+}
@@ -0,0 +1,196 @@
+package modes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"unified-thinking/internal/streaming"
+	"unified-thinking/internal/usage"
+)
+
+// deltaReporter records the partial results it receives
+type deltaReporter struct {
+	streaming.DefaultReporter
+	mu     sync.Mutex
+	deltas map[string]string
+	calls  int
+}
+
+func (r *deltaReporter) IsEnabled() bool { return true }
+
+func (r *deltaReporter) ReportPartialResult(stepName string, data any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deltas == nil {
+		r.deltas = make(map[string]string)
+	}
+	r.deltas[stepName] += data.(streaming.TextDelta).Text
+	r.calls++
+	return nil
+}
+
+// sseEvents renders events in the Messages API streaming format
+func sseEvents(events ...string) string {
+	var b strings.Builder
+	for _, event := range events {
+		var typed struct {
+			Type string `json:"type"`
+		}
+		_ = json.Unmarshal([]byte(event), &typed)
+		fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", typed.Type, event)
+	}
+	return b.String()
+}
+
+var streamedToolUse = sseEvents(
+	`{"type":"message_start","message":{"content":[],"usage":{"input_tokens":25,"output_tokens":1}}}`,
+	`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+	`{"type":"ping"}`,
+	`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me "}}`,
+	`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"check."}}`,
+	`{"type":"content_block_stop","index":0}`,
+	`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"t1","name":"echo","input":{}}}`,
+	`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"message\":"}}`,
+	`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":" \"hi\"}"}}`,
+	`{"type":"content_block_stop","index":1}`,
+	`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":15}}`,
+	`{"type":"message_stop"}`,
+)
+
+func TestReadMessageStream(t *testing.T) {
+	var text []string
+	resp, err := readMessageStream(strings.NewReader(streamedToolUse), func(step, delta string) {
+		if step == streaming.TextStep {
+			text = append(text, delta)
+		}
+	})
+	if err != nil {
+		t.Fatalf("readMessageStream: %v", err)
+	}
+	if len(resp.Content) != 2 || resp.Content[0].Text != "Let me check." {
+		t.Fatalf("content = %+v", resp.Content)
+	}
+	if resp.Content[1].Input["message"] != "hi" || resp.StopReason != "tool_use" {
+		t.Errorf("tool use = %+v, stop reason %q", resp.Content[1], resp.StopReason)
+	}
+	if resp.Usage.InputTokens != 25 || resp.Usage.OutputTokens != 15 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+	if strings.Join(text, "|") != "Let me |check." {
+		t.Errorf("text deltas = %q", text)
+	}
+}
+
+func TestReadMessageStream_Errors(t *testing.T) {
+	truncated := sseEvents(`{"type":"message_start","message":{"content":[]}}`)
+	if _, err := readMessageStream(strings.NewReader(truncated), func(string, string) {}); err == nil {
+		t.Error("expected an error for a stream without message_stop")
+	}
+
+	overloaded := sseEvents(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`)
+	if _, err := readMessageStream(strings.NewReader(overloaded), func(string, string) {}); err == nil || !strings.Contains(err.Error(), "overloaded_error") {
+		t.Errorf("err = %v, want the stream error", err)
+	}
+}
+
+func TestAnthropicBaseClient_StreamsToReporter(t *testing.T) {
+	var streamed atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true {
+			_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"whole"}],"stop_reason":"end_turn"}`))
+			return
+		}
+		streamed.Store(true)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(streamedToolUse))
+	}))
+	defer server.Close()
+
+	tracker := usage.NewTracker(usage.Budget{})
+	client := NewAnthropicBaseClient(BaseClientConfig{BaseURL: server.URL, Usage: tracker})
+
+	// Without an enabled reporter the request is not streamed
+	resp, err := client.SendRequest(context.Background(), &APIRequest{Model: "m", MaxTokens: 10})
+	if err != nil || extractTextFromResponse(resp) != "whole" || streamed.Load() {
+		t.Fatalf("unstreamed response = %+v, %v", resp, err)
+	}
+
+	reporter := &deltaReporter{}
+	ctx := streaming.WithConfig(streaming.WithReporter(context.Background(), reporter), streaming.StreamingConfig{MinInterval: time.Hour})
+	resp, err = client.SendRequest(ctx, &APIRequest{Model: "m", MaxTokens: 10})
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if !streamed.Load() || resp.Content[1].Name != "echo" {
+		t.Fatalf("streamed response = %+v", resp)
+	}
+	// Deltas are coalesced per step but none are lost
+	if reporter.deltas[streaming.TextStep] != "Let me check." || reporter.deltas[streaming.ToolInputStep] != `{"message": "hi"}` {
+		t.Errorf("deltas = %q", reporter.deltas)
+	}
+	if reporter.calls != 3 {
+		t.Errorf("partial results = %d, want 3 (first delta, rest of text, tool input)", reporter.calls)
+	}
+	if got := tracker.Summary().Total; got.Calls != 2 || got.TotalTokens != 40 {
+		t.Errorf("recorded usage = %+v, want the streamed usage", got)
+	}
+}
+
+func TestAnthropicBaseClient_StreamCancelled(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(sseEvents(
+			`{"type":"message_start","message":{"content":[]}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Thinking"}}`,
+		)))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	transport, _ := newTestTransport(TransportConfig{})
+	client := NewAnthropicBaseClient(BaseClientConfig{BaseURL: server.URL, Transport: transport})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reporter := &cancellingReporter{cancel: cancel}
+	_, err := client.SendRequest(streaming.WithReporter(ctx, reporter), &APIRequest{Model: "m", MaxTokens: 10})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, a cancelled stream must not be retried", calls.Load())
+	}
+}
+
+// cancellingReporter cancels the request once the first delta arrives, as a
+// client sending notifications/cancelled would
+type cancellingReporter struct {
+	streaming.DefaultReporter
+	cancel context.CancelFunc
+}
+
+func (r *cancellingReporter) IsEnabled() bool { return true }
+
+func (r *cancellingReporter) ReportPartialResult(string, any) error {
+	r.cancel()
+	return nil
+}
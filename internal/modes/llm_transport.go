@@ -0,0 +1,471 @@
+// Package modes - Resilient HTTP transport for LLM providers
+package modes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"unified-thinking/internal/ratelimit"
+)
+
+// ErrCircuitOpen is returned without contacting the provider while the
+// circuit breaker is open
+var ErrCircuitOpen = errors.New("LLM circuit breaker open")
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// APIError is a non-2xx response from an LLM provider
+type APIError struct {
+	StatusCode int           `json:"status_code"`
+	Type       string        `json:"type,omitempty"` // Provider error type, e.g. rate_limit_error
+	Message    string        `json:"message"`
+	RetryAfter time.Duration `json:"retry_after,omitempty"` // From retry-after headers, 0 if absent
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("API error %d (%s): %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed if sent again:
+// timeouts, rate limits (429), overload (529) and 5xx server errors
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return e.StatusCode >= 500
+}
+
+// IsRetryable reports whether err is a transient provider or network error.
+// Errors while consuming a streamed response are never retried.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var streamErr *streamError
+	if errors.As(err, &streamErr) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	// Anything else came from the HTTP round trip itself
+	return true
+}
+
+// newAPIError builds an APIError from a response, using the provider's
+// error message when the body is a JSON error object. Anthropic and OpenAI
+// both use {"error": {"type": ..., "message": ...}}.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    string(body),
+		RetryAfter: parseRetryAfter(resp.Header, time.Now()),
+	}
+
+	var parsed struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && parsed.Error.Message != "" {
+		apiErr.Type = parsed.Error.Type
+		apiErr.Message = parsed.Error.Message
+	}
+	return apiErr
+}
+
+// parseRetryAfter reads retry-after-ms or retry-after, which is either a
+// number of seconds or an HTTP date
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	value := header.Get("retry-after")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// TransportConfig configures retries, rate limiting and the circuit breaker
+type TransportConfig struct {
+	Timeout time.Duration // Per-attempt HTTP timeout; streams bound only the wait for response headers
+
+	MaxRetries int           // Retries after the first attempt
+	BaseDelay  time.Duration // Backoff before the first retry, doubled per retry
+	MaxDelay   time.Duration // Upper bound for backoff and retry-after waits
+
+	RequestsPerSecond float64 // Token bucket refill rate
+	Burst             int     // Token bucket size
+
+	FailureThreshold int           // Consecutive failed requests that open the circuit
+	Cooldown         time.Duration // How long the circuit stays open before a probe
+}
+
+// DefaultTransportConfig returns the default transport settings
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		Timeout:           defaultTimeout,
+		MaxRetries:        3,
+		BaseDelay:         time.Second,
+		MaxDelay:          30 * time.Second,
+		RequestsPerSecond: 10,
+		Burst:             10,
+		FailureThreshold:  5,
+		Cooldown:          30 * time.Second,
+	}
+}
+
+// TransportStats summarizes transport activity, reported by get-metrics
+type TransportStats struct {
+	Requests    int64 `json:"requests"`     // Logical requests, excluding retries
+	Attempts    int64 `json:"attempts"`     // HTTP round trips
+	Retries     int64 `json:"retries"`      // Attempts after the first
+	Failures    int64 `json:"failures"`     // Requests that failed after all retries
+	RateLimited int64 `json:"rate_limited"` // 429 and 529 responses
+	Rejected    int64 `json:"rejected"`     // Requests failed fast by the open circuit
+
+	CircuitState        string     `json:"circuit_state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// Transport sends provider requests with client-side rate limiting, retries
+// with exponential backoff and jitter, and a circuit breaker. It is safe for
+// concurrent use; clients sharing a Transport share its limits and breaker.
+type Transport struct {
+	config       TransportConfig
+	httpClient   *http.Client // Post: Timeout bounds the whole round trip
+	streamClient *http.Client // PostStream: Timeout bounds the wait for response headers
+	limiter      *ratelimit.TokenBucket
+
+	mu      sync.Mutex
+	stats   TransportStats
+	opened  time.Time
+	probing bool
+
+	// Replaceable in tests
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func() float64
+}
+
+// NewTransport creates a transport. Zero fields in config take their
+// defaults, except RequestsPerSecond, MaxRetries and FailureThreshold where
+// a negative value disables the feature.
+func NewTransport(config TransportConfig) *Transport {
+	defaults := DefaultTransportConfig()
+	if config.Timeout == 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = defaults.MaxRetries
+	}
+	if config.BaseDelay == 0 {
+		config.BaseDelay = defaults.BaseDelay
+	}
+	if config.MaxDelay == 0 {
+		config.MaxDelay = defaults.MaxDelay
+	}
+	if config.RequestsPerSecond == 0 {
+		config.RequestsPerSecond = defaults.RequestsPerSecond
+	}
+	if config.Burst <= 0 {
+		config.Burst = defaults.Burst
+	}
+	if config.FailureThreshold == 0 {
+		config.FailureThreshold = defaults.FailureThreshold
+	}
+	if config.Cooldown == 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+
+	// A stream may legitimately run far longer than Timeout once it has
+	// started, so after the headers it is bounded only by ctx
+	streamTransport := http.DefaultTransport.(*http.Transport).Clone()
+	streamTransport.ResponseHeaderTimeout = config.Timeout
+
+	t := &Transport{
+		config:       config,
+		httpClient:   &http.Client{Timeout: config.Timeout},
+		streamClient: &http.Client{Transport: streamTransport},
+		now:          time.Now,
+		sleep:        sleepContext,
+		jitter:       rand.Float64,
+	}
+	if config.RequestsPerSecond > 0 {
+		t.limiter = ratelimit.NewTokenBucket(config.RequestsPerSecond, config.Burst)
+	}
+	t.stats.CircuitState = CircuitClosed
+	return t
+}
+
+// Post sends a JSON body to url and returns the body of the 2xx response.
+// Non-2xx responses are returned as *APIError.
+func (t *Transport) Post(ctx context.Context, url string, header http.Header, body []byte) ([]byte, error) {
+	var respBody []byte
+	err := t.send(ctx, t.httpClient, url, header, body, func(r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("read response: %w", err)
+		}
+		respBody = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return respBody, nil
+}
+
+// PostStream sends a JSON body to url and hands the body of the 2xx response
+// to consume as it arrives. Failures before the response are retried like
+// Post; failures while consuming are not, since part of the response has
+// already been delivered. Timeout applies only until the response headers
+// arrive; the stream itself runs until it ends or ctx is cancelled.
+func (t *Transport) PostStream(ctx context.Context, url string, header http.Header, body []byte, consume func(io.Reader) error) error {
+	return t.send(ctx, t.streamClient, url, header, body, func(r io.Reader) error {
+		if err := consume(r); err != nil {
+			return &streamError{err: err}
+		}
+		return nil
+	})
+}
+
+// streamError is a failure while consuming a streamed response
+type streamError struct {
+	err error
+}
+
+// Error implements error
+func (e *streamError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *streamError) Unwrap() error {
+	return e.err
+}
+
+// send performs a request with rate limiting, retries and the circuit
+// breaker, passing the body of the 2xx response to consume
+func (t *Transport) send(ctx context.Context, client *http.Client, url string, header http.Header, body []byte, consume func(io.Reader) error) error {
+	if err := t.allow(); err != nil {
+		return err
+	}
+
+	var lastErr error
+	maxRetries := t.config.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := t.sleep(ctx, t.backoff(attempt, lastErr)); err != nil {
+				t.release()
+				return err
+			}
+			t.count(func(s *TransportStats) { s.Retries++ })
+		}
+
+		if t.limiter != nil {
+			if err := t.limiter.Wait(ctx); err != nil {
+				t.release()
+				return fmt.Errorf("rate limiter wait: %w", err)
+			}
+		}
+
+		err := t.attempt(ctx, client, url, header, body, consume)
+		if err == nil {
+			t.record(nil)
+			return nil
+		}
+		lastErr = err
+		if !IsRetryable(err) {
+			break
+		}
+	}
+
+	t.record(lastErr)
+	return lastErr
+}
+
+// attempt performs a single HTTP round trip
+func (t *Transport) attempt(ctx context.Context, client *http.Client, url string, header http.Header, body []byte, consume func(io.Reader) error) error {
+	t.count(func(s *TransportStats) { s.Attempts++ })
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	for key, values := range header {
+		httpReq.Header[key] = values
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("API request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read response: %w", err)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 529 {
+			t.count(func(s *TransportStats) { s.RateLimited++ })
+		}
+		return newAPIError(resp, respBody)
+	}
+	return consume(resp.Body)
+}
+
+// backoff returns the wait before a retry: the provider's retry-after when
+// given, otherwise BaseDelay doubled per retry with jitter in [d/2, d]
+func (t *Transport) backoff(attempt int, lastErr error) time.Duration {
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+		return minDuration(apiErr.RetryAfter, t.config.MaxDelay)
+	}
+
+	delay := float64(t.config.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if delay > float64(t.config.MaxDelay) {
+		delay = float64(t.config.MaxDelay)
+	}
+	return time.Duration(delay/2 + t.jitter()*delay/2)
+}
+
+// allow admits a request unless the circuit is open. After the cooldown a
+// single probe request is let through in the half-open state.
+func (t *Transport) allow() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats.Requests++
+
+	switch t.stats.CircuitState {
+	case CircuitOpen:
+		remaining := t.config.Cooldown - t.now().Sub(t.opened)
+		if remaining > 0 {
+			t.stats.Rejected++
+			return fmt.Errorf("%w: retrying in %s (last error: %s)", ErrCircuitOpen, remaining.Round(time.Second), t.stats.LastError)
+		}
+		t.stats.CircuitState = CircuitHalfOpen
+		t.probing = true
+	case CircuitHalfOpen:
+		if t.probing {
+			t.stats.Rejected++
+			return fmt.Errorf("%w: probe request in flight", ErrCircuitOpen)
+		}
+		t.probing = true
+	}
+	return nil
+}
+
+// release ends a request that was cancelled before reaching a verdict
+func (t *Transport) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probing = false
+}
+
+// record updates the breaker with a request outcome. Only transient errors
+// count as failures; a 400 says nothing about the provider's health.
+func (t *Transport) record(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probing = false
+
+	if err == nil {
+		t.stats.ConsecutiveFailures = 0
+		t.stats.CircuitState = CircuitClosed
+		t.stats.OpenedAt = nil
+		return
+	}
+
+	t.stats.Failures++
+	t.stats.LastError = err.Error()
+	if !IsRetryable(err) {
+		if t.stats.CircuitState == CircuitHalfOpen {
+			t.stats.CircuitState = CircuitClosed
+		}
+		return
+	}
+
+	t.stats.ConsecutiveFailures++
+	threshold := t.config.FailureThreshold
+	if t.stats.CircuitState == CircuitHalfOpen || (threshold > 0 && t.stats.ConsecutiveFailures >= threshold) {
+		t.opened = t.now()
+		opened := t.opened
+		t.stats.CircuitState = CircuitOpen
+		t.stats.OpenedAt = &opened
+	}
+}
+
+// count applies an update to the stats under the lock
+func (t *Transport) count(update func(*TransportStats)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	update(&t.stats)
+}
+
+// Stats returns a snapshot of the transport statistics
+func (t *Transport) Stats() TransportStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.stats
+	if t.stats.CircuitState == CircuitOpen && t.now().Sub(t.opened) >= t.config.Cooldown {
+		stats.CircuitState = CircuitHalfOpen
+	}
+	return stats
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,224 @@
+package modes
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestTransport returns a transport that records backoff waits instead
+// of sleeping
+func newTestTransport(config TransportConfig) (*Transport, *[]time.Duration) {
+	if config.RequestsPerSecond == 0 {
+		config.RequestsPerSecond = -1
+	}
+	transport := NewTransport(config)
+	var sleeps []time.Duration
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	transport.jitter = func() float64 { return 1 }
+	return transport, &sleeps
+}
+
+// statusSequence serves the given statuses in order, then 200s
+func statusSequence(t *testing.T, headers http.Header, statuses ...int) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1)) - 1
+		if n < len(statuses) {
+			for key, values := range headers {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(statuses[n])
+			_, _ = w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestTransport_RetriesTransientErrors(t *testing.T) {
+	server, calls := statusSequence(t, nil, 529, 503)
+	transport, sleeps := newTestTransport(TransportConfig{BaseDelay: 100 * time.Millisecond})
+
+	client := NewAnthropicBaseClient(BaseClientConfig{BaseURL: server.URL, Transport: transport})
+	resp, err := client.SendRequest(context.Background(), &APIRequest{Model: "m", MaxTokens: 10})
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if extractTextFromResponse(resp) != "ok" {
+		t.Errorf("response = %+v", resp)
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3", *calls)
+	}
+	// Exponential backoff: 100ms, then 200ms (jitter pinned to the maximum)
+	if len(*sleeps) != 2 || (*sleeps)[0] != 100*time.Millisecond || (*sleeps)[1] != 200*time.Millisecond {
+		t.Errorf("sleeps = %v", *sleeps)
+	}
+
+	stats := transport.Stats()
+	if stats.Requests != 1 || stats.Attempts != 3 || stats.Retries != 2 || stats.RateLimited != 1 || stats.Failures != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestTransport_HonorsRetryAfter(t *testing.T) {
+	server, _ := statusSequence(t, http.Header{"Retry-After": []string{"2"}}, 429)
+	transport, sleeps := newTestTransport(TransportConfig{})
+
+	if _, err := transport.Post(context.Background(), server.URL, http.Header{}, []byte("{}")); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 2*time.Second {
+		t.Errorf("sleeps = %v, want [2s]", *sleeps)
+	}
+}
+
+func TestTransport_TypedErrorsAreNotRetried(t *testing.T) {
+	server, calls := statusSequence(t, nil, 400)
+	transport, _ := newTestTransport(TransportConfig{})
+
+	_, err := transport.Post(context.Background(), server.URL, http.Header{}, []byte("{}"))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != 400 || apiErr.Type != "overloaded_error" || apiErr.Message != "Overloaded" {
+		t.Errorf("apiErr = %+v", apiErr)
+	}
+	if IsRetryable(err) || *calls != 1 {
+		t.Errorf("retryable = %v, calls = %d", IsRetryable(err), *calls)
+	}
+	if transport.Stats().ConsecutiveFailures != 0 {
+		t.Error("client errors should not count towards the circuit breaker")
+	}
+}
+
+func TestTransport_CircuitBreaker(t *testing.T) {
+	statuses := make([]int, 4)
+	for i := range statuses {
+		statuses[i] = 503
+	}
+	server, calls := statusSequence(t, nil, statuses...)
+	transport, _ := newTestTransport(TransportConfig{MaxRetries: 1, FailureThreshold: 2, Cooldown: time.Minute})
+	now := time.Now()
+	transport.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := transport.Post(ctx, server.URL, http.Header{}, nil); err == nil {
+			t.Fatal("expected failure")
+		}
+	}
+	if state := transport.Stats().CircuitState; state != CircuitOpen {
+		t.Fatalf("state = %s, want open", state)
+	}
+
+	// Open circuit fails fast without contacting the server
+	_, err := transport.Post(ctx, server.URL, http.Header{}, nil)
+	if !errors.Is(err, ErrCircuitOpen) || *calls != 4 {
+		t.Fatalf("err = %v, calls = %d", err, *calls)
+	}
+
+	// After the cooldown a probe is admitted and closes the circuit
+	now = now.Add(time.Minute)
+	if state := transport.Stats().CircuitState; state != CircuitHalfOpen {
+		t.Errorf("state = %s, want half_open", state)
+	}
+	if _, err := transport.Post(ctx, server.URL, http.Header{}, nil); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	stats := transport.Stats()
+	if stats.CircuitState != CircuitClosed || stats.Rejected != 1 || stats.Failures != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestTransport_FailedProbeReopens(t *testing.T) {
+	server, _ := statusSequence(t, nil, 500, 500)
+	transport, _ := newTestTransport(TransportConfig{MaxRetries: -1, FailureThreshold: 1, Cooldown: time.Second})
+	now := time.Now()
+	transport.now = func() time.Time { return now }
+
+	_, _ = transport.Post(context.Background(), server.URL, http.Header{}, nil)
+	now = now.Add(time.Second)
+	_, _ = transport.Post(context.Background(), server.URL, http.Header{}, nil)
+
+	stats := transport.Stats()
+	if stats.CircuitState != CircuitOpen || stats.OpenedAt == nil || !stats.OpenedAt.Equal(now) {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestTransport_StreamOutlivesTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-headers" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 4; i++ {
+			_, _ = w.Write([]byte("event\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	t.Cleanup(server.Close)
+	transport, _ := newTestTransport(TransportConfig{Timeout: 100 * time.Millisecond, MaxRetries: -1})
+
+	// The stream lasts twice the timeout but its headers arrive at once
+	var received []byte
+	err := transport.PostStream(context.Background(), server.URL, http.Header{}, nil, func(r io.Reader) error {
+		data, err := io.ReadAll(r)
+		received = data
+		return err
+	})
+	if err != nil || string(received) != "event\nevent\nevent\nevent\n" {
+		t.Fatalf("PostStream = %q, %v", received, err)
+	}
+
+	// The timeout still bounds the wait for the response headers
+	err = transport.PostStream(context.Background(), server.URL+"/slow-headers", http.Header{}, nil, func(r io.Reader) error {
+		_, err := io.ReadAll(r)
+		return err
+	})
+	if err == nil {
+		t.Error("expected timeout awaiting response headers")
+	}
+
+	// Non-streaming requests are bounded as a whole
+	if _, err := transport.Post(context.Background(), server.URL, http.Header{}, nil); err == nil {
+		t.Error("expected Post to time out on a long response")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"none", http.Header{}, 0},
+		{"seconds", http.Header{"Retry-After": []string{"3"}}, 3 * time.Second},
+		{"milliseconds", http.Header{"Retry-After-Ms": []string{"250"}, "Retry-After": []string{"1"}}, 250 * time.Millisecond},
+		{"date", http.Header{"Retry-After": []string{now.Add(5 * time.Second).Format(http.TimeFormat)}}, 5 * time.Second},
+		{"invalid", http.Header{"Retry-After": []string{"soon"}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.header, now); got != tt.want {
+				t.Errorf("parseRetryAfter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
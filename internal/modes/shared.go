@@ -10,7 +10,10 @@
 // and uses the shared storage layer for persistence.
 package modes
 
-import "unified-thinking/internal/types"
+import (
+	"unified-thinking/internal/storage"
+	"unified-thinking/internal/types"
+)
 
 // ThoughtInput represents input parameters for thought processing across all modes.
 //
@@ -25,6 +28,7 @@ import "unified-thinking/internal/types"
 //   - ForceRebellion: For divergent mode, forces unconventional thinking
 //   - ChallengeAssumptions: Forces modes to question underlying assumptions
 //   - CrossRefs: For tree mode, cross-references to other branches
+//   - Workspace: Namespace for thoughts and branches (empty keeps the mode's storage)
 type ThoughtInput struct {
 	Content              string
 	Type                 string
@@ -36,6 +40,7 @@ type ThoughtInput struct {
 	ForceRebellion       bool
 	ChallengeAssumptions bool
 	CrossRefs            []CrossRefInput
+	Workspace            string
 }
 
 // CrossRefInput represents a cross-reference input
@@ -73,3 +78,12 @@ type BranchHistory struct {
 	Insights   []*types.Insight
 	CrossRefs  []*types.CrossRef
 }
+
+// scopedStorage returns the view of store for workspace, or store itself
+// when no workspace was requested
+func scopedStorage(store storage.Storage, workspace string) storage.Storage {
+	if workspace == "" {
+		return store
+	}
+	return storage.ForWorkspace(store, workspace)
+}
@@ -148,20 +148,27 @@ var SafeToolSubset = []string{
 	"search-knowledge-graph",     // Knowledge retrieval (read-only)
 	"build-causal-graph",         // Causal analysis
 	"get-causal-graph",           // Retrieve causal graph (read-only)
+	"list-causal-graphs",         // List stored causal graphs (read-only)
 	"generate-hypotheses",        // Abductive reasoning
 	"evaluate-hypotheses",        // Hypothesis testing
 	"analyze-perspectives",       // Multi-perspective analysis
 	"detect-biases",              // Bias detection
 	"detect-fallacies",           // Fallacy detection
 	"decompose-problem",          // Problem decomposition
+	"list-decompositions",        // List problem decompositions (read-only)
+	"get-decomposition",          // Retrieve problem decomposition (read-only)
 	"make-decision",              // Decision analysis
+	"list-decisions",             // List decisions (read-only)
+	"get-decision",               // Retrieve decision (read-only)
 	"analyze-temporal",           // Temporal analysis
 	"probabilistic-reasoning",    // Probabilistic reasoning
+	"list-beliefs",               // List probabilistic beliefs (read-only)
 	"assess-evidence",            // Evidence assessment
 	"detect-contradictions",      // Contradiction detection
 	"self-evaluate",              // Metacognitive analysis
 	"detect-blind-spots",         // Unknown unknowns detection
 	"dual-process-think",         // System 1/2 reasoning
+	"diff-checkpoints",           // Compare checkpoints (read-only)
 	"verify-thought",             // Hallucination detection
 	"retrieve-similar-cases",     // Case-based reasoning (read-only)
 	"decompose-argument",         // Argument analysis
@@ -175,11 +182,22 @@ var SafeToolSubset = []string{
 	"got-refine",                 // Graph-of-Thoughts refinement
 	"got-score",                  // Graph-of-Thoughts scoring
 	"got-get-state",              // Graph-of-Thoughts state (read-only)
+	"got-list-states",            // List Graph-of-Thoughts graphs (read-only)
+}
+
+// RecursiveTools run other tools. Offering them to an agent or a workflow
+// step could recurse without bound, so neither may call them.
+var RecursiveTools = []string{
+	"run-agent",
+	"run-preset",
+	"execute-workflow",
+	"resume-workflow-run",
+	"rerun-workflow",
 }
 
 // ExcludedTools lists tools that should NOT be available for agentic use
 // These have side effects, are resource-intensive, or could cause recursion
-var ExcludedTools = []string{
+var ExcludedTools = append(append([]string{
 	// Side effects - write to storage
 	"store-entity",
 	"create-relationship",
@@ -188,19 +206,25 @@ var ExcludedTools = []string{
 	// Session management
 	"export-session",
 	"import-session",
+	"set-workspace",
+},
 	// Could cause recursion
-	"run-agent",
-	"run-preset",
-	"execute-workflow",
+	RecursiveTools...),
 	// State modifying
 	"got-initialize",
 	"got-prune",
 	"got-finalize",
 	"create-checkpoint",
 	"restore-checkpoint",
+	"fork-checkpoint",
+	"prune-branch",
+	"focus-branch",
+	"register-workflow",
+	"delete-workflow",
+	"configure-fallacy-rules",
 	// Resource intensive
 	"embed-multimodal",
-}
+)
 
 // BuildSchemaFromStruct creates a JSON schema from a struct definition
 // This is a helper for building tool input schemas
@@ -4,6 +4,7 @@ package modes
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 )
 
@@ -325,6 +326,46 @@ func TestSafeToolSubset(t *testing.T) {
 	}
 }
 
+func TestExcludedTools_StateWriting(t *testing.T) {
+	// Tools that change stored records, registries or server settings; the
+	// agent's default tools are every registered tool not in ExcludedTools
+	stateWriting := []string{
+		"store-entity",
+		"create-relationship",
+		"set-workspace",
+		"import-session",
+		"got-initialize",
+		"got-prune",
+		"got-finalize",
+		"create-checkpoint",
+		"restore-checkpoint",
+		"fork-checkpoint",
+		"prune-branch",
+		"focus-branch",
+		"register-workflow",
+		"delete-workflow",
+		"configure-fallacy-rules",
+	}
+
+	excluded := make(map[string]bool, len(ExcludedTools))
+	for _, name := range ExcludedTools {
+		excluded[name] = true
+	}
+	for _, name := range stateWriting {
+		if !excluded[name] {
+			t.Errorf("state-writing tool %q is available to the agent by default", name)
+		}
+	}
+}
+
+func TestExcludedTools_Recursive(t *testing.T) {
+	for _, name := range RecursiveTools {
+		if !slices.Contains(ExcludedTools, name) {
+			t.Errorf("recursive tool %q is available to the agent by default", name)
+		}
+	}
+}
+
 func TestBuildSchemaFromStruct(t *testing.T) {
 	schema := BuildSchemaFromStruct(
 		"Test schema",
@@ -21,10 +21,12 @@ func NewTreeMode(storage storage.Storage) *TreeMode {
 
 // ProcessThought processes a thought in tree mode with branching
 func (m *TreeMode) ProcessThought(ctx context.Context, input ThoughtInput) (*ThoughtResult, error) {
+	store := scopedStorage(m.storage, input.Workspace)
+
 	// Determine branch (use provided or active)
 	branchID := input.BranchID
 	if branchID == "" {
-		if activeBranch, err := m.storage.GetActiveBranch(); err == nil && activeBranch != nil {
+		if activeBranch, err := store.GetActiveBranch(); err == nil && activeBranch != nil {
 			branchID = activeBranch.ID
 		} else {
 			// Create new branch
@@ -38,14 +40,14 @@ func (m *TreeMode) ProcessThought(ctx context.Context, input ThoughtInput) (*Tho
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
 			}
-			if err := m.storage.StoreBranch(branch); err != nil {
+			if err := store.StoreBranch(branch); err != nil {
 				return nil, err
 			}
 			branchID = branch.ID
 		}
 	} else {
 		// BranchID was provided - check if it exists, create if it doesn't
-		_, err := m.storage.GetBranch(branchID)
+		_, err := store.GetBranch(branchID)
 		if err != nil {
 			// Branch doesn't exist, create it
 			branch := &types.Branch{
@@ -59,14 +61,14 @@ func (m *TreeMode) ProcessThought(ctx context.Context, input ThoughtInput) (*Tho
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
 			}
-			if err := m.storage.StoreBranch(branch); err != nil {
+			if err := store.StoreBranch(branch); err != nil {
 				return nil, err
 			}
 		}
 	}
 
 	// Update branch access tracking (branch is guaranteed to exist now)
-	if err := m.storage.UpdateBranchAccess(branchID); err != nil {
+	if err := store.UpdateBranchAccess(branchID); err != nil {
 		return nil, err
 	}
 
@@ -82,12 +84,12 @@ func (m *TreeMode) ProcessThought(ctx context.Context, input ThoughtInput) (*Tho
 		Timestamp:  time.Now(),
 	}
 
-	if err := m.storage.StoreThought(thought); err != nil {
+	if err := store.StoreThought(thought); err != nil {
 		return nil, err
 	}
 
 	// Append thought directly to branch (optimized - avoids deep copy)
-	if err := m.storage.AppendThoughtToBranch(branchID, thought); err != nil {
+	if err := store.AppendThoughtToBranch(branchID, thought); err != nil {
 		return nil, err
 	}
 
@@ -101,11 +103,11 @@ func (m *TreeMode) ProcessThought(ctx context.Context, input ThoughtInput) (*Tho
 			SupportingEvidence: map[string]interface{}{},
 			CreatedAt:          time.Now(),
 		}
-		if err := m.storage.StoreInsight(insight); err != nil {
+		if err := store.StoreInsight(insight); err != nil {
 			return nil, err
 		}
 		// Append insight directly to branch (optimized - avoids deep copy)
-		if err := m.storage.AppendInsightToBranch(branchID, insight); err != nil {
+		if err := store.AppendInsightToBranch(branchID, insight); err != nil {
 			return nil, err
 		}
 	}
@@ -122,14 +124,14 @@ func (m *TreeMode) ProcessThought(ctx context.Context, input ThoughtInput) (*Tho
 				CreatedAt:  time.Now(),
 			}
 			// Append cross-ref directly to branch (optimized - avoids deep copy)
-			if err := m.storage.AppendCrossRefToBranch(branchID, crossRef); err != nil {
+			if err := store.AppendCrossRefToBranch(branchID, crossRef); err != nil {
 				return nil, err
 			}
 		}
 	}
 
 	// Get branch for result metrics
-	branch, err := m.storage.GetBranch(branchID)
+	branch, err := store.GetBranch(branchID)
 	if err != nil {
 		return nil, err
 	}
@@ -138,10 +140,10 @@ func (m *TreeMode) ProcessThought(ctx context.Context, input ThoughtInput) (*Tho
 	if len(input.CrossRefs) > 0 {
 		m.updateBranchMetrics(branch)
 		// Store updated priority and confidence
-		if err := m.storage.UpdateBranchPriority(branchID, branch.Priority); err != nil {
+		if err := store.UpdateBranchPriority(branchID, branch.Priority); err != nil {
 			return nil, err
 		}
-		if err := m.storage.UpdateBranchConfidence(branchID, branch.Confidence); err != nil {
+		if err := store.UpdateBranchConfidence(branchID, branch.Confidence); err != nil {
 			return nil, err
 		}
 	}
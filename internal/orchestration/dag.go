@@ -0,0 +1,265 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"unified-thinking/internal/streaming"
+	"unified-thinking/internal/types"
+)
+
+// DefaultDAGConcurrency is the number of DAG steps run at once when a
+// workflow does not set MaxConcurrency
+const DefaultDAGConcurrency = 4
+
+// validateDAG checks that step IDs are unique, that dependencies name steps
+// of the workflow and that the dependencies have no cycles
+func validateDAG(workflow *Workflow) error {
+	if len(workflow.Steps) == 0 {
+		return fmt.Errorf("dag workflow has no steps")
+	}
+	switch workflow.FailurePolicy {
+	case "", FailureAbort, FailureSkipDependents:
+	default:
+		return fmt.Errorf("unknown failure policy %q (supported: %s, %s)", workflow.FailurePolicy, FailureAbort, FailureSkipDependents)
+	}
+	if workflow.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must not be negative")
+	}
+
+	steps := make(map[string]*WorkflowStep, len(workflow.Steps))
+	for i, step := range workflow.Steps {
+		if step == nil || step.ID == "" {
+			return fmt.Errorf("step %d has no id", i)
+		}
+		if _, exists := steps[step.ID]; exists {
+			return fmt.Errorf("duplicate step id %s", step.ID)
+		}
+		steps[step.ID] = step
+	}
+	for _, step := range workflow.Steps {
+		for _, dep := range step.DependsOn {
+			if _, exists := steps[dep]; !exists {
+				return fmt.Errorf("step %s depends on unknown step %s", step.ID, dep)
+			}
+		}
+	}
+
+	// Depth-first search; a step reached again while on the stack closes a cycle
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(steps))
+	var path []string
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == id {
+					start = i
+				}
+			}
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path[start:], id), " -> "))
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range steps[id].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+	for _, step := range workflow.Steps {
+		if err := visit(step.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dagOutcome is the result of one DAG step
+type dagOutcome struct {
+	step    *WorkflowStep
+	result  interface{}
+	run     stepRun
+	err     error
+	started time.Time
+}
+
+// executeDAG runs each step once all of its dependencies have finished,
+// running independent steps concurrently up to the workflow's limit. Steps
+// skipped by their condition count as finished. When a step fails, the
+// failure policy either aborts the run, cancelling running steps, or skips
+// everything that depends on the failed step and reports a partial result.
+func (o *Orchestrator) executeDAG(ctx context.Context, workflow *Workflow, input types.Metadata, reasoningCtx *ReasoningContext, result *WorkflowResult) error {
+	if err := validateDAG(workflow); err != nil {
+		return err
+	}
+	limit := workflow.MaxConcurrency
+	if limit <= 0 {
+		limit = DefaultDAGConcurrency
+	}
+	reporter := streaming.GetReporter(ctx)
+	totalSteps := len(workflow.Steps)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Unmet dependencies per step, and the steps waiting on each step
+	pending := make(map[string]int, totalSteps)
+	dependents := make(map[string][]*WorkflowStep)
+	var ready []*WorkflowStep
+	for _, step := range workflow.Steps {
+		pending[step.ID] = len(step.DependsOn)
+		for _, dep := range step.DependsOn {
+			dependents[dep] = append(dependents[dep], step)
+		}
+		if len(step.DependsOn) == 0 {
+			ready = append(ready, step)
+		}
+	}
+
+	var mu sync.Mutex // Guards reasoningCtx while steps run
+	outcomes := make(chan dagOutcome, totalSteps)
+	done := make(map[string]bool, totalSteps)
+	running, succeeded := 0, 0
+	var failed []string
+	var abortErr error
+
+	report := func(step *WorkflowStep, message string) {
+		if reporter.IsEnabled() {
+			if err := reporter.ReportStep(len(done), totalSteps, step.ID, message); err != nil {
+				log.Printf("failed to report dag step %s: %v", step.ID, err)
+			}
+		}
+	}
+	release := func(step *WorkflowStep) {
+		for _, dependent := range dependents[step.ID] {
+			pending[dependent.ID]--
+			if pending[dependent.ID] == 0 && !done[dependent.ID] {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	var skip func(step *WorkflowStep, reason error)
+	skip = func(step *WorkflowStep, reason error) {
+		done[step.ID] = true
+		result.recordStep(step, "skipped", time.Now(), reason)
+		report(step, "Skipped: "+reason.Error())
+		for _, dependent := range dependents[step.ID] {
+			if !done[dependent.ID] {
+				skip(dependent, fmt.Errorf("dependency %s did not run", step.ID))
+			}
+		}
+	}
+
+	for len(done) < totalSteps {
+		for abortErr == nil && running < limit && len(ready) > 0 {
+			step := ready[0]
+			ready = ready[1:]
+			if done[step.ID] {
+				continue
+			}
+			if result.completed[step.ID] {
+				done[step.ID] = true
+				succeeded++
+				release(step)
+				continue
+			}
+
+			mu.Lock()
+			shouldExecute := o.shouldRun(step, reasoningCtx)
+			mu.Unlock()
+			if !shouldExecute {
+				done[step.ID] = true
+				result.recordStep(step, "skipped", time.Now(), nil)
+				report(step, "Skipped (condition not met)")
+				release(step)
+				continue
+			}
+
+			running++
+			report(step, "Executing: "+step.Tool)
+			go func(step *WorkflowStep) {
+				started := time.Now()
+				stepResult, run, err := o.runStep(ctx, step, input, reasoningCtx, &mu)
+				outcomes <- dagOutcome{step: step, result: stepResult, run: run, err: err, started: started}
+			}(step)
+		}
+		if running == 0 {
+			break
+		}
+
+		outcome := <-outcomes
+		running--
+		step := outcome.step
+		done[step.ID] = true
+
+		if outcome.err != nil {
+			result.recordRun(step, "failed", outcome.started, outcome.run, outcome.err)
+			failed = append(failed, step.ID)
+			report(step, "Failed: "+outcome.err.Error())
+			if workflow.FailurePolicy == FailureSkipDependents {
+				for _, dependent := range dependents[step.ID] {
+					if !done[dependent.ID] {
+						skip(dependent, fmt.Errorf("dependency %s failed", step.ID))
+					}
+				}
+			} else if abortErr == nil {
+				abortErr = fmt.Errorf("step %s failed: %w", step.ID, outcome.err)
+				cancel()
+			}
+			continue
+		}
+
+		result.recordRun(step, "success", outcome.started, outcome.run, nil)
+		mu.Lock()
+		if step.StoreAs != "" {
+			reasoningCtx.Results[step.StoreAs] = outcome.result
+		}
+		mu.Unlock()
+		result.StepResults[step.ID] = outcome.result
+		succeeded++
+		if reporter.IsEnabled() {
+			if err := reporter.ReportPartialResult(step.ID, outcome.result); err != nil {
+				log.Printf("failed to report partial result for dag step %s: %v", step.ID, err)
+			}
+		}
+		release(step)
+	}
+
+	if err := o.UpdateContext(reasoningCtx); err != nil {
+		log.Printf("failed to update context after dag execution: %v", err)
+	}
+
+	if abortErr != nil {
+		for _, step := range workflow.Steps {
+			if !done[step.ID] {
+				done[step.ID] = true
+				result.recordStep(step, "skipped", time.Now(), fmt.Errorf("workflow aborted"))
+			}
+		}
+		return abortErr
+	}
+	if len(failed) > 0 {
+		result.Status = "partial"
+		if succeeded == 0 {
+			result.Status = "failed"
+		}
+		result.ErrorMessage = "failed steps: " + strings.Join(failed, ", ")
+	}
+	return nil
+}
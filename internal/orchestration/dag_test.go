@@ -0,0 +1,168 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// dagExecutor runs each tool for a fixed delay, tracking peak concurrency.
+// Tools named "fail-*" fail.
+type dagExecutor struct {
+	delay   time.Duration
+	running atomic.Int32
+	peak    atomic.Int32
+	mu      sync.Mutex
+	inputs  map[string]map[string]interface{}
+}
+
+func (e *dagExecutor) ExecuteTool(ctx context.Context, toolName string, input map[string]interface{}) (interface{}, error) {
+	n := e.running.Add(1)
+	defer e.running.Add(-1)
+	for {
+		peak := e.peak.Load()
+		if n <= peak || e.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	e.mu.Lock()
+	if e.inputs == nil {
+		e.inputs = make(map[string]map[string]interface{})
+	}
+	e.inputs[toolName] = input
+	e.mu.Unlock()
+
+	select {
+	case <-time.After(e.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if strings.HasPrefix(toolName, "fail") {
+		return nil, fmt.Errorf("%s failed", toolName)
+	}
+	return map[string]interface{}{"id": toolName + "-result"}, nil
+}
+
+func dagWorkflow(id string, steps ...*WorkflowStep) *Workflow {
+	return &Workflow{ID: id, Name: id, Type: WorkflowDAG, Steps: steps}
+}
+
+func dagStep(id string, deps ...string) *WorkflowStep {
+	return &WorkflowStep{ID: id, Tool: id, Input: types.Metadata{}, StoreAs: id, DependsOn: deps}
+}
+
+func timelineStatus(result *WorkflowResult) map[string]string {
+	statuses := make(map[string]string)
+	for _, timing := range result.Timeline {
+		statuses[timing.StepID] = timing.Status
+	}
+	return statuses
+}
+
+func TestRegisterWorkflow_DAGValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow *Workflow
+		want     string
+	}{
+		{"cycle", dagWorkflow("cycle", dagStep("a", "c"), dagStep("b", "a"), dagStep("c", "b")), "cycle: a -> c -> b -> a"},
+		{"self dependency", dagWorkflow("self", dagStep("a", "a")), "cycle"},
+		{"unknown dependency", dagWorkflow("unknown", dagStep("a"), dagStep("b", "missing")), "unknown step missing"},
+		{"duplicate id", dagWorkflow("dup", dagStep("a"), dagStep("a")), "duplicate step id a"},
+		{"no steps", dagWorkflow("empty"), "no steps"},
+		{"bad policy", &Workflow{ID: "policy", Name: "policy", Type: WorkflowDAG, FailurePolicy: "retry", Steps: []*WorkflowStep{dagStep("a")}}, "failure policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewOrchestrator().RegisterWorkflow(tt.workflow)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := NewOrchestrator().RegisterWorkflow(dagWorkflow("ok", dagStep("a"), dagStep("b", "a"), dagStep("c", "a", "b"))); err != nil {
+		t.Errorf("valid dag rejected: %v", err)
+	}
+}
+
+func TestExecuteDAGWorkflow(t *testing.T) {
+	executor := &dagExecutor{delay: 20 * time.Millisecond}
+	orch := NewOrchestratorWithExecutor(executor)
+
+	// a fans out to b, c and d, which join in e
+	join := dagStep("e", "b", "c", "d")
+	join.Input = types.Metadata{"from_b": "{{b.id}}"}
+	workflow := dagWorkflow("fan", dagStep("a"), dagStep("b", "a"), dagStep("c", "a"), dagStep("d", "a"), join)
+	workflow.MaxConcurrency = 2
+	if err := orch.RegisterWorkflow(workflow); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+
+	result, err := orch.ExecuteWorkflow(context.Background(), "fan", types.Metadata{})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+	if result.Status != "success" || len(result.StepResults) != 5 {
+		t.Fatalf("status = %s, results %v", result.Status, result.StepResults)
+	}
+	if peak := executor.peak.Load(); peak != 2 {
+		t.Errorf("peak concurrency = %d, want the limit of 2", peak)
+	}
+	if got := executor.inputs["e"]["from_b"]; got != "b-result" {
+		t.Errorf("join input = %v, want b's result", got)
+	}
+
+	// The timeline orders steps by completion and respects dependencies
+	if len(result.Timeline) != 5 || result.Timeline[0].StepID != "a" || result.Timeline[4].StepID != "e" {
+		t.Fatalf("timeline = %+v", result.Timeline)
+	}
+	for _, timing := range result.Timeline[1:4] {
+		if timing.StartedAt.Before(result.Timeline[0].FinishedAt) {
+			t.Errorf("%s started before its dependency finished", timing.StepID)
+		}
+	}
+}
+
+func TestExecuteDAGWorkflow_SkipDependents(t *testing.T) {
+	orch := NewOrchestratorWithExecutor(&dagExecutor{delay: time.Millisecond})
+	workflow := dagWorkflow("skip", dagStep("a"), dagStep("fail-b", "a"), dagStep("c", "fail-b"), dagStep("d", "c"), dagStep("e", "a"))
+	workflow.FailurePolicy = FailureSkipDependents
+	if err := orch.RegisterWorkflow(workflow); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+
+	result, err := orch.ExecuteWorkflow(context.Background(), "skip", types.Metadata{})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+	if result.Status != "partial" || !strings.Contains(result.ErrorMessage, "fail-b") {
+		t.Errorf("status = %s, error %q", result.Status, result.ErrorMessage)
+	}
+	want := map[string]string{"a": "success", "fail-b": "failed", "c": "skipped", "d": "skipped", "e": "success"}
+	if got := timelineStatus(result); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("timeline = %v, want %v", got, want)
+	}
+}
+
+func TestExecuteDAGWorkflow_Abort(t *testing.T) {
+	executor := &dagExecutor{delay: 10 * time.Millisecond}
+	orch := NewOrchestratorWithExecutor(executor)
+	if err := orch.RegisterWorkflow(dagWorkflow("abort", dagStep("fail-a"), dagStep("slow"), dagStep("b", "fail-a"))); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+
+	result, err := orch.ExecuteWorkflow(context.Background(), "abort", types.Metadata{})
+	if err == nil || result.Status != "failed" {
+		t.Fatalf("err = %v, status %s; want an aborted run", err, result.Status)
+	}
+	if status := timelineStatus(result); status["fail-a"] != "failed" || status["b"] != "skipped" {
+		t.Errorf("timeline = %v", status)
+	}
+}
@@ -0,0 +1,1540 @@
+package orchestration
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"unified-thinking/internal/types"
+	"unified-thinking/pkg/cache"
+)
+
+// Limits that keep expressions cheap to evaluate
+const (
+	MaxExpressionLength      = 4096    // Characters in an expression
+	MaxExpressionSteps       = 100000  // Nodes evaluated per evaluation, counting lambda calls
+	MaxExpressionStringBytes = 1 << 20 // Bytes in a string produced by evaluation
+	MaxExpressionListItems   = 100000  // Items in a list produced by evaluation
+	MaxCachedExpressions     = 1024    // Compiled expressions kept by CompileExpression
+)
+
+// Expression is a compiled workflow expression. Expressions are used by
+// StepCondition.Expression, OutputTransform.Expression and {{ }} templates
+// in step input that are not plain references.
+//
+// The language has literals (numbers, 'strings' or "strings", true, false,
+// null, [lists]), variables, member access (a.b, a[0], a["key"]), the
+// operators ! - * / % + < <= > >= == != in && || ?? and ?:, and the
+// functions listed in exprFunctions. and, or and not are aliases of &&, ||
+// and !. filter, map, any, all and count take a lambda: filter(xs, x => x.score > 0.5).
+// Expressions cannot call anything else and are bounded by MaxExpressionSteps,
+// MaxExpressionStringBytes and MaxExpressionListItems.
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+// expressionCache holds recently compiled expressions by source
+var expressionCache = cache.New[string, *Expression](&cache.Config{MaxEntries: MaxCachedExpressions})
+
+// CompileExpression parses and checks an expression: syntax, function names,
+// argument counts and lambda placement
+func CompileExpression(source string) (*Expression, error) {
+	if cached, ok := expressionCache.Get(source); ok {
+		return cached, nil
+	}
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	if len(source) > MaxExpressionLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", MaxExpressionLength)
+	}
+	tokens, err := lexExpression(source)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", source, err)
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseExpression()
+	if err == nil && p.peek().kind != tokEOF {
+		err = p.errorf(p.peek(), "unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", source, err)
+	}
+	expr := &Expression{source: source, root: root}
+	expressionCache.Set(source, expr)
+	return expr, nil
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate evaluates the expression, resolving variables with lookup
+func (e *Expression) Evaluate(lookup func(name string) (interface{}, bool)) (interface{}, error) {
+	state := &exprState{lookup: lookup}
+	value, err := e.root.eval(state, nil)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", e.source, err)
+	}
+	return value, nil
+}
+
+// EvaluateBool evaluates the expression and converts the result with
+// truthiness: null, false, 0, "" and empty lists and objects are false
+func (e *Expression) EvaluateBool(lookup func(name string) (interface{}, bool)) (bool, error) {
+	value, err := e.Evaluate(lookup)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+// contextLookup resolves expression variables against a workflow run: step
+// results stored in the reasoning context, then the workflow input, then
+// "input" (the whole input), "problem", "confidence" and "context" (the
+// reasoning context with its thought, graph, belief, evidence and decision
+// IDs). Callers running steps concurrently must hold the context lock.
+func contextLookup(ctx *ReasoningContext, input types.Metadata) func(string) (interface{}, bool) {
+	return func(name string) (interface{}, bool) {
+		if value, exists := ctx.Results[name]; exists {
+			return value, true
+		}
+		if value, exists := input[name]; exists {
+			return value, true
+		}
+		switch name {
+		case "input":
+			return map[string]interface{}(input), true
+		case "problem":
+			return ctx.Problem, true
+		case "confidence":
+			return ctx.Confidence, true
+		case "context":
+			return map[string]interface{}{
+				"confidence":    ctx.Confidence,
+				"problem":       ctx.Problem,
+				"thoughts":      stringsToList(ctx.Thoughts),
+				"causal_graphs": stringsToList(ctx.CausalGraphs),
+				"beliefs":       stringsToList(ctx.Beliefs),
+				"evidence":      stringsToList(ctx.Evidence),
+				"decisions":     stringsToList(ctx.Decisions),
+			}, true
+		}
+		return nil, false
+	}
+}
+
+func stringsToList(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return list
+}
+
+// plainReference matches template references resolved by lookup alone
+var plainReference = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z0-9_]+)*$`)
+
+// templateExpression returns the expression of a {{ }} template that is not a
+// plain reference such as {{problem}} or {{graph.id}}
+func templateExpression(value string) (string, bool) {
+	if len(value) <= 4 || !strings.HasPrefix(value, "{{") || !strings.HasSuffix(value, "}}") {
+		return "", false
+	}
+	inner := value[2 : len(value)-2]
+	if plainReference.MatchString(inner) {
+		return "", false
+	}
+	return strings.TrimSpace(inner), true
+}
+
+// validateExpressions compiles the expressions of a step: its condition,
+// loop conditions, transform and {{ }} expressions in its input
+func validateExpressions(step *WorkflowStep) error {
+	conditions := []*StepCondition{step.Condition}
+	if step.Loop != nil {
+		conditions = append(conditions, step.Loop.While, step.Loop.Until)
+	}
+	for _, condition := range conditions {
+		if condition == nil || condition.Expression == "" {
+			continue
+		}
+		if _, err := CompileExpression(condition.Expression); err != nil {
+			return fmt.Errorf("step %s: condition: %w", step.ID, err)
+		}
+	}
+	if transform := step.Transform; transform != nil {
+		if transform.Type == "expression" && transform.Expression == "" {
+			return fmt.Errorf("step %s: expression transform needs an expression", step.ID)
+		}
+		if transform.Expression != "" {
+			if _, err := CompileExpression(transform.Expression); err != nil {
+				return fmt.Errorf("step %s: transform: %w", step.ID, err)
+			}
+		}
+	}
+	for key, value := range step.Input {
+		if err := validateInputExpressions(value); err != nil {
+			return fmt.Errorf("step %s: input %s: %w", step.ID, key, err)
+		}
+	}
+	return nil
+}
+
+// validateInputExpressions compiles the {{ }} expressions of an input value
+// the way resolveTemplateValue resolves them: in strings and lists
+func validateInputExpressions(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if source, ok := templateExpression(v); ok {
+			if _, err := CompileExpression(source); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := validateInputExpressions(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Lexer
+// ============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type exprToken struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int // Byte offset in the source
+}
+
+func (t exprToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// exprOperators lists operators, longest first
+var exprOperators = []string{"=>", "==", "!=", "<=", ">=", "&&", "||", "??",
+	"(", ")", "[", "]", ",", ".", "?", ":", "!", "<", ">", "+", "-", "*", "/", "%"}
+
+func lexExpression(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	i := 0
+	for i < len(source) {
+		r, size := utf8.DecodeRuneInString(source[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9':
+			start := i
+			// After a dot the number is a list index, so a.0.1 is a[0][1]
+			afterDot := len(tokens) > 0 && tokens[len(tokens)-1].kind == tokOp && tokens[len(tokens)-1].text == "."
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				if source[i] == '.' && (afterDot || i+1 >= len(source) || !isDigit(source[i+1])) {
+					break
+				}
+				i++
+			}
+			if !afterDot && i < len(source) && (source[i] == 'e' || source[i] == 'E') {
+				j := i + 1
+				if j < len(source) && (source[j] == '+' || source[j] == '-') {
+					j++
+				}
+				if j < len(source) && isDigit(source[j]) {
+					i = j
+					for i < len(source) && isDigit(source[i]) {
+						i++
+					}
+				}
+			}
+			num, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", source[start:i], start)
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: source[start:i], num: num, pos: start})
+		case r == '\'' || r == '"':
+			start := i
+			var sb strings.Builder
+			i += size
+			closed := false
+			for i < len(source) {
+				c, cs := utf8.DecodeRuneInString(source[i:])
+				if c == r {
+					i += cs
+					closed = true
+					break
+				}
+				if c == '\\' && i+1 < len(source) {
+					i++
+					switch source[i] {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					default:
+						sb.WriteByte(source[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteRune(c)
+				i += cs
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: sb.String(), pos: start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(source) {
+				c, cs := utf8.DecodeRuneInString(source[i:])
+				if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+					break
+				}
+				i += cs
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: source[start:i], pos: start})
+		default:
+			matched := ""
+			for _, op := range exprOperators {
+				if strings.HasPrefix(source[i:], op) {
+					matched = op
+					break
+				}
+			}
+			if matched == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, exprToken{kind: tokOp, text: matched, pos: i})
+			i += len(matched)
+		}
+	}
+	return append(tokens, exprToken{kind: tokEOF, pos: len(source)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	params []string // Lambda parameters in scope
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isOp reports whether the next token is one of ops, accepting the keyword
+// aliases and, or, not and in
+func (p *exprParser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return t.kind == tokOp || op == "and" || op == "or" || op == "not" || op == "in"
+		}
+	}
+	return false
+}
+
+func (p *exprParser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.errorf(p.peek(), "expected %q, found %s", op, p.peek())
+	}
+	p.next()
+	return nil
+}
+
+func (p *exprParser) errorf(t exprToken, format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), t.pos)
+}
+
+// parseExpression parses a conditional: a ?? b ? c : d
+func (p *exprParser) parseExpression() (exprNode, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOp("?") {
+		return cond, nil
+	}
+	p.next()
+	then, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return &condNode{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+// binaryLevels lists binary operators from the lowest precedence
+var binaryLevels = [][]string{
+	{"??"},
+	{"||", "or"},
+	{"&&", "and"},
+	{"==", "!="},
+	{"<", "<=", ">", ">=", "in"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) parseBinary(level int) (exprNode, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp(binaryLevels[level]...) {
+		op := p.next().text
+		switch op {
+		case "and":
+			op = "&&"
+		case "or":
+			op = "||"
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.isOp("!", "not", "-") {
+		op := p.next().text
+		if op == "not" {
+			op = "!"
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.isOp("."):
+			p.next()
+			t := p.next()
+			switch t.kind {
+			case tokIdent:
+				node = &indexNode{target: node, index: &literalNode{value: t.text}}
+			case tokNumber:
+				node = &indexNode{target: node, index: &literalNode{value: t.num}}
+			default:
+				return nil, p.errorf(t, "expected a field name after \".\", found %s", t)
+			}
+		case p.isOp("["):
+			p.next()
+			index, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			node = &indexNode{target: node, index: index}
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &literalNode{value: t.num}, nil
+	case tokString:
+		return &literalNode{value: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null", "nil":
+			return &literalNode{value: nil}, nil
+		case "and", "or", "not", "in":
+			return nil, p.errorf(t, "unexpected %s", t)
+		}
+		if p.isOp("=>") {
+			return nil, p.errorf(t, "lambda %s => ... is only allowed as an argument of filter, map, any, all or count", t.text)
+		}
+		if p.isOp("(") {
+			return p.parseCall(t)
+		}
+		return &identNode{name: t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			node, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expect(")")
+		case "[":
+			list := &listNode{}
+			for !p.isOp("]") {
+				item, err := p.parseExpression()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+			return list, p.expect("]")
+		}
+	}
+	return nil, p.errorf(t, "unexpected %s", t)
+}
+
+func (p *exprParser) parseCall(name exprToken) (exprNode, error) {
+	fn, exists := exprFunctions[name.text]
+	if !exists {
+		return nil, p.errorf(name, "unknown function %s", name.text)
+	}
+	p.next() // (
+	call := &callNode{name: name.text, fn: fn}
+	for !p.isOp(")") {
+		var arg exprNode
+		var err error
+		if fn.lambda && len(call.args) == 1 {
+			arg, err = p.parseLambda(name.text)
+		} else {
+			arg, err = p.parseExpression()
+		}
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(call.args) < fn.minArgs || (fn.maxArgs >= 0 && len(call.args) > fn.maxArgs) {
+		return nil, p.errorf(name, "%s takes %s", name.text, fn.arity())
+	}
+	return call, nil
+}
+
+func (p *exprParser) parseLambda(function string) (exprNode, error) {
+	param := p.next()
+	if param.kind != tokIdent || !p.isOp("=>") {
+		return nil, p.errorf(param, "%s expects a lambda such as x => x.score > 0.5 as its second argument", function)
+	}
+	p.next()
+	body, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return &lambdaNode{param: param.text, body: body}, nil
+}
+
+// ============================================================================
+// Evaluation
+// ============================================================================
+
+type exprState struct {
+	lookup func(string) (interface{}, bool)
+	steps  int
+}
+
+// exprScope binds lambda parameters
+type exprScope struct {
+	name   string
+	value  interface{}
+	parent *exprScope
+}
+
+type exprNode interface {
+	eval(state *exprState, scope *exprScope) (interface{}, error)
+}
+
+func (s *exprState) step() error {
+	s.steps++
+	if s.steps > MaxExpressionSteps {
+		return fmt.Errorf("evaluation exceeded %d steps", MaxExpressionSteps)
+	}
+	return nil
+}
+
+// checkStringSize fails when a string of n bytes would exceed
+// MaxExpressionStringBytes
+func checkStringSize(n int) error {
+	if n > MaxExpressionStringBytes {
+		return fmt.Errorf("string would be longer than %d bytes", MaxExpressionStringBytes)
+	}
+	return nil
+}
+
+// checkListSize fails when a list of n items would exceed MaxExpressionListItems
+func checkListSize(n int) error {
+	if n > MaxExpressionListItems {
+		return fmt.Errorf("list would have more than %d items", MaxExpressionListItems)
+	}
+	return nil
+}
+
+// checkValueSize fails when a value produced by evaluation is too large
+func checkValueSize(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return checkStringSize(len(v))
+	case []interface{}:
+		return checkListSize(len(v))
+	}
+	return nil
+}
+
+type literalNode struct{ value interface{} }
+
+func (n *literalNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	return n.value, state.step()
+}
+
+type listNode struct{ items []exprNode }
+
+func (n *listNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	if err := state.step(); err != nil {
+		return nil, err
+	}
+	list := make([]interface{}, len(n.items))
+	for i, item := range n.items {
+		value, err := item.eval(state, scope)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = value
+	}
+	return list, nil
+}
+
+type identNode struct{ name string }
+
+// eval resolves a variable. Unknown variables are null so that ?? and
+// default can supply fallbacks.
+func (n *identNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	if err := state.step(); err != nil {
+		return nil, err
+	}
+	for s := scope; s != nil; s = s.parent {
+		if s.name == n.name {
+			return s.value, nil
+		}
+	}
+	if state.lookup != nil {
+		if value, ok := state.lookup(n.name); ok {
+			return normalizeValue(value), nil
+		}
+	}
+	return nil, nil
+}
+
+type indexNode struct{ target, index exprNode }
+
+// eval reads a field or list element; missing ones are null
+func (n *indexNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	target, err := n.target.eval(state, scope)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(state, scope)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case map[string]interface{}:
+		key, ok := index.(string)
+		if !ok {
+			key = formatValue(index)
+		}
+		return normalizeValue(t[key]), nil
+	case []interface{}:
+		i, ok := index.(float64)
+		if !ok || i != math.Trunc(i) {
+			return nil, fmt.Errorf("list index must be an integer, got %s", typeName(index))
+		}
+		if i < 0 {
+			i += float64(len(t))
+		}
+		if i < 0 || int(i) >= len(t) {
+			return nil, nil
+		}
+		return normalizeValue(t[int(i)]), nil
+	case string:
+		if i, ok := index.(float64); ok && i == math.Trunc(i) {
+			runes := []rune(t)
+			if i < 0 {
+				i += float64(len(runes))
+			}
+			if i >= 0 && int(i) < len(runes) {
+				return string(runes[int(i)]), nil
+			}
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n *unaryNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	value, err := n.operand.eval(state, scope)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !truthy(value), nil
+	}
+	num, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %s", typeName(value))
+	}
+	return -num, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	left, err := n.left.eval(state, scope)
+	if err != nil {
+		return nil, err
+	}
+	// Short-circuit operators
+	switch n.op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(state, scope)
+		return truthy(right), err
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(state, scope)
+		return truthy(right), err
+	case "??":
+		if left != nil {
+			return left, nil
+		}
+		return n.right.eval(state, scope)
+	}
+
+	right, err := n.right.eval(state, scope)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return valuesEqual(left, right), nil
+	case "!=":
+		return !valuesEqual(left, right), nil
+	case "in":
+		return containsValue(right, left)
+	case "<", "<=", ">", ">=":
+		cmp, err := compareOrdered(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		}
+		return cmp >= 0, nil
+	case "+":
+		if ls, ok := left.(string); ok {
+			if err := checkStringSize(len(ls) + formattedSize(right)); err != nil {
+				return nil, err
+			}
+			return ls + formatValue(right), nil
+		}
+		if ll, ok := left.([]interface{}); ok {
+			if rl, ok := right.([]interface{}); ok {
+				if err := checkListSize(len(ll) + len(rl)); err != nil {
+					return nil, err
+				}
+				return append(append([]interface{}{}, ll...), rl...), nil
+			}
+		}
+	}
+
+	a, aOK := left.(float64)
+	b, bOK := right.(float64)
+	if !aOK || !bOK {
+		return nil, fmt.Errorf("operator %s needs numbers, got %s and %s", n.op, typeName(left), typeName(right))
+	}
+	switch n.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return a / b, nil
+	}
+	if b == 0 {
+		return nil, fmt.Errorf("modulo by zero")
+	}
+	return math.Mod(a, b), nil
+}
+
+type condNode struct{ cond, then, otherwise exprNode }
+
+func (n *condNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	cond, err := n.cond.eval(state, scope)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(cond) {
+		return n.then.eval(state, scope)
+	}
+	return n.otherwise.eval(state, scope)
+}
+
+type lambdaNode struct {
+	param string
+	body  exprNode
+}
+
+func (n *lambdaNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	return nil, fmt.Errorf("lambda used outside a list function")
+}
+
+// call evaluates the lambda body with its parameter bound to value
+func (n *lambdaNode) call(state *exprState, scope *exprScope, value interface{}) (interface{}, error) {
+	return n.body.eval(state, &exprScope{name: n.param, value: value, parent: scope})
+}
+
+type callNode struct {
+	name string
+	fn   *exprFunc
+	args []exprNode
+}
+
+func (n *callNode) eval(state *exprState, scope *exprScope) (interface{}, error) {
+	if err := state.step(); err != nil {
+		return nil, err
+	}
+	if n.fn.lambda {
+		list, err := n.args[0].eval(state, scope)
+		if err != nil {
+			return nil, err
+		}
+		items, ok := list.([]interface{})
+		if list == nil {
+			items, ok = []interface{}{}, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s needs a list, got %s", n.name, typeName(list))
+		}
+		var lambda *lambdaNode
+		if len(n.args) > 1 {
+			lambda = n.args[1].(*lambdaNode)
+		}
+		value, err := n.fn.list(items, func(item interface{}) (interface{}, error) {
+			return lambda.call(state, scope, item)
+		}, lambda != nil)
+		if err == nil {
+			err = checkValueSize(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n.name, err)
+		}
+		return value, nil
+	}
+
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(state, scope)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	value, err := n.fn.call(args)
+	if err == nil {
+		err = checkValueSize(value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return value, nil
+}
+
+// ============================================================================
+// Values
+// ============================================================================
+
+// normalizeValue converts Go values from tool results to expression values:
+// float64 numbers, map[string]interface{} objects and []interface{} lists
+func normalizeValue(value interface{}) interface{} {
+	if num, ok := toFloat64(value); ok {
+		return num
+	}
+	switch v := value.(type) {
+	case nil, bool, string, float64, map[string]interface{}, []interface{}:
+		return v
+	case types.Metadata:
+		return map[string]interface{}(v)
+	case []string:
+		return stringsToList(v)
+	case uint, uint8, uint16, uint32, uint64, int8, int16:
+		return reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0))).Float()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Ptr:
+		// Typed tool results are read through their JSON form
+		data, err := json.Marshal(value)
+		if err != nil {
+			return value
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return value
+		}
+		return decoded
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = rv.Index(i).Interface()
+		}
+		return list
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			m := make(map[string]interface{}, rv.Len())
+			for _, key := range rv.MapKeys() {
+				m[key.String()] = rv.MapIndex(key).Interface()
+			}
+			return m
+		}
+	}
+	return value
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func valuesEqual(a, b interface{}) bool {
+	a, b = normalizeValue(a), normalizeValue(b)
+	if an, ok := a.(float64); ok {
+		bn, ok := b.(float64)
+		return ok && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compareOrdered(a, b interface{}) (int, error) {
+	switch av := a.(type) {
+	case float64:
+		if bv, ok := b.(float64); ok {
+			switch {
+			case av < bv:
+				return -1, nil
+			case av > bv:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s", typeName(a), typeName(b))
+}
+
+// containsValue reports whether a list holds an item, a string holds a
+// substring or an object holds a key
+func containsValue(container, item interface{}) (bool, error) {
+	switch c := container.(type) {
+	case nil:
+		return false, nil
+	case string:
+		s, ok := item.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot search a string for %s", typeName(item))
+		}
+		return strings.Contains(c, s), nil
+	case []interface{}:
+		for _, v := range c {
+			if valuesEqual(v, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		key, ok := item.(string)
+		if !ok {
+			return false, nil
+		}
+		_, exists := c[key]
+		return exists, nil
+	}
+	return false, fmt.Errorf("cannot search %s", typeName(container))
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// formattedSize estimates the length of formatValue(value) without building
+// it, stopping once it exceeds MaxExpressionStringBytes
+func formattedSize(value interface{}) int {
+	switch v := value.(type) {
+	case nil:
+		return 4
+	case string:
+		return len(v)
+	case float64:
+		return len(strconv.FormatFloat(v, 'f', -1, 64))
+	case []interface{}:
+		size := 2 + len(v)
+		for _, item := range v {
+			if size > MaxExpressionStringBytes {
+				break
+			}
+			size += formattedSize(item)
+		}
+		return size
+	case map[string]interface{}:
+		size := 5 + 2*len(v)
+		for key, item := range v {
+			if size > MaxExpressionStringBytes {
+				break
+			}
+			size += len(key) + formattedSize(item)
+		}
+		return size
+	}
+	return len(fmt.Sprint(value))
+}
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// exprFunc is a function callable from expressions. Functions with lambda
+// set take a list and an optional lambda and are evaluated by list.
+type exprFunc struct {
+	minArgs, maxArgs int // maxArgs -1 is variadic
+	lambda           bool
+	call             func(args []interface{}) (interface{}, error)
+	list             func(items []interface{}, apply func(interface{}) (interface{}, error), hasLambda bool) (interface{}, error)
+}
+
+func (f *exprFunc) arity() string {
+	switch {
+	case f.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", f.minArgs)
+	case f.minArgs == f.maxArgs && f.minArgs == 1:
+		return "1 argument"
+	case f.minArgs == f.maxArgs:
+		return fmt.Sprintf("%d arguments", f.minArgs)
+	}
+	return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+}
+
+func fixed(n int, call func(args []interface{}) (interface{}, error)) *exprFunc {
+	return &exprFunc{minArgs: n, maxArgs: n, call: call}
+}
+
+func stringFunc(fn func(s string) interface{}) *exprFunc {
+	return fixed(1, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return fn(s), nil
+	})
+}
+
+func numberFunc(fn func(x float64) float64) *exprFunc {
+	return fixed(1, func(args []interface{}) (interface{}, error) {
+		x, ok := args[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("needs a number, got %s", typeName(args[0]))
+		}
+		return fn(x), nil
+	})
+}
+
+func stringArg(value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("needs a string, got %s", typeName(value))
+	}
+	return s, nil
+}
+
+func listArg(value interface{}) ([]interface{}, error) {
+	if value == nil {
+		return []interface{}{}, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("needs a list, got %s", typeName(value))
+	}
+	return list, nil
+}
+
+// numbers returns the numbers of a list, or of the arguments when there are
+// several
+func numbers(args []interface{}) ([]float64, error) {
+	values := args
+	if len(args) == 1 {
+		list, err := listArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		values = list
+	}
+	nums := make([]float64, len(values))
+	for i, v := range values {
+		num, ok := normalizeValue(v).(float64)
+		if !ok {
+			return nil, fmt.Errorf("needs numbers, got %s", typeName(normalizeValue(v)))
+		}
+		nums[i] = num
+	}
+	return nums, nil
+}
+
+// exprFunctions are the functions expressions can call
+var exprFunctions = map[string]*exprFunc{
+	// Strings
+	"lower": stringFunc(func(s string) interface{} { return strings.ToLower(s) }),
+	"upper": stringFunc(func(s string) interface{} { return strings.ToUpper(s) }),
+	"trim":  stringFunc(func(s string) interface{} { return strings.TrimSpace(s) }),
+	"starts_with": fixed(2, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		prefix, err := stringArg(args[1])
+		return strings.HasPrefix(s, prefix), err
+	}),
+	"ends_with": fixed(2, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		suffix, err := stringArg(args[1])
+		return strings.HasSuffix(s, suffix), err
+	}),
+	"contains": fixed(2, func(args []interface{}) (interface{}, error) {
+		return containsValue(args[0], args[1])
+	}),
+	"split": fixed(2, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		sep, err := stringArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if err := checkListSize(strings.Count(s, sep) + 1); err != nil {
+			return nil, err
+		}
+		return stringsToList(strings.Split(s, sep)), nil
+	}),
+	"join": fixed(2, func(args []interface{}) (interface{}, error) {
+		list, err := listArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		sep, err := stringArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		parts := make([]string, len(list))
+		size := len(sep) * len(list)
+		for i, v := range list {
+			value := normalizeValue(v)
+			if size += formattedSize(value); size > MaxExpressionStringBytes {
+				return nil, checkStringSize(size)
+			}
+			parts[i] = formatValue(value)
+		}
+		return strings.Join(parts, sep), nil
+	}),
+	"replace": fixed(3, func(args []interface{}) (interface{}, error) {
+		var s [3]string
+		for i, arg := range args {
+			str, err := stringArg(arg)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = str
+		}
+		if err := checkStringSize(len(s[0]) + strings.Count(s[0], s[1])*(len(s[2])-len(s[1]))); err != nil {
+			return nil, err
+		}
+		return strings.ReplaceAll(s[0], s[1], s[2]), nil
+	}),
+	"matches": fixed(2, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := stringArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+		return re.MatchString(s), nil
+	}),
+	"string": fixed(1, func(args []interface{}) (interface{}, error) {
+		if err := checkStringSize(formattedSize(args[0])); err != nil {
+			return nil, err
+		}
+		return formatValue(args[0]), nil
+	}),
+	"number": fixed(1, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case float64:
+			return v, nil
+		case bool:
+			if v {
+				return 1.0, nil
+			}
+			return 0.0, nil
+		case string:
+			num, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", v)
+			}
+			return num, nil
+		}
+		return nil, fmt.Errorf("cannot convert %s to a number", typeName(args[0]))
+	}),
+
+	// Numbers
+	"abs":   numberFunc(math.Abs),
+	"floor": numberFunc(math.Floor),
+	"ceil":  numberFunc(math.Ceil),
+	"round": {minArgs: 1, maxArgs: 2, call: func(args []interface{}) (interface{}, error) {
+		x, ok := args[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("needs a number, got %s", typeName(args[0]))
+		}
+		digits := 0.0
+		if len(args) == 2 {
+			if digits, ok = args[1].(float64); !ok {
+				return nil, fmt.Errorf("digits must be a number")
+			}
+		}
+		scale := math.Pow(10, digits)
+		return math.Round(x*scale) / scale, nil
+	}},
+	"min": {minArgs: 1, maxArgs: -1, call: func(args []interface{}) (interface{}, error) {
+		nums, err := numbers(args)
+		if err != nil || len(nums) == 0 {
+			return nil, err
+		}
+		m := nums[0]
+		for _, n := range nums[1:] {
+			m = math.Min(m, n)
+		}
+		return m, nil
+	}},
+	"max": {minArgs: 1, maxArgs: -1, call: func(args []interface{}) (interface{}, error) {
+		nums, err := numbers(args)
+		if err != nil || len(nums) == 0 {
+			return nil, err
+		}
+		m := nums[0]
+		for _, n := range nums[1:] {
+			m = math.Max(m, n)
+		}
+		return m, nil
+	}},
+	"sum": fixed(1, func(args []interface{}) (interface{}, error) {
+		nums, err := numbers(args)
+		total := 0.0
+		for _, n := range nums {
+			total += n
+		}
+		return total, err
+	}),
+	"avg": fixed(1, func(args []interface{}) (interface{}, error) {
+		nums, err := numbers(args)
+		if err != nil || len(nums) == 0 {
+			return nil, err
+		}
+		total := 0.0
+		for _, n := range nums {
+			total += n
+		}
+		return total / float64(len(nums)), nil
+	}),
+
+	// Collections
+	"len": fixed(1, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case nil:
+			return 0.0, nil
+		case string:
+			return float64(utf8.RuneCountInString(v)), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("%s has no length", typeName(args[0]))
+	}),
+	"keys": fixed(1, func(args []interface{}) (interface{}, error) {
+		m, ok := args[0].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("needs an object, got %s", typeName(args[0]))
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return stringsToList(keys), nil
+	}),
+	"first": fixed(1, func(args []interface{}) (interface{}, error) {
+		list, err := listArg(args[0])
+		if err != nil || len(list) == 0 {
+			return nil, err
+		}
+		return normalizeValue(list[0]), nil
+	}),
+	"last": fixed(1, func(args []interface{}) (interface{}, error) {
+		list, err := listArg(args[0])
+		if err != nil || len(list) == 0 {
+			return nil, err
+		}
+		return normalizeValue(list[len(list)-1]), nil
+	}),
+	"sort": fixed(1, func(args []interface{}) (interface{}, error) {
+		list, err := listArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		sorted := make([]interface{}, len(list))
+		for i, v := range list {
+			sorted[i] = normalizeValue(v)
+		}
+		var sortErr error
+		sort.SliceStable(sorted, func(i, j int) bool {
+			cmp, err := compareOrdered(sorted[i], sorted[j])
+			if err != nil && sortErr == nil {
+				sortErr = err
+			}
+			return cmp < 0
+		})
+		return sorted, sortErr
+	}),
+
+	// Defaulting
+	"default": fixed(2, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil || args[0] == "" {
+			return args[1], nil
+		}
+		return args[0], nil
+	}),
+	"exists": fixed(1, func(args []interface{}) (interface{}, error) {
+		return args[0] != nil, nil
+	}),
+
+	// List functions taking a lambda
+	"filter": {minArgs: 2, maxArgs: 2, lambda: true, list: func(items []interface{}, apply func(interface{}) (interface{}, error), _ bool) (interface{}, error) {
+		filtered := make([]interface{}, 0)
+		for _, item := range items {
+			item = normalizeValue(item)
+			keep, err := apply(item)
+			if err != nil {
+				return nil, err
+			}
+			if truthy(keep) {
+				filtered = append(filtered, item)
+			}
+		}
+		return filtered, nil
+	}},
+	"map": {minArgs: 2, maxArgs: 2, lambda: true, list: func(items []interface{}, apply func(interface{}) (interface{}, error), _ bool) (interface{}, error) {
+		mapped := make([]interface{}, len(items))
+		for i, item := range items {
+			value, err := apply(normalizeValue(item))
+			if err != nil {
+				return nil, err
+			}
+			mapped[i] = value
+		}
+		return mapped, nil
+	}},
+	"any": {minArgs: 2, maxArgs: 2, lambda: true, list: func(items []interface{}, apply func(interface{}) (interface{}, error), _ bool) (interface{}, error) {
+		for _, item := range items {
+			ok, err := apply(normalizeValue(item))
+			if err != nil {
+				return nil, err
+			}
+			if truthy(ok) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}},
+	"all": {minArgs: 2, maxArgs: 2, lambda: true, list: func(items []interface{}, apply func(interface{}) (interface{}, error), _ bool) (interface{}, error) {
+		for _, item := range items {
+			ok, err := apply(normalizeValue(item))
+			if err != nil {
+				return nil, err
+			}
+			if !truthy(ok) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}},
+	"count": {minArgs: 1, maxArgs: 2, lambda: true, list: func(items []interface{}, apply func(interface{}) (interface{}, error), hasLambda bool) (interface{}, error) {
+		if !hasLambda {
+			return float64(len(items)), nil
+		}
+		n := 0
+		for _, item := range items {
+			ok, err := apply(normalizeValue(item))
+			if err != nil {
+				return nil, err
+			}
+			if truthy(ok) {
+				n++
+			}
+		}
+		return float64(n), nil
+	}},
+}
@@ -0,0 +1,297 @@
+package orchestration
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"unified-thinking/internal/types"
+)
+
+func TestExpression_Evaluate(t *testing.T) {
+	vars := map[string]interface{}{
+		"hypotheses": []interface{}{
+			map[string]interface{}{"name": "cache", "score": 0.9},
+			map[string]interface{}{"name": "db", "score": 0.4},
+			types.Metadata{"name": "network", "score": 0.7},
+		},
+		"analysis": map[string]interface{}{"summary": "  Latency Spike ", "count": 3},
+		"tags":     []string{"perf", "db"},
+	}
+	lookup := func(name string) (interface{}, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3 % 4", 1.0},
+		{"-analysis.count + 1", -2.0},
+		{"analysis.count >= 3 && !(analysis.count > 5)", true},
+		{"analysis.count == 3 and not false", true},
+		{"'a' < 'b' or 1 / 0", true},
+		{"lower(trim(analysis.summary))", "latency spike"},
+		{"upper('x') + '-' + 2", "X-2"},
+		{"starts_with(analysis.summary, '  L') && ends_with('abc', 'bc')", true},
+		{"contains(analysis.summary, 'Spike') && 'db' in tags && !('x' in tags)", true},
+		{"join(split('a,b,c', ','), '+')", "a+b+c"},
+		{"replace('a-b', '-', '_')", "a_b"},
+		{"matches('v1.2', '^v[0-9]+\\\\.[0-9]+$')", true},
+		{"number('2.5') + 1", 3.5},
+		{"string(3) + string(true)", "3true"},
+		{"round(2.345, 2) + abs(-1) + floor(1.7) + ceil(0.2)", 5.35},
+		{"min(3, 1, 2) + max([4, 5])", 6.0},
+		{"sum(map(hypotheses, h => h.score))", 2.0},
+		{"avg([1, 2, 3])", 2.0},
+		{"map(filter(hypotheses, h => h.score > 0.5), h => h.name)", []interface{}{"cache", "network"}},
+		{"any(hypotheses, h => h.name == 'db') && !all(hypotheses, h => h.score > 0.5)", true},
+		{"count(hypotheses, h => h.score > 0.5) + count(tags)", 4.0},
+		{"first(sort(map(hypotheses, h => h.name)))", "cache"},
+		{"last(hypotheses).name", "network"},
+		{"hypotheses.0.name + hypotheses[-1]['name']", "cachenetwork"},
+		{"len(hypotheses) + len('héllo') + len(analysis)", 10.0},
+		{"keys(analysis)", []interface{}{"count", "summary"}},
+		{"missing.field ?? 'fallback'", "fallback"},
+		{"default('', 'empty') + default('x', 'y')", "emptyx"},
+		{"exists(analysis.count) && !exists(analysis.nothing)", true},
+		{"analysis.count > 2 ? 'high' : 'low'", "high"},
+		{"[1, 'a'] + [true]", []interface{}{1.0, "a", true}},
+		{"hypotheses[5] == null", true},
+	}
+	for _, tt := range tests {
+		expr, err := CompileExpression(tt.expr)
+		if err != nil {
+			t.Errorf("CompileExpression(%s): %v", tt.expr, err)
+			continue
+		}
+		got, err := expr.Evaluate(lookup)
+		if err != nil {
+			t.Errorf("Evaluate(%s): %v", tt.expr, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestExpression_RuntimeErrors(t *testing.T) {
+	tests := map[string]string{
+		"'a' > 1":                    "cannot compare string with number",
+		"1 / 0":                      "division by zero",
+		"-'a'":                       "cannot negate string",
+		"lower(1)":                   "lower: needs a string",
+		"number('x')":                "is not a number",
+		"filter('abc', x => x)":      "filter needs a list",
+		"[1, 2][0.5]":                "list index must be an integer",
+		"matches('a', '[')":          "invalid pattern",
+		"sum(['a'])":                 "needs numbers",
+		"count([1, 2], x => 1 / 0)":  "division by zero",
+		"sort([1, 'a'])":             "cannot compare",
+		"keys([1])":                  "needs an object",
+		"len(true)":                  "boolean has no length",
+		"round(1, 'a')":              "digits must be a number",
+		"missing.score > 0.5":        "cannot compare null",
+		"2 * 'x'":                    "needs numbers",
+		"contains(1, 'a')":           "cannot search number",
+		"'a' in 1":                   "cannot search number",
+		"starts_with('a', 1)":        "needs a string",
+		"min('a')":                   "needs a list",
+		"abs('a')":                   "needs a number",
+		"number([1])":                "cannot convert list",
+		"5 % 0":                      "modulo by zero",
+		"count('a')":                 "count needs a list",
+		"'a' in 'abc' && 1 in 'abc'": "cannot search a string",
+	}
+	for source, want := range tests {
+		expr, err := CompileExpression(source)
+		if err != nil {
+			t.Errorf("CompileExpression(%s): %v", source, err)
+			continue
+		}
+		if _, err := expr.Evaluate(nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", source, err, want)
+		}
+	}
+
+	// Evaluation is bounded
+	expr, _ := CompileExpression("map(xs, x => map(xs, y => map(xs, z => x + y + z)))")
+	xs := make([]interface{}, 100)
+	for i := range xs {
+		xs[i] = float64(i)
+	}
+	_, err := expr.Evaluate(func(string) (interface{}, bool) { return xs, true })
+	if err == nil || !strings.Contains(err.Error(), "exceeded") {
+		t.Errorf("err = %v, want the step budget exceeded", err)
+	}
+
+	// So are the strings and lists it builds
+	nested := "'a'"
+	for i := 0; i < 7; i++ {
+		nested = "replace(" + nested + ", 'a', 'aaaaaaaaaa')"
+	}
+	half := strings.Repeat("a", MaxExpressionStringBytes/2+1)
+	items := make([]interface{}, MaxExpressionListItems/2+1)
+	values := map[string]interface{}{"s": half, "xs": items, "big": []interface{}{half, half}}
+	lookup := func(name string) (interface{}, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+	sizeTests := map[string]string{
+		nested:                 "longer than",
+		"s + s":                "longer than",
+		"join(big, '')":        "longer than",
+		"string(big)":          "longer than",
+		"split(s, '')":         "more than",
+		"xs + xs":              "more than",
+		"map(big, x => x + x)": "longer than",
+	}
+	for source, want := range sizeTests {
+		expr, err := CompileExpression(source)
+		if err != nil {
+			t.Errorf("CompileExpression(%s): %v", source, err)
+			continue
+		}
+		if _, err := expr.Evaluate(lookup); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", source, err, want)
+		}
+	}
+}
+
+func TestCompileExpression_CacheIsBounded(t *testing.T) {
+	for i := 0; i < MaxCachedExpressions+10; i++ {
+		if _, err := CompileExpression(fmt.Sprintf("x + %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if size := expressionCache.Size(); size > MaxCachedExpressions {
+		t.Errorf("cache holds %d expressions, want at most %d", size, MaxCachedExpressions)
+	}
+}
+
+func TestCompileExpression_Errors(t *testing.T) {
+	tests := map[string]string{
+		"":               "empty",
+		"1 +":            "unexpected end of expression at position 3",
+		"a b":            `unexpected "b" at position 2`,
+		"(1":             `expected ")"`,
+		"'open":          "unterminated string at position 0",
+		"a # b":          "unexpected character '#' at position 2",
+		"nope(1)":        "unknown function nope at position 0",
+		"lower()":        "lower takes 1 argument",
+		"replace('a')":   "replace takes 3 arguments",
+		"round(1, 2, 3)": "round takes 1 to 2 arguments",
+		"filter(xs, 1)":  "expects a lambda",
+		"x => x":         "only allowed as an argument",
+		"a.":             `expected a field name after "."`,
+		"a ? b":          `expected ":"`,
+		strings.Repeat("1", MaxExpressionLength+1): "longer than",
+	}
+	for source, want := range tests {
+		if _, err := CompileExpression(source); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: err = %v, want %q", source, err, want)
+		}
+	}
+}
+
+func TestWorkflowExpressions(t *testing.T) {
+	executor := &scriptedExecutor{scripts: map[string][]interface{}{
+		"generate": {map[string]interface{}{"hypotheses": []interface{}{
+			map[string]interface{}{"name": "cache", "score": 0.9},
+			map[string]interface{}{"name": "db", "score": 0.3},
+		}}},
+	}}
+	generate := &WorkflowStep{
+		ID:      "generate",
+		Tool:    "generate",
+		StoreAs: "strong",
+		Transform: &OutputTransform{
+			Type:       "expression",
+			Expression: "filter(result.hypotheses, h => h.score >= (input.threshold ?? 0.5))",
+		},
+	}
+	investigate := &WorkflowStep{
+		ID:        "investigate",
+		Tool:      "investigate",
+		Condition: &StepCondition{Expression: "len(strong) > 0 && problem != ''"},
+		Input: types.Metadata{
+			"targets": "{{ join(map(strong, h => upper(h.name)), ', ') }}",
+			"depth":   "{{ depth ?? 2 }}",
+			"problem": "{{problem}}",
+		},
+	}
+	skipped := &WorkflowStep{ID: "escalate", Tool: "escalate", Condition: &StepCondition{Expression: "any(strong, h => h.name == 'db')"}}
+
+	result, err := runWorkflow(t, executor, generate, investigate, skipped)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+	if strong := result.StepResults["generate"].([]interface{}); len(strong) != 1 {
+		t.Errorf("transformed result = %v", strong)
+	}
+	call := executor.calls["investigate"][0]
+	if call["targets"] != "CACHE" || call["depth"] != 2.0 || call["problem"] != "p" {
+		t.Errorf("investigate input = %v", call)
+	}
+	if len(executor.calls["escalate"]) != 0 || result.Timeline[2].Status != "skipped" {
+		t.Errorf("timeline = %+v", result.Timeline)
+	}
+
+	// A transform that fails to evaluate fails the step
+	generate.Transform.Expression = "result.hypotheses > 1"
+	if _, err := runWorkflow(t, &scriptedExecutor{}, generate); err == nil || !strings.Contains(err.Error(), "transform failed") {
+		t.Errorf("err = %v, want the transform to fail", err)
+	}
+}
+
+func TestRegisterWorkflow_ExpressionValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		step *WorkflowStep
+		want string
+	}{
+		{"condition", &WorkflowStep{ID: "a", Condition: &StepCondition{Expression: "len(x) >"}}, "step a: condition"},
+		{"loop condition", &WorkflowStep{ID: "a", Loop: &StepLoop{MaxIterations: 2, Until: &StepCondition{Expression: "nope()"}}}, "unknown function nope"},
+		{"transform", &WorkflowStep{ID: "a", Transform: &OutputTransform{Type: "expression", Expression: "result."}}, "step a: transform"},
+		{"transform without expression", &WorkflowStep{ID: "a", Transform: &OutputTransform{Type: "expression"}}, "needs an expression"},
+		{"input template", &WorkflowStep{ID: "a", Input: types.Metadata{"items": []interface{}{"{{ filter(x) }}"}}}, "step a: input items"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewOrchestrator().RegisterWorkflow(&Workflow{ID: "w", Name: "w", Type: WorkflowSequential, Steps: []*WorkflowStep{tt.step}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// Plain references and loop expressions are accepted
+	valid := &WorkflowStep{ID: "a", Input: types.Metadata{"x": "{{graph.id}}", "y": "$problem", "z": "{{ confidence * 100 }}"},
+		Loop: &StepLoop{MaxIterations: 2, While: &StepCondition{Expression: "len(a_iterations) < 2"}}}
+	if err := NewOrchestrator().RegisterWorkflow(&Workflow{ID: "w", Name: "w", Type: WorkflowSequential, Steps: []*WorkflowStep{valid}}); err != nil {
+		t.Errorf("RegisterWorkflow: %v", err)
+	}
+}
+
+func TestLoopWithExpressions(t *testing.T) {
+	executor := &scriptedExecutor{scripts: map[string][]interface{}{
+		"refine": {scored(0.5), scored(0.7), scored(0.95)},
+	}}
+	refine := &WorkflowStep{
+		ID:   "refine",
+		Tool: "refine",
+		Loop: &StepLoop{
+			Until:         &StepCondition{Expression: "refine.confidence >= 0.9 || avg(map(refine_iterations, r => r.confidence)) > 0.8"},
+			MaxIterations: 5,
+		},
+	}
+	if _, err := runWorkflow(t, executor, refine); err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+	if len(executor.calls["refine"]) != 3 {
+		t.Errorf("refine ran %d times, want 3", len(executor.calls["refine"]))
+	}
+}
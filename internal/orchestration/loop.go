@@ -0,0 +1,303 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// Limits applied when a workflow is registered
+const (
+	MaxLoopIterations = 100 // Upper bound for StepLoop.MaxIterations
+	MaxRetryAttempts  = 10  // Upper bound for RetryPolicy.MaxAttempts
+
+	defaultRetryBackoff = 100 * time.Millisecond
+)
+
+// StepLoop repeats a step. While is checked before every iteration and Until
+// after it; the loop ends when either says so or after MaxIterations. Each
+// iteration can read {{loop.iteration}} (1-based), {{loop.index}} (0-based)
+// and {{loop.previous}} (the prior iteration's result). Results of all
+// iterations are stored as <store_as>_iterations, e.g.
+// {{refined_iterations.0.confidence}}.
+type StepLoop struct {
+	While         *StepCondition `json:"while,omitempty"`
+	Until         *StepCondition `json:"until,omitempty"`
+	MaxIterations int            `json:"max_iterations"`
+}
+
+// RetryPolicy retries a failed or timed out step execution with exponential
+// backoff. Each loop iteration is retried independently.
+type RetryPolicy struct {
+	MaxAttempts   int     `json:"max_attempts"`             // Attempts including the first
+	BackoffMs     int     `json:"backoff_ms,omitempty"`     // Wait before the first retry (default 100)
+	BackoffFactor float64 `json:"backoff_factor,omitempty"` // Growth of the wait per retry (default 2)
+	MaxBackoffMs  int     `json:"max_backoff_ms,omitempty"` // Upper bound for the wait
+}
+
+// stepRun counts what running a step took
+type stepRun struct {
+	iterations int
+	attempts   int
+}
+
+// validateStep checks a step's loop, retry and timeout settings
+func validateStep(step *WorkflowStep) error {
+	if step.TimeoutMs < 0 {
+		return fmt.Errorf("step %s: timeout_ms must not be negative", step.ID)
+	}
+	if loop := step.Loop; loop != nil {
+		if loop.MaxIterations < 1 || loop.MaxIterations > MaxLoopIterations {
+			return fmt.Errorf("step %s: loop max_iterations must be between 1 and %d", step.ID, MaxLoopIterations)
+		}
+		for _, condition := range []*StepCondition{loop.While, loop.Until} {
+			if condition != nil && condition.Field == "" && condition.Expression == "" {
+				return fmt.Errorf("step %s: loop condition needs a field or an expression", step.ID)
+			}
+		}
+	}
+	if retry := step.Retry; retry != nil {
+		if retry.MaxAttempts < 1 || retry.MaxAttempts > MaxRetryAttempts {
+			return fmt.Errorf("step %s: retry max_attempts must be between 1 and %d", step.ID, MaxRetryAttempts)
+		}
+		if retry.BackoffMs < 0 || retry.MaxBackoffMs < 0 || retry.BackoffFactor < 0 {
+			return fmt.Errorf("step %s: retry backoff must not be negative", step.ID)
+		}
+	}
+	return nil
+}
+
+// shouldRun reports whether a step's condition, and the while condition of a
+// loop step, allow it to start. Callers running steps concurrently must hold
+// the reasoning context lock.
+func (o *Orchestrator) shouldRun(step *WorkflowStep, ctx *ReasoningContext) bool {
+	if step.Condition != nil && !o.evaluateCondition(step.Condition, ctx) {
+		return false
+	}
+	if step.Loop != nil && step.Loop.While != nil && !o.evaluateCondition(step.Loop.While, ctx) {
+		return false
+	}
+	return true
+}
+
+// runStep executes a step with its loop and retry settings. A loop step's
+// result is the result of its last iteration.
+func (o *Orchestrator) runStep(ctx context.Context, step *WorkflowStep, input types.Metadata, reasoningCtx *ReasoningContext, mu *sync.Mutex) (interface{}, stepRun, error) {
+	if step.Loop == nil {
+		result, attempts, err := o.executeWithRetry(ctx, step, input, reasoningCtx, mu)
+		return result, stepRun{iterations: 1, attempts: attempts}, err
+	}
+
+	lock := func() {
+		if mu != nil {
+			mu.Lock()
+		}
+	}
+	unlock := func() {
+		if mu != nil {
+			mu.Unlock()
+		}
+	}
+	key := step.StoreAs
+	if key == "" {
+		key = step.ID
+	}
+
+	var run stepRun
+	var result interface{}
+	history := []interface{}{}
+	for run.iterations < step.Loop.MaxIterations {
+		// The while condition for the first iteration was checked by shouldRun
+		if run.iterations > 0 && step.Loop.While != nil {
+			lock()
+			proceed := o.evaluateCondition(step.Loop.While, reasoningCtx)
+			unlock()
+			if !proceed {
+				break
+			}
+		}
+
+		iterationInput := make(types.Metadata, len(input)+1)
+		for k, v := range input {
+			iterationInput[k] = v
+		}
+		iterationInput["loop"] = map[string]interface{}{
+			"iteration": run.iterations + 1,
+			"index":     run.iterations,
+			"previous":  result,
+		}
+
+		iterationResult, attempts, err := o.executeWithRetry(ctx, step, iterationInput, reasoningCtx, mu)
+		run.attempts += attempts
+		if err != nil {
+			return nil, run, fmt.Errorf("iteration %d: %w", run.iterations+1, err)
+		}
+		run.iterations++
+		result = iterationResult
+		history = append(history, iterationResult)
+
+		// Expose the iteration to the until condition and later iterations
+		lock()
+		reasoningCtx.Results[key] = iterationResult
+		reasoningCtx.Results[key+"_iterations"] = append([]interface{}(nil), history...)
+		done := step.Loop.Until != nil && o.evaluateCondition(step.Loop.Until, reasoningCtx)
+		unlock()
+		if done {
+			break
+		}
+	}
+	return result, run, nil
+}
+
+// executeWithRetry executes a step, retrying failures and timeouts according
+// to its retry policy. It returns the number of attempts made.
+func (o *Orchestrator) executeWithRetry(ctx context.Context, step *WorkflowStep, input types.Metadata, reasoningCtx *ReasoningContext, mu *sync.Mutex) (interface{}, int, error) {
+	maxAttempts := 1
+	backoff := defaultRetryBackoff
+	factor := 2.0
+	var maxBackoff time.Duration
+	if retry := step.Retry; retry != nil {
+		maxAttempts = retry.MaxAttempts
+		if retry.BackoffMs > 0 {
+			backoff = time.Duration(retry.BackoffMs) * time.Millisecond
+		}
+		if retry.BackoffFactor > 0 {
+			factor = retry.BackoffFactor
+		}
+		maxBackoff = time.Duration(retry.MaxBackoffMs) * time.Millisecond
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, attempt - 1, fmt.Errorf("%w (retry cancelled: %v)", lastErr, ctx.Err())
+			case <-timer.C:
+			}
+			backoff = time.Duration(float64(backoff) * factor)
+			if maxBackoff > 0 && backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+
+		result, err := o.executeAttempt(ctx, step, input, reasoningCtx, mu)
+		if err == nil {
+			return result, attempt, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, attempt, err
+		}
+	}
+	if maxAttempts > 1 {
+		return nil, maxAttempts, fmt.Errorf("%w (after %d attempts)", lastErr, maxAttempts)
+	}
+	return nil, maxAttempts, lastErr
+}
+
+// executeAttempt executes a step once, bounded by its timeout
+func (o *Orchestrator) executeAttempt(ctx context.Context, step *WorkflowStep, input types.Metadata, reasoningCtx *ReasoningContext, mu *sync.Mutex) (interface{}, error) {
+	if step.TimeoutMs <= 0 {
+		return o.executeStepWithLock(ctx, step, input, reasoningCtx, mu)
+	}
+	timeout := time.Duration(step.TimeoutMs) * time.Millisecond
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := o.executeStepWithLock(attemptCtx, step, input, reasoningCtx, mu)
+	if err != nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, fmt.Errorf("step %s timed out after %s: %w", step.ID, timeout, err)
+	}
+	return result, err
+}
+
+// conditionValue looks up a condition field: a step result key, a dotted
+// path into a result (e.g. "hypotheses.best.score"), or a reasoning context
+// field ("confidence", or the number of "thoughts", "causal_graphs",
+// "beliefs", "evidence" or "decisions")
+func conditionValue(field string, ctx *ReasoningContext) interface{} {
+	if value, exists := ctx.Results[field]; exists {
+		return value
+	}
+	if parts := splitReference(field); len(parts) > 1 {
+		if root, exists := ctx.Results[parts[0]]; exists {
+			return extractNestedValue(root, parts[1:])
+		}
+	}
+	switch field {
+	case "confidence":
+		return ctx.Confidence
+	case "thoughts":
+		return float64(len(ctx.Thoughts))
+	case "causal_graphs":
+		return float64(len(ctx.CausalGraphs))
+	case "beliefs":
+		return float64(len(ctx.Beliefs))
+	case "evidence":
+		return float64(len(ctx.Evidence))
+	case "decisions":
+		return float64(len(ctx.Decisions))
+	}
+	return nil
+}
+
+// compareValues applies a condition operator. Numbers compare numerically;
+// eq and ne fall back to deep equality.
+func compareValues(value interface{}, operator string, target interface{}) bool {
+	a, aNum := toFloat64(value)
+	b, bNum := toFloat64(target)
+	switch operator {
+	case "gt":
+		return aNum && bNum && a > b
+	case "gte":
+		return aNum && bNum && a >= b
+	case "lt":
+		return aNum && bNum && a < b
+	case "lte":
+		return aNum && bNum && a <= b
+	case "eq":
+		return (aNum && bNum && a == b) || reflect.DeepEqual(value, target)
+	case "ne":
+		return !((aNum && bNum && a == b) || reflect.DeepEqual(value, target))
+	case "contains":
+		switch v := value.(type) {
+		case string:
+			search, _ := target.(string)
+			return search != "" && strings.Contains(v, search)
+		case []interface{}:
+			for _, item := range v {
+				if reflect.DeepEqual(item, target) {
+					return true
+				}
+			}
+		}
+	case "exists":
+		return value != nil
+	}
+	return false
+}
+
+// toFloat64 converts a numeric value to float64
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	}
+	return 0, false
+}
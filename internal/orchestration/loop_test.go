@@ -0,0 +1,216 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// scriptedExecutor answers each call to a tool with the next scripted
+// result; errors in the script are returned as failures
+type scriptedExecutor struct {
+	mu      sync.Mutex
+	scripts map[string][]interface{}
+	calls   map[string][]map[string]interface{}
+}
+
+func (e *scriptedExecutor) ExecuteTool(ctx context.Context, toolName string, input map[string]interface{}) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.calls == nil {
+		e.calls = make(map[string][]map[string]interface{})
+	}
+	call := len(e.calls[toolName])
+	e.calls[toolName] = append(e.calls[toolName], input)
+
+	script := e.scripts[toolName]
+	if len(script) == 0 {
+		return map[string]interface{}{"id": toolName}, nil
+	}
+	if call >= len(script) {
+		call = len(script) - 1
+	}
+	if err, ok := script[call].(error); ok {
+		return nil, err
+	}
+	return script[call], nil
+}
+
+func scored(confidence float64) map[string]interface{} {
+	return map[string]interface{}{"confidence": confidence}
+}
+
+func runWorkflow(t *testing.T, executor ToolExecutor, steps ...*WorkflowStep) (*WorkflowResult, error) {
+	t.Helper()
+	orch := NewOrchestratorWithExecutor(executor)
+	if err := orch.RegisterWorkflow(&Workflow{ID: "loop", Name: "loop", Type: WorkflowSequential, Steps: steps}); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+	return orch.ExecuteWorkflow(context.Background(), "loop", types.Metadata{"problem": "p"})
+}
+
+func TestLoopUntil(t *testing.T) {
+	executor := &scriptedExecutor{scripts: map[string][]interface{}{
+		"refine": {scored(0.5), scored(0.7), scored(0.9), scored(0.95)},
+	}}
+	refine := &WorkflowStep{
+		ID:      "refine",
+		Tool:    "refine",
+		Input:   types.Metadata{"attempt": "{{loop.iteration}}", "previous": "{{loop.previous.confidence}}"},
+		StoreAs: "refined",
+		Loop: &StepLoop{
+			Until:         &StepCondition{Field: "refined.confidence", Operator: "gte", Value: 0.8},
+			MaxIterations: 4,
+		},
+	}
+	report := &WorkflowStep{ID: "report", Tool: "report", Input: types.Metadata{"first": "{{refined_iterations.0.confidence}}"}}
+
+	result, err := runWorkflow(t, executor, refine, report)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+
+	calls := executor.calls["refine"]
+	if len(calls) != 3 {
+		t.Fatalf("refine ran %d times, want 3", len(calls))
+	}
+	if calls[2]["attempt"] != 3 || calls[2]["previous"] != 0.7 {
+		t.Errorf("third iteration input = %v", calls[2])
+	}
+	if got := executor.calls["report"][0]["first"]; got != 0.5 {
+		t.Errorf("first iteration confidence = %v", got)
+	}
+	if got := result.StepResults["refine"]; fmt.Sprint(got) != fmt.Sprint(scored(0.9)) {
+		t.Errorf("loop result = %v, want the last iteration", got)
+	}
+	if history := result.Context.Results["refined_iterations"].([]interface{}); len(history) != 3 {
+		t.Errorf("history = %v", history)
+	}
+	if timing := result.Timeline[0]; timing.Iterations != 3 || timing.Attempts != 3 {
+		t.Errorf("timeline = %+v", timing)
+	}
+}
+
+func TestLoopWhile(t *testing.T) {
+	executor := &scriptedExecutor{}
+	generate := &WorkflowStep{
+		ID:   "generate",
+		Tool: "generate",
+		Loop: &StepLoop{
+			While:         &StepCondition{Field: "generate_iterations.1", Operator: "exists", Value: nil},
+			MaxIterations: 5,
+		},
+	}
+	// The while condition starts out false, so the step is skipped
+	result, err := runWorkflow(t, executor, generate)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+	if len(executor.calls["generate"]) != 0 || result.Timeline[0].Status != "skipped" {
+		t.Errorf("timeline = %+v", result.Timeline)
+	}
+
+	// Without conditions the loop runs to its cap
+	executor = &scriptedExecutor{}
+	generate.Loop = &StepLoop{MaxIterations: 3}
+	if _, err := runWorkflow(t, executor, generate); err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+	if len(executor.calls["generate"]) != 3 {
+		t.Errorf("generate ran %d times, want 3", len(executor.calls["generate"]))
+	}
+}
+
+func TestStepRetry(t *testing.T) {
+	executor := &scriptedExecutor{scripts: map[string][]interface{}{
+		"flaky": {fmt.Errorf("unavailable"), fmt.Errorf("unavailable"), scored(0.8)},
+	}}
+	flaky := &WorkflowStep{ID: "flaky", Tool: "flaky", Retry: &RetryPolicy{MaxAttempts: 3, BackoffMs: 1}}
+
+	result, err := runWorkflow(t, executor, flaky)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+	if timing := result.Timeline[0]; timing.Status != "success" || timing.Attempts != 3 {
+		t.Errorf("timeline = %+v", timing)
+	}
+
+	executor = &scriptedExecutor{scripts: map[string][]interface{}{"flaky": {fmt.Errorf("unavailable")}}}
+	flaky.Retry.MaxAttempts = 2
+	if _, err := runWorkflow(t, executor, flaky); err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("err = %v, want retries exhausted", err)
+	}
+}
+
+// blockingExecutor waits for its context to end
+type blockingExecutor struct{}
+
+func (blockingExecutor) ExecuteTool(ctx context.Context, toolName string, input map[string]interface{}) (interface{}, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestStepTimeout(t *testing.T) {
+	step := &WorkflowStep{ID: "slow", Tool: "slow", TimeoutMs: 10, Retry: &RetryPolicy{MaxAttempts: 2, BackoffMs: 1}}
+	start := time.Now()
+	result, err := runWorkflow(t, blockingExecutor{}, step)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 10ms") {
+		t.Fatalf("err = %v, want a timeout", err)
+	}
+	if result.Timeline[0].Attempts != 2 || time.Since(start) > time.Second {
+		t.Errorf("timeline = %+v after %s", result.Timeline, time.Since(start))
+	}
+}
+
+func TestRegisterWorkflow_LoopValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		step *WorkflowStep
+		want string
+	}{
+		{"no iteration cap", &WorkflowStep{ID: "a", Loop: &StepLoop{}}, "max_iterations"},
+		{"iteration cap too high", &WorkflowStep{ID: "a", Loop: &StepLoop{MaxIterations: MaxLoopIterations + 1}}, "max_iterations"},
+		{"condition without field", &WorkflowStep{ID: "a", Loop: &StepLoop{MaxIterations: 2, Until: &StepCondition{Operator: "gt"}}}, "needs a field"},
+		{"no attempts", &WorkflowStep{ID: "a", Retry: &RetryPolicy{}}, "max_attempts"},
+		{"negative backoff", &WorkflowStep{ID: "a", Retry: &RetryPolicy{MaxAttempts: 2, BackoffMs: -1}}, "backoff"},
+		{"negative timeout", &WorkflowStep{ID: "a", TimeoutMs: -1}, "timeout_ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewOrchestrator().RegisterWorkflow(&Workflow{ID: "w", Name: "w", Type: WorkflowSequential, Steps: []*WorkflowStep{tt.step}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCondition_Fields(t *testing.T) {
+	o := NewOrchestrator()
+	ctx := &ReasoningContext{
+		Results:    types.Metadata{"hypotheses": map[string]interface{}{"best": map[string]interface{}{"score": 7}}},
+		Thoughts:   []string{"t1", "t2"},
+		Confidence: 0.8,
+	}
+	tests := []struct {
+		condition StepCondition
+		want      bool
+	}{
+		{StepCondition{Field: "confidence", Operator: "gte", Value: 0.8}, true},
+		{StepCondition{Field: "confidence", Operator: "lt", Value: 0.8}, false},
+		{StepCondition{Field: "hypotheses.best.score", Operator: "gt", Value: 5}, true},
+		{StepCondition{Field: "thoughts", Operator: "eq", Value: 2}, true},
+		{StepCondition{Field: "hypotheses.worst", Operator: "exists"}, false},
+		{StepCondition{Field: "hypotheses", Operator: "ne", Value: "x"}, true},
+	}
+	for _, tt := range tests {
+		if got := o.evaluateCondition(&tt.condition, ctx); got != tt.want {
+			t.Errorf("%+v = %v, want %v", tt.condition, got, tt.want)
+		}
+	}
+}
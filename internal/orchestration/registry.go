@@ -0,0 +1,420 @@
+package orchestration
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"unified-thinking/internal/types"
+)
+
+// WorkflowStore persists workflow definitions with their version history
+type WorkflowStore interface {
+	StoreWorkflowVersion(record *types.WorkflowVersionRecord) error
+	GetWorkflowVersion(workflowID string, version int) (*types.WorkflowVersionRecord, error)
+	ListWorkflowVersions(workflowID string) ([]*types.WorkflowVersionRecord, error)
+	ListLatestWorkflowVersions() ([]*types.WorkflowVersionRecord, error)
+	DeleteWorkflowVersions(workflowID string, version int) (int, error)
+}
+
+// Workflow sources recorded with each stored version
+const (
+	SourceTool       = "tool"
+	sourceFilePrefix = "file:"
+)
+
+var errNoWorkflowStore = fmt.Errorf("workflow versions are not persisted (SQLite storage is required)")
+
+// SetWorkflowStore enables persistence of registered workflows. The latest
+// version of every stored workflow is registered, replacing workflows of the
+// same ID registered earlier (e.g. predefined ones). Stored definitions that
+// no longer validate are skipped.
+func (o *Orchestrator) SetWorkflowStore(store WorkflowStore) error {
+	records, err := store.ListLatestWorkflowVersions()
+	if err != nil {
+		return fmt.Errorf("failed to load stored workflows: %w", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.store = store
+	for _, record := range records {
+		workflow, err := decodeWorkflowVersion(record)
+		if err == nil {
+			err = validateWorkflow(workflow)
+		}
+		if err != nil {
+			log.Printf("skipping stored workflow %s v%d: %v", record.WorkflowID, record.Version, err)
+			continue
+		}
+		o.workflows[workflow.ID] = workflow
+	}
+	return nil
+}
+
+// UpdateWorkflow registers a workflow, replacing any workflow with the same
+// ID. With a workflow store the definition is stored as a new version unless
+// it is unchanged from the latest one.
+func (o *Orchestrator) UpdateWorkflow(workflow *Workflow) error {
+	return o.saveWorkflow(workflow, SourceTool, true)
+}
+
+// saveWorkflow validates, persists and registers a workflow
+func (o *Orchestrator) saveWorkflow(workflow *Workflow, source string, replace bool) error {
+	if err := validateWorkflow(workflow); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.workflows[workflow.ID]; exists && !replace {
+		return fmt.Errorf("workflow %s already exists", workflow.ID)
+	}
+
+	workflow.CreatedAt = time.Now()
+	if o.store != nil {
+		record, err := newWorkflowVersion(workflow, source)
+		if err != nil {
+			return err
+		}
+		if err := o.store.StoreWorkflowVersion(record); err != nil {
+			return fmt.Errorf("failed to store workflow %s: %w", workflow.ID, err)
+		}
+		workflow.Version = record.Version
+		workflow.CreatedAt = record.CreatedAt
+	}
+	o.workflows[workflow.ID] = workflow
+	return nil
+}
+
+// WorkflowVersions lists the stored versions of a workflow, oldest first
+func (o *Orchestrator) WorkflowVersions(workflowID string) ([]*types.WorkflowVersionRecord, error) {
+	store := o.workflowStore()
+	if store == nil {
+		return nil, errNoWorkflowStore
+	}
+	versions, err := store.ListWorkflowVersions(workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("workflow %s has no stored versions", workflowID)
+	}
+	return versions, nil
+}
+
+// GetWorkflowVersion loads a stored version of a workflow; version 0 loads
+// the latest
+func (o *Orchestrator) GetWorkflowVersion(workflowID string, version int) (*Workflow, error) {
+	store := o.workflowStore()
+	if store == nil {
+		return nil, errNoWorkflowStore
+	}
+	record, err := store.GetWorkflowVersion(workflowID, version)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWorkflowVersion(record)
+}
+
+// DeleteWorkflow deletes one stored version of a workflow, or the whole
+// workflow when version is 0, and returns the number of stored versions
+// removed. Deleting the current version falls back to the previous one.
+func (o *Orchestrator) DeleteWorkflow(workflowID string, version int) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	current, registered := o.workflows[workflowID]
+	deleted := 0
+	if o.store != nil {
+		n, err := o.store.DeleteWorkflowVersions(workflowID, version)
+		if err != nil {
+			return 0, err
+		}
+		deleted = n
+	}
+
+	if version == 0 {
+		if !registered && deleted == 0 {
+			return 0, fmt.Errorf("workflow %s not found", workflowID)
+		}
+		delete(o.workflows, workflowID)
+		return deleted, nil
+	}
+
+	if deleted == 0 {
+		return 0, fmt.Errorf("workflow version not found: %s v%d", workflowID, version)
+	}
+	if registered && current.Version == version {
+		record, err := o.store.GetWorkflowVersion(workflowID, 0)
+		if err != nil {
+			// That was the only version
+			delete(o.workflows, workflowID)
+			return deleted, nil
+		}
+		previous, err := decodeWorkflowVersion(record)
+		if err != nil {
+			return deleted, err
+		}
+		o.workflows[workflowID] = previous
+	}
+	return deleted, nil
+}
+
+// workflowStore returns the workflow store, if any
+func (o *Orchestrator) workflowStore() WorkflowStore {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.store
+}
+
+// LoadWorkflowDir registers the workflow definitions (*.json, *.yaml, *.yml)
+// in a directory, replacing registered workflows of the same ID. Each file
+// holds one workflow in the register-workflow format. Definitions that
+// changed since they were last stored become new versions.
+func (o *Orchestrator) LoadWorkflowDir(dir string) ([]*Workflow, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow directory: %w", err)
+	}
+
+	var loaded []*Workflow
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return loaded, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		workflow, err := ParseWorkflowDefinition(data, ext)
+		if err != nil {
+			return loaded, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if err := o.saveWorkflow(workflow, sourceFilePrefix+entry.Name(), true); err != nil {
+			return loaded, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		loaded = append(loaded, workflow)
+	}
+	return loaded, nil
+}
+
+// ParseWorkflowDefinition decodes a JSON or YAML (".yaml", ".yml") workflow
+// definition. Unknown fields are rejected so that typos do not go unnoticed.
+func ParseWorkflowDefinition(data []byte, ext string) (*Workflow, error) {
+	if ext == ".yaml" || ext == ".yml" {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		data = converted
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var workflow Workflow
+	if err := decoder.Decode(&workflow); err != nil {
+		return nil, fmt.Errorf("invalid workflow definition: %w", err)
+	}
+	return &workflow, nil
+}
+
+// newWorkflowVersion encodes a workflow for the workflow store
+func newWorkflowVersion(workflow *Workflow, source string) (*types.WorkflowVersionRecord, error) {
+	definition := *workflow
+	definition.Version = 0
+	definition.CreatedAt = time.Time{}
+	definition.Context = nil
+	data, err := json.Marshal(&definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode workflow %s: %w", workflow.ID, err)
+	}
+	sum := sha256.Sum256(data)
+	return &types.WorkflowVersionRecord{
+		WorkflowID: workflow.ID,
+		Name:       workflow.Name,
+		Source:     source,
+		Checksum:   hex.EncodeToString(sum[:]),
+		Definition: data,
+	}, nil
+}
+
+// decodeWorkflowVersion decodes a stored workflow version
+func decodeWorkflowVersion(record *types.WorkflowVersionRecord) (*Workflow, error) {
+	var workflow Workflow
+	if err := json.Unmarshal(record.Definition, &workflow); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow %s v%d: %w", record.WorkflowID, record.Version, err)
+	}
+	workflow.Version = record.Version
+	workflow.CreatedAt = record.CreatedAt
+	return &workflow, nil
+}
+
+// WorkflowDiff describes the changes between two versions of a workflow
+type WorkflowDiff struct {
+	WorkflowID     string        `json:"workflow_id"`
+	FromVersion    int           `json:"from_version"`
+	ToVersion      int           `json:"to_version"`
+	FieldsChanged  []FieldChange `json:"fields_changed"` // Workflow-level fields
+	StepsAdded     []string      `json:"steps_added"`
+	StepsRemoved   []string      `json:"steps_removed"`
+	StepsModified  []StepChange  `json:"steps_modified"`
+	StepsReordered bool          `json:"steps_reordered"`
+	HasChanges     bool          `json:"has_changes"`
+}
+
+// FieldChange is a field whose value differs between versions
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+// StepChange lists the changed fields of a step present in both versions
+type StepChange struct {
+	StepID string        `json:"step_id"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// DiffWorkflowVersions compares two stored versions of a workflow. A
+// toVersion of 0 means the latest version, and a fromVersion of 0 the
+// version before toVersion.
+func (o *Orchestrator) DiffWorkflowVersions(workflowID string, fromVersion, toVersion int) (*WorkflowDiff, error) {
+	to, err := o.GetWorkflowVersion(workflowID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	if fromVersion == 0 {
+		fromVersion = to.Version - 1
+		if fromVersion < 1 {
+			return nil, fmt.Errorf("workflow %s has no version before v%d", workflowID, to.Version)
+		}
+	}
+	from, err := o.GetWorkflowVersion(workflowID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return diffWorkflows(from, to)
+}
+
+// diffWorkflows compares two workflow definitions field by field, matching
+// steps by ID
+func diffWorkflows(from, to *Workflow) (*WorkflowDiff, error) {
+	diff := &WorkflowDiff{
+		WorkflowID:    to.ID,
+		FromVersion:   from.Version,
+		ToVersion:     to.Version,
+		FieldsChanged: []FieldChange{},
+		StepsAdded:    []string{},
+		StepsRemoved:  []string{},
+		StepsModified: []StepChange{},
+	}
+
+	fromFields, err := genericFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := genericFields(to)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range []string{"steps", "version", "created_at", "context"} {
+		delete(fromFields, field)
+		delete(toFields, field)
+	}
+	diff.FieldsChanged = diffFields(fromFields, toFields)
+
+	fromSteps := make(map[string]*WorkflowStep, len(from.Steps))
+	var fromOrder []string
+	for _, step := range from.Steps {
+		fromSteps[step.ID] = step
+	}
+	toSteps := make(map[string]bool, len(to.Steps))
+	var toOrder []string
+	for _, step := range to.Steps {
+		toSteps[step.ID] = true
+		previous, existed := fromSteps[step.ID]
+		if !existed {
+			diff.StepsAdded = append(diff.StepsAdded, step.ID)
+			continue
+		}
+		toOrder = append(toOrder, step.ID)
+		previousFields, err := genericFields(previous)
+		if err != nil {
+			return nil, err
+		}
+		stepFields, err := genericFields(step)
+		if err != nil {
+			return nil, err
+		}
+		if changes := diffFields(previousFields, stepFields); len(changes) > 0 {
+			diff.StepsModified = append(diff.StepsModified, StepChange{StepID: step.ID, Fields: changes})
+		}
+	}
+	for _, step := range from.Steps {
+		if toSteps[step.ID] {
+			fromOrder = append(fromOrder, step.ID)
+		} else {
+			diff.StepsRemoved = append(diff.StepsRemoved, step.ID)
+		}
+	}
+	diff.StepsReordered = !reflect.DeepEqual(fromOrder, toOrder)
+
+	diff.HasChanges = len(diff.FieldsChanged)+len(diff.StepsAdded)+len(diff.StepsRemoved)+len(diff.StepsModified) > 0 ||
+		diff.StepsReordered
+	return diff, nil
+}
+
+// genericFields converts a value to its JSON object form
+func genericFields(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// diffFields lists the keys whose values differ, in key order
+func diffFields(from, to map[string]interface{}) []FieldChange {
+	keys := make(map[string]bool, len(from)+len(to))
+	for key := range from {
+		keys[key] = true
+	}
+	for key := range to {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	changes := []FieldChange{}
+	for _, key := range sorted {
+		if !reflect.DeepEqual(from[key], to[key]) {
+			changes = append(changes, FieldChange{Field: key, From: from[key], To: to[key]})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,211 @@
+package orchestration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"unified-thinking/internal/storage"
+	"unified-thinking/internal/types"
+)
+
+func newWorkflowStore(t *testing.T) (*storage.SQLiteStorage, string) {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "workflows.db")
+	store, err := storage.NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store, dbPath
+}
+
+func reviewWorkflow(steps ...*WorkflowStep) *Workflow {
+	return &Workflow{ID: "review", Name: "Review", Type: WorkflowSequential, Steps: steps}
+}
+
+func TestWorkflowRegistry_Versions(t *testing.T) {
+	store, dbPath := newWorkflowStore(t)
+	orch := NewOrchestrator()
+	if err := orch.SetWorkflowStore(store); err != nil {
+		t.Fatalf("SetWorkflowStore: %v", err)
+	}
+
+	think := &WorkflowStep{ID: "think", Tool: "think", Input: types.Metadata{"content": "{{problem}}"}}
+	if err := orch.RegisterWorkflow(reviewWorkflow(think)); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+	if err := orch.RegisterWorkflow(reviewWorkflow(think)); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+
+	// Updates create versions unless the definition is unchanged
+	validate := &WorkflowStep{ID: "validate", Tool: "validate", Input: types.Metadata{}}
+	for _, workflow := range []*Workflow{reviewWorkflow(think, validate), reviewWorkflow(think, validate)} {
+		if err := orch.UpdateWorkflow(workflow); err != nil {
+			t.Fatalf("UpdateWorkflow: %v", err)
+		}
+	}
+	current, _ := orch.GetWorkflow("review")
+	if current.Version != 2 {
+		t.Errorf("current version = %d, want 2", current.Version)
+	}
+
+	// Stored workflows survive a restart and replace predefined ones
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	reopened, err := storage.NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+	restarted := NewOrchestrator()
+	if err := restarted.RegisterWorkflow(reviewWorkflow(think)); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+	if err := restarted.SetWorkflowStore(reopened); err != nil {
+		t.Fatalf("SetWorkflowStore: %v", err)
+	}
+	loaded, err := restarted.GetWorkflow("review")
+	if err != nil || loaded.Version != 2 || len(loaded.Steps) != 2 {
+		t.Fatalf("loaded = %+v, err %v", loaded, err)
+	}
+
+	versions, err := restarted.WorkflowVersions("review")
+	if err != nil || len(versions) != 2 || versions[0].Source != SourceTool {
+		t.Errorf("versions = %+v, err %v", versions, err)
+	}
+
+	diff, err := restarted.DiffWorkflowVersions("review", 0, 0)
+	if err != nil {
+		t.Fatalf("DiffWorkflowVersions: %v", err)
+	}
+	if diff.FromVersion != 1 || diff.ToVersion != 2 || !diff.HasChanges ||
+		len(diff.StepsAdded) != 1 || diff.StepsAdded[0] != "validate" || len(diff.StepsModified) != 0 {
+		t.Errorf("diff = %+v", diff)
+	}
+
+	// Deleting the current version falls back to the previous one
+	if n, err := restarted.DeleteWorkflow("review", 2); err != nil || n != 1 {
+		t.Fatalf("DeleteWorkflow(v2) = %d, %v", n, err)
+	}
+	if current, _ := restarted.GetWorkflow("review"); current.Version != 1 || len(current.Steps) != 1 {
+		t.Errorf("current after delete = %+v", current)
+	}
+	if n, err := restarted.DeleteWorkflow("review", 0); err != nil || n != 1 {
+		t.Fatalf("DeleteWorkflow(all) = %d, %v", n, err)
+	}
+	if _, err := restarted.GetWorkflow("review"); err == nil {
+		t.Error("expected deleted workflow to be unregistered")
+	}
+	if _, err := restarted.DeleteWorkflow("review", 0); err == nil {
+		t.Error("expected error deleting a missing workflow")
+	}
+}
+
+func TestWorkflowRegistry_WithoutStore(t *testing.T) {
+	orch := NewOrchestrator()
+	if err := orch.RegisterWorkflow(reviewWorkflow()); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+	if _, err := orch.WorkflowVersions("review"); err == nil || !strings.Contains(err.Error(), "not persisted") {
+		t.Errorf("err = %v, want versions unavailable", err)
+	}
+	if n, err := orch.DeleteWorkflow("review", 0); err != nil || n != 0 {
+		t.Errorf("DeleteWorkflow = %d, %v", n, err)
+	}
+	if len(orch.ListWorkflows()) != 0 {
+		t.Error("expected workflow to be removed")
+	}
+}
+
+func TestDiffWorkflows(t *testing.T) {
+	from := &Workflow{ID: "w", Name: "W", Type: WorkflowSequential, Version: 1, Steps: []*WorkflowStep{
+		{ID: "a", Tool: "think"}, {ID: "b", Tool: "validate"}, {ID: "c", Tool: "prove"},
+	}}
+	to := &Workflow{ID: "w", Name: "W2", Type: WorkflowSequential, Version: 2, Steps: []*WorkflowStep{
+		{ID: "b", Tool: "validate", Retry: &RetryPolicy{MaxAttempts: 2}}, {ID: "a", Tool: "think"}, {ID: "d", Tool: "decide"},
+	}}
+
+	diff, err := diffWorkflows(from, to)
+	if err != nil {
+		t.Fatalf("diffWorkflows: %v", err)
+	}
+	if len(diff.FieldsChanged) != 1 || diff.FieldsChanged[0].Field != "name" || diff.FieldsChanged[0].To != "W2" {
+		t.Errorf("fields = %+v", diff.FieldsChanged)
+	}
+	if strings.Join(diff.StepsAdded, ",") != "d" || strings.Join(diff.StepsRemoved, ",") != "c" || !diff.StepsReordered {
+		t.Errorf("diff = %+v", diff)
+	}
+	if len(diff.StepsModified) != 1 || diff.StepsModified[0].StepID != "b" || diff.StepsModified[0].Fields[0].Field != "retry" {
+		t.Errorf("modified = %+v", diff.StepsModified)
+	}
+
+	if same, _ := diffWorkflows(from, from); same.HasChanges {
+		t.Errorf("identical workflows differ: %+v", same)
+	}
+}
+
+func TestLoadWorkflowDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"refine.yaml": `
+id: refine
+name: Iterative refinement
+type: sequential
+steps:
+  - id: evaluate
+    tool: self-evaluate
+    input:
+      content: "{{problem}}"
+    store_as: evaluation
+    loop:
+      until: {field: evaluation.confidence, operator: gte, value: 0.8}
+      max_iterations: 3
+`,
+		"triage.json": `{"id": "triage", "name": "Triage", "type": "dag", "steps": [
+			{"id": "a", "tool": "think", "input": {}},
+			{"id": "b", "tool": "validate", "input": {}, "depends_on": ["a"]}
+		]}`,
+		"README.md": "not a workflow",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, _ := newWorkflowStore(t)
+	orch := NewOrchestrator()
+	if err := orch.SetWorkflowStore(store); err != nil {
+		t.Fatalf("SetWorkflowStore: %v", err)
+	}
+	loaded, err := orch.LoadWorkflowDir(dir)
+	if err != nil || len(loaded) != 2 {
+		t.Fatalf("LoadWorkflowDir = %v, %v", loaded, err)
+	}
+	refine, err := orch.GetWorkflow("refine")
+	if err != nil || refine.Steps[0].Loop == nil || refine.Steps[0].Loop.MaxIterations != 3 || refine.Version != 1 {
+		t.Fatalf("refine = %+v, err %v", refine, err)
+	}
+
+	// Reloading unchanged files keeps their versions
+	if _, err := orch.LoadWorkflowDir(dir); err != nil {
+		t.Fatalf("LoadWorkflowDir: %v", err)
+	}
+	versions, _ := orch.WorkflowVersions("triage")
+	if len(versions) != 1 || versions[0].Source != "file:triage.json" {
+		t.Errorf("versions = %+v", versions)
+	}
+
+	// Invalid definitions name the file
+	bad := filepath.Join(dir, "zz-bad.json")
+	if err := os.WriteFile(bad, []byte(`{"id": "bad", "name": "Bad", "type": "dag", "stpes": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orch.LoadWorkflowDir(dir); err == nil || !strings.Contains(err.Error(), "zz-bad.json") {
+		t.Errorf("err = %v, want the invalid file named", err)
+	}
+}
@@ -0,0 +1,308 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// RunStore persists the history of workflow runs. Runs are partitioned by
+// workspace; a record is stored in its own Workspace.
+type RunStore interface {
+	StoreWorkflowRun(record *types.WorkflowRunRecord) error
+	GetWorkflowRun(workspace, id string) (*types.WorkflowRunRecord, error)
+	ListWorkflowRuns(workspace, workflowID, status string, limit, offset int) ([]*types.WorkflowRunSummary, error)
+}
+
+// workspaceKey is the context key for the workspace runs are recorded in
+type workspaceKey struct{}
+
+// WithWorkspace returns a context whose workflow runs are recorded in, and
+// resumed or re-run from, the given workspace
+func WithWorkspace(ctx context.Context, workspace string) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, workspace)
+}
+
+// workspaceFrom returns the workspace set by WithWorkspace, or ""
+func workspaceFrom(ctx context.Context) string {
+	workspace, _ := ctx.Value(workspaceKey{}).(string)
+	return workspace
+}
+
+// How a run was started
+const (
+	RunTriggerExecute = "execute" // ExecuteWorkflow
+	RunTriggerResume  = "resume"  // ResumeRun
+	RunTriggerRerun   = "rerun"   // RerunWorkflow
+)
+
+// RunStatusRunning is the status of a run that has not finished. A run left
+// running by a process that exited stays in this status.
+const RunStatusRunning = "running"
+
+// MaxMemoryRuns is the number of runs kept when no RunStore is set
+const MaxMemoryRuns = 100
+
+// WorkflowRun is the record of one workflow execution: its input and, once
+// finished, its result with step outputs, reasoning context and timeline
+type WorkflowRun struct {
+	ID              string          `json:"id"`
+	Workspace       string          `json:"workspace,omitempty"`
+	WorkflowID      string          `json:"workflow_id"`
+	WorkflowVersion int             `json:"workflow_version,omitempty"`
+	Trigger         string          `json:"trigger"`
+	ParentRunID     string          `json:"parent_run_id,omitempty"` // Run resumed or re-run
+	Status          string          `json:"status"`                  // "running", "success", "partial", "failed"
+	Input           types.Metadata  `json:"input"`
+	Result          *WorkflowResult `json:"result,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	StartedAt       time.Time       `json:"started_at"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+}
+
+var runCounter uint64
+
+// SetRunStore replaces the in-memory run history with a persistent store
+func (o *Orchestrator) SetRunStore(store RunStore) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.runs = store
+}
+
+// runStore returns the run store
+func (o *Orchestrator) runStore() RunStore {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.runs
+}
+
+// GetRun loads a run recorded in workspace
+func (o *Orchestrator) GetRun(workspace, runID string) (*WorkflowRun, error) {
+	record, err := o.runStore().GetWorkflowRun(workspace, runID)
+	if err != nil {
+		return nil, err
+	}
+	var run WorkflowRun
+	if err := json.Unmarshal(record.Run, &run); err != nil {
+		return nil, fmt.Errorf("failed to decode run %s: %w", runID, err)
+	}
+	return &run, nil
+}
+
+// ListRuns lists the runs recorded in workspace, most recent first. Empty
+// workflowID or status match every run.
+func (o *Orchestrator) ListRuns(workspace, workflowID, status string, limit, offset int) ([]*types.WorkflowRunSummary, error) {
+	return o.runStore().ListWorkflowRuns(workspace, workflowID, status, limit, offset)
+}
+
+// ResumeRun continues a failed or partial run from the steps that did not
+// succeed. Outputs of the steps that succeeded and the reasoning context are
+// restored from the run, so those steps are not executed again. Overrides
+// replace keys of the run's input. The resumed run is recorded as a new run.
+// The run is looked up in the workspace of ctx, see WithWorkspace.
+func (o *Orchestrator) ResumeRun(ctx context.Context, runID string, overrides types.Metadata) (*WorkflowResult, error) {
+	previous, err := o.GetRun(workspaceFrom(ctx), runID)
+	if err != nil {
+		return nil, err
+	}
+	if previous.Status != "failed" && previous.Status != "partial" {
+		return nil, fmt.Errorf("run %s is %s; only failed or partial runs can be resumed", runID, previous.Status)
+	}
+	if previous.Result == nil {
+		return nil, fmt.Errorf("run %s has no recorded results", runID)
+	}
+	workflow, err := o.workflowForRun(previous)
+	if err != nil {
+		return nil, err
+	}
+	run := &WorkflowRun{Workspace: previous.Workspace, Trigger: RunTriggerResume, ParentRunID: runID, Input: mergeInput(previous.Input, overrides)}
+	return o.executeRun(ctx, workflow, run, previous.Result)
+}
+
+// RerunWorkflow executes the workflow of a recorded run again from the start
+// with the run's input, with overrides replacing keys of that input. The run
+// is looked up in the workspace of ctx.
+func (o *Orchestrator) RerunWorkflow(ctx context.Context, runID string, overrides types.Metadata) (*WorkflowResult, error) {
+	previous, err := o.GetRun(workspaceFrom(ctx), runID)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := o.workflowForRun(previous)
+	if err != nil {
+		return nil, err
+	}
+	run := &WorkflowRun{Workspace: previous.Workspace, Trigger: RunTriggerRerun, ParentRunID: runID, Input: mergeInput(previous.Input, overrides)}
+	return o.executeRun(ctx, workflow, run, nil)
+}
+
+// workflowForRun returns the workflow version a run executed, falling back
+// to the registered workflow when that version is not stored
+func (o *Orchestrator) workflowForRun(run *WorkflowRun) (*Workflow, error) {
+	current, err := o.GetWorkflow(run.WorkflowID)
+	if err == nil && current.Version == run.WorkflowVersion {
+		return current, nil
+	}
+	if run.WorkflowVersion > 0 && o.workflowStore() != nil {
+		if workflow, versionErr := o.GetWorkflowVersion(run.WorkflowID, run.WorkflowVersion); versionErr == nil {
+			return workflow, nil
+		}
+	}
+	return current, err
+}
+
+// mergeInput copies input and applies overrides
+func mergeInput(input, overrides types.Metadata) types.Metadata {
+	merged := make(types.Metadata, len(input)+len(overrides))
+	for k, v := range input {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+// startRun assigns the run its ID and records it as running
+func (o *Orchestrator) startRun(workflow *Workflow, run *WorkflowRun) {
+	run.ID = fmt.Sprintf("run_%d_%d", time.Now().UnixNano(), atomic.AddUint64(&runCounter, 1))
+	run.WorkflowID = workflow.ID
+	run.WorkflowVersion = workflow.Version
+	run.Status = RunStatusRunning
+	run.StartedAt = time.Now()
+	if run.Trigger == "" {
+		run.Trigger = RunTriggerExecute
+	}
+	o.saveRun(run)
+}
+
+// finishRun records the outcome of a run
+func (o *Orchestrator) finishRun(run *WorkflowRun, result *WorkflowResult, err error) {
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.Result = result
+	if result != nil {
+		run.Status = result.Status
+		run.Error = result.ErrorMessage
+	}
+	if err != nil {
+		run.Status = "failed"
+		run.Error = err.Error()
+	}
+	o.saveRun(run)
+}
+
+// saveRun stores a run. Failing to record a run does not fail the workflow.
+func (o *Orchestrator) saveRun(run *WorkflowRun) {
+	data, err := json.Marshal(run)
+	if err != nil {
+		log.Printf("failed to encode workflow run %s: %v", run.ID, err)
+		return
+	}
+	record := &types.WorkflowRunRecord{
+		WorkflowRunSummary: types.WorkflowRunSummary{
+			ID:              run.ID,
+			Workspace:       run.Workspace,
+			WorkflowID:      run.WorkflowID,
+			WorkflowVersion: run.WorkflowVersion,
+			Trigger:         run.Trigger,
+			ParentRunID:     run.ParentRunID,
+			Status:          run.Status,
+			Error:           run.Error,
+			StartedAt:       run.StartedAt,
+			FinishedAt:      run.FinishedAt,
+		},
+		Run: data,
+	}
+	if err := o.runStore().StoreWorkflowRun(record); err != nil {
+		log.Printf("failed to record workflow run %s: %v", run.ID, err)
+	}
+}
+
+// restoreRun seeds a resumed run with the reasoning context, results and
+// timeline entries of the steps that succeeded in the previous run
+func restoreRun(previous *WorkflowResult, reasoningCtx *ReasoningContext, result *WorkflowResult) {
+	if prev := previous.Context; prev != nil {
+		for k, v := range prev.Results {
+			reasoningCtx.Results[k] = v
+		}
+		reasoningCtx.Thoughts = append(reasoningCtx.Thoughts, prev.Thoughts...)
+		reasoningCtx.CausalGraphs = append(reasoningCtx.CausalGraphs, prev.CausalGraphs...)
+		reasoningCtx.Beliefs = append(reasoningCtx.Beliefs, prev.Beliefs...)
+		reasoningCtx.Evidence = append(reasoningCtx.Evidence, prev.Evidence...)
+		reasoningCtx.Decisions = append(reasoningCtx.Decisions, prev.Decisions...)
+		reasoningCtx.Confidence = prev.Confidence
+	}
+
+	result.completed = make(map[string]bool)
+	for _, timing := range previous.Timeline {
+		if timing.Status != "success" {
+			continue
+		}
+		result.completed[timing.StepID] = true
+		timing.Restored = true
+		result.Timeline = append(result.Timeline, timing)
+		if value, exists := previous.StepResults[timing.StepID]; exists {
+			result.StepResults[timing.StepID] = value
+		}
+	}
+}
+
+// memoryRunStore keeps the most recent runs in memory
+type memoryRunStore struct {
+	mu      sync.Mutex
+	records map[string]*types.WorkflowRunRecord
+	order   []string // Run IDs, oldest first
+}
+
+func newMemoryRunStore() *memoryRunStore {
+	return &memoryRunStore{records: make(map[string]*types.WorkflowRunRecord)}
+}
+
+func (m *memoryRunStore) StoreWorkflowRun(record *types.WorkflowRunRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.records[record.ID]; !exists {
+		m.order = append(m.order, record.ID)
+	}
+	m.records[record.ID] = record
+	for len(m.order) > MaxMemoryRuns {
+		delete(m.records, m.order[0])
+		m.order = m.order[1:]
+	}
+	return nil
+}
+
+func (m *memoryRunStore) GetWorkflowRun(workspace, id string) (*types.WorkflowRunRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, exists := m.records[id]
+	if !exists || record.Workspace != workspace {
+		return nil, fmt.Errorf("workflow run not found: %s", id)
+	}
+	return record, nil
+}
+
+func (m *memoryRunStore) ListWorkflowRuns(workspace, workflowID, status string, limit, offset int) ([]*types.WorkflowRunSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	summaries := make([]*types.WorkflowRunSummary, 0)
+	for i := len(m.order) - 1; i >= 0 && len(summaries) < limit; i-- {
+		record := m.records[m.order[i]]
+		if record.Workspace != workspace || (workflowID != "" && record.WorkflowID != workflowID) || (status != "" && record.Status != status) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		summary := record.WorkflowRunSummary
+		summaries = append(summaries, &summary)
+	}
+	return summaries, nil
+}
@@ -0,0 +1,184 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"unified-thinking/internal/types"
+)
+
+func pipelineWorkflow(workflowType WorkflowType) *Workflow {
+	return &Workflow{ID: "pipeline", Name: "Pipeline", Type: workflowType, FailurePolicy: FailureSkipDependents, Steps: []*WorkflowStep{
+		{ID: "a", Tool: "gather", Input: types.Metadata{}, StoreAs: "gathered"},
+		{ID: "b", Tool: "flaky", Input: types.Metadata{"from": "{{gathered.id}}"}, DependsOn: []string{"a"}},
+		{ID: "c", Tool: "report", Input: types.Metadata{"topic": "{{problem}}"}, DependsOn: []string{"b"}},
+	}}
+}
+
+func TestResumeRun(t *testing.T) {
+	for _, workflowType := range []WorkflowType{WorkflowSequential, WorkflowConditional, WorkflowDAG} {
+		t.Run(string(workflowType), func(t *testing.T) {
+			executor := &scriptedExecutor{scripts: map[string][]interface{}{
+				"flaky": {fmt.Errorf("unavailable"), scored(0.9)},
+			}}
+			orch := NewOrchestratorWithExecutor(executor)
+			if err := orch.RegisterWorkflow(pipelineWorkflow(workflowType)); err != nil {
+				t.Fatalf("RegisterWorkflow: %v", err)
+			}
+
+			first, _ := orch.ExecuteWorkflow(context.Background(), "pipeline", types.Metadata{"problem": "p"})
+			if first == nil || first.RunID == "" || first.Status == "success" {
+				t.Fatalf("first = %+v", first)
+			}
+			recorded, err := orch.GetRun("", first.RunID)
+			if err != nil {
+				t.Fatalf("GetRun: %v", err)
+			}
+			if recorded.Status == RunStatusRunning || recorded.Input["problem"] != "p" || recorded.Result == nil ||
+				recorded.Result.StepResults["a"] == nil || recorded.FinishedAt == nil {
+				t.Fatalf("recorded = %+v", recorded)
+			}
+
+			resumed, err := orch.ResumeRun(context.Background(), first.RunID, nil)
+			if err != nil {
+				t.Fatalf("ResumeRun: %v", err)
+			}
+			if resumed.Status != "success" || resumed.RunID == first.RunID {
+				t.Fatalf("resumed = %+v", resumed)
+			}
+			if len(executor.calls["gather"]) != 1 || len(executor.calls["flaky"]) != 2 || len(executor.calls["report"]) != 1 {
+				t.Errorf("calls = %v", executor.calls)
+			}
+			// Restored results feed the remaining steps
+			if got := executor.calls["flaky"][1]["from"]; got != "gather" {
+				t.Errorf("flaky input from = %v", got)
+			}
+			if !resumed.Timeline[0].Restored || resumed.Timeline[0].StepID != "a" || len(resumed.Timeline) != 3 {
+				t.Errorf("timeline = %+v", resumed.Timeline)
+			}
+
+			run, _ := orch.GetRun("", resumed.RunID)
+			if run.Trigger != RunTriggerResume || run.ParentRunID != first.RunID || run.Status != "success" {
+				t.Errorf("resumed run = %+v", run)
+			}
+			if _, err := orch.ResumeRun(context.Background(), resumed.RunID, nil); err == nil || !strings.Contains(err.Error(), "only failed or partial") {
+				t.Errorf("err = %v, want successful run not resumable", err)
+			}
+		})
+	}
+}
+
+func TestRerunWorkflow(t *testing.T) {
+	executor := &scriptedExecutor{}
+	orch := NewOrchestratorWithExecutor(executor)
+	if err := orch.RegisterWorkflow(pipelineWorkflow(WorkflowSequential)); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+	first, err := orch.ExecuteWorkflow(context.Background(), "pipeline", types.Metadata{"problem": "p", "depth": 2})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
+	}
+
+	rerun, err := orch.RerunWorkflow(context.Background(), first.RunID, types.Metadata{"problem": "q"})
+	if err != nil {
+		t.Fatalf("RerunWorkflow: %v", err)
+	}
+	if len(executor.calls["gather"]) != 2 || executor.calls["report"][1]["topic"] != "q" || executor.calls["report"][1]["depth"] != 2.0 {
+		t.Errorf("calls = %v", executor.calls)
+	}
+	run, _ := orch.GetRun("", rerun.RunID)
+	if run.Trigger != RunTriggerRerun || run.ParentRunID != first.RunID || run.Input["problem"] != "q" {
+		t.Errorf("rerun = %+v", run)
+	}
+
+	runs, err := orch.ListRuns("", "pipeline", "", 10, 0)
+	if err != nil || len(runs) != 2 || runs[0].ID != rerun.RunID {
+		t.Errorf("runs = %+v, err %v", runs, err)
+	}
+	if runs, _ := orch.ListRuns("", "", "failed", 10, 0); len(runs) != 0 {
+		t.Errorf("failed runs = %+v", runs)
+	}
+	if _, err := orch.RerunWorkflow(context.Background(), "missing", nil); err == nil {
+		t.Error("expected error for unknown run")
+	}
+}
+
+func TestRunHistory_Persistent(t *testing.T) {
+	store, _ := newWorkflowStore(t)
+	executor := &scriptedExecutor{scripts: map[string][]interface{}{"flaky": {fmt.Errorf("unavailable"), scored(0.9)}}}
+	orch := NewOrchestratorWithExecutor(executor)
+	orch.SetRunStore(store)
+	if err := orch.RegisterWorkflow(pipelineWorkflow(WorkflowSequential)); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+	failed, err := orch.ExecuteWorkflow(context.Background(), "pipeline", types.Metadata{"problem": "p"})
+	if err == nil {
+		t.Fatal("expected the first run to fail")
+	}
+
+	// Another orchestrator sharing the store resumes the run
+	restarted := NewOrchestratorWithExecutor(executor)
+	restarted.SetRunStore(store)
+	if err := restarted.RegisterWorkflow(pipelineWorkflow(WorkflowSequential)); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+	runs, err := restarted.ListRuns("", "pipeline", "failed", 10, 0)
+	if err != nil || len(runs) != 1 || runs[0].ID != failed.RunID || !strings.Contains(runs[0].Error, "step b failed") {
+		t.Fatalf("runs = %+v, err %v", runs, err)
+	}
+	resumed, err := restarted.ResumeRun(context.Background(), failed.RunID, nil)
+	if err != nil || resumed.Status != "success" || len(executor.calls["gather"]) != 1 {
+		t.Errorf("resumed = %+v, err %v, calls %v", resumed, err, executor.calls)
+	}
+}
+
+func TestRunHistory_Workspaces(t *testing.T) {
+	executor := &scriptedExecutor{scripts: map[string][]interface{}{"flaky": {fmt.Errorf("unavailable"), scored(0.9)}}}
+	orch := NewOrchestratorWithExecutor(executor)
+	if err := orch.RegisterWorkflow(pipelineWorkflow(WorkflowSequential)); err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
+	}
+	alpha := WithWorkspace(context.Background(), "alpha")
+	failed, err := orch.ExecuteWorkflow(alpha, "pipeline", types.Metadata{"problem": "p"})
+	if err == nil {
+		t.Fatal("expected the first run to fail")
+	}
+
+	if runs, _ := orch.ListRuns("beta", "", "", 10, 0); len(runs) != 0 {
+		t.Errorf("beta runs = %+v", runs)
+	}
+	if _, err := orch.GetRun("beta", failed.RunID); err == nil {
+		t.Error("expected run to be hidden from another workspace")
+	}
+	if _, err := orch.ResumeRun(WithWorkspace(context.Background(), "beta"), failed.RunID, nil); err == nil {
+		t.Error("expected resume from another workspace to fail")
+	}
+
+	resumed, err := orch.ResumeRun(alpha, failed.RunID, nil)
+	if err != nil {
+		t.Fatalf("ResumeRun: %v", err)
+	}
+	runs, err := orch.ListRuns("alpha", "pipeline", "", 10, 0)
+	if err != nil || len(runs) != 2 || runs[0].ID != resumed.RunID || runs[0].Workspace != "alpha" {
+		t.Errorf("alpha runs = %+v, err %v", runs, err)
+	}
+}
+
+func TestMemoryRunStore_Bounded(t *testing.T) {
+	store := newMemoryRunStore()
+	for i := 0; i < MaxMemoryRuns+5; i++ {
+		record := &types.WorkflowRunRecord{WorkflowRunSummary: types.WorkflowRunSummary{ID: fmt.Sprintf("run-%d", i), Status: "success"}}
+		if err := store.StoreWorkflowRun(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.GetWorkflowRun("", "run-0"); err == nil {
+		t.Error("expected the oldest run to be evicted")
+	}
+	runs, _ := store.ListWorkflowRuns("", "", "", 2, 1)
+	if len(runs) != 2 || runs[0].ID != fmt.Sprintf("run-%d", MaxMemoryRuns+3) {
+		t.Errorf("runs = %+v", runs)
+	}
+}
@@ -1,14 +1,16 @@
 // Package orchestration provides workflow orchestration for automated tool chaining.
 //
 // This package enables automatic coordination of multiple reasoning tools to execute
-// complex analysis workflows without manual intervention. Workflows can be sequential
-// or parallel, with conditional execution based on intermediate results.
+// complex analysis workflows without manual intervention. Workflows can be sequential,
+// parallel, or a dependency graph (DAG), with conditional execution based on
+// intermediate results.
 package orchestration
 
 import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"sync"
 	"time"
 
@@ -23,18 +25,31 @@ const (
 	WorkflowSequential  WorkflowType = "sequential"  // Execute steps in order
 	WorkflowParallel    WorkflowType = "parallel"    // Execute steps concurrently
 	WorkflowConditional WorkflowType = "conditional" // Execute based on conditions
+	WorkflowDAG         WorkflowType = "dag"         // Execute steps as their dependencies complete
+)
+
+// FailurePolicy decides what happens to a DAG workflow when a step fails
+type FailurePolicy string
+
+const (
+	FailureAbort          FailurePolicy = "abort"           // Cancel running steps and fail the run (default)
+	FailureSkipDependents FailurePolicy = "skip_dependents" // Skip the failed step's dependents, run the rest
 )
 
 // Workflow represents a coordinated sequence of tool executions
 type Workflow struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	Type        WorkflowType      `json:"type"`
-	Steps       []*WorkflowStep   `json:"steps"`
-	Context     *ReasoningContext `json:"context,omitempty"`
-	CreatedAt   time.Time         `json:"created_at"`
-	Metadata    types.Metadata    `json:"metadata,omitempty"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Type        WorkflowType    `json:"type"`
+	Steps       []*WorkflowStep `json:"steps"`
+	// DAG workflows only: steps running at once (default 4) and failure handling
+	MaxConcurrency int               `json:"max_concurrency,omitempty"`
+	FailurePolicy  FailurePolicy     `json:"failure_policy,omitempty"`
+	Context        *ReasoningContext `json:"context,omitempty"`
+	Version        int               `json:"version,omitempty"` // Set when stored in a WorkflowStore
+	CreatedAt      time.Time         `json:"created_at"`
+	Metadata       types.Metadata    `json:"metadata,omitempty"`
 }
 
 // WorkflowStep represents a single step in a workflow
@@ -46,21 +61,26 @@ type WorkflowStep struct {
 	Condition *StepCondition   `json:"condition,omitempty"`  // Conditional execution
 	Transform *OutputTransform `json:"transform,omitempty"`  // Output transformation
 	StoreAs   string           `json:"store_as,omitempty"`   // Store result in context
+	Loop      *StepLoop        `json:"loop,omitempty"`       // Repeat the step
+	Retry     *RetryPolicy     `json:"retry,omitempty"`      // Retry failed executions
+	TimeoutMs int              `json:"timeout_ms,omitempty"` // Per-attempt deadline passed to the tool
 	Metadata  types.Metadata   `json:"metadata,omitempty"`
 }
 
 // StepCondition defines when a step should execute
 type StepCondition struct {
-	Type     string      `json:"type"` // "confidence_threshold", "result_match", etc.
-	Field    string      `json:"field,omitempty"`
-	Operator string      `json:"operator"` // "gt", "lt", "eq", "contains", etc.
-	Value    interface{} `json:"value"`
+	Type       string      `json:"type"`            // "confidence_threshold", "result_match", etc.
+	Field      string      `json:"field,omitempty"` // Result key, dotted path or context field
+	Operator   string      `json:"operator"`        // "gt", "gte", "lt", "lte", "eq", "ne", "contains", "exists"
+	Value      interface{} `json:"value"`
+	Expression string      `json:"expression,omitempty"` // Replaces field, operator and value, see Expression
 }
 
 // OutputTransform defines how to transform step output
 type OutputTransform struct {
-	Type   string         `json:"type"`   // "extract_field", "map", "filter"
-	Config types.Metadata `json:"config"` // Transform configuration
+	Type       string         `json:"type"`                 // "extract_field", "map", "filter", "expression"
+	Config     types.Metadata `json:"config"`               // Transform configuration
+	Expression string         `json:"expression,omitempty"` // Computes the output from "result", see Expression
 }
 
 // ReasoningContext tracks shared state across workflow execution
@@ -83,19 +103,59 @@ type ReasoningContext struct {
 // WorkflowResult contains the outcome of workflow execution
 type WorkflowResult struct {
 	WorkflowID   string            `json:"workflow_id"`
-	Status       string            `json:"status"` // "success", "partial", "failed"
+	RunID        string            `json:"run_id,omitempty"` // Recorded run, see GetRun
+	Status       string            `json:"status"`           // "success", "partial", "failed"
 	StepResults  types.Metadata    `json:"step_results"`
 	Context      *ReasoningContext `json:"context"`
 	Duration     time.Duration     `json:"duration"`
 	ErrorMessage string            `json:"error_message,omitempty"`
+	Timeline     []StepTiming      `json:"timeline,omitempty"` // Steps in order of completion
 	Metadata     types.Metadata    `json:"metadata,omitempty"`
+
+	completed map[string]bool // Steps restored from a resumed run
+}
+
+// StepTiming records when a step ran and how it ended
+type StepTiming struct {
+	StepID     string    `json:"step_id"`
+	Tool       string    `json:"tool"`
+	Status     string    `json:"status"` // "success", "failed", "skipped"
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+	Iterations int       `json:"iterations,omitempty"` // Loop iterations completed
+	Attempts   int       `json:"attempts,omitempty"`   // Executions including retries
+	Restored   bool      `json:"restored,omitempty"`   // Succeeded in the run this one resumed
+}
+
+// recordStep appends a step to the timeline. Callers running steps
+// concurrently must hold their result lock.
+func (r *WorkflowResult) recordStep(step *WorkflowStep, status string, started time.Time, err error) {
+	timing := StepTiming{StepID: step.ID, Tool: step.Tool, Status: status, StartedAt: started, FinishedAt: time.Now()}
+	if err != nil {
+		timing.Error = err.Error()
+	}
+	r.Timeline = append(r.Timeline, timing)
+}
+
+// recordRun appends an executed step to the timeline with its iteration and
+// attempt counts
+func (r *WorkflowResult) recordRun(step *WorkflowStep, status string, started time.Time, run stepRun, err error) {
+	r.recordStep(step, status, started, err)
+	timing := &r.Timeline[len(r.Timeline)-1]
+	timing.Attempts = run.attempts
+	if step.Loop != nil {
+		timing.Iterations = run.iterations
+	}
 }
 
 // Orchestrator manages workflow execution and coordination
 type Orchestrator struct {
 	workflows map[string]*Workflow
 	contexts  map[string]*ReasoningContext
-	executor  ToolExecutor // Add executor for tool execution
+	executor  ToolExecutor  // Add executor for tool execution
+	store     WorkflowStore // Optional persistence of registered workflows
+	runs      RunStore      // Run history, in memory unless SetRunStore is called
 	mu        sync.RWMutex
 }
 
@@ -105,6 +165,7 @@ func NewOrchestrator() *Orchestrator {
 		workflows: make(map[string]*Workflow),
 		contexts:  make(map[string]*ReasoningContext),
 		executor:  nil, // Executor must be set via SetExecutor
+		runs:      newMemoryRunStore(),
 	}
 }
 
@@ -114,6 +175,7 @@ func NewOrchestratorWithExecutor(executor ToolExecutor) *Orchestrator {
 		workflows: make(map[string]*Workflow),
 		contexts:  make(map[string]*ReasoningContext),
 		executor:  executor,
+		runs:      newMemoryRunStore(),
 	}
 }
 
@@ -124,24 +186,36 @@ func (o *Orchestrator) SetExecutor(executor ToolExecutor) {
 	o.executor = executor
 }
 
-// RegisterWorkflow adds a new workflow to the orchestrator
+// RegisterWorkflow adds a new workflow to the orchestrator. With a workflow
+// store the workflow is persisted as its first version.
 func (o *Orchestrator) RegisterWorkflow(workflow *Workflow) error {
+	return o.saveWorkflow(workflow, SourceTool, false)
+}
+
+// validateWorkflow checks a workflow before it is registered
+func validateWorkflow(workflow *Workflow) error {
 	if workflow.ID == "" {
 		return fmt.Errorf("workflow ID is required")
 	}
 	if workflow.Name == "" {
 		return fmt.Errorf("workflow name is required")
 	}
-
-	o.mu.Lock()
-	defer o.mu.Unlock()
-
-	if _, exists := o.workflows[workflow.ID]; exists {
-		return fmt.Errorf("workflow %s already exists", workflow.ID)
+	for _, step := range workflow.Steps {
+		if step == nil {
+			continue
+		}
+		if err := validateStep(step); err != nil {
+			return fmt.Errorf("workflow %s: %w", workflow.ID, err)
+		}
+		if err := validateExpressions(step); err != nil {
+			return fmt.Errorf("workflow %s: %w", workflow.ID, err)
+		}
+	}
+	if workflow.Type == WorkflowDAG {
+		if err := validateDAG(workflow); err != nil {
+			return fmt.Errorf("workflow %s: %w", workflow.ID, err)
+		}
 	}
-
-	workflow.CreatedAt = time.Now()
-	o.workflows[workflow.ID] = workflow
 	return nil
 }
 
@@ -221,25 +295,39 @@ func (o *Orchestrator) UpdateContext(ctx *ReasoningContext) error {
 	return nil
 }
 
-// ExecuteWorkflow executes a workflow with the given input
+// ExecuteWorkflow executes a workflow with the given input. The execution is
+// recorded as a run in the workspace of ctx, see GetRun and WithWorkspace.
 func (o *Orchestrator) ExecuteWorkflow(ctx context.Context, workflowID string, input types.Metadata) (*WorkflowResult, error) {
-	startTime := time.Now()
-
 	workflow, err := o.GetWorkflow(workflowID)
 	if err != nil {
 		return nil, err
 	}
+	return o.executeRun(ctx, workflow, &WorkflowRun{Workspace: workspaceFrom(ctx), Trigger: RunTriggerExecute, Input: input}, nil)
+}
+
+// executeRun executes a workflow and records it as run. Steps that succeeded
+// in previous, when set, are restored instead of executed.
+func (o *Orchestrator) executeRun(ctx context.Context, workflow *Workflow, run *WorkflowRun, previous *WorkflowResult) (*WorkflowResult, error) {
+	startTime := time.Now()
+	input := run.Input
 
 	// Create reasoning context
 	problem, _ := input["problem"].(string)
-	reasoningCtx := o.CreateContext(workflowID, problem)
+	reasoningCtx := o.CreateContext(workflow.ID, problem)
 
 	result := &WorkflowResult{
-		WorkflowID:  workflowID,
+		WorkflowID:  workflow.ID,
 		StepResults: make(types.Metadata),
 		Context:     reasoningCtx,
 		Metadata:    make(types.Metadata),
 	}
+	if previous != nil {
+		restoreRun(previous, reasoningCtx, result)
+	}
+	o.startRun(workflow, run)
+	result.RunID = run.ID
+
+	var err error
 
 	// Get progress reporter from context if available
 	reporter := streaming.GetReporter(ctx)
@@ -260,8 +348,12 @@ func (o *Orchestrator) ExecuteWorkflow(ctx context.Context, workflowID string, i
 		err = o.executeParallel(ctx, workflow, input, reasoningCtx, result)
 	case WorkflowConditional:
 		err = o.executeConditional(ctx, workflow, input, reasoningCtx, result)
+	case WorkflowDAG:
+		err = o.executeDAG(ctx, workflow, input, reasoningCtx, result)
 	default:
-		return nil, fmt.Errorf("unknown workflow type: %s", workflow.Type)
+		err = fmt.Errorf("unknown workflow type: %s", workflow.Type)
+		o.finishRun(run, nil, err)
+		return nil, err
 	}
 
 	result.Duration = time.Since(startTime)
@@ -275,17 +367,23 @@ func (o *Orchestrator) ExecuteWorkflow(ctx context.Context, workflowID string, i
 				log.Printf("failed to report workflow failure: %v", reportErr)
 			}
 		}
+		o.finishRun(run, result, err)
 		return result, err
 	}
 
+	// DAG workflows that skipped the dependents of failed steps are partial
+	if result.Status == "" {
+		result.Status = "success"
+	}
+
 	// Report completion
 	if reporter.IsEnabled() {
-		if err := reporter.ReportStep(len(workflow.Steps), len(workflow.Steps), "complete", "Workflow completed successfully"); err != nil {
+		if err := reporter.ReportStep(len(workflow.Steps), len(workflow.Steps), "complete", "Workflow completed: "+result.Status); err != nil {
 			log.Printf("failed to report workflow completion: %v", err)
 		}
 	}
 
-	result.Status = "success"
+	o.finishRun(run, result, nil)
 	return result, nil
 }
 
@@ -295,8 +393,13 @@ func (o *Orchestrator) executeSequential(ctx context.Context, workflow *Workflow
 	totalSteps := len(workflow.Steps)
 
 	for i, step := range workflow.Steps {
+		if result.completed[step.ID] {
+			continue
+		}
+
 		// Check condition if present
-		if step.Condition != nil && !o.evaluateCondition(step.Condition, reasoningCtx) {
+		if !o.shouldRun(step, reasoningCtx) {
+			result.recordStep(step, "skipped", time.Now(), nil)
 			// Report skipped step
 			if reporter.IsEnabled() {
 				if err := reporter.ReportStep(i+1, totalSteps, step.ID, "Skipped (condition not met)"); err != nil {
@@ -314,10 +417,13 @@ func (o *Orchestrator) executeSequential(ctx context.Context, workflow *Workflow
 		}
 
 		// Execute step
-		stepResult, err := o.executeStep(ctx, step, input, reasoningCtx)
+		started := time.Now()
+		stepResult, run, err := o.runStep(ctx, step, input, reasoningCtx, nil)
 		if err != nil {
+			result.recordRun(step, "failed", started, run, err)
 			return fmt.Errorf("step %s failed: %w", step.ID, err)
 		}
+		result.recordRun(step, "success", started, run, nil)
 
 		// Store result
 		if step.StoreAs != "" {
@@ -348,28 +454,39 @@ func (o *Orchestrator) executeParallel(ctx context.Context, workflow *Workflow,
 	var resultMu sync.Mutex
 
 	for _, step := range workflow.Steps {
+		if result.completed[step.ID] {
+			continue
+		}
 		wg.Add(1)
 		go func(s *WorkflowStep) {
 			defer wg.Done()
 
 			// Check condition if present (needs mutex for reading reasoningCtx)
 			resultMu.Lock()
-			shouldExecute := s.Condition == nil || o.evaluateCondition(s.Condition, reasoningCtx)
+			shouldExecute := o.shouldRun(s, reasoningCtx)
 			resultMu.Unlock()
 
 			if !shouldExecute {
+				resultMu.Lock()
+				result.recordStep(s, "skipped", time.Now(), nil)
+				resultMu.Unlock()
 				return
 			}
 
 			// Execute step (passing mutex for reasoningCtx protection)
-			stepResult, err := o.executeStepWithLock(ctx, s, input, reasoningCtx, &resultMu)
+			started := time.Now()
+			stepResult, run, err := o.runStep(ctx, s, input, reasoningCtx, &resultMu)
 			if err != nil {
+				resultMu.Lock()
+				result.recordRun(s, "failed", started, run, err)
+				resultMu.Unlock()
 				errors <- fmt.Errorf("step %s failed: %w", s.ID, err)
 				return
 			}
 
 			// Store result
 			resultMu.Lock()
+			result.recordRun(s, "success", started, run, nil)
 			if s.StoreAs != "" {
 				reasoningCtx.Results[s.StoreAs] = stepResult
 			}
@@ -381,6 +498,11 @@ func (o *Orchestrator) executeParallel(ctx context.Context, workflow *Workflow,
 	wg.Wait()
 	close(errors)
 
+	// Update results, including those of a failed run so it can be resumed
+	for id, res := range stepResults {
+		result.StepResults[id] = res
+	}
+
 	// Check for errors
 	for err := range errors {
 		if err != nil {
@@ -388,11 +510,6 @@ func (o *Orchestrator) executeParallel(ctx context.Context, workflow *Workflow,
 		}
 	}
 
-	// Update results
-	for id, res := range stepResults {
-		result.StepResults[id] = res
-	}
-
 	if err := o.UpdateContext(reasoningCtx); err != nil {
 		log.Printf("failed to update context after parallel execution: %v", err)
 	}
@@ -413,6 +530,12 @@ func (o *Orchestrator) executeConditional(ctx context.Context, workflow *Workflo
 	// Execute steps respecting dependencies
 	executed := make(map[string]bool)
 	executedCount := 0
+	for _, step := range workflow.Steps {
+		if result.completed[step.ID] {
+			executed[step.ID] = true
+			executedCount++
+		}
+	}
 	for len(executed) < len(workflow.Steps) {
 		progress := false
 
@@ -435,7 +558,8 @@ func (o *Orchestrator) executeConditional(ctx context.Context, workflow *Workflo
 			}
 
 			// Check condition
-			if step.Condition != nil && !o.evaluateCondition(step.Condition, reasoningCtx) {
+			if !o.shouldRun(step, reasoningCtx) {
+				result.recordStep(step, "skipped", time.Now(), nil)
 				executed[step.ID] = true
 				executedCount++
 				// Report skipped step
@@ -456,10 +580,13 @@ func (o *Orchestrator) executeConditional(ctx context.Context, workflow *Workflo
 			}
 
 			// Execute step
-			stepResult, err := o.executeStep(ctx, step, input, reasoningCtx)
+			started := time.Now()
+			stepResult, run, err := o.runStep(ctx, step, input, reasoningCtx, nil)
 			if err != nil {
+				result.recordRun(step, "failed", started, run, err)
 				return fmt.Errorf("step %s failed: %w", step.ID, err)
 			}
+			result.recordRun(step, "success", started, run, nil)
 
 			// Store result
 			if step.StoreAs != "" {
@@ -530,7 +657,18 @@ func (o *Orchestrator) executeStepWithLock(ctx context.Context, step *WorkflowSt
 	}
 
 	// Apply output transformation if specified
-	if step.Transform != nil {
+	if step.Transform != nil && step.Transform.Expression != "" {
+		if mu != nil {
+			mu.Lock()
+		}
+		result, err = o.transformExpression(step.Transform.Expression, result, input, reasoningCtx)
+		if mu != nil {
+			mu.Unlock()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("step %s transform failed: %w", step.ID, err)
+		}
+	} else if step.Transform != nil {
 		result = applyTransform(result, step.Transform)
 	}
 
@@ -552,25 +690,25 @@ func (o *Orchestrator) executeStepWithLock(ctx context.Context, step *WorkflowSt
 		}
 	case "build-causal-graph":
 		if resultMap != nil {
-			if graphID, ok := resultMap["id"].(string); ok {
+			if graphID := resultID(resultMap, "graph"); graphID != "" {
 				reasoningCtx.CausalGraphs = append(reasoningCtx.CausalGraphs, graphID)
 			}
 		}
 	case "probabilistic-reasoning":
 		if resultMap != nil {
-			if beliefID, ok := resultMap["id"].(string); ok {
+			if beliefID := resultID(resultMap, "belief"); beliefID != "" {
 				reasoningCtx.Beliefs = append(reasoningCtx.Beliefs, beliefID)
 			}
 		}
 	case "assess-evidence":
 		if resultMap != nil {
-			if evidenceID, ok := resultMap["id"].(string); ok {
+			if evidenceID := resultID(resultMap, "evidence"); evidenceID != "" {
 				reasoningCtx.Evidence = append(reasoningCtx.Evidence, evidenceID)
 			}
 		}
 	case "make-decision":
 		if resultMap != nil {
-			if decisionID, ok := resultMap["id"].(string); ok {
+			if decisionID := resultID(resultMap, "decision"); decisionID != "" {
 				reasoningCtx.Decisions = append(reasoningCtx.Decisions, decisionID)
 			}
 		}
@@ -608,6 +746,19 @@ func (o *Orchestrator) resolveTemplateValue(value interface{}, workflowInput typ
 		return value
 	}
 
+	// Templates that are not plain references are expressions
+	if source, ok := templateExpression(strVal); ok {
+		expr, err := CompileExpression(source)
+		if err == nil {
+			var resolved interface{}
+			if resolved, err = expr.Evaluate(contextLookup(reasoningCtx, workflowInput)); err == nil {
+				return resolved
+			}
+		}
+		log.Printf("failed to resolve template %s: %v", strVal, err)
+		return strVal
+	}
+
 	// Convert {{variable}} syntax to $variable for unified processing
 	templateValue := convertTemplateToReference(strVal)
 
@@ -694,6 +845,7 @@ func splitReference(ref string) []string {
 
 // extractNestedValue extracts a nested value from an interface using a path.
 // Handles both types.Metadata and map[string]interface{} for JSON unmarshaling compatibility.
+// Numeric path elements index into lists (e.g. "refined_iterations.0.confidence").
 func extractNestedValue(val interface{}, path []string) interface{} {
 	if len(path) == 0 {
 		return val
@@ -709,6 +861,10 @@ func extractNestedValue(val interface{}, path []string) interface{} {
 		if nextVal, exists := v[path[0]]; exists {
 			return extractNestedValue(nextVal, path[1:])
 		}
+	case []interface{}:
+		if index, err := strconv.Atoi(path[0]); err == nil && index >= 0 && index < len(v) {
+			return extractNestedValue(v[index], path[1:])
+		}
 	}
 
 	return nil
@@ -756,6 +912,23 @@ func applyTransform(result interface{}, transform *OutputTransform) interface{}
 	return result
 }
 
+// transformExpression computes a step's output with an expression in which
+// "result" is the tool result. Callers running steps concurrently must hold
+// the reasoning context lock.
+func (o *Orchestrator) transformExpression(source string, result interface{}, input types.Metadata, reasoningCtx *ReasoningContext) (interface{}, error) {
+	expr, err := CompileExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	lookup := contextLookup(reasoningCtx, input)
+	return expr.Evaluate(func(name string) (interface{}, bool) {
+		if name == "result" {
+			return result, true
+		}
+		return lookup(name)
+	})
+}
+
 // extractResultMap extracts a map from interface{} (handles both types.Metadata and map[string]interface{})
 func extractResultMap(result interface{}) map[string]interface{} {
 	switch v := result.(type) {
@@ -767,6 +940,20 @@ func extractResultMap(result interface{}) map[string]interface{} {
 	return nil
 }
 
+// resultID returns the id of a tool result, either at the top level or in
+// the object the tool wraps it in (e.g. {"graph": {"id": ...}})
+func resultID(resultMap map[string]interface{}, wrapper string) string {
+	if id, ok := resultMap["id"].(string); ok {
+		return id
+	}
+	if nested := extractResultMap(resultMap[wrapper]); nested != nil {
+		if id, ok := nested["id"].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
 // extractConfidence extracts confidence value from a result
 func extractConfidence(result interface{}) float64 {
 	resultMap := extractResultMap(result)
@@ -787,37 +974,28 @@ func extractConfidence(result interface{}) float64 {
 	return 0
 }
 
-// evaluateCondition checks if a step condition is met
+// evaluateCondition checks if a step condition is met. An expression that
+// fails to evaluate does not meet the condition.
 func (o *Orchestrator) evaluateCondition(condition *StepCondition, ctx *ReasoningContext) bool {
-	// Extract value from context or result
-	var value interface{}
-
-	if condition.Field != "" {
-		value = ctx.Results[condition.Field]
-	}
-
-	// Evaluate based on operator
-	switch condition.Operator {
-	case "gt":
-		if v, ok := value.(float64); ok {
-			threshold, _ := condition.Value.(float64)
-			return v > threshold
-		}
-	case "lt":
-		if v, ok := value.(float64); ok {
-			threshold, _ := condition.Value.(float64)
-			return v < threshold
+	if condition.Expression != "" {
+		expr, err := CompileExpression(condition.Expression)
+		if err != nil {
+			log.Printf("invalid condition: %v", err)
+			return false
 		}
-	case "eq":
-		return value == condition.Value
-	case "contains":
-		if v, ok := value.(string); ok {
-			search, _ := condition.Value.(string)
-			return len(search) > 0 && len(v) > 0 && contains(v, search)
+		met, err := expr.EvaluateBool(contextLookup(ctx, nil))
+		if err != nil {
+			log.Printf("failed to evaluate condition: %v", err)
+			return false
 		}
+		return met
 	}
 
-	return false
+	var value interface{}
+	if condition.Field != "" {
+		value = conditionValue(condition.Field, ctx)
+	}
+	return compareValues(value, condition.Operator, condition.Value)
 }
 
 // contains checks if a string contains a substring
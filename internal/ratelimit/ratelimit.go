@@ -0,0 +1,60 @@
+// Package ratelimit provides the client-side token bucket shared by the
+// outbound API clients (LLM providers, Voyage AI embeddings and reranking).
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a token bucket rate limiter. It is safe for concurrent use.
+type TokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	maxTokens  float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+// NewTokenBucket creates a token bucket that starts full
+func NewTokenBucket(ratePerSecond float64, burst int) *TokenBucket {
+	return &TokenBucket{
+		tokens:     float64(burst),
+		maxTokens:  float64(burst),
+		refillRate: ratePerSecond,
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or context is cancelled
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		// Refill tokens based on elapsed time
+		now := time.Now()
+		b.tokens += now.Sub(b.lastRefill).Seconds() * b.refillRate
+		if b.tokens > b.maxTokens {
+			b.tokens = b.maxTokens
+		}
+		b.lastRefill = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+
+		// Wait for the next token or context cancellation
+		waitTime := time.Duration((1.0 - b.tokens) / b.refillRate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(waitTime)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_Burst(t *testing.T) {
+	// 10 tokens/sec with a burst of 5
+	limiter := NewTokenBucket(10, 5)
+
+	ctx := context.Background()
+
+	// The burst is available immediately
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Errorf("expected no error on token %d, got %v", i, err)
+		}
+	}
+}
+
+func TestTokenBucket_ContextCancellation(t *testing.T) {
+	limiter := NewTokenBucket(1, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := limiter.Wait(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTokenBucket_Timeout(t *testing.T) {
+	limiter := NewTokenBucket(1, 1)
+	ctx := context.Background()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("first token: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded while the bucket refills", err)
+	}
+}
+
+func TestTokenBucket_Refill(t *testing.T) {
+	// 100 tokens/sec with a burst of 1
+	limiter := NewTokenBucket(100, 1)
+
+	ctx := context.Background()
+
+	// Use the initial token
+	if err := limiter.Wait(ctx); err != nil {
+		t.Errorf("expected no error on first token, got %v", err)
+	}
+
+	// Wait a bit for refill
+	time.Sleep(20 * time.Millisecond)
+
+	// Should be able to get another token after refill
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Errorf("expected no error after refill, got %v", err)
+	}
+
+	// Should have been nearly instant (< 20ms) since we waited for refill
+	if elapsed > 20*time.Millisecond {
+		t.Errorf("expected fast response after refill, took %v", elapsed)
+	}
+}
+
+func TestTokenBucket_Concurrent(t *testing.T) {
+	limiter := NewTokenBucket(1000, 100)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errCount := 0
+	var mu sync.Mutex
+
+	// Launch 50 concurrent waiters
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(ctx); err != nil {
+				mu.Lock()
+				errCount++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if errCount > 0 {
+		t.Errorf("expected no errors, got %d", errCount)
+	}
+}
+
+func BenchmarkTokenBucket(b *testing.B) {
+	limiter := NewTokenBucket(10000, 1000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = limiter.Wait(ctx)
+	}
+}
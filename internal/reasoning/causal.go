@@ -7,20 +7,36 @@ package reasoning
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"unified-thinking/internal/types"
 )
 
+// CausalStorage persists causal graphs and the interventions and
+// counterfactuals derived from them, so they survive restarts
+type CausalStorage interface {
+	StoreCausalGraph(graph *types.CausalGraph) error
+	GetCausalGraph(id string) (*types.CausalGraph, error)
+	ListCausalGraphs(limit, offset int) ([]*types.CausalGraphSummary, error)
+	StoreCausalIntervention(intervention *types.CausalIntervention) error
+	StoreCounterfactual(counterfactual *types.Counterfactual) error
+}
+
 // CausalReasoner performs causal inference and counterfactual reasoning
 type CausalReasoner struct {
 	mu      sync.RWMutex
 	graphs  map[string]*types.CausalGraph
-	counter int
+	storage CausalStorage // Optional persistent storage
 }
 
+// causalIDs numbers the records of every causal reasoner, so reasoners for
+// different workspaces sharing one database never reuse an ID
+var causalIDs atomic.Int64
+
 // NewCausalReasoner creates a new causal reasoner
 func NewCausalReasoner() *CausalReasoner {
 	return &CausalReasoner{
@@ -28,6 +44,50 @@ func NewCausalReasoner() *CausalReasoner {
 	}
 }
 
+// SetStorage enables write-through persistence. Graphs not held in memory
+// are loaded from storage on first use.
+func (cr *CausalReasoner) SetStorage(store CausalStorage) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.storage = store
+}
+
+// nextID returns a unique identifier. The timestamp keeps IDs from colliding
+// with records persisted by earlier processes.
+func (cr *CausalReasoner) nextID(prefix string) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, time.Now().Unix(), causalIDs.Add(1))
+}
+
+// lookupGraph returns a graph from memory, falling back to storage
+func (cr *CausalReasoner) lookupGraph(graphID string) (*types.CausalGraph, error) {
+	cr.mu.RLock()
+	graph, exists := cr.graphs[graphID]
+	store := cr.storage
+	cr.mu.RUnlock()
+
+	if exists {
+		return graph, nil
+	}
+	if store == nil {
+		return nil, fmt.Errorf("graph not found: %s", graphID)
+	}
+
+	graph, err := store.GetCausalGraph(graphID)
+	if err != nil {
+		return nil, fmt.Errorf("graph not found: %s", graphID)
+	}
+
+	cr.mu.Lock()
+	if cached, ok := cr.graphs[graphID]; ok {
+		graph = cached
+	} else {
+		cr.graphs[graphID] = graph
+	}
+	cr.mu.Unlock()
+
+	return graph, nil
+}
+
 // BuildCausalGraph constructs a causal graph from observations
 func (cr *CausalReasoner) BuildCausalGraph(description string, observations []string) (*types.CausalGraph, error) {
 	if description == "" {
@@ -40,8 +100,6 @@ func (cr *CausalReasoner) BuildCausalGraph(description string, observations []st
 	cr.mu.Lock()
 	defer cr.mu.Unlock()
 
-	cr.counter++
-
 	// Extract variables from observations
 	variables := cr.extractVariables(observations)
 
@@ -49,7 +107,7 @@ func (cr *CausalReasoner) BuildCausalGraph(description string, observations []st
 	links := cr.identifyCausalLinks(variables, observations)
 
 	graph := &types.CausalGraph{
-		ID:          fmt.Sprintf("causal-graph-%d", cr.counter),
+		ID:          cr.nextID("causal-graph"),
 		Description: description,
 		Variables:   variables,
 		Links:       links,
@@ -57,6 +115,12 @@ func (cr *CausalReasoner) BuildCausalGraph(description string, observations []st
 		CreatedAt:   time.Now(),
 	}
 
+	if cr.storage != nil {
+		if err := cr.storage.StoreCausalGraph(graph); err != nil {
+			return nil, fmt.Errorf("failed to persist causal graph: %w", err)
+		}
+	}
+
 	cr.graphs[graph.ID] = graph
 
 	return graph, nil
@@ -469,12 +533,9 @@ func (cr *CausalReasoner) performGraphSurgery(graph *types.CausalGraph, interven
 
 // SimulateIntervention simulates the effects of an intervention using Pearl's do-calculus
 func (cr *CausalReasoner) SimulateIntervention(graphID, variableID, interventionType string) (*types.CausalIntervention, error) {
-	cr.mu.RLock()
-	graph, exists := cr.graphs[graphID]
-	cr.mu.RUnlock()
-
-	if !exists {
-		return nil, fmt.Errorf("graph not found: %s", graphID)
+	graph, err := cr.lookupGraph(graphID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Find the target variable
@@ -493,8 +554,6 @@ func (cr *CausalReasoner) SimulateIntervention(graphID, variableID, intervention
 	cr.mu.Lock()
 	defer cr.mu.Unlock()
 
-	cr.counter++
-
 	// CRITICAL: Apply graph surgery for intervention (Pearl's do-calculus)
 	// When we do(X=x), we remove all incoming edges to X, breaking its natural causes
 	// This isolates X from its parents while preserving its effects on descendants
@@ -507,7 +566,7 @@ func (cr *CausalReasoner) SimulateIntervention(graphID, variableID, intervention
 	overallConfidence := cr.calculateInterventionConfidence(effects)
 
 	intervention := &types.CausalIntervention{
-		ID:               fmt.Sprintf("intervention-%d", cr.counter),
+		ID:               cr.nextID("intervention"),
 		GraphID:          graphID,
 		Variable:         targetVar.Name,
 		InterventionType: interventionType,
@@ -520,6 +579,12 @@ func (cr *CausalReasoner) SimulateIntervention(graphID, variableID, intervention
 		CreatedAt: time.Now(),
 	}
 
+	if cr.storage != nil {
+		if err := cr.storage.StoreCausalIntervention(intervention); err != nil {
+			return nil, fmt.Errorf("failed to persist intervention: %w", err)
+		}
+	}
+
 	return intervention, nil
 }
 
@@ -623,12 +688,9 @@ func (cr *CausalReasoner) calculateInterventionConfidence(effects []*types.Predi
 
 // GenerateCounterfactual generates a counterfactual scenario
 func (cr *CausalReasoner) GenerateCounterfactual(graphID, scenario string, changes map[string]string) (*types.Counterfactual, error) {
-	cr.mu.RLock()
-	graph, exists := cr.graphs[graphID]
-	cr.mu.RUnlock()
-
-	if !exists {
-		return nil, fmt.Errorf("graph not found: %s", graphID)
+	graph, err := cr.lookupGraph(graphID)
+	if err != nil {
+		return nil, err
 	}
 
 	if scenario == "" {
@@ -642,8 +704,6 @@ func (cr *CausalReasoner) GenerateCounterfactual(graphID, scenario string, chang
 	cr.mu.Lock()
 	defer cr.mu.Unlock()
 
-	cr.counter++
-
 	// Predict outcomes based on changes
 	outcomes := make(map[string]string)
 
@@ -677,7 +737,7 @@ func (cr *CausalReasoner) GenerateCounterfactual(graphID, scenario string, chang
 	plausibility := cr.estimateCounterfactualPlausibility(changes, outcomes)
 
 	counterfactual := &types.Counterfactual{
-		ID:           fmt.Sprintf("counterfactual-%d", cr.counter),
+		ID:           cr.nextID("counterfactual"),
 		GraphID:      graphID,
 		Scenario:     scenario,
 		Changes:      changes,
@@ -687,6 +747,12 @@ func (cr *CausalReasoner) GenerateCounterfactual(graphID, scenario string, chang
 		CreatedAt:    time.Now(),
 	}
 
+	if cr.storage != nil {
+		if err := cr.storage.StoreCounterfactual(counterfactual); err != nil {
+			return nil, fmt.Errorf("failed to persist counterfactual: %w", err)
+		}
+	}
+
 	return counterfactual, nil
 }
 
@@ -710,15 +776,52 @@ func (cr *CausalReasoner) estimateCounterfactualPlausibility(changes, outcomes m
 
 // GetGraph retrieves a causal graph by ID
 func (cr *CausalReasoner) GetGraph(graphID string) (*types.CausalGraph, error) {
+	return cr.lookupGraph(graphID)
+}
+
+// ListGraphs returns summaries of known causal graphs, newest first.
+// With storage attached this includes graphs from earlier sessions.
+func (cr *CausalReasoner) ListGraphs(limit, offset int) ([]*types.CausalGraphSummary, error) {
 	cr.mu.RLock()
-	defer cr.mu.RUnlock()
+	store := cr.storage
+	cr.mu.RUnlock()
 
-	graph, exists := cr.graphs[graphID]
-	if !exists {
-		return nil, fmt.Errorf("graph not found: %s", graphID)
+	if store != nil {
+		return store.ListCausalGraphs(limit, offset)
 	}
 
-	return graph, nil
+	cr.mu.RLock()
+	summaries := make([]*types.CausalGraphSummary, 0, len(cr.graphs))
+	for _, graph := range cr.graphs {
+		summaries = append(summaries, &types.CausalGraphSummary{
+			ID:            graph.ID,
+			Description:   graph.Description,
+			VariableCount: len(graph.Variables),
+			LinkCount:     len(graph.Links),
+			CreatedAt:     graph.CreatedAt,
+		})
+	}
+	cr.mu.RUnlock()
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].CreatedAt.Equal(summaries[j].CreatedAt) {
+			return summaries[i].ID > summaries[j].ID
+		}
+		return summaries[i].CreatedAt.After(summaries[j].CreatedAt)
+	})
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(summaries) {
+		return []*types.CausalGraphSummary{}, nil
+	}
+	summaries = summaries[offset:]
+	if limit > 0 && limit < len(summaries) {
+		summaries = summaries[:limit]
+	}
+
+	return summaries, nil
 }
 
 // AnalyzeCorrelationVsCausation distinguishes correlation from causation
@@ -1,6 +1,7 @@
 package reasoning
 
 import (
+	"fmt"
 	"testing"
 	"time"
 	"unified-thinking/internal/types"
@@ -1236,3 +1237,126 @@ func TestCalculateInterventionConfidence(t *testing.T) {
 		})
 	}
 }
+
+// memoryCausalStorage is an in-memory CausalStorage used to simulate restarts
+type memoryCausalStorage struct {
+	graphs          map[string]*types.CausalGraph
+	interventions   map[string]*types.CausalIntervention
+	counterfactuals map[string]*types.Counterfactual
+}
+
+func newMemoryCausalStorage() *memoryCausalStorage {
+	return &memoryCausalStorage{
+		graphs:          make(map[string]*types.CausalGraph),
+		interventions:   make(map[string]*types.CausalIntervention),
+		counterfactuals: make(map[string]*types.Counterfactual),
+	}
+}
+
+func (m *memoryCausalStorage) StoreCausalGraph(graph *types.CausalGraph) error {
+	m.graphs[graph.ID] = graph
+	return nil
+}
+
+func (m *memoryCausalStorage) GetCausalGraph(id string) (*types.CausalGraph, error) {
+	graph, ok := m.graphs[id]
+	if !ok {
+		return nil, fmt.Errorf("causal graph not found: %s", id)
+	}
+	return graph, nil
+}
+
+func (m *memoryCausalStorage) ListCausalGraphs(limit, offset int) ([]*types.CausalGraphSummary, error) {
+	summaries := make([]*types.CausalGraphSummary, 0, len(m.graphs))
+	for _, graph := range m.graphs {
+		summaries = append(summaries, &types.CausalGraphSummary{ID: graph.ID, Description: graph.Description})
+	}
+	return summaries, nil
+}
+
+func (m *memoryCausalStorage) StoreCausalIntervention(intervention *types.CausalIntervention) error {
+	m.interventions[intervention.ID] = intervention
+	return nil
+}
+
+func (m *memoryCausalStorage) StoreCounterfactual(counterfactual *types.Counterfactual) error {
+	m.counterfactuals[counterfactual.ID] = counterfactual
+	return nil
+}
+
+func TestCausalReasoner_PersistsAcrossRestart(t *testing.T) {
+	store := newMemoryCausalStorage()
+
+	first := NewCausalReasoner()
+	first.SetStorage(store)
+
+	graph, err := first.BuildCausalGraph("Marketing", []string{"Marketing spend increases sales"})
+	if err != nil {
+		t.Fatalf("BuildCausalGraph() error = %v", err)
+	}
+	if _, ok := store.graphs[graph.ID]; !ok {
+		t.Fatal("graph was not written through to storage")
+	}
+
+	// A fresh reasoner simulates a server restart
+	second := NewCausalReasoner()
+	second.SetStorage(store)
+
+	got, err := second.GetGraph(graph.ID)
+	if err != nil {
+		t.Fatalf("GetGraph() after restart error = %v", err)
+	}
+	if got.Description != "Marketing" {
+		t.Errorf("Description = %q, want Marketing", got.Description)
+	}
+
+	intervention, err := second.SimulateIntervention(graph.ID, "marketing spend", "increase")
+	if err != nil {
+		t.Fatalf("SimulateIntervention() after restart error = %v", err)
+	}
+	if _, ok := store.interventions[intervention.ID]; !ok {
+		t.Error("intervention was not written through to storage")
+	}
+
+	counterfactual, err := second.GenerateCounterfactual(graph.ID, "Double spend", map[string]string{"marketing spend": "increase"})
+	if err != nil {
+		t.Fatalf("GenerateCounterfactual() after restart error = %v", err)
+	}
+	if _, ok := store.counterfactuals[counterfactual.ID]; !ok {
+		t.Error("counterfactual was not written through to storage")
+	}
+
+	summaries, err := second.ListGraphs(10, 0)
+	if err != nil {
+		t.Fatalf("ListGraphs() error = %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].ID != graph.ID {
+		t.Errorf("ListGraphs() = %+v, want the stored graph", summaries)
+	}
+}
+
+func TestCausalReasoner_ListGraphsInMemory(t *testing.T) {
+	cr := NewCausalReasoner()
+
+	for _, desc := range []string{"First", "Second", "Third"} {
+		if _, err := cr.BuildCausalGraph(desc, []string{"A causes B"}); err != nil {
+			t.Fatalf("BuildCausalGraph() error = %v", err)
+		}
+	}
+
+	all, err := cr.ListGraphs(0, 0)
+	if err != nil {
+		t.Fatalf("ListGraphs() error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("ListGraphs() returned %d, want 3", len(all))
+	}
+
+	page, _ := cr.ListGraphs(2, 2)
+	if len(page) != 1 {
+		t.Errorf("ListGraphs(2, 2) returned %d, want 1", len(page))
+	}
+	if page, _ := cr.ListGraphs(10, 5); len(page) != 0 {
+		t.Errorf("ListGraphs(10, 5) returned %d, want 0", len(page))
+	}
+}
@@ -2,18 +2,44 @@ package reasoning
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"unified-thinking/internal/types"
 )
 
+// DecisionStorage persists decisions so they survive restarts
+type DecisionStorage interface {
+	StoreDecision(decision *types.Decision) error
+	GetDecision(id string) (*types.Decision, error)
+	ListDecisions(limit, offset int) ([]*types.Decision, error)
+	DeleteDecision(id string) error
+}
+
+// DecompositionStorage persists problem decompositions so they survive restarts
+type DecompositionStorage interface {
+	StoreDecomposition(decomposition *types.ProblemDecomposition) error
+	GetDecomposition(id string) (*types.ProblemDecomposition, error)
+	ListDecompositions(limit, offset int) ([]*types.ProblemDecomposition, error)
+}
+
+// decisionIDs and decompositionIDs number the records of every decision
+// maker and problem decomposer, so instances for different workspaces sharing
+// one database never reuse an ID
+var (
+	decisionIDs      atomic.Int64
+	decompositionIDs atomic.Int64
+)
+
 // DecisionMaker provides structured decision-making frameworks
 type DecisionMaker struct {
 	mu        sync.RWMutex
 	counter   int
 	decisions map[string]*types.Decision // Storage for created decisions
+	storage   DecisionStorage            // Optional persistent storage
 }
 
 // NewDecisionMaker creates a new decision maker
@@ -23,6 +49,32 @@ func NewDecisionMaker() *DecisionMaker {
 	}
 }
 
+// SetStorage enables write-through persistence. Decisions not held in memory
+// are loaded from storage on first use.
+func (dm *DecisionMaker) SetStorage(store DecisionStorage) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.storage = store
+}
+
+// loadDecision returns a decision from memory, falling back to storage.
+// Caller must hold dm.mu for writing.
+func (dm *DecisionMaker) loadDecision(decisionID string) (*types.Decision, error) {
+	if decision, exists := dm.decisions[decisionID]; exists {
+		return decision, nil
+	}
+	if dm.storage == nil {
+		return nil, fmt.Errorf("decision not found: %s", decisionID)
+	}
+
+	decision, err := dm.storage.GetDecision(decisionID)
+	if err != nil {
+		return nil, fmt.Errorf("decision not found: %s", decisionID)
+	}
+	dm.decisions[decisionID] = decision
+	return decision, nil
+}
+
 // CreateDecision creates a structured decision framework
 func (dm *DecisionMaker) CreateDecision(question string, options []*types.DecisionOption, criteria []*types.DecisionCriterion) (*types.Decision, error) {
 	dm.mu.Lock()
@@ -78,7 +130,7 @@ func (dm *DecisionMaker) CreateDecision(question string, options []*types.Decisi
 	confidence := dm.calculateDecisionConfidence(options, bestOption)
 
 	decision := &types.Decision{
-		ID:             fmt.Sprintf("decision-%d", dm.counter),
+		ID:             fmt.Sprintf("decision-%d-%d", time.Now().Unix(), decisionIDs.Add(1)),
 		Question:       question,
 		Options:        options,
 		Criteria:       criteria,
@@ -89,6 +141,11 @@ func (dm *DecisionMaker) CreateDecision(question string, options []*types.Decisi
 	}
 
 	// Store the decision for future retrieval and re-evaluation
+	if dm.storage != nil {
+		if err := dm.storage.StoreDecision(decision); err != nil {
+			return nil, fmt.Errorf("failed to persist decision: %w", err)
+		}
+	}
 	dm.decisions[decision.ID] = decision
 
 	return decision, nil
@@ -96,26 +153,37 @@ func (dm *DecisionMaker) CreateDecision(question string, options []*types.Decisi
 
 // GetDecision retrieves a decision by ID
 func (dm *DecisionMaker) GetDecision(decisionID string) (*types.Decision, error) {
-	dm.mu.RLock()
-	defer dm.mu.RUnlock()
-
-	decision, exists := dm.decisions[decisionID]
-	if !exists {
-		return nil, fmt.Errorf("decision not found: %s", decisionID)
-	}
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
 
-	return decision, nil
+	return dm.loadDecision(decisionID)
 }
 
-// ListDecisions returns all stored decisions
+// ListDecisions returns all stored decisions, newest first. With storage
+// attached this includes decisions from earlier sessions.
 func (dm *DecisionMaker) ListDecisions() []*types.Decision {
 	dm.mu.RLock()
 	defer dm.mu.RUnlock()
 
+	if dm.storage != nil {
+		if decisions, err := dm.storage.ListDecisions(0, 0); err == nil {
+			// Prefer in-memory instances so callers see unsaved mutations
+			for i, d := range decisions {
+				if cached, ok := dm.decisions[d.ID]; ok {
+					decisions[i] = cached
+				}
+			}
+			return decisions
+		}
+	}
+
 	decisions := make([]*types.Decision, 0, len(dm.decisions))
 	for _, d := range dm.decisions {
 		decisions = append(decisions, d)
 	}
+	sort.Slice(decisions, func(i, j int) bool {
+		return decisions[i].CreatedAt.After(decisions[j].CreatedAt)
+	})
 
 	return decisions
 }
@@ -126,9 +194,9 @@ func (dm *DecisionMaker) RecalculateDecision(decisionID string, scoreAdjustments
 	dm.mu.Lock()
 	defer dm.mu.Unlock()
 
-	decision, exists := dm.decisions[decisionID]
-	if !exists {
-		return nil, fmt.Errorf("decision not found: %s", decisionID)
+	decision, err := dm.loadDecision(decisionID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Apply score adjustments to options
@@ -188,11 +256,20 @@ func (dm *DecisionMaker) RecalculateDecision(decisionID string, scoreAdjustments
 	}
 	decision.Metadata["last_recalculated"] = time.Now()
 	recalcCount := 0
-	if count, ok := decision.Metadata["recalculation_count"].(int); ok {
+	switch count := decision.Metadata["recalculation_count"].(type) {
+	case int:
 		recalcCount = count
+	case float64: // Decoded from persisted JSON metadata
+		recalcCount = int(count)
 	}
 	decision.Metadata["recalculation_count"] = recalcCount + 1
 
+	if dm.storage != nil {
+		if err := dm.storage.StoreDecision(decision); err != nil {
+			return nil, fmt.Errorf("failed to persist decision: %w", err)
+		}
+	}
+
 	return decision, nil
 }
 
@@ -201,7 +278,12 @@ func (dm *DecisionMaker) DeleteDecision(decisionID string) error {
 	dm.mu.Lock()
 	defer dm.mu.Unlock()
 
-	if _, exists := dm.decisions[decisionID]; !exists {
+	_, exists := dm.decisions[decisionID]
+	if dm.storage != nil {
+		if err := dm.storage.DeleteDecision(decisionID); err != nil && !exists {
+			return err
+		}
+	} else if !exists {
 		return fmt.Errorf("decision not found: %s", decisionID)
 	}
 
@@ -287,13 +369,91 @@ func (dm *DecisionMaker) AddCriterion(decision *types.Decision, name, descriptio
 
 // ProblemDecomposer breaks down complex problems into subproblems
 type ProblemDecomposer struct {
-	mu      sync.RWMutex
-	counter int
+	mu             sync.RWMutex
+	counter        int
+	decompositions map[string]*types.ProblemDecomposition
+	storage        DecompositionStorage // Optional persistent storage
 }
 
 // NewProblemDecomposer creates a new problem decomposer
 func NewProblemDecomposer() *ProblemDecomposer {
-	return &ProblemDecomposer{}
+	return &ProblemDecomposer{
+		decompositions: make(map[string]*types.ProblemDecomposition),
+	}
+}
+
+// SetStorage enables write-through persistence. Decompositions not held in
+// memory are loaded from storage on first use.
+func (pd *ProblemDecomposer) SetStorage(store DecompositionStorage) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.storage = store
+}
+
+// SaveDecomposition records a decomposition produced elsewhere (for example
+// by the LLM decomposer) so it can be listed and retrieved later
+func (pd *ProblemDecomposer) SaveDecomposition(decomposition *types.ProblemDecomposition) error {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	return pd.saveDecomposition(decomposition)
+}
+
+// saveDecomposition caches and persists a decomposition. Caller must hold pd.mu.
+func (pd *ProblemDecomposer) saveDecomposition(decomposition *types.ProblemDecomposition) error {
+	if pd.storage != nil {
+		if err := pd.storage.StoreDecomposition(decomposition); err != nil {
+			return fmt.Errorf("failed to persist decomposition: %w", err)
+		}
+	}
+	if pd.decompositions == nil {
+		pd.decompositions = make(map[string]*types.ProblemDecomposition)
+	}
+	pd.decompositions[decomposition.ID] = decomposition
+	return nil
+}
+
+// GetDecomposition retrieves a decomposition by ID
+func (pd *ProblemDecomposer) GetDecomposition(decompositionID string) (*types.ProblemDecomposition, error) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	if decomposition, exists := pd.decompositions[decompositionID]; exists {
+		return decomposition, nil
+	}
+	if pd.storage == nil {
+		return nil, fmt.Errorf("decomposition not found: %s", decompositionID)
+	}
+
+	decomposition, err := pd.storage.GetDecomposition(decompositionID)
+	if err != nil {
+		return nil, fmt.Errorf("decomposition not found: %s", decompositionID)
+	}
+	if pd.decompositions == nil {
+		pd.decompositions = make(map[string]*types.ProblemDecomposition)
+	}
+	pd.decompositions[decompositionID] = decomposition
+	return decomposition, nil
+}
+
+// ListDecompositions returns decompositions, newest first. With storage
+// attached this includes decompositions from earlier sessions.
+func (pd *ProblemDecomposer) ListDecompositions(limit, offset int) ([]*types.ProblemDecomposition, error) {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	if pd.storage != nil {
+		return pd.storage.ListDecompositions(limit, offset)
+	}
+
+	decompositions := make([]*types.ProblemDecomposition, 0, len(pd.decompositions))
+	for _, d := range pd.decompositions {
+		decompositions = append(decompositions, d)
+	}
+	sort.Slice(decompositions, func(i, j int) bool {
+		return decompositions[i].CreatedAt.After(decompositions[j].CreatedAt)
+	})
+
+	return paginate(decompositions, limit, offset), nil
 }
 
 // DecomposeProblem breaks down a problem into manageable subproblems
@@ -309,7 +469,7 @@ func (pd *ProblemDecomposer) DecomposeProblem(problem string) (*types.ProblemDec
 	solutionPath := pd.determineSolutionPath(subproblems, dependencies)
 
 	decomposition := &types.ProblemDecomposition{
-		ID:           fmt.Sprintf("decomposition-%d", pd.counter),
+		ID:           fmt.Sprintf("decomposition-%d-%d", time.Now().Unix(), decompositionIDs.Add(1)),
 		Problem:      problem,
 		Subproblems:  subproblems,
 		Dependencies: dependencies,
@@ -318,6 +478,10 @@ func (pd *ProblemDecomposer) DecomposeProblem(problem string) (*types.ProblemDec
 		CreatedAt:    time.Now(),
 	}
 
+	if err := pd.saveDecomposition(decomposition); err != nil {
+		return nil, err
+	}
+
 	return decomposition, nil
 }
 
@@ -682,8 +846,29 @@ func (pd *ProblemDecomposer) UpdateSubproblemStatus(decomposition *types.Problem
 			if solution != "" {
 				sp.Solution = solution
 			}
+			if pd.storage != nil {
+				if err := pd.storage.StoreDecomposition(decomposition); err != nil {
+					return fmt.Errorf("failed to persist decomposition: %w", err)
+				}
+			}
 			return nil
 		}
 	}
 	return fmt.Errorf("subproblem not found: %s", subproblemID)
 }
+
+// paginate applies limit and offset to a slice. A limit of zero or less
+// returns everything after offset.
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -1,6 +1,8 @@
 package reasoning
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"unified-thinking/internal/types"
@@ -502,3 +504,210 @@ func TestDecisionConcurrency(t *testing.T) {
 		<-done
 	}
 }
+
+// jsonDecisionStorage round-trips records through JSON like the SQLite backend
+type jsonDecisionStorage struct {
+	decisions      map[string][]byte
+	decompositions map[string][]byte
+}
+
+func newJSONDecisionStorage() *jsonDecisionStorage {
+	return &jsonDecisionStorage{
+		decisions:      make(map[string][]byte),
+		decompositions: make(map[string][]byte),
+	}
+}
+
+func (s *jsonDecisionStorage) StoreDecision(decision *types.Decision) error {
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+	s.decisions[decision.ID] = data
+	return nil
+}
+
+func (s *jsonDecisionStorage) GetDecision(id string) (*types.Decision, error) {
+	data, ok := s.decisions[id]
+	if !ok {
+		return nil, fmt.Errorf("decision not found: %s", id)
+	}
+	var decision types.Decision
+	err := json.Unmarshal(data, &decision)
+	return &decision, err
+}
+
+func (s *jsonDecisionStorage) ListDecisions(limit, offset int) ([]*types.Decision, error) {
+	decisions := make([]*types.Decision, 0, len(s.decisions))
+	for id := range s.decisions {
+		d, err := s.GetDecision(id)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions, nil
+}
+
+func (s *jsonDecisionStorage) DeleteDecision(id string) error {
+	if _, ok := s.decisions[id]; !ok {
+		return fmt.Errorf("decision not found: %s", id)
+	}
+	delete(s.decisions, id)
+	return nil
+}
+
+func (s *jsonDecisionStorage) StoreDecomposition(decomposition *types.ProblemDecomposition) error {
+	data, err := json.Marshal(decomposition)
+	if err != nil {
+		return err
+	}
+	s.decompositions[decomposition.ID] = data
+	return nil
+}
+
+func (s *jsonDecisionStorage) GetDecomposition(id string) (*types.ProblemDecomposition, error) {
+	data, ok := s.decompositions[id]
+	if !ok {
+		return nil, fmt.Errorf("decomposition not found: %s", id)
+	}
+	var decomposition types.ProblemDecomposition
+	err := json.Unmarshal(data, &decomposition)
+	return &decomposition, err
+}
+
+func (s *jsonDecisionStorage) ListDecompositions(limit, offset int) ([]*types.ProblemDecomposition, error) {
+	decompositions := make([]*types.ProblemDecomposition, 0, len(s.decompositions))
+	for id := range s.decompositions {
+		d, err := s.GetDecomposition(id)
+		if err != nil {
+			return nil, err
+		}
+		decompositions = append(decompositions, d)
+	}
+	return paginate(decompositions, limit, offset), nil
+}
+
+func TestDecisionMaker_PersistsAcrossRestart(t *testing.T) {
+	store := newJSONDecisionStorage()
+
+	first := NewDecisionMaker()
+	first.SetStorage(store)
+
+	decision, err := first.CreateDecision("Which cache?",
+		[]*types.DecisionOption{
+			{ID: "redis", Name: "Redis", Scores: map[string]float64{"speed": 0.9}},
+			{ID: "memcached", Name: "Memcached", Scores: map[string]float64{"speed": 0.7}},
+		},
+		[]*types.DecisionCriterion{{ID: "speed", Name: "Speed", Weight: 1.0, Maximize: true}},
+	)
+	if err != nil {
+		t.Fatalf("CreateDecision() error = %v", err)
+	}
+
+	// A fresh decision maker simulates a server restart
+	second := NewDecisionMaker()
+	second.SetStorage(store)
+
+	if got := second.ListDecisions(); len(got) != 1 || got[0].ID != decision.ID {
+		t.Fatalf("ListDecisions() after restart = %v, want [%s]", got, decision.ID)
+	}
+
+	for i := 1; i <= 2; i++ {
+		recalculated, err := second.RecalculateDecision(decision.ID, map[string]map[string]float64{
+			"memcached": {"speed": 0.2},
+		})
+		if err != nil {
+			t.Fatalf("RecalculateDecision() error = %v", err)
+		}
+		if recalculated.Metadata["recalculation_count"] != i {
+			t.Errorf("recalculation_count = %v, want %d", recalculated.Metadata["recalculation_count"], i)
+		}
+	}
+
+	// The recalculation must be visible to yet another process
+	third := NewDecisionMaker()
+	third.SetStorage(store)
+	reloaded, err := third.GetDecision(decision.ID)
+	if err != nil {
+		t.Fatalf("GetDecision() error = %v", err)
+	}
+	if score := reloaded.Options[1].Scores["speed"]; score < 0.99 {
+		t.Errorf("persisted memcached speed = %.2f, want 1.0", score)
+	}
+	if count, _ := reloaded.Metadata["recalculation_count"].(float64); count != 2 {
+		t.Errorf("persisted recalculation_count = %v, want 2", reloaded.Metadata["recalculation_count"])
+	}
+
+	if err := third.DeleteDecision(decision.ID); err != nil {
+		t.Fatalf("DeleteDecision() error = %v", err)
+	}
+	fourth := NewDecisionMaker()
+	fourth.SetStorage(store)
+	if _, err := fourth.GetDecision(decision.ID); err == nil {
+		t.Error("deleted decision should not be loadable")
+	}
+}
+
+func TestProblemDecomposer_PersistsAcrossRestart(t *testing.T) {
+	store := newJSONDecisionStorage()
+
+	first := NewProblemDecomposer()
+	first.SetStorage(store)
+
+	decomposition, err := first.DecomposeProblemWithDomain("Debug the flaky payment test", nil)
+	if err != nil {
+		t.Fatalf("DecomposeProblemWithDomain() error = %v", err)
+	}
+	if len(decomposition.Subproblems) == 0 {
+		t.Fatal("expected subproblems")
+	}
+
+	second := NewProblemDecomposer()
+	second.SetStorage(store)
+
+	loaded, err := second.GetDecomposition(decomposition.ID)
+	if err != nil {
+		t.Fatalf("GetDecomposition() after restart error = %v", err)
+	}
+
+	subproblemID := loaded.Subproblems[0].ID
+	if err := second.UpdateSubproblemStatus(loaded, subproblemID, "solved", "done"); err != nil {
+		t.Fatalf("UpdateSubproblemStatus() error = %v", err)
+	}
+
+	reloaded, err := store.GetDecomposition(decomposition.ID)
+	if err != nil {
+		t.Fatalf("store.GetDecomposition() error = %v", err)
+	}
+	if reloaded.Subproblems[0].Status != "solved" {
+		t.Errorf("persisted status = %q, want solved", reloaded.Subproblems[0].Status)
+	}
+
+	list, err := second.ListDecompositions(10, 0)
+	if err != nil || len(list) != 1 {
+		t.Errorf("ListDecompositions() = %d, %v; want 1", len(list), err)
+	}
+}
+
+func TestProblemDecomposer_ListInMemory(t *testing.T) {
+	pd := NewProblemDecomposer()
+
+	if _, err := pd.DecomposeProblem("Improve API latency"); err != nil {
+		t.Fatalf("DecomposeProblem() error = %v", err)
+	}
+	if _, err := pd.DecomposeProblemWithDomain("Design a billing service", nil); err != nil {
+		t.Fatalf("DecomposeProblemWithDomain() error = %v", err)
+	}
+
+	list, err := pd.ListDecompositions(0, 0)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListDecompositions() = %d, %v; want 2", len(list), err)
+	}
+	if _, err := pd.GetDecomposition(list[0].ID); err != nil {
+		t.Errorf("GetDecomposition() error = %v", err)
+	}
+	if _, err := pd.GetDecomposition("missing"); err == nil {
+		t.Error("expected error for missing decomposition")
+	}
+}
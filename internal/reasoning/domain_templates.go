@@ -1100,7 +1100,7 @@ func (pd *ProblemDecomposer) DecomposeProblemWithDomain(problem string, explicit
 	solutionPath := pd.determineSolutionPath(subproblems, dependencies)
 
 	decomposition := &types.ProblemDecomposition{
-		ID:           fmt.Sprintf("decomposition-%d", pd.counter),
+		ID:           fmt.Sprintf("decomposition-%d-%d", time.Now().Unix(), decompositionIDs.Add(1)),
 		Problem:      problem,
 		Subproblems:  subproblems,
 		Dependencies: dependencies,
@@ -1120,5 +1120,9 @@ func (pd *ProblemDecomposer) DecomposeProblemWithDomain(problem string, explicit
 		CreatedAt: time.Now(),
 	}
 
+	if err := pd.saveDecomposition(decomposition); err != nil {
+		return nil, err
+	}
+
 	return decomposition, nil
 }
@@ -7,13 +7,26 @@ import (
 	"log"
 	"math"
 	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"unified-thinking/internal/metrics"
 	"unified-thinking/internal/types"
 )
 
+// BeliefStorage persists probabilistic beliefs so they survive restarts
+type BeliefStorage interface {
+	StoreBelief(belief *types.ProbabilisticBelief) error
+	GetBelief(id string) (*types.ProbabilisticBelief, error)
+	ListBeliefs(limit, offset int) ([]*types.ProbabilisticBelief, error)
+}
+
+// beliefIDs numbers the beliefs of every probabilistic reasoner, so reasoners
+// for different workspaces sharing one database never reuse an ID
+var beliefIDs atomic.Int64
+
 // ProbabilisticReasoner performs Bayesian inference and probabilistic reasoning
 type ProbabilisticReasoner struct {
 	mu        sync.RWMutex
@@ -21,6 +34,7 @@ type ProbabilisticReasoner struct {
 	counter   int
 	metrics   *metrics.ProbabilisticMetrics
 	estimator LikelihoodEstimator
+	storage   BeliefStorage // Optional persistent storage
 }
 
 // NewProbabilisticReasoner creates a new probabilistic reasoner with default settings
@@ -44,6 +58,43 @@ func NewProbabilisticReasonerWithEstimator(estimator LikelihoodEstimator) *Proba
 	}
 }
 
+// SetStorage enables write-through persistence. Beliefs not held in memory
+// are loaded from storage on first use.
+func (pr *ProbabilisticReasoner) SetStorage(store BeliefStorage) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.storage = store
+}
+
+// loadBelief returns a belief from memory, falling back to storage.
+// Caller must hold pr.mu for writing.
+func (pr *ProbabilisticReasoner) loadBelief(beliefID string) (*types.ProbabilisticBelief, error) {
+	if belief, exists := pr.beliefs[beliefID]; exists {
+		return belief, nil
+	}
+	if pr.storage == nil {
+		return nil, fmt.Errorf("belief not found: %s", beliefID)
+	}
+
+	belief, err := pr.storage.GetBelief(beliefID)
+	if err != nil {
+		return nil, fmt.Errorf("belief not found: %s", beliefID)
+	}
+	pr.beliefs[beliefID] = belief
+	return belief, nil
+}
+
+// persistBelief writes a belief through to storage. Caller must hold pr.mu.
+func (pr *ProbabilisticReasoner) persistBelief(belief *types.ProbabilisticBelief) error {
+	if pr.storage == nil {
+		return nil
+	}
+	if err := pr.storage.StoreBelief(belief); err != nil {
+		return fmt.Errorf("failed to persist belief: %w", err)
+	}
+	return nil
+}
+
 // CreateBelief creates a new probabilistic belief with prior probability
 func (pr *ProbabilisticReasoner) CreateBelief(statement string, priorProb float64) (*types.ProbabilisticBelief, error) {
 	if priorProb < 0 || priorProb > 1 {
@@ -55,7 +106,7 @@ func (pr *ProbabilisticReasoner) CreateBelief(statement string, priorProb float6
 
 	pr.counter++
 	belief := &types.ProbabilisticBelief{
-		ID:          fmt.Sprintf("belief-%d", pr.counter),
+		ID:          fmt.Sprintf("belief-%d-%d", time.Now().Unix(), beliefIDs.Add(1)),
 		Statement:   statement,
 		Probability: priorProb,
 		PriorProb:   priorProb,
@@ -64,6 +115,9 @@ func (pr *ProbabilisticReasoner) CreateBelief(statement string, priorProb float6
 		Metadata:    map[string]interface{}{},
 	}
 
+	if err := pr.persistBelief(belief); err != nil {
+		return nil, err
+	}
 	pr.beliefs[belief.ID] = belief
 
 	if pr.metrics != nil {
@@ -180,9 +234,9 @@ func (pr *ProbabilisticReasoner) UpdateBeliefFull(beliefID string, evidenceID st
 	pr.mu.Lock()
 	defer pr.mu.Unlock()
 
-	belief, exists := pr.beliefs[beliefID]
-	if !exists {
-		return nil, fmt.Errorf("belief not found: %s", beliefID)
+	belief, err := pr.loadBelief(beliefID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate likelihood parameters
@@ -223,6 +277,10 @@ func (pr *ProbabilisticReasoner) UpdateBeliefFull(beliefID string, evidenceID st
 			pr.metrics.RecordUninformative()
 		}
 
+		if err := pr.persistBelief(belief); err != nil {
+			return nil, err
+		}
+
 		return belief, nil
 	}
 
@@ -251,6 +309,10 @@ func (pr *ProbabilisticReasoner) UpdateBeliefFull(beliefID string, evidenceID st
 		pr.metrics.RecordUpdate()
 	}
 
+	if err := pr.persistBelief(belief); err != nil {
+		return nil, err
+	}
+
 	return belief, nil
 }
 
@@ -258,9 +320,11 @@ func (pr *ProbabilisticReasoner) UpdateBeliefFull(beliefID string, evidenceID st
 // This method estimates both P(E|H) and P(E|¬H) from evidence quality using
 // a configurable LikelihoodEstimator.
 func (pr *ProbabilisticReasoner) UpdateBeliefWithEvidence(beliefID string, evidence *types.Evidence) (*types.ProbabilisticBelief, error) {
-	_, exists := pr.beliefs[beliefID]
-	if !exists {
-		return nil, fmt.Errorf("belief not found: %s", beliefID)
+	pr.mu.Lock()
+	_, err := pr.loadBelief(beliefID)
+	pr.mu.Unlock()
+	if err != nil {
+		return nil, err
 	}
 
 	// Use the likelihood estimator to convert evidence quality to conditional probabilities
@@ -278,14 +342,31 @@ func (pr *ProbabilisticReasoner) UpdateBeliefWithEvidence(beliefID string, evide
 
 // GetBelief retrieves a belief by ID
 func (pr *ProbabilisticReasoner) GetBelief(beliefID string) (*types.ProbabilisticBelief, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	return pr.loadBelief(beliefID)
+}
+
+// ListBeliefs returns beliefs, most recently updated first. With storage
+// attached this includes beliefs from earlier sessions.
+func (pr *ProbabilisticReasoner) ListBeliefs(limit, offset int) ([]*types.ProbabilisticBelief, error) {
 	pr.mu.RLock()
 	defer pr.mu.RUnlock()
 
-	belief, exists := pr.beliefs[beliefID]
-	if !exists {
-		return nil, fmt.Errorf("belief not found: %s", beliefID)
+	if pr.storage != nil {
+		return pr.storage.ListBeliefs(limit, offset)
 	}
-	return belief, nil
+
+	beliefs := make([]*types.ProbabilisticBelief, 0, len(pr.beliefs))
+	for _, b := range pr.beliefs {
+		beliefs = append(beliefs, b)
+	}
+	sort.Slice(beliefs, func(i, j int) bool {
+		return beliefs[i].UpdatedAt.After(beliefs[j].UpdatedAt)
+	})
+
+	return paginate(beliefs, limit, offset), nil
 }
 
 // CombineBeliefs combines multiple independent beliefs using probability theory
@@ -295,8 +376,8 @@ func (pr *ProbabilisticReasoner) CombineBeliefs(beliefIDs []string, operation st
 		return 0, fmt.Errorf("no beliefs provided")
 	}
 
-	pr.mu.RLock()
-	defer pr.mu.RUnlock()
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
 
 	var result float64
 
@@ -304,9 +385,9 @@ func (pr *ProbabilisticReasoner) CombineBeliefs(beliefIDs []string, operation st
 	case "and": // P(A and B) = P(A) * P(B) for independent events
 		result = 1.0
 		for _, id := range beliefIDs {
-			belief, exists := pr.beliefs[id]
-			if !exists {
-				return 0, fmt.Errorf("belief not found: %s", id)
+			belief, err := pr.loadBelief(id)
+			if err != nil {
+				return 0, err
 			}
 			result *= belief.Probability
 		}
@@ -314,9 +395,9 @@ func (pr *ProbabilisticReasoner) CombineBeliefs(beliefIDs []string, operation st
 	case "or": // P(A or B) = P(A) + P(B) - P(A)*P(B) for independent events
 		result = 0.0
 		for _, id := range beliefIDs {
-			belief, exists := pr.beliefs[id]
-			if !exists {
-				return 0, fmt.Errorf("belief not found: %s", id)
+			belief, err := pr.loadBelief(id)
+			if err != nil {
+				return 0, err
 			}
 			// P(A or B) = 1 - P(not A and not B) = 1 - (1-P(A))*(1-P(B))
 			result = 1 - (1-result)*(1-belief.Probability)
@@ -1,6 +1,7 @@
 package reasoning
 
 import (
+	"fmt"
 	"testing"
 
 	"unified-thinking/internal/types"
@@ -544,3 +545,93 @@ func TestEstimateConfidence_EdgeCases(t *testing.T) {
 		t.Errorf("Expected 0.5 for zero score evidence, got %.3f", confidence)
 	}
 }
+
+// memoryBeliefStorage is an in-memory BeliefStorage used to simulate restarts
+type memoryBeliefStorage struct {
+	beliefs map[string]types.ProbabilisticBelief
+}
+
+func (m *memoryBeliefStorage) StoreBelief(belief *types.ProbabilisticBelief) error {
+	stored := *belief
+	stored.Evidence = append([]string(nil), belief.Evidence...)
+	m.beliefs[belief.ID] = stored
+	return nil
+}
+
+func (m *memoryBeliefStorage) GetBelief(id string) (*types.ProbabilisticBelief, error) {
+	belief, ok := m.beliefs[id]
+	if !ok {
+		return nil, fmt.Errorf("belief not found: %s", id)
+	}
+	return &belief, nil
+}
+
+func (m *memoryBeliefStorage) ListBeliefs(limit, offset int) ([]*types.ProbabilisticBelief, error) {
+	beliefs := make([]*types.ProbabilisticBelief, 0, len(m.beliefs))
+	for id := range m.beliefs {
+		b, _ := m.GetBelief(id)
+		beliefs = append(beliefs, b)
+	}
+	return paginate(beliefs, limit, offset), nil
+}
+
+func TestProbabilisticReasoner_PersistsAcrossRestart(t *testing.T) {
+	store := &memoryBeliefStorage{beliefs: make(map[string]types.ProbabilisticBelief)}
+
+	first := NewProbabilisticReasoner()
+	first.SetStorage(store)
+
+	belief, err := first.CreateBelief("The rollout is safe", 0.5)
+	if err != nil {
+		t.Fatalf("CreateBelief() error = %v", err)
+	}
+
+	// A fresh reasoner simulates a server restart
+	second := NewProbabilisticReasoner()
+	second.SetStorage(store)
+
+	updated, err := second.UpdateBeliefFull(belief.ID, "canary-green", 0.9, 0.3)
+	if err != nil {
+		t.Fatalf("UpdateBeliefFull() after restart error = %v", err)
+	}
+	if updated.Probability <= 0.5 {
+		t.Errorf("posterior = %.3f, want > 0.5", updated.Probability)
+	}
+
+	persisted, _ := store.GetBelief(belief.ID)
+	if persisted.Probability != updated.Probability || len(persisted.Evidence) != 1 {
+		t.Errorf("persisted belief = %+v, want posterior %.3f with 1 evidence", persisted, updated.Probability)
+	}
+
+	third := NewProbabilisticReasoner()
+	third.SetStorage(store)
+	combined, err := third.CombineBeliefs([]string{belief.ID}, "and")
+	if err != nil {
+		t.Fatalf("CombineBeliefs() after restart error = %v", err)
+	}
+	if combined != updated.Probability {
+		t.Errorf("combined = %.3f, want %.3f", combined, updated.Probability)
+	}
+
+	beliefs, err := third.ListBeliefs(10, 0)
+	if err != nil || len(beliefs) != 1 {
+		t.Errorf("ListBeliefs() = %d, %v; want 1", len(beliefs), err)
+	}
+}
+
+func TestProbabilisticReasoner_ListBeliefsInMemory(t *testing.T) {
+	pr := NewProbabilisticReasoner()
+	for _, statement := range []string{"A", "B", "C"} {
+		if _, err := pr.CreateBelief(statement, 0.5); err != nil {
+			t.Fatalf("CreateBelief() error = %v", err)
+		}
+	}
+
+	page, err := pr.ListBeliefs(2, 0)
+	if err != nil || len(page) != 2 {
+		t.Errorf("ListBeliefs(2, 0) = %d, %v; want 2", len(page), err)
+	}
+	if page, _ := pr.ListBeliefs(2, 2); len(page) != 1 {
+		t.Errorf("ListBeliefs(2, 2) = %d, want 1", len(page))
+	}
+}
@@ -46,6 +46,7 @@ type Outcome struct {
 	ReasoningPath      types.Metadata `json:"reasoning_path"`
 	Timestamp          int64          `json:"timestamp"`
 	Metadata           types.Metadata `json:"metadata"`
+	Workspace          string         `json:"workspace,omitempty"`
 }
 
 // ProblemContext provides context for strategy selection
@@ -0,0 +1,216 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"unified-thinking/internal/embeddings"
+	"unified-thinking/internal/modes"
+	"unified-thinking/internal/usage"
+)
+
+// TextGenerator generates free-form text, as used by the hypothesis,
+// perspective and decomposition generators
+type TextGenerator interface {
+	GenerateText(ctx context.Context, prompt string) (string, error)
+}
+
+// modelOf returns the model of a client that reports one, so fixtures are
+// re-recorded when the model changes
+func modelOf(client any) string {
+	if m, ok := client.(interface{ Model() string }); ok {
+		return m.Model()
+	}
+	return ""
+}
+
+// LLMClient records or replays the calls of a modes.LLMClient
+type LLMClient struct {
+	inner modes.LLMClient
+	store *Store
+	model string
+}
+
+// WrapLLMClient wraps an LLM client, or returns it unchanged for a nil store
+func (s *Store) WrapLLMClient(inner modes.TextLLMClient) modes.TextLLMClient {
+	if s == nil {
+		return inner
+	}
+	return &LLMClient{inner: inner, store: s, model: modelOf(inner)}
+}
+
+// NewLLMClient wraps any LLM client. GenerateText is supported when inner
+// implements it.
+func NewLLMClient(inner modes.LLMClient, store *Store) *LLMClient {
+	return &LLMClient{inner: inner, store: store, model: modelOf(inner)}
+}
+
+// Generate implements modes.LLMClient
+func (c *LLMClient) Generate(ctx context.Context, prompt string, k int) ([]string, error) {
+	request := map[string]any{"model": c.model, "prompt": prompt, "k": k}
+	return do(c.store, "llm/generate", request, func() ([]string, error) {
+		return c.inner.Generate(ctx, prompt, k)
+	})
+}
+
+// Aggregate implements modes.LLMClient
+func (c *LLMClient) Aggregate(ctx context.Context, thoughts []string, problem string) (string, error) {
+	request := map[string]any{"model": c.model, "thoughts": thoughts, "problem": problem}
+	return do(c.store, "llm/aggregate", request, func() (string, error) {
+		return c.inner.Aggregate(ctx, thoughts, problem)
+	})
+}
+
+// Refine implements modes.LLMClient
+func (c *LLMClient) Refine(ctx context.Context, thought string, problem string, refinementCount int) (string, error) {
+	request := map[string]any{"model": c.model, "thought": thought, "problem": problem, "refinement_count": refinementCount}
+	return do(c.store, "llm/refine", request, func() (string, error) {
+		return c.inner.Refine(ctx, thought, problem, refinementCount)
+	})
+}
+
+// scoreResponse is the recorded result of Score
+type scoreResponse struct {
+	Overall float64            `json:"overall"`
+	Scores  map[string]float64 `json:"scores"`
+}
+
+// Score implements modes.LLMClient
+func (c *LLMClient) Score(ctx context.Context, thought string, problem string, criteria map[string]float64) (float64, map[string]float64, error) {
+	request := map[string]any{"model": c.model, "thought": thought, "problem": problem, "criteria": criteria}
+	response, err := do(c.store, "llm/score", request, func() (scoreResponse, error) {
+		overall, scores, err := c.inner.Score(ctx, thought, problem, criteria)
+		return scoreResponse{Overall: overall, Scores: scores}, err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return response.Overall, response.Scores, nil
+}
+
+// ExtractKeyPoints implements modes.LLMClient
+func (c *LLMClient) ExtractKeyPoints(ctx context.Context, thought string) ([]string, error) {
+	request := map[string]any{"model": c.model, "thought": thought}
+	return do(c.store, "llm/key_points", request, func() ([]string, error) {
+		return c.inner.ExtractKeyPoints(ctx, thought)
+	})
+}
+
+// CalculateNovelty implements modes.LLMClient
+func (c *LLMClient) CalculateNovelty(ctx context.Context, thought string, siblings []string) (float64, error) {
+	request := map[string]any{"model": c.model, "thought": thought, "siblings": siblings}
+	return do(c.store, "llm/novelty", request, func() (float64, error) {
+		return c.inner.CalculateNovelty(ctx, thought, siblings)
+	})
+}
+
+// ResearchWithSearch implements modes.LLMClient
+func (c *LLMClient) ResearchWithSearch(ctx context.Context, query string, problem string) (*modes.ResearchResult, error) {
+	request := map[string]any{"model": c.model, "query": query, "problem": problem}
+	return do(c.store, "llm/research", request, func() (*modes.ResearchResult, error) {
+		return c.inner.ResearchWithSearch(ctx, query, problem)
+	})
+}
+
+// GenerateText implements TextGenerator when the wrapped client does
+func (c *LLMClient) GenerateText(ctx context.Context, prompt string) (string, error) {
+	request := map[string]any{"model": c.model, "prompt": prompt}
+	return do(c.store, "text", request, func() (string, error) {
+		generator, ok := c.inner.(TextGenerator)
+		if !ok {
+			return "", fmt.Errorf("%T does not generate text", c.inner)
+		}
+		return generator.GenerateText(ctx, prompt)
+	})
+}
+
+// textGenerator records or replays a standalone TextGenerator
+type textGenerator struct {
+	inner TextGenerator
+	store *Store
+	model string
+}
+
+// WrapTextGenerator wraps a text generator, or returns it unchanged for a
+// nil store
+func (s *Store) WrapTextGenerator(inner TextGenerator) TextGenerator {
+	if s == nil {
+		return inner
+	}
+	return &textGenerator{inner: inner, store: s, model: modelOf(inner)}
+}
+
+// GenerateText implements TextGenerator
+func (g *textGenerator) GenerateText(ctx context.Context, prompt string) (string, error) {
+	request := map[string]any{"model": g.model, "prompt": prompt}
+	return do(g.store, "text", request, func() (string, error) {
+		return g.inner.GenerateText(ctx, prompt)
+	})
+}
+
+// sender records or replays Messages API requests, covering multi-turn
+// conversations such as the agent loop
+type sender struct {
+	modes.MessageSender
+	store *Store
+}
+
+// WrapSender wraps a message sender, or returns it unchanged for a nil
+// store. It fits modes.ProviderConfig.WrapSender.
+func (s *Store) WrapSender(inner modes.MessageSender) modes.MessageSender {
+	if s == nil {
+		return inner
+	}
+	return &sender{MessageSender: inner, store: s}
+}
+
+// SendRequest implements modes.MessageSender. The request carries the model.
+func (s *sender) SendRequest(ctx context.Context, req *modes.APIRequest) (*modes.APIResponse, error) {
+	return do(s.store, "messages", req, func() (*modes.APIResponse, error) {
+		return s.MessageSender.SendRequest(ctx, req)
+	})
+}
+
+// embedder records or replays embeddings. Dimension, Model and Provider are
+// answered by the wrapped embedder without API calls.
+type embedder struct {
+	embeddings.Embedder
+	store *Store
+}
+
+// WrapEmbedder wraps an embedder, or returns it unchanged for a nil store
+func (s *Store) WrapEmbedder(inner embeddings.Embedder) embeddings.Embedder {
+	if s == nil {
+		return inner
+	}
+	return &embedder{Embedder: inner, store: s}
+}
+
+// Embed implements embeddings.Embedder as a batch of one, so single and
+// batched embeddings of the same text share a fixture format
+func (e *embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+	return vectors[0], nil
+}
+
+// EmbedBatch implements embeddings.Embedder
+func (e *embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	request := map[string]any{"provider": e.Provider(), "model": e.Model(), "texts": texts}
+	return do(e.store, "embed", request, func() ([][]float32, error) {
+		return e.Embedder.EmbedBatch(ctx, texts)
+	})
+}
+
+// SetUsageTracker forwards to the wrapped embedder, so replayed embedders
+// can still be wired like the real ones
+func (e *embedder) SetUsageTracker(tracker *usage.Tracker) {
+	if tracked, ok := e.Embedder.(embeddings.UsageTracked); ok {
+		tracked.SetUsageTracker(tracker)
+	}
+}
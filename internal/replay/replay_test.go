@@ -0,0 +1,218 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"unified-thinking/internal/embeddings"
+	"unified-thinking/internal/modes"
+	"unified-thinking/internal/storage"
+	"unified-thinking/internal/testutil"
+)
+
+func newStore(t *testing.T, dir string, mode Mode) *Store {
+	t.Helper()
+	store, err := NewStore(dir, mode)
+	if err != nil {
+		t.Fatalf("NewStore(%s): %v", mode, err)
+	}
+	return store
+}
+
+func TestKey_Normalization(t *testing.T) {
+	a, err := Key("text", map[string]any{"prompt": "Explain X.  \r\nThen Y\n", "model": "m"})
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	b, _ := Key("text", map[string]any{"model": "m", "prompt": "Explain X.\nThen Y"})
+	if a != b {
+		t.Errorf("whitespace and key order should not change the key: %s != %s", a, b)
+	}
+	if c, _ := Key("llm/generate", map[string]any{"model": "m", "prompt": "Explain X.\nThen Y"}); c == a {
+		t.Error("kinds should have distinct keys")
+	}
+	if d, _ := Key("text", map[string]any{"model": "m", "prompt": "Explain Z"}); d == a {
+		t.Error("different prompts should have distinct keys")
+	}
+}
+
+func TestNewStore_Validation(t *testing.T) {
+	if _, err := NewStore("", ModeRecord); err == nil {
+		t.Error("expected error without a directory")
+	}
+	if _, err := NewStore(t.TempDir(), "rewind"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+	if _, err := NewStore(filepath.Join(t.TempDir(), "missing"), ModeReplay); err == nil {
+		t.Error("expected error for missing fixtures in replay mode")
+	}
+}
+
+func TestStoreFromEnv(t *testing.T) {
+	t.Setenv("LLM_REPLAY_MODE", "")
+	if store, err := StoreFromEnv(); store != nil || err != nil {
+		t.Errorf("unset mode = %v, %v; want nil, nil", store, err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	t.Setenv("LLM_REPLAY_MODE", "Record")
+	t.Setenv("LLM_REPLAY_DIR", dir)
+	store, err := StoreFromEnv()
+	if err != nil || store.Mode() != ModeRecord {
+		t.Fatalf("StoreFromEnv = %v, %v", store, err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("record mode should create the directory: %v", err)
+	}
+}
+
+// cannedText answers every prompt with the same text
+type cannedText string
+
+func (c cannedText) GenerateText(context.Context, string) (string, error) {
+	return string(c), nil
+}
+
+func TestNilStoreWrapsNothing(t *testing.T) {
+	var store *Store
+	if got := store.WrapSender(nil); got != nil {
+		t.Errorf("WrapSender = %v, want unchanged", got)
+	}
+	embedder := embeddings.NewMockEmbedder(8)
+	if got := store.WrapEmbedder(embedder); got != embedder {
+		t.Errorf("WrapEmbedder = %v, want unchanged", got)
+	}
+	if got := store.WrapTextGenerator(cannedText("x")); got != cannedText("x") {
+		t.Errorf("WrapTextGenerator = %v, want unchanged", got)
+	}
+}
+
+// failingLLM fails every call, proving replay never reaches the client
+type failingLLM struct {
+	modes.LLMClient
+}
+
+func (failingLLM) Generate(context.Context, string, int) ([]string, error) {
+	return nil, errors.New("network disabled")
+}
+
+func TestLLMClient_GraphOfThoughtsOffline(t *testing.T) {
+	dir := t.TempDir()
+	run := func(llm modes.LLMClient) []string {
+		gc := modes.NewGraphController(storage.NewMemoryStorage())
+		state, err := gc.Initialize("replay-graph", "Reduce API latency", nil)
+		if err != nil {
+			t.Fatalf("Initialize: %v", err)
+		}
+		vertices, err := gc.Generate(context.Background(), state.ID, llm, modes.GenerateRequest{K: 3, Problem: "Reduce API latency"})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		var contents []string
+		for _, v := range vertices {
+			contents = append(contents, v.Content)
+		}
+		return contents
+	}
+
+	recorded := run(NewLLMClient(testutil.NewMockLLMClient(), newStore(t, dir, ModeRecord)))
+	replayed := run(NewLLMClient(failingLLM{}, newStore(t, dir, ModeReplay)))
+	if len(recorded) != 3 || !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("replayed %v, recorded %v", replayed, recorded)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "llm_generate-*.json"))
+	if len(files) != 1 {
+		t.Errorf("fixtures = %v, want one generate fixture", files)
+	}
+}
+
+func TestLLMClient_ReplayMiss(t *testing.T) {
+	client := NewLLMClient(failingLLM{}, newStore(t, t.TempDir(), ModeReplay))
+	_, err := client.Generate(context.Background(), "unrecorded", 2)
+	if !errors.Is(err, ErrMiss) {
+		t.Errorf("err = %v, want ErrMiss", err)
+	}
+}
+
+func TestTextGenerator_RecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	generator := cannedText(`{"hypotheses": [{"description": "Cache misses"}]}`)
+	recorded, err := newStore(t, dir, ModeRecord).WrapTextGenerator(generator).GenerateText(context.Background(), "Why is the build slow?")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	replayer := newStore(t, dir, ModeReplay).WrapTextGenerator(nil)
+	replayed, err := replayer.GenerateText(context.Background(), "Why is the build slow?\n")
+	if err != nil || replayed != recorded {
+		t.Errorf("replayed %q, %v; want %q", replayed, err, recorded)
+	}
+}
+
+func TestSender_AgentLoopOffline(t *testing.T) {
+	responses := []string{
+		`{"content":[{"type":"tool_use","id":"t1","name":"echo","input":{"message":"hi"}}],"stop_reason":"tool_use","usage":{"input_tokens":10,"output_tokens":5}}`,
+		`{"content":[{"type":"text","text":"The tool echoed hi"}],"stop_reason":"end_turn","usage":{"input_tokens":20,"output_tokens":5}}`,
+	}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[calls%len(responses)]))
+		calls++
+	}))
+	defer server.Close()
+
+	registry := modes.NewToolRegistry()
+	_ = registry.Register(modes.ToolSpec{
+		Name:        "echo",
+		Description: "Returns the input message",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"echoed": input["message"]}, nil
+		},
+	})
+
+	dir := t.TempDir()
+	run := func(store *Store, baseURL string) *modes.AgenticResult {
+		provider := modes.ProviderConfig{Provider: modes.ProviderAnthropic, BaseURL: baseURL, WrapSender: store.WrapSender}
+		result, err := modes.NewAgenticClientWithProvider(provider, registry, modes.AgenticConfig{Model: "claude-test"}).Run(context.Background(), "Echo hi")
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return result
+	}
+
+	recorded := run(newStore(t, dir, ModeRecord), server.URL)
+	replayed := run(newStore(t, dir, ModeReplay), "http://127.0.0.1:1")
+	if calls != 2 {
+		t.Errorf("server calls = %d, want 2 (replay must not call the API)", calls)
+	}
+	if replayed.FinalAnswer != recorded.FinalAnswer || !strings.Contains(replayed.FinalAnswer, "echoed hi") {
+		t.Errorf("replayed %q, recorded %q", replayed.FinalAnswer, recorded.FinalAnswer)
+	}
+}
+
+func TestEmbedder_RecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	recorder := newStore(t, dir, ModeRecord).WrapEmbedder(embeddings.NewMockEmbedder(8))
+	recorded, err := recorder.Embed(context.Background(), "graph of thoughts")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	replayer := newStore(t, dir, ModeReplay).WrapEmbedder(embeddings.NewMockEmbedder(8))
+	replayed, err := replayer.EmbedBatch(context.Background(), []string{"graph of thoughts"})
+	if err != nil || !reflect.DeepEqual(replayed[0], recorded) {
+		t.Errorf("replayed %v, %v; want %v", replayed, err, recorded)
+	}
+	if _, err := replayer.Embed(context.Background(), "something else"); !errors.Is(err, ErrMiss) {
+		t.Errorf("err = %v, want ErrMiss", err)
+	}
+}
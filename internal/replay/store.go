@@ -0,0 +1,204 @@
+// Package replay records LLM and embedding calls to a fixture directory and
+// serves them back deterministically, so reasoning pipelines can be
+// regression-tested offline against real model outputs and benchmark runs
+// are reproducible.
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mode selects whether calls are recorded or replayed
+type Mode string
+
+const (
+	// ModeRecord calls the wrapped client and writes every response
+	ModeRecord Mode = "record"
+	// ModeReplay serves responses from fixtures and never calls the client
+	ModeReplay Mode = "replay"
+)
+
+// ErrMiss is returned in replay mode when no fixture matches a request
+var ErrMiss = errors.New("no recorded response")
+
+// fixture is the on-disk form of a recorded call. The request is kept for
+// readability; only its hash is used for lookups.
+type fixture struct {
+	Kind     string          `json:"kind"`
+	Request  any             `json:"request"`
+	Response json.RawMessage `json:"response"`
+}
+
+// Store reads and writes fixtures in a directory. A nil *Store wraps
+// nothing, so callers can wrap clients unconditionally.
+type Store struct {
+	dir  string
+	mode Mode
+}
+
+// NewStore creates a store. The directory is created in record mode.
+func NewStore(dir string, mode Mode) (*Store, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("replay fixture directory is required")
+	}
+	switch mode {
+	case ModeRecord:
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("create fixture directory: %w", err)
+		}
+	case ModeReplay:
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("fixture directory %s not found", dir)
+		}
+	default:
+		return nil, fmt.Errorf("unknown replay mode %q (supported: %s, %s)", mode, ModeRecord, ModeReplay)
+	}
+	return &Store{dir: dir, mode: mode}, nil
+}
+
+// StoreFromEnv reads LLM_REPLAY_MODE (record or replay) and LLM_REPLAY_DIR.
+// It returns nil when LLM_REPLAY_MODE is unset or "off".
+func StoreFromEnv() (*Store, error) {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("LLM_REPLAY_MODE")))
+	if mode == "" || mode == "off" {
+		return nil, nil
+	}
+	return NewStore(os.Getenv("LLM_REPLAY_DIR"), Mode(mode))
+}
+
+// Mode returns the store's mode
+func (s *Store) Mode() Mode {
+	return s.mode
+}
+
+// Key returns the fixture key of a request: a hash of its kind and its
+// normalized JSON form. Line endings, surrounding whitespace and map key
+// order do not change the key.
+func Key(kind string, request any) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("marshal %s request: %w", kind, err)
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return "", fmt.Errorf("normalize %s request: %w", kind, err)
+	}
+	normalized, err := json.Marshal(normalize(generic))
+	if err != nil {
+		return "", fmt.Errorf("normalize %s request: %w", kind, err)
+	}
+
+	sum := sha256.Sum256(append([]byte(kind+"\n"), normalized...))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// normalize canonicalizes the strings of a decoded JSON value
+func normalize(value any) any {
+	switch v := value.(type) {
+	case string:
+		lines := strings.Split(strings.ReplaceAll(v, "\r\n", "\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+		return strings.TrimSpace(strings.Join(lines, "\n"))
+	case []any:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+		return v
+	case map[string]any:
+		for key := range v {
+			v[key] = normalize(v[key])
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// path returns the fixture file for a kind and key
+func (s *Store) path(kind, key string) string {
+	return filepath.Join(s.dir, strings.ReplaceAll(kind, "/", "_")+"-"+key+".json")
+}
+
+// load reads the recorded response for a request into response
+func (s *Store) load(kind, key string, response any) error {
+	data, err := os.ReadFile(s.path(kind, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w for %s request %s in %s", ErrMiss, kind, key, s.dir)
+	}
+	if err != nil {
+		return fmt.Errorf("read fixture: %w", err)
+	}
+
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("parse fixture %s: %w", s.path(kind, key), err)
+	}
+	if err := json.Unmarshal(f.Response, response); err != nil {
+		return fmt.Errorf("decode fixture %s: %w", s.path(kind, key), err)
+	}
+	return nil
+}
+
+// save writes a fixture atomically, so concurrent recorders never leave a
+// partial file behind
+func (s *Store) save(kind, key string, request, response any) error {
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("marshal %s response: %w", kind, err)
+	}
+	data, err := json.MarshalIndent(fixture{Kind: kind, Request: request, Response: encoded}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal fixture: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".fixture-*")
+	if err != nil {
+		return fmt.Errorf("write fixture: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write fixture: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write fixture: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path(kind, key))
+}
+
+// do serves a call from its fixture in replay mode, or makes it and records
+// the response in record mode. Failed calls are not recorded.
+func do[T any](s *Store, kind string, request any, call func() (T, error)) (T, error) {
+	var zero T
+	key, err := Key(kind, request)
+	if err != nil {
+		return zero, err
+	}
+
+	if s.mode == ModeReplay {
+		var response T
+		if err := s.load(kind, key, &response); err != nil {
+			return zero, err
+		}
+		return response, nil
+	}
+
+	response, err := call()
+	if err != nil {
+		return zero, err
+	}
+	if err := s.save(kind, key, request, response); err != nil {
+		return zero, err
+	}
+	return response, nil
+}
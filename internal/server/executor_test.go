@@ -2,136 +2,163 @@ package server
 
 import (
 	"context"
-	"reflect"
+	"strings"
 	"testing"
-)
 
-func TestGetStringField(t *testing.T) {
-	value := getStringField(map[string]interface{}{"content": "hello"}, "content")
-	if value != "hello" {
-		t.Fatalf("expected hello, got %s", value)
-	}
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/modes"
+	"unified-thinking/internal/orchestration"
+	"unified-thinking/internal/server/handlers"
+	"unified-thinking/internal/storage"
+	"unified-thinking/internal/types"
+)
 
-	empty := getStringField(map[string]interface{}{"content": 42}, "content")
-	if empty != "" {
-		t.Fatalf("expected empty string for non-string value, got %s", empty)
-	}
+// newDispatchServer registers a few tools the way RegisterTools does
+func newDispatchServer(t *testing.T) *UnifiedServer {
+	t.Helper()
+	s := &UnifiedServer{dispatcher: handlers.NewDispatcher(), agentTools: modes.NewToolRegistry()}
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0"}, nil)
+
+	type graphRequest struct {
+		Description  string   `json:"description"`
+		Observations []string `json:"observations"`
+	}
+	type graph struct {
+		ID           string   `json:"id"`
+		Observations []string `json:"observations"`
+	}
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{Name: "build-causal-graph", Description: "Build a graph.\n\nDetails."},
+		func(ctx context.Context, req *mcp.CallToolRequest, input graphRequest) (*mcp.CallToolResult, *struct {
+			Graph *graph `json:"graph"`
+		}, error) {
+			return nil, &struct {
+				Graph *graph `json:"graph"`
+			}{Graph: &graph{ID: "graph-1", Observations: input.Observations}}, nil
+		})
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{Name: "run-agent", Description: "Recursive"},
+		func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, any, error) {
+			return nil, nil, nil
+		})
+	return s
 }
 
-func TestGetFloatField(t *testing.T) {
-	tests := []struct {
-		name  string
-		input interface{}
-		want  float64
-	}{
-		{name: "float64", input: 1.25, want: 1.25},
-		{name: "float32", input: float32(2.5), want: 2.5},
-		{name: "int", input: 3, want: 3},
-		{name: "int64", input: int64(4), want: 4},
-		{name: "invalid", input: "oops", want: 0},
-	}
-
-	for _, tc := range tests {
-		got := getFloatField(map[string]interface{}{"value": tc.input}, "value")
-		if got != tc.want {
-			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
-		}
+func TestExecuteToolDispatches(t *testing.T) {
+	executor := NewServerToolExecutor(newDispatchServer(t))
+	result, err := executor.ExecuteTool(context.Background(), "build-causal-graph", map[string]interface{}{
+		"description":  "Rain causes wet streets",
+		"observations": "Streets are wet after rain",
+		"problem":      "workflow input not accepted by the tool",
+	})
+	if err != nil {
+		t.Fatalf("ExecuteTool: %v", err)
+	}
+	graph := result.(map[string]interface{})["graph"].(map[string]interface{})
+	if graph["id"] != "graph-1" || len(graph["observations"].([]interface{})) != 1 {
+		t.Errorf("result = %v", result)
 	}
 }
 
-func TestGetBoolField(t *testing.T) {
-	value := getBoolField(map[string]interface{}{"flag": true}, "flag")
-	if !value {
-		t.Fatal("expected true value")
+func TestExecuteToolUnsupported(t *testing.T) {
+	executor := &serverToolExecutor{}
+	_, err := executor.ExecuteTool(context.Background(), "unknown-tool", map[string]interface{}{})
+	if err == nil {
+		t.Fatal("expected error for unsupported tool")
 	}
 
-	missing := getBoolField(map[string]interface{}{}, "flag")
-	if missing {
-		t.Fatal("expected false for missing key")
+	executor = &serverToolExecutor{server: newDispatchServer(t)}
+	if _, err := executor.ExecuteTool(context.Background(), "unknown-tool", nil); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("err = %v, want unsupported tool", err)
 	}
 }
 
-func TestGetStringSliceField(t *testing.T) {
-	fromInterface := getStringSliceField(map[string]interface{}{"values": []interface{}{"a", "b"}}, "values")
-	if !reflect.DeepEqual(fromInterface, []string{"a", "b"}) {
-		t.Fatalf("expected slice from interface, got %v", fromInterface)
+func TestExecuteToolInWorkflow(t *testing.T) {
+	orchestrator := orchestration.NewOrchestratorWithExecutor(NewServerToolExecutor(newDispatchServer(t)))
+	err := orchestrator.RegisterWorkflow(&orchestration.Workflow{
+		ID:   "graph",
+		Name: "Graph",
+		Type: orchestration.WorkflowSequential,
+		Steps: []*orchestration.WorkflowStep{
+			{ID: "build", Tool: "build-causal-graph", Input: types.Metadata{"description": "{{problem}}"}, StoreAs: "causal_graph"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterWorkflow: %v", err)
 	}
 
-	fromStrings := getStringSliceField(map[string]interface{}{"values": []string{"x", "y"}}, "values")
-	if !reflect.DeepEqual(fromStrings, []string{"x", "y"}) {
-		t.Fatalf("expected slice from strings, got %v", fromStrings)
+	result, err := orchestrator.ExecuteWorkflow(context.Background(), "graph", types.Metadata{"problem": "Why are streets wet?", "observations": []interface{}{"rain"}})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow: %v", err)
 	}
-
-	missing := getStringSliceField(map[string]interface{}{}, "values")
-	if missing != nil {
-		t.Fatalf("expected nil for missing key, got %v", missing)
+	if result.Status != "success" {
+		t.Fatalf("status = %s, error %s", result.Status, result.ErrorMessage)
+	}
+	if graphs := result.Context.CausalGraphs; len(graphs) != 1 || graphs[0] != "graph-1" {
+		t.Errorf("causal graphs = %v", graphs)
 	}
 }
 
-// TestGetCriteria removed - _getCriteria helper function no longer needed
-// as make-decision tool uses simplified response format in orchestrator
+func TestPopulateToolRegistry(t *testing.T) {
+	s := newDispatchServer(t)
+	s.populateToolRegistry(s.agentTools)
 
-func TestExecuteToolAssessEvidence(t *testing.T) {
-	executor := &serverToolExecutor{}
-	payload := map[string]interface{}{
-		"claim":    "claim",
-		"evidence": []interface{}{"a", "b"},
-	}
-
-	result, err := executor.ExecuteTool(context.Background(), "assess-evidence", payload)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if _, ok := s.agentTools.Get("run-agent"); ok {
+		t.Error("excluded tools must not be available to the agent")
 	}
-
-	data, ok := result.(map[string]interface{})
+	spec, ok := s.agentTools.Get("build-causal-graph")
 	if !ok {
-		t.Fatalf("expected map result, got %T", result)
+		t.Fatal("build-causal-graph not registered")
 	}
-
-	if data["claim"] != "claim" {
-		t.Fatalf("unexpected claim: %v", data["claim"])
+	if spec.Description != "Build a graph." || spec.InputSchema["properties"] == nil {
+		t.Errorf("spec = %+v", spec)
 	}
 
-	evidence, ok := data["evidence"].([]string)
-	if !ok {
-		t.Fatalf("expected evidence slice, got %T", data["evidence"])
+	result, err := s.agentTools.Execute(context.Background(), "build-causal-graph", map[string]interface{}{"description": "d", "observations": []interface{}{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
 	}
-
-	if !reflect.DeepEqual(evidence, []string{"a", "b"}) {
-		t.Fatalf("unexpected evidence values: %v", evidence)
+	if result.(map[string]interface{})["graph"] == nil {
+		t.Errorf("result = %v", result)
 	}
 
-	if data["strength"] != 0.7 {
-		t.Fatalf("unexpected strength: %v", data["strength"])
-	}
+	// Servers built without NewUnifiedServer have nothing to populate
+	(&UnifiedServer{storage: storage.NewMemoryStorage()}).populateToolRegistry(modes.NewToolRegistry())
 }
 
-func TestExecuteToolMakeDecision(t *testing.T) {
-	executor := &serverToolExecutor{}
-	payload := map[string]interface{}{
-		"situation": "choose path",
-		"options":   []string{"optA", "optB"},
-	}
-
-	result, err := executor.ExecuteTool(context.Background(), "make-decision", payload)
+func TestExecuteToolRejectsSelfReferencingWorkflow(t *testing.T) {
+	s := newDispatchServer(t)
+	orchestrator := orchestration.NewOrchestratorWithExecutor(NewServerToolExecutor(s))
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0"}, nil)
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{Name: "execute-workflow", Description: "Run a workflow"},
+		func(ctx context.Context, req *mcp.CallToolRequest, input struct {
+			WorkflowID string `json:"workflow_id"`
+		}) (*mcp.CallToolResult, *orchestration.WorkflowResult, error) {
+			result, err := orchestrator.ExecuteWorkflow(ctx, input.WorkflowID, types.Metadata{})
+			return nil, result, err
+		})
+
+	err := orchestrator.RegisterWorkflow(&orchestration.Workflow{
+		ID:   "loop",
+		Name: "Loop",
+		Type: orchestration.WorkflowSequential,
+		Steps: []*orchestration.WorkflowStep{
+			{ID: "self", Tool: "execute-workflow", Input: types.Metadata{"workflow_id": "loop"}},
+		},
+	})
 	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+		t.Fatalf("RegisterWorkflow: %v", err)
 	}
 
-	data, ok := result.(map[string]interface{})
-	if !ok {
-		t.Fatalf("expected map result, got %T", result)
+	result, err := orchestrator.ExecuteWorkflow(context.Background(), "loop", types.Metadata{})
+	if err == nil && result.Status == "success" {
+		t.Fatal("self-referencing workflow should fail")
 	}
-
-	if data["selected"] != "optA" {
-		t.Fatalf("expected first option selected, got %v", data["selected"])
+	msg := ""
+	if err != nil {
+		msg = err.Error()
+	} else {
+		msg = result.ErrorMessage
 	}
-}
-
-func TestExecuteToolUnsupported(t *testing.T) {
-	executor := &serverToolExecutor{}
-	_, err := executor.ExecuteTool(context.Background(), "unknown-tool", map[string]interface{}{})
-	if err == nil {
-		t.Fatal("expected error for unsupported tool")
+	if !strings.Contains(msg, "cannot be a workflow step") {
+		t.Errorf("error = %q, want recursion rejection", msg)
 	}
 }
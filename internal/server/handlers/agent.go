@@ -9,21 +9,37 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"unified-thinking/internal/modes"
+	"unified-thinking/internal/streaming"
 )
 
 // AgentHandler handles agentic tool execution
 type AgentHandler struct {
 	registry *modes.ToolRegistry
-	apiKey   string
+	provider modes.ProviderConfig
+	model    string
 }
 
-// NewAgentHandler creates a new agent handler
+// NewAgentHandler creates a new agent handler using the Anthropic provider
 // Agent is ALWAYS enabled - no AGENT_ENABLED check
 // Will FAIL at runtime if ANTHROPIC_API_KEY is not set
 func NewAgentHandler(registry *modes.ToolRegistry) *AgentHandler {
+	return NewAgentHandlerWithProvider(registry, modes.ProviderConfig{
+		Provider: modes.ProviderAnthropic,
+		APIKey:   os.Getenv("ANTHROPIC_API_KEY"),
+	})
+}
+
+// NewAgentHandlerWithProvider creates an agent handler for the given LLM
+// provider. AGENT_MODEL overrides the default model.
+func NewAgentHandlerWithProvider(registry *modes.ToolRegistry, provider modes.ProviderConfig) *AgentHandler {
+	model := os.Getenv("AGENT_MODEL")
+	if model == "" {
+		model = provider.DefaultModel()
+	}
 	return &AgentHandler{
 		registry: registry,
-		apiKey:   os.Getenv("ANTHROPIC_API_KEY"),
+		provider: provider,
+		model:    model,
 	}
 }
 
@@ -58,6 +74,7 @@ func (h *AgentHandler) HandleRunAgent(ctx context.Context, req *mcp.CallToolRequ
 
 	// Build config
 	config := modes.DefaultAgenticConfig()
+	config.Model = h.model
 	if input.MaxIterations > 0 {
 		config.MaxIterations = input.MaxIterations
 	}
@@ -78,9 +95,10 @@ func (h *AgentHandler) HandleRunAgent(ctx context.Context, req *mcp.CallToolRequ
 	}
 
 	// Create agentic client
-	agent := modes.NewAgenticClient(h.apiKey, registry, config)
+	agent := modes.NewAgenticClientWithProvider(h.provider, registry, config)
 
-	// Execute
+	// Execute, streaming model output to clients that sent a progress token
+	ctx, _ = streaming.InjectReporter(ctx, req, "run-agent")
 	var result *modes.AgenticResult
 	var err error
 	if input.SystemPrompt != "" {
@@ -183,8 +201,8 @@ func agentToJSONContent(data interface{}) []mcp.Content {
 }
 
 // RegisterAgentTools registers agent MCP tools
-func RegisterAgentTools(mcpServer *mcp.Server, handler *AgentHandler) {
-	mcp.AddTool(mcpServer, &mcp.Tool{
+func RegisterAgentTools(mcpServer *mcp.Server, dispatcher *Dispatcher, handler *AgentHandler) {
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "run-agent",
 		Description: `Execute a task using an agentic LLM with access to unified-thinking tools.
 
@@ -197,12 +215,12 @@ until the task is complete or max iterations reached.
 **Parameters:**
 - task (required): The task for the agent to complete
 - max_iterations (optional): Maximum tool-calling iterations (default: 10, max: 20)
-- allowed_tools (optional): List of specific tools to allow (default: all safe tools)
+- allowed_tools (optional): List of specific tools to allow (default: every registered tool except the excluded ones)
 - stop_on_error (optional): Stop if a tool call fails (default: true)
 - system_prompt (optional): Custom system prompt for the agent
 - temperature (optional): Model temperature (default: 0.3)
 
-**Safe Tools (available by default):**
+**Safe Tools (listed with is_safe by list-agent-tools):**
 - Reasoning: think, decompose-problem, make-decision, dual-process-think
 - Analysis: analyze-perspectives, detect-biases, detect-fallacies
 - Evidence: assess-evidence, probabilistic-reasoning, detect-contradictions
@@ -213,9 +231,9 @@ until the task is complete or max iterations reached.
 
 **Excluded Tools (not available):**
 - Storage: store-entity, create-relationship (side effects)
-- Sessions: export-session, import-session (session management)
+- Sessions: export-session, import-session, set-workspace (session management)
 - Orchestration: run-agent, run-preset, execute-workflow (recursion risk)
-- State: create-checkpoint, restore-checkpoint (state modification)
+- State: create-checkpoint, restore-checkpoint, fork-checkpoint, prune-branch, focus-branch, register-workflow, delete-workflow, configure-fallacy-rules (state modification)
 
 **Returns:**
 - final_answer: The agent's final response
@@ -240,7 +258,7 @@ until the task is complete or max iterations reached.
 - Problem decomposition and systematic analysis`,
 	}, handler.HandleRunAgent)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "list-agent-tools",
 		Description: `List all tools available for agentic use.
 
@@ -3,24 +3,57 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"unified-thinking/internal/modes"
 	"unified-thinking/internal/storage"
+	"unified-thinking/internal/types"
 )
 
-// BacktrackingHandler handles backtracking operations
+// BacktrackingHandler handles backtracking operations. Checkpoints are kept
+// per workspace, each by a manager over that workspace's storage.
 type BacktrackingHandler struct {
 	manager *modes.BacktrackingManager
 	storage storage.Storage
+
+	mu       sync.Mutex
+	managers map[string]*modes.BacktrackingManager // workspace -> manager
 }
 
-// NewBacktrackingHandler creates a new backtracking handler
+// NewBacktrackingHandler creates a new backtracking handler. manager serves
+// the workspace store is bound to; managers for other workspaces are created
+// on first use.
 func NewBacktrackingHandler(manager *modes.BacktrackingManager, store storage.Storage) *BacktrackingHandler {
 	return &BacktrackingHandler{
-		manager: manager,
-		storage: store,
+		manager:  manager,
+		storage:  store,
+		managers: map[string]*modes.BacktrackingManager{storage.WorkspaceOf(store): manager},
+	}
+}
+
+// forWorkspace returns the manager and storage view for a workspace
+func (h *BacktrackingHandler) forWorkspace(workspace string) (*modes.BacktrackingManager, storage.Storage, error) {
+	if err := storage.ValidateWorkspace(workspace); err != nil {
+		return nil, nil, &ValidationError{"workspace", err.Error()}
+	}
+	if workspace == "" {
+		workspace = storage.WorkspaceOf(h.storage)
+	}
+	workspace = storage.NormalizeWorkspace(workspace)
+	store := storage.ForWorkspace(h.storage, workspace)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if manager, exists := h.managers[workspace]; exists {
+		return manager, store, nil
 	}
+	manager := modes.NewBacktrackingManager(store)
+	if checkpointStore, ok := store.(modes.CheckpointStorage); ok {
+		manager.SetCheckpointStorage(checkpointStore)
+	}
+	h.managers[workspace] = manager
+	return manager, store, nil
 }
 
 // CreateCheckpointRequest represents a checkpoint creation request
@@ -28,6 +61,7 @@ type CreateCheckpointRequest struct {
 	BranchID    string `json:"branch_id"`
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+	Workspace   string `json:"workspace,omitempty"`
 }
 
 // CreateCheckpointResponse represents the response
@@ -65,13 +99,18 @@ func (h *BacktrackingHandler) createCheckpoint(ctx context.Context, req CreateCh
 		return nil, fmt.Errorf("name is required")
 	}
 
-	checkpoint, err := h.manager.CreateCheckpoint(ctx, req.BranchID, req.Name, req.Description)
+	manager, store, err := h.forWorkspace(req.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := manager.CreateCheckpoint(ctx, req.BranchID, req.Name, req.Description)
 	if err != nil {
 		return nil, fmt.Errorf("checkpoint creation failed: %w", err)
 	}
 
 	// Get branch to get counts
-	branch, _ := h.storage.GetBranch(checkpoint.BranchID)
+	branch, _ := store.GetBranch(checkpoint.BranchID)
 	thoughtCount := 0
 	insightCount := 0
 	if branch != nil {
@@ -93,6 +132,7 @@ func (h *BacktrackingHandler) createCheckpoint(ctx context.Context, req CreateCh
 // RestoreCheckpointRequest represents a restore request
 type RestoreCheckpointRequest struct {
 	CheckpointID string `json:"checkpoint_id"`
+	Workspace    string `json:"workspace,omitempty"`
 }
 
 // RestoreCheckpointResponse represents the response
@@ -124,7 +164,12 @@ func (h *BacktrackingHandler) restoreCheckpoint(ctx context.Context, req Restore
 		return nil, fmt.Errorf("checkpoint_id is required")
 	}
 
-	branch, err := h.manager.RestoreCheckpoint(ctx, req.CheckpointID)
+	manager, _, err := h.forWorkspace(req.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := manager.RestoreCheckpoint(ctx, req.CheckpointID)
 	if err != nil {
 		return nil, fmt.Errorf("checkpoint restoration failed: %w", err)
 	}
@@ -139,7 +184,8 @@ func (h *BacktrackingHandler) restoreCheckpoint(ctx context.Context, req Restore
 
 // ListCheckpointsRequest represents a list request
 type ListCheckpointsRequest struct {
-	BranchID string `json:"branch_id,omitempty"`
+	BranchID  string `json:"branch_id,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
 }
 
 // ListCheckpointsResponse represents the response
@@ -175,12 +221,16 @@ func (h *BacktrackingHandler) HandleListCheckpoints(ctx context.Context, params
 
 // listCheckpoints is the typed internal implementation
 func (h *BacktrackingHandler) listCheckpoints(_ context.Context, req ListCheckpointsRequest) (*ListCheckpointsResponse, error) {
-	checkpoints := h.manager.ListCheckpoints(req.BranchID)
+	manager, store, err := h.forWorkspace(req.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	checkpoints := manager.ListCheckpoints(req.BranchID)
 
 	infos := make([]*CheckpointInfo, 0, len(checkpoints))
 	for _, cp := range checkpoints {
 		// Get branch for thought count
-		branch, _ := h.storage.GetBranch(cp.BranchID)
+		branch, _ := store.GetBranch(cp.BranchID)
 		thoughtCount := 0
 		if branch != nil {
 			thoughtCount = len(branch.Thoughts)
@@ -202,3 +252,188 @@ func (h *BacktrackingHandler) listCheckpoints(_ context.Context, req ListCheckpo
 		Count:       len(infos),
 	}, nil
 }
+
+// ForkCheckpointRequest represents a fork request
+type ForkCheckpointRequest struct {
+	CheckpointID string `json:"checkpoint_id"`
+	Name         string `json:"name,omitempty"`
+	Workspace    string `json:"workspace,omitempty"`
+}
+
+// ForkCheckpointResponse represents the response
+type ForkCheckpointResponse struct {
+	BranchID       string `json:"branch_id"`
+	ParentBranchID string `json:"parent_branch_id"`
+	CheckpointID   string `json:"checkpoint_id"`
+	Name           string `json:"name,omitempty"`
+	ThoughtCount   int    `json:"thought_count"`
+	InsightCount   int    `json:"insight_count"`
+	Message        string `json:"message"`
+}
+
+// HandleForkCheckpoint creates a new branch from a checkpoint
+func (h *BacktrackingHandler) HandleForkCheckpoint(ctx context.Context, params map[string]interface{}) (*mcp.CallToolResult, error) {
+	req, err := unmarshalRequest[ForkCheckpointRequest](params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	resp, err := h.forkCheckpoint(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcp.CallToolResult{Content: toJSONContent(resp)}, nil
+}
+
+// forkCheckpoint is the typed internal implementation
+func (h *BacktrackingHandler) forkCheckpoint(ctx context.Context, req ForkCheckpointRequest) (*ForkCheckpointResponse, error) {
+	if req.CheckpointID == "" {
+		return nil, fmt.Errorf("checkpoint_id is required")
+	}
+
+	manager, _, err := h.forWorkspace(req.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := manager.ForkFromCheckpoint(ctx, req.CheckpointID, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint fork failed: %w", err)
+	}
+
+	return &ForkCheckpointResponse{
+		BranchID:       branch.ID,
+		ParentBranchID: branch.ParentBranchID,
+		CheckpointID:   req.CheckpointID,
+		Name:           req.Name,
+		ThoughtCount:   len(branch.Thoughts),
+		InsightCount:   len(branch.Insights),
+		Message:        "Branch forked from checkpoint",
+	}, nil
+}
+
+// DiffCheckpointsRequest represents a diff request
+type DiffCheckpointsRequest struct {
+	FromCheckpointID string `json:"from_checkpoint_id"`
+	ToCheckpointID   string `json:"to_checkpoint_id"`
+	Workspace        string `json:"workspace,omitempty"`
+}
+
+// DiffCheckpointsResponse represents the response
+type DiffCheckpointsResponse struct {
+	FromCheckpointID string   `json:"from_checkpoint_id"`
+	ToCheckpointID   string   `json:"to_checkpoint_id"`
+	FromBranchID     string   `json:"from_branch_id"`
+	ToBranchID       string   `json:"to_branch_id"`
+	Reversed         bool     `json:"reversed"`
+	ThoughtsAdded    []string `json:"thoughts_added"`
+	ThoughtsRemoved  []string `json:"thoughts_removed"`
+	ThoughtsModified []string `json:"thoughts_modified"`
+	InsightsAdded    []string `json:"insights_added"`
+	InsightsRemoved  []string `json:"insights_removed"`
+	HasChanges       bool     `json:"has_changes"`
+}
+
+// HandleDiffCheckpoints compares two checkpoints
+func (h *BacktrackingHandler) HandleDiffCheckpoints(ctx context.Context, params map[string]interface{}) (*mcp.CallToolResult, error) {
+	req, err := unmarshalRequest[DiffCheckpointsRequest](params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	resp, err := h.diffCheckpoints(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcp.CallToolResult{Content: toJSONContent(resp)}, nil
+}
+
+// diffCheckpoints is the typed internal implementation
+func (h *BacktrackingHandler) diffCheckpoints(_ context.Context, req DiffCheckpointsRequest) (*DiffCheckpointsResponse, error) {
+	if req.FromCheckpointID == "" {
+		return nil, fmt.Errorf("from_checkpoint_id is required")
+	}
+	if req.ToCheckpointID == "" {
+		return nil, fmt.Errorf("to_checkpoint_id is required")
+	}
+
+	manager, _, err := h.forWorkspace(req.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := manager.GetCheckpointDiff(req.FromCheckpointID, req.ToCheckpointID)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint diff failed: %w", err)
+	}
+
+	return &DiffCheckpointsResponse{
+		FromCheckpointID: diff.Checkpoint1.ID,
+		ToCheckpointID:   diff.Checkpoint2.ID,
+		FromBranchID:     diff.Checkpoint1.BranchID,
+		ToBranchID:       diff.Checkpoint2.BranchID,
+		Reversed:         diff.Reversed,
+		ThoughtsAdded:    diff.ThoughtsAdded,
+		ThoughtsRemoved:  diff.ThoughtsRemoved,
+		ThoughtsModified: diff.ThoughtsModified,
+		InsightsAdded:    diff.InsightsAdded,
+		InsightsRemoved:  diff.InsightsRemoved,
+		HasChanges: len(diff.ThoughtsAdded)+len(diff.ThoughtsRemoved)+len(diff.ThoughtsModified)+
+			len(diff.InsightsAdded)+len(diff.InsightsRemoved) > 0,
+	}, nil
+}
+
+// PruneBranchRequest represents a prune request
+type PruneBranchRequest struct {
+	BranchID  string `json:"branch_id"`
+	Reason    string `json:"reason,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// PruneBranchResponse represents the response
+type PruneBranchResponse struct {
+	BranchID string `json:"branch_id"`
+	State    string `json:"state"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message"`
+}
+
+// HandlePruneBranch marks a branch as a dead end
+func (h *BacktrackingHandler) HandlePruneBranch(ctx context.Context, params map[string]interface{}) (*mcp.CallToolResult, error) {
+	req, err := unmarshalRequest[PruneBranchRequest](params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	resp, err := h.pruneBranch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcp.CallToolResult{Content: toJSONContent(resp)}, nil
+}
+
+// pruneBranch is the typed internal implementation
+func (h *BacktrackingHandler) pruneBranch(_ context.Context, req PruneBranchRequest) (*PruneBranchResponse, error) {
+	if req.BranchID == "" {
+		return nil, fmt.Errorf("branch_id is required")
+	}
+
+	manager, _, err := h.forWorkspace(req.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := manager.PruneBranch(req.BranchID, req.Reason); err != nil {
+		return nil, fmt.Errorf("branch prune failed: %w", err)
+	}
+
+	return &PruneBranchResponse{
+		BranchID: req.BranchID,
+		State:    string(types.StateDeadEnd),
+		Reason:   req.Reason,
+		Message:  "Branch marked as dead end",
+	}, nil
+}
@@ -224,3 +224,127 @@ func TestBacktrackingHandler_HandleListCheckpoints(t *testing.T) {
 		})
 	}
 }
+
+// TestBacktrackingHandler_ForkDiffAndPrune tests forking, diffing and pruning
+func TestBacktrackingHandler_ForkDiffAndPrune(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	manager := modes.NewBacktrackingManager(store)
+	handler := NewBacktrackingHandler(manager, store)
+	ctx := context.Background()
+
+	branch := types.NewBranch().Build()
+	branch.Thoughts = []*types.Thought{{ID: "thought-1", Content: "Initial thought"}}
+	_ = store.StoreBranch(branch)
+
+	checkpoint, err := manager.CreateCheckpoint(ctx, branch.ID, "Fork point", "")
+	if err != nil {
+		t.Fatalf("Failed to create checkpoint: %v", err)
+	}
+
+	if _, err := handler.HandleForkCheckpoint(ctx, map[string]interface{}{}); err == nil {
+		t.Error("HandleForkCheckpoint() should require checkpoint_id")
+	}
+	forked, err := handler.forkCheckpoint(ctx, ForkCheckpointRequest{CheckpointID: checkpoint.ID, Name: "Alternative"})
+	if err != nil {
+		t.Fatalf("forkCheckpoint() error = %v", err)
+	}
+	if forked.BranchID == "" || forked.BranchID == branch.ID || forked.ParentBranchID != branch.ID {
+		t.Errorf("fork response = %+v, want new branch with parent %s", forked, branch.ID)
+	}
+
+	forkedBranch, err := store.GetBranch(forked.BranchID)
+	if err != nil {
+		t.Fatalf("forked branch not stored: %v", err)
+	}
+	forkedBranch.Thoughts = append(forkedBranch.Thoughts, &types.Thought{ID: "thought-2", Content: "Alternative approach"})
+	_ = store.StoreBranch(forkedBranch)
+	forkCheckpoint, err := manager.CreateCheckpoint(ctx, forked.BranchID, "Alternative", "")
+	if err != nil {
+		t.Fatalf("Failed to create fork checkpoint: %v", err)
+	}
+
+	if _, err := handler.HandleDiffCheckpoints(ctx, map[string]interface{}{"from_checkpoint_id": checkpoint.ID}); err == nil {
+		t.Error("HandleDiffCheckpoints() should require to_checkpoint_id")
+	}
+	if _, err := handler.HandleDiffCheckpoints(ctx, map[string]interface{}{"from_checkpoint_id": checkpoint.ID, "to_checkpoint_id": "nonexistent"}); err == nil {
+		t.Error("HandleDiffCheckpoints() should fail for unknown checkpoints")
+	}
+	diff, err := handler.diffCheckpoints(ctx, DiffCheckpointsRequest{FromCheckpointID: checkpoint.ID, ToCheckpointID: forkCheckpoint.ID})
+	if err != nil {
+		t.Fatalf("diffCheckpoints() error = %v", err)
+	}
+	if !diff.HasChanges || len(diff.ThoughtsAdded) != 1 || diff.ThoughtsAdded[0] != "thought-2" {
+		t.Errorf("diff = %+v, want thought-2 added", diff)
+	}
+	if diff.ToBranchID != forked.BranchID {
+		t.Errorf("ToBranchID = %s, want %s", diff.ToBranchID, forked.BranchID)
+	}
+
+	if _, err := handler.HandlePruneBranch(ctx, map[string]interface{}{}); err == nil {
+		t.Error("HandlePruneBranch() should require branch_id")
+	}
+	if _, err := handler.HandlePruneBranch(ctx, map[string]interface{}{"branch_id": forked.BranchID, "reason": "Did not pan out"}); err != nil {
+		t.Fatalf("HandlePruneBranch() error = %v", err)
+	}
+	pruned, _ := store.GetBranch(forked.BranchID)
+	if pruned.State != types.StateDeadEnd {
+		t.Errorf("pruned branch state = %s, want %s", pruned.State, types.StateDeadEnd)
+	}
+}
+
+func TestBacktrackingHandler_Workspaces(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBacktrackingHandler(modes.NewBacktrackingManager(store), store)
+	ctx := context.Background()
+
+	alpha := storage.ForWorkspace(store, "alpha")
+	branch := types.NewBranch().Build()
+	branch.Thoughts = []*types.Thought{{ID: "thought-1", Content: "Initial thought"}}
+	_ = alpha.StoreBranch(branch)
+
+	if _, err := handler.createCheckpoint(ctx, CreateCheckpointRequest{BranchID: branch.ID, Name: "Default"}); err == nil {
+		t.Error("createCheckpoint() should not find a branch of another workspace")
+	}
+	created, err := handler.createCheckpoint(ctx, CreateCheckpointRequest{BranchID: branch.ID, Name: "Alpha", Workspace: "alpha"})
+	if err != nil {
+		t.Fatalf("createCheckpoint() error = %v", err)
+	}
+
+	if _, err := handler.forkCheckpoint(ctx, ForkCheckpointRequest{CheckpointID: created.CheckpointID}); err == nil {
+		t.Error("forkCheckpoint() should not find a checkpoint of another workspace")
+	}
+	forked, err := handler.forkCheckpoint(ctx, ForkCheckpointRequest{CheckpointID: created.CheckpointID, Workspace: "alpha"})
+	if err != nil {
+		t.Fatalf("forkCheckpoint() error = %v", err)
+	}
+	if _, err := alpha.GetBranch(forked.BranchID); err != nil {
+		t.Errorf("forked branch should be stored in alpha: %v", err)
+	}
+	if _, err := store.GetBranch(forked.BranchID); err == nil {
+		t.Error("forked branch should not be stored in the default workspace")
+	}
+
+	if _, err := handler.diffCheckpoints(ctx, DiffCheckpointsRequest{FromCheckpointID: created.CheckpointID, ToCheckpointID: created.CheckpointID}); err == nil {
+		t.Error("diffCheckpoints() should not find checkpoints of another workspace")
+	}
+	if _, err := handler.diffCheckpoints(ctx, DiffCheckpointsRequest{FromCheckpointID: created.CheckpointID, ToCheckpointID: created.CheckpointID, Workspace: "alpha"}); err != nil {
+		t.Errorf("diffCheckpoints() error = %v", err)
+	}
+
+	if listed, _ := handler.listCheckpoints(ctx, ListCheckpointsRequest{}); listed.Count != 0 {
+		t.Errorf("default workspace lists %d checkpoints, want 0", listed.Count)
+	}
+	if listed, _ := handler.listCheckpoints(ctx, ListCheckpointsRequest{Workspace: "alpha"}); listed.Count != 1 {
+		t.Errorf("alpha lists %d checkpoints, want 1", listed.Count)
+	}
+
+	if _, err := handler.pruneBranch(ctx, PruneBranchRequest{BranchID: forked.BranchID}); err == nil {
+		t.Error("pruneBranch() should not find a branch of another workspace")
+	}
+	if _, err := handler.pruneBranch(ctx, PruneBranchRequest{BranchID: forked.BranchID, Workspace: "alpha"}); err != nil {
+		t.Errorf("pruneBranch() error = %v", err)
+	}
+	if _, err := handler.pruneBranch(ctx, PruneBranchRequest{BranchID: forked.BranchID, Workspace: "bad workspace!"}); err == nil {
+		t.Error("pruneBranch() should reject an invalid workspace")
+	}
+}
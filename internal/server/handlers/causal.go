@@ -3,9 +3,12 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/export"
 	"unified-thinking/internal/reasoning"
+	"unified-thinking/internal/storage"
 	"unified-thinking/internal/streaming"
 	"unified-thinking/internal/types"
 )
@@ -13,6 +16,15 @@ import (
 // CausalHandler handles causal reasoning operations
 type CausalHandler struct {
 	causalReasoner *reasoning.CausalReasoner
+	// storage, when set, gives each workspace its own reasoner persisting
+	// through that workspace's view
+	storage storage.Storage
+	// resolveWorkspace maps a request's explicit workspace (possibly empty)
+	// to the workspace to use, e.g. the one selected for the MCP session
+	resolveWorkspace func(req *mcp.CallToolRequest, workspace string) string
+
+	mu        sync.Mutex
+	reasoners map[string]*reasoning.CausalReasoner // workspace -> reasoner
 }
 
 // NewCausalHandler creates a new causal handler
@@ -22,6 +34,50 @@ func NewCausalHandler(causalReasoner *reasoning.CausalReasoner) *CausalHandler {
 	}
 }
 
+// SetWorkspaceStorage keeps causal graphs per workspace. The reasoner passed
+// to NewCausalHandler serves the workspace store is bound to; reasoners for
+// other workspaces are created on first use over their storage views.
+func (h *CausalHandler) SetWorkspaceStorage(store storage.Storage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.storage = store
+	h.reasoners = map[string]*reasoning.CausalReasoner{storage.WorkspaceOf(store): h.causalReasoner}
+}
+
+// SetWorkspaceResolver sets how tool calls without an explicit workspace pick one
+func (h *CausalHandler) SetWorkspaceResolver(resolve func(req *mcp.CallToolRequest, workspace string) string) {
+	h.resolveWorkspace = resolve
+}
+
+// reasonerFor returns the causal reasoner for a tool call's workspace
+func (h *CausalHandler) reasonerFor(req *mcp.CallToolRequest, workspace string) (*reasoning.CausalReasoner, error) {
+	if err := storage.ValidateWorkspace(workspace); err != nil {
+		return nil, &ValidationError{"workspace", err.Error()}
+	}
+	if h.resolveWorkspace != nil {
+		workspace = h.resolveWorkspace(req, workspace)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.storage == nil {
+		return h.causalReasoner, nil
+	}
+	if workspace == "" {
+		workspace = storage.WorkspaceOf(h.storage)
+	}
+	workspace = storage.NormalizeWorkspace(workspace)
+	if reasoner, exists := h.reasoners[workspace]; exists {
+		return reasoner, nil
+	}
+	reasoner := reasoning.NewCausalReasoner()
+	if causalStore, ok := storage.ForWorkspace(h.storage, workspace).(reasoning.CausalStorage); ok {
+		reasoner.SetStorage(causalStore)
+	}
+	h.reasoners[workspace] = reasoner
+	return reasoner, nil
+}
+
 // ============================================================================
 // Request/Response Types
 // ============================================================================
@@ -30,6 +86,7 @@ func NewCausalHandler(causalReasoner *reasoning.CausalReasoner) *CausalHandler {
 type BuildCausalGraphRequest struct {
 	Description  string   `json:"description"`
 	Observations []string `json:"observations"`
+	Workspace    string   `json:"workspace,omitempty"`
 }
 
 // BuildCausalGraphResponse represents a causal graph building response
@@ -44,6 +101,7 @@ type SimulateInterventionRequest struct {
 	GraphID          string `json:"graph_id"`
 	VariableID       string `json:"variable_id"`
 	InterventionType string `json:"intervention_type"`
+	Workspace        string `json:"workspace,omitempty"`
 }
 
 // SimulateInterventionResponse represents an intervention simulation response
@@ -54,9 +112,10 @@ type SimulateInterventionResponse struct {
 
 // GenerateCounterfactualRequest represents a counterfactual generation request
 type GenerateCounterfactualRequest struct {
-	GraphID  string            `json:"graph_id"`
-	Scenario string            `json:"scenario"`
-	Changes  map[string]string `json:"changes"`
+	GraphID   string            `json:"graph_id"`
+	Scenario  string            `json:"scenario"`
+	Changes   map[string]string `json:"changes"`
+	Workspace string            `json:"workspace,omitempty"`
 }
 
 // GenerateCounterfactualResponse represents a counterfactual generation response
@@ -78,15 +137,33 @@ type AnalyzeCorrelationVsCausationResponse struct {
 
 // GetCausalGraphRequest represents a causal graph retrieval request
 type GetCausalGraphRequest struct {
-	GraphID string `json:"graph_id"`
+	GraphID   string `json:"graph_id"`
+	Format    string `json:"format,omitempty"` // json (default), dot, mermaid, graphml
+	Workspace string `json:"workspace,omitempty"`
 }
 
 // GetCausalGraphResponse represents a causal graph retrieval response
 type GetCausalGraphResponse struct {
 	Graph  *types.CausalGraph `json:"graph"`
+	Format string             `json:"format,omitempty"`
+	Export string             `json:"export,omitempty"`
 	Status string             `json:"status"`
 }
 
+// ListCausalGraphsRequest represents a causal graph listing request
+type ListCausalGraphsRequest struct {
+	Limit     int    `json:"limit,omitempty"`
+	Offset    int    `json:"offset,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// ListCausalGraphsResponse represents a causal graph listing response
+type ListCausalGraphsResponse struct {
+	Graphs []*types.CausalGraphSummary `json:"graphs"`
+	Count  int                         `json:"count"`
+	Status string                      `json:"status"`
+}
+
 // ============================================================================
 // Handler Methods
 // ============================================================================
@@ -107,7 +184,11 @@ func (h *CausalHandler) HandleBuildCausalGraph(
 		_ = reporter.ReportStep(1, totalSteps, "parse", fmt.Sprintf("Parsing %d observations...", observationCount))
 	}
 
-	graph, err := h.causalReasoner.BuildCausalGraph(input.Description, input.Observations)
+	reasoner, err := h.reasonerFor(req, input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	graph, err := reasoner.BuildCausalGraph(input.Description, input.Observations)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -140,7 +221,11 @@ func (h *CausalHandler) HandleSimulateIntervention(
 	req *mcp.CallToolRequest,
 	input SimulateInterventionRequest,
 ) (*mcp.CallToolResult, *SimulateInterventionResponse, error) {
-	intervention, err := h.causalReasoner.SimulateIntervention(
+	reasoner, err := h.reasonerFor(req, input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	intervention, err := reasoner.SimulateIntervention(
 		input.GraphID,
 		input.VariableID,
 		input.InterventionType,
@@ -165,7 +250,11 @@ func (h *CausalHandler) HandleGenerateCounterfactual(
 	req *mcp.CallToolRequest,
 	input GenerateCounterfactualRequest,
 ) (*mcp.CallToolResult, *GenerateCounterfactualResponse, error) {
-	counterfactual, err := h.causalReasoner.GenerateCounterfactual(
+	reasoner, err := h.reasonerFor(req, input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	counterfactual, err := reasoner.GenerateCounterfactual(
 		input.GraphID,
 		input.Scenario,
 		input.Changes,
@@ -211,7 +300,16 @@ func (h *CausalHandler) HandleGetCausalGraph(
 	req *mcp.CallToolRequest,
 	input GetCausalGraphRequest,
 ) (*mcp.CallToolResult, *GetCausalGraphResponse, error) {
-	graph, err := h.causalReasoner.GetGraph(input.GraphID)
+	format, err := resolveExportFormat(input.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reasoner, err := h.reasonerFor(req, input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	graph, err := reasoner.GetGraph(input.GraphID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -221,6 +319,46 @@ func (h *CausalHandler) HandleGetCausalGraph(
 		Status: "success",
 	}
 
+	if format != "" {
+		rendered, err := export.RenderCausalGraph(graph, format)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to export causal graph: %w", err)
+		}
+		response.Format = string(format)
+		response.Export = rendered
+	}
+
+	return &mcp.CallToolResult{
+		Content: withExport(toJSONContent(response), response.Export),
+	}, response, nil
+}
+
+// HandleListCausalGraphs processes causal graph listing requests
+func (h *CausalHandler) HandleListCausalGraphs(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListCausalGraphsRequest,
+) (*mcp.CallToolResult, *ListCausalGraphsResponse, error) {
+	limit, err := resolvePagination(input.Limit, input.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reasoner, err := h.reasonerFor(req, input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	graphs, err := reasoner.ListGraphs(limit, input.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &ListCausalGraphsResponse{
+		Graphs: graphs,
+		Count:  len(graphs),
+		Status: "success",
+	}
+
 	return &mcp.CallToolResult{
 		Content: toJSONContent(response),
 	}, response, nil
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"unified-thinking/internal/reasoning"
+	"unified-thinking/internal/storage"
 )
 
 func TestNewCausalHandler(t *testing.T) {
@@ -372,6 +373,36 @@ func TestHandleGetCausalGraph(t *testing.T) {
 				assert.Equal(t, graphID, resp.Graph.ID)
 			},
 		},
+		{
+			name: "export as mermaid",
+			input: GetCausalGraphRequest{
+				GraphID: graphID,
+				Format:  "Mermaid",
+			},
+			wantErr: false,
+			validate: func(t *testing.T, result *mcp.CallToolResult, resp *GetCausalGraphResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				assert.Equal(t, "mermaid", resp.Format)
+				assert.Contains(t, resp.Export, "flowchart LR")
+				require.Len(t, result.Content, 2)
+				text, ok := result.Content[1].(*mcp.TextContent)
+				require.True(t, ok)
+				assert.Equal(t, resp.Export, text.Text)
+			},
+		},
+		{
+			name: "unsupported export format",
+			input: GetCausalGraphRequest{
+				GraphID: graphID,
+				Format:  "svg",
+			},
+			wantErr: true,
+			validate: func(t *testing.T, result *mcp.CallToolResult, resp *GetCausalGraphResponse, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unsupported export format")
+			},
+		},
 		{
 			name: "non-existent graph",
 			input: GetCausalGraphRequest{
@@ -401,3 +432,62 @@ func TestHandleGetCausalGraph(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleListCausalGraphs(t *testing.T) {
+	handler := NewCausalHandler(reasoning.NewCausalReasoner())
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	_, resp, err := handler.HandleListCausalGraphs(ctx, req, ListCausalGraphsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.Count)
+
+	_, buildResp, err := handler.HandleBuildCausalGraph(ctx, req, BuildCausalGraphRequest{
+		Description:  "Test graph",
+		Observations: []string{"A causes B"},
+	})
+	require.NoError(t, err)
+
+	result, resp, err := handler.HandleListCausalGraphs(ctx, req, ListCausalGraphsRequest{Limit: 10})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "success", resp.Status)
+	require.Equal(t, 1, resp.Count)
+	assert.Equal(t, buildResp.Graph.ID, resp.Graphs[0].ID)
+
+	_, _, err = handler.HandleListCausalGraphs(ctx, req, ListCausalGraphsRequest{Offset: -1})
+	assert.Error(t, err)
+}
+
+func TestCausalHandler_Workspaces(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewCausalHandler(reasoning.NewCausalReasoner())
+	handler.SetWorkspaceStorage(store)
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	_, built, err := handler.HandleBuildCausalGraph(ctx, req, BuildCausalGraphRequest{
+		Description:  "Alpha graph",
+		Observations: []string{"Rain causes wet streets"},
+		Workspace:    "alpha",
+	})
+	require.NoError(t, err)
+
+	_, listed, err := handler.HandleListCausalGraphs(ctx, req, ListCausalGraphsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, listed.Count, "default workspace should not list alpha's graphs")
+	_, _, err = handler.HandleGetCausalGraph(ctx, req, GetCausalGraphRequest{GraphID: built.Graph.ID})
+	assert.Error(t, err, "default workspace should not find alpha's graph")
+	_, _, err = handler.HandleSimulateIntervention(ctx, req, SimulateInterventionRequest{GraphID: built.Graph.ID, VariableID: built.Graph.Variables[0].ID, InterventionType: "increase"})
+	assert.Error(t, err, "default workspace should not simulate on alpha's graph")
+
+	_, listed, err = handler.HandleListCausalGraphs(ctx, req, ListCausalGraphsRequest{Workspace: "ALPHA"})
+	require.NoError(t, err)
+	require.Equal(t, 1, listed.Count)
+	assert.Equal(t, built.Graph.ID, listed.Graphs[0].ID)
+	_, _, err = handler.HandleGetCausalGraph(ctx, req, GetCausalGraphRequest{GraphID: built.Graph.ID, Workspace: "alpha"})
+	assert.NoError(t, err)
+
+	_, _, err = handler.HandleListCausalGraphs(ctx, req, ListCausalGraphsRequest{Workspace: "bad workspace!"})
+	assert.Error(t, err)
+}
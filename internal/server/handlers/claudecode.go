@@ -3,12 +3,14 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	ccerrors "unified-thinking/internal/claudecode/errors"
 	"unified-thinking/internal/claudecode/format"
 	"unified-thinking/internal/claudecode/presets"
 	"unified-thinking/internal/claudecode/session"
+	"unified-thinking/internal/orchestration"
 	"unified-thinking/internal/storage"
 	"unified-thinking/internal/streaming"
 )
@@ -19,6 +21,7 @@ type ClaudeCodeHandler struct {
 	exporter *session.Exporter
 	importer *session.Importer
 	registry *presets.Registry
+	executor orchestration.ToolExecutor // Runs preset steps, nil to only plan them
 }
 
 // NewClaudeCodeHandler creates a new handler for Claude Code tools
@@ -284,7 +287,24 @@ func (h *ClaudeCodeHandler) HandleRunPreset(ctx context.Context, req *mcp.CallTo
 		_ = reporter.ReportStep(0, len(preset.Steps), "initialize", "Starting preset execution: "+preset.Name)
 	}
 
-	// Execute steps (currently returns ready status - actual execution requires tool executor)
+	if h.executor != nil {
+		for name, spec := range preset.InputSchema {
+			if _, ok := input.Input[name]; spec.Required && !ok {
+				return nil, nil, ccerrors.NewStructuredError(ccerrors.ErrMissingRequired, fmt.Sprintf("input %s is required by preset %s", name, preset.ID)).
+					WithDetails(spec.Description).
+					WithRecovery("Provide every required input listed in the preset's input_schema").
+					WithRelatedTools("list-presets")
+			}
+		}
+
+		response := h.runPreset(ctx, preset, input.Input, reporter)
+		if reporter.IsEnabled() {
+			_ = reporter.ReportStep(len(preset.Steps), len(preset.Steps), "complete", "Preset "+response.Status)
+		}
+		return nil, response, nil
+	}
+
+	// Without an executor the steps are only reported as ready
 	stepResults := make([]PresetStepResult, len(preset.Steps))
 	for i, step := range preset.Steps {
 		// Report step progress
@@ -345,8 +365,8 @@ func (h *ClaudeCodeHandler) HandleFormatResponse(ctx context.Context, req *mcp.C
 }
 
 // RegisterClaudeCodeTools registers all Claude Code optimization tools
-func RegisterClaudeCodeTools(mcpServer *mcp.Server, handler *ClaudeCodeHandler) {
-	mcp.AddTool(mcpServer, &mcp.Tool{
+func RegisterClaudeCodeTools(mcpServer *mcp.Server, dispatcher *Dispatcher, handler *ClaudeCodeHandler) {
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "export-session",
 		Description: `Export current reasoning session to a portable JSON format for backup, sharing, or later restoration.
 
@@ -371,7 +391,7 @@ func RegisterClaudeCodeTools(mcpServer *mcp.Server, handler *ClaudeCodeHandler)
 **Example:** {"session_id": "debug-session-123", "compress": true}`,
 	}, handler.HandleExportSession)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "import-session",
 		Description: `Import a previously exported reasoning session with merge strategy control.
 
@@ -392,7 +412,7 @@ func RegisterClaudeCodeTools(mcpServer *mcp.Server, handler *ClaudeCodeHandler)
 **Example:** {"export_data": "...", "merge_strategy": "merge"}`,
 	}, handler.HandleImportSession)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "list-presets",
 		Description: `List available workflow presets for common development tasks.
 
@@ -417,7 +437,7 @@ func RegisterClaudeCodeTools(mcpServer *mcp.Server, handler *ClaudeCodeHandler)
 **Example:** {"category": "code"}`,
 	}, handler.HandleListPresets)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "run-preset",
 		Description: `Execute a workflow preset with provided inputs.
 
@@ -430,6 +450,10 @@ func RegisterClaudeCodeTools(mcpServer *mcp.Server, handler *ClaudeCodeHandler)
 **Returns:**
 - preset_id: Executed preset identifier
 - status: "success", "partial", "failed", or "dry_run"
+
+Steps run in order through the same dispatcher as MCP calls. Optional steps
+that fail are recorded and skipped over; a required step that fails stops the
+run. Steps depending on a skipped step are skipped.
 - steps_completed: Number of steps completed
 - total_steps: Total steps in preset
 - step_results: Array of individual step results
@@ -439,7 +463,7 @@ func RegisterClaudeCodeTools(mcpServer *mcp.Server, handler *ClaudeCodeHandler)
 **Example:** {"preset_id": "code-review", "input": {"code": "func example() {...}"}}`,
 	}, handler.HandleRunPreset)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "format-response",
 		Description: `Apply format optimization to reduce response size for Claude Code.
 
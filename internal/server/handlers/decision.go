@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"unified-thinking/internal/analysis"
 	"unified-thinking/internal/reasoning"
 	"unified-thinking/internal/storage"
+	"unified-thinking/internal/streaming"
 	"unified-thinking/internal/types"
 )
 
@@ -21,9 +23,15 @@ type DecisionHandler struct {
 	llmProblemDecomposer *reasoning.LLMProblemDecomposer
 	sensitivityAnalyzer  *analysis.SensitivityAnalyzer
 	metadataGen          *MetadataGenerator
+
+	mu                 sync.Mutex
+	decisionMakers     map[string]*reasoning.DecisionMaker     // workspace -> decision maker
+	problemDecomposers map[string]*reasoning.ProblemDecomposer // workspace -> decomposer
 }
 
-// NewDecisionHandler creates a new decision handler
+// NewDecisionHandler creates a new decision handler. The decision maker and
+// problem decomposer serve the workspace store is bound to; those for other
+// workspaces are created on first use over their storage views.
 func NewDecisionHandler(
 	store storage.Storage,
 	decisionMaker *reasoning.DecisionMaker,
@@ -36,7 +44,61 @@ func NewDecisionHandler(
 		problemDecomposer:   problemDecomposer,
 		sensitivityAnalyzer: sensitivityAnalyzer,
 		metadataGen:         NewMetadataGenerator(),
+		decisionMakers:      map[string]*reasoning.DecisionMaker{storage.WorkspaceOf(store): decisionMaker},
+		problemDecomposers:  map[string]*reasoning.ProblemDecomposer{storage.WorkspaceOf(store): problemDecomposer},
+	}
+}
+
+// requestWorkspace validates a request's workspace and normalizes it, with
+// empty meaning the workspace store is bound to
+func (h *DecisionHandler) requestWorkspace(workspace string) (string, error) {
+	if err := storage.ValidateWorkspace(workspace); err != nil {
+		return "", &ValidationError{"workspace", err.Error()}
 	}
+	if workspace == "" {
+		workspace = storage.WorkspaceOf(h.storage)
+	}
+	return storage.NormalizeWorkspace(workspace), nil
+}
+
+// decisionMakerFor returns the decision maker for a workspace
+func (h *DecisionHandler) decisionMakerFor(workspace string) (*reasoning.DecisionMaker, error) {
+	workspace, err := h.requestWorkspace(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if decisionMaker, exists := h.decisionMakers[workspace]; exists {
+		return decisionMaker, nil
+	}
+	decisionMaker := reasoning.NewDecisionMaker()
+	if decisionStore, ok := storage.ForWorkspace(h.storage, workspace).(reasoning.DecisionStorage); ok {
+		decisionMaker.SetStorage(decisionStore)
+	}
+	h.decisionMakers[workspace] = decisionMaker
+	return decisionMaker, nil
+}
+
+// problemDecomposerFor returns the problem decomposer for a workspace
+func (h *DecisionHandler) problemDecomposerFor(workspace string) (*reasoning.ProblemDecomposer, error) {
+	workspace, err := h.requestWorkspace(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if problemDecomposer, exists := h.problemDecomposers[workspace]; exists {
+		return problemDecomposer, nil
+	}
+	problemDecomposer := reasoning.NewProblemDecomposer()
+	if decompositionStore, ok := storage.ForWorkspace(h.storage, workspace).(reasoning.DecompositionStorage); ok {
+		problemDecomposer.SetStorage(decompositionStore)
+	}
+	h.problemDecomposers[workspace] = problemDecomposer
+	return problemDecomposer, nil
 }
 
 // SetLLMProblemDecomposer sets the LLM-based problem decomposer
@@ -50,9 +112,10 @@ func (h *DecisionHandler) SetLLMProblemDecomposer(llmDecomposer *reasoning.LLMPr
 
 // MakeDecisionRequest represents a decision-making request
 type MakeDecisionRequest struct {
-	Question string                     `json:"question"`
-	Options  []*types.DecisionOption    `json:"options"`
-	Criteria []*types.DecisionCriterion `json:"criteria"`
+	Question  string                     `json:"question"`
+	Options   []*types.DecisionOption    `json:"options"`
+	Criteria  []*types.DecisionCriterion `json:"criteria"`
+	Workspace string                     `json:"workspace,omitempty"`
 }
 
 // MakeDecisionResponse represents a decision-making response
@@ -64,8 +127,9 @@ type MakeDecisionResponse struct {
 
 // DecomposeProblemRequest represents a problem decomposition request
 type DecomposeProblemRequest struct {
-	Problem string  `json:"problem"`
-	Domain  *string `json:"domain,omitempty"` // Optional: "debugging", "proof", "architecture", "research", or auto-detect
+	Problem   string  `json:"problem"`
+	Domain    *string `json:"domain,omitempty"` // Optional: "debugging", "proof", "architecture", "research", or auto-detect
+	Workspace string  `json:"workspace,omitempty"`
 }
 
 // DecomposeProblemResponse represents a problem decomposition response
@@ -82,6 +146,59 @@ type DecomposeProblemResponse struct {
 	Metadata             *types.ResponseMetadata     `json:"metadata,omitempty"`
 }
 
+// ListDecisionsRequest represents a decision listing request
+type ListDecisionsRequest struct {
+	Limit     int    `json:"limit,omitempty"`
+	Offset    int    `json:"offset,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// ListDecisionsResponse represents a decision listing response
+type ListDecisionsResponse struct {
+	Decisions []*types.Decision `json:"decisions"`
+	Count     int               `json:"count"`
+	Total     int               `json:"total"`
+	Status    string            `json:"status"`
+}
+
+// GetDecisionRequest represents a decision retrieval request
+type GetDecisionRequest struct {
+	DecisionID string `json:"decision_id"`
+	Workspace  string `json:"workspace,omitempty"`
+}
+
+// GetDecisionResponse represents a decision retrieval response
+type GetDecisionResponse struct {
+	Decision *types.Decision `json:"decision"`
+	Status   string          `json:"status"`
+}
+
+// ListDecompositionsRequest represents a problem decomposition listing request
+type ListDecompositionsRequest struct {
+	Limit     int    `json:"limit,omitempty"`
+	Offset    int    `json:"offset,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// ListDecompositionsResponse represents a problem decomposition listing response
+type ListDecompositionsResponse struct {
+	Decompositions []*types.ProblemDecomposition `json:"decompositions"`
+	Count          int                           `json:"count"`
+	Status         string                        `json:"status"`
+}
+
+// GetDecompositionRequest represents a problem decomposition retrieval request
+type GetDecompositionRequest struct {
+	DecompositionID string `json:"decomposition_id"`
+	Workspace       string `json:"workspace,omitempty"`
+}
+
+// GetDecompositionResponse represents a problem decomposition retrieval response
+type GetDecompositionResponse struct {
+	Decomposition *types.ProblemDecomposition `json:"decomposition"`
+	Status        string                      `json:"status"`
+}
+
 // SensitivityAnalysisRequest represents a sensitivity analysis request
 type SensitivityAnalysisRequest struct {
 	TargetClaim    string   `json:"target_claim"`
@@ -105,7 +222,11 @@ func (h *DecisionHandler) HandleMakeDecision(ctx context.Context, req *mcp.CallT
 		return nil, nil, err
 	}
 
-	decision, err := h.decisionMaker.CreateDecision(input.Question, input.Options, input.Criteria)
+	decisionMaker, err := h.decisionMakerFor(input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	decision, err := decisionMaker.CreateDecision(input.Question, input.Options, input.Criteria)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -130,6 +251,11 @@ func (h *DecisionHandler) HandleDecomposeProblem(ctx context.Context, req *mcp.C
 		return nil, nil, err
 	}
 
+	problemDecomposer, err := h.problemDecomposerFor(input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// SEMANTIC CLASSIFICATION: Check if problem is actually decomposable
 	classifier := reasoning.NewProblemClassifier()
 	classification := classifier.ClassifyProblem(input.Problem)
@@ -175,11 +301,16 @@ func (h *DecisionHandler) HandleDecomposeProblem(ctx context.Context, req *mcp.C
 
 	// Use LLM decomposer if available, otherwise fall back to template-based
 	var decomposition *types.ProblemDecomposition
-	var err error
 	if h.llmProblemDecomposer != nil && h.llmProblemDecomposer.HasGenerator() {
+		// Stream the model's reasoning to clients that sent a progress token
+		ctx, _ := streaming.InjectReporter(ctx, req, "decompose-problem")
 		decomposition, err = h.llmProblemDecomposer.DecomposeProblemWithDomain(ctx, input.Problem, explicitDomain)
+		if err == nil {
+			// Record LLM decompositions alongside template ones so they can be listed later
+			err = problemDecomposer.SaveDecomposition(decomposition)
+		}
 	} else {
-		decomposition, err = h.problemDecomposer.DecomposeProblemWithDomain(input.Problem, explicitDomain)
+		decomposition, err = problemDecomposer.DecomposeProblemWithDomain(input.Problem, explicitDomain)
 	}
 	if err != nil {
 		return nil, nil, err
@@ -215,6 +346,117 @@ func (h *DecisionHandler) HandleDecomposeProblem(ctx context.Context, req *mcp.C
 	}, response, nil
 }
 
+// HandleListDecisions lists stored decisions, newest first
+func (h *DecisionHandler) HandleListDecisions(ctx context.Context, req *mcp.CallToolRequest, input ListDecisionsRequest) (*mcp.CallToolResult, *ListDecisionsResponse, error) {
+	limit, err := resolvePagination(input.Limit, input.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decisionMaker, err := h.decisionMakerFor(input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	all := decisionMaker.ListDecisions()
+	page := []*types.Decision{}
+	if input.Offset < len(all) {
+		page = all[input.Offset:]
+		if limit < len(page) {
+			page = page[:limit]
+		}
+	}
+
+	response := &ListDecisionsResponse{
+		Decisions: page,
+		Count:     len(page),
+		Total:     len(all),
+		Status:    "success",
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
+// HandleGetDecision retrieves a stored decision by ID
+func (h *DecisionHandler) HandleGetDecision(ctx context.Context, req *mcp.CallToolRequest, input GetDecisionRequest) (*mcp.CallToolResult, *GetDecisionResponse, error) {
+	if input.DecisionID == "" {
+		return nil, nil, &ValidationError{"decision_id", "decision_id is required"}
+	}
+
+	decisionMaker, err := h.decisionMakerFor(input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	decision, err := decisionMaker.GetDecision(input.DecisionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &GetDecisionResponse{
+		Decision: decision,
+		Status:   "success",
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
+// HandleListDecompositions lists stored problem decompositions, newest first
+func (h *DecisionHandler) HandleListDecompositions(ctx context.Context, req *mcp.CallToolRequest, input ListDecompositionsRequest) (*mcp.CallToolResult, *ListDecompositionsResponse, error) {
+	limit, err := resolvePagination(input.Limit, input.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	problemDecomposer, err := h.problemDecomposerFor(input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decompositions, err := problemDecomposer.ListDecompositions(limit, input.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &ListDecompositionsResponse{
+		Decompositions: decompositions,
+		Count:          len(decompositions),
+		Status:         "success",
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
+// HandleGetDecomposition retrieves a stored problem decomposition by ID
+func (h *DecisionHandler) HandleGetDecomposition(ctx context.Context, req *mcp.CallToolRequest, input GetDecompositionRequest) (*mcp.CallToolResult, *GetDecompositionResponse, error) {
+	if input.DecompositionID == "" {
+		return nil, nil, &ValidationError{"decomposition_id", "decomposition_id is required"}
+	}
+
+	problemDecomposer, err := h.problemDecomposerFor(input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	decomposition, err := problemDecomposer.GetDecomposition(input.DecompositionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &GetDecompositionResponse{
+		Decomposition: decomposition,
+		Status:        "success",
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
 // HandleSensitivityAnalysis processes sensitivity analysis requests
 func (h *DecisionHandler) HandleSensitivityAnalysis(ctx context.Context, req *mcp.CallToolRequest, input SensitivityAnalysisRequest) (*mcp.CallToolResult, *SensitivityAnalysisResponse, error) {
 	if err := ValidateSensitivityAnalysisRequest(&input); err != nil {
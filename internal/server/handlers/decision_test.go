@@ -700,3 +700,132 @@ func createManyAssumptions(n int) []string {
 	}
 	return assumptions
 }
+
+func TestHandleListAndGetDecisions(t *testing.T) {
+	handler := NewDecisionHandler(storage.NewMemoryStorage(), reasoning.NewDecisionMaker(),
+		reasoning.NewProblemDecomposer(), analysis.NewSensitivityAnalyzer())
+	ctx := context.Background()
+
+	_, created, err := handler.HandleMakeDecision(ctx, &mcp.CallToolRequest{}, MakeDecisionRequest{
+		Question: "Which queue?",
+		Options: []*types.DecisionOption{
+			{ID: "kafka", Name: "Kafka", Scores: map[string]float64{"throughput": 0.9}},
+			{ID: "sqs", Name: "SQS", Scores: map[string]float64{"throughput": 0.6}},
+		},
+		Criteria: []*types.DecisionCriterion{{ID: "throughput", Name: "Throughput", Weight: 1.0, Maximize: true}},
+	})
+	if err != nil {
+		t.Fatalf("HandleMakeDecision() error = %v", err)
+	}
+
+	_, list, err := handler.HandleListDecisions(ctx, nil, ListDecisionsRequest{})
+	if err != nil {
+		t.Fatalf("HandleListDecisions() error = %v", err)
+	}
+	if list.Count != 1 || list.Total != 1 || list.Decisions[0].ID != created.Decision.ID {
+		t.Errorf("HandleListDecisions() = %+v, want the created decision", list)
+	}
+
+	_, list, _ = handler.HandleListDecisions(ctx, nil, ListDecisionsRequest{Offset: 5})
+	if list.Count != 0 || list.Total != 1 {
+		t.Errorf("offset past end: count=%d total=%d, want 0 and 1", list.Count, list.Total)
+	}
+
+	_, got, err := handler.HandleGetDecision(ctx, nil, GetDecisionRequest{DecisionID: created.Decision.ID})
+	if err != nil {
+		t.Fatalf("HandleGetDecision() error = %v", err)
+	}
+	if got.Decision.Question != "Which queue?" {
+		t.Errorf("Question = %q, want Which queue?", got.Decision.Question)
+	}
+
+	if _, _, err := handler.HandleGetDecision(ctx, nil, GetDecisionRequest{}); err == nil {
+		t.Error("expected validation error for missing decision_id")
+	}
+	if _, _, err := handler.HandleListDecisions(ctx, nil, ListDecisionsRequest{Limit: -1}); err == nil {
+		t.Error("expected validation error for negative limit")
+	}
+}
+
+func TestHandleListAndGetDecompositions(t *testing.T) {
+	handler := NewDecisionHandler(storage.NewMemoryStorage(), reasoning.NewDecisionMaker(),
+		reasoning.NewProblemDecomposer(), analysis.NewSensitivityAnalyzer())
+	ctx := context.Background()
+
+	_, decomposed, err := handler.HandleDecomposeProblem(ctx, &mcp.CallToolRequest{}, DecomposeProblemRequest{
+		Problem: "How to improve CI/CD pipeline performance and reduce build times?",
+	})
+	if err != nil {
+		t.Fatalf("HandleDecomposeProblem() error = %v", err)
+	}
+	if decomposed.Decomposition == nil {
+		t.Skip("problem classified as non-decomposable")
+	}
+
+	_, list, err := handler.HandleListDecompositions(ctx, nil, ListDecompositionsRequest{})
+	if err != nil {
+		t.Fatalf("HandleListDecompositions() error = %v", err)
+	}
+	if list.Count != 1 || list.Decompositions[0].ID != decomposed.Decomposition.ID {
+		t.Errorf("HandleListDecompositions() = %+v, want the created decomposition", list)
+	}
+
+	_, got, err := handler.HandleGetDecomposition(ctx, nil, GetDecompositionRequest{DecompositionID: decomposed.Decomposition.ID})
+	if err != nil {
+		t.Fatalf("HandleGetDecomposition() error = %v", err)
+	}
+	if got.Decomposition.Problem != decomposed.Decomposition.Problem {
+		t.Errorf("Problem = %q, want %q", got.Decomposition.Problem, decomposed.Decomposition.Problem)
+	}
+
+	if _, _, err := handler.HandleGetDecomposition(ctx, nil, GetDecompositionRequest{DecompositionID: "missing"}); err == nil {
+		t.Error("expected error for missing decomposition")
+	}
+}
+
+func TestDecisionHandler_Workspaces(t *testing.T) {
+	handler := NewDecisionHandler(storage.NewMemoryStorage(), reasoning.NewDecisionMaker(),
+		reasoning.NewProblemDecomposer(), analysis.NewSensitivityAnalyzer())
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	_, created, err := handler.HandleMakeDecision(ctx, req, MakeDecisionRequest{
+		Question:  "Which queue?",
+		Options:   []*types.DecisionOption{{ID: "kafka", Name: "Kafka", Scores: map[string]float64{"throughput": 0.9}}},
+		Criteria:  []*types.DecisionCriterion{{ID: "throughput", Name: "Throughput", Weight: 1.0, Maximize: true}},
+		Workspace: "alpha",
+	})
+	if err != nil {
+		t.Fatalf("HandleMakeDecision() error = %v", err)
+	}
+	_, decomposed, err := handler.HandleDecomposeProblem(ctx, req, DecomposeProblemRequest{
+		Problem:   "How to improve CI/CD pipeline performance and reduce build times?",
+		Workspace: "alpha",
+	})
+	if err != nil || decomposed.Decomposition == nil {
+		t.Fatalf("HandleDecomposeProblem() = %+v, %v", decomposed, err)
+	}
+
+	if _, list, _ := handler.HandleListDecisions(ctx, req, ListDecisionsRequest{}); list.Total != 0 {
+		t.Errorf("default workspace listed %d decisions, want 0", list.Total)
+	}
+	if _, _, err := handler.HandleGetDecision(ctx, req, GetDecisionRequest{DecisionID: created.Decision.ID}); err == nil {
+		t.Error("default workspace should not find alpha's decision")
+	}
+	if _, list, _ := handler.HandleListDecompositions(ctx, req, ListDecompositionsRequest{}); list.Count != 0 {
+		t.Errorf("default workspace listed %d decompositions, want 0", list.Count)
+	}
+	if _, _, err := handler.HandleGetDecomposition(ctx, req, GetDecompositionRequest{DecompositionID: decomposed.Decomposition.ID}); err == nil {
+		t.Error("default workspace should not find alpha's decomposition")
+	}
+
+	if _, list, _ := handler.HandleListDecisions(ctx, req, ListDecisionsRequest{Workspace: "ALPHA"}); list.Total != 1 {
+		t.Errorf("alpha listed %d decisions, want 1", list.Total)
+	}
+	if _, _, err := handler.HandleGetDecomposition(ctx, req, GetDecompositionRequest{DecompositionID: decomposed.Decomposition.ID, Workspace: "alpha"}); err != nil {
+		t.Errorf("alpha HandleGetDecomposition() error = %v", err)
+	}
+	if _, _, err := handler.HandleListDecisions(ctx, req, ListDecisionsRequest{Workspace: "bad workspace!"}); err == nil {
+		t.Error("expected validation error for invalid workspace")
+	}
+}
@@ -0,0 +1,344 @@
+// Package handlers - Generic tool dispatch for workflows, presets and agents
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Dispatcher calls registered MCP tools in-process by name, so workflows,
+// presets and the agent can use any tool without hand-written wiring.
+// Tools are recorded by AddTool as they are registered with the MCP server.
+type Dispatcher struct {
+	mu    sync.RWMutex
+	tools map[string]*dispatchTool
+}
+
+// dispatchTool is a registered tool with its resolved input schema
+type dispatchTool struct {
+	tool   *mcp.Tool
+	schema *jsonschema.Resolved
+	call   func(ctx context.Context, req *mcp.CallToolRequest, arguments json.RawMessage) (any, error)
+}
+
+// NewDispatcher creates an empty dispatcher
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{tools: make(map[string]*dispatchTool)}
+}
+
+// AddTool registers a tool with the MCP server, as mcp.AddTool does, and
+// records it with the dispatcher. A nil dispatcher only registers the tool.
+func AddTool[In, Out any](server *mcp.Server, d *Dispatcher, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) {
+	mcp.AddTool(server, tool, handler)
+	if d == nil {
+		return
+	}
+
+	schema, err := inputSchema[In](tool)
+	if err != nil {
+		// mcp.AddTool panics on an invalid schema, so this is unreachable
+		panic(fmt.Sprintf("AddTool %q: %v", tool.Name, err))
+	}
+
+	// Describe the tool with the schema it was registered with
+	described := *tool
+	described.InputSchema = schema.Schema()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tools[tool.Name] = &dispatchTool{
+		tool:   &described,
+		schema: schema,
+		call: func(ctx context.Context, req *mcp.CallToolRequest, arguments json.RawMessage) (any, error) {
+			var in In
+			if err := json.Unmarshal(arguments, &in); err != nil {
+				return nil, fmt.Errorf("decode %s input: %w", tool.Name, err)
+			}
+			result, out, err := handler(ctx, req, in)
+			if err != nil {
+				return nil, err
+			}
+			if result != nil && result.IsError {
+				return nil, toolResultError(result)
+			}
+			if isNil(out) {
+				return resultContent(result), nil
+			}
+			return toGeneric(out)
+		},
+	}
+}
+
+// inputSchema resolves a tool's explicit input schema, or infers one from
+// its request type
+func inputSchema[In any](tool *mcp.Tool) (*jsonschema.Resolved, error) {
+	var schema *jsonschema.Schema
+	switch s := tool.InputSchema.(type) {
+	case nil:
+		inferred, err := jsonschema.For[In](&jsonschema.ForOptions{})
+		if err != nil {
+			return nil, err
+		}
+		schema = inferred
+	case *jsonschema.Schema:
+		schema = s
+	default:
+		data, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, err
+		}
+	}
+	return schema.Resolve(&jsonschema.ResolveOptions{ValidateDefaults: true})
+}
+
+// Middleware records the calling session in the context, so tools called
+// through the dispatcher resolve the same workspace as the outer call
+func (d *Dispatcher) Middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if callReq, ok := req.(*mcp.CallToolRequest); ok && callReq.Session != nil {
+			ctx = context.WithValue(ctx, sessionKey{}, callReq.Session)
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// sessionKey is the context key of the calling MCP session
+type sessionKey struct{}
+
+// Has reports whether a tool is registered
+func (d *Dispatcher) Has(name string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.tools[name]
+	return ok
+}
+
+// Tools returns the registered tools sorted by name. Each carries the input
+// schema used to decode its calls.
+func (d *Dispatcher) Tools() []*mcp.Tool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	tools := make([]*mcp.Tool, 0, len(d.tools))
+	for _, t := range d.tools {
+		tools = append(tools, t.tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// Call runs a tool with generic input and returns its output decoded as
+// generic JSON (usually map[string]interface{}).
+//
+// Input is decoded against the tool's schema: keys the tool does not accept
+// are dropped, since workflows pass their whole input to every step; values
+// are coerced to the declared type where unambiguous (a single value for an
+// array, a numeric string for a number); and defaults are applied before
+// validation. Tool errors, including error results, are returned as errors.
+func (d *Dispatcher) Call(ctx context.Context, name string, input map[string]interface{}) (interface{}, error) {
+	d.mu.RLock()
+	t, ok := d.tools[name]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown tool: %s", name)
+	}
+
+	arguments, err := decodeInput(t.schema, input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s input: %w", name, err)
+	}
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: name, Arguments: arguments}}
+	if session, ok := ctx.Value(sessionKey{}).(*mcp.ServerSession); ok {
+		req.Session = session
+	}
+	return t.call(ctx, req, arguments)
+}
+
+// decodeInput fits generic input to a schema and validates it
+func decodeInput(schema *jsonschema.Resolved, input map[string]interface{}) (json.RawMessage, error) {
+	normalized := make(map[string]any)
+	if len(input) > 0 {
+		generic, err := toGeneric(input)
+		if err != nil {
+			return nil, err
+		}
+		normalized, _ = generic.(map[string]any)
+	}
+
+	properties := schema.Schema().Properties
+	for key, value := range normalized {
+		prop, declared := properties[key]
+		switch {
+		case value == nil, len(properties) > 0 && !declared:
+			delete(normalized, key)
+		case declared:
+			normalized[key] = coerce(value, prop)
+		}
+	}
+
+	if err := schema.ApplyDefaults(&normalized); err != nil {
+		return nil, err
+	}
+	if err := schema.Validate(normalized); err != nil {
+		return nil, err
+	}
+	return json.Marshal(normalized)
+}
+
+// coerce converts a generic JSON value to the type a property declares,
+// leaving it unchanged when it already fits or no conversion applies
+func coerce(value any, prop *jsonschema.Schema) any {
+	if prop == nil {
+		return value
+	}
+	types := prop.Types
+	if prop.Type != "" {
+		types = []string{prop.Type}
+	}
+	if len(types) == 0 {
+		return value
+	}
+	for _, t := range types {
+		if fitsType(value, t) {
+			if t == "array" && prop.Items != nil {
+				items := value.([]any)
+				for i := range items {
+					items[i] = coerce(items[i], prop.Items)
+				}
+			}
+			return value
+		}
+	}
+
+	for _, t := range types {
+		switch t {
+		case "array":
+			if s, ok := value.(string); ok && strings.HasPrefix(strings.TrimSpace(s), "[") {
+				var items []any
+				if json.Unmarshal([]byte(s), &items) == nil {
+					return coerce(items, prop)
+				}
+			}
+			return coerce([]any{value}, prop)
+		case "string":
+			switch v := value.(type) {
+			case float64, bool:
+				return fmt.Sprint(v)
+			}
+		case "number", "integer":
+			if s, ok := value.(string); ok {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+					return f
+				}
+			}
+		case "boolean":
+			if s, ok := value.(string); ok {
+				if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+					return b
+				}
+			}
+		case "object":
+			if s, ok := value.(string); ok {
+				var object map[string]any
+				if json.Unmarshal([]byte(s), &object) == nil {
+					return object
+				}
+			}
+		}
+	}
+	return value
+}
+
+// fitsType reports whether a generic JSON value has a JSON schema type
+func fitsType(value any, t string) bool {
+	switch v := value.(type) {
+	case string:
+		return t == "string"
+	case bool:
+		return t == "boolean"
+	case float64:
+		return t == "number" || (t == "integer" && v == float64(int64(v)))
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	case nil:
+		return t == "null"
+	}
+	return false
+}
+
+// toGeneric converts a value to its generic JSON form
+func toGeneric(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// isNil reports whether a handler output is nil or a typed nil
+func isNil(value any) bool {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// resultText joins the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// resultContent returns the content of a tool without structured output:
+// its text decoded as JSON when possible, otherwise the text itself
+func resultContent(result *mcp.CallToolResult) any {
+	text := resultText(result)
+	var generic any
+	if json.Unmarshal([]byte(text), &generic) == nil {
+		return generic
+	}
+	return text
+}
+
+// toolResultError returns the error of an error result
+func toolResultError(result *mcp.CallToolResult) error {
+	if err := result.GetError(); err != nil {
+		return err
+	}
+	if text := resultText(result); text != "" {
+		return fmt.Errorf("%s", text)
+	}
+	return fmt.Errorf("tool returned an error")
+}
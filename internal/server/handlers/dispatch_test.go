@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type dispatchRequest struct {
+	Statement  string   `json:"statement"`
+	Premises   []string `json:"premises,omitempty"`
+	Confidence float64  `json:"confidence,omitempty"`
+	Strict     bool     `json:"strict,omitempty"`
+	Label      string   `json:"label,omitempty"`
+}
+
+type dispatchResponse struct {
+	Input dispatchRequest `json:"input"`
+}
+
+func newTestDispatcher(t *testing.T) *Dispatcher {
+	t.Helper()
+	d := NewDispatcher()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0"}, nil)
+
+	AddTool(server, d, &mcp.Tool{Name: "echo"}, func(ctx context.Context, req *mcp.CallToolRequest, input dispatchRequest) (*mcp.CallToolResult, *dispatchResponse, error) {
+		if req.Params.Name != "echo" {
+			t.Errorf("request name = %q", req.Params.Name)
+		}
+		return nil, &dispatchResponse{Input: input}, nil
+	})
+	AddTool(server, d, &mcp.Tool{
+		Name: "explicit",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"statement": map[string]any{"type": "string"},
+				"label":     map[string]any{"type": "string", "default": "unlabelled"},
+			},
+			"required": []string{"statement"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input dispatchRequest) (*mcp.CallToolResult, *dispatchResponse, error) {
+		return nil, &dispatchResponse{Input: input}, nil
+	})
+	AddTool(server, d, &mcp.Tool{Name: "fails"}, func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, any, error) {
+		return nil, nil, errors.New("boom")
+	})
+	AddTool(server, d, &mcp.Tool{Name: "error-result"}, func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "bad input"}}}, nil, nil
+	})
+	AddTool(server, d, &mcp.Tool{Name: "text-only"}, func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: `{"ok": true}`}}}, nil, nil
+	})
+	return d
+}
+
+func TestDispatcher_DecodesInput(t *testing.T) {
+	d := newTestDispatcher(t)
+	result, err := d.Call(context.Background(), "echo", map[string]interface{}{
+		"statement":  "All men are mortal",
+		"premises":   "Socrates is a man",
+		"confidence": "0.8",
+		"strict":     "true",
+		"problem":    "extra workflow input",
+		"label":      nil,
+	})
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	input := result.(map[string]interface{})["input"].(map[string]interface{})
+	if input["confidence"] != 0.8 || input["strict"] != true {
+		t.Errorf("scalars not coerced: %v", input)
+	}
+	if premises := input["premises"].([]interface{}); len(premises) != 1 || premises[0] != "Socrates is a man" {
+		t.Errorf("premises = %v", premises)
+	}
+
+	// A JSON array passed as a string is decoded
+	result, err = d.Call(context.Background(), "echo", map[string]interface{}{"statement": 42, "premises": `["a", "b"]`})
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	input = result.(map[string]interface{})["input"].(map[string]interface{})
+	if input["statement"] != "42" || len(input["premises"].([]interface{})) != 2 {
+		t.Errorf("input = %v", input)
+	}
+}
+
+func TestDispatcher_ExplicitSchema(t *testing.T) {
+	d := newTestDispatcher(t)
+	result, err := d.Call(context.Background(), "explicit", map[string]interface{}{"statement": "p", "strict": true})
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	input := result.(map[string]interface{})["input"].(map[string]interface{})
+	if input["label"] != "unlabelled" || input["strict"] != nil {
+		t.Errorf("defaults or filtering not applied: %v", input)
+	}
+
+	if _, err := d.Call(context.Background(), "explicit", nil); err == nil || !strings.Contains(err.Error(), "invalid explicit input") {
+		t.Errorf("err = %v, want a validation error for the missing statement", err)
+	}
+}
+
+func TestDispatcher_Errors(t *testing.T) {
+	d := newTestDispatcher(t)
+	if _, err := d.Call(context.Background(), "fails", nil); err == nil || err.Error() != "boom" {
+		t.Errorf("handler error = %v", err)
+	}
+	if _, err := d.Call(context.Background(), "error-result", nil); err == nil || err.Error() != "bad input" {
+		t.Errorf("error result = %v", err)
+	}
+	if _, err := d.Call(context.Background(), "missing", nil); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+	if result, err := d.Call(context.Background(), "text-only", nil); err != nil || result.(map[string]interface{})["ok"] != true {
+		t.Errorf("text result = %v, %v", result, err)
+	}
+}
+
+func TestDispatcher_Tools(t *testing.T) {
+	d := newTestDispatcher(t)
+	tools := d.Tools()
+	if len(tools) != 5 || tools[0].Name != "echo" || !d.Has("fails") || d.Has("missing") {
+		t.Fatalf("tools = %v", tools)
+	}
+	if tools[0].InputSchema == nil {
+		t.Error("inferred schema not recorded")
+	}
+
+	// A nil dispatcher only registers with the server
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0"}, nil)
+	AddTool(server, nil, &mcp.Tool{Name: "echo"}, func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, any, error) {
+		return nil, nil, nil
+	})
+}
@@ -1,7 +1,8 @@
 // Package handlers provides MCP tool handlers for enhanced reasoning capabilities.
 //
 // This module adds handlers for analogical reasoning, argument decomposition,
-// fallacy detection, workflow orchestration, and evidence pipeline integration.
+// workflow orchestration, and evidence pipeline integration. Fallacy detection
+// is registered separately (see RegisterFallacyTools).
 package handlers
 
 import (
@@ -12,23 +13,21 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"unified-thinking/internal/analysis"
 	"unified-thinking/internal/integration"
-	"unified-thinking/internal/orchestration"
 	"unified-thinking/internal/reasoning"
-	"unified-thinking/internal/validation"
+	"unified-thinking/internal/storage"
 )
 
 // RegisterEnhancedTools registers all enhanced reasoning tools
 func RegisterEnhancedTools(
 	mcpServer *mcp.Server,
+	dispatcher *Dispatcher,
 	analogicalReasoner *reasoning.AnalogicalReasoner,
 	argumentAnalyzer *analysis.ArgumentAnalyzer,
-	fallacyDetector *validation.FallacyDetector,
-	orchestrator *orchestration.Orchestrator,
 	evidencePipeline *integration.EvidencePipeline,
 	causalTemporalIntegration *integration.CausalTemporalIntegration,
 ) {
 	// Analogical Reasoning Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name:        "find-analogy",
 		Description: "Find analogies between source and target domains for cross-domain reasoning. Required: source_domain (string), target_problem (string). Optional: constraints (array). Example: {\"source_domain\": \"biology: immune system\", \"target_problem\": \"How to protect computer network?\"}",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input FindAnalogyRequest) (*mcp.CallToolResult, *FindAnalogyResponse, error) {
@@ -50,7 +49,7 @@ func RegisterEnhancedTools(
 		}, response, nil
 	})
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name:        "apply-analogy",
 		Description: "Apply an existing analogy to a new context. Required: analogy_id (from find-analogy), target_context (string). Example: {\"analogy_id\": \"analogy_123\", \"target_context\": \"New security scenario\"}",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ApplyAnalogyRequest) (*mcp.CallToolResult, *ApplyAnalogyResponse, error) {
@@ -73,7 +72,7 @@ func RegisterEnhancedTools(
 	})
 
 	// Argument Analysis Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name:        "decompose-argument",
 		Description: "Break down an argument into premises, claims, assumptions, and inference chains. Required: argument (string). Example: {\"argument\": \"We should adopt policy X because studies show it reduces Y by 30%\"}",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input DecomposeArgumentRequest) (*mcp.CallToolResult, *DecomposeArgumentResponse, error) {
@@ -95,7 +94,7 @@ func RegisterEnhancedTools(
 		}, response, nil
 	})
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name:        "generate-counter-arguments",
 		Description: "Generate counter-arguments for a given argument using multiple strategies. Required: argument_id (from decompose-argument). Example: {\"argument_id\": \"arg_123\"}",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input GenerateCounterArgumentsRequest) (*mcp.CallToolResult, *GenerateCounterArgumentsResponse, error) {
@@ -118,69 +117,8 @@ func RegisterEnhancedTools(
 		}, response, nil
 	})
 
-	// Fallacy Detection Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
-		Name:        "detect-fallacies",
-		Description: "Detect formal and informal logical fallacies in reasoning. Required: content (string). Optional: check_formal (bool), check_informal (bool). Detects ad hominem, strawman, false dichotomy, etc. NOTE: For cognitive biases (confirmation bias, anchoring, etc.), use detect-biases instead. Example: {\"content\": \"Everyone says X is true, so it must be\", \"check_formal\": true, \"check_informal\": true}",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input DetectFallaciesRequest) (*mcp.CallToolResult, *DetectFallaciesResponse, error) {
-		if err := ValidateDetectFallaciesRequest(&input); err != nil {
-			return nil, nil, err
-		}
-		fallacies := fallacyDetector.DetectFallacies(input.Content, input.CheckFormal, input.CheckInformal)
-
-		response := &DetectFallaciesResponse{
-			Fallacies: fallacies,
-			Count:     len(fallacies),
-			Status:    "success",
-		}
-
-		return &mcp.CallToolResult{
-			Content: toJSONContent(response),
-		}, response, nil
-	})
-
-	// Workflow Orchestration Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
-		Name:        "execute-workflow",
-		Description: "Execute a predefined reasoning workflow with automatic tool chaining",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input ExecuteWorkflowRequest) (*mcp.CallToolResult, *ExecuteWorkflowResponse, error) {
-		result, err := orchestrator.ExecuteWorkflow(ctx, input.WorkflowID, input.Input)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		response := &ExecuteWorkflowResponse{
-			Result: result,
-			Status: "success",
-		}
-
-		return &mcp.CallToolResult{
-			Content: toJSONContent(response),
-		}, response, nil
-	})
-
-	mcp.AddTool(mcpServer, &mcp.Tool{
-		Name:        "list-workflows",
-		Description: "List all available reasoning workflows",
-		InputSchema: map[string]any{
-			"type":       "object",
-			"properties": map[string]any{},
-		},
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input EmptyRequest) (*mcp.CallToolResult, *ListWorkflowsResponse, error) {
-		workflows := orchestrator.ListWorkflows()
-
-		response := &ListWorkflowsResponse{
-			Workflows: workflows,
-			Count:     len(workflows),
-		}
-
-		return &mcp.CallToolResult{
-			Content: toJSONContent(response),
-		}, response, nil
-	})
-
 	// Evidence Pipeline Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name:        "process-evidence-pipeline",
 		Description: "Process evidence and auto-update beliefs, causal graphs, and decisions. Required: content (string), source (string), supports_claim (bool). Optional: claim_id. Example: {\"content\": \"Study shows X increases Y by 30%\", \"source\": \"Journal of Science 2024\", \"supports_claim\": true}",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ProcessEvidencePipelineRequest) (*mcp.CallToolResult, *ProcessEvidencePipelineResponse, error) {
@@ -208,7 +146,7 @@ func RegisterEnhancedTools(
 	})
 
 	// Causal-Temporal Integration Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name:        "analyze-temporal-causal-effects",
 		Description: "Analyze how causal effects evolve across different time horizons. Required: graph_id (from build-causal-graph), variable_id, intervention_type (\"increase\"/\"decrease\"/\"remove\"/\"introduce\"). Example: {\"graph_id\": \"graph_123\", \"variable_id\": \"marketing_spend\", \"intervention_type\": \"increase\"}",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input AnalyzeTemporalCausalEffectsRequest) (*mcp.CallToolResult, *AnalyzeTemporalCausalEffectsResponse, error) {
@@ -234,7 +172,7 @@ func RegisterEnhancedTools(
 		}, response, nil
 	})
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name:        "analyze-decision-timing",
 		Description: "Determine optimal timing for decisions based on causal and temporal analysis. Required: situation (string). Optional: causal_graph_id. Example: {\"situation\": \"When to launch product?\", \"causal_graph_id\": \"graph_123\"}",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input AnalyzeDecisionTimingRequest) (*mcp.CallToolResult, *AnalyzeDecisionTimingResponse, error) {
@@ -306,31 +244,16 @@ type DetectFallaciesRequest struct {
 	Content       string `json:"content"`
 	CheckFormal   bool   `json:"check_formal"`
 	CheckInformal bool   `json:"check_informal"`
+	Workspace     string `json:"workspace,omitempty"` // Selects the enabled rules
 }
 
 type DetectFallaciesResponse struct {
 	Fallacies interface{} `json:"fallacies"`
 	Count     int         `json:"count"`
+	Workspace string      `json:"workspace"`
 	Status    string      `json:"status"`
 }
 
-type ExecuteWorkflowRequest struct {
-	WorkflowID string                 `json:"workflow_id"`
-	Input      map[string]interface{} `json:"input"`
-}
-
-type ExecuteWorkflowResponse struct {
-	Result interface{} `json:"result"`
-	Status string      `json:"status"`
-}
-
-// EmptyRequest type removed - using the one from branches.go
-
-type ListWorkflowsResponse struct {
-	Workflows interface{} `json:"workflows"`
-	Count     int         `json:"count"`
-}
-
 type ProcessEvidencePipelineRequest struct {
 	Content       string `json:"content"`
 	Source        string `json:"source"`
@@ -467,6 +390,9 @@ func ValidateDetectFallaciesRequest(req *DetectFallaciesRequest) error {
 	if len(req.Content) > MaxContentLength {
 		return &ValidationError{"content", fmt.Sprintf("content exceeds max length of %d", MaxContentLength)}
 	}
+	if err := storage.ValidateWorkspace(req.Workspace); err != nil {
+		return &ValidationError{"workspace", err.Error()}
+	}
 	return nil
 }
 
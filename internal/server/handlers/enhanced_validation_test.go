@@ -305,6 +305,15 @@ func TestValidateDetectFallaciesRequest(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid workspace",
+			req: &DetectFallaciesRequest{
+				Content:   "Some argument",
+				Workspace: "perf reviews",
+			},
+			wantErr: true,
+			errMsg:  "workspace contains invalid character",
+		},
 	}
 
 	for _, tt := range tests {
@@ -20,6 +20,9 @@ type EpisodicMemoryHandler struct {
 	retrospective *memory.RetrospectiveAnalyzer
 	kg            *knowledge.KnowledgeGraph
 	extractor     *knowledge.TrajectoryExtractor
+	// resolveWorkspace maps a request's explicit workspace (possibly empty)
+	// to the workspace to use, e.g. the one selected for the MCP session
+	resolveWorkspace func(req *mcp.CallToolRequest, workspace string) string
 }
 
 // NewEpisodicMemoryHandler creates a new episodic memory handler
@@ -39,6 +42,19 @@ func NewEpisodicMemoryHandler(store *memory.EpisodicMemoryStore, tracker *memory
 	}
 }
 
+// SetWorkspaceResolver sets how tool calls without an explicit workspace pick one
+func (h *EpisodicMemoryHandler) SetWorkspaceResolver(resolve func(req *mcp.CallToolRequest, workspace string) string) {
+	h.resolveWorkspace = resolve
+}
+
+// workspaceFor resolves the workspace for a tool call
+func (h *EpisodicMemoryHandler) workspaceFor(req *mcp.CallToolRequest, workspace string) string {
+	if h.resolveWorkspace == nil {
+		return workspace
+	}
+	return h.resolveWorkspace(req, workspace)
+}
+
 // StartSessionRequest starts tracking a reasoning session
 type StartSessionRequest struct {
 	SessionID   string                 `json:"session_id"`
@@ -48,6 +64,7 @@ type StartSessionRequest struct {
 	Context     string                 `json:"context,omitempty"`
 	Complexity  float64                `json:"complexity,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Workspace   string                 `json:"workspace,omitempty"`
 }
 
 // StartSessionResponse returns session details
@@ -90,6 +107,7 @@ func (h *EpisodicMemoryHandler) startSession(ctx context.Context, req StartSessi
 		Goals:       req.Goals,
 		Domain:      req.Domain,
 		Complexity:  req.Complexity,
+		Workspace:   req.Workspace,
 	}
 
 	// Start session
@@ -224,6 +242,7 @@ type GetRecommendationsRequest struct {
 	Context     string   `json:"context,omitempty"`
 	Complexity  float64  `json:"complexity,omitempty"`
 	Limit       int      `json:"limit,omitempty"`
+	Workspace   string   `json:"workspace,omitempty"`
 }
 
 // GetRecommendationsResponse returns recommendations
@@ -266,6 +285,7 @@ func (h *EpisodicMemoryHandler) getRecommendations(ctx context.Context, req GetR
 		Goals:       req.Goals,
 		Domain:      req.Domain,
 		Complexity:  req.Complexity,
+		Workspace:   req.Workspace,
 	}
 
 	// Find similar trajectories
@@ -310,6 +330,7 @@ type SearchTrajectoriesRequest struct {
 	MinSuccess  float64  `json:"min_success,omitempty"`
 	ProblemType string   `json:"problem_type,omitempty"`
 	Limit       int      `json:"limit,omitempty"`
+	Workspace   string   `json:"workspace,omitempty"`
 }
 
 // SearchTrajectoriesResponse returns matching trajectories
@@ -362,6 +383,11 @@ func (h *EpisodicMemoryHandler) searchTrajectories(_ context.Context, req Search
 	}
 	filtered := make([]*memory.ReasoningTrajectory, 0, limit)
 	for _, traj := range allTrajectories {
+		// Filter by workspace
+		if !traj.InWorkspace(req.Workspace) {
+			continue
+		}
+
 		// Filter by domain
 		if req.Domain != "" && traj.Domain != req.Domain {
 			continue
@@ -509,8 +535,8 @@ func ComputeProblemHash(problem *memory.ProblemDescription) string {
 }
 
 // RegisterEpisodicMemoryTools registers episodic memory tools with the MCP server
-func RegisterEpisodicMemoryTools(mcpServer *mcp.Server, handler *EpisodicMemoryHandler) {
-	mcp.AddTool(mcpServer, &mcp.Tool{
+func RegisterEpisodicMemoryTools(mcpServer *mcp.Server, dispatcher *Dispatcher, handler *EpisodicMemoryHandler) {
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "start-reasoning-session",
 		Description: `Start tracking a reasoning session to build episodic memory and learn from experience.
 
@@ -525,6 +551,7 @@ recognize successful patterns, and provide adaptive recommendations.
 - context (optional): Additional context about the problem
 - complexity (optional): Estimated complexity 0.0-1.0
 - metadata (optional): Additional metadata
+- workspace (optional): Project namespace (default: session workspace)
 
 **Returns:**
 - session_id: Session identifier
@@ -546,6 +573,8 @@ recognize successful patterns, and provide adaptive recommendations.
   "complexity": 0.6
 }`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input StartSessionRequest) (*mcp.CallToolResult, *StartSessionResponse, error) {
+		input.Workspace = handler.workspaceFor(req, input.Workspace)
+
 		var params map[string]interface{}
 		paramsBytes, err := json.Marshal(input)
 		if err != nil {
@@ -574,7 +603,7 @@ recognize successful patterns, and provide adaptive recommendations.
 		return result, response, nil
 	})
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "complete-reasoning-session",
 		Description: `Complete a reasoning session and store the trajectory for learning.
 
@@ -640,7 +669,7 @@ The system learns which approaches work best for different problem types.
 		return result, response, nil
 	})
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "get-recommendations",
 		Description: `Get adaptive recommendations based on episodic memory of similar past problems.
 
@@ -654,6 +683,7 @@ successful reasoning sessions. Includes learned patterns and historical success
 - context (optional): Additional context
 - complexity (optional): Estimated complexity (0.0-1.0)
 - limit (optional): Max recommendations (default: 5)
+- workspace (optional): Only use trajectories from this workspace (default: session workspace)
 
 **Returns:**
 - recommendations: Array of recommendations with:
@@ -680,6 +710,8 @@ successful reasoning sessions. Includes learned patterns and historical success
   "limit": 3
 }`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetRecommendationsRequest) (*mcp.CallToolResult, *GetRecommendationsResponse, error) {
+		input.Workspace = handler.workspaceFor(req, input.Workspace)
+
 		var params map[string]interface{}
 		paramsBytes, err := json.Marshal(input)
 		if err != nil {
@@ -709,7 +741,7 @@ successful reasoning sessions. Includes learned patterns and historical success
 		return result, response, nil
 	})
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "search-trajectories",
 		Description: `Search for past reasoning trajectories to learn from experience.
 
@@ -722,6 +754,7 @@ understanding what worked in the past and learning from both successes and failu
 - min_success (optional): Minimum success score (0.0-1.0)
 - problem_type (optional): Filter by problem type
 - limit (optional): Max results (default: 10)
+- workspace (optional): Workspace to search (default: session workspace)
 
 **Returns:**
 - trajectories: Array of trajectory summaries with:
@@ -749,6 +782,8 @@ understanding what worked in the past and learning from both successes and failu
   "limit": 5
 }`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input SearchTrajectoriesRequest) (*mcp.CallToolResult, *SearchTrajectoriesResponse, error) {
+		input.Workspace = handler.workspaceFor(req, input.Workspace)
+
 		var params map[string]interface{}
 		paramsBytes, err := json.Marshal(input)
 		if err != nil {
@@ -777,7 +812,7 @@ understanding what worked in the past and learning from both successes and failu
 		return result, response, nil
 	})
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "analyze-trajectory",
 		Description: `Perform retrospective analysis of a completed reasoning session.
 
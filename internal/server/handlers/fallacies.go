@@ -0,0 +1,202 @@
+// Package handlers - Fallacy detection and fallacy rule pack tools
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/storage"
+	"unified-thinking/internal/validation"
+)
+
+// MaxFallacyRuleIDs limits the rule IDs a configure-fallacy-rules call may name
+const MaxFallacyRuleIDs = 100
+
+// FallacyHandler detects fallacies and manages the fallacy rules enabled in
+// each workspace
+type FallacyHandler struct {
+	detector *validation.FallacyDetector
+	// resolveWorkspace maps a request's explicit workspace (possibly empty)
+	// to the workspace to use, e.g. the one selected for the MCP session
+	resolveWorkspace func(req *mcp.CallToolRequest, workspace string) string
+}
+
+// NewFallacyHandler creates a new fallacy handler
+func NewFallacyHandler(detector *validation.FallacyDetector) *FallacyHandler {
+	return &FallacyHandler{detector: detector}
+}
+
+// SetWorkspaceResolver sets how tool calls without an explicit workspace pick one
+func (h *FallacyHandler) SetWorkspaceResolver(resolve func(req *mcp.CallToolRequest, workspace string) string) {
+	h.resolveWorkspace = resolve
+}
+
+// workspaceFor resolves the workspace for a tool call
+func (h *FallacyHandler) workspaceFor(req *mcp.CallToolRequest, workspace string) string {
+	if h.resolveWorkspace == nil {
+		return storage.NormalizeWorkspace(workspace)
+	}
+	return h.resolveWorkspace(req, workspace)
+}
+
+// ListFallacyRulesRequest lists fallacy rules
+type ListFallacyRulesRequest struct {
+	Workspace   string `json:"workspace,omitempty"`    // Workspace whose enabled state is reported
+	Category    string `json:"category,omitempty"`     // Only rules of this category
+	Pack        string `json:"pack,omitempty"`         // Only rules of this pack
+	EnabledOnly bool   `json:"enabled_only,omitempty"` // Only rules enabled in the workspace
+}
+
+// ListFallacyRulesResponse lists fallacy rules with their precision stats
+type ListFallacyRulesResponse struct {
+	Workspace string                            `json:"workspace"`
+	Rules     []*validation.FallacyRuleInfo     `json:"rules"`
+	Packs     []*validation.FallacyRulePackInfo `json:"packs"`
+	Count     int                               `json:"count"`
+	Status    string                            `json:"status"`
+}
+
+// ConfigureFallacyRulesRequest enables and disables fallacy rules in a workspace
+type ConfigureFallacyRulesRequest struct {
+	Workspace string   `json:"workspace,omitempty"`
+	Enable    []string `json:"enable,omitempty"`  // Rule IDs to enable
+	Disable   []string `json:"disable,omitempty"` // Rule IDs to disable
+	Reset     bool     `json:"reset,omitempty"`   // Drop earlier settings first, restoring pack defaults
+}
+
+// ConfigureFallacyRulesResponse reports the rules enabled in the workspace afterwards
+type ConfigureFallacyRulesResponse struct {
+	Workspace string   `json:"workspace"`
+	Enabled   []string `json:"enabled"`
+	Disabled  []string `json:"disabled"`
+	Status    string   `json:"status"`
+}
+
+// detectFallacies is the typed implementation of detect-fallacies
+func (h *FallacyHandler) detectFallacies(workspace string, req DetectFallaciesRequest) *DetectFallaciesResponse {
+	fallacies := h.detector.DetectFallaciesIn(workspace, req.Content, req.CheckFormal, req.CheckInformal)
+	return &DetectFallaciesResponse{
+		Fallacies: fallacies,
+		Count:     len(fallacies),
+		Workspace: workspace,
+		Status:    "success",
+	}
+}
+
+// listRules is the typed implementation of list-fallacy-rules
+func (h *FallacyHandler) listRules(workspace string, req ListFallacyRulesRequest) *ListFallacyRulesResponse {
+	rules := make([]*validation.FallacyRuleInfo, 0)
+	for _, rule := range h.detector.ListRules(workspace) {
+		if req.Category != "" && string(rule.Category) != req.Category {
+			continue
+		}
+		if req.Pack != "" && rule.Pack != req.Pack {
+			continue
+		}
+		if req.EnabledOnly && !rule.Enabled {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return &ListFallacyRulesResponse{
+		Workspace: workspace,
+		Rules:     rules,
+		Packs:     h.detector.RulePacks(),
+		Count:     len(rules),
+		Status:    "success",
+	}
+}
+
+// configureRules is the typed implementation of configure-fallacy-rules
+func (h *FallacyHandler) configureRules(workspace string, req ConfigureFallacyRulesRequest) (*ConfigureFallacyRulesResponse, error) {
+	if err := h.detector.ConfigureRules(workspace, req.Enable, req.Disable, req.Reset); err != nil {
+		return nil, err
+	}
+
+	response := &ConfigureFallacyRulesResponse{
+		Workspace: workspace,
+		Enabled:   []string{},
+		Disabled:  []string{},
+		Status:    "success",
+	}
+	for _, rule := range h.detector.ListRules(workspace) {
+		if rule.Enabled {
+			response.Enabled = append(response.Enabled, rule.ID)
+		} else {
+			response.Disabled = append(response.Disabled, rule.ID)
+		}
+	}
+	return response, nil
+}
+
+// RegisterFallacyTools registers fallacy detection and rule pack tools
+func RegisterFallacyTools(mcpServer *mcp.Server, dispatcher *Dispatcher, handler *FallacyHandler) {
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
+		Name:        "detect-fallacies",
+		Description: "Detect formal and informal logical fallacies in reasoning using the fallacy rules enabled in the workspace (see list-fallacy-rules). Required: content (string). Optional: check_formal (bool), check_informal (bool, includes statistical and custom rule categories), workspace (default: session workspace). Detects ad hominem, strawman, false dichotomy, etc. NOTE: For cognitive biases (confirmation bias, anchoring, etc.), use detect-biases instead. Example: {\"content\": \"Everyone says X is true, so it must be\", \"check_formal\": true, \"check_informal\": true}",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input DetectFallaciesRequest) (*mcp.CallToolResult, *DetectFallaciesResponse, error) {
+		if err := ValidateDetectFallaciesRequest(&input); err != nil {
+			return nil, nil, err
+		}
+		response := handler.detectFallacies(handler.workspaceFor(req, input.Workspace), input)
+
+		return &mcp.CallToolResult{
+			Content: toJSONContent(response),
+		}, response, nil
+	})
+
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
+		Name:        "list-fallacy-rules",
+		Description: "List the fallacy rules used by detect-fallacies: the built-in core pack plus rule packs loaded from FALLACY_RULES_DIR. Optional: workspace (default: session workspace), category, pack, enabled_only (bool). Returns each rule's id, pack, category, severity, confidence, whether it is enabled in the workspace, and stats: precision and recall on the rule's labelled examples (with missed examples and false alarms) and detections since startup. Example: {\"pack\": \"core\", \"category\": \"statistical\"}",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListFallacyRulesRequest) (*mcp.CallToolResult, *ListFallacyRulesResponse, error) {
+		if err := ValidateListFallacyRulesRequest(&input); err != nil {
+			return nil, nil, err
+		}
+		response := handler.listRules(handler.workspaceFor(req, input.Workspace), input)
+
+		return &mcp.CallToolResult{
+			Content: toJSONContent(response),
+		}, response, nil
+	})
+
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
+		Name:        "configure-fallacy-rules",
+		Description: "Enable or disable fallacy rules for a workspace. Optional: workspace (default: session workspace), enable (array of rule IDs), disable (array of rule IDs), reset (bool, drop earlier settings first). Settings last until the server restarts; rule packs set the defaults. Returns the enabled and disabled rule IDs. Example: {\"workspace\": \"perf-reviews\", \"enable\": [\"benchmark_cherry_picking\"], \"disable\": [\"appeal_to_emotion\"]}",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ConfigureFallacyRulesRequest) (*mcp.CallToolResult, *ConfigureFallacyRulesResponse, error) {
+		if err := ValidateConfigureFallacyRulesRequest(&input); err != nil {
+			return nil, nil, err
+		}
+		response, err := handler.configureRules(handler.workspaceFor(req, input.Workspace), input)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &mcp.CallToolResult{
+			Content: toJSONContent(response),
+		}, response, nil
+	})
+}
+
+// ValidateListFallacyRulesRequest validates a ListFallacyRulesRequest
+func ValidateListFallacyRulesRequest(req *ListFallacyRulesRequest) error {
+	if err := storage.ValidateWorkspace(req.Workspace); err != nil {
+		return &ValidationError{"workspace", err.Error()}
+	}
+	return nil
+}
+
+// ValidateConfigureFallacyRulesRequest validates a ConfigureFallacyRulesRequest
+func ValidateConfigureFallacyRulesRequest(req *ConfigureFallacyRulesRequest) error {
+	if err := storage.ValidateWorkspace(req.Workspace); err != nil {
+		return &ValidationError{"workspace", err.Error()}
+	}
+	if len(req.Enable) == 0 && len(req.Disable) == 0 && !req.Reset {
+		return &ValidationError{"enable", "enable, disable or reset is required. Example: {\"disable\": [\"appeal_to_emotion\"]}"}
+	}
+	if len(req.Enable)+len(req.Disable) > MaxFallacyRuleIDs {
+		return &ValidationError{"enable", fmt.Sprintf("at most %d rule IDs can be configured at once", MaxFallacyRuleIDs)}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/validation"
+)
+
+func TestFallacyHandler_WorkspaceRules(t *testing.T) {
+	handler := NewFallacyHandler(validation.NewFallacyDetector())
+	handler.SetWorkspaceResolver(func(req *mcp.CallToolRequest, workspace string) string {
+		if workspace == "" {
+			return "session-ws"
+		}
+		return workspace
+	})
+	content := "You can't trust his argument because he's an idiot."
+
+	configured, err := handler.configureRules(handler.workspaceFor(nil, ""), ConfigureFallacyRulesRequest{Disable: []string{"ad_hominem"}})
+	if err != nil {
+		t.Fatalf("configureRules() error = %v", err)
+	}
+	if configured.Workspace != "session-ws" || len(configured.Disabled) != 1 || configured.Disabled[0] != "ad_hominem" {
+		t.Errorf("unexpected configure response: %+v", configured)
+	}
+
+	detected := handler.detectFallacies(handler.workspaceFor(nil, ""), DetectFallaciesRequest{Content: content, CheckInformal: true})
+	if detected.Count != 0 {
+		t.Errorf("ad_hominem should be disabled in the session workspace, got %+v", detected.Fallacies)
+	}
+	detected = handler.detectFallacies(handler.workspaceFor(nil, "other"), DetectFallaciesRequest{Content: content, CheckInformal: true})
+	if detected.Count != 1 || detected.Workspace != "other" {
+		t.Errorf("ad_hominem should be detected in other workspaces, got %+v", detected)
+	}
+
+	if _, err := handler.configureRules("session-ws", ConfigureFallacyRulesRequest{Enable: []string{"benchmark_cherry_picking"}}); err == nil || !strings.Contains(err.Error(), "unknown fallacy rule") {
+		t.Errorf("expected unknown rule error, got %v", err)
+	}
+}
+
+func TestFallacyHandler_ListRules(t *testing.T) {
+	handler := NewFallacyHandler(validation.NewFallacyDetector())
+	if _, err := handler.configureRules("default", ConfigureFallacyRulesRequest{Disable: []string{"base_rate_neglect"}}); err != nil {
+		t.Fatalf("configureRules() error = %v", err)
+	}
+
+	listed := handler.listRules("default", ListFallacyRulesRequest{Category: "statistical"})
+	if listed.Count != 4 || len(listed.Packs) != 1 || listed.Packs[0].Name != "core" {
+		t.Fatalf("unexpected list response: %+v", listed)
+	}
+	for _, rule := range listed.Rules {
+		if rule.Stats.Precision == nil || rule.Stats.Recall == nil {
+			t.Errorf("%s: missing precision stats", rule.ID)
+		}
+	}
+
+	enabled := handler.listRules("default", ListFallacyRulesRequest{Category: "statistical", EnabledOnly: true})
+	if enabled.Count != 3 {
+		t.Errorf("got %d enabled statistical rules, want 3", enabled.Count)
+	}
+	if other := handler.listRules("default", ListFallacyRulesRequest{Pack: "missing"}); other.Count != 0 || other.Rules == nil {
+		t.Errorf("unknown pack should list no rules: %+v", other)
+	}
+}
+
+func TestValidateConfigureFallacyRulesRequest(t *testing.T) {
+	tests := map[string]struct {
+		req     ConfigureFallacyRulesRequest
+		wantErr bool
+	}{
+		"disable":           {ConfigureFallacyRulesRequest{Disable: []string{"ad_hominem"}}, false},
+		"reset only":        {ConfigureFallacyRulesRequest{Reset: true}, false},
+		"nothing to do":     {ConfigureFallacyRulesRequest{}, true},
+		"invalid workspace": {ConfigureFallacyRulesRequest{Workspace: "a b", Reset: true}, true},
+		"too many rules":    {ConfigureFallacyRulesRequest{Enable: make([]string, MaxFallacyRuleIDs+1)}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ValidateConfigureFallacyRulesRequest(&tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfigureFallacyRulesRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,14 +6,20 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/export"
 	"unified-thinking/internal/modes"
+	"unified-thinking/internal/storage"
 	"unified-thinking/internal/streaming"
+	"unified-thinking/internal/types"
 )
 
 // GoTHandler handles Graph-of-Thoughts operations
 type GoTHandler struct {
 	controller *modes.GraphController
 	llm        modes.LLMClient
+	// resolveWorkspace maps a request's explicit workspace (possibly empty)
+	// to the workspace to use, e.g. the one selected for the MCP session
+	resolveWorkspace func(req *mcp.CallToolRequest, workspace string) string
 }
 
 // NewGoTHandler creates a new GoT handler
@@ -24,11 +30,31 @@ func NewGoTHandler(controller *modes.GraphController, llm modes.LLMClient) *GoTH
 	}
 }
 
+// SetWorkspaceResolver sets how tool calls without an explicit workspace pick one
+func (h *GoTHandler) SetWorkspaceResolver(resolve func(req *mcp.CallToolRequest, workspace string) string) {
+	h.resolveWorkspace = resolve
+}
+
+// controllerFor returns the graph controller for a tool call's workspace
+func (h *GoTHandler) controllerFor(req *mcp.CallToolRequest, workspace string) (*modes.GraphController, error) {
+	if err := storage.ValidateWorkspace(workspace); err != nil {
+		return nil, &ValidationError{"workspace", err.Error()}
+	}
+	if h.resolveWorkspace != nil {
+		workspace = h.resolveWorkspace(req, workspace)
+	}
+	if workspace == "" {
+		return h.controller, nil
+	}
+	return h.controller.ForWorkspace(workspace), nil
+}
+
 // InitializeRequest for got-initialize
 type InitializeRequest struct {
 	GraphID        string             `json:"graph_id"`
 	InitialThought string             `json:"initial_thought"`
 	Config         *modes.GraphConfig `json:"config,omitempty"`
+	Workspace      string             `json:"workspace,omitempty"`
 }
 
 // InitializeResponse for got-initialize
@@ -48,7 +74,12 @@ func (h *GoTHandler) HandleInitialize(ctx context.Context, req *mcp.CallToolRequ
 		return nil, nil, fmt.Errorf("initial_thought is required")
 	}
 
-	state, err := h.controller.Initialize(request.GraphID, request.InitialThought, request.Config)
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state, err := controller.Initialize(request.GraphID, request.InitialThought, request.Config)
 	if err != nil {
 		return nil, nil, fmt.Errorf("initialization failed: %w", err)
 	}
@@ -69,6 +100,7 @@ type GenerateRequest struct {
 	K         int      `json:"k"`
 	SourceIDs []string `json:"source_ids,omitempty"`
 	Problem   string   `json:"problem"`
+	Workspace string   `json:"workspace,omitempty"`
 }
 
 // GenerateResponse for got-generate
@@ -98,6 +130,11 @@ func (h *GoTHandler) HandleGenerate(ctx context.Context, req *mcp.CallToolReques
 		return nil, nil, fmt.Errorf("k must be positive")
 	}
 
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Create progress reporter for streaming notifications
 	reporter := streaming.CreateReporter(req, "got-generate")
 
@@ -116,12 +153,12 @@ func (h *GoTHandler) HandleGenerate(ctx context.Context, req *mcp.CallToolReques
 	// Inject reporter into context for the controller to use
 	ctx = streaming.WithReporter(ctx, reporter)
 
-	vertices, err := h.controller.Generate(ctx, request.GraphID, h.llm, genReq)
+	vertices, err := controller.Generate(ctx, request.GraphID, h.llm, genReq)
 	if err != nil {
 		return nil, nil, fmt.Errorf("generation failed: %w", err)
 	}
 
-	state, _ := h.controller.GetState(request.GraphID)
+	state, _ := controller.GetState(request.GraphID)
 
 	newVertices := make([]VertexInfo, len(vertices))
 	for i, v := range vertices {
@@ -161,6 +198,7 @@ type AggregateRequest struct {
 	GraphID   string   `json:"graph_id"`
 	VertexIDs []string `json:"vertex_ids"`
 	Problem   string   `json:"problem"`
+	Workspace string   `json:"workspace,omitempty"`
 }
 
 // AggregateResponse for got-aggregate
@@ -179,6 +217,11 @@ func (h *GoTHandler) HandleAggregate(ctx context.Context, req *mcp.CallToolReque
 		return nil, nil, fmt.Errorf("need at least 2 vertices to aggregate")
 	}
 
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Create progress reporter for streaming notifications
 	reporter := streaming.CreateReporter(req, "got-aggregate")
 	sourceCount := len(request.VertexIDs)
@@ -205,7 +248,7 @@ func (h *GoTHandler) HandleAggregate(ctx context.Context, req *mcp.CallToolReque
 		_ = reporter.ReportStep(sourceCount, sourceCount+1, "merge", "Merging vertices into unified insight")
 	}
 
-	vertex, err := h.controller.Aggregate(ctx, request.GraphID, h.llm, aggReq)
+	vertex, err := controller.Aggregate(ctx, request.GraphID, h.llm, aggReq)
 	if err != nil {
 		return nil, nil, fmt.Errorf("aggregation failed: %w", err)
 	}
@@ -238,9 +281,10 @@ func (h *GoTHandler) HandleAggregate(ctx context.Context, req *mcp.CallToolReque
 
 // RefineRequest for got-refine
 type RefineRequest struct {
-	GraphID  string `json:"graph_id"`
-	VertexID string `json:"vertex_id"`
-	Problem  string `json:"problem"`
+	GraphID   string `json:"graph_id"`
+	VertexID  string `json:"vertex_id"`
+	Problem   string `json:"problem"`
+	Workspace string `json:"workspace,omitempty"`
 }
 
 // RefineResponse for got-refine
@@ -259,12 +303,17 @@ func (h *GoTHandler) HandleRefine(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, fmt.Errorf("vertex_id is required")
 	}
 
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	refReq := modes.RefineRequest{
 		VertexID: request.VertexID,
 		Problem:  request.Problem,
 	}
 
-	vertex, err := h.controller.Refine(ctx, request.GraphID, h.llm, refReq)
+	vertex, err := controller.Refine(ctx, request.GraphID, h.llm, refReq)
 	if err != nil {
 		return nil, nil, fmt.Errorf("refinement failed: %w", err)
 	}
@@ -287,9 +336,10 @@ func (h *GoTHandler) HandleRefine(ctx context.Context, req *mcp.CallToolRequest,
 
 // ScoreRequest for got-score
 type ScoreRequest struct {
-	GraphID  string `json:"graph_id"`
-	VertexID string `json:"vertex_id"`
-	Problem  string `json:"problem"`
+	GraphID   string `json:"graph_id"`
+	VertexID  string `json:"vertex_id"`
+	Problem   string `json:"problem"`
+	Workspace string `json:"workspace,omitempty"`
 }
 
 // ScoreResponse for got-score
@@ -308,12 +358,17 @@ func (h *GoTHandler) HandleScore(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, fmt.Errorf("vertex_id is required")
 	}
 
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	scoreReq := modes.ScoreRequest{
 		VertexID: request.VertexID,
 		Problem:  request.Problem,
 	}
 
-	breakdown, err := h.controller.Score(ctx, request.GraphID, h.llm, scoreReq)
+	breakdown, err := controller.Score(ctx, request.GraphID, h.llm, scoreReq)
 	if err != nil {
 		return nil, nil, fmt.Errorf("scoring failed: %w", err)
 	}
@@ -331,6 +386,7 @@ func (h *GoTHandler) HandleScore(ctx context.Context, req *mcp.CallToolRequest,
 type PruneRequest struct {
 	GraphID   string  `json:"graph_id"`
 	Threshold float64 `json:"threshold,omitempty"`
+	Workspace string  `json:"workspace,omitempty"`
 }
 
 // PruneResponse for got-prune
@@ -347,12 +403,17 @@ func (h *GoTHandler) HandlePrune(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, fmt.Errorf("graph_id is required")
 	}
 
-	removed, err := h.controller.Prune(ctx, request.GraphID, request.Threshold)
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	removed, err := controller.Prune(ctx, request.GraphID, request.Threshold)
 	if err != nil {
 		return nil, nil, fmt.Errorf("pruning failed: %w", err)
 	}
 
-	state, _ := h.controller.GetState(request.GraphID)
+	state, _ := controller.GetState(request.GraphID)
 
 	response := &PruneResponse{
 		GraphID:        request.GraphID,
@@ -366,7 +427,9 @@ func (h *GoTHandler) HandlePrune(ctx context.Context, req *mcp.CallToolRequest,
 
 // GetStateRequest for got-get-state
 type GetStateRequest struct {
-	GraphID string `json:"graph_id"`
+	GraphID   string `json:"graph_id"`
+	Format    string `json:"format,omitempty"` // json (default), dot, mermaid, graphml
+	Workspace string `json:"workspace,omitempty"`
 }
 
 // GetStateResponse for got-get-state
@@ -379,6 +442,8 @@ type GetStateResponse struct {
 	TerminalIDs []string           `json:"terminal_ids"`
 	Vertices    []VertexInfo       `json:"vertices"`
 	Config      *modes.GraphConfig `json:"config"`
+	Format      string             `json:"format,omitempty"`
+	Export      string             `json:"export,omitempty"`
 }
 
 // HandleGetState retrieves current graph state
@@ -387,7 +452,17 @@ func (h *GoTHandler) HandleGetState(ctx context.Context, req *mcp.CallToolReques
 		return nil, nil, fmt.Errorf("graph_id is required")
 	}
 
-	state, err := h.controller.GetState(request.GraphID)
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	format, err := resolveExportFormat(request.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state, err := controller.GetState(request.GraphID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get state: %w", err)
 	}
@@ -415,6 +490,53 @@ func (h *GoTHandler) HandleGetState(ctx context.Context, req *mcp.CallToolReques
 		Config:      state.Config,
 	}
 
+	if format != "" {
+		rendered, err := export.RenderGraphState(state, format)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to export graph: %w", err)
+		}
+		response.Format = string(format)
+		response.Export = rendered
+	}
+
+	return &mcp.CallToolResult{Content: withExport(toJSONContent(response), response.Export)}, response, nil
+}
+
+// ListStatesRequest for got-list-states
+type ListStatesRequest struct {
+	Limit     int    `json:"limit,omitempty"`
+	Offset    int    `json:"offset,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// ListStatesResponse for got-list-states
+type ListStatesResponse struct {
+	States []*types.GraphStateSummary `json:"states"`
+	Count  int                        `json:"count"`
+}
+
+// HandleListStates lists known graphs, including graphs from earlier sessions
+func (h *GoTHandler) HandleListStates(ctx context.Context, req *mcp.CallToolRequest, request ListStatesRequest) (*mcp.CallToolResult, *ListStatesResponse, error) {
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit, err := resolvePagination(request.Limit, request.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	states, err := controller.ListStates(limit, request.Offset)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list states: %w", err)
+	}
+
+	response := &ListStatesResponse{
+		States: states,
+		Count:  len(states),
+	}
+
 	return &mcp.CallToolResult{Content: toJSONContent(response)}, response, nil
 }
 
@@ -422,6 +544,7 @@ func (h *GoTHandler) HandleGetState(ctx context.Context, req *mcp.CallToolReques
 type FinalizeRequest struct {
 	GraphID     string   `json:"graph_id"`
 	TerminalIDs []string `json:"terminal_ids"`
+	Workspace   string   `json:"workspace,omitempty"`
 }
 
 // FinalizeResponse for got-finalize
@@ -440,11 +563,16 @@ func (h *GoTHandler) HandleFinalize(ctx context.Context, req *mcp.CallToolReques
 		return nil, nil, fmt.Errorf("terminal_ids cannot be empty")
 	}
 
-	if err := h.controller.SetTerminalVertices(request.GraphID, request.TerminalIDs); err != nil {
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := controller.SetTerminalVertices(request.GraphID, request.TerminalIDs); err != nil {
 		return nil, nil, fmt.Errorf("failed to set terminals: %w", err)
 	}
 
-	state, _ := h.controller.GetState(request.GraphID)
+	state, _ := controller.GetState(request.GraphID)
 
 	conclusions := make([]VertexInfo, 0, len(request.TerminalIDs))
 	for _, termID := range request.TerminalIDs {
@@ -474,7 +602,10 @@ type ExploreRequest struct {
 	GraphID        string         `json:"graph_id"`
 	InitialThought string         `json:"initial_thought"`
 	Problem        string         `json:"problem"`
+	Strategy       string         `json:"strategy,omitempty"` // "iterative" (default) or "mcts"
 	Config         *ExploreConfig `json:"config,omitempty"`
+	MCTS           *MCTSConfig    `json:"mcts,omitempty"`
+	Workspace      string         `json:"workspace,omitempty"`
 }
 
 // ExploreConfig controls the exploration workflow
@@ -484,19 +615,33 @@ type ExploreConfig struct {
 	PruneThreshold float64 `json:"prune_threshold,omitempty"` // Score threshold for pruning (default: 0.3)
 	RefineTopN     int     `json:"refine_top_n,omitempty"`    // Refine top N vertices (default: 1)
 	ScoreAll       bool    `json:"score_all,omitempty"`       // Score all vertices, not just active
+
+	ScoringWeights map[string]float64 `json:"scoring_weights,omitempty"` // Criterion weights (see got-initialize)
+}
+
+// MCTSConfig controls the MCTS exploration strategy and its budget
+type MCTSConfig struct {
+	Iterations          int     `json:"iterations,omitempty"`           // Search iterations (default: 20)
+	MaxLLMCalls         int     `json:"max_llm_calls,omitempty"`        // Generate and Score call budget (default: 40)
+	ExplorationConstant float64 `json:"exploration_constant,omitempty"` // UCT exploration weight (default: 1.414)
+	ExpansionWidth      int     `json:"expansion_width,omitempty"`      // Children per expansion (default: 3)
+	MaxDepth            int     `json:"max_depth,omitempty"`            // Deepest expandable vertex (default: 7)
 }
 
 // ExploreResponse for got-explore
 type ExploreResponse struct {
-	GraphID         string            `json:"graph_id"`
-	Problem         string            `json:"problem"`
-	Iterations      int               `json:"iterations"`
-	TotalGenerated  int               `json:"total_generated"`
-	TotalPruned     int               `json:"total_pruned"`
-	TotalRefined    int               `json:"total_refined"`
-	BestVertices    []VertexInfo      `json:"best_vertices"`
-	Conclusions     []VertexInfo      `json:"conclusions"`
-	ExplorationPath []ExplorationStep `json:"exploration_path"`
+	GraphID         string                  `json:"graph_id"`
+	Problem         string                  `json:"problem"`
+	Strategy        string                  `json:"strategy"`
+	Iterations      int                     `json:"iterations"`
+	TotalGenerated  int                     `json:"total_generated"`
+	TotalPruned     int                     `json:"total_pruned"`
+	TotalRefined    int                     `json:"total_refined"`
+	LLMCalls        int                     `json:"llm_calls,omitempty"`
+	BestVertices    []VertexInfo            `json:"best_vertices"`
+	Conclusions     []VertexInfo            `json:"conclusions"`
+	ExplorationPath []ExplorationStep       `json:"exploration_path"`
+	VertexStats     []modes.MCTSVertexStats `json:"vertex_stats,omitempty"`
 }
 
 // ExplorationStep records a single step in the exploration workflow
@@ -519,6 +664,11 @@ func (h *GoTHandler) HandleExplore(ctx context.Context, req *mcp.CallToolRequest
 		return nil, nil, fmt.Errorf("problem is required")
 	}
 
+	controller, err := h.controllerFor(req, request.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Create progress reporter for streaming notifications
 	reporter := streaming.CreateReporter(req, "got-explore")
 
@@ -536,19 +686,30 @@ func (h *GoTHandler) HandleExplore(ctx context.Context, req *mcp.CallToolRequest
 			PruneThreshold: request.Config.PruneThreshold,
 			RefineTopN:     request.Config.RefineTopN,
 			ScoreAll:       request.Config.ScoreAll,
+			ScoringWeights: request.Config.ScoringWeights,
 		}
 	}
 
 	exploreReq := modes.ExploreRequest{
 		InitialThought: request.InitialThought,
 		Problem:        request.Problem,
+		Strategy:       modes.ExploreStrategy(request.Strategy),
 		Config:         modesConfig,
 	}
+	if request.MCTS != nil {
+		exploreReq.MCTS = &modes.MCTSConfig{
+			Iterations:          request.MCTS.Iterations,
+			MaxLLMCalls:         request.MCTS.MaxLLMCalls,
+			ExplorationConstant: request.MCTS.ExplorationConstant,
+			ExpansionWidth:      request.MCTS.ExpansionWidth,
+			MaxDepth:            request.MCTS.MaxDepth,
+		}
+	}
 
 	// Inject reporter into context for the controller to use
 	ctx = streaming.WithReporter(ctx, reporter)
 
-	result, err := h.controller.Explore(ctx, request.GraphID, h.llm, exploreReq)
+	result, err := controller.Explore(ctx, request.GraphID, h.llm, exploreReq)
 	if err != nil {
 		return nil, nil, fmt.Errorf("exploration failed: %w", err)
 	}
@@ -605,35 +766,44 @@ func (h *GoTHandler) HandleExplore(ctx context.Context, req *mcp.CallToolRequest
 	response := &ExploreResponse{
 		GraphID:         result.GraphID,
 		Problem:         result.Problem,
+		Strategy:        string(result.Strategy),
 		Iterations:      result.Iterations,
 		TotalGenerated:  result.TotalGenerated,
 		TotalPruned:     result.TotalPruned,
 		TotalRefined:    result.TotalRefined,
+		LLMCalls:        result.LLMCalls,
 		BestVertices:    bestVertices,
 		Conclusions:     conclusions,
 		ExplorationPath: explorationPath,
+		VertexStats:     result.VertexStats,
 	}
 
 	return &mcp.CallToolResult{Content: toJSONContent(response)}, response, nil
 }
 
 // RegisterGoTTools registers all GoT MCP tools
-func RegisterGoTTools(mcpServer *mcp.Server, handler *GoTHandler) {
-	mcp.AddTool(mcpServer, &mcp.Tool{
+func RegisterGoTTools(mcpServer *mcp.Server, dispatcher *Dispatcher, handler *GoTHandler) {
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-initialize",
 		Description: `Initialize a new Graph-of-Thoughts graph with an initial thought.
 
 **Parameters:**
 - graph_id (required): Unique identifier for this graph
 - initial_thought (required): Starting thought content
-- config (optional): GraphConfig with limits
+- config (optional): GraphConfig with limits and scoring_weights. scoring_weights maps
+  criteria to weights (normalized): the LLM criteria confidence, validity, relevance,
+  novelty, depth_factor, plus the registered scorers logic, fallacy, hallucination and,
+  when embeddings are configured, embedding_relevance
+- workspace (optional): Workspace to create the graph (default: session workspace)
 
 **Returns:** graph_id, root_id, status, config
 
-**Example:** {"graph_id": "sorting-problem", "initial_thought": "Sort [3,1,2] using comparisons"}`,
+**Example:** {"graph_id": "sorting-problem", "initial_thought": "Sort [3,1,2] using comparisons"}
+
+**Custom scoring:** {"graph_id": "api-design", "initial_thought": "...", "config": {"max_vertices": 50, "max_active_vertices": 10, "max_depth": 7, "max_refinements": 3, "prune_threshold": 0.3, "aggregate_min_paths": 2, "scoring_weights": {"validity": 0.4, "relevance": 0.3, "logic": 0.2, "fallacy": 0.1}}}`,
 	}, handler.HandleInitialize)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-generate",
 		Description: `Generate k diverse continuations from active or specified vertices.
 
@@ -642,13 +812,14 @@ func RegisterGoTTools(mcpServer *mcp.Server, handler *GoTHandler) {
 - k (required): Number of continuations per source (1-10)
 - source_ids (optional): Specific vertices to expand from (default: active)
 - problem (required): Original problem context
+- workspace (optional): Workspace holding the graph (default: session workspace)
 
 **Returns:** new_vertices array, count, active_count
 
 **Example:** {"graph_id": "sorting-problem", "k": 3, "problem": "Sort the array"}`,
 	}, handler.HandleGenerate)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-aggregate",
 		Description: `Merge multiple parallel reasoning paths into a unified insight.
 
@@ -656,13 +827,14 @@ func RegisterGoTTools(mcpServer *mcp.Server, handler *GoTHandler) {
 - graph_id (required): Graph identifier
 - vertex_ids (required): Array of vertices to merge (min: 2)
 - problem (required): Original problem context
+- workspace (optional): Workspace holding the graph (default: session workspace)
 
 **Returns:** aggregated_vertex, source_count
 
 **Example:** {"graph_id": "sorting-problem", "vertex_ids": ["v1", "v2", "v3"], "problem": "Sort"}`,
 	}, handler.HandleAggregate)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-refine",
 		Description: `Iteratively improve a thought through self-critique.
 
@@ -670,13 +842,14 @@ func RegisterGoTTools(mcpServer *mcp.Server, handler *GoTHandler) {
 - graph_id (required): Graph identifier
 - vertex_id (required): Vertex to refine
 - problem (required): Original problem context
+- workspace (optional): Workspace holding the graph (default: session workspace)
 
 **Returns:** refined_vertex, refinement_count
 
 **Example:** {"graph_id": "sorting-problem", "vertex_id": "v1", "problem": "Sort"}`,
 	}, handler.HandleRefine)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-score",
 		Description: `Evaluate thought quality with multi-criteria breakdown.
 
@@ -684,78 +857,121 @@ func RegisterGoTTools(mcpServer *mcp.Server, handler *GoTHandler) {
 - graph_id (required): Graph identifier
 - vertex_id (required): Vertex to score
 - problem (required): Original problem context
+- workspace (optional): Workspace holding the graph (default: session workspace)
 
-**Returns:** breakdown (confidence, validity, relevance, novelty, depth_factor, overall)
+**Returns:** breakdown (confidence, validity, relevance, novelty, depth_factor, signals from
+registered scorers, normalized weights, overall). Weights come from the graph's scoring_weights
 
 **Example:** {"graph_id": "sorting-problem", "vertex_id": "v1", "problem": "Sort"}`,
 	}, handler.HandleScore)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-prune",
 		Description: `Remove low-quality vertices below threshold (preserves roots and terminals).
 
 **Parameters:**
 - graph_id (required): Graph identifier
 - threshold (optional): Minimum score to keep (default: config.PruneThreshold)
+- workspace (optional): Workspace holding the graph (default: session workspace)
 
 **Returns:** removed_count, remaining_count, threshold
 
 **Example:** {"graph_id": "sorting-problem", "threshold": 0.3}`,
 	}, handler.HandlePrune)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-get-state",
 		Description: `Get current graph state with all vertices and metadata.
 
 **Parameters:**
 - graph_id (required): Graph identifier
+- format (optional): json (default), dot, mermaid, or graphml. Renders the graph with
+  scores as fill colors, edge types as line styles, terminal vertices double-bordered
+  and vertices below the prune threshold dashed
+- workspace (optional): Workspace holding the graph (default: session workspace)
 
-**Returns:** vertex_count, edge_count, root_ids, active_ids, terminal_ids, vertices, config
+**Returns:** vertex_count, edge_count, root_ids, active_ids, terminal_ids, vertices, config,
+plus format and export when a diagram format is requested
 
-**Example:** {"graph_id": "sorting-problem"}`,
+**Example:** {"graph_id": "sorting-problem", "format": "mermaid"}`,
 	}, handler.HandleGetState)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
+		Name: "got-list-states",
+		Description: `List Graph-of-Thoughts graphs, most recently updated first.
+
+Graphs are persisted with SQLite storage, so graphs from earlier sessions are listed and
+can be continued with got-generate, got-aggregate and got-refine. Graphs untouched for
+longer than GOT_STATE_TTL (default 168h) are removed. Only graphs in the workspace are listed.
+
+**Parameters:**
+- limit (optional): Maximum graphs to return (default: 50)
+- offset (optional): Number of graphs to skip
+- workspace (optional): Workspace to list (default: session workspace)
+
+**Returns:** states array (id, initial_thought, vertex_count, edge_count, active_count, terminal_count, created_at, updated_at), count
+
+**Example:** {"limit": 10}`,
+	}, handler.HandleListStates)
+
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-finalize",
 		Description: `Mark terminal vertices and retrieve final conclusions.
 
 **Parameters:**
 - graph_id (required): Graph identifier
 - terminal_ids (required): Array of final conclusion vertex IDs
+- workspace (optional): Workspace holding the graph (default: session workspace)
 
 **Returns:** terminal_ids, conclusions array
 
 **Example:** {"graph_id": "sorting-problem", "terminal_ids": ["v10", "v15"]}`,
 	}, handler.HandleFinalize)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "got-explore",
 		Description: `Auto-orchestrated Graph-of-Thoughts exploration workflow.
 
 Combines initialize → generate → score → prune → refine → finalize into a single tool call.
 Reduces the typical 6+ tool calls to just 1 call for common exploration patterns.
 
+The "mcts" strategy instead runs Monte Carlo Tree Search: UCT selection over vertices,
+expansion via generation, evaluation via LLM scoring, and backpropagation of scores
+along edges. Use it for hard design problems where promising branches deserve deeper search.
+
 **Parameters:**
 - graph_id (required): Unique identifier for this graph
 - initial_thought (required): Starting thought content
 - problem (required): Problem context for the exploration
-- config (optional): ExploreConfig with:
+- strategy (optional): "iterative" (default) or "mcts"
+- config (optional): ExploreConfig for the iterative strategy:
   - k: Continuations per step (default: 3)
   - max_iterations: Max exploration cycles (default: 2)
   - prune_threshold: Score threshold for pruning (default: 0.3)
   - refine_top_n: Refine top N vertices (default: 1)
   - score_all: Score all vertices, not just active (default: false)
+  - scoring_weights: Criterion weights, as in got-initialize (also used by mcts)
+- mcts (optional): MCTS settings and budget:
+  - iterations: Search iterations (default: 20)
+  - max_llm_calls: Generate and score call budget (default: 40)
+  - exploration_constant: UCT exploration weight (default: 1.414)
+  - expansion_width: Children per expansion (default: 3)
+  - max_depth: Deepest expandable vertex (default: 7)
+- workspace (optional): Workspace to create the graph (default: session workspace)
 
 **Returns:**
 - graph_id: Identifier of the created graph
 - problem: The problem that was explored
+- strategy: Strategy used
 - iterations: Number of exploration cycles completed
 - total_generated: Total vertices generated
 - total_pruned: Total vertices pruned
 - total_refined: Total vertices refined
-- best_vertices: Top-scoring vertices
+- llm_calls: LLM calls made (mcts only)
+- best_vertices: Top-scoring vertices (highest value estimates for mcts)
 - conclusions: Final conclusions (same as best_vertices)
 - exploration_path: Step-by-step record of actions taken
+- vertex_stats: Visit count and value estimate per vertex (mcts only)
 
 **Example:**
 {
@@ -763,6 +979,15 @@ Reduces the typical 6+ tool calls to just 1 call for common exploration patterns
   "initial_thought": "CI tests fail intermittently, need to identify root cause",
   "problem": "Debug flaky CI tests",
   "config": {"k": 3, "max_iterations": 2}
+}
+
+**MCTS Example:**
+{
+  "graph_id": "cache-design",
+  "initial_thought": "Design a cache invalidation scheme for a multi-region API",
+  "problem": "Choose a cache invalidation strategy",
+  "strategy": "mcts",
+  "mcts": {"iterations": 30, "max_llm_calls": 50}
 }`,
 	}, handler.HandleExplore)
 }
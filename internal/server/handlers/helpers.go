@@ -3,8 +3,11 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/export"
 	"unified-thinking/internal/server/types"
 )
 
@@ -34,3 +37,42 @@ func unmarshalRequest[T any](params map[string]interface{}) (T, error) {
 func generateID(prefix string) string {
 	return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
 }
+
+// defaultListLimit is used by list tools when no limit is given
+const defaultListLimit = 50
+
+// resolvePagination validates list paging parameters and applies the default limit
+func resolvePagination(limit, offset int) (int, error) {
+	if limit < 0 {
+		return 0, &ValidationError{"limit", "limit must be non-negative"}
+	}
+	if offset < 0 {
+		return 0, &ValidationError{"offset", "offset must be non-negative"}
+	}
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	return limit, nil
+}
+
+// resolveExportFormat validates an optional graph export format. An empty
+// format or "json" means the graph is returned as JSON only.
+func resolveExportFormat(format string) (export.Format, error) {
+	if format == "" || strings.EqualFold(format, "json") {
+		return "", nil
+	}
+	parsed, err := export.ParseFormat(format)
+	if err != nil {
+		return "", &ValidationError{"format", err.Error()}
+	}
+	return parsed, nil
+}
+
+// withExport appends a rendered graph as its own text block, so it can be
+// copied verbatim instead of unescaped from the JSON response
+func withExport(content []mcp.Content, rendered string) []mcp.Content {
+	if rendered == "" {
+		return content
+	}
+	return append(content, &mcp.TextContent{Text: rendered})
+}
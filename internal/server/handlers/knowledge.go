@@ -21,9 +21,9 @@ func NewKnowledgeHandlers(kg *knowledge.KnowledgeGraph) *KnowledgeHandlers {
 }
 
 // RegisterKnowledgeGraphTools registers all knowledge graph MCP tools
-func RegisterKnowledgeGraphTools(mcpServer *mcp.Server, kh *KnowledgeHandlers) {
+func RegisterKnowledgeGraphTools(mcpServer *mcp.Server, dispatcher *Dispatcher, kh *KnowledgeHandlers) {
 	// Tool 64: store-entity
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "store-entity",
 		Description: `Store an entity in the knowledge graph with semantic indexing.
 
@@ -43,7 +43,7 @@ Stores entities in both Neo4j graph database and chromem-go vector search for hy
 	}, kh.HandleStoreEntity)
 
 	// Tool 65: search-knowledge-graph
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "search-knowledge-graph",
 		Description: `Search for entities using semantic similarity or graph traversal.
 
@@ -64,7 +64,7 @@ Stores entities in both Neo4j graph database and chromem-go vector search for hy
 	}, kh.HandleSearchKnowledgeGraph)
 
 	// Tool 66: create-relationship
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "create-relationship",
 		Description: `Create a typed relationship between entities in the knowledge graph.
 
@@ -165,8 +165,8 @@ func multimodalToJSONContent(data interface{}) []mcp.Content {
 }
 
 // RegisterMultimodalTools registers all multimodal MCP tools
-func RegisterMultimodalTools(mcpServer *mcp.Server, handler *MultimodalHandler) {
-	mcp.AddTool(mcpServer, &mcp.Tool{
+func RegisterMultimodalTools(mcpServer *mcp.Server, dispatcher *Dispatcher, handler *MultimodalHandler) {
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "embed-multimodal",
 		Description: `Generate embeddings for multimodal content (text, images, or both).
 
@@ -0,0 +1,225 @@
+// Package handlers - Execution of workflow presets through the tool executor
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"unified-thinking/internal/claudecode/presets"
+	"unified-thinking/internal/orchestration"
+	"unified-thinking/internal/streaming"
+)
+
+// SetToolExecutor enables run-preset to execute steps. Without an executor
+// run-preset only reports the steps as ready.
+func (h *ClaudeCodeHandler) SetToolExecutor(executor orchestration.ToolExecutor) {
+	h.executor = executor
+}
+
+// runPreset executes a preset's steps in order. A required step that fails
+// stops the run; optional failures are recorded and the run continues.
+// Steps whose condition is not met, or that depend on a skipped step, are
+// skipped.
+func (h *ClaudeCodeHandler) runPreset(ctx context.Context, preset *presets.WorkflowPreset, input map[string]any, reporter streaming.ProgressReporter) *RunPresetResponse {
+	start := time.Now()
+	response := &RunPresetResponse{
+		PresetID:    preset.ID,
+		Status:      "success",
+		TotalSteps:  len(preset.Steps),
+		StepResults: make([]PresetStepResult, 0, len(preset.Steps)),
+	}
+
+	stored := make(map[string]any)     // Results by StoreAs key, then step ID
+	succeeded := make(map[string]bool) // Step IDs that completed
+	finished := make(map[string]bool)  // Step IDs that ran, including optional failures
+	timestamp := strconv.FormatInt(start.Unix(), 10)
+
+	for i, step := range preset.Steps {
+		result := PresetStepResult{StepID: step.StepID, Tool: step.Tool, Description: step.Description}
+
+		if reason := skipReason(step, finished, stored); reason != "" {
+			result.Status = "skipped"
+			result.Error = reason
+			response.StepResults = append(response.StepResults, result)
+			continue
+		}
+
+		if reporter.IsEnabled() {
+			_ = reporter.ReportStep(i+1, len(preset.Steps), step.StepID, "Running: "+step.Description)
+		}
+
+		output, err := h.executor.ExecuteTool(ctx, step.Tool, stepInput(preset, step, input, stored, timestamp))
+		if err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+			response.StepResults = append(response.StepResults, result)
+			if step.Optional {
+				finished[step.StepID] = true
+				continue
+			}
+			response.Status = "partial"
+			if response.StepsCompleted == 0 {
+				response.Status = "failed"
+			}
+			break
+		}
+
+		result.Status = "success"
+		result.Result = output
+		response.StepResults = append(response.StepResults, result)
+		response.StepsCompleted++
+		succeeded[step.StepID] = true
+		finished[step.StepID] = true
+		stored[step.StepID] = output
+		if step.StoreAs != "" {
+			stored[step.StoreAs] = output
+		}
+	}
+
+	final := make(map[string]any)
+	for _, step := range preset.Steps {
+		if !succeeded[step.StepID] {
+			continue
+		}
+		key := step.StoreAs
+		if key == "" {
+			key = step.StepID
+		}
+		final[key] = stored[key]
+	}
+	if len(final) > 0 {
+		response.FinalResult = final
+	}
+	response.ExecutionTimeMs = time.Since(start).Milliseconds()
+	return response
+}
+
+// stepInput builds a step's tool input from its static inputs and input map.
+// Input map sources are preset inputs (falling back to their defaults) or
+// "store_as.field" paths into earlier results; unresolved sources are left
+// out so the tool's own defaults apply.
+func stepInput(preset *presets.WorkflowPreset, step presets.PresetStep, input map[string]any, stored map[string]any, timestamp string) map[string]any {
+	toolInput := make(map[string]any, len(step.StaticInputs)+len(step.InputMap))
+	for param, value := range step.StaticInputs {
+		if s, ok := value.(string); ok {
+			value = strings.ReplaceAll(s, "${timestamp}", timestamp)
+		}
+		toolInput[param] = value
+	}
+	for param, source := range step.InputMap {
+		if value, ok := resolveSource(preset, source, input, stored); ok {
+			toolInput[param] = value
+		}
+	}
+	return toolInput
+}
+
+// resolveSource looks up an input map source
+func resolveSource(preset *presets.WorkflowPreset, source string, input map[string]any, stored map[string]any) (any, bool) {
+	if value, ok := input[source]; ok && value != nil {
+		return value, true
+	}
+	if spec, ok := preset.InputSchema[source]; ok && spec.Default != nil {
+		return spec.Default, true
+	}
+	root, path, nested := strings.Cut(source, ".")
+	if !nested {
+		return nil, false
+	}
+	if value, ok := stored[root]; ok {
+		return lookupPath(value, path)
+	}
+	if value, ok := input[root]; ok {
+		return lookupPath(value, path)
+	}
+	return nil, false
+}
+
+// lookupPath follows a dotted path through nested maps
+func lookupPath(value any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok || value == nil {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// skipReason explains why a step should not run, or returns ""
+func skipReason(step presets.PresetStep, finished map[string]bool, stored map[string]any) string {
+	for _, dep := range step.DependsOn {
+		if !finished[dep] {
+			return fmt.Sprintf("dependency %s did not run", dep)
+		}
+	}
+	if c := step.Condition; c != nil {
+		source := stored[c.SourceStep]
+		value := source
+		if c.Field != "" {
+			value, _ = lookupPath(source, c.Field)
+		}
+		if !conditionMet(value, c.Operator, c.Value) {
+			return fmt.Sprintf("condition %s %s %v not met", c.Field, c.Operator, c.Value)
+		}
+	}
+	return ""
+}
+
+// conditionMet compares a result value with a condition value. Numbers are
+// compared numerically; eq and ne fall back to deep equality.
+func conditionMet(value any, operator string, target any) bool {
+	a, aNum := toFloat(value)
+	b, bNum := toFloat(target)
+	switch operator {
+	case "gt":
+		return aNum && bNum && a > b
+	case "gte":
+		return aNum && bNum && a >= b
+	case "lt":
+		return aNum && bNum && a < b
+	case "lte":
+		return aNum && bNum && a <= b
+	case "eq":
+		return (aNum && bNum && a == b) || reflect.DeepEqual(value, target)
+	case "ne":
+		return !((aNum && bNum && a == b) || reflect.DeepEqual(value, target))
+	case "contains":
+		switch v := value.(type) {
+		case string:
+			return strings.Contains(v, fmt.Sprint(target))
+		case []any:
+			for _, item := range v {
+				if reflect.DeepEqual(item, target) {
+					return true
+				}
+			}
+		}
+		return false
+	case "exists":
+		return value != nil
+	}
+	return false
+}
+
+// toFloat converts a numeric value to float64
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/claudecode/presets"
+	"unified-thinking/internal/storage"
+)
+
+// recordingExecutor records tool calls and answers from canned results
+type recordingExecutor struct {
+	calls   map[string]map[string]interface{}
+	results map[string]interface{}
+	fail    map[string]bool
+}
+
+func (e *recordingExecutor) ExecuteTool(ctx context.Context, toolName string, input map[string]interface{}) (interface{}, error) {
+	if e.calls == nil {
+		e.calls = make(map[string]map[string]interface{})
+	}
+	e.calls[toolName] = input
+	if e.fail[toolName] {
+		return nil, fmt.Errorf("%s failed", toolName)
+	}
+	if result, ok := e.results[toolName]; ok {
+		return result, nil
+	}
+	return map[string]interface{}{"status": "ok"}, nil
+}
+
+func TestHandleRunPreset_ExecutesSteps(t *testing.T) {
+	executor := &recordingExecutor{
+		results: map[string]interface{}{
+			"start-reasoning-session": map[string]interface{}{"session_id": "debug-1"},
+		},
+		fail: map[string]bool{"simulate-intervention": true},
+	}
+	h := NewClaudeCodeHandler(storage.NewMemoryStorage())
+	h.SetToolExecutor(executor)
+
+	_, resp, err := h.HandleRunPreset(context.Background(), &mcp.CallToolRequest{}, RunPresetRequest{
+		PresetID: "debug-analysis",
+		Input:    map[string]any{"problem": "Checkout returns 500", "observations": []any{"High CPU usage"}},
+	})
+	if err != nil {
+		t.Fatalf("HandleRunPreset: %v", err)
+	}
+	if resp.Status != "success" || resp.StepsCompleted != resp.TotalSteps-1 {
+		t.Errorf("status = %s, completed %d of %d", resp.Status, resp.StepsCompleted, resp.TotalSteps)
+	}
+
+	// Inputs come from the preset input, static inputs and earlier results
+	if got := executor.calls["build-causal-graph"]["description"]; got != "Checkout returns 500" {
+		t.Errorf("description = %v", got)
+	}
+	if got := executor.calls["generate-hypotheses"]["max_hypotheses"]; got != 5 {
+		t.Errorf("max_hypotheses = %v", got)
+	}
+	if got := executor.calls["complete-reasoning-session"]["session_id"]; got != "debug-1" {
+		t.Errorf("session_id = %v", got)
+	}
+	if got, _ := executor.calls["start-reasoning-session"]["session_id"].(string); strings.Contains(got, "${timestamp}") {
+		t.Errorf("timestamp placeholder not replaced: %s", got)
+	}
+
+	// The optional simulation failed without stopping the run
+	for _, step := range resp.StepResults {
+		if step.Tool == "simulate-intervention" && step.Status != "failed" {
+			t.Errorf("simulate step = %+v", step)
+		}
+	}
+}
+
+func TestHandleRunPreset_RequiredFailure(t *testing.T) {
+	h := NewClaudeCodeHandler(storage.NewMemoryStorage())
+	h.SetToolExecutor(&recordingExecutor{fail: map[string]bool{"build-causal-graph": true}})
+
+	_, resp, err := h.HandleRunPreset(context.Background(), &mcp.CallToolRequest{}, RunPresetRequest{
+		PresetID: "debug-analysis",
+		Input:    map[string]any{"problem": "Memory leak"},
+	})
+	if err != nil {
+		t.Fatalf("HandleRunPreset: %v", err)
+	}
+	if resp.Status != "partial" || resp.StepsCompleted != 1 || len(resp.StepResults) != 2 {
+		t.Errorf("response = %+v", resp)
+	}
+
+	if _, _, err := h.HandleRunPreset(context.Background(), &mcp.CallToolRequest{}, RunPresetRequest{PresetID: "debug-analysis"}); err == nil {
+		t.Error("expected error for a missing required input")
+	}
+}
+
+func TestPresetStepSkipping(t *testing.T) {
+	step := presets.PresetStep{
+		StepID:    "refine",
+		DependsOn: []string{"score"},
+		Condition: &presets.StepCondition{SourceStep: "score", Field: "result.confidence", Operator: "lt", Value: 0.7},
+	}
+	stored := map[string]any{"score": map[string]any{"result": map[string]any{"confidence": 0.9}}}
+
+	if reason := skipReason(step, map[string]bool{}, stored); !strings.Contains(reason, "dependency") {
+		t.Errorf("reason = %q, want unmet dependency", reason)
+	}
+	if reason := skipReason(step, map[string]bool{"score": true}, stored); !strings.Contains(reason, "condition") {
+		t.Errorf("reason = %q, want unmet condition", reason)
+	}
+	stored["score"] = map[string]any{"result": map[string]any{"confidence": 0.5}}
+	if reason := skipReason(step, map[string]bool{"score": true}, stored); reason != "" {
+		t.Errorf("reason = %q, want the step to run", reason)
+	}
+}
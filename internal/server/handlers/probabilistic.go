@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -18,9 +19,14 @@ type ProbabilisticHandler struct {
 	probabilisticReasoner *reasoning.ProbabilisticReasoner
 	evidenceAnalyzer      *analysis.EvidenceAnalyzer
 	contradictionDetector *analysis.ContradictionDetector
+
+	mu        sync.Mutex
+	reasoners map[string]*reasoning.ProbabilisticReasoner // workspace -> reasoner
 }
 
-// NewProbabilisticHandler creates a new probabilistic handler
+// NewProbabilisticHandler creates a new probabilistic handler. The reasoner
+// serves the workspace store is bound to; reasoners for other workspaces are
+// created on first use over their storage views.
 func NewProbabilisticHandler(
 	store storage.Storage,
 	probabilisticReasoner *reasoning.ProbabilisticReasoner,
@@ -32,9 +38,33 @@ func NewProbabilisticHandler(
 		probabilisticReasoner: probabilisticReasoner,
 		evidenceAnalyzer:      evidenceAnalyzer,
 		contradictionDetector: contradictionDetector,
+		reasoners:             map[string]*reasoning.ProbabilisticReasoner{storage.WorkspaceOf(store): probabilisticReasoner},
 	}
 }
 
+// reasonerFor returns the probabilistic reasoner for a workspace
+func (h *ProbabilisticHandler) reasonerFor(workspace string) (*reasoning.ProbabilisticReasoner, error) {
+	if err := storage.ValidateWorkspace(workspace); err != nil {
+		return nil, &ValidationError{"workspace", err.Error()}
+	}
+	if workspace == "" {
+		workspace = storage.WorkspaceOf(h.storage)
+	}
+	workspace = storage.NormalizeWorkspace(workspace)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if reasoner, exists := h.reasoners[workspace]; exists {
+		return reasoner, nil
+	}
+	reasoner := reasoning.NewProbabilisticReasoner()
+	if beliefStore, ok := storage.ForWorkspace(h.storage, workspace).(reasoning.BeliefStorage); ok {
+		reasoner.SetStorage(beliefStore)
+	}
+	h.reasoners[workspace] = reasoner
+	return reasoner, nil
+}
+
 // ============================================================================
 // Request/Response Types
 // ============================================================================
@@ -50,6 +80,7 @@ type ProbabilisticReasoningRequest struct {
 	EvidenceProb float64  `json:"evidence_prob,omitempty"` // For update operation
 	BeliefIDs    []string `json:"belief_ids,omitempty"`    // For combine operation
 	CombineOp    string   `json:"combine_op,omitempty"`    // "and" or "or" for combine
+	Workspace    string   `json:"workspace,omitempty"`
 }
 
 // ProbabilisticReasoningResponse represents a probabilistic reasoning response
@@ -60,6 +91,20 @@ type ProbabilisticReasoningResponse struct {
 	Status       string                     `json:"status"`
 }
 
+// ListBeliefsRequest represents a belief listing request
+type ListBeliefsRequest struct {
+	Limit     int    `json:"limit,omitempty"`
+	Offset    int    `json:"offset,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// ListBeliefsResponse represents a belief listing response
+type ListBeliefsResponse struct {
+	Beliefs []*types.ProbabilisticBelief `json:"beliefs"`
+	Count   int                          `json:"count"`
+	Status  string                       `json:"status"`
+}
+
 // AssessEvidenceRequest represents an evidence assessment request
 type AssessEvidenceRequest struct {
 	Content       string `json:"content"`
@@ -98,6 +143,11 @@ func (h *ProbabilisticHandler) HandleProbabilisticReasoning(ctx context.Context,
 		return nil, nil, err
 	}
 
+	reasoner, err := h.reasonerFor(input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	response := &ProbabilisticReasoningResponse{
 		Operation: input.Operation,
 		Status:    "success",
@@ -105,28 +155,28 @@ func (h *ProbabilisticHandler) HandleProbabilisticReasoning(ctx context.Context,
 
 	switch input.Operation {
 	case "create":
-		belief, err := h.probabilisticReasoner.CreateBelief(input.Statement, input.PriorProb)
+		belief, err := reasoner.CreateBelief(input.Statement, input.PriorProb)
 		if err != nil {
 			return nil, nil, err
 		}
 		response.Belief = belief
 
 	case "update":
-		belief, err := h.probabilisticReasoner.UpdateBelief(input.BeliefID, input.EvidenceID, input.Likelihood, input.EvidenceProb)
+		belief, err := reasoner.UpdateBelief(input.BeliefID, input.EvidenceID, input.Likelihood, input.EvidenceProb)
 		if err != nil {
 			return nil, nil, err
 		}
 		response.Belief = belief
 
 	case "get":
-		belief, err := h.probabilisticReasoner.GetBelief(input.BeliefID)
+		belief, err := reasoner.GetBelief(input.BeliefID)
 		if err != nil {
 			return nil, nil, err
 		}
 		response.Belief = belief
 
 	case "combine":
-		combinedProb, err := h.probabilisticReasoner.CombineBeliefs(input.BeliefIDs, input.CombineOp)
+		combinedProb, err := reasoner.CombineBeliefs(input.BeliefIDs, input.CombineOp)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -141,6 +191,34 @@ func (h *ProbabilisticHandler) HandleProbabilisticReasoning(ctx context.Context,
 	}, response, nil
 }
 
+// HandleListBeliefs lists stored beliefs, most recently updated first
+func (h *ProbabilisticHandler) HandleListBeliefs(ctx context.Context, req *mcp.CallToolRequest, input ListBeliefsRequest) (*mcp.CallToolResult, *ListBeliefsResponse, error) {
+	limit, err := resolvePagination(input.Limit, input.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reasoner, err := h.reasonerFor(input.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	beliefs, err := reasoner.ListBeliefs(limit, input.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &ListBeliefsResponse{
+		Beliefs: beliefs,
+		Count:   len(beliefs),
+		Status:  "success",
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
 // HandleAssessEvidence processes evidence assessment requests
 func (h *ProbabilisticHandler) HandleAssessEvidence(ctx context.Context, req *mcp.CallToolRequest, input AssessEvidenceRequest) (*mcp.CallToolResult, *AssessEvidenceResponse, error) {
 	if err := ValidateAssessEvidenceRequest(&input); err != nil {
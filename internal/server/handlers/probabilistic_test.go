@@ -817,3 +817,71 @@ func createManyThoughtIDs(n int) []string {
 	}
 	return ids
 }
+
+func TestHandleListBeliefs(t *testing.T) {
+	handler := NewProbabilisticHandler(storage.NewMemoryStorage(), reasoning.NewProbabilisticReasoner(),
+		analysis.NewEvidenceAnalyzer(), analysis.NewContradictionDetector())
+	ctx := context.Background()
+
+	for _, statement := range []string{"A holds", "B holds"} {
+		_, _, err := handler.HandleProbabilisticReasoning(ctx, &mcp.CallToolRequest{}, ProbabilisticReasoningRequest{
+			Operation: "create",
+			Statement: statement,
+			PriorProb: 0.5,
+		})
+		if err != nil {
+			t.Fatalf("create belief error = %v", err)
+		}
+	}
+
+	_, resp, err := handler.HandleListBeliefs(ctx, nil, ListBeliefsRequest{})
+	if err != nil {
+		t.Fatalf("HandleListBeliefs() error = %v", err)
+	}
+	if resp.Count != 2 || resp.Status != "success" {
+		t.Errorf("HandleListBeliefs() = %+v, want 2 beliefs", resp)
+	}
+
+	_, resp, _ = handler.HandleListBeliefs(ctx, nil, ListBeliefsRequest{Limit: 1})
+	if resp.Count != 1 {
+		t.Errorf("limit 1 returned %d beliefs", resp.Count)
+	}
+
+	if _, _, err := handler.HandleListBeliefs(ctx, nil, ListBeliefsRequest{Offset: -1}); err == nil {
+		t.Error("expected validation error for negative offset")
+	}
+}
+
+func TestProbabilisticHandler_Workspaces(t *testing.T) {
+	handler := NewProbabilisticHandler(storage.NewMemoryStorage(), reasoning.NewProbabilisticReasoner(),
+		analysis.NewEvidenceAnalyzer(), analysis.NewContradictionDetector())
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	_, created, err := handler.HandleProbabilisticReasoning(ctx, req, ProbabilisticReasoningRequest{
+		Operation: "create",
+		Statement: "A holds",
+		PriorProb: 0.5,
+		Workspace: "alpha",
+	})
+	if err != nil {
+		t.Fatalf("create belief error = %v", err)
+	}
+
+	if _, resp, _ := handler.HandleListBeliefs(ctx, req, ListBeliefsRequest{}); resp.Count != 0 {
+		t.Errorf("default workspace listed %d beliefs, want 0", resp.Count)
+	}
+	if _, _, err := handler.HandleProbabilisticReasoning(ctx, req, ProbabilisticReasoningRequest{Operation: "get", BeliefID: created.Belief.ID}); err == nil {
+		t.Error("default workspace should not find alpha's belief")
+	}
+
+	if _, resp, _ := handler.HandleListBeliefs(ctx, req, ListBeliefsRequest{Workspace: "alpha"}); resp.Count != 1 {
+		t.Errorf("alpha listed %d beliefs, want 1", resp.Count)
+	}
+	if _, _, err := handler.HandleProbabilisticReasoning(ctx, req, ProbabilisticReasoningRequest{Operation: "get", BeliefID: created.Belief.ID, Workspace: "ALPHA"}); err != nil {
+		t.Errorf("alpha get belief error = %v", err)
+	}
+	if _, _, err := handler.HandleListBeliefs(ctx, req, ListBeliefsRequest{Workspace: "bad workspace!"}); err == nil {
+		t.Error("expected validation error for invalid workspace")
+	}
+}
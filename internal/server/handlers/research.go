@@ -8,6 +8,7 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"unified-thinking/internal/modes"
+	"unified-thinking/internal/streaming"
 )
 
 // ResearchHandler handles web search augmented research operations
@@ -61,7 +62,9 @@ func (h *ResearchHandler) HandleResearchWithSearch(ctx context.Context, req *mcp
 		problem = request.Query
 	}
 
-	// Perform research with web search
+	// Perform research with web search, streaming findings to clients
+	// that sent a progress token
+	ctx, _ = streaming.InjectReporter(ctx, req, "research-with-search")
 	result, err := h.llm.ResearchWithSearch(ctx, request.Query, problem)
 	if err != nil {
 		return nil, nil, fmt.Errorf("research failed: %w", err)
@@ -113,8 +116,8 @@ func researchToJSONContent(data interface{}) []mcp.Content {
 }
 
 // RegisterResearchTools registers all research MCP tools
-func RegisterResearchTools(mcpServer *mcp.Server, handler *ResearchHandler) {
-	mcp.AddTool(mcpServer, &mcp.Tool{
+func RegisterResearchTools(mcpServer *mcp.Server, dispatcher *Dispatcher, handler *ResearchHandler) {
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "research-with-search",
 		Description: `Perform web-augmented research using Anthropic's built-in web search.
 
@@ -86,8 +86,8 @@ func (h *SimilarityHandler) HandleSearchSimilarThoughts(ctx context.Context, req
 }
 
 // RegisterSimilarityTools registers thought similarity MCP tools
-func RegisterSimilarityTools(mcpServer *mcp.Server, sh *SimilarityHandler) {
-	mcp.AddTool(mcpServer, &mcp.Tool{
+func RegisterSimilarityTools(mcpServer *mcp.Server, dispatcher *Dispatcher, sh *SimilarityHandler) {
+	AddTool(mcpServer, dispatcher, &mcp.Tool{
 		Name: "search-similar-thoughts",
 		Description: `Search for thoughts similar to a query using semantic embeddings.
 
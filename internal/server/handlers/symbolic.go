@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"unified-thinking/internal/storage"
@@ -26,17 +27,27 @@ func NewSymbolicHandler(reasoner *validation.SymbolicReasoner, store storage.Sto
 // ProveTheoremRequest represents a theorem proving request
 type ProveTheoremRequest struct {
 	Name       string   `json:"name"`
-	Premises   []string `json:"premises"`
-	Conclusion string   `json:"conclusion"`
+	Premises   []string `json:"premises,omitempty"`   // Required unless a problem is given
+	Conclusion string   `json:"conclusion,omitempty"` // Required unless a problem is given
+	MaxDepth   int      `json:"max_depth,omitempty"`  // Bound on the resolution search depth
+	TimeoutMs  int      `json:"timeout_ms,omitempty"` // Bound on the resolution search time
+	Format     string   `json:"format,omitempty"`     // "tptp" or "smtlib": read the theorem from problem
+	Problem    string   `json:"problem,omitempty"`    // Problem text, instead of premises and conclusion
+	Export     string   `json:"export,omitempty"`     // "tptp" or "smtlib": also return the problem and proof in that format
 }
 
 // ProveTheoremResponse represents the response
 type ProveTheoremResponse struct {
-	Name       string       `json:"name"`
-	Status     string       `json:"status"`
-	IsValid    bool         `json:"is_valid"`
-	Confidence float64      `json:"confidence"`
-	Proof      *ProofOutput `json:"proof,omitempty"`
+	Name            string       `json:"name"`
+	Status          string       `json:"status"`
+	IsValid         bool         `json:"is_valid"`
+	Confidence      float64      `json:"confidence"`
+	Proof           *ProofOutput `json:"proof,omitempty"`
+	Premises        []string     `json:"premises,omitempty"`         // Premises read from an imported problem
+	Conclusion      string       `json:"conclusion,omitempty"`       // Conclusion read from an imported problem
+	ExpectedStatus  string       `json:"expected_status,omitempty"`  // Status the imported problem declares
+	ExportedProblem string       `json:"exported_problem,omitempty"` // The theorem in the export format
+	ExportedProof   string       `json:"exported_proof,omitempty"`   // The proof in the export format
 }
 
 // ProofOutput represents a proof
@@ -72,23 +83,57 @@ func (h *SymbolicHandler) HandleProveTheorem(ctx context.Context, params map[str
 
 // proveTheorem is the typed internal implementation
 func (h *SymbolicHandler) proveTheorem(_ context.Context, req ProveTheoremRequest) (*ProveTheoremResponse, error) {
-	if req.Conclusion == "" {
-		return nil, fmt.Errorf("conclusion is required")
+	if req.MaxDepth < 0 || req.TimeoutMs < 0 {
+		return nil, fmt.Errorf("max_depth and timeout_ms must not be negative")
 	}
-	if len(req.Premises) == 0 {
-		return nil, fmt.Errorf("premises are required")
+	if err := checkInterchangeFormat("export", req.Export); err != nil {
+		return nil, err
 	}
 
-	// Create theorem
+	// Create theorem, or read it from the problem
+	var imported *validation.ImportedTheorem
 	theorem := &validation.SymbolicTheorem{
 		Name:       req.Name,
 		Premises:   req.Premises,
 		Conclusion: req.Conclusion,
 		Status:     validation.StatusUnproven,
 	}
+	switch {
+	case req.Problem != "":
+		if len(req.Premises) > 0 || req.Conclusion != "" {
+			return nil, fmt.Errorf("give either a problem or premises and a conclusion, not both")
+		}
+		var err error
+		switch req.Format {
+		case "tptp":
+			imported, err = validation.ParseTPTPTheorem(req.Problem)
+		case "smtlib":
+			imported, err = validation.ParseSMTLIBTheorem(req.Problem)
+		default:
+			return nil, fmt.Errorf("format must be \"tptp\" or \"smtlib\" when a problem is given")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s problem: %w", req.Format, err)
+		}
+		theorem = imported.Theorem
+		if req.Name != "" {
+			theorem.Name = req.Name
+		}
+	case req.Conclusion == "":
+		return nil, fmt.Errorf("conclusion is required")
+	case len(req.Premises) == 0:
+		return nil, fmt.Errorf("premises are required")
+	}
 
-	// Prove theorem
-	proof, err := h.reasoner.ProveTheorem(theorem)
+	// Prove theorem, with the reasoner's limits unless the request sets its own
+	var proof *validation.TheoremProof
+	var err error
+	if req.MaxDepth > 0 || req.TimeoutMs > 0 {
+		limits := validation.ProverLimits{MaxDepth: req.MaxDepth, Timeout: time.Duration(req.TimeoutMs) * time.Millisecond}
+		proof, err = h.reasoner.ProveTheoremWithLimits(theorem, limits)
+	} else {
+		proof, err = h.reasoner.ProveTheorem(theorem)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("theorem proving failed: %w", err)
 	}
@@ -119,14 +164,47 @@ func (h *SymbolicHandler) proveTheorem(_ context.Context, req ProveTheoremReques
 			Explanation: proof.Explanation,
 		}
 	}
+	if imported != nil {
+		resp.Premises = theorem.Premises
+		resp.Conclusion = theorem.Conclusion
+		resp.ExpectedStatus = imported.Status
+	}
+
+	switch req.Export {
+	case "tptp":
+		resp.ExportedProblem, err = validation.ExportTheoremTPTP(theorem)
+		if err == nil && proof != nil {
+			resp.ExportedProof, err = validation.ExportProofTPTP(theorem, proof)
+		}
+	case "smtlib":
+		resp.ExportedProblem, err = validation.ExportTheoremSMTLIB(theorem)
+		if err == nil && proof != nil {
+			resp.ExportedProof, err = validation.ExportProofSMTLIB(theorem, proof)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s export failed: %w", req.Export, err)
+	}
 
 	return resp, nil
 }
 
+// checkInterchangeFormat validates an optional problem format
+func checkInterchangeFormat(field, format string) error {
+	switch format {
+	case "", "tptp", "smtlib":
+		return nil
+	}
+	return fmt.Errorf("%s must be \"tptp\" or \"smtlib\", got %q", field, format)
+}
+
 // CheckConstraintsRequest represents a constraint checking request
 type CheckConstraintsRequest struct {
-	Symbols     []*SymbolInput     `json:"symbols"`
-	Constraints []*ConstraintInput `json:"constraints"`
+	Symbols     []*SymbolInput     `json:"symbols,omitempty"`     // Required unless a problem is given
+	Constraints []*ConstraintInput `json:"constraints,omitempty"` // Required unless a problem is given
+	Format      string             `json:"format,omitempty"`      // "tptp" or "smtlib": read symbols and constraints from problem
+	Problem     string             `json:"problem,omitempty"`     // Problem text, instead of symbols and constraints
+	Export      string             `json:"export,omitempty"`      // "tptp" or "smtlib": also return the constraints in that format
 }
 
 // SymbolInput represents a symbol definition
@@ -145,17 +223,25 @@ type ConstraintInput struct {
 
 // CheckConstraintsResponse represents the response
 type CheckConstraintsResponse struct {
-	IsConsistent bool              `json:"is_consistent"`
-	Conflicts    []*ConflictOutput `json:"conflicts,omitempty"`
-	Explanation  string            `json:"explanation"`
+	IsConsistent bool               `json:"is_consistent"`
+	Conflicts    []*ConflictOutput  `json:"conflicts,omitempty"`
+	Explanation  string             `json:"explanation"`
+	Witness      map[string]float64 `json:"witness,omitempty"`    // Satisfying assignment of the linear symbols
+	UnsatCore    []string           `json:"unsat_core,omitempty"` // Minimal set of conflicting constraint IDs
+	Unchecked    []string           `json:"unchecked,omitempty"`  // Constraints only compared pairwise
+
+	Names           map[string]string `json:"names,omitempty"`            // Constraint IDs of an imported problem's named constraints
+	ExpectedStatus  string            `json:"expected_status,omitempty"`  // Status the imported problem declares: "sat" or "unsat"
+	ExportedProblem string            `json:"exported_problem,omitempty"` // The constraints in the export format
 }
 
 // ConflictOutput represents a constraint conflict
 type ConflictOutput struct {
-	Constraint1  string `json:"constraint1"`
-	Constraint2  string `json:"constraint2"`
-	ConflictType string `json:"conflict_type"`
-	Explanation  string `json:"explanation"`
+	Constraint1  string   `json:"constraint1"`
+	Constraint2  string   `json:"constraint2"`
+	Constraints  []string `json:"constraints,omitempty"` // Every constraint in an unsatisfiable core
+	ConflictType string   `json:"conflict_type"`
+	Explanation  string   `json:"explanation"`
 }
 
 // HandleCheckConstraints checks constraint consistency
@@ -175,6 +261,28 @@ func (h *SymbolicHandler) HandleCheckConstraints(ctx context.Context, params map
 
 // checkConstraints is the typed internal implementation
 func (h *SymbolicHandler) checkConstraints(_ context.Context, req CheckConstraintsRequest) (*CheckConstraintsResponse, error) {
+	if err := checkInterchangeFormat("export", req.Export); err != nil {
+		return nil, err
+	}
+	var problem *validation.ConstraintProblem
+	if req.Problem != "" {
+		if len(req.Symbols) > 0 || len(req.Constraints) > 0 {
+			return nil, fmt.Errorf("give either a problem or symbols and constraints, not both")
+		}
+		var err error
+		switch req.Format {
+		case "tptp":
+			problem, err = validation.ParseTPTPConstraints(req.Problem)
+		case "smtlib":
+			problem, err = validation.ParseSMTLIBConstraints(req.Problem)
+		default:
+			return nil, fmt.Errorf("format must be \"tptp\" or \"smtlib\" when a problem is given")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s problem: %w", req.Format, err)
+		}
+		req.Symbols, req.Constraints = problemInputs(problem)
+	}
 	if len(req.Symbols) == 0 {
 		return nil, fmt.Errorf("symbols are required")
 	}
@@ -199,7 +307,8 @@ func (h *SymbolicHandler) checkConstraints(_ context.Context, req CheckConstrain
 
 	// Add constraints and collect IDs
 	constraintIDs := make([]string, 0, len(req.Constraints))
-	for _, cons := range req.Constraints {
+	names := make(map[string]string)
+	for i, cons := range req.Constraints {
 		constraintType := validation.ConstraintType(cons.Type)
 
 		constraint, err := h.reasoner.AddConstraint(constraintType, cons.Expression, cons.Symbols)
@@ -207,6 +316,9 @@ func (h *SymbolicHandler) checkConstraints(_ context.Context, req CheckConstrain
 			return nil, fmt.Errorf("failed to add constraint: %w", err)
 		}
 		constraintIDs = append(constraintIDs, constraint.ID)
+		if problem != nil && problem.Constraints[i].ID != "" {
+			names[constraint.ID] = problem.Constraints[i].ID
+		}
 	}
 
 	// Check consistency
@@ -219,6 +331,9 @@ func (h *SymbolicHandler) checkConstraints(_ context.Context, req CheckConstrain
 	resp := &CheckConstraintsResponse{
 		IsConsistent: result.IsConsistent,
 		Explanation:  result.Explanation,
+		Witness:      result.Witness,
+		UnsatCore:    result.UnsatCore,
+		Unchecked:    result.Unchecked,
 	}
 
 	if len(result.Conflicts) > 0 {
@@ -227,12 +342,42 @@ func (h *SymbolicHandler) checkConstraints(_ context.Context, req CheckConstrain
 			conflicts[i] = &ConflictOutput{
 				Constraint1:  conf.Constraint1,
 				Constraint2:  conf.Constraint2,
+				Constraints:  conf.Constraints,
 				ConflictType: conf.ConflictType,
 				Explanation:  conf.Explanation,
 			}
 		}
 		resp.Conflicts = conflicts
 	}
+	if problem != nil {
+		resp.ExpectedStatus = problem.Status
+		if len(names) > 0 {
+			resp.Names = names
+		}
+	}
+
+	switch req.Export {
+	case "tptp":
+		resp.ExportedProblem, err = h.reasoner.ExportConstraintsTPTP(constraintIDs, result)
+	case "smtlib":
+		resp.ExportedProblem, err = h.reasoner.ExportConstraintsSMTLIB(constraintIDs, result)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s export failed: %w", req.Export, err)
+	}
 
 	return resp, nil
 }
+
+// problemInputs converts an imported constraint problem to request inputs
+func problemInputs(problem *validation.ConstraintProblem) ([]*SymbolInput, []*ConstraintInput) {
+	symbols := make([]*SymbolInput, len(problem.Symbols))
+	for i, sym := range problem.Symbols {
+		symbols[i] = &SymbolInput{Name: sym.Name, Type: string(sym.Type), Domain: sym.Domain}
+	}
+	constraints := make([]*ConstraintInput, len(problem.Constraints))
+	for i, cons := range problem.Constraints {
+		constraints[i] = &ConstraintInput{Type: string(cons.Type), Expression: cons.Expression, Symbols: cons.Symbols}
+	}
+	return symbols, constraints
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"unified-thinking/internal/storage"
@@ -377,3 +378,127 @@ func TestSymbolicHandler_HandleProveTheorem_InvalidProof(t *testing.T) {
 		t.Error("HandleProveTheorem() should return result even for invalid proof")
 	}
 }
+
+// TestSymbolicHandler_ProveTheorem_SearchLimits tests first-order proofs and request limits
+func TestSymbolicHandler_ProveTheorem_SearchLimits(t *testing.T) {
+	handler := NewSymbolicHandler(validation.NewSymbolicReasoner(), storage.NewMemoryStorage())
+	ctx := context.Background()
+
+	resp, err := handler.proveTheorem(ctx, ProveTheoremRequest{
+		Premises:   []string{"All men are mortal", "Socrates is a man"},
+		Conclusion: "Socrates is mortal",
+	})
+	if err != nil {
+		t.Fatalf("proveTheorem() error = %v", err)
+	}
+	if resp.Status != "proven" || resp.Proof.Method != validation.ProofMethodFirstOrder {
+		t.Errorf("status = %s, method = %s", resp.Status, resp.Proof.Method)
+	}
+
+	resp, err = handler.proveTheorem(ctx, ProveTheoremRequest{
+		Premises:   []string{"∀x (Q(f(x)) → Q(x))"},
+		Conclusion: "∃y Q(y)",
+		MaxDepth:   3,
+		TimeoutMs:  500,
+	})
+	if err != nil {
+		t.Fatalf("proveTheorem() error = %v", err)
+	}
+	if resp.Status != "undecidable" || resp.IsValid {
+		t.Errorf("status = %s, is_valid = %v", resp.Status, resp.IsValid)
+	}
+
+	if _, err := handler.proveTheorem(ctx, ProveTheoremRequest{Premises: []string{"P"}, Conclusion: "P", MaxDepth: -1}); err == nil {
+		t.Error("proveTheorem() should reject a negative max_depth")
+	}
+}
+
+// TestSymbolicHandler_CheckConstraints_Linear tests witnesses and unsatisfiable cores
+func TestSymbolicHandler_CheckConstraints_Linear(t *testing.T) {
+	handler := NewSymbolicHandler(validation.NewSymbolicReasoner(), storage.NewMemoryStorage())
+	symbols := []*SymbolInput{
+		{Name: "api", Type: "variable", Domain: "integer"},
+		{Name: "worker", Type: "variable", Domain: "integer"},
+	}
+	constraints := []*ConstraintInput{
+		{Type: "inequality", Expression: "api + worker <= 10", Symbols: []string{"api", "worker"}},
+		{Type: "range", Expression: "api in [6, 8]", Symbols: []string{"api"}},
+		{Type: "inequality", Expression: "worker >= 3", Symbols: []string{"worker"}},
+	}
+
+	resp, err := handler.checkConstraints(context.Background(), CheckConstraintsRequest{Symbols: symbols, Constraints: constraints})
+	if err != nil {
+		t.Fatalf("checkConstraints() error = %v", err)
+	}
+	if !resp.IsConsistent || resp.Witness["api"] != 6 || resp.Witness["worker"] != 3 {
+		t.Errorf("response = %+v", resp)
+	}
+
+	constraints[2].Expression = "worker >= 5"
+	resp, err = handler.checkConstraints(context.Background(), CheckConstraintsRequest{Symbols: symbols, Constraints: constraints})
+	if err != nil {
+		t.Fatalf("checkConstraints() error = %v", err)
+	}
+	if resp.IsConsistent || len(resp.UnsatCore) != 3 || len(resp.Conflicts) != 1 || len(resp.Conflicts[0].Constraints) != 3 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestSymbolicHandler_ProveTheorem_Interchange(t *testing.T) {
+	handler := NewSymbolicHandler(validation.NewSymbolicReasoner(), storage.NewMemoryStorage())
+	problem := `% Status   : Theorem
+fof(mortality, axiom, ! [X] : (human(X) => mortal(X))).
+fof(socrates, axiom, human(socrates)).
+fof(goal, conjecture, mortal(socrates)).`
+
+	resp, err := handler.proveTheorem(context.Background(), ProveTheoremRequest{Format: "tptp", Problem: problem, Export: "smtlib"})
+	if err != nil {
+		t.Fatalf("proveTheorem() error = %v", err)
+	}
+	if !resp.IsValid || resp.ExpectedStatus != "Theorem" || resp.Conclusion != "mortal(socrates)" || len(resp.Premises) != 2 {
+		t.Errorf("response = %+v", resp)
+	}
+	if !strings.Contains(resp.ExportedProblem, "(set-info :status unsat)") || !strings.Contains(resp.ExportedProof, "(check-sat) ; expect unsat") {
+		t.Errorf("exported problem:\n%s\nproof:\n%s", resp.ExportedProblem, resp.ExportedProof)
+	}
+
+	for _, req := range []ProveTheoremRequest{
+		{Format: "tptp", Problem: problem, Conclusion: "mortal(socrates)"},
+		{Format: "dimacs", Problem: problem},
+		{Format: "smtlib", Problem: problem},
+		{Premises: []string{"P"}, Conclusion: "P", Export: "dimacs"},
+	} {
+		if _, err := handler.proveTheorem(context.Background(), req); err == nil {
+			t.Errorf("proveTheorem(%+v) succeeded", req)
+		}
+	}
+}
+
+func TestSymbolicHandler_CheckConstraints_Interchange(t *testing.T) {
+	handler := NewSymbolicHandler(validation.NewSymbolicReasoner(), storage.NewMemoryStorage())
+	problem := `(set-logic QF_LIA)
+(set-info :status unsat)
+(declare-const api Int)
+(declare-const worker Int)
+(assert (! (<= (+ api worker) 10) :named capacity))
+(assert (<= 6 api 8))
+(assert (! (>= worker 5) :named workers))`
+
+	resp, err := handler.checkConstraints(context.Background(), CheckConstraintsRequest{Format: "smtlib", Problem: problem, Export: "tptp"})
+	if err != nil {
+		t.Fatalf("checkConstraints() error = %v", err)
+	}
+	if resp.IsConsistent || resp.ExpectedStatus != "unsat" || len(resp.UnsatCore) != 3 {
+		t.Errorf("response = %+v", resp)
+	}
+	if resp.Names[resp.UnsatCore[0]] != "capacity" || len(resp.Names) != 2 {
+		t.Errorf("names = %v", resp.Names)
+	}
+	if !strings.Contains(resp.ExportedProblem, "% Status   : Unsatisfiable") {
+		t.Errorf("exported problem:\n%s", resp.ExportedProblem)
+	}
+
+	if _, err := handler.checkConstraints(context.Background(), CheckConstraintsRequest{Format: "tptp", Problem: problem}); err == nil {
+		t.Error("read an SMT-LIB script as TPTP")
+	}
+}
@@ -43,10 +43,12 @@ type ProveRequest struct {
 
 // ProveResponse represents a prove response
 type ProveResponse struct {
-	IsProvable bool     `json:"is_provable"`
-	Steps      []string `json:"steps,omitempty"`
-	Premises   []string `json:"premises"`
-	Conclusion string   `json:"conclusion"`
+	IsProvable   bool            `json:"is_provable"`
+	Steps        []string        `json:"steps,omitempty"`
+	Premises     []string        `json:"premises"`
+	Conclusion   string          `json:"conclusion"`
+	Method       string          `json:"method,omitempty"`
+	CounterModel map[string]bool `json:"counter_model,omitempty"`
 }
 
 // CheckSyntaxRequest represents a check syntax request
@@ -91,10 +93,12 @@ func (h *ValidationHandler) HandleProve(ctx context.Context, req *mcp.CallToolRe
 	result := h.validator.Prove(input.Premises, input.Conclusion)
 
 	response := &ProveResponse{
-		IsProvable: result.IsProvable,
-		Steps:      result.Steps,
-		Premises:   input.Premises,
-		Conclusion: input.Conclusion,
+		IsProvable:   result.IsProvable,
+		Steps:        result.Steps,
+		Premises:     input.Premises,
+		Conclusion:   input.Conclusion,
+		Method:       result.Method,
+		CounterModel: result.CounterModel,
 	}
 
 	return &mcp.CallToolResult{}, response, nil
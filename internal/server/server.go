@@ -42,17 +42,18 @@
 //   - Testability: Handler logic independently testable
 //   - Documentation: Schema and examples live with definitions
 //
-// TOOL CATEGORIES (63 total):
+// TOOL CATEGORIES (65 total):
 //
-// Core Tools (11):
+// Core Tools (12):
 //   - think, history, list-branches, focus-branch, branch-history, recent-branches
-//   - validate, prove, check-syntax, search, get-metrics
+//   - validate, prove, check-syntax, search, get-metrics, set-workspace
 //
-// Probabilistic & Evidence Tools (4):
-//   - probabilistic-reasoning, assess-evidence, detect-contradictions, sensitivity-analysis
+// Probabilistic & Evidence Tools (5):
+//   - probabilistic-reasoning, list-beliefs, assess-evidence, detect-contradictions, sensitivity-analysis
 //
-// Decision & Problem-Solving Tools (2):
-//   - make-decision, decompose-problem
+// Decision & Problem-Solving Tools (6):
+//   - make-decision, list-decisions, get-decision
+//   - decompose-problem, list-decompositions, get-decomposition
 //
 // Metacognition Tools (3):
 //   - self-evaluate, detect-biases, detect-blind-spots
@@ -63,22 +64,26 @@
 // Temporal & Perspective Tools (4):
 //   - analyze-perspectives, analyze-temporal, compare-time-horizons, identify-optimal-timing
 //
-// Causal Reasoning Tools (5):
+// Causal Reasoning Tools (6):
 //   - build-causal-graph, simulate-intervention, generate-counterfactual
-//   - analyze-correlation-vs-causation, get-causal-graph
+//   - analyze-correlation-vs-causation, get-causal-graph, list-causal-graphs
 //
-// Integration & Synthesis Tools (6):
+// Integration & Synthesis Tools (13):
 //   - synthesize-insights, detect-emergent-patterns
 //   - execute-workflow, list-workflows, register-workflow, list-integration-patterns
+//   - list-workflow-versions, diff-workflow-versions, delete-workflow
+//   - list-workflow-runs, get-workflow-run, resume-workflow-run, rerun-workflow
 //
-// Advanced Reasoning Tools (10):
+// Advanced Reasoning Tools (13):
 //   - dual-process-think, create-checkpoint, restore-checkpoint, list-checkpoints
+//   - fork-checkpoint, diff-checkpoints, prune-branch
 //   - generate-hypotheses, evaluate-hypotheses, retrieve-similar-cases, perform-cbr-cycle
 //   - prove-theorem, check-constraints
 //
-// Enhanced Tools (8):
+// Enhanced Tools (10):
 //   - find-analogy, apply-analogy
-//   - decompose-argument, generate-counter-arguments, detect-fallacies
+//   - decompose-argument, generate-counter-arguments
+//   - detect-fallacies, list-fallacy-rules, configure-fallacy-rules
 //   - process-evidence-pipeline, analyze-temporal-causal-effects, analyze-decision-timing
 //
 // Episodic Memory & Learning Tools (5):
@@ -93,6 +98,8 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"unified-thinking/internal/analysis"
@@ -106,11 +113,13 @@ import (
 	"unified-thinking/internal/orchestration"
 	"unified-thinking/internal/processing"
 	"unified-thinking/internal/reasoning"
+	"unified-thinking/internal/replay"
 	"unified-thinking/internal/server/handlers"
 	"unified-thinking/internal/similarity"
 	"unified-thinking/internal/storage"
 	"unified-thinking/internal/streaming"
 	"unified-thinking/internal/types"
+	"unified-thinking/internal/usage"
 	"unified-thinking/internal/validation"
 )
 
@@ -135,6 +144,7 @@ type UnifiedServer struct {
 	probabilisticHandler *handlers.ProbabilisticHandler
 	decisionHandler      *handlers.DecisionHandler
 	metacognitionHandler *handlers.MetacognitionHandler
+	fallacyHandler       *handlers.FallacyHandler
 	// Phase 2: Handler delegates
 	temporalHandler *handlers.TemporalHandler
 	causalHandler   *handlers.CausalHandler
@@ -175,6 +185,15 @@ type UnifiedServer struct {
 	// Graph-of-Thoughts controller
 	graphController *modes.GraphController
 	gotHandler      *handlers.GoTHandler
+	// Transport shared by the LLM clients, reported by get-metrics
+	llmTransport *modes.Transport
+	// Token usage and budgets of the LLM and embedding clients
+	usage *usage.Tracker
+	// Records or replays LLM and embedding calls, nil when disabled
+	replay *replay.Store
+	// Episodic session bound to each MCP session for usage attribution
+	usageMu       sync.RWMutex
+	usageSessions map[*mcp.ServerSession]string
 	// Claude Code optimization handler
 	claudeCodeHandler *handlers.ClaudeCodeHandler
 	// Research with web search handler
@@ -183,6 +202,12 @@ type UnifiedServer struct {
 	multimodalHandler *handlers.MultimodalHandler
 	// Agentic tool calling handler (ALWAYS enabled)
 	agentHandler *handlers.AgentHandler
+	agentTools   *modes.ToolRegistry
+	// Calls registered tools by name for workflows, presets and the agent
+	dispatcher *handlers.Dispatcher
+	// Workspace selected per MCP session via set-workspace
+	workspaceMu       sync.RWMutex
+	sessionWorkspaces map[*mcp.ServerSession]string
 }
 
 // SetKnowledgeGraph sets the knowledge graph instance (optional)
@@ -197,6 +222,7 @@ func (s *UnifiedServer) SetKnowledgeGraph(kg *knowledge.KnowledgeGraph) {
 			s.learningEngine,
 			kg,
 		)
+		s.episodicMemoryHandler.SetWorkspaceResolver(s.resolveWorkspace)
 		log.Println("[DEBUG] Reinitialized episodic memory handler with knowledge graph")
 	}
 }
@@ -220,6 +246,30 @@ func NewUnifiedServer(
 	temporalReasoner := reasoning.NewTemporalReasoner()
 	causalReasoner := reasoning.NewCausalReasoner()
 
+	// Fallacy rule packs extend or replace the built-in rules
+	fallacyDetector := validation.NewFallacyDetector()
+	if dir := os.Getenv("FALLACY_RULES_DIR"); dir != "" {
+		packs, err := fallacyDetector.LoadRulePackDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fallacy rules from FALLACY_RULES_DIR: %w", err)
+		}
+		log.Printf("Loaded %d fallacy rule packs from %s", len(packs), dir)
+	}
+
+	// Persist reasoning artifacts when the storage backend supports it
+	if causalStore, ok := store.(reasoning.CausalStorage); ok {
+		causalReasoner.SetStorage(causalStore)
+	}
+	if decisionStore, ok := store.(reasoning.DecisionStorage); ok {
+		decisionMaker.SetStorage(decisionStore)
+	}
+	if beliefStore, ok := store.(reasoning.BeliefStorage); ok {
+		probabilisticReasoner.SetStorage(beliefStore)
+	}
+	if decompositionStore, ok := store.(reasoning.DecompositionStorage); ok {
+		problemDecomposer.SetStorage(decompositionStore)
+	}
+
 	s := &UnifiedServer{
 		storage:               store,
 		linear:                linear,
@@ -235,11 +285,12 @@ func NewUnifiedServer(
 		sensitivityAnalyzer:   sensitivityAnalyzer,
 		selfEvaluator:         metacognition.NewSelfEvaluator(),
 		biasDetector:          metacognition.NewBiasDetector(),
-		fallacyDetector:       validation.NewFallacyDetector(),
+		fallacyDetector:       fallacyDetector,
 		// Phase 1: Initialize handler delegates
 		probabilisticHandler: handlers.NewProbabilisticHandler(store, probabilisticReasoner, evidenceAnalyzer, contradictionDetector),
 		decisionHandler:      handlers.NewDecisionHandler(store, decisionMaker, problemDecomposer, sensitivityAnalyzer),
-		metacognitionHandler: handlers.NewMetacognitionHandler(store, metacognition.NewSelfEvaluator(), metacognition.NewBiasDetector(), validation.NewFallacyDetector()),
+		metacognitionHandler: handlers.NewMetacognitionHandler(store, metacognition.NewSelfEvaluator(), metacognition.NewBiasDetector(), fallacyDetector),
+		fallacyHandler:       handlers.NewFallacyHandler(fallacyDetector),
 		// Phase 2: Initialize temporal handler delegate
 		temporalHandler: handlers.NewTemporalHandler(perspectiveAnalyzer, temporalReasoner),
 		// Phase 2-3: Initialize advanced reasoning modules
@@ -253,16 +304,53 @@ func NewUnifiedServer(
 		hallucinationHandler: handlers.NewHallucinationHandler(store),
 		// Initialize calibration tracker
 		calibrationHandler: handlers.NewCalibrationHandler(),
+		dispatcher:         handlers.NewDispatcher(),
 	}
 
-	// Initialize Graph-of-Thoughts (requires ANTHROPIC_API_KEY)
+	s.fallacyHandler.SetWorkspaceResolver(s.resolveWorkspace)
+	s.causalHandler.SetWorkspaceStorage(store)
+	s.causalHandler.SetWorkspaceResolver(s.resolveWorkspace)
+
+	budget, err := usage.BudgetFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s.usage = usage.NewTracker(budget)
+
+	s.replay, err = replay.StoreFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize LLM replay: %w", err)
+	}
+	if s.replay != nil {
+		log.Printf("LLM and embedding calls in %s mode (LLM_REPLAY_DIR)", s.replay.Mode())
+	}
+
+	// Initialize Graph-of-Thoughts (requires ANTHROPIC_API_KEY, or an
+	// OpenAI-compatible endpoint with LLM_PROVIDER=openai)
 	s.graphController = modes.NewGraphController(store)
-	llmClient, err := modes.NewAnthropicLLMClient()
+	s.graphController.RegisterScorer(modes.NewLogicScorer(validator))
+	s.graphController.RegisterScorer(modes.NewFallacyScorer(s.fallacyDetector))
+	s.graphController.RegisterScorer(modes.NewHallucinationScorer(validation.NewHallucinationDetector()))
+	if graphStore, ok := store.(modes.GraphStateStorage); ok {
+		s.graphController.SetStateStorage(graphStore)
+	}
+	if ttl := os.Getenv("GOT_STATE_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GOT_STATE_TTL: %w", err)
+		}
+		s.graphController.SetStateTTL(d)
+	}
+	provider, err := modes.ProviderConfigFromEnv()
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Graph-of-Thoughts: %w", err)
 	}
-	log.Println("Graph-of-Thoughts enabled with Anthropic Claude API")
+	provider.Usage = s.usage
+	s.llmTransport = provider.Transport
+	llmClient := s.replay.WrapLLMClient(modes.NewLLMClient(provider))
+	log.Printf("Graph-of-Thoughts enabled with %s provider", provider.Provider)
 	s.gotHandler = handlers.NewGoTHandler(s.graphController, llmClient)
+	s.gotHandler.SetWorkspaceResolver(s.resolveWorkspace)
 
 	// Initialize Phase 2-3 handlers with LLM client
 	s.initializeAdvancedHandlers(llmClient)
@@ -284,21 +372,24 @@ func NewUnifiedServer(
 			multimodalModel = "voyage-multimodal-3"
 		}
 		multimodalEmbedder := embeddings.NewVoyageMultimodalEmbedder(voyageKey, textModel, multimodalModel)
+		multimodalEmbedder.SetUsageTracker(s.usage)
 		s.multimodalHandler = handlers.NewMultimodalHandler(multimodalEmbedder)
 	}
 
 	// Initialize agent handler - ALWAYS enabled
-	// Requires ANTHROPIC_API_KEY, will fail at tool call time if not set
-	toolRegistry := modes.NewToolRegistry()
-	// Populate with safe tools for agentic use
-	s.populateToolRegistry(toolRegistry)
-	s.agentHandler = handlers.NewAgentHandler(toolRegistry)
+	// Requires the provider's credentials, will fail at tool call time if not set
+	// The registry is populated from the dispatcher once tools are registered
+	s.agentTools = modes.NewToolRegistry()
+	if s.replay != nil {
+		provider.WrapSender = s.replay.WrapSender
+	}
+	s.agentHandler = handlers.NewAgentHandlerWithProvider(s.agentTools, provider)
 
 	return s, nil
 }
 
 // initializeAdvancedHandlers initializes Phase 2-3 reasoning handlers
-func (s *UnifiedServer) initializeAdvancedHandlers(llmClient *modes.AnthropicLLMClient) {
+func (s *UnifiedServer) initializeAdvancedHandlers(llmClient modes.TextLLMClient) {
 	// Dual-process executor
 	dualProcessExecutor := processing.NewDualProcessExecutor(s.storage, map[types.ThinkingMode]modes.ThinkingMode{
 		types.ModeLinear:    s.linear,
@@ -309,6 +400,9 @@ func (s *UnifiedServer) initializeAdvancedHandlers(llmClient *modes.AnthropicLLM
 
 	// Backtracking manager
 	backtrackingManager := modes.NewBacktrackingManager(s.storage)
+	if checkpointStore, ok := s.storage.(modes.CheckpointStorage); ok {
+		backtrackingManager.SetCheckpointStorage(checkpointStore)
+	}
 	s.backtrackingHandler = handlers.NewBacktrackingHandler(backtrackingManager, s.storage)
 
 	// Abductive reasoner with LLM-based hypothesis generation
@@ -363,6 +457,7 @@ func (s *UnifiedServer) initializeAdvancedHandlers(llmClient *modes.AnthropicLLM
 
 	// Initialize Claude Code handler
 	s.claudeCodeHandler = handlers.NewClaudeCodeHandler(s.storage)
+	s.claudeCodeHandler.SetToolExecutor(NewServerToolExecutor(s))
 }
 
 // SetThoughtSearcher sets the thought similarity searcher
@@ -396,6 +491,10 @@ func (s *UnifiedServer) initializeEpisodicMemory() {
 		log.Fatalf("FATAL: Embedding integration is nil without error - this indicates a configuration problem")
 	}
 	log.Printf("Embeddings initialized successfully with provider: %s", embeddingIntegration.GetProvider())
+	if tracked, ok := embeddingIntegration.GetEmbedder().(embeddings.UsageTracked); ok {
+		tracked.SetUsageTracker(s.usage)
+	}
+	embeddingIntegration.SetEmbedder(s.replay.WrapEmbedder(embeddingIntegration.GetEmbedder()))
 	// Load any existing embeddings from storage
 	if err := embeddingIntegration.LoadEmbeddingsFromStorage(); err != nil {
 		log.Fatalf("FATAL: Failed to load embeddings from storage: %v - storage must be accessible", err)
@@ -432,6 +531,7 @@ func (s *UnifiedServer) initializeEpisodicMemory() {
 	// Create episodic memory handler (with knowledge graph for automatic entity extraction)
 	// Note: knowledgeGraph may be nil here - will be reinitialized in SetKnowledgeGraph
 	s.episodicMemoryHandler = handlers.NewEpisodicMemoryHandler(s.episodicMemoryStore, s.sessionTracker, s.learningEngine, s.knowledgeGraph)
+	s.episodicMemoryHandler.SetWorkspaceResolver(s.resolveWorkspace)
 }
 
 // initializeSemanticAutoMode sets up semantic mode detection for auto mode.
@@ -447,8 +547,17 @@ func (s *UnifiedServer) initializeSemanticAutoMode() {
 		model = "voyage-3-lite"
 	}
 
-	embedder := embeddings.NewVoyageEmbedder(apiKey, model)
+	voyage := embeddings.NewVoyageEmbedder(apiKey, model)
+	voyage.SetUsageTracker(s.usage)
+	embedder := s.replay.WrapEmbedder(voyage)
 	s.auto.SetEmbedder(embedder)
+	s.graphController.RegisterScorer(modes.NewEmbeddingRelevanceScorer(embedder))
+}
+
+// UsageTracker returns the tracker shared by the server's LLM and embedding
+// clients, for embedders created outside the server
+func (s *UnifiedServer) UsageTracker() *usage.Tracker {
+	return s.usage
 }
 
 // SetOrchestrator sets the workflow orchestrator for the server
@@ -472,7 +581,7 @@ func (s *UnifiedServer) GetContextBridge() *contextbridge.ContextBridge {
 	return s.contextBridge
 }
 
-// RegisterTools registers all 85 MCP tools with the server.
+// RegisterTools registers all 87 MCP tools with the server.
 //
 // ORGANIZATION:
 // Tools are registered in the following order matching the package documentation:
@@ -483,13 +592,13 @@ func (s *UnifiedServer) GetContextBridge() *contextbridge.ContextBridge {
 //  5. Hallucination & Calibration (4): verification and calibration tracking
 //  6. Temporal & Perspective (4): temporal analysis and perspective tools
 //  7. Causal Reasoning (5): causal graphs, interventions, counterfactuals
-//  8. Integration & Synthesis (6): synthesis, workflows, patterns
+//  8. Integration & Synthesis (13): synthesis, workflows, workflow versions and runs, patterns
 //  9. Advanced Reasoning (10): dual-process, backtracking, abductive, CBR, symbolic
-//  10. Enhanced Tools (8): analogies, arguments, evidence pipeline
+//  10. Enhanced Tools (10): analogies, arguments, fallacies and fallacy rules, evidence pipeline
 //  11. Episodic Memory (5): session tracking, learning, recommendations
 //  12. Knowledge Graph (3): store-entity, search-knowledge-graph, create-relationship
 //  13. Similarity (1): search-similar-thoughts
-//  14. Graph-of-Thoughts (9): got-* tools
+//  14. Graph-of-Thoughts (10): got-* tools
 //  15. Claude Code (5): export-session, import-session, list-presets, run-preset, format-response
 //  16. Research (1): research-with-search
 //  17. Multimodal (1): embed-multimodal - ALWAYS enabled
@@ -501,11 +610,16 @@ func (s *UnifiedServer) GetContextBridge() *contextbridge.ContextBridge {
 //  3. Call handler method with typed request
 //  4. Return result or error
 func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
+	// Attribute LLM and embedding usage to tools and sessions
+	mcpServer.AddReceivingMiddleware(s.usageMiddleware)
+	// Let tools called through the dispatcher see the calling session
+	mcpServer.AddReceivingMiddleware(s.dispatcher.Middleware)
+
 	// ========================================================================
 	// CORE THINKING TOOLS (11 tools)
 	// ========================================================================
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "think",
 		Description: `Main thinking tool supporting multiple cognitive modes (linear, tree, divergent, auto).
 
@@ -515,6 +629,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 - confidence: 0.0-1.0 (default: 0.8)
 - key_points: Array of key observations
 - branch_id: For tree mode continuation
+- workspace: Project namespace (default: session workspace)
 
 **Returns:** thought_id, mode, confidence, metadata with:
 - suggested_next_tools: Recommended next steps
@@ -537,51 +652,53 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 **Example:** {"content": "Analyze database performance", "mode": "linear", "confidence": 0.7}`,
 	}, s.handleThink)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "history",
-		Description: "View thinking history",
+		Description: "View thinking history of the current workspace",
 	}, s.handleHistory)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "list-branches",
-		Description: "List all thinking branches",
+		Description: "List all thinking branches in the current workspace",
 		InputSchema: map[string]any{
-			"type":       "object",
-			"properties": map[string]any{},
+			"type": "object",
+			"properties": map[string]any{
+				"workspace": workspaceSchemaProperty,
+			},
 		},
 	}, s.handleListBranches)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "focus-branch",
 		Description: "Switch the active thinking branch",
 	}, s.handleFocusBranch)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "branch-history",
 		Description: "Get detailed history of a specific branch",
 	}, s.handleBranchHistory)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "validate",
 		Description: "Validate a thought for logical consistency",
 	}, s.handleValidate)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "prove",
-		Description: "Attempt to prove a logical conclusion from premises",
+		Description: "Attempt to prove a logical conclusion from premises; returns a resolution proof or a counter-model for propositional arguments, and a first-order resolution proof for quantified ones",
 	}, s.handleProve)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "check-syntax",
-		Description: "Validate syntax of logical statements",
+		Description: "Validate syntax of logical statements, reporting the position of each syntax error",
 	}, s.handleCheckSyntax)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "search",
-		Description: "Search through all thoughts",
+		Description: "Search through all thoughts in the current workspace",
 	}, s.handleSearch)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "get-metrics",
 		Description: "Get system performance and usage metrics",
 		InputSchema: map[string]any{
@@ -590,7 +707,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 		},
 	}, s.handleGetMetrics)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "list-integration-patterns",
 		Description: `List common multi-server workflow patterns for orchestrating tools across the MCP ecosystem.
 
@@ -626,31 +743,55 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 		},
 	}, s.handleListIntegrationPatterns)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "recent-branches",
 		Description: "Get recently accessed branches for quick context switching",
 		InputSchema: map[string]any{
-			"type":       "object",
-			"properties": map[string]any{},
+			"type": "object",
+			"properties": map[string]any{
+				"workspace": workspaceSchemaProperty,
+			},
 		},
 	}, s.handleRecentBranches)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name: "set-workspace",
+		Description: `Select the workspace (project namespace) for the current MCP session.
+
+Thoughts, branches, the active branch, insights, episodic trajectories, context
+signatures and RL outcomes are partitioned by workspace. Tools that accept a
+"workspace" parameter use it when given; otherwise they use the workspace
+selected here, or "default".
+
+**Parameters:**
+- workspace (required): Letters, digits, '-', '_', '.' and '/' (max 64 characters)
+
+**Returns:** status, workspace, previous_workspace, session_scoped
+
+**Example:** {"workspace": "billing-service"}`,
+	}, s.handleSetWorkspace)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "probabilistic-reasoning",
-		Description: "Perform Bayesian inference and update probabilistic beliefs based on evidence. Required: operation (\"create\", \"update\", \"get\", or \"combine\"). For create: statement, prior_prob (0-1). For update: belief_id, evidence_id, likelihood (0-1), evidence_prob (0-1). For get: belief_id. For combine: belief_ids (array), combine_op (\"and\" or \"or\"). Example: {\"operation\": \"create\", \"statement\": \"X is true\", \"prior_prob\": 0.5}",
+		Description: "Perform Bayesian inference and update probabilistic beliefs based on evidence. Required: operation (\"create\", \"update\", \"get\", or \"combine\"). For create: statement, prior_prob (0-1). For update: belief_id, evidence_id, likelihood (0-1), evidence_prob (0-1). For get: belief_id. For combine: belief_ids (array), combine_op (\"and\" or \"or\"). Optional: workspace holding the beliefs (default: session workspace). Example: {\"operation\": \"create\", \"statement\": \"X is true\", \"prior_prob\": 0.5}",
 	}, s.handleProbabilisticReasoning)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "list-beliefs",
+		Description: "List the workspace's probabilistic beliefs, most recently updated first, including beliefs from earlier sessions. Optional: workspace (default: session workspace), limit (default 50), offset. Use probabilistic-reasoning with operation \"get\" or \"update\" to work with a listed belief.",
+	}, s.handleListBeliefs)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "assess-evidence",
 		Description: "Assess the quality, reliability, and relevance of evidence for claims",
 	}, s.handleAssessEvidence)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "detect-contradictions",
 		Description: "Detect contradictions among a set of thoughts or statements",
 	}, s.handleDetectContradictions)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "make-decision",
 		Description: `Create structured multi-criteria decision framework and recommendations.
 
@@ -658,6 +799,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 - question (required): Decision question
 - options (required): Array of options with id, name, description, scores, pros, cons
 - criteria (required): Array of criteria with id, name, weight, maximize flag
+- workspace (optional): Workspace to store the decision in (default: session workspace)
 
 **Returns:** decision with recommendation, confidence, and metadata with:
 - export_formats.obsidian_note: Complete decision document in markdown
@@ -680,13 +822,24 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 **Example:** {"question": "Which database?", "options": [{"id": "pg", "name": "PostgreSQL", "scores": {"cost": 0.8}}], "criteria": [{"id": "cost", "weight": 0.5}]}`,
 	}, s.handleMakeDecision)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "list-decisions",
+		Description: "List the workspace's decisions created by make-decision, newest first, including decisions from earlier sessions. Optional: workspace (default: session workspace), limit (default 50), offset.",
+	}, s.handleListDecisions)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "get-decision",
+		Description: "Retrieve a previously created decision of the workspace by ID. Required: decision_id. Optional: workspace (default: session workspace).",
+	}, s.handleGetDecision)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "decompose-problem",
 		Description: `Break down complex problems into manageable subproblems with dependencies using domain-specific templates.
 
 **Parameters:**
 - problem (required): Complex problem statement
 - domain (optional): Explicit domain override - "debugging", "proof", "architecture", "research", or "general"
+- workspace (optional): Workspace to store the decomposition in (default: session workspace)
 
 **Domain-Specific Templates:**
 - debugging (6 steps): For bugs, errors, crashes, flaky tests - keywords: debug, error, fix, crash, trace
@@ -718,23 +871,33 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 - Explicit: {"problem": "Improve performance", "domain": "debugging"}`,
 	}, s.handleDecomposeProblem)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "list-decompositions",
+		Description: "List the workspace's problem decompositions created by decompose-problem, newest first, including decompositions from earlier sessions. Optional: workspace (default: session workspace), limit (default 50), offset.",
+	}, s.handleListDecompositions)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "get-decomposition",
+		Description: "Retrieve a previously created problem decomposition of the workspace by ID. Required: decomposition_id. Optional: workspace (default: session workspace).",
+	}, s.handleGetDecomposition)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "sensitivity-analysis",
 		Description: "Test robustness of conclusions to changes in underlying assumptions",
 	}, s.handleSensitivityAnalysis)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "self-evaluate",
 		Description: "Perform metacognitive self-assessment of reasoning quality and completeness",
 	}, s.handleSelfEvaluate)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "detect-biases",
 		Description: "Identify cognitive biases AND logical fallacies in reasoning (comprehensive analysis). Required: EITHER thought_id OR branch_id (not both). Detects cognitive biases (confirmation bias, anchoring, availability heuristic, etc.) AND logical fallacies (ad hominem, straw man, affirming the consequent, etc.). Returns separate lists plus a unified 'combined' list. Example: {\"thought_id\": \"thought_123\"}",
 	}, s.handleDetectBiases)
 
 	// Phase 1: Hallucination Detection Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "verify-thought",
 		Description: `Verify a thought for hallucinations using semantic uncertainty measurement.
 
@@ -764,7 +927,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 **Example:** {"thought_id": "thought_123", "verification_level": "hybrid"}`,
 	}, s.handleVerifyThought)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "get-hallucination-report",
 		Description: `Retrieve cached hallucination verification report for a thought.
 
@@ -782,7 +945,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 	}, s.handleGetHallucinationReport)
 
 	// Phase 1: Confidence Calibration Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "record-prediction",
 		Description: `Record a confidence prediction for calibration tracking.
 
@@ -802,7 +965,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 **Example:** {"thought_id": "thought_123", "confidence": 0.8, "mode": "linear"}`,
 	}, s.handleRecordPrediction)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "record-outcome",
 		Description: `Record the actual outcome of a prediction for calibration.
 
@@ -823,7 +986,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 **Example:** {"thought_id": "thought_123", "was_correct": true, "actual_confidence": 0.9, "source": "validation"}`,
 	}, s.handleRecordOutcome)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "get-calibration-report",
 		Description: `Generate comprehensive confidence calibration report.
 
@@ -851,7 +1014,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 	}, s.handleGetCalibrationReport)
 
 	// Phase 2: Multi-Perspective Analysis Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "analyze-perspectives",
 		Description: `Analyze a situation from multiple stakeholder perspectives, identifying concerns, priorities, and conflicts.
 
@@ -879,7 +1042,7 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 	}, s.handleAnalyzePerspectives)
 
 	// Phase 2: Temporal Reasoning Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "analyze-temporal",
 		Description: `Analyze short-term vs long-term implications of a decision, identifying tradeoffs and providing recommendations.
 
@@ -907,24 +1070,25 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 **Example:** {"situation": "Refactor now or after release?", "time_horizon": "months"}`,
 	}, s.handleAnalyzeTemporal)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "compare-time-horizons",
 		Description: "Compare how a decision looks across different time horizons (days-weeks, months, years)",
 	}, s.handleCompareTimeHorizons)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "identify-optimal-timing",
 		Description: "Determine optimal timing for a decision based on situation and constraints",
 	}, s.handleIdentifyOptimalTiming)
 
 	// Phase 3: Causal Reasoning Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name: "build-causal-graph",
 		Description: `Construct a causal graph from observations, identifying variables and causal relationships.
 
 **Parameters:**
 - description (required): Context for the causal model
 - observations (required): Array of causal statements
+- workspace (optional): Workspace to store the graph in (default: session workspace)
 
 **Returns:** graph with variables, links, and metadata with:
 - suggested_next_tools: memory:create_entities, simulate-intervention
@@ -945,44 +1109,49 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 **Example:** {"description": "Sales process", "observations": ["Marketing increases awareness", "Awareness drives sales"]}`,
 	}, s.handleBuildCausalGraph)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "simulate-intervention",
-		Description: "Simulate the effects of intervening on a variable in a causal graph",
+		Description: "Simulate the effects of intervening on a variable in a causal graph of the workspace (workspace parameter, default: session workspace)",
 	}, s.handleSimulateIntervention)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "generate-counterfactual",
-		Description: "Generate a counterfactual scenario ('what if') by changing variables in a causal model",
+		Description: "Generate a counterfactual scenario ('what if') by changing variables in a causal model of the workspace (workspace parameter, default: session workspace)",
 	}, s.handleGenerateCounterfactual)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "analyze-correlation-vs-causation",
 		Description: "Analyze whether an observed relationship is likely correlation or causation",
 	}, s.handleAnalyzeCorrelationVsCausation)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "get-causal-graph",
-		Description: "Retrieve a previously built causal graph by ID",
+		Description: "Retrieve a previously built causal graph of the workspace by ID, optionally rendered as Graphviz DOT, Mermaid or GraphML (format parameter). Optional: workspace (default: session workspace)",
 	}, s.handleGetCausalGraph)
 
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "list-causal-graphs",
+		Description: "List the workspace's stored causal graphs, newest first, including graphs built in earlier sessions. Optional: workspace (default: session workspace), limit, offset",
+	}, s.handleListCausalGraphs)
+
 	// Phase 3: Cross-Mode Synthesis Tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "synthesize-insights",
 		Description: "Synthesize insights from multiple reasoning modes, identifying synergies and conflicts",
 	}, s.handleSynthesizeInsights)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "detect-emergent-patterns",
 		Description: "Detect emergent patterns that become visible when combining multiple reasoning modes",
 	}, s.handleDetectEmergentPatterns)
 
 	// Workflow orchestration tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "execute-workflow",
-		Description: "Execute a predefined workflow that coordinates multiple reasoning tools automatically. Required: workflow_id (string), input (object with workflow parameters). Common workflows: \"comprehensive-analysis\", \"validation-pipeline\". Input must include \"problem\" field. Use list-workflows to see available workflows. Example: {\"workflow_id\": \"comprehensive-analysis\", \"input\": {\"problem\": \"Optimize system\"}}",
+		Description: "Execute a predefined workflow that coordinates multiple reasoning tools automatically. Required: workflow_id (string), input (object with workflow parameters). Common workflows: \"comprehensive-analysis\", \"validation-pipeline\". Input must include \"problem\" field. Optional: workspace the run is recorded in (default: session workspace). Use list-workflows to see available workflows. Example: {\"workflow_id\": \"comprehensive-analysis\", \"input\": {\"problem\": \"Optimize system\"}}",
 	}, s.handleExecuteWorkflow)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "list-workflows",
 		Description: "List all available automated workflows for multi-tool reasoning pipelines",
 		InputSchema: map[string]any{
@@ -991,111 +1160,166 @@ func (s *UnifiedServer) RegisterTools(mcpServer *mcp.Server) {
 		},
 	}, s.handleListWorkflows)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "register-workflow",
-		Description: "Register a new custom workflow for automated tool coordination",
+		Description: "Register a new custom workflow for automated tool coordination. Set update to replace an existing workflow; with SQLite storage every change is kept as a new version",
 	}, s.handleRegisterWorkflow)
 
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "list-workflow-versions",
+		Description: "List the stored versions of a workflow with their source (tool or definition file), checksum and creation time. Parameters: workflow_id (required)",
+	}, s.handleListWorkflowVersions)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "diff-workflow-versions",
+		Description: "Compare two stored versions of a workflow: changed fields, steps added, removed, modified or reordered. Parameters: workflow_id (required), from_version (default: previous), to_version (default: latest)",
+	}, s.handleDiffWorkflowVersions)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "delete-workflow",
+		Description: "Delete a workflow with all its versions, or a single version. Deleting the current version falls back to the previous one. Parameters: workflow_id (required), version",
+	}, s.handleDeleteWorkflow)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "list-workflow-runs",
+		Description: "List recorded workflow runs, most recent first, with status, trigger (execute, resume or rerun) and timings. Parameters: workflow_id, status ('running'/'success'/'partial'/'failed'), limit (default 20, max 100), offset, workspace (default: session workspace)",
+	}, s.handleListWorkflowRuns)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "get-workflow-run",
+		Description: "Fetch a recorded workflow run: its input, per-step outputs, reasoning context, step timeline and error. Parameters: run_id (required), workspace holding the run (default: session workspace)",
+	}, s.handleGetWorkflowRun)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "resume-workflow-run",
+		Description: "Resume a failed or partial workflow run from its failing steps. Steps that succeeded are restored from the run instead of executed again. Parameters: run_id (required), input (keys replacing the run's input), workspace holding the run (default: session workspace)",
+	}, s.handleResumeWorkflowRun)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "rerun-workflow",
+		Description: "Execute the workflow of a recorded run again from the start with the run's input. Parameters: run_id (required), input (keys replacing the run's input, e.g. {\"problem\": \"...\"}), workspace holding the run (default: session workspace)",
+	}, s.handleRerunWorkflow)
+
 	// Phase 2-3: Advanced reasoning tools
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "dual-process-think",
 		Description: "Execute dual-process reasoning (System 1: fast/intuitive, System 2: slow/analytical). Auto-detects complexity and escalates as needed. Parameters: content (required), mode, branch_id, force_system ('system1'/'system2'), key_points. Returns: thought_id, system_used, complexity, escalated, timings, confidence",
 	}, s.handleDualProcessThink)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "create-checkpoint",
-		Description: "Create a backtracking checkpoint in tree mode. Save current branch state for later restoration. Parameters: branch_id (required), name (required), description. Returns: checkpoint_id, thought_count, insight_count, created_at",
+		Description: "Create a backtracking checkpoint in tree mode. Save current branch state for later restoration. Parameters: branch_id (required), name (required), description, workspace (default: session workspace). Returns: checkpoint_id, thought_count, insight_count, created_at",
 	}, s.handleCreateCheckpoint)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "restore-checkpoint",
-		Description: "Restore branch from a checkpoint. Enables backtracking in tree exploration. Parameters: checkpoint_id (required). Returns: branch_id, thought_count, insight_count, message",
+		Description: "Restore branch from a checkpoint. Enables backtracking in tree exploration. Parameters: checkpoint_id (required), workspace (default: session workspace). Returns: branch_id, thought_count, insight_count, message",
 	}, s.handleRestoreCheckpoint)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "list-checkpoints",
-		Description: "List available checkpoints for backtracking. Parameters: branch_id (optional - filter by branch). Returns: array of checkpoints with id, name, description, branch_id, thought_count, created_at",
+		Description: "List available checkpoints for backtracking. Parameters: branch_id (optional - filter by branch), workspace (default: session workspace). Returns: array of checkpoints with id, name, description, branch_id, thought_count, created_at",
 	}, s.handleListCheckpoints)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "fork-checkpoint",
+		Description: "Fork a new branch from a checkpoint without modifying the original branch. Use it to try alternative approaches from the same savepoint. Parameters: checkpoint_id (required), name (optional label for the fork), workspace (default: session workspace). Returns: branch_id, parent_branch_id, thought_count, insight_count",
+	}, s.handleForkCheckpoint)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "diff-checkpoints",
+		Description: "Compare two checkpoints, including checkpoints on a branch and its forks. Parameters: from_checkpoint_id (required), to_checkpoint_id (required), workspace (default: session workspace). Returns: thoughts_added, thoughts_removed, thoughts_modified, insights_added, insights_removed, has_changes",
+	}, s.handleDiffCheckpoints)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
+		Name:        "prune-branch",
+		Description: "Mark a branch as a dead end after a failed exploration. Parameters: branch_id (required), reason, workspace (default: session workspace). Returns: branch_id, state, reason",
+	}, s.handlePruneBranch)
+
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "generate-hypotheses",
 		Description: "Generate hypotheses from observations using abductive reasoning (inference to best explanation). Parameters: observations (array of {description, confidence}), max_hypotheses, min_parsimony. Returns: array of hypotheses with id, description, parsimony, prior_probability",
 	}, s.handleGenerateHypotheses)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "evaluate-hypotheses",
 		Description: "Evaluate and rank hypotheses using Bayesian inference, parsimony, and explanatory power. Parameters: observations (required), hypotheses (required), method ('bayesian'/'parsimony'/'combined'). Returns: ranked_hypotheses with posterior_probability, explanatory_power, parsimony scores",
 	}, s.handleEvaluateHypotheses)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "retrieve-similar-cases",
 		Description: "Retrieve similar cases from case library using CBR (case-based reasoning). Parameters: problem {description, context, goals, constraints}, domain, max_cases, min_similarity. Returns: array of similar cases with similarity scores, solutions, success_rates",
 	}, s.handleRetrieveCases)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "perform-cbr-cycle",
 		Description: "Perform full CBR cycle: Retrieve similar cases, Reuse/adapt solution, provide recommendations. Parameters: problem {description, context, goals, constraints}, domain. Returns: retrieved_count, best_case, adapted_solution, strategy, confidence",
 	}, s.handlePerformCBRCycle)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "detect-blind-spots",
 		Description: "Detect unknown unknowns, blind spots, and knowledge gaps using metacognitive analysis. Parameters: content (required), domain, context, assumptions, confidence. Returns: blind_spots (array), missing_considerations, unchallenged_assumptions, suggested_questions, overall_risk, risk_level, analysis",
 	}, s.handleDetectBlindSpots)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "prove-theorem",
-		Description: "Attempt to prove a theorem using symbolic reasoning and logical inference rules (modus ponens, simplification, conjunction). Parameters: name, premises (array), conclusion. Returns: status, is_valid, confidence, proof with steps (step_number, statement, justification, rule, dependencies)",
+		Description: "Attempt to prove a theorem. Single-step rules (modus ponens, simplification, conjunction) are tried first; otherwise premises and conclusion are formalized in first-order logic (predicates P(x), ∀/∃ or \"for all x\", and English such as \"all services that call auth must use TLS\") and the conclusion is proved by skolemization and resolution with unification. Parameters: name, premises (array), conclusion, max_depth and timeout_ms (optional search bounds); or format (tptp for FOF/CNF, smtlib for SMT-LIB v2 UF) and problem instead of premises and conclusion; export (tptp or smtlib). Returns: status (proven, unproven, or undecidable when the search hits a bound), is_valid, confidence, proof with numbered steps (step_number, statement, justification, rule, dependencies), premises, conclusion and expected_status for an imported problem, exported_problem and exported_proof (TSTP derivation or SMT-LIB step checks)",
 	}, s.handleProveTheorem)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	handlers.AddTool(mcpServer, s.dispatcher, &mcp.Tool{
 		Name:        "check-constraints",
-		Description: "Check consistency of symbolic constraints. Detect conflicts and contradictions. Parameters: symbols (array of {name, type, domain}), constraints (array of {type, expression, symbols}). Returns: is_consistent, conflicts (array), explanation",
+		Description: "Check consistency of symbolic constraints. Linear comparisons over integer and real symbols (e.g. \"x + 2y <= 10\", \"0 <= x < y\", \"x in [1, 5]\") are decided together by bounds propagation and Fourier–Motzkin elimination, with branch and bound for integers; other constraints are compared pairwise. Parameters: symbols (array of {name, type, domain}), constraints (array of {type, expression, symbols}); or format (smtlib for QF_LIA/QF_LRA/QF_LIRA, tptp for TFF arithmetic) and problem instead of symbols and constraints; export (tptp or smtlib). Returns: is_consistent, conflicts (array), explanation, witness (satisfying assignment when consistent), unsat_core (minimal set of conflicting constraint IDs), unchecked, names and expected_status for an imported problem, exported_problem",
 	}, s.handleCheckConstraints)
 
-	// Register enhanced tools (analogical reasoning, argument analysis, fallacy detection, evidence pipeline, temporal-causal integration)
+	// Register enhanced tools (analogical reasoning, argument analysis, evidence pipeline, temporal-causal integration)
 	handlers.RegisterEnhancedTools(
 		mcpServer,
+		s.dispatcher,
 		s.analogicalReasoner,
 		s.argumentAnalyzer,
-		s.fallacyDetector,
-		s.orchestrator,
 		s.evidencePipeline,
 		s.causalTemporalIntegration,
 	)
 
+	// Register fallacy detection and rule pack tools
+	handlers.RegisterFallacyTools(mcpServer, s.dispatcher, s.fallacyHandler)
+
 	// Register episodic memory tools (Phase 2)
-	handlers.RegisterEpisodicMemoryTools(mcpServer, s.episodicMemoryHandler)
+	handlers.RegisterEpisodicMemoryTools(mcpServer, s.dispatcher, s.episodicMemoryHandler)
 
 	// Register knowledge graph tools (Phase 4)
 	if s.knowledgeGraph != nil {
 		kgHandler := handlers.NewKnowledgeHandlers(s.knowledgeGraph)
-		handlers.RegisterKnowledgeGraphTools(mcpServer, kgHandler)
+		handlers.RegisterKnowledgeGraphTools(mcpServer, s.dispatcher, kgHandler)
 	}
 
 	// Register thought similarity tools - only if embedder available
 	if s.thoughtSearcher != nil {
 		simHandler := handlers.NewSimilarityHandler(s.thoughtSearcher)
-		handlers.RegisterSimilarityTools(mcpServer, simHandler)
+		handlers.RegisterSimilarityTools(mcpServer, s.dispatcher, simHandler)
 	}
 
-	// Register Graph-of-Thoughts tools (8 tools)
-	handlers.RegisterGoTTools(mcpServer, s.gotHandler)
+	// Register Graph-of-Thoughts tools (10 tools)
+	handlers.RegisterGoTTools(mcpServer, s.dispatcher, s.gotHandler)
 
 	// Register Claude Code optimization tools (5 tools)
-	handlers.RegisterClaudeCodeTools(mcpServer, s.claudeCodeHandler)
+	handlers.RegisterClaudeCodeTools(mcpServer, s.dispatcher, s.claudeCodeHandler)
 
 	// Register research tools with web search (1 tool)
-	handlers.RegisterResearchTools(mcpServer, s.researchHandler)
+	handlers.RegisterResearchTools(mcpServer, s.dispatcher, s.researchHandler)
 
 	// Register multimodal tools (1 tool) - ALWAYS enabled
 	if s.multimodalHandler != nil {
-		handlers.RegisterMultimodalTools(mcpServer, s.multimodalHandler)
+		handlers.RegisterMultimodalTools(mcpServer, s.dispatcher, s.multimodalHandler)
 	}
 
 	// Register agentic tools (2 tools) - ALWAYS enabled
 	if s.agentHandler != nil {
-		handlers.RegisterAgentTools(mcpServer, s.agentHandler)
+		handlers.RegisterAgentTools(mcpServer, s.dispatcher, s.agentHandler)
 	}
+
+	// Expose the registered tools to the agent
+	s.populateToolRegistry(s.agentTools)
 }
 
 type ThinkRequest struct {
@@ -1111,6 +1335,7 @@ type ThinkRequest struct {
 	ForceRebellion       bool            `json:"force_rebellion,omitempty"`
 	CrossRefs            []CrossRefInput `json:"cross_refs,omitempty"`
 	FormatLevel          string          `json:"format_level,omitempty"` // "full", "compact", or "minimal"
+	Workspace            string          `json:"workspace,omitempty"`
 }
 
 type CrossRefInput struct {
@@ -1138,6 +1363,9 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 		return nil, nil, err
 	}
 
+	workspace := s.resolveWorkspace(req, input.Workspace)
+	store := s.storageFor(workspace)
+
 	// Track if this is an auto-retry for metadata
 	isAutoRetry := false
 	autoValidationTriggered := false
@@ -1154,6 +1382,7 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 			ForceRebellion:       input.ForceRebellion,
 			ChallengeAssumptions: challengeAssumptions || input.ChallengeAssumptions,
 			CrossRefs:            convertCrossRefs(input.CrossRefs),
+			Workspace:            workspace,
 		}
 
 		if thoughtInput.Confidence == 0 {
@@ -1202,7 +1431,7 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 		autoValidationTriggered = true
 
 		// Get the thought for self-evaluation
-		thought, err := s.storage.GetThought(result.ThoughtID)
+		thought, err := store.GetThought(result.ThoughtID)
 		if err == nil && thought != nil {
 			// Run self-evaluation
 			evaluation, evalErr := s.selfEvaluator.EvaluateThought(thought)
@@ -1231,7 +1460,7 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 						result = retryResult
 
 						// Update the thought's metadata to indicate auto-validation occurred
-						if retryThought, err := s.storage.GetThought(result.ThoughtID); err == nil && retryThought != nil {
+						if retryThought, err := store.GetThought(result.ThoughtID); err == nil && retryThought != nil {
 							if retryThought.Metadata == nil {
 								retryThought.Metadata = make(map[string]interface{})
 							}
@@ -1244,7 +1473,7 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 								"coherence":    evaluation.CoherenceScore,
 							}
 							// Re-store the thought with updated metadata
-							_ = s.storage.StoreThought(retryThought)
+							_ = store.StoreThought(retryThought)
 						}
 					}
 				} else {
@@ -1259,7 +1488,7 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 						"coherence":    evaluation.CoherenceScore,
 					}
 					// Re-store the thought with updated metadata
-					_ = s.storage.StoreThought(thought)
+					_ = store.StoreThought(thought)
 				}
 			}
 		}
@@ -1268,7 +1497,7 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 	// Standard validation if requested
 	isValid := true
 	if input.RequireValidation {
-		thought, _ := s.storage.GetThought(result.ThoughtID)
+		thought, _ := store.GetThought(result.ThoughtID)
 		if thought != nil {
 			validationResult, _ := s.validator.ValidateThought(thought)
 			if validationResult != nil {
@@ -1278,7 +1507,7 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 	}
 
 	// Generate metadata for Claude orchestration
-	thought, _ := s.storage.GetThought(result.ThoughtID)
+	thought, _ := store.GetThought(result.ThoughtID)
 	var metadata *types.ResponseMetadata
 	if thought != nil {
 		metadataGen := handlers.NewMetadataGenerator()
@@ -1324,8 +1553,9 @@ func (s *UnifiedServer) handleThink(ctx context.Context, req *mcp.CallToolReques
 	var finalResponse interface{} = response
 	if s.contextBridge != nil {
 		params := map[string]interface{}{
-			"content": input.Content,
-			"mode":    input.Mode,
+			"content":   input.Content,
+			"mode":      input.Mode,
+			"workspace": workspace,
 		}
 		if input.BranchID != "" {
 			params["branch_id"] = input.BranchID
@@ -1357,6 +1587,7 @@ type HistoryRequest struct {
 	Limit       int    `json:"limit,omitempty"`
 	Offset      int    `json:"offset,omitempty"`
 	FormatLevel string `json:"format_level,omitempty"` // "full", "compact", or "minimal"
+	Workspace   string `json:"workspace,omitempty"`
 }
 
 type HistoryResponse struct {
@@ -1375,10 +1606,12 @@ func (s *UnifiedServer) handleHistory(ctx context.Context, req *mcp.CallToolRequ
 		limit = 100 // Default to 100 results
 	}
 
+	store := s.storageFor(s.resolveWorkspace(req, input.Workspace))
+
 	var thoughts []*types.Thought
 
 	if input.BranchID != "" {
-		branch, err := s.storage.GetBranch(input.BranchID)
+		branch, err := store.GetBranch(input.BranchID)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1386,7 +1619,7 @@ func (s *UnifiedServer) handleHistory(ctx context.Context, req *mcp.CallToolRequ
 		thoughts = paginateThoughts(branch.Thoughts, limit, input.Offset)
 	} else {
 		mode := types.ThinkingMode(input.Mode)
-		thoughts = s.storage.SearchThoughts("", mode, limit, input.Offset)
+		thoughts = store.SearchThoughts("", mode, limit, input.Offset)
 	}
 
 	response := &HistoryResponse{Thoughts: thoughts}
@@ -1418,10 +1651,15 @@ type ListBranchesResponse struct {
 	ActiveBranchID string          `json:"active_branch_id"`
 }
 
-func (s *UnifiedServer) handleListBranches(ctx context.Context, req *mcp.CallToolRequest, input EmptyRequest) (*mcp.CallToolResult, *ListBranchesResponse, error) {
-	branches := s.storage.ListBranches()
+func (s *UnifiedServer) handleListBranches(ctx context.Context, req *mcp.CallToolRequest, input WorkspaceRequest) (*mcp.CallToolResult, *ListBranchesResponse, error) {
+	if err := ValidateWorkspaceRequest(&input); err != nil {
+		return nil, nil, err
+	}
+
+	store := s.storageFor(s.resolveWorkspace(req, input.Workspace))
+	branches := store.ListBranches()
 
-	activeBranch, _ := s.storage.GetActiveBranch()
+	activeBranch, _ := store.GetActiveBranch()
 	activeID := ""
 	if activeBranch != nil {
 		activeID = activeBranch.ID
@@ -1440,6 +1678,7 @@ func (s *UnifiedServer) handleListBranches(ctx context.Context, req *mcp.CallToo
 type FocusBranchRequest struct {
 	BranchID    string `json:"branch_id"`
 	FormatLevel string `json:"format_level,omitempty"` // "full", "compact", or "minimal"
+	Workspace   string `json:"workspace,omitempty"`
 }
 
 type FocusBranchResponse struct {
@@ -1453,8 +1692,10 @@ func (s *UnifiedServer) handleFocusBranch(ctx context.Context, req *mcp.CallTool
 		return nil, nil, err
 	}
 
+	store := s.storageFor(s.resolveWorkspace(req, input.Workspace))
+
 	// Check if branch is already active
-	activeBranch, _ := s.storage.GetActiveBranch()
+	activeBranch, _ := store.GetActiveBranch()
 	if activeBranch != nil && activeBranch.ID == input.BranchID {
 		response := &FocusBranchResponse{
 			Status:         "already_active",
@@ -1465,7 +1706,7 @@ func (s *UnifiedServer) handleFocusBranch(ctx context.Context, req *mcp.CallTool
 		}, response, nil
 	}
 
-	if err := s.storage.SetActiveBranch(input.BranchID); err != nil {
+	if err := store.SetActiveBranch(input.BranchID); err != nil {
 		return nil, nil, err
 	}
 
@@ -1480,7 +1721,8 @@ func (s *UnifiedServer) handleFocusBranch(ctx context.Context, req *mcp.CallTool
 }
 
 type BranchHistoryRequest struct {
-	BranchID string `json:"branch_id"`
+	BranchID  string `json:"branch_id"`
+	Workspace string `json:"workspace,omitempty"`
 }
 
 func (s *UnifiedServer) handleBranchHistory(ctx context.Context, req *mcp.CallToolRequest, input BranchHistoryRequest) (*mcp.CallToolResult, *modes.BranchHistory, error) {
@@ -1489,7 +1731,12 @@ func (s *UnifiedServer) handleBranchHistory(ctx context.Context, req *mcp.CallTo
 		return nil, nil, err
 	}
 
-	history, err := s.tree.GetBranchHistory(ctx, input.BranchID)
+	tree := s.tree
+	if workspace := s.resolveWorkspace(req, input.Workspace); workspace != storage.WorkspaceOf(s.storage) {
+		tree = modes.NewTreeMode(s.storageFor(workspace))
+	}
+
+	history, err := tree.GetBranchHistory(ctx, input.BranchID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1501,6 +1748,7 @@ func (s *UnifiedServer) handleBranchHistory(ctx context.Context, req *mcp.CallTo
 
 type ValidateRequest struct {
 	ThoughtID string `json:"thought_id"`
+	Workspace string `json:"workspace,omitempty"`
 }
 
 type ValidateResponse struct {
@@ -1514,7 +1762,8 @@ func (s *UnifiedServer) handleValidate(ctx context.Context, req *mcp.CallToolReq
 		return nil, nil, err
 	}
 
-	thought, err := s.storage.GetThought(input.ThoughtID)
+	store := s.storageFor(s.resolveWorkspace(req, input.Workspace))
+	thought, err := store.GetThought(input.ThoughtID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1557,10 +1806,12 @@ type ProveRequest struct {
 }
 
 type ProveResponse struct {
-	IsProvable bool     `json:"is_provable"`
-	Premises   []string `json:"premises"`
-	Conclusion string   `json:"conclusion"`
-	Steps      []string `json:"steps"`
+	IsProvable   bool            `json:"is_provable"`
+	Premises     []string        `json:"premises"`
+	Conclusion   string          `json:"conclusion"`
+	Steps        []string        `json:"steps"`
+	Method       string          `json:"method,omitempty"`        // "resolution", "first_order_resolution" or "patterns"
+	CounterModel map[string]bool `json:"counter_model,omitempty"` // Assignment refuting an invalid argument
 }
 
 func (s *UnifiedServer) handleProve(ctx context.Context, req *mcp.CallToolRequest, input ProveRequest) (*mcp.CallToolResult, *ProveResponse, error) {
@@ -1572,10 +1823,12 @@ func (s *UnifiedServer) handleProve(ctx context.Context, req *mcp.CallToolReques
 	result := s.validator.Prove(input.Premises, input.Conclusion)
 
 	response := &ProveResponse{
-		IsProvable: result.IsProvable,
-		Premises:   result.Premises,
-		Conclusion: result.Conclusion,
-		Steps:      result.Steps,
+		IsProvable:   result.IsProvable,
+		Premises:     result.Premises,
+		Conclusion:   result.Conclusion,
+		Steps:        result.Steps,
+		Method:       result.Method,
+		CounterModel: result.CounterModel,
 	}
 
 	return &mcp.CallToolResult{
@@ -1609,10 +1862,11 @@ func (s *UnifiedServer) handleCheckSyntax(ctx context.Context, req *mcp.CallTool
 }
 
 type SearchRequest struct {
-	Query  string `json:"query"`
-	Mode   string `json:"mode,omitempty"`
-	Limit  int    `json:"limit,omitempty"`
-	Offset int    `json:"offset,omitempty"`
+	Query     string `json:"query"`
+	Mode      string `json:"mode,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+	Offset    int    `json:"offset,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
 }
 
 type SearchResponse struct {
@@ -1632,7 +1886,8 @@ func (s *UnifiedServer) handleSearch(ctx context.Context, req *mcp.CallToolReque
 	}
 
 	mode := types.ThinkingMode(input.Mode)
-	thoughts := s.storage.SearchThoughts(input.Query, mode, limit, input.Offset)
+	store := s.storageFor(s.resolveWorkspace(req, input.Workspace))
+	thoughts := store.SearchThoughts(input.Query, mode, limit, input.Offset)
 
 	response := &SearchResponse{Thoughts: thoughts}
 
@@ -1665,6 +1920,8 @@ type MetricsResponse struct {
 	Probabilistic      map[string]interface{} `json:"probabilistic,omitempty"`
 	ThompsonSamplingRL map[string]interface{} `json:"thompson_sampling_rl,omitempty"`
 	KnowledgeGraph     map[string]interface{} `json:"knowledge_graph,omitempty"`
+	LLMTransport       *modes.TransportStats  `json:"llm_transport,omitempty"`
+	Usage              *usage.Summary         `json:"usage,omitempty"`
 }
 
 type RecentBranchesResponse struct {
@@ -1690,6 +1947,18 @@ func (s *UnifiedServer) handleGetMetrics(ctx context.Context, req *mcp.CallToolR
 		response.ContextBridge = s.contextBridge.GetMetrics()
 	}
 
+	// Include LLM retry, rate limit and circuit breaker state
+	if s.llmTransport != nil {
+		stats := s.llmTransport.Stats()
+		response.LLMTransport = &stats
+	}
+
+	// Include token usage by tool, session and model
+	if s.usage != nil {
+		summary := s.usage.Summary()
+		response.Usage = &summary
+	}
+
 	// Include probabilistic reasoning metrics
 	if s.probabilisticReasoner != nil {
 		response.Probabilistic = s.probabilisticReasoner.GetMetrics()
@@ -1795,14 +2064,19 @@ func (s *UnifiedServer) handleListIntegrationPatterns(ctx context.Context, req *
 	}, response, nil
 }
 
-func (s *UnifiedServer) handleRecentBranches(ctx context.Context, req *mcp.CallToolRequest, input EmptyRequest) (*mcp.CallToolResult, *RecentBranchesResponse, error) {
-	branches, err := s.storage.GetRecentBranches()
+func (s *UnifiedServer) handleRecentBranches(ctx context.Context, req *mcp.CallToolRequest, input WorkspaceRequest) (*mcp.CallToolResult, *RecentBranchesResponse, error) {
+	if err := ValidateWorkspaceRequest(&input); err != nil {
+		return nil, nil, err
+	}
+
+	store := s.storageFor(s.resolveWorkspace(req, input.Workspace))
+	branches, err := store.GetRecentBranches()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get recent branches: %w", err)
 	}
 
 	// Get active branch for context
-	activeBranch, _ := s.storage.GetActiveBranch()
+	activeBranch, _ := store.GetActiveBranch()
 	activeBranchID := ""
 	if activeBranch != nil {
 		activeBranchID = activeBranch.ID
@@ -1824,9 +2098,15 @@ func (s *UnifiedServer) handleRecentBranches(ctx context.Context, req *mcp.CallT
 // ============================================================================
 
 func (s *UnifiedServer) handleProbabilisticReasoning(ctx context.Context, req *mcp.CallToolRequest, input handlers.ProbabilisticReasoningRequest) (*mcp.CallToolResult, *handlers.ProbabilisticReasoningResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
 	return s.probabilisticHandler.HandleProbabilisticReasoning(ctx, req, input)
 }
 
+func (s *UnifiedServer) handleListBeliefs(ctx context.Context, req *mcp.CallToolRequest, input handlers.ListBeliefsRequest) (*mcp.CallToolResult, *handlers.ListBeliefsResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
+	return s.probabilisticHandler.HandleListBeliefs(ctx, req, input)
+}
+
 // ============================================================================
 // Assess Evidence Tool
 // ============================================================================
@@ -1848,6 +2128,7 @@ func (s *UnifiedServer) handleDetectContradictions(ctx context.Context, req *mcp
 // ============================================================================
 
 func (s *UnifiedServer) handleMakeDecision(ctx context.Context, req *mcp.CallToolRequest, input handlers.MakeDecisionRequest) (*mcp.CallToolResult, *handlers.MakeDecisionResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
 	result, response, err := s.decisionHandler.HandleMakeDecision(ctx, req, input)
 	if err != nil || result == nil {
 		return result, response, err
@@ -1872,11 +2153,22 @@ func (s *UnifiedServer) handleMakeDecision(ctx context.Context, req *mcp.CallToo
 	return result, response, err
 }
 
+func (s *UnifiedServer) handleListDecisions(ctx context.Context, req *mcp.CallToolRequest, input handlers.ListDecisionsRequest) (*mcp.CallToolResult, *handlers.ListDecisionsResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
+	return s.decisionHandler.HandleListDecisions(ctx, req, input)
+}
+
+func (s *UnifiedServer) handleGetDecision(ctx context.Context, req *mcp.CallToolRequest, input handlers.GetDecisionRequest) (*mcp.CallToolResult, *handlers.GetDecisionResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
+	return s.decisionHandler.HandleGetDecision(ctx, req, input)
+}
+
 // ============================================================================
 // Decompose Problem Tool
 // ============================================================================
 
 func (s *UnifiedServer) handleDecomposeProblem(ctx context.Context, req *mcp.CallToolRequest, input handlers.DecomposeProblemRequest) (*mcp.CallToolResult, *handlers.DecomposeProblemResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
 	result, response, err := s.decisionHandler.HandleDecomposeProblem(ctx, req, input)
 	if err != nil || result == nil {
 		return result, response, err
@@ -1901,6 +2193,16 @@ func (s *UnifiedServer) handleDecomposeProblem(ctx context.Context, req *mcp.Cal
 	return result, response, err
 }
 
+func (s *UnifiedServer) handleListDecompositions(ctx context.Context, req *mcp.CallToolRequest, input handlers.ListDecompositionsRequest) (*mcp.CallToolResult, *handlers.ListDecompositionsResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
+	return s.decisionHandler.HandleListDecompositions(ctx, req, input)
+}
+
+func (s *UnifiedServer) handleGetDecomposition(ctx context.Context, req *mcp.CallToolRequest, input handlers.GetDecompositionRequest) (*mcp.CallToolResult, *handlers.GetDecompositionResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
+	return s.decisionHandler.HandleGetDecomposition(ctx, req, input)
+}
+
 // ============================================================================
 // Sensitivity Analysis Tool
 // ============================================================================
@@ -2011,6 +2313,10 @@ func (s *UnifiedServer) handleGetCausalGraph(ctx context.Context, req *mcp.CallT
 	return s.causalHandler.HandleGetCausalGraph(ctx, req, input)
 }
 
+func (s *UnifiedServer) handleListCausalGraphs(ctx context.Context, req *mcp.CallToolRequest, input handlers.ListCausalGraphsRequest) (*mcp.CallToolResult, *handlers.ListCausalGraphsResponse, error) {
+	return s.causalHandler.HandleListCausalGraphs(ctx, req, input)
+}
+
 // Phase 3: Cross-Mode Synthesis
 
 type SynthesizeInsightsRequest struct {
@@ -2266,10 +2572,12 @@ func (s *UnifiedServer) DetectEmergentPatterns(ctx context.Context, req DetectEm
 type ExecuteWorkflowRequest struct {
 	WorkflowID string                 `json:"workflow_id"`
 	Input      map[string]interface{} `json:"input"`
+	Workspace  string                 `json:"workspace,omitempty"` // Workspace the run is recorded in
 }
 
 type ExecuteWorkflowResponse struct {
 	Result *orchestration.WorkflowResult `json:"result"`
+	RunID  string                        `json:"run_id,omitempty"` // Recorded run, also for failed runs
 	Status string                        `json:"status"`
 	Error  string                        `json:"error,omitempty"`
 }
@@ -2281,14 +2589,69 @@ type ListWorkflowsResponse struct {
 
 type RegisterWorkflowRequest struct {
 	Workflow *orchestration.Workflow `json:"workflow"`
+	Update   bool                    `json:"update,omitempty"` // Replace an existing workflow, storing a new version
 }
 
 type RegisterWorkflowResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	Version int    `json:"version,omitempty"`
 	Error   string `json:"error,omitempty"`
 }
 
+type ListWorkflowVersionsRequest struct {
+	WorkflowID string `json:"workflow_id"`
+}
+
+type ListWorkflowVersionsResponse struct {
+	WorkflowID     string                         `json:"workflow_id"`
+	CurrentVersion int                            `json:"current_version,omitempty"`
+	Versions       []*types.WorkflowVersionRecord `json:"versions"`
+	Count          int                            `json:"count"`
+}
+
+type DiffWorkflowVersionsRequest struct {
+	WorkflowID  string `json:"workflow_id"`
+	FromVersion int    `json:"from_version,omitempty"` // Default: the version before to_version
+	ToVersion   int    `json:"to_version,omitempty"`   // Default: the latest version
+}
+
+type DeleteWorkflowRequest struct {
+	WorkflowID string `json:"workflow_id"`
+	Version    int    `json:"version,omitempty"` // Default: the whole workflow with all its versions
+}
+
+type DeleteWorkflowResponse struct {
+	WorkflowID      string `json:"workflow_id"`
+	VersionsDeleted int    `json:"versions_deleted"`
+	CurrentVersion  int    `json:"current_version,omitempty"` // Version registered after the delete
+	Registered      bool   `json:"registered"`                // Whether the workflow is still registered
+}
+
+type ListWorkflowRunsRequest struct {
+	WorkflowID string `json:"workflow_id,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Limit      int    `json:"limit,omitempty"` // Default 20, max 100
+	Offset     int    `json:"offset,omitempty"`
+	Workspace  string `json:"workspace,omitempty"`
+}
+
+type ListWorkflowRunsResponse struct {
+	Runs  []*types.WorkflowRunSummary `json:"runs"`
+	Count int                         `json:"count"`
+}
+
+type GetWorkflowRunRequest struct {
+	RunID     string `json:"run_id"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+type WorkflowRunRequest struct {
+	RunID     string                 `json:"run_id"`
+	Input     map[string]interface{} `json:"input,omitempty"` // Keys replacing the run's input
+	Workspace string                 `json:"workspace,omitempty"`
+}
+
 // ============================================================================
 // Workflow Orchestration Handlers
 // ============================================================================
@@ -2304,11 +2667,23 @@ func (s *UnifiedServer) handleExecuteWorkflow(ctx context.Context, req *mcp.Call
 		return nil, nil, fmt.Errorf("orchestrator not initialized")
 	}
 
+	ctx = orchestration.WithWorkspace(ctx, s.resolveWorkspace(req, input.Workspace))
 	result, err := s.orchestrator.ExecuteWorkflow(ctx, input.WorkflowID, input.Input)
+	response := workflowResponse(result, err)
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
 
+// workflowResponse reports the outcome of executing a workflow
+func workflowResponse(result *orchestration.WorkflowResult, err error) *ExecuteWorkflowResponse {
 	response := &ExecuteWorkflowResponse{
 		Status: "completed",
 	}
+	if result != nil {
+		response.RunID = result.RunID
+	}
 
 	if err != nil {
 		response.Status = "failed"
@@ -2316,10 +2691,7 @@ func (s *UnifiedServer) handleExecuteWorkflow(ctx context.Context, req *mcp.Call
 	} else {
 		response.Result = result
 	}
-
-	return &mcp.CallToolResult{
-		Content: toJSONContent(response),
-	}, response, nil
+	return response
 }
 
 // handleListWorkflows lists all available workflows
@@ -2351,11 +2723,17 @@ func (s *UnifiedServer) handleRegisterWorkflow(ctx context.Context, req *mcp.Cal
 		return nil, nil, fmt.Errorf("orchestrator not initialized")
 	}
 
-	err := s.orchestrator.RegisterWorkflow(input.Workflow)
+	var err error
+	if input.Update {
+		err = s.orchestrator.UpdateWorkflow(input.Workflow)
+	} else {
+		err = s.orchestrator.RegisterWorkflow(input.Workflow)
+	}
 
 	response := &RegisterWorkflowResponse{
 		Success: err == nil,
 		Message: "Workflow registered successfully",
+		Version: input.Workflow.Version,
 	}
 
 	if err != nil {
@@ -2369,6 +2747,190 @@ func (s *UnifiedServer) handleRegisterWorkflow(ctx context.Context, req *mcp.Cal
 	}, response, nil
 }
 
+// handleListWorkflowVersions lists the stored versions of a workflow
+func (s *UnifiedServer) handleListWorkflowVersions(ctx context.Context, req *mcp.CallToolRequest, input ListWorkflowVersionsRequest) (*mcp.CallToolResult, *ListWorkflowVersionsResponse, error) {
+	if input.WorkflowID == "" {
+		return nil, nil, fmt.Errorf("workflow_id is required")
+	}
+	if s.orchestrator == nil {
+		return nil, nil, fmt.Errorf("orchestrator not initialized")
+	}
+
+	versions, err := s.orchestrator.WorkflowVersions(input.WorkflowID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &ListWorkflowVersionsResponse{
+		WorkflowID: input.WorkflowID,
+		Versions:   versions,
+		Count:      len(versions),
+	}
+	if current, err := s.orchestrator.GetWorkflow(input.WorkflowID); err == nil {
+		response.CurrentVersion = current.Version
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
+// handleDiffWorkflowVersions compares two stored versions of a workflow
+func (s *UnifiedServer) handleDiffWorkflowVersions(ctx context.Context, req *mcp.CallToolRequest, input DiffWorkflowVersionsRequest) (*mcp.CallToolResult, *orchestration.WorkflowDiff, error) {
+	if input.WorkflowID == "" {
+		return nil, nil, fmt.Errorf("workflow_id is required")
+	}
+	if input.FromVersion < 0 || input.ToVersion < 0 {
+		return nil, nil, fmt.Errorf("versions must not be negative")
+	}
+	if s.orchestrator == nil {
+		return nil, nil, fmt.Errorf("orchestrator not initialized")
+	}
+
+	diff, err := s.orchestrator.DiffWorkflowVersions(input.WorkflowID, input.FromVersion, input.ToVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("workflow diff failed: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(diff),
+	}, diff, nil
+}
+
+// handleDeleteWorkflow deletes a workflow or one of its versions
+func (s *UnifiedServer) handleDeleteWorkflow(ctx context.Context, req *mcp.CallToolRequest, input DeleteWorkflowRequest) (*mcp.CallToolResult, *DeleteWorkflowResponse, error) {
+	if input.WorkflowID == "" {
+		return nil, nil, fmt.Errorf("workflow_id is required")
+	}
+	if input.Version < 0 {
+		return nil, nil, fmt.Errorf("version must not be negative")
+	}
+	if s.orchestrator == nil {
+		return nil, nil, fmt.Errorf("orchestrator not initialized")
+	}
+
+	deleted, err := s.orchestrator.DeleteWorkflow(input.WorkflowID, input.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &DeleteWorkflowResponse{
+		WorkflowID:      input.WorkflowID,
+		VersionsDeleted: deleted,
+	}
+	if current, err := s.orchestrator.GetWorkflow(input.WorkflowID); err == nil {
+		response.Registered = true
+		response.CurrentVersion = current.Version
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
+// handleListWorkflowRuns lists recorded workflow runs
+func (s *UnifiedServer) handleListWorkflowRuns(ctx context.Context, req *mcp.CallToolRequest, input ListWorkflowRunsRequest) (*mcp.CallToolResult, *ListWorkflowRunsResponse, error) {
+	if input.Limit < 0 || input.Offset < 0 {
+		return nil, nil, fmt.Errorf("limit and offset must not be negative")
+	}
+	if input.Limit == 0 {
+		input.Limit = 20
+	}
+	if input.Limit > 100 {
+		input.Limit = 100
+	}
+	if err := ValidateWorkspace(input.Workspace); err != nil {
+		return nil, nil, err
+	}
+	if s.orchestrator == nil {
+		return nil, nil, fmt.Errorf("orchestrator not initialized")
+	}
+
+	runs, err := s.orchestrator.ListRuns(s.resolveWorkspace(req, input.Workspace), input.WorkflowID, input.Status, input.Limit, input.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &ListWorkflowRunsResponse{
+		Runs:  runs,
+		Count: len(runs),
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
+// handleGetWorkflowRun fetches a recorded workflow run
+func (s *UnifiedServer) handleGetWorkflowRun(ctx context.Context, req *mcp.CallToolRequest, input GetWorkflowRunRequest) (*mcp.CallToolResult, *orchestration.WorkflowRun, error) {
+	if input.RunID == "" {
+		return nil, nil, fmt.Errorf("run_id is required")
+	}
+	if err := ValidateWorkspace(input.Workspace); err != nil {
+		return nil, nil, err
+	}
+	if s.orchestrator == nil {
+		return nil, nil, fmt.Errorf("orchestrator not initialized")
+	}
+
+	run, err := s.orchestrator.GetRun(s.resolveWorkspace(req, input.Workspace), input.RunID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(run),
+	}, run, nil
+}
+
+// handleResumeWorkflowRun resumes a failed or partial workflow run
+func (s *UnifiedServer) handleResumeWorkflowRun(ctx context.Context, req *mcp.CallToolRequest, input WorkflowRunRequest) (*mcp.CallToolResult, *ExecuteWorkflowResponse, error) {
+	if input.RunID == "" {
+		return nil, nil, fmt.Errorf("run_id is required")
+	}
+	if err := ValidateWorkspace(input.Workspace); err != nil {
+		return nil, nil, err
+	}
+	if s.orchestrator == nil {
+		return nil, nil, fmt.Errorf("orchestrator not initialized")
+	}
+
+	ctx = orchestration.WithWorkspace(ctx, s.resolveWorkspace(req, input.Workspace))
+	result, err := s.orchestrator.ResumeRun(ctx, input.RunID, input.Input)
+	if result == nil && err != nil {
+		return nil, nil, err
+	}
+	response := workflowResponse(result, err)
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
+// handleRerunWorkflow executes the workflow of a recorded run again
+func (s *UnifiedServer) handleRerunWorkflow(ctx context.Context, req *mcp.CallToolRequest, input WorkflowRunRequest) (*mcp.CallToolResult, *ExecuteWorkflowResponse, error) {
+	if input.RunID == "" {
+		return nil, nil, fmt.Errorf("run_id is required")
+	}
+	if err := ValidateWorkspace(input.Workspace); err != nil {
+		return nil, nil, err
+	}
+	if s.orchestrator == nil {
+		return nil, nil, fmt.Errorf("orchestrator not initialized")
+	}
+
+	ctx = orchestration.WithWorkspace(ctx, s.resolveWorkspace(req, input.Workspace))
+	result, err := s.orchestrator.RerunWorkflow(ctx, input.RunID, input.Input)
+	if result == nil && err != nil {
+		return nil, nil, err
+	}
+	response := workflowResponse(result, err)
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
+
 // handleVerifyThought verifies a thought for hallucinations
 func (s *UnifiedServer) handleVerifyThought(ctx context.Context, req *mcp.CallToolRequest, input handlers.VerifyThoughtRequest) (*mcp.CallToolResult, *handlers.VerifyThoughtResponse, error) {
 	// Validate input
@@ -2514,6 +3076,7 @@ func (s *UnifiedServer) handleDualProcessThink(ctx context.Context, req *mcp.Cal
 
 // handleCreateCheckpoint creates a backtracking checkpoint
 func (s *UnifiedServer) handleCreateCheckpoint(ctx context.Context, req *mcp.CallToolRequest, input handlers.CreateCheckpointRequest) (*mcp.CallToolResult, *handlers.CreateCheckpointResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
 	params := make(map[string]interface{})
 	data, err := json.Marshal(input)
 	if err != nil {
@@ -2542,6 +3105,7 @@ func (s *UnifiedServer) handleCreateCheckpoint(ctx context.Context, req *mcp.Cal
 
 // handleRestoreCheckpoint restores from a checkpoint
 func (s *UnifiedServer) handleRestoreCheckpoint(ctx context.Context, req *mcp.CallToolRequest, input handlers.RestoreCheckpointRequest) (*mcp.CallToolResult, *handlers.RestoreCheckpointResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
 	params := make(map[string]interface{})
 	data, err := json.Marshal(input)
 	if err != nil {
@@ -2570,6 +3134,7 @@ func (s *UnifiedServer) handleRestoreCheckpoint(ctx context.Context, req *mcp.Ca
 
 // handleListCheckpoints lists available checkpoints
 func (s *UnifiedServer) handleListCheckpoints(ctx context.Context, req *mcp.CallToolRequest, input handlers.ListCheckpointsRequest) (*mcp.CallToolResult, *handlers.ListCheckpointsResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
 	params := make(map[string]interface{})
 	data, err := json.Marshal(input)
 	if err != nil {
@@ -2596,6 +3161,93 @@ func (s *UnifiedServer) handleListCheckpoints(ctx context.Context, req *mcp.Call
 	return result, &response, nil
 }
 
+// handleForkCheckpoint forks a new branch from a checkpoint
+func (s *UnifiedServer) handleForkCheckpoint(ctx context.Context, req *mcp.CallToolRequest, input handlers.ForkCheckpointRequest) (*mcp.CallToolResult, *handlers.ForkCheckpointResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
+	params := make(map[string]interface{})
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal input: %w", err)
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	result, err := s.backtrackingHandler.HandleForkCheckpoint(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response handlers.ForkCheckpointResponse
+	if len(result.Content) > 0 {
+		if textContent, ok := result.Content[0].(*mcp.TextContent); ok {
+			if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+				log.Printf("Warning: failed to unmarshal response: %v", err)
+			}
+		}
+	}
+
+	return result, &response, nil
+}
+
+// handleDiffCheckpoints compares two checkpoints
+func (s *UnifiedServer) handleDiffCheckpoints(ctx context.Context, req *mcp.CallToolRequest, input handlers.DiffCheckpointsRequest) (*mcp.CallToolResult, *handlers.DiffCheckpointsResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
+	params := make(map[string]interface{})
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal input: %w", err)
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	result, err := s.backtrackingHandler.HandleDiffCheckpoints(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response handlers.DiffCheckpointsResponse
+	if len(result.Content) > 0 {
+		if textContent, ok := result.Content[0].(*mcp.TextContent); ok {
+			if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+				log.Printf("Warning: failed to unmarshal response: %v", err)
+			}
+		}
+	}
+
+	return result, &response, nil
+}
+
+// handlePruneBranch marks a branch as a dead end
+func (s *UnifiedServer) handlePruneBranch(ctx context.Context, req *mcp.CallToolRequest, input handlers.PruneBranchRequest) (*mcp.CallToolResult, *handlers.PruneBranchResponse, error) {
+	input.Workspace = s.resolveWorkspace(req, input.Workspace)
+	params := make(map[string]interface{})
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal input: %w", err)
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	result, err := s.backtrackingHandler.HandlePruneBranch(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response handlers.PruneBranchResponse
+	if len(result.Content) > 0 {
+		if textContent, ok := result.Content[0].(*mcp.TextContent); ok {
+			if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+				log.Printf("Warning: failed to unmarshal response: %v", err)
+			}
+		}
+	}
+
+	return result, &response, nil
+}
+
 // handleGenerateHypotheses generates abductive hypotheses
 func (s *UnifiedServer) handleGenerateHypotheses(ctx context.Context, req *mcp.CallToolRequest, input handlers.GenerateHypothesesRequest) (*mcp.CallToolResult, *handlers.GenerateHypothesesResponse, error) {
 	params := make(map[string]interface{})
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"unified-thinking/internal/modes"
 	"unified-thinking/internal/orchestration"
 	"unified-thinking/internal/reasoning"
@@ -293,7 +294,7 @@ func TestHandleListBranches(t *testing.T) {
 		server.handleThink(ctx, nil, input)
 	}
 
-	_, resp, err := server.handleListBranches(ctx, nil, EmptyRequest{})
+	_, resp, err := server.handleListBranches(ctx, nil, WorkspaceRequest{})
 	if err != nil {
 		t.Fatalf("handleListBranches() error = %v", err)
 	}
@@ -458,7 +459,7 @@ func TestHandleRecentBranches(t *testing.T) {
 	}
 
 	// Get recent branches
-	_, resp, err := server.handleRecentBranches(ctx, nil, EmptyRequest{})
+	_, resp, err := server.handleRecentBranches(ctx, nil, WorkspaceRequest{})
 	if err != nil {
 		t.Fatalf("handleRecentBranches() error = %v", err)
 	}
@@ -931,3 +932,180 @@ func TestHandleRegisterWorkflow(t *testing.T) {
 		t.Fatal("expected duplicate registration error message")
 	}
 }
+
+func TestHandleWorkflowVersionTools(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(t.TempDir()+"/workflows.db", 5000)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage() error = %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	orch := orchestration.NewOrchestratorWithExecutor(&stubExecutor{})
+	server := &UnifiedServer{}
+	server.SetOrchestrator(orch)
+	ctx := context.Background()
+
+	// Without persistence there are no versions
+	if _, _, err := server.handleListWorkflowVersions(ctx, nil, ListWorkflowVersionsRequest{WorkflowID: "review"}); err == nil {
+		t.Error("expected error without a workflow store")
+	}
+	if err := orch.SetWorkflowStore(store); err != nil {
+		t.Fatalf("SetWorkflowStore() error = %v", err)
+	}
+
+	workflow := func(tools ...string) *orchestration.Workflow {
+		w := &orchestration.Workflow{ID: "review", Name: "Review", Type: orchestration.WorkflowSequential}
+		for _, tool := range tools {
+			w.Steps = append(w.Steps, &orchestration.WorkflowStep{ID: tool, Tool: tool, Input: map[string]interface{}{}})
+		}
+		return w
+	}
+	for i, req := range []RegisterWorkflowRequest{
+		{Workflow: workflow("think")},
+		{Workflow: workflow("think", "validate"), Update: true},
+	} {
+		_, resp, err := server.handleRegisterWorkflow(ctx, nil, req)
+		if err != nil || !resp.Success || resp.Version != i+1 {
+			t.Fatalf("register %d = %+v, err %v", i, resp, err)
+		}
+	}
+
+	_, versions, err := server.handleListWorkflowVersions(ctx, nil, ListWorkflowVersionsRequest{WorkflowID: "review"})
+	if err != nil || versions.Count != 2 || versions.CurrentVersion != 2 {
+		t.Fatalf("versions = %+v, err %v", versions, err)
+	}
+
+	_, diff, err := server.handleDiffWorkflowVersions(ctx, nil, DiffWorkflowVersionsRequest{WorkflowID: "review"})
+	if err != nil || !diff.HasChanges || len(diff.StepsAdded) != 1 || diff.StepsAdded[0] != "validate" {
+		t.Fatalf("diff = %+v, err %v", diff, err)
+	}
+
+	_, deleted, err := server.handleDeleteWorkflow(ctx, nil, DeleteWorkflowRequest{WorkflowID: "review", Version: 2})
+	if err != nil || deleted.VersionsDeleted != 1 || !deleted.Registered || deleted.CurrentVersion != 1 {
+		t.Fatalf("delete v2 = %+v, err %v", deleted, err)
+	}
+	_, deleted, err = server.handleDeleteWorkflow(ctx, nil, DeleteWorkflowRequest{WorkflowID: "review"})
+	if err != nil || deleted.VersionsDeleted != 1 || deleted.Registered {
+		t.Fatalf("delete all = %+v, err %v", deleted, err)
+	}
+	if _, _, err := server.handleDeleteWorkflow(ctx, nil, DeleteWorkflowRequest{}); err == nil {
+		t.Error("expected error for missing workflow_id")
+	}
+}
+
+func TestHandleWorkflowRunTools(t *testing.T) {
+	executor := &stubExecutor{err: fmt.Errorf("unavailable")}
+	orch := orchestration.NewOrchestratorWithExecutor(executor)
+	server := &UnifiedServer{}
+	server.SetOrchestrator(orch)
+	ctx := context.Background()
+
+	workflow := &orchestration.Workflow{ID: "review", Name: "Review", Type: orchestration.WorkflowSequential, Steps: []*orchestration.WorkflowStep{
+		{ID: "think", Tool: "think", Input: map[string]interface{}{"content": "{{problem}}"}},
+	}}
+	if err := orch.RegisterWorkflow(workflow); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	_, executed, err := server.handleExecuteWorkflow(ctx, nil, ExecuteWorkflowRequest{WorkflowID: "review", Input: map[string]interface{}{"problem": "p"}})
+	if err != nil || executed.Status != "failed" || executed.RunID == "" {
+		t.Fatalf("execute = %+v, err %v", executed, err)
+	}
+
+	_, runs, err := server.handleListWorkflowRuns(ctx, nil, ListWorkflowRunsRequest{Status: "failed"})
+	if err != nil || runs.Count != 1 || runs.Runs[0].ID != executed.RunID {
+		t.Fatalf("runs = %+v, err %v", runs, err)
+	}
+	_, run, err := server.handleGetWorkflowRun(ctx, nil, GetWorkflowRunRequest{RunID: executed.RunID})
+	if err != nil || run.Input["problem"] != "p" || len(run.Result.Timeline) != 1 {
+		t.Fatalf("run = %+v, err %v", run, err)
+	}
+
+	executor.err = nil
+	_, resumed, err := server.handleResumeWorkflowRun(ctx, nil, WorkflowRunRequest{RunID: executed.RunID})
+	if err != nil || resumed.Status != "completed" || resumed.RunID == executed.RunID {
+		t.Fatalf("resume = %+v, err %v", resumed, err)
+	}
+	if _, _, err := server.handleResumeWorkflowRun(ctx, nil, WorkflowRunRequest{RunID: resumed.RunID}); err == nil {
+		t.Error("expected error resuming a successful run")
+	}
+
+	_, rerun, err := server.handleRerunWorkflow(ctx, nil, WorkflowRunRequest{RunID: executed.RunID, Input: map[string]interface{}{"problem": "q"}})
+	if err != nil || rerun.Status != "completed" || executor.lastInput["content"] != "q" {
+		t.Fatalf("rerun = %+v, err %v, input %v", rerun, err, executor.lastInput)
+	}
+	if _, _, err := server.handleGetWorkflowRun(ctx, nil, GetWorkflowRunRequest{}); err == nil {
+		t.Error("expected error for missing run_id")
+	}
+}
+
+func TestHandleWorkflowRunTools_Workspaces(t *testing.T) {
+	orch := orchestration.NewOrchestratorWithExecutor(&stubExecutor{err: fmt.Errorf("unavailable")})
+	server := &UnifiedServer{}
+	server.SetOrchestrator(orch)
+	ctx := context.Background()
+
+	workflow := &orchestration.Workflow{ID: "review", Name: "Review", Type: orchestration.WorkflowSequential, Steps: []*orchestration.WorkflowStep{
+		{ID: "think", Tool: "think", Input: map[string]interface{}{"content": "{{problem}}"}},
+	}}
+	if err := orch.RegisterWorkflow(workflow); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	_, executed, err := server.handleExecuteWorkflow(ctx, nil, ExecuteWorkflowRequest{WorkflowID: "review", Input: map[string]interface{}{"problem": "p"}, Workspace: "Alpha"})
+	if err != nil || executed.RunID == "" {
+		t.Fatalf("execute = %+v, err %v", executed, err)
+	}
+
+	_, runs, err := server.handleListWorkflowRuns(ctx, nil, ListWorkflowRunsRequest{})
+	if err != nil || runs.Count != 0 {
+		t.Errorf("default runs = %+v, err %v", runs, err)
+	}
+	_, runs, err = server.handleListWorkflowRuns(ctx, nil, ListWorkflowRunsRequest{Workspace: "alpha"})
+	if err != nil || runs.Count != 1 || runs.Runs[0].Workspace != "alpha" {
+		t.Errorf("alpha runs = %+v, err %v", runs, err)
+	}
+	if _, _, err := server.handleGetWorkflowRun(ctx, nil, GetWorkflowRunRequest{RunID: executed.RunID}); err == nil {
+		t.Error("expected run to be hidden from the default workspace")
+	}
+	if _, _, err := server.handleRerunWorkflow(ctx, nil, WorkflowRunRequest{RunID: executed.RunID, Workspace: "bad name"}); err == nil {
+		t.Error("expected error for invalid workspace")
+	}
+	_, rerun, err := server.handleRerunWorkflow(ctx, nil, WorkflowRunRequest{RunID: executed.RunID, Workspace: "alpha"})
+	if err != nil || rerun.RunID == "" {
+		t.Fatalf("rerun = %+v, err %v", rerun, err)
+	}
+	if _, run, err := server.handleGetWorkflowRun(ctx, nil, GetWorkflowRunRequest{RunID: rerun.RunID, Workspace: "alpha"}); err != nil || run.Workspace != "alpha" {
+		t.Errorf("rerun = %+v, err %v", run, err)
+	}
+}
+
+func TestExecuteWorkflowToolReportsFailedRun(t *testing.T) {
+	orch := orchestration.NewOrchestratorWithExecutor(&stubExecutor{err: fmt.Errorf("unavailable")})
+	workflow := &orchestration.Workflow{ID: "review", Name: "Review", Type: orchestration.WorkflowSequential, Steps: []*orchestration.WorkflowStep{
+		{ID: "think", Tool: "think", Input: map[string]interface{}{"content": "{{problem}}"}},
+	}}
+	if err := orch.RegisterWorkflow(workflow); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	// Register every tool, so a later registration of execute-workflow
+	// would replace the server's handler
+	server := &UnifiedServer{storage: storage.NewMemoryStorage(), dispatcher: handlers.NewDispatcher(), agentTools: modes.NewToolRegistry()}
+	server.SetOrchestrator(orch)
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	server.RegisterTools(mcpServer)
+	session := connectTestClient(t, mcpServer)
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "execute-workflow",
+		Arguments: map[string]any{"workflow_id": "review", "input": map[string]any{"problem": "p"}},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("execute-workflow: %v %+v", err, result)
+	}
+	response := result.StructuredContent.(map[string]any)
+	if response["status"] != "failed" || response["run_id"] == "" || response["run_id"] == nil {
+		t.Errorf("response = %v, want a failed run with its run_id", response)
+	}
+}
@@ -2,36 +2,43 @@ package server
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
+	"strings"
+
 	"unified-thinking/internal/modes"
 )
 
-// populateToolRegistry registers safe tools for agentic use
+// populateToolRegistry registers every dispatchable tool for agentic use,
+// except those in modes.ExcludedTools. Descriptions are cut to their first
+// paragraph to keep the agent's tool definitions short.
 func (s *UnifiedServer) populateToolRegistry(registry *modes.ToolRegistry) {
-	// Register 30+ safe read-only tools
-	safeTools := []string{
-		"think", "validate", "search", "history", "decompose-problem",
-		"make-decision", "analyze-perspectives", "analyze-temporal",
-		"build-causal-graph", "generate-hypotheses", "evaluate-hypotheses",
-		"detect-biases", "detect-fallacies", "detect-blind-spots",
-		"self-evaluate", "assess-evidence", "detect-contradictions",
-		"sensitivity-analysis", "prove", "check-syntax",
-		"find-analogy", "apply-analogy", "decompose-argument",
-		"search-similar-thoughts", "search-knowledge-graph",
-		"probabilistic-reasoning", "got-initialize", "got-generate",
-		"got-score", "got-aggregate", "synthesize-insights",
+	if registry == nil || s.dispatcher == nil {
+		return
+	}
+
+	excluded := make(map[string]bool, len(modes.ExcludedTools))
+	for _, name := range modes.ExcludedTools {
+		excluded[name] = true
 	}
 
-	for _, name := range safeTools {
+	for _, tool := range s.dispatcher.Tools() {
+		if excluded[tool.Name] {
+			continue
+		}
+
+		var schema map[string]interface{}
+		if data, err := json.Marshal(tool.InputSchema); err == nil {
+			_ = json.Unmarshal(data, &schema)
+		}
+		description, _, _ := strings.Cut(tool.Description, "\n\n")
+
+		name := tool.Name
 		_ = registry.Register(modes.ToolSpec{
 			Name:        name,
-			Description: fmt.Sprintf("Safe tool: %s", name),
-			InputSchema: map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
-			},
+			Description: strings.TrimSpace(description),
+			InputSchema: schema,
 			Handler: func(ctx context.Context, input map[string]interface{}) (interface{}, error) {
-				return map[string]interface{}{"status": "stub"}, nil
+				return s.dispatcher.Call(ctx, name, input)
 			},
 		})
 	}
@@ -17,6 +17,7 @@ var ToolDefinitions = []mcp.Tool{
 - confidence: 0.0-1.0 (default: 0.8)
 - key_points: Array of key observations
 - branch_id: For tree mode continuation
+- workspace: Project namespace (default: session workspace)
 - format_level: Response size control - "full" (default), "compact" (40-60% smaller), "minimal" (80% smaller)
 
 **Returns:** thought_id, mode, confidence, metadata with:
@@ -41,14 +42,16 @@ var ToolDefinitions = []mcp.Tool{
 	},
 	{
 		Name:        "history",
-		Description: "View thinking history",
+		Description: "View thinking history of the current workspace",
 	},
 	{
 		Name:        "list-branches",
-		Description: "List all thinking branches",
+		Description: "List all thinking branches in the current workspace",
 		InputSchema: map[string]interface{}{
-			"type":       "object",
-			"properties": map[string]interface{}{},
+			"type": "object",
+			"properties": map[string]interface{}{
+				"workspace": workspaceSchemaProperty,
+			},
 		},
 	},
 	{
@@ -63,10 +66,16 @@ var ToolDefinitions = []mcp.Tool{
 		Name:        "recent-branches",
 		Description: "Get recently accessed branches for quick context switching",
 		InputSchema: map[string]interface{}{
-			"type":       "object",
-			"properties": map[string]interface{}{},
+			"type": "object",
+			"properties": map[string]interface{}{
+				"workspace": workspaceSchemaProperty,
+			},
 		},
 	},
+	{
+		Name:        "set-workspace",
+		Description: "Select the workspace (project namespace) used by the current MCP session",
+	},
 
 	// Validation Tools
 	{
@@ -75,17 +84,17 @@ var ToolDefinitions = []mcp.Tool{
 	},
 	{
 		Name:        "prove",
-		Description: "Attempt to prove a logical conclusion from premises",
+		Description: "Attempt to prove a logical conclusion from premises; returns a resolution proof or a counter-model for propositional arguments, and a first-order resolution proof for quantified ones",
 	},
 	{
 		Name:        "check-syntax",
-		Description: "Validate syntax of logical statements",
+		Description: "Validate syntax of logical statements, reporting the position of each syntax error",
 	},
 
 	// Search and Metadata Tools
 	{
 		Name:        "search",
-		Description: "Search through all thoughts",
+		Description: "Search through all thoughts in the current workspace",
 	},
 	{
 		Name:        "get-metrics",
@@ -134,7 +143,11 @@ var ToolDefinitions = []mcp.Tool{
 	// Probabilistic Reasoning Tools
 	{
 		Name:        "probabilistic-reasoning",
-		Description: "Perform Bayesian inference and update probabilistic beliefs based on evidence. Required: operation (\"create\", \"update\", \"get\", or \"combine\"). For create: statement, prior_prob (0-1). For update: belief_id, evidence_id, likelihood (0-1), evidence_prob (0-1). For get: belief_id. For combine: belief_ids (array), combine_op (\"and\" or \"or\"). Example: {\"operation\": \"create\", \"statement\": \"X is true\", \"prior_prob\": 0.5}",
+		Description: "Perform Bayesian inference and update probabilistic beliefs based on evidence. Required: operation (\"create\", \"update\", \"get\", or \"combine\"). For create: statement, prior_prob (0-1). For update: belief_id, evidence_id, likelihood (0-1), evidence_prob (0-1). For get: belief_id. For combine: belief_ids (array), combine_op (\"and\" or \"or\"). Optional: workspace holding the beliefs (default: session workspace). Example: {\"operation\": \"create\", \"statement\": \"X is true\", \"prior_prob\": 0.5}",
+	},
+	{
+		Name:        "list-beliefs",
+		Description: "List the workspace's probabilistic beliefs, most recently updated first, including beliefs from earlier sessions. Optional: workspace (default: session workspace), limit (default 50), offset. Use probabilistic-reasoning with operation \"get\" or \"update\" to work with a listed belief.",
 	},
 	{
 		Name:        "assess-evidence",
@@ -158,6 +171,7 @@ var ToolDefinitions = []mcp.Tool{
 - question (required): Decision question
 - options (required): Array of options with id, name, description, scores, pros, cons
 - criteria (required): Array of criteria with id, name, weight, maximize flag
+- workspace (optional): Workspace to store the decision in (default: session workspace)
 
 **Returns:** decision with recommendation, confidence, and metadata with:
 - export_formats.obsidian_note: Complete decision document in markdown
@@ -179,12 +193,21 @@ var ToolDefinitions = []mcp.Tool{
 
 **Example:** {"question": "Which database?", "options": [{"id": "pg", "name": "PostgreSQL", "scores": {"cost": 0.8}}], "criteria": [{"id": "cost", "weight": 0.5}]}`,
 	},
+	{
+		Name:        "list-decisions",
+		Description: "List the workspace's decisions created by make-decision, newest first, including decisions from earlier sessions. Optional: workspace (default: session workspace), limit (default 50), offset.",
+	},
+	{
+		Name:        "get-decision",
+		Description: "Retrieve a previously created decision of the workspace by ID. Required: decision_id. Optional: workspace (default: session workspace).",
+	},
 	{
 		Name: "decompose-problem",
 		Description: `Break down complex problems into manageable subproblems with dependencies.
 
 **Parameters:**
 - problem (required): Complex problem statement
+- workspace (optional): Workspace to store the decomposition in (default: session workspace)
 
 **Returns:** decomposition with subproblems, dependencies, solution_path, and metadata with:
 - suggested_next_tools: brave-search, obsidian:search-notes, think
@@ -204,6 +227,14 @@ var ToolDefinitions = []mcp.Tool{
 
 **Example:** {"problem": "How to improve CI/CD pipeline performance?"}`,
 	},
+	{
+		Name:        "list-decompositions",
+		Description: "List the workspace's problem decompositions created by decompose-problem, newest first, including decompositions from earlier sessions. Optional: workspace (default: session workspace), limit (default 50), offset.",
+	},
+	{
+		Name:        "get-decomposition",
+		Description: "Retrieve a previously created problem decomposition of the workspace by ID. Required: decomposition_id. Optional: workspace (default: session workspace).",
+	},
 
 	// Metacognition Tools
 	{
@@ -402,6 +433,7 @@ var ToolDefinitions = []mcp.Tool{
 **Parameters:**
 - description (required): Context for the causal model
 - observations (required): Array of causal statements
+- workspace (optional): Workspace to store the graph in (default: session workspace)
 
 **Returns:** graph with variables, links, and metadata with:
 - suggested_next_tools: memory:create_entities, simulate-intervention
@@ -423,11 +455,11 @@ var ToolDefinitions = []mcp.Tool{
 	},
 	{
 		Name:        "simulate-intervention",
-		Description: "Simulate the effects of intervening on a variable in a causal graph",
+		Description: "Simulate the effects of intervening on a variable in a causal graph of the workspace (workspace parameter, default: session workspace)",
 	},
 	{
 		Name:        "generate-counterfactual",
-		Description: "Generate a counterfactual scenario ('what if') by changing variables in a causal model",
+		Description: "Generate a counterfactual scenario ('what if') by changing variables in a causal model of the workspace (workspace parameter, default: session workspace)",
 	},
 	{
 		Name:        "analyze-correlation-vs-causation",
@@ -435,7 +467,11 @@ var ToolDefinitions = []mcp.Tool{
 	},
 	{
 		Name:        "get-causal-graph",
-		Description: "Retrieve a previously built causal graph by ID",
+		Description: "Retrieve a previously built causal graph of the workspace by ID, optionally rendered as Graphviz DOT, Mermaid or GraphML (format parameter). Optional: workspace (default: session workspace)",
+	},
+	{
+		Name:        "list-causal-graphs",
+		Description: "List the workspace's stored causal graphs, newest first, including graphs built in earlier sessions. Optional: workspace (default: session workspace), limit, offset",
 	},
 
 	// Integration & Synthesis Tools
@@ -475,15 +511,27 @@ var ToolDefinitions = []mcp.Tool{
 	// Backtracking Tools
 	{
 		Name:        "create-checkpoint",
-		Description: "Create a backtracking checkpoint in tree mode. Save current branch state for later restoration. Parameters: branch_id (required), name (required), description. Returns: checkpoint_id, thought_count, insight_count, created_at",
+		Description: "Create a backtracking checkpoint in tree mode. Save current branch state for later restoration. Parameters: branch_id (required), name (required), description, workspace (default: session workspace). Returns: checkpoint_id, thought_count, insight_count, created_at",
 	},
 	{
 		Name:        "restore-checkpoint",
-		Description: "Restore branch from a checkpoint. Enables backtracking in tree exploration. Parameters: checkpoint_id (required). Returns: branch_id, thought_count, insight_count, message",
+		Description: "Restore branch from a checkpoint. Enables backtracking in tree exploration. Parameters: checkpoint_id (required), workspace (default: session workspace). Returns: branch_id, thought_count, insight_count, message",
 	},
 	{
 		Name:        "list-checkpoints",
-		Description: "List available checkpoints for backtracking. Parameters: branch_id (optional - filter by branch). Returns: array of checkpoints with id, name, description, branch_id, thought_count, created_at",
+		Description: "List available checkpoints for backtracking. Parameters: branch_id (optional - filter by branch), workspace (default: session workspace). Returns: array of checkpoints with id, name, description, branch_id, thought_count, created_at",
+	},
+	{
+		Name:        "fork-checkpoint",
+		Description: "Fork a new branch from a checkpoint without modifying the original branch. Use it to try alternative approaches from the same savepoint. Parameters: checkpoint_id (required), name (optional label for the fork), workspace (default: session workspace). Returns: branch_id, parent_branch_id, thought_count, insight_count",
+	},
+	{
+		Name:        "diff-checkpoints",
+		Description: "Compare two checkpoints, including checkpoints on a branch and its forks. Parameters: from_checkpoint_id (required), to_checkpoint_id (required), workspace (default: session workspace). Returns: thoughts_added, thoughts_removed, thoughts_modified, insights_added, insights_removed, has_changes",
+	},
+	{
+		Name:        "prune-branch",
+		Description: "Mark a branch as a dead end after a failed exploration. Parameters: branch_id (required), reason, workspace (default: session workspace). Returns: branch_id, state, reason",
 	},
 
 	// Abductive Reasoning Tools
@@ -509,11 +557,11 @@ var ToolDefinitions = []mcp.Tool{
 	// Symbolic Reasoning Tools
 	{
 		Name:        "prove-theorem",
-		Description: "Attempt to prove a theorem using symbolic reasoning and logical inference rules (modus ponens, simplification, conjunction). Parameters: name, premises (array), conclusion. Returns: status, is_valid, confidence, proof with steps (step_number, statement, justification, rule, dependencies)",
+		Description: "Attempt to prove a theorem. Single-step rules (modus ponens, simplification, conjunction) are tried first; otherwise premises and conclusion are formalized in first-order logic (predicates P(x), ∀/∃ or \"for all x\", and English such as \"all services that call auth must use TLS\") and the conclusion is proved by skolemization and resolution with unification. Parameters: name, premises (array), conclusion, max_depth and timeout_ms (optional search bounds); or format (tptp for FOF/CNF, smtlib for SMT-LIB v2 UF) and problem instead of premises and conclusion; export (tptp or smtlib). Returns: status (proven, unproven, or undecidable when the search hits a bound), is_valid, confidence, proof with numbered steps (step_number, statement, justification, rule, dependencies), premises, conclusion and expected_status for an imported problem, exported_problem and exported_proof (TSTP derivation or SMT-LIB step checks)",
 	},
 	{
 		Name:        "check-constraints",
-		Description: "Check consistency of symbolic constraints. Detect conflicts and contradictions. Parameters: symbols (array of {name, type, domain}), constraints (array of {type, expression, symbols}). Returns: is_consistent, conflicts (array), explanation",
+		Description: "Check consistency of symbolic constraints. Linear comparisons over integer and real symbols (e.g. \"x + 2y <= 10\", \"0 <= x < y\", \"x in [1, 5]\") are decided together by bounds propagation and Fourier–Motzkin elimination, with branch and bound for integers; other constraints are compared pairwise. Parameters: symbols (array of {name, type, domain}), constraints (array of {type, expression, symbols}); or format (smtlib for QF_LIA/QF_LRA/QF_LIRA, tptp for TFF arithmetic) and problem instead of symbols and constraints; export (tptp or smtlib). Returns: is_consistent, conflicts (array), explanation, witness (satisfying assignment when consistent), unsat_core (minimal set of conflicting constraint IDs), unchecked, names and expected_status for an imported problem, exported_problem",
 	},
 
 	// Enhanced Tools
@@ -535,7 +583,15 @@ var ToolDefinitions = []mcp.Tool{
 	},
 	{
 		Name:        "detect-fallacies",
-		Description: "Detect formal and informal logical fallacies in reasoning. Required: content (string). Optional: check_formal (bool), check_informal (bool). Detects ad hominem, strawman, false dichotomy, etc. NOTE: For cognitive biases (confirmation bias, anchoring, etc.), use detect-biases instead. Example: {\"content\": \"Everyone says X is true, so it must be\", \"check_formal\": true, \"check_informal\": true}",
+		Description: "Detect formal and informal logical fallacies in reasoning using the fallacy rules enabled in the workspace (see list-fallacy-rules). Required: content (string). Optional: check_formal (bool), check_informal (bool, includes statistical and custom rule categories), workspace (default: session workspace). Detects ad hominem, strawman, false dichotomy, etc. NOTE: For cognitive biases (confirmation bias, anchoring, etc.), use detect-biases instead. Example: {\"content\": \"Everyone says X is true, so it must be\", \"check_formal\": true, \"check_informal\": true}",
+	},
+	{
+		Name:        "list-fallacy-rules",
+		Description: "List the fallacy rules used by detect-fallacies: the built-in core pack plus rule packs loaded from FALLACY_RULES_DIR. Optional: workspace (default: session workspace), category, pack, enabled_only (bool). Returns each rule's id, pack, category, severity, confidence, whether it is enabled in the workspace, and stats: precision and recall on the rule's labelled examples (with missed examples and false alarms) and detections since startup. Example: {\"pack\": \"core\", \"category\": \"statistical\"}",
+	},
+	{
+		Name:        "configure-fallacy-rules",
+		Description: "Enable or disable fallacy rules for a workspace. Optional: workspace (default: session workspace), enable (array of rule IDs), disable (array of rule IDs), reset (bool, drop earlier settings first). Settings last until the server restarts; rule packs set the defaults. Returns the enabled and disabled rule IDs. Example: {\"workspace\": \"perf-reviews\", \"enable\": [\"benchmark_cherry_picking\"], \"disable\": [\"appeal_to_emotion\"]}",
 	},
 	{
 		Name:        "process-evidence-pipeline",
@@ -0,0 +1,102 @@
+// Package server - Token usage attribution and budget enforcement for tool calls
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ccerrors "unified-thinking/internal/claudecode/errors"
+	"unified-thinking/internal/usage"
+)
+
+// usageMiddleware attributes the API usage of each tool call to the tool and
+// its episodic session. The session is the call's session_id argument, or the
+// reasoning session started from the same MCP session. When a call hits the
+// usage budget, its result is replaced by the structured budget error, since
+// handlers may wrap or swallow the client error.
+func (s *UnifiedServer) usageMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		callReq, ok := req.(*mcp.CallToolRequest)
+		if !ok || method != "tools/call" || callReq.Params == nil {
+			return next(ctx, method, req)
+		}
+
+		tool := callReq.Params.Name
+		sessionID := sessionIDArgument(callReq.Params.Arguments)
+		if sessionID == "" {
+			sessionID = s.boundUsageSession(callReq.Session)
+		}
+
+		ctx, call := usage.WithCall(ctx, tool, sessionID)
+		result, err := next(ctx, method, req)
+
+		if budgetErr := call.BudgetError(); budgetErr != nil {
+			return budgetErrorResult(budgetErr), nil
+		}
+		if toolResult, ok := result.(*mcp.CallToolResult); ok && err == nil && !toolResult.IsError {
+			s.trackUsageSession(callReq, tool, sessionID)
+		}
+		return result, err
+	}
+}
+
+// sessionIDArgument returns the session_id argument of a tool call, if any
+func sessionIDArgument(arguments json.RawMessage) string {
+	var args struct {
+		SessionID string `json:"session_id"`
+	}
+	if len(arguments) == 0 || json.Unmarshal(arguments, &args) != nil {
+		return ""
+	}
+	return args.SessionID
+}
+
+// boundUsageSession returns the episodic session bound to an MCP session.
+// Sessions are keyed by connection rather than ID, since stdio sessions
+// have no ID.
+func (s *UnifiedServer) boundUsageSession(session *mcp.ServerSession) string {
+	if session == nil {
+		return ""
+	}
+	s.usageMu.RLock()
+	defer s.usageMu.RUnlock()
+	return s.usageSessions[session]
+}
+
+// trackUsageSession binds an MCP session to the reasoning session it started,
+// so later tool calls without a session_id are attributed to it, and unbinds
+// it when the session completes or the client disconnects
+func (s *UnifiedServer) trackUsageSession(req *mcp.CallToolRequest, tool, sessionID string) {
+	if req.Session == nil || sessionID == "" {
+		return
+	}
+
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	switch tool {
+	case "start-reasoning-session":
+		if s.usageSessions == nil {
+			s.usageSessions = make(map[*mcp.ServerSession]string)
+		}
+		if _, known := s.usageSessions[req.Session]; !known {
+			s.releaseOnClose(req.Session)
+		}
+		s.usageSessions[req.Session] = sessionID
+	case "complete-reasoning-session":
+		if s.usageSessions[req.Session] == sessionID {
+			delete(s.usageSessions, req.Session)
+		}
+	}
+}
+
+// budgetErrorResult reports a budget error as a tool error whose content is
+// the JSON encoded structured error
+func budgetErrorResult(err *ccerrors.StructuredError) *mcp.CallToolResult {
+	result := &mcp.CallToolResult{}
+	if data, marshalErr := json.Marshal(err); marshalErr == nil {
+		result.Content = []mcp.Content{&mcp.TextContent{Text: string(data)}}
+	}
+	result.SetError(err)
+	return result
+}
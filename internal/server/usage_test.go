@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ccerrors "unified-thinking/internal/claudecode/errors"
+	"unified-thinking/internal/usage"
+)
+
+type usageTestInput struct {
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// connectUsageServer serves tools that spend tokens through the usage
+// middleware and returns a connected client session
+func connectUsageServer(t *testing.T, s *UnifiedServer) *mcp.ClientSession {
+	t.Helper()
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	mcpServer.AddReceivingMiddleware(s.usageMiddleware)
+
+	spend := func(ctx context.Context, req *mcp.CallToolRequest, in usageTestInput) (*mcp.CallToolResult, any, error) {
+		if err := s.usage.Check(ctx); err != nil {
+			// Handlers often wrap client errors
+			return nil, nil, fmt.Errorf("generation failed: %v", err)
+		}
+		s.usage.Record(ctx, "claude-sonnet-4-5", 60, 40)
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil, nil
+	}
+	for _, name := range []string{"spend", "start-reasoning-session", "complete-reasoning-session"} {
+		mcp.AddTool(mcpServer, &mcp.Tool{Name: name}, spend)
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	serverSession, err := mcpServer.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func callUsageTool(t *testing.T, session *mcp.ClientSession, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool(%s): %v", name, err)
+	}
+	return result
+}
+
+func TestUsageMiddleware_AttributesToolAndSession(t *testing.T) {
+	s := &UnifiedServer{usage: usage.NewTracker(usage.Budget{})}
+	session := connectUsageServer(t, s)
+
+	callUsageTool(t, session, "spend", nil)
+	callUsageTool(t, session, "start-reasoning-session", map[string]any{"session_id": "debug-1"})
+	callUsageTool(t, session, "spend", nil)
+	callUsageTool(t, session, "complete-reasoning-session", map[string]any{"session_id": "debug-1"})
+	callUsageTool(t, session, "spend", nil)
+
+	summary := s.usage.Summary()
+	if got := summary.ByTool["spend"]; got.Calls != 3 || got.TotalTokens != 300 {
+		t.Errorf("by_tool[spend] = %+v", got)
+	}
+	// The start call, the call in between and the complete call
+	if got := summary.BySession["debug-1"]; got.Calls != 3 {
+		t.Errorf("by_session[debug-1] = %+v, want 3 calls", got)
+	}
+	if got := summary.ByModel["claude-sonnet-4-5"]; got.CostUSD <= 0 {
+		t.Errorf("by_model = %+v, want a cost", summary.ByModel)
+	}
+}
+
+func TestUsageMiddleware_ReleasesSessionOnDisconnect(t *testing.T) {
+	s := &UnifiedServer{usage: usage.NewTracker(usage.Budget{})}
+	session := connectUsageServer(t, s)
+
+	callUsageTool(t, session, "start-reasoning-session", map[string]any{"session_id": "debug-1"})
+	s.usageMu.RLock()
+	bound := len(s.usageSessions)
+	s.usageMu.RUnlock()
+	if bound != 1 {
+		t.Fatalf("bound sessions = %d, want 1", bound)
+	}
+
+	_ = session.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.usageMu.RLock()
+		bound = len(s.usageSessions)
+		s.usageMu.RUnlock()
+		if bound == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d usage sessions left after disconnect", bound)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUsageMiddleware_BudgetExhausted(t *testing.T) {
+	s := &UnifiedServer{usage: usage.NewTracker(usage.Budget{MaxSessionTokens: 100})}
+	session := connectUsageServer(t, s)
+
+	if result := callUsageTool(t, session, "spend", map[string]any{"session_id": "s1"}); result.IsError {
+		t.Fatalf("first call failed: %+v", result)
+	}
+
+	result := callUsageTool(t, session, "spend", map[string]any{"session_id": "s1"})
+	if !result.IsError || len(result.Content) != 1 {
+		t.Fatalf("result = %+v, want a tool error", result)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	var structured ccerrors.StructuredError
+	if err := json.Unmarshal([]byte(text), &structured); err != nil {
+		t.Fatalf("content is not a structured error: %q", text)
+	}
+	if structured.Code != ccerrors.ErrBudgetExhausted || !strings.Contains(structured.Details, "s1") {
+		t.Errorf("structured error = %+v", structured)
+	}
+
+	// Other sessions keep their own budget
+	if result := callUsageTool(t, session, "spend", map[string]any{"session_id": "s2"}); result.IsError {
+		t.Errorf("other session rejected: %+v", result)
+	}
+}
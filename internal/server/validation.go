@@ -2,7 +2,10 @@ package server
 
 import (
 	"fmt"
+	"strings"
 	"unicode/utf8"
+
+	"unified-thinking/internal/storage"
 )
 
 // Input validation limits to protect against resource exhaustion and
@@ -244,7 +247,7 @@ func ValidateThinkRequest(req *ThinkRequest) error {
 		}
 	}
 
-	return nil
+	return ValidateWorkspace(req.Workspace)
 }
 
 // ValidateHistoryRequest validates a HistoryRequest
@@ -262,7 +265,7 @@ func ValidateHistoryRequest(req *HistoryRequest) error {
 		return &ValidationError{"branch_id", "branch_id too long"}
 	}
 
-	return nil
+	return ValidateWorkspace(req.Workspace)
 }
 
 // ValidateFocusBranchRequest validates a FocusBranchRequest
@@ -273,7 +276,7 @@ func ValidateFocusBranchRequest(req *FocusBranchRequest) error {
 	if len(req.BranchID) > MaxBranchIDLength {
 		return &ValidationError{"branch_id", "branch_id too long"}
 	}
-	return nil
+	return ValidateWorkspace(req.Workspace)
 }
 
 // ValidateBranchHistoryRequest validates a BranchHistoryRequest
@@ -284,7 +287,7 @@ func ValidateBranchHistoryRequest(req *BranchHistoryRequest) error {
 	if len(req.BranchID) > MaxBranchIDLength {
 		return &ValidationError{"branch_id", "branch_id too long"}
 	}
-	return nil
+	return ValidateWorkspace(req.Workspace)
 }
 
 // ValidateValidateRequest validates a ValidateRequest
@@ -295,7 +298,7 @@ func ValidateValidateRequest(req *ValidateRequest) error {
 	if len(req.ThoughtID) > MaxBranchIDLength {
 		return &ValidationError{"thought_id", "thought_id too long"}
 	}
-	return nil
+	return ValidateWorkspace(req.Workspace)
 }
 
 // ValidateProveRequest validates a ProveRequest
@@ -367,7 +370,7 @@ func ValidateSearchRequest(req *SearchRequest) error {
 		}
 	}
 
-	return nil
+	return ValidateWorkspace(req.Workspace)
 }
 
 // ValidateExecuteWorkflowRequest validates an ExecuteWorkflowRequest
@@ -392,7 +395,7 @@ func ValidateExecuteWorkflowRequest(req *ExecuteWorkflowRequest) error {
 		}
 	}
 
-	return nil
+	return ValidateWorkspace(req.Workspace)
 }
 
 // ValidateRegisterWorkflowRequest validates a RegisterWorkflowRequest
@@ -414,9 +417,9 @@ func ValidateRegisterWorkflowRequest(req *RegisterWorkflowRequest) error {
 	}
 
 	// Validate workflow type
-	validTypes := map[string]bool{"sequential": true, "parallel": true, "conditional": true}
+	validTypes := map[string]bool{"sequential": true, "parallel": true, "conditional": true, "dag": true}
 	if !validTypes[string(req.Workflow.Type)] {
-		return &ValidationError{"workflow.type", "workflow type must be 'sequential', 'parallel', or 'conditional'"}
+		return &ValidationError{"workflow.type", "workflow type must be 'sequential', 'parallel', 'conditional', or 'dag'"}
 	}
 
 	// Validate each step
@@ -434,3 +437,24 @@ func ValidateRegisterWorkflowRequest(req *RegisterWorkflowRequest) error {
 
 	return nil
 }
+
+// ValidateWorkspace validates an optional workspace identifier
+func ValidateWorkspace(workspace string) error {
+	if err := storage.ValidateWorkspace(workspace); err != nil {
+		return &ValidationError{"workspace", err.Error()}
+	}
+	return nil
+}
+
+// ValidateWorkspaceRequest validates a WorkspaceRequest
+func ValidateWorkspaceRequest(req *WorkspaceRequest) error {
+	return ValidateWorkspace(req.Workspace)
+}
+
+// ValidateSetWorkspaceRequest validates a SetWorkspaceRequest
+func ValidateSetWorkspaceRequest(req *SetWorkspaceRequest) error {
+	if strings.TrimSpace(req.Workspace) == "" {
+		return &ValidationError{"workspace", "workspace is required"}
+	}
+	return ValidateWorkspace(req.Workspace)
+}
@@ -0,0 +1,118 @@
+// Package server - Workspace selection for MCP sessions and tool calls
+package server
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/storage"
+)
+
+// WorkspaceRequest is used by tools whose only input is an optional workspace
+type WorkspaceRequest struct {
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// SetWorkspaceRequest selects the default workspace for the calling session
+type SetWorkspaceRequest struct {
+	Workspace string `json:"workspace"`
+}
+
+// SetWorkspaceResponse reports the session's workspace selection
+type SetWorkspaceResponse struct {
+	Status            string `json:"status"`
+	Workspace         string `json:"workspace"`
+	PreviousWorkspace string `json:"previous_workspace"`
+	SessionScoped     bool   `json:"session_scoped"`
+}
+
+// workspaceSchemaProperty is the JSON schema fragment for the optional
+// workspace parameter shared by workspace-aware tools
+var workspaceSchemaProperty = map[string]any{
+	"type":        "string",
+	"description": "Workspace (project namespace) to use. Defaults to the session workspace set via set-workspace, or \"default\".",
+}
+
+// resolveWorkspace picks the workspace for a tool call: an explicit workspace
+// wins, then the workspace selected for the MCP session, then the default
+func (s *UnifiedServer) resolveWorkspace(req *mcp.CallToolRequest, workspace string) string {
+	if workspace != "" {
+		return storage.NormalizeWorkspace(workspace)
+	}
+
+	if session := sessionOf(req); session != nil {
+		s.workspaceMu.RLock()
+		selected, ok := s.sessionWorkspaces[session]
+		s.workspaceMu.RUnlock()
+		if ok {
+			return selected
+		}
+	}
+
+	return storage.DefaultWorkspace
+}
+
+// storageFor returns the storage view for a resolved workspace
+func (s *UnifiedServer) storageFor(workspace string) storage.Storage {
+	return storage.ForWorkspace(s.storage, workspace)
+}
+
+// sessionOf returns the MCP session of a request, if any. Direct handler
+// calls have no session. Sessions are keyed by connection rather than ID,
+// since stdio sessions have no ID.
+func sessionOf(req *mcp.CallToolRequest) *mcp.ServerSession {
+	if req == nil {
+		return nil
+	}
+	return req.Session
+}
+
+// releaseOnClose forgets the state kept for an MCP session once its client
+// disconnects
+func (s *UnifiedServer) releaseOnClose(session *mcp.ServerSession) {
+	go func() {
+		_ = session.Wait()
+
+		s.workspaceMu.Lock()
+		delete(s.sessionWorkspaces, session)
+		s.workspaceMu.Unlock()
+
+		s.usageMu.Lock()
+		delete(s.usageSessions, session)
+		s.usageMu.Unlock()
+	}()
+}
+
+func (s *UnifiedServer) handleSetWorkspace(ctx context.Context, req *mcp.CallToolRequest, input SetWorkspaceRequest) (*mcp.CallToolResult, *SetWorkspaceResponse, error) {
+	if err := ValidateSetWorkspaceRequest(&input); err != nil {
+		return nil, nil, err
+	}
+
+	workspace := storage.NormalizeWorkspace(input.Workspace)
+	previous := s.resolveWorkspace(req, "")
+	session := sessionOf(req)
+
+	if session != nil {
+		s.workspaceMu.Lock()
+		if s.sessionWorkspaces == nil {
+			s.sessionWorkspaces = make(map[*mcp.ServerSession]string)
+		}
+		_, known := s.sessionWorkspaces[session]
+		s.sessionWorkspaces[session] = workspace
+		s.workspaceMu.Unlock()
+		if !known {
+			s.releaseOnClose(session)
+		}
+	}
+
+	response := &SetWorkspaceResponse{
+		Status:            "success",
+		Workspace:         workspace,
+		PreviousWorkspace: previous,
+		SessionScoped:     session != nil,
+	}
+
+	return &mcp.CallToolResult{
+		Content: toJSONContent(response),
+	}, response, nil
+}
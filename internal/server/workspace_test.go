@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"unified-thinking/internal/storage"
+	"unified-thinking/internal/types"
+)
+
+func TestResolveWorkspace(t *testing.T) {
+	s := &UnifiedServer{storage: storage.NewMemoryStorage()}
+
+	if got := s.resolveWorkspace(nil, ""); got != storage.DefaultWorkspace {
+		t.Errorf("resolveWorkspace(nil, \"\") = %q, want %q", got, storage.DefaultWorkspace)
+	}
+	if got := s.resolveWorkspace(nil, " Billing "); got != "billing" {
+		t.Errorf("resolveWorkspace(nil, \" Billing \") = %q, want billing", got)
+	}
+}
+
+func TestHandleSetWorkspace_Validation(t *testing.T) {
+	s := &UnifiedServer{storage: storage.NewMemoryStorage()}
+	ctx := context.Background()
+
+	if _, _, err := s.handleSetWorkspace(ctx, nil, SetWorkspaceRequest{}); err == nil {
+		t.Error("expected error for empty workspace")
+	}
+	if _, _, err := s.handleSetWorkspace(ctx, nil, SetWorkspaceRequest{Workspace: "bad name"}); err == nil {
+		t.Error("expected error for invalid workspace")
+	}
+
+	_, resp, err := s.handleSetWorkspace(ctx, nil, SetWorkspaceRequest{Workspace: "Billing"})
+	if err != nil {
+		t.Fatalf("handleSetWorkspace() error = %v", err)
+	}
+	if resp.Workspace != "billing" {
+		t.Errorf("Workspace = %q, want billing", resp.Workspace)
+	}
+	if resp.SessionScoped {
+		t.Error("SessionScoped should be false without an MCP session")
+	}
+}
+
+// connectTestClient connects a new client session to mcpServer
+func connectTestClient(t *testing.T, mcpServer *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	serverSession, err := mcpServer.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func TestSetWorkspace_PerSession(t *testing.T) {
+	s := &UnifiedServer{storage: storage.NewMemoryStorage()}
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	mcp.AddTool(mcpServer, &mcp.Tool{Name: "set-workspace"}, s.handleSetWorkspace)
+	mcp.AddTool(mcpServer, &mcp.Tool{Name: "current-workspace"},
+		func(ctx context.Context, req *mcp.CallToolRequest, input WorkspaceRequest) (*mcp.CallToolResult, *WorkspaceRequest, error) {
+			return nil, &WorkspaceRequest{Workspace: s.resolveWorkspace(req, input.Workspace)}, nil
+		})
+
+	current := func(session *mcp.ClientSession) string {
+		t.Helper()
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "current-workspace"})
+		if err != nil || result.IsError {
+			t.Fatalf("current-workspace: %v %+v", err, result)
+		}
+		return result.StructuredContent.(map[string]any)["workspace"].(string)
+	}
+
+	first := connectTestClient(t, mcpServer)
+	second := connectTestClient(t, mcpServer)
+
+	result, err := first.CallTool(context.Background(), &mcp.CallToolParams{Name: "set-workspace", Arguments: map[string]any{"workspace": "Billing"}})
+	if err != nil || result.IsError {
+		t.Fatalf("set-workspace: %v %+v", err, result)
+	}
+	if scoped := result.StructuredContent.(map[string]any)["session_scoped"]; scoped != true {
+		t.Errorf("session_scoped = %v, want true", scoped)
+	}
+
+	if got := current(first); got != "billing" {
+		t.Errorf("first session workspace = %q, want billing", got)
+	}
+	if got := current(second); got != storage.DefaultWorkspace {
+		t.Errorf("second session workspace = %q, want %q", got, storage.DefaultWorkspace)
+	}
+
+	// The selection is released when the client disconnects
+	_ = first.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.workspaceMu.RLock()
+		remaining := len(s.sessionWorkspaces)
+		s.workspaceMu.RUnlock()
+		if remaining == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d session workspaces left after disconnect", remaining)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandleSearch_WorkspaceScoped(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	s := &UnifiedServer{storage: store}
+	ctx := context.Background()
+
+	thought := &types.Thought{Content: "billing retries", Mode: types.ModeLinear, Confidence: 0.8, Timestamp: time.Now()}
+	if err := storage.ForWorkspace(store, "billing").StoreThought(thought); err != nil {
+		t.Fatalf("StoreThought() error = %v", err)
+	}
+
+	_, resp, err := s.handleSearch(ctx, nil, SearchRequest{Query: "retries"})
+	if err != nil {
+		t.Fatalf("handleSearch() error = %v", err)
+	}
+	if len(resp.Thoughts) != 0 {
+		t.Errorf("default workspace search returned %d thoughts, want 0", len(resp.Thoughts))
+	}
+
+	_, resp, err = s.handleSearch(ctx, nil, SearchRequest{Query: "retries", Workspace: "billing"})
+	if err != nil {
+		t.Fatalf("handleSearch() error = %v", err)
+	}
+	if len(resp.Thoughts) != 1 {
+		t.Errorf("billing workspace search returned %d thoughts, want 1", len(resp.Thoughts))
+	}
+
+	if _, _, err := s.handleSearch(ctx, nil, SearchRequest{Query: "x", Workspace: "no spaces allowed"}); err == nil {
+		t.Error("expected validation error for invalid workspace")
+	}
+}
@@ -0,0 +1,351 @@
+// Package storage provides causal reasoning persistence methods.
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// StoreCausalGraph persists a causal graph with its variables and links in
+// the workspace of the view. Storing an existing graph replaces its structure
+// but keeps the interventions and counterfactuals recorded against it.
+func (s *SQLiteStorage) StoreCausalGraph(graph *types.CausalGraph) error {
+	if graph == nil || graph.ID == "" {
+		return fmt.Errorf("causal graph ID is required")
+	}
+
+	metadataJSON, _ := json.Marshal(graph.Metadata)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.Exec(`
+		INSERT INTO causal_graphs (id, description, metadata, created_at, workspace)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			description=excluded.description,
+			metadata=excluded.metadata
+		WHERE causal_graphs.workspace = excluded.workspace
+	`, graph.ID, graph.Description, string(metadataJSON), graph.CreatedAt.Unix(), s.workspace)
+	if err != nil {
+		return fmt.Errorf("failed to insert causal graph: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("causal graph %s already exists in another workspace", graph.ID)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM causal_variables WHERE graph_id = ?`, graph.ID); err != nil {
+		return fmt.Errorf("failed to clear causal variables: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM causal_links WHERE graph_id = ?`, graph.ID); err != nil {
+		return fmt.Errorf("failed to clear causal links: %w", err)
+	}
+
+	for i, v := range graph.Variables {
+		varMetadataJSON, _ := json.Marshal(v.Metadata)
+		_, err := tx.Exec(`
+			INSERT INTO causal_variables (graph_id, id, position, name, description, type, observable, metadata)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, graph.ID, v.ID, i, v.Name, v.Description, v.Type, boolToInt(v.Observable), string(varMetadataJSON))
+		if err != nil {
+			return fmt.Errorf("failed to insert causal variable %s: %w", v.ID, err)
+		}
+	}
+
+	for i, l := range graph.Links {
+		evidenceJSON, _ := json.Marshal(l.Evidence)
+		linkMetadataJSON, _ := json.Marshal(l.Metadata)
+		_, err := tx.Exec(`
+			INSERT INTO causal_links (graph_id, id, position, from_variable, to_variable,
+			                          strength, type, confidence, evidence, metadata)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, graph.ID, l.ID, i, l.From, l.To, l.Strength, l.Type, l.Confidence,
+			string(evidenceJSON), string(linkMetadataJSON))
+		if err != nil {
+			return fmt.Errorf("failed to insert causal link %s: %w", l.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit causal graph: %w", err)
+	}
+	return nil
+}
+
+// GetCausalGraph loads a causal graph of the workspace with its variables and links
+func (s *SQLiteStorage) GetCausalGraph(id string) (*types.CausalGraph, error) {
+	var metadataJSON sql.NullString
+	var createdAt int64
+	graph := &types.CausalGraph{ID: id}
+
+	err := s.db.QueryRow(`
+		SELECT description, metadata, created_at
+		FROM causal_graphs
+		WHERE id = ? AND workspace = ?
+	`, id, s.workspace).Scan(&graph.Description, &metadataJSON, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("causal graph not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query causal graph: %w", err)
+	}
+	graph.CreatedAt = time.Unix(createdAt, 0)
+	graph.Metadata = unmarshalMetadata(metadataJSON)
+
+	variables, err := s.loadCausalVariables(id)
+	if err != nil {
+		return nil, err
+	}
+	graph.Variables = variables
+
+	links, err := s.loadCausalLinks(id)
+	if err != nil {
+		return nil, err
+	}
+	graph.Links = links
+
+	return graph, nil
+}
+
+func (s *SQLiteStorage) loadCausalVariables(graphID string) ([]*types.CausalVariable, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, description, type, observable, metadata
+		FROM causal_variables
+		WHERE graph_id = ?
+		ORDER BY position ASC
+	`, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query causal variables: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	variables := make([]*types.CausalVariable, 0)
+	for rows.Next() {
+		v := &types.CausalVariable{}
+		var description, metadataJSON sql.NullString
+		var observable int
+		if err := rows.Scan(&v.ID, &v.Name, &description, &v.Type, &observable, &metadataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan causal variable: %w", err)
+		}
+		v.Description = description.String
+		v.Observable = observable == 1
+		v.Metadata = unmarshalMetadata(metadataJSON)
+		variables = append(variables, v)
+	}
+
+	return variables, rows.Err()
+}
+
+func (s *SQLiteStorage) loadCausalLinks(graphID string) ([]*types.CausalLink, error) {
+	rows, err := s.db.Query(`
+		SELECT id, from_variable, to_variable, strength, type, confidence, evidence, metadata
+		FROM causal_links
+		WHERE graph_id = ?
+		ORDER BY position ASC
+	`, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query causal links: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	links := make([]*types.CausalLink, 0)
+	for rows.Next() {
+		l := &types.CausalLink{}
+		var evidenceJSON, metadataJSON sql.NullString
+		if err := rows.Scan(&l.ID, &l.From, &l.To, &l.Strength, &l.Type, &l.Confidence, &evidenceJSON, &metadataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan causal link: %w", err)
+		}
+		if evidenceJSON.Valid && evidenceJSON.String != "" {
+			if err := json.Unmarshal([]byte(evidenceJSON.String), &l.Evidence); err != nil {
+				l.Evidence = []string{}
+			}
+		}
+		l.Metadata = unmarshalMetadata(metadataJSON)
+		links = append(links, l)
+	}
+
+	return links, rows.Err()
+}
+
+// ListCausalGraphs returns summaries of the workspace's causal graphs, newest first
+func (s *SQLiteStorage) ListCausalGraphs(limit, offset int) ([]*types.CausalGraphSummary, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	rows, err := s.db.Query(`
+		SELECT g.id, g.description, g.created_at,
+			(SELECT COUNT(*) FROM causal_variables v WHERE v.graph_id = g.id),
+			(SELECT COUNT(*) FROM causal_links l WHERE l.graph_id = g.id),
+			(SELECT COUNT(*) FROM causal_interventions i WHERE i.graph_id = g.id),
+			(SELECT COUNT(*) FROM causal_counterfactuals c WHERE c.graph_id = g.id)
+		FROM causal_graphs g
+		WHERE g.workspace = ?
+		ORDER BY g.created_at DESC, g.id DESC
+		LIMIT ? OFFSET ?
+	`, s.workspace, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query causal graphs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	summaries := make([]*types.CausalGraphSummary, 0)
+	for rows.Next() {
+		summary := &types.CausalGraphSummary{}
+		var createdAt int64
+		if err := rows.Scan(&summary.ID, &summary.Description, &createdAt,
+			&summary.VariableCount, &summary.LinkCount,
+			&summary.InterventionCount, &summary.CounterfactualCount); err != nil {
+			return nil, fmt.Errorf("failed to scan causal graph summary: %w", err)
+		}
+		summary.CreatedAt = time.Unix(createdAt, 0)
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// StoreCausalIntervention persists a simulated intervention
+func (s *SQLiteStorage) StoreCausalIntervention(intervention *types.CausalIntervention) error {
+	if intervention == nil || intervention.ID == "" {
+		return fmt.Errorf("intervention ID is required")
+	}
+
+	effectsJSON, _ := json.Marshal(intervention.PredictedEffects)
+	metadataJSON, _ := json.Marshal(intervention.Metadata)
+
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO causal_interventions (
+			id, graph_id, variable, intervention_type, intervention_value,
+			predicted_effects, confidence, metadata, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, intervention.ID, intervention.GraphID, intervention.Variable, intervention.InterventionType,
+		intervention.InterventionValue, string(effectsJSON), intervention.Confidence,
+		string(metadataJSON), intervention.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to insert causal intervention: %w", err)
+	}
+	return nil
+}
+
+// GetCausalInterventions returns the interventions simulated on a graph of
+// the workspace, oldest first
+func (s *SQLiteStorage) GetCausalInterventions(graphID string) ([]*types.CausalIntervention, error) {
+	rows, err := s.db.Query(`
+		SELECT id, variable, intervention_type, intervention_value, predicted_effects,
+		       confidence, metadata, created_at
+		FROM causal_interventions
+		WHERE graph_id = ? AND graph_id IN (SELECT id FROM causal_graphs WHERE workspace = ?)
+		ORDER BY created_at ASC, id ASC
+	`, graphID, s.workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query causal interventions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	interventions := make([]*types.CausalIntervention, 0)
+	for rows.Next() {
+		iv := &types.CausalIntervention{GraphID: graphID}
+		var value, effectsJSON, metadataJSON sql.NullString
+		var createdAt int64
+		if err := rows.Scan(&iv.ID, &iv.Variable, &iv.InterventionType, &value, &effectsJSON,
+			&iv.Confidence, &metadataJSON, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan causal intervention: %w", err)
+		}
+		iv.InterventionValue = value.String
+		if effectsJSON.Valid && effectsJSON.String != "" {
+			if err := json.Unmarshal([]byte(effectsJSON.String), &iv.PredictedEffects); err != nil {
+				iv.PredictedEffects = []*types.PredictedEffect{}
+			}
+		}
+		iv.Metadata = unmarshalMetadata(metadataJSON)
+		iv.CreatedAt = time.Unix(createdAt, 0)
+		interventions = append(interventions, iv)
+	}
+
+	return interventions, rows.Err()
+}
+
+// StoreCounterfactual persists a generated counterfactual scenario
+func (s *SQLiteStorage) StoreCounterfactual(cf *types.Counterfactual) error {
+	if cf == nil || cf.ID == "" {
+		return fmt.Errorf("counterfactual ID is required")
+	}
+
+	changesJSON, _ := json.Marshal(cf.Changes)
+	outcomesJSON, _ := json.Marshal(cf.Outcomes)
+	metadataJSON, _ := json.Marshal(cf.Metadata)
+
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO causal_counterfactuals (
+			id, graph_id, scenario, changes, outcomes, plausibility, metadata, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, cf.ID, cf.GraphID, cf.Scenario, string(changesJSON), string(outcomesJSON),
+		cf.Plausibility, string(metadataJSON), cf.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to insert counterfactual: %w", err)
+	}
+	return nil
+}
+
+// GetCounterfactuals returns the counterfactuals generated from a graph of
+// the workspace, oldest first
+func (s *SQLiteStorage) GetCounterfactuals(graphID string) ([]*types.Counterfactual, error) {
+	rows, err := s.db.Query(`
+		SELECT id, scenario, changes, outcomes, plausibility, metadata, created_at
+		FROM causal_counterfactuals
+		WHERE graph_id = ? AND graph_id IN (SELECT id FROM causal_graphs WHERE workspace = ?)
+		ORDER BY created_at ASC, id ASC
+	`, graphID, s.workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query counterfactuals: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	counterfactuals := make([]*types.Counterfactual, 0)
+	for rows.Next() {
+		cf := &types.Counterfactual{GraphID: graphID}
+		var changesJSON, outcomesJSON, metadataJSON sql.NullString
+		var createdAt int64
+		if err := rows.Scan(&cf.ID, &cf.Scenario, &changesJSON, &outcomesJSON,
+			&cf.Plausibility, &metadataJSON, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan counterfactual: %w", err)
+		}
+		cf.Changes = unmarshalStringMap(changesJSON)
+		cf.Outcomes = unmarshalStringMap(outcomesJSON)
+		cf.Metadata = unmarshalMetadata(metadataJSON)
+		cf.CreatedAt = time.Unix(createdAt, 0)
+		counterfactuals = append(counterfactuals, cf)
+	}
+
+	return counterfactuals, rows.Err()
+}
+
+// unmarshalMetadata decodes a nullable JSON metadata column
+func unmarshalMetadata(data sql.NullString) types.Metadata {
+	metadata := types.Metadata{}
+	if data.Valid && data.String != "" && data.String != "null" {
+		if err := json.Unmarshal([]byte(data.String), &metadata); err != nil {
+			return types.Metadata{}
+		}
+	}
+	return metadata
+}
+
+// unmarshalStringMap decodes a nullable JSON object of strings
+func unmarshalStringMap(data sql.NullString) map[string]string {
+	result := map[string]string{}
+	if data.Valid && data.String != "" && data.String != "null" {
+		if err := json.Unmarshal([]byte(data.String), &result); err != nil {
+			return map[string]string{}
+		}
+	}
+	return result
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+func newTestCausalGraph(id string) *types.CausalGraph {
+	return &types.CausalGraph{
+		ID:          id,
+		Description: "Marketing impact on sales",
+		Variables: []*types.CausalVariable{
+			{ID: "var-1", Name: "marketing spend", Type: "continuous", Observable: true, Metadata: map[string]interface{}{}},
+			{ID: "var-2", Name: "sales", Type: "continuous", Observable: true, Metadata: map[string]interface{}{}},
+		},
+		Links: []*types.CausalLink{
+			{ID: "link-1", From: "var-1", To: "var-2", Strength: 0.7, Type: "positive", Confidence: 0.8, Evidence: []string{"marketing spend increases sales"}},
+		},
+		Metadata:  map[string]interface{}{"source": "test"},
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestSQLiteStorage_CausalGraphPersistence(t *testing.T) {
+	store, dbPath := newTestSQLiteStorage(t)
+
+	graph := newTestCausalGraph("causal-graph-test-1")
+	if err := store.StoreCausalGraph(graph); err != nil {
+		t.Fatalf("StoreCausalGraph() error = %v", err)
+	}
+
+	intervention := &types.CausalIntervention{
+		ID:               "intervention-test-1",
+		GraphID:          graph.ID,
+		Variable:         "marketing spend",
+		InterventionType: "increase",
+		PredictedEffects: []*types.PredictedEffect{
+			{Variable: "sales", Effect: "increase", Magnitude: 0.7, Probability: 0.8, PathLength: 1},
+		},
+		Confidence: 0.8,
+		Metadata:   map[string]interface{}{"graph_surgery_applied": true},
+		CreatedAt:  time.Now(),
+	}
+	if err := store.StoreCausalIntervention(intervention); err != nil {
+		t.Fatalf("StoreCausalIntervention() error = %v", err)
+	}
+
+	counterfactual := &types.Counterfactual{
+		ID:           "counterfactual-test-1",
+		GraphID:      graph.ID,
+		Scenario:     "What if marketing spend doubled?",
+		Changes:      map[string]string{"marketing spend": "increase"},
+		Outcomes:     map[string]string{"sales": "increase"},
+		Plausibility: 0.7,
+		CreatedAt:    time.Now(),
+	}
+	if err := store.StoreCounterfactual(counterfactual); err != nil {
+		t.Fatalf("StoreCounterfactual() error = %v", err)
+	}
+
+	// Re-storing a graph must not drop its interventions
+	if err := store.StoreCausalGraph(graph); err != nil {
+		t.Fatalf("StoreCausalGraph() second call error = %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("failed to reopen storage: %v", err)
+	}
+	defer reopened.Close()
+
+	got, err := reopened.GetCausalGraph(graph.ID)
+	if err != nil {
+		t.Fatalf("GetCausalGraph() error = %v", err)
+	}
+	if got.Description != graph.Description {
+		t.Errorf("Description = %q, want %q", got.Description, graph.Description)
+	}
+	if len(got.Variables) != 2 || got.Variables[0].Name != "marketing spend" {
+		t.Errorf("Variables not restored in order: %+v", got.Variables)
+	}
+	if len(got.Links) != 1 || got.Links[0].From != "var-1" || got.Links[0].To != "var-2" {
+		t.Fatalf("Links not restored: %+v", got.Links)
+	}
+	if len(got.Links[0].Evidence) != 1 {
+		t.Errorf("link evidence = %v, want 1 entry", got.Links[0].Evidence)
+	}
+	if got.Metadata["source"] != "test" {
+		t.Errorf("Metadata = %v, want source=test", got.Metadata)
+	}
+
+	interventions, err := reopened.GetCausalInterventions(graph.ID)
+	if err != nil {
+		t.Fatalf("GetCausalInterventions() error = %v", err)
+	}
+	if len(interventions) != 1 || len(interventions[0].PredictedEffects) != 1 {
+		t.Errorf("interventions = %+v, want 1 with 1 effect", interventions)
+	}
+
+	counterfactuals, err := reopened.GetCounterfactuals(graph.ID)
+	if err != nil {
+		t.Fatalf("GetCounterfactuals() error = %v", err)
+	}
+	if len(counterfactuals) != 1 || counterfactuals[0].Outcomes["sales"] != "increase" {
+		t.Errorf("counterfactuals = %+v, want 1 with sales outcome", counterfactuals)
+	}
+
+	summaries, err := reopened.ListCausalGraphs(10, 0)
+	if err != nil {
+		t.Fatalf("ListCausalGraphs() error = %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("ListCausalGraphs() returned %d, want 1", len(summaries))
+	}
+	s := summaries[0]
+	if s.VariableCount != 2 || s.LinkCount != 1 || s.InterventionCount != 1 || s.CounterfactualCount != 1 {
+		t.Errorf("summary counts = %+v", s)
+	}
+
+	if _, err := reopened.GetCausalGraph("missing"); err == nil {
+		t.Error("expected error for missing graph")
+	}
+}
+
+func TestSQLiteStorage_ListCausalGraphsPagination(t *testing.T) {
+	store, _ := newTestSQLiteStorage(t)
+	defer store.Close()
+
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"g-old", "g-mid", "g-new"} {
+		graph := newTestCausalGraph(id)
+		graph.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if err := store.StoreCausalGraph(graph); err != nil {
+			t.Fatalf("StoreCausalGraph(%s) error = %v", id, err)
+		}
+	}
+
+	page, err := store.ListCausalGraphs(2, 0)
+	if err != nil {
+		t.Fatalf("ListCausalGraphs() error = %v", err)
+	}
+	if len(page) != 2 || page[0].ID != "g-new" || page[1].ID != "g-mid" {
+		t.Errorf("first page = %v, want [g-new g-mid]", summaryIDs(page))
+	}
+
+	page, err = store.ListCausalGraphs(2, 2)
+	if err != nil {
+		t.Fatalf("ListCausalGraphs() error = %v", err)
+	}
+	if len(page) != 1 || page[0].ID != "g-old" {
+		t.Errorf("second page = %v, want [g-old]", summaryIDs(page))
+	}
+}
+
+func TestSQLiteStorage_CausalGraphWorkspaces(t *testing.T) {
+	store, _ := newTestSQLiteStorage(t)
+	defer store.Close()
+	alpha := store.WorkspaceView("alpha")
+
+	graph := newTestCausalGraph("causal-graph-alpha")
+	if err := alpha.StoreCausalGraph(graph); err != nil {
+		t.Fatalf("StoreCausalGraph() error = %v", err)
+	}
+	intervention := &types.CausalIntervention{ID: "intervention-alpha", GraphID: graph.ID, Variable: "sales", InterventionType: "increase", CreatedAt: time.Now()}
+	if err := alpha.StoreCausalIntervention(intervention); err != nil {
+		t.Fatalf("StoreCausalIntervention() error = %v", err)
+	}
+
+	if _, err := store.GetCausalGraph(graph.ID); err == nil {
+		t.Error("graph from alpha should not be visible in the default workspace")
+	}
+	if summaries, err := store.ListCausalGraphs(0, 0); err != nil || len(summaries) != 0 {
+		t.Errorf("default ListCausalGraphs() = %v, %v; want none", summaryIDs(summaries), err)
+	}
+	if interventions, err := store.GetCausalInterventions(graph.ID); err != nil || len(interventions) != 0 {
+		t.Errorf("default GetCausalInterventions() = %d, %v; want none", len(interventions), err)
+	}
+	if err := store.StoreCausalGraph(newTestCausalGraph(graph.ID)); err == nil {
+		t.Error("expected error storing a graph with an ID owned by another workspace")
+	}
+
+	summaries, err := alpha.ListCausalGraphs(0, 0)
+	if err != nil || len(summaries) != 1 || summaries[0].InterventionCount != 1 {
+		t.Errorf("alpha ListCausalGraphs() = %+v, %v; want the graph with 1 intervention", summaries, err)
+	}
+	if got, err := alpha.GetCausalGraph(graph.ID); err != nil || len(got.Variables) != 2 {
+		t.Errorf("alpha GetCausalGraph() = %+v, %v; want 2 variables", got, err)
+	}
+}
+
+func summaryIDs(summaries []*types.CausalGraphSummary) []string {
+	ids := make([]string, len(summaries))
+	for i, s := range summaries {
+		ids[i] = s.ID
+	}
+	return ids
+}
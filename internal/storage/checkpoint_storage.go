@@ -0,0 +1,216 @@
+// Package storage provides persistence for backtracking checkpoints, branch snapshots and deltas.
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// StoreCheckpoint inserts or updates a checkpoint in the workspace of the view
+func (s *SQLiteStorage) StoreCheckpoint(checkpoint *types.CheckpointRecord) error {
+	if checkpoint == nil || checkpoint.ID == "" {
+		return fmt.Errorf("checkpoint ID is required")
+	}
+
+	metadataJSON, _ := json.Marshal(checkpoint.Metadata)
+
+	result, err := s.db.Exec(`
+		INSERT INTO checkpoints (id, name, description, branch_id, snapshot_id, delta_count, metadata, created_at, workspace)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name=excluded.name,
+			description=excluded.description,
+			snapshot_id=excluded.snapshot_id,
+			delta_count=excluded.delta_count,
+			metadata=excluded.metadata
+		WHERE checkpoints.workspace = excluded.workspace
+	`, checkpoint.ID, checkpoint.Name, checkpoint.Description, checkpoint.BranchID,
+		checkpoint.SnapshotID, checkpoint.DeltaCount, string(metadataJSON), checkpoint.CreatedAt.Unix(), s.workspace)
+	if err != nil {
+		return fmt.Errorf("failed to store checkpoint: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("checkpoint %s already exists in another workspace", checkpoint.ID)
+	}
+	return nil
+}
+
+// GetCheckpoint loads a checkpoint of the workspace by ID
+func (s *SQLiteStorage) GetCheckpoint(id string) (*types.CheckpointRecord, error) {
+	row := s.db.QueryRow(`
+		SELECT id, name, description, branch_id, snapshot_id, delta_count, metadata, created_at
+		FROM checkpoints
+		WHERE id = ? AND workspace = ?
+	`, id, s.workspace)
+
+	checkpoint, err := scanCheckpoint(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("checkpoint not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// ListCheckpoints returns the workspace's checkpoints in creation order. An
+// empty branchID returns the checkpoints of every branch.
+func (s *SQLiteStorage) ListCheckpoints(branchID string) ([]*types.CheckpointRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, description, branch_id, snapshot_id, delta_count, metadata, created_at
+		FROM checkpoints
+		WHERE workspace = ? AND (? = '' OR branch_id = ?)
+		ORDER BY created_at, rowid
+	`, s.workspace, branchID, branchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query checkpoints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	checkpoints := make([]*types.CheckpointRecord, 0)
+	for rows.Next() {
+		checkpoint, err := scanCheckpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, rows.Err()
+}
+
+func scanCheckpoint(scanner rowScanner) (*types.CheckpointRecord, error) {
+	checkpoint := &types.CheckpointRecord{}
+	var description, metadataJSON sql.NullString
+	var createdAt int64
+
+	err := scanner.Scan(&checkpoint.ID, &checkpoint.Name, &description, &checkpoint.BranchID,
+		&checkpoint.SnapshotID, &checkpoint.DeltaCount, &metadataJSON, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan checkpoint: %w", err)
+	}
+
+	checkpoint.Description = description.String
+	checkpoint.Metadata = unmarshalMetadata(metadataJSON)
+	checkpoint.CreatedAt = time.Unix(createdAt, 0)
+
+	return checkpoint, nil
+}
+
+// StoreBranchSnapshot inserts a branch snapshot. Snapshots are immutable, so
+// storing an existing ID is a no-op.
+func (s *SQLiteStorage) StoreBranchSnapshot(snapshot *types.BranchSnapshotRecord) error {
+	if snapshot == nil || snapshot.ID == "" {
+		return fmt.Errorf("snapshot ID is required")
+	}
+	if snapshot.Branch == nil {
+		return fmt.Errorf("snapshot %s has no branch", snapshot.ID)
+	}
+
+	branchJSON, err := json.Marshal(snapshot.Branch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot branch: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT OR IGNORE INTO branch_snapshots (id, branch_id, branch, thought_count, insight_count, cross_ref_count, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, snapshot.ID, snapshot.BranchID, string(branchJSON), snapshot.ThoughtCount,
+		snapshot.InsightCount, snapshot.CrossRefCount, snapshot.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to store branch snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetBranchSnapshot loads a branch snapshot by ID
+func (s *SQLiteStorage) GetBranchSnapshot(id string) (*types.BranchSnapshotRecord, error) {
+	snapshot := &types.BranchSnapshotRecord{}
+	var branchJSON string
+	var createdAt int64
+
+	err := s.db.QueryRow(`
+		SELECT id, branch_id, branch, thought_count, insight_count, cross_ref_count, created_at
+		FROM branch_snapshots
+		WHERE id = ?
+	`, id).Scan(&snapshot.ID, &snapshot.BranchID, &branchJSON, &snapshot.ThoughtCount,
+		&snapshot.InsightCount, &snapshot.CrossRefCount, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("branch snapshot not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch snapshot: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(branchJSON), &snapshot.Branch); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot branch: %w", err)
+	}
+	snapshot.CreatedAt = time.Unix(createdAt, 0)
+
+	return snapshot, nil
+}
+
+// StoreBranchDelta appends a delta to the end of its snapshot's delta log
+func (s *SQLiteStorage) StoreBranchDelta(delta *types.BranchDeltaRecord) error {
+	if delta == nil || delta.ID == "" {
+		return fmt.Errorf("delta ID is required")
+	}
+	if delta.SnapshotID == "" {
+		return fmt.Errorf("delta %s has no snapshot", delta.ID)
+	}
+
+	var entity interface{}
+	if len(delta.Entity) > 0 {
+		entity = string(delta.Entity)
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO branch_deltas (id, snapshot_id, branch_id, position, operation, entity_type, entity_id, entity, created_at)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM branch_deltas WHERE snapshot_id = ?), ?, ?, ?, ?, ?)
+	`, delta.ID, delta.SnapshotID, delta.BranchID, delta.SnapshotID,
+		delta.Operation, delta.EntityType, delta.EntityID, entity, delta.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to store branch delta: %w", err)
+	}
+	return nil
+}
+
+// GetBranchDeltas returns the deltas recorded on top of a snapshot, in the
+// order they were recorded
+func (s *SQLiteStorage) GetBranchDeltas(snapshotID string) ([]*types.BranchDeltaRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT id, snapshot_id, branch_id, operation, entity_type, entity_id, entity, created_at
+		FROM branch_deltas
+		WHERE snapshot_id = ?
+		ORDER BY position
+	`, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query branch deltas: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	deltas := make([]*types.BranchDeltaRecord, 0)
+	for rows.Next() {
+		delta := &types.BranchDeltaRecord{}
+		var entity sql.NullString
+		var createdAt int64
+		if err := rows.Scan(&delta.ID, &delta.SnapshotID, &delta.BranchID, &delta.Operation,
+			&delta.EntityType, &delta.EntityID, &entity, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan branch delta: %w", err)
+		}
+		if entity.Valid {
+			delta.Entity = json.RawMessage(entity.String)
+		}
+		delta.CreatedAt = time.Unix(createdAt, 0)
+		deltas = append(deltas, delta)
+	}
+
+	return deltas, rows.Err()
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+func TestSQLiteStorage_CheckpointPersistence(t *testing.T) {
+	store, dbPath := newTestSQLiteStorage(t)
+
+	snapshot := &types.BranchSnapshotRecord{
+		ID:       "snapshot-test-1",
+		BranchID: "branch-1",
+		Branch: &types.Branch{
+			ID:       "branch-1",
+			State:    types.StateActive,
+			Thoughts: []*types.Thought{{ID: "thought-1", Content: "Initial thought", Mode: types.ModeTree}},
+		},
+		ThoughtCount: 1,
+		CreatedAt:    time.Now(),
+	}
+	if err := store.StoreBranchSnapshot(snapshot); err != nil {
+		t.Fatalf("StoreBranchSnapshot() error = %v", err)
+	}
+
+	for _, id := range []string{"thought-2", "thought-3"} {
+		entity, _ := json.Marshal(&types.Thought{ID: id, Content: "Later thought"})
+		delta := &types.BranchDeltaRecord{
+			ID:         "delta-" + id,
+			SnapshotID: snapshot.ID,
+			BranchID:   "branch-1",
+			Operation:  "add",
+			EntityType: "thought",
+			EntityID:   id,
+			Entity:     entity,
+			CreatedAt:  time.Now(),
+		}
+		if err := store.StoreBranchDelta(delta); err != nil {
+			t.Fatalf("StoreBranchDelta(%s) error = %v", id, err)
+		}
+	}
+
+	checkpoint := &types.CheckpointRecord{
+		ID:          "checkpoint-test-1",
+		Name:        "After second thought",
+		Description: "Fork point",
+		BranchID:    "branch-1",
+		SnapshotID:  snapshot.ID,
+		DeltaCount:  1,
+		Metadata:    map[string]interface{}{"thought_ids": []string{"thought-1", "thought-2"}},
+		CreatedAt:   time.Now(),
+	}
+	if err := store.StoreCheckpoint(checkpoint); err != nil {
+		t.Fatalf("StoreCheckpoint() error = %v", err)
+	}
+	other := &types.CheckpointRecord{ID: "checkpoint-test-2", Name: "Other", BranchID: "branch-2", SnapshotID: snapshot.ID, CreatedAt: time.Now()}
+	if err := store.StoreCheckpoint(other); err != nil {
+		t.Fatalf("StoreCheckpoint() error = %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("failed to reopen storage: %v", err)
+	}
+	defer reopened.Close()
+
+	got, err := reopened.GetCheckpoint(checkpoint.ID)
+	if err != nil {
+		t.Fatalf("GetCheckpoint() error = %v", err)
+	}
+	if got.SnapshotID != snapshot.ID || got.DeltaCount != 1 || got.Description != "Fork point" {
+		t.Errorf("checkpoint not restored: %+v", got)
+	}
+	if ids, ok := got.Metadata["thought_ids"].([]interface{}); !ok || len(ids) != 2 {
+		t.Errorf("checkpoint metadata = %v, want 2 thought_ids", got.Metadata)
+	}
+
+	gotSnapshot, err := reopened.GetBranchSnapshot(snapshot.ID)
+	if err != nil {
+		t.Fatalf("GetBranchSnapshot() error = %v", err)
+	}
+	if gotSnapshot.Branch == nil || len(gotSnapshot.Branch.Thoughts) != 1 || gotSnapshot.Branch.Thoughts[0].Content != "Initial thought" {
+		t.Errorf("snapshot branch not restored: %+v", gotSnapshot.Branch)
+	}
+
+	deltas, err := reopened.GetBranchDeltas(snapshot.ID)
+	if err != nil {
+		t.Fatalf("GetBranchDeltas() error = %v", err)
+	}
+	if len(deltas) != 2 || deltas[0].EntityID != "thought-2" || deltas[1].EntityID != "thought-3" {
+		t.Fatalf("deltas not restored in order: %+v", deltas)
+	}
+	var thought types.Thought
+	if err := json.Unmarshal(deltas[0].Entity, &thought); err != nil || thought.ID != "thought-2" {
+		t.Errorf("delta entity = %s, %v", deltas[0].Entity, err)
+	}
+
+	all, err := reopened.ListCheckpoints("")
+	if err != nil {
+		t.Fatalf("ListCheckpoints() error = %v", err)
+	}
+	if len(all) != 2 || all[0].ID != checkpoint.ID {
+		t.Errorf("ListCheckpoints(\"\") = %d checkpoints, want 2 in creation order", len(all))
+	}
+	filtered, err := reopened.ListCheckpoints("branch-2")
+	if err != nil || len(filtered) != 1 || filtered[0].ID != other.ID {
+		t.Errorf("ListCheckpoints(branch-2) = %v, %v; want [%s]", filtered, err, other.ID)
+	}
+
+	if _, err := reopened.GetCheckpoint("missing"); err == nil {
+		t.Error("expected error for missing checkpoint")
+	}
+	if _, err := reopened.GetBranchSnapshot("missing"); err == nil {
+		t.Error("expected error for missing snapshot")
+	}
+}
+
+func TestSQLiteStorage_CheckpointWorkspaces(t *testing.T) {
+	store, _ := newTestSQLiteStorage(t)
+	defer store.Close()
+	alpha := store.WorkspaceView("alpha")
+
+	checkpoint := &types.CheckpointRecord{ID: "checkpoint-alpha", Name: "Alpha", BranchID: "branch-1", SnapshotID: "snapshot-1", CreatedAt: time.Now()}
+	if err := alpha.StoreCheckpoint(checkpoint); err != nil {
+		t.Fatalf("StoreCheckpoint() error = %v", err)
+	}
+
+	if _, err := store.GetCheckpoint(checkpoint.ID); err == nil {
+		t.Error("checkpoint from alpha should not be visible in the default workspace")
+	}
+	if all, err := store.ListCheckpoints(""); err != nil || len(all) != 0 {
+		t.Errorf("default ListCheckpoints(\"\") = %d checkpoints, %v; want 0", len(all), err)
+	}
+	if err := store.StoreCheckpoint(&types.CheckpointRecord{ID: checkpoint.ID, Name: "Overwrite", BranchID: "branch-1", SnapshotID: "snapshot-1", CreatedAt: time.Now()}); err == nil {
+		t.Error("expected error storing a checkpoint with an ID owned by another workspace")
+	}
+	if got, err := alpha.GetCheckpoint(checkpoint.ID); err != nil || got.Name != "Alpha" {
+		t.Errorf("alpha GetCheckpoint() = %+v, %v; want the original checkpoint", got, err)
+	}
+}
@@ -0,0 +1,371 @@
+// Package storage provides persistence for decisions, beliefs and problem decompositions.
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// StoreDecision inserts or updates a decision in the workspace of the view
+func (s *SQLiteStorage) StoreDecision(decision *types.Decision) error {
+	if decision == nil || decision.ID == "" {
+		return fmt.Errorf("decision ID is required")
+	}
+
+	optionsJSON, err := json.Marshal(decision.Options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal decision options: %w", err)
+	}
+	criteriaJSON, err := json.Marshal(decision.Criteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal decision criteria: %w", err)
+	}
+	metadataJSON, _ := json.Marshal(decision.Metadata)
+
+	result, err := s.db.Exec(`
+		INSERT INTO decisions (id, question, options, criteria, recommendation, confidence, metadata, created_at, updated_at, workspace)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			question=excluded.question,
+			options=excluded.options,
+			criteria=excluded.criteria,
+			recommendation=excluded.recommendation,
+			confidence=excluded.confidence,
+			metadata=excluded.metadata,
+			updated_at=excluded.updated_at
+		WHERE decisions.workspace = excluded.workspace
+	`, decision.ID, decision.Question, string(optionsJSON), string(criteriaJSON),
+		decision.Recommendation, decision.Confidence, string(metadataJSON),
+		decision.CreatedAt.Unix(), time.Now().Unix(), s.workspace)
+	if err != nil {
+		return fmt.Errorf("failed to store decision: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("decision %s already exists in another workspace", decision.ID)
+	}
+	return nil
+}
+
+// GetDecision loads a decision of the workspace by ID
+func (s *SQLiteStorage) GetDecision(id string) (*types.Decision, error) {
+	row := s.db.QueryRow(`
+		SELECT id, question, options, criteria, recommendation, confidence, metadata, created_at
+		FROM decisions
+		WHERE id = ? AND workspace = ?
+	`, id, s.workspace)
+
+	decision, err := scanDecision(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("decision not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+// ListDecisions returns the workspace's decisions, newest first. A limit of
+// zero or less returns all decisions.
+func (s *SQLiteStorage) ListDecisions(limit, offset int) ([]*types.Decision, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, question, options, criteria, recommendation, confidence, metadata, created_at
+		FROM decisions
+		WHERE workspace = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, s.workspace, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decisions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	decisions := make([]*types.Decision, 0)
+	for rows.Next() {
+		decision, err := scanDecision(rows)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, decision)
+	}
+
+	return decisions, rows.Err()
+}
+
+// DeleteDecision removes a decision of the workspace
+func (s *SQLiteStorage) DeleteDecision(id string) error {
+	result, err := s.db.Exec(`DELETE FROM decisions WHERE id = ? AND workspace = ?`, id, s.workspace)
+	if err != nil {
+		return fmt.Errorf("failed to delete decision: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("decision not found: %s", id)
+	}
+	return nil
+}
+
+func scanDecision(scanner rowScanner) (*types.Decision, error) {
+	decision := &types.Decision{}
+	var optionsJSON, criteriaJSON string
+	var recommendation, metadataJSON sql.NullString
+	var createdAt int64
+
+	err := scanner.Scan(&decision.ID, &decision.Question, &optionsJSON, &criteriaJSON,
+		&recommendation, &decision.Confidence, &metadataJSON, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan decision: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(optionsJSON), &decision.Options); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal decision options: %w", err)
+	}
+	if err := json.Unmarshal([]byte(criteriaJSON), &decision.Criteria); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal decision criteria: %w", err)
+	}
+	decision.Recommendation = recommendation.String
+	decision.Metadata = unmarshalMetadata(metadataJSON)
+	decision.CreatedAt = time.Unix(createdAt, 0)
+
+	return decision, nil
+}
+
+// StoreBelief inserts or updates a probabilistic belief in the workspace of the view
+func (s *SQLiteStorage) StoreBelief(belief *types.ProbabilisticBelief) error {
+	if belief == nil || belief.ID == "" {
+		return fmt.Errorf("belief ID is required")
+	}
+
+	evidenceJSON, _ := json.Marshal(belief.Evidence)
+	metadataJSON, _ := json.Marshal(belief.Metadata)
+
+	result, err := s.db.Exec(`
+		INSERT INTO probabilistic_beliefs (id, statement, probability, prior_prob, evidence, metadata, updated_at, workspace)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			statement=excluded.statement,
+			probability=excluded.probability,
+			prior_prob=excluded.prior_prob,
+			evidence=excluded.evidence,
+			metadata=excluded.metadata,
+			updated_at=excluded.updated_at
+		WHERE probabilistic_beliefs.workspace = excluded.workspace
+	`, belief.ID, belief.Statement, belief.Probability, belief.PriorProb,
+		string(evidenceJSON), string(metadataJSON), belief.UpdatedAt.Unix(), s.workspace)
+	if err != nil {
+		return fmt.Errorf("failed to store belief: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("belief %s already exists in another workspace", belief.ID)
+	}
+	return nil
+}
+
+// GetBelief loads a probabilistic belief of the workspace by ID
+func (s *SQLiteStorage) GetBelief(id string) (*types.ProbabilisticBelief, error) {
+	row := s.db.QueryRow(`
+		SELECT id, statement, probability, prior_prob, evidence, metadata, updated_at
+		FROM probabilistic_beliefs
+		WHERE id = ? AND workspace = ?
+	`, id, s.workspace)
+
+	belief, err := scanBelief(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("belief not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return belief, nil
+}
+
+// ListBeliefs returns the workspace's beliefs, most recently updated first. A
+// limit of zero or less returns all beliefs.
+func (s *SQLiteStorage) ListBeliefs(limit, offset int) ([]*types.ProbabilisticBelief, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, statement, probability, prior_prob, evidence, metadata, updated_at
+		FROM probabilistic_beliefs
+		WHERE workspace = ?
+		ORDER BY updated_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, s.workspace, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query beliefs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	beliefs := make([]*types.ProbabilisticBelief, 0)
+	for rows.Next() {
+		belief, err := scanBelief(rows)
+		if err != nil {
+			return nil, err
+		}
+		beliefs = append(beliefs, belief)
+	}
+
+	return beliefs, rows.Err()
+}
+
+func scanBelief(scanner rowScanner) (*types.ProbabilisticBelief, error) {
+	belief := &types.ProbabilisticBelief{}
+	var evidenceJSON, metadataJSON sql.NullString
+	var updatedAt int64
+
+	err := scanner.Scan(&belief.ID, &belief.Statement, &belief.Probability, &belief.PriorProb,
+		&evidenceJSON, &metadataJSON, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan belief: %w", err)
+	}
+
+	belief.Evidence = []string{}
+	if evidenceJSON.Valid && evidenceJSON.String != "" && evidenceJSON.String != "null" {
+		if err := json.Unmarshal([]byte(evidenceJSON.String), &belief.Evidence); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal belief evidence: %w", err)
+		}
+	}
+	belief.Metadata = unmarshalMetadata(metadataJSON)
+	belief.UpdatedAt = time.Unix(updatedAt, 0)
+
+	return belief, nil
+}
+
+// StoreDecomposition inserts or updates a problem decomposition in the
+// workspace of the view
+func (s *SQLiteStorage) StoreDecomposition(decomposition *types.ProblemDecomposition) error {
+	if decomposition == nil || decomposition.ID == "" {
+		return fmt.Errorf("decomposition ID is required")
+	}
+
+	subproblemsJSON, err := json.Marshal(decomposition.Subproblems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal subproblems: %w", err)
+	}
+	dependenciesJSON, _ := json.Marshal(decomposition.Dependencies)
+	solutionPathJSON, _ := json.Marshal(decomposition.SolutionPath)
+	metadataJSON, _ := json.Marshal(decomposition.Metadata)
+
+	result, err := s.db.Exec(`
+		INSERT INTO problem_decompositions (id, problem, subproblems, dependencies, solution_path, metadata, created_at, updated_at, workspace)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			problem=excluded.problem,
+			subproblems=excluded.subproblems,
+			dependencies=excluded.dependencies,
+			solution_path=excluded.solution_path,
+			metadata=excluded.metadata,
+			updated_at=excluded.updated_at
+		WHERE problem_decompositions.workspace = excluded.workspace
+	`, decomposition.ID, decomposition.Problem, string(subproblemsJSON), string(dependenciesJSON),
+		string(solutionPathJSON), string(metadataJSON), decomposition.CreatedAt.Unix(), time.Now().Unix(), s.workspace)
+	if err != nil {
+		return fmt.Errorf("failed to store decomposition: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("decomposition %s already exists in another workspace", decomposition.ID)
+	}
+	return nil
+}
+
+// GetDecomposition loads a problem decomposition of the workspace by ID
+func (s *SQLiteStorage) GetDecomposition(id string) (*types.ProblemDecomposition, error) {
+	row := s.db.QueryRow(`
+		SELECT id, problem, subproblems, dependencies, solution_path, metadata, created_at
+		FROM problem_decompositions
+		WHERE id = ? AND workspace = ?
+	`, id, s.workspace)
+
+	decomposition, err := scanDecomposition(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("decomposition not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decomposition, nil
+}
+
+// ListDecompositions returns the workspace's problem decompositions, newest
+// first. A limit of zero or less returns all decompositions.
+func (s *SQLiteStorage) ListDecompositions(limit, offset int) ([]*types.ProblemDecomposition, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, problem, subproblems, dependencies, solution_path, metadata, created_at
+		FROM problem_decompositions
+		WHERE workspace = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, s.workspace, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decompositions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	decompositions := make([]*types.ProblemDecomposition, 0)
+	for rows.Next() {
+		decomposition, err := scanDecomposition(rows)
+		if err != nil {
+			return nil, err
+		}
+		decompositions = append(decompositions, decomposition)
+	}
+
+	return decompositions, rows.Err()
+}
+
+func scanDecomposition(scanner rowScanner) (*types.ProblemDecomposition, error) {
+	decomposition := &types.ProblemDecomposition{}
+	var subproblemsJSON string
+	var dependenciesJSON, solutionPathJSON, metadataJSON sql.NullString
+	var createdAt int64
+
+	err := scanner.Scan(&decomposition.ID, &decomposition.Problem, &subproblemsJSON,
+		&dependenciesJSON, &solutionPathJSON, &metadataJSON, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan decomposition: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(subproblemsJSON), &decomposition.Subproblems); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal subproblems: %w", err)
+	}
+	if dependenciesJSON.Valid && dependenciesJSON.String != "" {
+		if err := json.Unmarshal([]byte(dependenciesJSON.String), &decomposition.Dependencies); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dependencies: %w", err)
+		}
+	}
+	if solutionPathJSON.Valid && solutionPathJSON.String != "" {
+		if err := json.Unmarshal([]byte(solutionPathJSON.String), &decomposition.SolutionPath); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal solution path: %w", err)
+		}
+	}
+	decomposition.Metadata = unmarshalMetadata(metadataJSON)
+	decomposition.CreatedAt = time.Unix(createdAt, 0)
+
+	return decomposition, nil
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+func TestSQLiteStorage_DecisionPersistence(t *testing.T) {
+	store, dbPath := newTestSQLiteStorage(t)
+
+	decision := &types.Decision{
+		ID:       "decision-test-1",
+		Question: "Which database?",
+		Options: []*types.DecisionOption{
+			{ID: "pg", Name: "PostgreSQL", Scores: map[string]float64{"cost": 0.8}, Pros: []string{"mature"}, TotalScore: 0.8},
+			{ID: "mongo", Name: "MongoDB", Scores: map[string]float64{"cost": 0.6}, TotalScore: 0.6},
+		},
+		Criteria: []*types.DecisionCriterion{
+			{ID: "cost", Name: "Cost", Weight: 1.0, Maximize: true},
+		},
+		Recommendation: "Recommended option: PostgreSQL (score: 0.80)",
+		Confidence:     0.6,
+		Metadata:       map[string]interface{}{"recalculation_count": 2},
+		CreatedAt:      time.Now(),
+	}
+	if err := store.StoreDecision(decision); err != nil {
+		t.Fatalf("StoreDecision() error = %v", err)
+	}
+
+	belief := &types.ProbabilisticBelief{
+		ID:          "belief-test-1",
+		Statement:   "The migration will finish this quarter",
+		Probability: 0.7,
+		PriorProb:   0.5,
+		Evidence:    []string{"ev-1"},
+		UpdatedAt:   time.Now(),
+	}
+	if err := store.StoreBelief(belief); err != nil {
+		t.Fatalf("StoreBelief() error = %v", err)
+	}
+
+	decomposition := &types.ProblemDecomposition{
+		ID:      "decomposition-test-1",
+		Problem: "Reduce CI time",
+		Subproblems: []*types.Subproblem{
+			{ID: "sp-1", Description: "Profile the pipeline", Complexity: "low", Priority: "high", Status: "pending"},
+			{ID: "sp-2", Description: "Parallelize tests", Complexity: "medium", Priority: "high", Status: "pending"},
+		},
+		Dependencies: []*types.Dependency{{FromSubproblem: "sp-1", ToSubproblem: "sp-2", Type: "required"}},
+		SolutionPath: []string{"sp-1", "sp-2"},
+		CreatedAt:    time.Now(),
+	}
+	if err := store.StoreDecomposition(decomposition); err != nil {
+		t.Fatalf("StoreDecomposition() error = %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("failed to reopen storage: %v", err)
+	}
+	defer reopened.Close()
+
+	gotDecision, err := reopened.GetDecision(decision.ID)
+	if err != nil {
+		t.Fatalf("GetDecision() error = %v", err)
+	}
+	if len(gotDecision.Options) != 2 || gotDecision.Options[0].Scores["cost"] != 0.8 {
+		t.Errorf("decision options not restored: %+v", gotDecision.Options)
+	}
+	if len(gotDecision.Criteria) != 1 || !gotDecision.Criteria[0].Maximize {
+		t.Errorf("decision criteria not restored: %+v", gotDecision.Criteria)
+	}
+	if gotDecision.Recommendation != decision.Recommendation {
+		t.Errorf("Recommendation = %q, want %q", gotDecision.Recommendation, decision.Recommendation)
+	}
+
+	gotBelief, err := reopened.GetBelief(belief.ID)
+	if err != nil {
+		t.Fatalf("GetBelief() error = %v", err)
+	}
+	if gotBelief.Probability != 0.7 || gotBelief.PriorProb != 0.5 || len(gotBelief.Evidence) != 1 {
+		t.Errorf("belief not restored: %+v", gotBelief)
+	}
+
+	gotDecomposition, err := reopened.GetDecomposition(decomposition.ID)
+	if err != nil {
+		t.Fatalf("GetDecomposition() error = %v", err)
+	}
+	if len(gotDecomposition.Subproblems) != 2 || len(gotDecomposition.Dependencies) != 1 || len(gotDecomposition.SolutionPath) != 2 {
+		t.Errorf("decomposition not restored: %+v", gotDecomposition)
+	}
+
+	if decisions, err := reopened.ListDecisions(0, 0); err != nil || len(decisions) != 1 {
+		t.Errorf("ListDecisions() = %d, %v; want 1", len(decisions), err)
+	}
+	if beliefs, err := reopened.ListBeliefs(10, 0); err != nil || len(beliefs) != 1 {
+		t.Errorf("ListBeliefs() = %d, %v; want 1", len(beliefs), err)
+	}
+	if decompositions, err := reopened.ListDecompositions(10, 1); err != nil || len(decompositions) != 0 {
+		t.Errorf("ListDecompositions(offset 1) = %d, %v; want 0", len(decompositions), err)
+	}
+
+	if err := reopened.DeleteDecision(decision.ID); err != nil {
+		t.Fatalf("DeleteDecision() error = %v", err)
+	}
+	if _, err := reopened.GetDecision(decision.ID); err == nil {
+		t.Error("expected error for deleted decision")
+	}
+	if err := reopened.DeleteDecision(decision.ID); err == nil {
+		t.Error("expected error deleting a missing decision")
+	}
+}
+
+func TestSQLiteStorage_StoreDecisionUpdates(t *testing.T) {
+	store, _ := newTestSQLiteStorage(t)
+	defer store.Close()
+
+	decision := &types.Decision{
+		ID:       "decision-update",
+		Question: "Build or buy?",
+		Options: []*types.DecisionOption{
+			{ID: "build", Name: "Build", Scores: map[string]float64{"speed": 0.3}},
+		},
+		Criteria:   []*types.DecisionCriterion{{ID: "speed", Name: "Speed", Weight: 1.0, Maximize: true}},
+		Confidence: 0.5,
+		CreatedAt:  time.Now(),
+	}
+	if err := store.StoreDecision(decision); err != nil {
+		t.Fatalf("StoreDecision() error = %v", err)
+	}
+
+	decision.Options[0].Scores["speed"] = 0.9
+	decision.Confidence = 0.9
+	if err := store.StoreDecision(decision); err != nil {
+		t.Fatalf("StoreDecision() update error = %v", err)
+	}
+
+	got, err := store.GetDecision(decision.ID)
+	if err != nil {
+		t.Fatalf("GetDecision() error = %v", err)
+	}
+	if got.Options[0].Scores["speed"] != 0.9 || got.Confidence != 0.9 {
+		t.Errorf("update not persisted: score=%v confidence=%v", got.Options[0].Scores["speed"], got.Confidence)
+	}
+}
+
+func TestSQLiteStorage_DecisionWorkspaces(t *testing.T) {
+	store, _ := newTestSQLiteStorage(t)
+	defer store.Close()
+	alpha := store.WorkspaceView("alpha")
+
+	decision := &types.Decision{
+		ID:         "decision-alpha",
+		Question:   "Build or buy?",
+		Options:    []*types.DecisionOption{{ID: "build", Name: "Build", Scores: map[string]float64{"speed": 0.3}}},
+		Criteria:   []*types.DecisionCriterion{{ID: "speed", Name: "Speed", Weight: 1.0, Maximize: true}},
+		Confidence: 0.5,
+		CreatedAt:  time.Now(),
+	}
+	belief := &types.ProbabilisticBelief{ID: "belief-alpha", Statement: "It ships", Probability: 0.6, PriorProb: 0.5, UpdatedAt: time.Now()}
+	decomposition := &types.ProblemDecomposition{
+		ID:          "decomposition-alpha",
+		Problem:     "Reduce CI time",
+		Subproblems: []*types.Subproblem{{ID: "sp-1", Description: "Profile the pipeline"}},
+		CreatedAt:   time.Now(),
+	}
+	if err := alpha.StoreDecision(decision); err != nil {
+		t.Fatalf("StoreDecision() error = %v", err)
+	}
+	if err := alpha.StoreBelief(belief); err != nil {
+		t.Fatalf("StoreBelief() error = %v", err)
+	}
+	if err := alpha.StoreDecomposition(decomposition); err != nil {
+		t.Fatalf("StoreDecomposition() error = %v", err)
+	}
+
+	if _, err := store.GetDecision(decision.ID); err == nil {
+		t.Error("decision from alpha should not be visible in the default workspace")
+	}
+	if _, err := store.GetBelief(belief.ID); err == nil {
+		t.Error("belief from alpha should not be visible in the default workspace")
+	}
+	if _, err := store.GetDecomposition(decomposition.ID); err == nil {
+		t.Error("decomposition from alpha should not be visible in the default workspace")
+	}
+	if decisions, err := store.ListDecisions(0, 0); err != nil || len(decisions) != 0 {
+		t.Errorf("default ListDecisions() = %d, %v; want none", len(decisions), err)
+	}
+	if beliefs, err := store.ListBeliefs(0, 0); err != nil || len(beliefs) != 0 {
+		t.Errorf("default ListBeliefs() = %d, %v; want none", len(beliefs), err)
+	}
+	if decompositions, err := store.ListDecompositions(0, 0); err != nil || len(decompositions) != 0 {
+		t.Errorf("default ListDecompositions() = %d, %v; want none", len(decompositions), err)
+	}
+	if err := store.DeleteDecision(decision.ID); err == nil {
+		t.Error("expected error deleting a decision owned by another workspace")
+	}
+	if err := store.StoreDecision(decision); err == nil {
+		t.Error("expected error storing a decision with an ID owned by another workspace")
+	}
+	if err := store.StoreBelief(belief); err == nil {
+		t.Error("expected error storing a belief with an ID owned by another workspace")
+	}
+	if err := store.StoreDecomposition(decomposition); err == nil {
+		t.Error("expected error storing a decomposition with an ID owned by another workspace")
+	}
+
+	if decisions, err := alpha.ListDecisions(0, 0); err != nil || len(decisions) != 1 {
+		t.Errorf("alpha ListDecisions() = %d, %v; want 1", len(decisions), err)
+	}
+	if beliefs, err := alpha.ListBeliefs(0, 0); err != nil || len(beliefs) != 1 {
+		t.Errorf("alpha ListBeliefs() = %d, %v; want 1", len(beliefs), err)
+	}
+	if got, err := alpha.GetDecomposition(decomposition.ID); err != nil || got.Problem != decomposition.Problem {
+		t.Errorf("alpha GetDecomposition() = %+v, %v", got, err)
+	}
+}
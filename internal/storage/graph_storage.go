@@ -0,0 +1,132 @@
+// Package storage provides persistence for Graph-of-Thoughts states.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// StoreGraphState inserts or updates a Graph-of-Thoughts state in the
+// storage's workspace
+func (s *SQLiteStorage) StoreGraphState(record *types.GraphStateRecord) error {
+	if record == nil || record.ID == "" {
+		return fmt.Errorf("graph state ID is required")
+	}
+	if len(record.State) == 0 {
+		return fmt.Errorf("graph state %s has no data", record.ID)
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO graph_states (id, initial_thought, state, vertex_count, edge_count, active_count, terminal_count, created_at, updated_at, workspace)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			initial_thought=excluded.initial_thought,
+			state=excluded.state,
+			vertex_count=excluded.vertex_count,
+			edge_count=excluded.edge_count,
+			active_count=excluded.active_count,
+			terminal_count=excluded.terminal_count,
+			created_at=excluded.created_at,
+			updated_at=excluded.updated_at
+		WHERE graph_states.workspace = excluded.workspace
+	`, record.ID, record.InitialThought, string(record.State), record.VertexCount, record.EdgeCount,
+		record.ActiveCount, record.TerminalCount, record.CreatedAt.Unix(), record.UpdatedAt.Unix(), s.workspace)
+	if err != nil {
+		return fmt.Errorf("failed to store graph state: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("graph state %s already exists in another workspace", record.ID)
+	}
+	return nil
+}
+
+// GetGraphState loads a Graph-of-Thoughts state by ID
+func (s *SQLiteStorage) GetGraphState(id string) (*types.GraphStateRecord, error) {
+	record := &types.GraphStateRecord{}
+	var initialThought sql.NullString
+	var state string
+	var createdAt, updatedAt int64
+
+	err := s.db.QueryRow(`
+		SELECT id, initial_thought, state, vertex_count, edge_count, active_count, terminal_count, created_at, updated_at
+		FROM graph_states
+		WHERE id = ? AND workspace = ?
+	`, id, s.workspace).Scan(&record.ID, &initialThought, &state, &record.VertexCount, &record.EdgeCount,
+		&record.ActiveCount, &record.TerminalCount, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("graph state not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph state: %w", err)
+	}
+
+	record.InitialThought = initialThought.String
+	record.State = []byte(state)
+	record.CreatedAt = time.Unix(createdAt, 0)
+	record.UpdatedAt = time.Unix(updatedAt, 0)
+
+	return record, nil
+}
+
+// ListGraphStates returns the summaries of the workspace's graphs, most
+// recently updated first. A limit of zero or less returns all graphs.
+func (s *SQLiteStorage) ListGraphStates(limit, offset int) ([]*types.GraphStateSummary, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, initial_thought, vertex_count, edge_count, active_count, terminal_count, created_at, updated_at
+		FROM graph_states
+		WHERE workspace = ?
+		ORDER BY updated_at DESC, id
+		LIMIT ? OFFSET ?
+	`, s.workspace, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query graph states: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	summaries := make([]*types.GraphStateSummary, 0)
+	for rows.Next() {
+		summary := &types.GraphStateSummary{}
+		var initialThought sql.NullString
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&summary.ID, &initialThought, &summary.VertexCount, &summary.EdgeCount,
+			&summary.ActiveCount, &summary.TerminalCount, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan graph state: %w", err)
+		}
+		summary.InitialThought = initialThought.String
+		summary.CreatedAt = time.Unix(createdAt, 0)
+		summary.UpdatedAt = time.Unix(updatedAt, 0)
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// DeleteGraphState removes a Graph-of-Thoughts state
+func (s *SQLiteStorage) DeleteGraphState(id string) error {
+	result, err := s.db.Exec(`DELETE FROM graph_states WHERE id = ? AND workspace = ?`, id, s.workspace)
+	if err != nil {
+		return fmt.Errorf("failed to delete graph state: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("graph state not found: %s", id)
+	}
+	return nil
+}
+
+// DeleteGraphStatesBefore removes graph states last updated before cutoff, in
+// every workspace, and returns how many were removed
+func (s *SQLiteStorage) DeleteGraphStatesBefore(cutoff time.Time) (int, error) {
+	result, err := s.db.Exec(`DELETE FROM graph_states WHERE updated_at < ?`, cutoff.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired graph states: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+func newTestGraphStateRecord(id string, updatedAt time.Time) *types.GraphStateRecord {
+	state, _ := json.Marshal(map[string]interface{}{"id": id, "vertices": map[string]interface{}{}})
+	return &types.GraphStateRecord{
+		GraphStateSummary: types.GraphStateSummary{
+			ID:             id,
+			InitialThought: "Sort [3,1,2]",
+			VertexCount:    4,
+			EdgeCount:      3,
+			ActiveCount:    3,
+			CreatedAt:      updatedAt.Add(-time.Minute),
+			UpdatedAt:      updatedAt,
+		},
+		State: state,
+	}
+}
+
+func TestSQLiteStorage_GraphStatePersistence(t *testing.T) {
+	store, dbPath := newTestSQLiteStorage(t)
+
+	now := time.Now()
+	if err := store.StoreGraphState(newTestGraphStateRecord("graph-old", now.Add(-2*time.Hour))); err != nil {
+		t.Fatalf("StoreGraphState() error = %v", err)
+	}
+	record := newTestGraphStateRecord("graph-new", now)
+	if err := store.StoreGraphState(record); err != nil {
+		t.Fatalf("StoreGraphState() error = %v", err)
+	}
+
+	// Updating a graph replaces its state and counts
+	record.VertexCount = 7
+	if err := store.StoreGraphState(record); err != nil {
+		t.Fatalf("StoreGraphState() update error = %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("failed to reopen storage: %v", err)
+	}
+	defer reopened.Close()
+
+	got, err := reopened.GetGraphState("graph-new")
+	if err != nil {
+		t.Fatalf("GetGraphState() error = %v", err)
+	}
+	if got.VertexCount != 7 || got.InitialThought != "Sort [3,1,2]" || string(got.State) != string(record.State) {
+		t.Errorf("graph state not restored: %+v", got)
+	}
+
+	summaries, err := reopened.ListGraphStates(0, 0)
+	if err != nil {
+		t.Fatalf("ListGraphStates() error = %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].ID != "graph-new" || summaries[1].ID != "graph-old" {
+		t.Errorf("ListGraphStates() = %+v, want [graph-new graph-old]", summaries)
+	}
+
+	removed, err := reopened.DeleteGraphStatesBefore(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("DeleteGraphStatesBefore() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("DeleteGraphStatesBefore() removed %d, want 1", removed)
+	}
+	if _, err := reopened.GetGraphState("graph-old"); err == nil {
+		t.Error("expected expired graph to be removed")
+	}
+
+	if err := reopened.DeleteGraphState("graph-new"); err != nil {
+		t.Fatalf("DeleteGraphState() error = %v", err)
+	}
+	if err := reopened.DeleteGraphState("graph-new"); err == nil {
+		t.Error("expected error deleting a missing graph")
+	}
+}
+
+func TestSQLiteStorage_GraphStateWorkspaces(t *testing.T) {
+	store, _ := newTestSQLiteStorage(t)
+	defer func() { _ = store.Close() }()
+
+	alpha := store.WorkspaceView("alpha")
+	now := time.Now()
+	if err := alpha.StoreGraphState(newTestGraphStateRecord("graph-1", now)); err != nil {
+		t.Fatalf("StoreGraphState() error = %v", err)
+	}
+
+	if _, err := alpha.GetGraphState("graph-1"); err != nil {
+		t.Fatalf("GetGraphState(alpha) error = %v", err)
+	}
+	if _, err := store.GetGraphState("graph-1"); err == nil {
+		t.Error("expected graph to be hidden from the default workspace")
+	}
+	if summaries, _ := store.ListGraphStates(0, 0); len(summaries) != 0 {
+		t.Errorf("default graphs = %+v", summaries)
+	}
+	if summaries, _ := alpha.ListGraphStates(0, 0); len(summaries) != 1 {
+		t.Errorf("alpha graphs = %+v", summaries)
+	}
+	if err := store.DeleteGraphState("graph-1"); err == nil {
+		t.Error("expected error deleting a graph from another workspace")
+	}
+
+	// A graph ID cannot be taken over from another workspace
+	if err := store.StoreGraphState(newTestGraphStateRecord("graph-1", now)); err == nil {
+		t.Error("expected error storing a graph that exists in another workspace")
+	}
+
+	// Expiry applies to every workspace
+	removed, err := store.DeleteGraphStatesBefore(now.Add(time.Minute))
+	if err != nil || removed != 1 {
+		t.Errorf("DeleteGraphStatesBefore() = %d, %v; want 1", removed, err)
+	}
+}
+
+func TestSQLiteStorage_GraphStateMigration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v16.db")
+
+	// Create a v16 database whose graphs predate the workspace column
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE schema_metadata (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+		INSERT INTO schema_metadata (key, value) VALUES ('version', '16');
+		CREATE TABLE graph_states (
+			id TEXT PRIMARY KEY, initial_thought TEXT, state TEXT NOT NULL,
+			vertex_count INTEGER NOT NULL DEFAULT 0, edge_count INTEGER NOT NULL DEFAULT 0,
+			active_count INTEGER NOT NULL DEFAULT 0, terminal_count INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL, updated_at INTEGER NOT NULL
+		);
+		INSERT INTO graph_states (id, initial_thought, state, created_at, updated_at)
+		VALUES ('legacy-graph', 'Sort [3,1,2]', '{}', 1700000000, 1700000000);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create v16 schema: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage() on v16 database error = %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	if _, err := store.GetGraphState("legacy-graph"); err != nil {
+		t.Errorf("legacy graph should be in the default workspace: %v", err)
+	}
+	if _, err := store.WorkspaceView("alpha").GetGraphState("legacy-graph"); err == nil {
+		t.Error("expected legacy graph to be hidden from other workspaces")
+	}
+}
@@ -59,3 +59,9 @@ type Storage interface {
 
 // Verify MemoryStorage implements Storage interface
 var _ Storage = (*MemoryStorage)(nil)
+
+// Verify both backends support workspace scoping
+var (
+	_ WorkspaceScoped = (*MemoryStorage)(nil)
+	_ WorkspaceScoped = (*SQLiteStorage)(nil)
+)
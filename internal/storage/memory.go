@@ -69,6 +69,13 @@ type MemoryStorage struct {
 	insightCounter      int
 	validationCounter   int
 	relationshipCounter int
+
+	// Workspace partitioning: the root storage serves the default workspace and
+	// owns one child partition per additional workspace (created lazily)
+	workspace    string
+	root         *MemoryStorage
+	workspaceMu  sync.Mutex
+	workspaceMap map[string]*MemoryStorage
 }
 
 // NewMemoryStorage creates a new in-memory storage
@@ -88,7 +95,47 @@ func NewMemoryStorage() *MemoryStorage {
 		thoughtsOrdered:  make([]*types.Thought, 0, 100), // Pre-allocate typical size
 		branchesOrdered:  make([]*types.Branch, 0, 20),   // Pre-allocate typical size
 		recentBranchIDs:  make([]string, 0, MaxRecentBranches),
+		workspace:        DefaultWorkspace,
+	}
+}
+
+// Workspace returns the workspace this storage view is bound to
+func (s *MemoryStorage) Workspace() string {
+	return s.workspace
+}
+
+// ForWorkspace returns the partition for the given workspace. Partitions are
+// fully isolated: thoughts, branches, insights, the active branch and recent
+// branches are tracked separately per workspace.
+func (s *MemoryStorage) ForWorkspace(workspace string) Storage {
+	return s.workspacePartition(workspace)
+}
+
+// workspacePartition returns (creating if needed) the partition for workspace
+func (s *MemoryStorage) workspacePartition(workspace string) *MemoryStorage {
+	workspace = NormalizeWorkspace(workspace)
+	root := s
+	if s.root != nil {
+		root = s.root
+	}
+	if workspace == root.workspace {
+		return root
+	}
+
+	root.workspaceMu.Lock()
+	defer root.workspaceMu.Unlock()
+
+	if root.workspaceMap == nil {
+		root.workspaceMap = make(map[string]*MemoryStorage)
+	}
+	partition, exists := root.workspaceMap[workspace]
+	if !exists {
+		partition = NewMemoryStorage()
+		partition.workspace = workspace
+		partition.root = root
+		root.workspaceMap[workspace] = partition
 	}
+	return partition
 }
 
 // StoreThought stores a thought in memory. If the thought ID is empty, a unique ID
@@ -103,6 +150,7 @@ func (s *MemoryStorage) StoreThought(thought *types.Thought) error {
 		s.thoughtCounter++
 		thought.ID = fmt.Sprintf("thought-%d-%d", time.Now().Unix(), s.thoughtCounter)
 	}
+	thought.Workspace = s.workspace
 
 	s.thoughts[thought.ID] = thought
 
@@ -234,6 +282,7 @@ func (s *MemoryStorage) StoreBranch(branch *types.Branch) error {
 		s.branchCounter++
 		branch.ID = fmt.Sprintf("branch-%d-%d", time.Now().Unix(), s.branchCounter)
 	}
+	branch.Workspace = s.workspace
 
 	// Initialize LastAccessedAt if not set
 	if branch.LastAccessedAt.IsZero() {
@@ -358,6 +407,7 @@ func (s *MemoryStorage) StoreInsight(insight *types.Insight) error {
 		s.insightCounter++
 		insight.ID = fmt.Sprintf("insight-%d", s.insightCounter)
 	}
+	insight.Workspace = s.workspace
 
 	s.insights[insight.ID] = insight
 	return nil
@@ -138,12 +138,17 @@ func (s *SQLiteStorage) RecordRLOutcome(outcome *reinforcement.Outcome) error {
 	reasoningPathJSON, _ := json.Marshal(outcome.ReasoningPath)
 	metadataJSON, _ := json.Marshal(outcome.Metadata)
 
+	workspace := s.workspace
+	if outcome.Workspace != "" {
+		workspace = NormalizeWorkspace(outcome.Workspace)
+	}
+
 	_, err := s.db.Exec(`
 		INSERT INTO rl_strategy_outcomes (
 			strategy_id, problem_id, problem_type, problem_description,
 			success, confidence_before, confidence_after, execution_time_ns,
-			token_count, reasoning_path, timestamp, metadata
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			token_count, reasoning_path, timestamp, metadata, workspace
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		outcome.StrategyID,
 		outcome.ProblemID,
@@ -157,6 +162,7 @@ func (s *SQLiteStorage) RecordRLOutcome(outcome *reinforcement.Outcome) error {
 		string(reasoningPathJSON),
 		outcome.Timestamp,
 		string(metadataJSON),
+		workspace,
 	)
 
 	return err
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -21,6 +22,14 @@ type SQLiteStorage struct {
 	db    *sql.DB
 	cache *MemoryStorage // Write-through cache for fast reads
 
+	// Workspace scoping: the root storage serves the default workspace; views
+	// for other workspaces share the database and prepared statements but keep
+	// their own cache (and therefore their own active branch)
+	workspace      string
+	root           *SQLiteStorage
+	workspaceMu    sync.Mutex
+	workspaceViews map[string]*SQLiteStorage
+
 	// Removed redundant activeBranchID mutex - delegated to cache
 	// This eliminates double lock acquisition for active branch operations
 	thoughtCounter atomic.Int64
@@ -85,8 +94,9 @@ func NewSQLiteStorage(dbPath string, timeoutMs int) (*SQLiteStorage, error) {
 	}
 
 	s := &SQLiteStorage{
-		db:    db,
-		cache: NewMemoryStorage(),
+		db:        db,
+		cache:     NewMemoryStorage(),
+		workspace: DefaultWorkspace,
 	}
 
 	// Prepare statements
@@ -111,12 +121,13 @@ func (s *SQLiteStorage) prepareStatements() error {
 	s.stmtInsertThought, err = s.db.Prepare(`
 		INSERT INTO thoughts (
 			id, content, mode, branch_id, parent_id, type, confidence,
-			timestamp, key_points, metadata, is_rebellion, challenges_assumption
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			timestamp, key_points, metadata, is_rebellion, challenges_assumption, workspace
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			content=excluded.content,
 			confidence=excluded.confidence,
 			timestamp=excluded.timestamp
+		WHERE thoughts.workspace = excluded.workspace
 	`)
 	if err != nil {
 		return fmt.Errorf("prepare insert thought: %w", err)
@@ -125,7 +136,7 @@ func (s *SQLiteStorage) prepareStatements() error {
 	s.stmtGetThought, err = s.db.Prepare(`
 		SELECT id, content, mode, branch_id, parent_id, type, confidence,
 		       timestamp, key_points, metadata, is_rebellion, challenges_assumption
-		FROM thoughts WHERE id = ?
+		FROM thoughts WHERE id = ? AND workspace = ?
 	`)
 	if err != nil {
 		return fmt.Errorf("prepare get thought: %w", err)
@@ -137,7 +148,7 @@ func (s *SQLiteStorage) prepareStatements() error {
 		       t.is_rebellion, t.challenges_assumption
 		FROM thoughts_fts fts
 		JOIN thoughts t ON t.rowid = fts.rowid
-		WHERE fts.content MATCH ? AND (? = '' OR t.mode = ?)
+		WHERE fts.content MATCH ? AND (? = '' OR t.mode = ?) AND t.workspace = ?
 		ORDER BY t.timestamp DESC
 		LIMIT ? OFFSET ?
 	`)
@@ -148,13 +159,14 @@ func (s *SQLiteStorage) prepareStatements() error {
 	s.stmtInsertBranch, err = s.db.Prepare(`
 		INSERT INTO branches (
 			id, parent_branch_id, state, priority, confidence,
-			created_at, updated_at, last_accessed_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			created_at, updated_at, last_accessed_at, workspace
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			state=excluded.state,
 			priority=excluded.priority,
 			confidence=excluded.confidence,
 			updated_at=excluded.updated_at
+		WHERE branches.workspace = excluded.workspace
 	`)
 	if err != nil {
 		return fmt.Errorf("prepare insert branch: %w", err)
@@ -163,14 +175,14 @@ func (s *SQLiteStorage) prepareStatements() error {
 	s.stmtGetBranch, err = s.db.Prepare(`
 		SELECT id, parent_branch_id, state, priority, confidence,
 		       created_at, updated_at, last_accessed_at
-		FROM branches WHERE id = ?
+		FROM branches WHERE id = ? AND workspace = ?
 	`)
 	if err != nil {
 		return fmt.Errorf("prepare get branch: %w", err)
 	}
 
 	s.stmtUpdateBranchAccess, err = s.db.Prepare(`
-		UPDATE branches SET last_accessed_at = ? WHERE id = ?
+		UPDATE branches SET last_accessed_at = ? WHERE id = ? AND workspace = ?
 	`)
 	if err != nil {
 		return fmt.Errorf("prepare update branch access: %w", err)
@@ -179,8 +191,8 @@ func (s *SQLiteStorage) prepareStatements() error {
 	s.stmtInsertInsight, err = s.db.Prepare(`
 		INSERT INTO insights (
 			id, type, content, context, parent_insights,
-			applicability_score, supporting_evidence, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			applicability_score, supporting_evidence, created_at, workspace
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return fmt.Errorf("prepare insert insight: %w", err)
@@ -214,9 +226,10 @@ func (s *SQLiteStorage) warmCache() error {
 		SELECT id, content, mode, branch_id, parent_id, type, confidence,
 		       timestamp, key_points, metadata, is_rebellion, challenges_assumption
 		FROM thoughts
+		WHERE workspace = ?
 		ORDER BY timestamp DESC
 		LIMIT 1000
-	`)
+	`, s.workspace)
 	if err != nil {
 		return fmt.Errorf("failed to query thoughts: %w", err)
 	}
@@ -233,7 +246,7 @@ func (s *SQLiteStorage) warmCache() error {
 		}
 	}
 
-	log.Printf("Warmed cache with %d thoughts", len(s.cache.thoughts))
+	log.Printf("Warmed cache with %d thoughts (workspace: %s)", len(s.cache.thoughts), s.workspace)
 	return nil
 }
 
@@ -241,9 +254,10 @@ func (s *SQLiteStorage) warmCache() error {
 func (s *SQLiteStorage) StoreThought(thought *types.Thought) error {
 	// Generate ID if needed
 	if thought.ID == "" {
-		counter := s.thoughtCounter.Add(1)
+		counter := s.base().thoughtCounter.Add(1)
 		thought.ID = fmt.Sprintf("thought-%d-%d", time.Now().Unix(), counter)
 	}
+	thought.Workspace = s.workspace
 
 	// Marshal JSON fields
 	keyPointsJSON, _ := json.Marshal(thought.KeyPoints)
@@ -259,15 +273,19 @@ func (s *SQLiteStorage) StoreThought(thought *types.Thought) error {
 	}
 
 	// Write to database
-	_, err := s.stmtInsertThought.Exec(
+	result, err := s.stmtInsertThought.Exec(
 		thought.ID, thought.Content, thought.Mode, branchID, parentID,
 		thought.Type, thought.Confidence, thought.Timestamp.Unix(),
 		keyPointsJSON, metadataJSON,
 		boolToInt(thought.IsRebellion), boolToInt(thought.ChallengesAssumption),
+		s.workspace,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert thought: %w", err)
 	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("thought %s already exists in another workspace", thought.ID)
+	}
 
 	// Update cache
 	return s.cache.StoreThought(thought)
@@ -301,7 +319,7 @@ func (s *SQLiteStorage) fetchThought(id string) (*types.Thought, error) {
 	var isRebellion, challengesAssumption int
 	var timestamp int64
 
-	err := s.stmtGetThought.QueryRow(id).Scan(
+	err := s.stmtGetThought.QueryRow(id, s.workspace).Scan(
 		&thought.ID, &thought.Content, &thought.Mode, &branchID,
 		&parentID, &thought.Type, &thought.Confidence, &timestamp,
 		&keyPointsJSON, &metadataJSON, &isRebellion, &challengesAssumption,
@@ -322,6 +340,7 @@ func (s *SQLiteStorage) fetchThought(id string) (*types.Thought, error) {
 	thought.Timestamp = time.Unix(timestamp, 0)
 	thought.IsRebellion = isRebellion == 1
 	thought.ChallengesAssumption = challengesAssumption == 1
+	thought.Workspace = s.workspace
 
 	if len(keyPointsJSON) > 0 {
 		if err := json.Unmarshal(keyPointsJSON, &thought.KeyPoints); err != nil {
@@ -366,6 +385,7 @@ func (s *SQLiteStorage) scanThought(row interface{ Scan(...interface{}) error })
 	thought.Timestamp = time.Unix(timestamp, 0)
 	thought.IsRebellion = isRebellion == 1
 	thought.ChallengesAssumption = challengesAssumption == 1
+	thought.Workspace = s.workspace
 
 	if len(keyPointsJSON) > 0 {
 		if err := json.Unmarshal(keyPointsJSON, &thought.KeyPoints); err != nil {
@@ -454,7 +474,7 @@ func (s *SQLiteStorage) SearchThoughts(query string, mode types.ThinkingMode, li
 // searchThoughtsFTS performs full-text search using SQLite FTS5
 func (s *SQLiteStorage) searchThoughtsFTS(query string, mode types.ThinkingMode, limit, offset int) []*types.Thought {
 	modeStr := string(mode)
-	rows, err := s.stmtSearchFTS.Query(query, modeStr, modeStr, limit, offset)
+	rows, err := s.stmtSearchFTS.Query(query, modeStr, modeStr, s.workspace, limit, offset)
 	if err != nil {
 		log.Printf("FTS search error: %v", err)
 		return nil
@@ -478,9 +498,10 @@ func (s *SQLiteStorage) searchThoughtsFTS(query string, mode types.ThinkingMode,
 func (s *SQLiteStorage) StoreBranch(branch *types.Branch) error {
 	// Generate ID if needed
 	if branch.ID == "" {
-		counter := s.branchCounter.Add(1)
+		counter := s.base().branchCounter.Add(1)
 		branch.ID = fmt.Sprintf("branch-%d-%d", time.Now().Unix(), counter)
 	}
+	branch.Workspace = s.workspace
 
 	// Handle optional foreign key - convert empty string to NULL
 	var parentBranchID interface{}
@@ -489,13 +510,17 @@ func (s *SQLiteStorage) StoreBranch(branch *types.Branch) error {
 	}
 
 	// Write to database
-	_, err := s.stmtInsertBranch.Exec(
+	result, err := s.stmtInsertBranch.Exec(
 		branch.ID, parentBranchID, branch.State, branch.Priority, branch.Confidence,
 		branch.CreatedAt.Unix(), branch.UpdatedAt.Unix(), branch.LastAccessedAt.Unix(),
+		s.workspace,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert branch: %w", err)
 	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("branch %s already exists in another workspace", branch.ID)
+	}
 
 	// Update cache
 	return s.cache.StoreBranch(branch)
@@ -513,7 +538,7 @@ func (s *SQLiteStorage) GetBranch(id string) (*types.Branch, error) {
 	var parentBranchID sql.NullString
 	var createdAt, updatedAt, lastAccessedAt int64
 
-	err := s.stmtGetBranch.QueryRow(id).Scan(
+	err := s.stmtGetBranch.QueryRow(id, s.workspace).Scan(
 		&branch.ID, &parentBranchID, &branch.State, &branch.Priority, &branch.Confidence,
 		&createdAt, &updatedAt, &lastAccessedAt,
 	)
@@ -530,6 +555,7 @@ func (s *SQLiteStorage) GetBranch(id string) (*types.Branch, error) {
 	branch.CreatedAt = time.Unix(createdAt, 0)
 	branch.UpdatedAt = time.Unix(updatedAt, 0)
 	branch.LastAccessedAt = time.Unix(lastAccessedAt, 0)
+	branch.Workspace = s.workspace
 
 	// Load associated data
 	thoughts, err := s.loadBranchThoughts(id)
@@ -566,9 +592,9 @@ func (s *SQLiteStorage) loadBranchThoughts(branchID string) ([]*types.Thought, e
 		SELECT id, content, mode, branch_id, parent_id, type, confidence,
 		       timestamp, key_points, metadata, is_rebellion, challenges_assumption
 		FROM thoughts
-		WHERE branch_id = ?
+		WHERE branch_id = ? AND workspace = ?
 		ORDER BY timestamp ASC
-	`, branchID)
+	`, branchID, s.workspace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query branch thoughts: %w", err)
 	}
@@ -593,9 +619,9 @@ func (s *SQLiteStorage) loadBranchInsights(branchID string) ([]*types.Insight, e
 		SELECT id, type, content, context, applicability_score,
 		       parent_insights, supporting_evidence, created_at
 		FROM insights
-		WHERE branch_id = ?
+		WHERE branch_id = ? AND workspace = ?
 		ORDER BY created_at ASC
-	`, branchID)
+	`, branchID, s.workspace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query branch insights: %w", err)
 	}
@@ -675,7 +701,7 @@ func (s *SQLiteStorage) SetActiveBranch(branchID string) error {
 // UpdateBranchAccess updates last accessed time
 func (s *SQLiteStorage) UpdateBranchAccess(branchID string) error {
 	now := time.Now()
-	_, err := s.stmtUpdateBranchAccess.Exec(now.Unix(), branchID)
+	_, err := s.stmtUpdateBranchAccess.Exec(now.Unix(), branchID, s.workspace)
 	if err != nil {
 		return fmt.Errorf("failed to update branch access: %w", err)
 	}
@@ -688,9 +714,10 @@ func (s *SQLiteStorage) UpdateBranchAccess(branchID string) error {
 func (s *SQLiteStorage) StoreInsight(insight *types.Insight) error {
 	// Generate ID if needed
 	if insight.ID == "" {
-		counter := s.thoughtCounter.Add(1) // Reuse counter for simplicity
+		counter := s.base().thoughtCounter.Add(1) // Reuse counter for simplicity
 		insight.ID = fmt.Sprintf("insight-%d-%d", time.Now().Unix(), counter)
 	}
+	insight.Workspace = s.workspace
 
 	// Marshal JSON fields
 	contextJSON, _ := json.Marshal(insight.Context)
@@ -700,10 +727,10 @@ func (s *SQLiteStorage) StoreInsight(insight *types.Insight) error {
 	// Write to database (branch_id is NULL for now, set via AppendInsightToBranch)
 	_, err := s.db.Exec(`
 		INSERT OR REPLACE INTO insights (id, branch_id, type, content, context, parent_insights,
-		                                  applicability_score, supporting_evidence, created_at)
-		VALUES (?, NULL, ?, ?, ?, ?, ?, ?, ?)
+		                                  applicability_score, supporting_evidence, created_at, workspace)
+		VALUES (?, NULL, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, insight.ID, insight.Type, insight.Content, contextJSON, parentInsightsJSON,
-		insight.ApplicabilityScore, supportingEvidenceJSON, insight.CreatedAt.Unix())
+		insight.ApplicabilityScore, supportingEvidenceJSON, insight.CreatedAt.Unix(), s.workspace)
 
 	if err != nil {
 		return fmt.Errorf("failed to insert insight: %w", err)
@@ -728,8 +755,8 @@ func (s *SQLiteStorage) GetInsight(id string) (*types.Insight, error) {
 		SELECT id, type, content, context, parent_insights, applicability_score,
 		       supporting_evidence, created_at
 		FROM insights
-		WHERE id = ?
-	`, id).Scan(&insight.ID, &insight.Type, &insight.Content, &contextJSON, &parentInsightsJSON,
+		WHERE id = ? AND workspace = ?
+	`, id, s.workspace).Scan(&insight.ID, &insight.Type, &insight.Content, &contextJSON, &parentInsightsJSON,
 		&insight.ApplicabilityScore, &supportingEvidenceJSON, &createdAt)
 
 	if err == sql.ErrNoRows {
@@ -740,6 +767,7 @@ func (s *SQLiteStorage) GetInsight(id string) (*types.Insight, error) {
 	}
 
 	insight.CreatedAt = time.Unix(createdAt, 0)
+	insight.Workspace = s.workspace
 
 	// Unmarshal JSON fields
 	if len(contextJSON) > 0 {
@@ -779,7 +807,7 @@ func (s *SQLiteStorage) GetInsight(id string) (*types.Insight, error) {
 func (s *SQLiteStorage) StoreValidation(validation *types.Validation) error {
 	// Generate ID if needed
 	if validation.ID == "" {
-		counter := s.thoughtCounter.Add(1)
+		counter := s.base().thoughtCounter.Add(1)
 		validation.ID = fmt.Sprintf("validation-%d-%d", time.Now().Unix(), counter)
 	}
 
@@ -850,7 +878,7 @@ func (s *SQLiteStorage) GetValidation(id string) (*types.Validation, error) {
 func (s *SQLiteStorage) StoreRelationship(rel *types.Relationship) error {
 	// Generate ID if needed
 	if rel.ID == "" {
-		counter := s.thoughtCounter.Add(1)
+		counter := s.base().thoughtCounter.Add(1)
 		rel.ID = fmt.Sprintf("relationship-%d-%d", time.Now().Unix(), counter)
 	}
 
@@ -924,8 +952,8 @@ func (s *SQLiteStorage) AppendThoughtToBranch(branchID string, thought *types.Th
 func (s *SQLiteStorage) AppendInsightToBranch(branchID string, insight *types.Insight) error {
 	// Update database to set branch_id for this insight
 	_, err := s.db.Exec(`
-		UPDATE insights SET branch_id = ? WHERE id = ?
-	`, branchID, insight.ID)
+		UPDATE insights SET branch_id = ? WHERE id = ? AND workspace = ?
+	`, branchID, insight.ID, s.workspace)
 
 	if err != nil {
 		return fmt.Errorf("failed to update insight branch_id: %w", err)
@@ -944,8 +972,8 @@ func (s *SQLiteStorage) AppendCrossRefToBranch(branchID string, crossRef *types.
 func (s *SQLiteStorage) UpdateBranchPriority(branchID string, priority float64) error {
 	// Update database
 	_, err := s.db.Exec(`
-		UPDATE branches SET priority = ?, updated_at = ? WHERE id = ?
-	`, priority, time.Now().Unix(), branchID)
+		UPDATE branches SET priority = ?, updated_at = ? WHERE id = ? AND workspace = ?
+	`, priority, time.Now().Unix(), branchID, s.workspace)
 
 	if err != nil {
 		return fmt.Errorf("failed to update branch priority: %w", err)
@@ -958,8 +986,8 @@ func (s *SQLiteStorage) UpdateBranchPriority(branchID string, priority float64)
 func (s *SQLiteStorage) UpdateBranchConfidence(branchID string, confidence float64) error {
 	// Update database
 	_, err := s.db.Exec(`
-		UPDATE branches SET confidence = ?, updated_at = ? WHERE id = ?
-	`, confidence, time.Now().Unix(), branchID)
+		UPDATE branches SET confidence = ?, updated_at = ? WHERE id = ? AND workspace = ?
+	`, confidence, time.Now().Unix(), branchID, s.workspace)
 
 	if err != nil {
 		return fmt.Errorf("failed to update branch confidence: %w", err)
@@ -975,9 +1003,10 @@ func (s *SQLiteStorage) GetRecentBranches() ([]*types.Branch, error) {
 		SELECT id, parent_branch_id, state, priority, confidence,
 		       created_at, updated_at, last_accessed_at
 		FROM branches
+		WHERE workspace = ?
 		ORDER BY last_accessed_at DESC
 		LIMIT ?
-	`, MaxRecentBranches)
+	`, s.workspace, MaxRecentBranches)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query recent branches: %w", err)
 	}
@@ -1004,6 +1033,7 @@ func (s *SQLiteStorage) GetRecentBranches() ([]*types.Branch, error) {
 		branch.CreatedAt = time.Unix(createdAt, 0)
 		branch.UpdatedAt = time.Unix(updatedAt, 0)
 		branch.LastAccessedAt = time.Unix(lastAccessedAt, 0)
+		branch.Workspace = s.workspace
 
 		// Load associated data
 		thoughts, _ := s.loadBranchThoughts(branch.ID)
@@ -1133,6 +1163,7 @@ type ContextSignature struct {
 	ToolSequence []string
 	Complexity   float64
 	Embedding    []float32 // Semantic embedding for similarity matching
+	Workspace    string    // Workspace the trajectory belongs to (defaults to the storage view's workspace)
 }
 
 // CandidateWithSignature combines trajectory metadata with its signature
@@ -1161,11 +1192,16 @@ func (s *SQLiteStorage) StoreContextSignature(trajectoryID string, sig *ContextS
 		embeddingBlob = embeddings.SerializeFloat32(sig.Embedding)
 	}
 
+	workspace := s.workspace
+	if sig.Workspace != "" {
+		workspace = NormalizeWorkspace(sig.Workspace)
+	}
+
 	_, err := s.db.Exec(`
 		INSERT INTO context_signatures
-		(trajectory_id, fingerprint, fingerprint_prefix, domain, key_concepts, tool_sequence, complexity, embedding)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, trajectoryID, sig.Fingerprint, prefix, sig.Domain, concepts, tools, sig.Complexity, embeddingBlob)
+		(trajectory_id, fingerprint, fingerprint_prefix, domain, key_concepts, tool_sequence, complexity, embedding, workspace)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, trajectoryID, sig.Fingerprint, prefix, sig.Domain, concepts, tools, sig.Complexity, embeddingBlob, workspace)
 
 	if err != nil {
 		return fmt.Errorf("failed to store context signature: %w", err)
@@ -1193,12 +1229,13 @@ func (s *SQLiteStorage) FindCandidatesWithSignatures(domain string, fingerprintP
 				cs.complexity,
 				cs.embedding
 			FROM context_signatures cs
+			WHERE cs.workspace = ?
 			ORDER BY
 				CASE WHEN cs.domain = ? THEN 0 ELSE 1 END,
 				cs.created_at DESC
 			LIMIT ?
 		`
-		args = []interface{}{domain, limit}
+		args = []interface{}{s.workspace, domain, limit}
 	} else {
 		// No domain filter, return most recent
 		query = `
@@ -1211,10 +1248,11 @@ func (s *SQLiteStorage) FindCandidatesWithSignatures(domain string, fingerprintP
 				cs.complexity,
 				cs.embedding
 			FROM context_signatures cs
+			WHERE cs.workspace = ?
 			ORDER BY cs.created_at DESC
 			LIMIT ?
 		`
-		args = []interface{}{limit}
+		args = []interface{}{s.workspace, limit}
 	}
 
 	rows, err := s.db.Query(query, args...)
@@ -1249,6 +1287,7 @@ func (s *SQLiteStorage) FindCandidatesWithSignatures(domain string, fingerprintP
 			TrajectoryID: trajectoryID,
 			Fingerprint:  fingerprint,
 			Complexity:   complexity,
+			Workspace:    s.workspace,
 		}
 
 		if domainVal.Valid {
@@ -1443,10 +1482,12 @@ func (s *SQLiteStorage) UpdateSignatureEmbedding(trajectoryID string, embedding
 
 // StoreTrajectoryJSON stores a reasoning trajectory as JSON in SQLite
 func (s *SQLiteStorage) StoreTrajectoryJSON(id string, trajectoryJSON string) error {
+	// The workspace column mirrors the trajectory's own "workspace" field so
+	// trajectories can be queried per workspace without decoding the JSON
 	_, err := s.db.Exec(`
-		INSERT INTO trajectories (id, trajectory_json, created_at)
-		VALUES (?, ?, ?)
-	`, id, trajectoryJSON, time.Now().Unix())
+		INSERT INTO trajectories (id, trajectory_json, created_at, workspace)
+		VALUES (?, ?, ?, COALESCE(NULLIF(json_extract(?, '$.workspace'), ''), ?))
+	`, id, trajectoryJSON, time.Now().Unix(), trajectoryJSON, DefaultWorkspace)
 
 	return err
 }
@@ -1496,13 +1537,80 @@ func (s *SQLiteStorage) GetAllTrajectoriesJSON() (map[string]string, error) {
 	return result, rows.Err()
 }
 
+// Workspace returns the workspace this storage view is bound to
+func (s *SQLiteStorage) Workspace() string {
+	return s.workspace
+}
+
+// ForWorkspace returns a view bound to the given workspace
+func (s *SQLiteStorage) ForWorkspace(workspace string) Storage {
+	return s.WorkspaceView(workspace)
+}
+
+// WorkspaceView returns (creating if needed) the SQLite view for a workspace.
+// Views share the database handle and prepared statements with the root
+// storage; each has its own write-through cache warmed from that workspace.
+func (s *SQLiteStorage) WorkspaceView(workspace string) *SQLiteStorage {
+	workspace = NormalizeWorkspace(workspace)
+	root := s.base()
+	if workspace == root.workspace {
+		return root
+	}
+
+	root.workspaceMu.Lock()
+	defer root.workspaceMu.Unlock()
+
+	if root.workspaceViews == nil {
+		root.workspaceViews = make(map[string]*SQLiteStorage)
+	}
+	if view, exists := root.workspaceViews[workspace]; exists {
+		return view
+	}
+
+	view := &SQLiteStorage{
+		db:                     root.db,
+		cache:                  NewMemoryStorage(),
+		workspace:              workspace,
+		root:                   root,
+		stmtInsertThought:      root.stmtInsertThought,
+		stmtGetThought:         root.stmtGetThought,
+		stmtSearchFTS:          root.stmtSearchFTS,
+		stmtInsertBranch:       root.stmtInsertBranch,
+		stmtGetBranch:          root.stmtGetBranch,
+		stmtUpdateBranchAccess: root.stmtUpdateBranchAccess,
+		stmtInsertInsight:      root.stmtInsertInsight,
+		stmtInsertCrossRef:     root.stmtInsertCrossRef,
+		stmtInsertValidation:   root.stmtInsertValidation,
+	}
+	view.cache.workspace = workspace
+	if err := view.warmCache(); err != nil {
+		log.Printf("Warning: failed to warm cache for workspace %s: %v", workspace, err)
+	}
+
+	root.workspaceViews[workspace] = view
+	return view
+}
+
+// base returns the root storage that owns ID counters and workspace views
+func (s *SQLiteStorage) base() *SQLiteStorage {
+	if s.root != nil {
+		return s.root
+	}
+	return s
+}
+
 // DB returns the underlying *sql.DB for advanced operations (e.g., knowledge graph embedding cache)
 func (s *SQLiteStorage) DB() *sql.DB {
 	return s.db
 }
 
-// Close releases database resources
+// Close releases database resources. Closing a workspace view closes the
+// shared root storage.
 func (s *SQLiteStorage) Close() error {
+	if s.root != nil {
+		return s.root.Close()
+	}
+
 	// Close prepared statements (ignore errors in cleanup)
 	if s.stmtInsertThought != nil {
 		_ = s.stmtInsertThought.Close()
@@ -6,7 +6,7 @@ import (
 	"fmt"
 )
 
-const schemaVersion = 8 // Updated to add entity embedding cache for knowledge graphs
+const schemaVersion = 17 // Updated to partition Graph-of-Thoughts states by workspace
 
 // Schema defines the complete database schema
 const schema = `
@@ -26,6 +26,7 @@ CREATE TABLE IF NOT EXISTS branches (
     created_at INTEGER NOT NULL,
     updated_at INTEGER NOT NULL,
     last_accessed_at INTEGER NOT NULL,
+    workspace TEXT NOT NULL DEFAULT 'default',
     FOREIGN KEY (parent_branch_id) REFERENCES branches(id) ON DELETE SET NULL
 );
 
@@ -43,6 +44,7 @@ CREATE TABLE IF NOT EXISTS thoughts (
     metadata TEXT,
     is_rebellion INTEGER DEFAULT 0,
     challenges_assumption INTEGER DEFAULT 0,
+    workspace TEXT NOT NULL DEFAULT 'default',
     FOREIGN KEY (branch_id) REFERENCES branches(id) ON DELETE SET NULL
 );
 
@@ -57,6 +59,7 @@ CREATE TABLE IF NOT EXISTS insights (
     applicability_score REAL NOT NULL,
     supporting_evidence TEXT,
     created_at INTEGER NOT NULL,
+    workspace TEXT NOT NULL DEFAULT 'default',
     FOREIGN KEY (branch_id) REFERENCES branches(id) ON DELETE SET NULL
 );
 
@@ -124,7 +127,8 @@ CREATE TABLE IF NOT EXISTS context_signatures (
     tool_sequence TEXT,     -- JSON array
     complexity REAL,
     embedding BLOB,         -- Semantic embedding for similarity (serialized float32 vector)
-    created_at INTEGER DEFAULT (strftime('%s', 'now'))
+    created_at INTEGER DEFAULT (strftime('%s', 'now')),
+    workspace TEXT NOT NULL DEFAULT 'default'
 );
 
 -- Indexes for context signature lookups
@@ -137,7 +141,8 @@ CREATE INDEX IF NOT EXISTS idx_context_trajectory ON context_signatures(trajecto
 CREATE TABLE IF NOT EXISTS trajectories (
     id TEXT PRIMARY KEY,
     trajectory_json TEXT NOT NULL,   -- Complete ReasoningTrajectory as JSON
-    created_at INTEGER NOT NULL
+    created_at INTEGER NOT NULL,
+    workspace TEXT NOT NULL DEFAULT 'default'
 );
 
 -- Index for retrieval
@@ -170,6 +175,7 @@ CREATE TABLE IF NOT EXISTS rl_strategy_outcomes (
     reasoning_path TEXT,
     timestamp INTEGER NOT NULL,
     metadata TEXT,
+    workspace TEXT NOT NULL DEFAULT 'default',
     FOREIGN KEY (strategy_id) REFERENCES rl_strategies(id)
 );
 
@@ -243,6 +249,207 @@ CREATE TABLE IF NOT EXISTS entity_embeddings (
 CREATE INDEX IF NOT EXISTS idx_entity_embeddings_type ON entity_embeddings(entity_type);
 CREATE INDEX IF NOT EXISTS idx_entity_embeddings_created ON entity_embeddings(created_at DESC);
 
+-- Causal graphs built by the causal reasoner
+CREATE TABLE IF NOT EXISTS causal_graphs (
+    id TEXT PRIMARY KEY,
+    description TEXT NOT NULL,
+    metadata TEXT,
+    created_at INTEGER NOT NULL,
+    workspace TEXT NOT NULL DEFAULT 'default'
+);
+
+-- Causal variables (nodes), ordered by position within a graph
+CREATE TABLE IF NOT EXISTS causal_variables (
+    graph_id TEXT NOT NULL,
+    id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    type TEXT NOT NULL,
+    observable INTEGER NOT NULL DEFAULT 1,
+    metadata TEXT,
+    PRIMARY KEY (graph_id, id),
+    FOREIGN KEY (graph_id) REFERENCES causal_graphs(id) ON DELETE CASCADE
+);
+
+-- Causal links (edges), ordered by position within a graph
+CREATE TABLE IF NOT EXISTS causal_links (
+    graph_id TEXT NOT NULL,
+    id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    from_variable TEXT NOT NULL,
+    to_variable TEXT NOT NULL,
+    strength REAL NOT NULL,
+    type TEXT NOT NULL,
+    confidence REAL NOT NULL,
+    evidence TEXT,          -- JSON array
+    metadata TEXT,
+    PRIMARY KEY (graph_id, id),
+    FOREIGN KEY (graph_id) REFERENCES causal_graphs(id) ON DELETE CASCADE
+);
+
+-- Simulated interventions on causal graphs
+CREATE TABLE IF NOT EXISTS causal_interventions (
+    id TEXT PRIMARY KEY,
+    graph_id TEXT NOT NULL,
+    variable TEXT NOT NULL,
+    intervention_type TEXT NOT NULL,
+    intervention_value TEXT,
+    predicted_effects TEXT, -- JSON array of PredictedEffect
+    confidence REAL NOT NULL,
+    metadata TEXT,
+    created_at INTEGER NOT NULL,
+    FOREIGN KEY (graph_id) REFERENCES causal_graphs(id) ON DELETE CASCADE
+);
+
+-- Counterfactual scenarios generated from causal graphs
+CREATE TABLE IF NOT EXISTS causal_counterfactuals (
+    id TEXT PRIMARY KEY,
+    graph_id TEXT NOT NULL,
+    scenario TEXT NOT NULL,
+    changes TEXT,           -- JSON object
+    outcomes TEXT,          -- JSON object
+    plausibility REAL NOT NULL,
+    metadata TEXT,
+    created_at INTEGER NOT NULL,
+    FOREIGN KEY (graph_id) REFERENCES causal_graphs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_causal_graphs_created ON causal_graphs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_causal_interventions_graph ON causal_interventions(graph_id);
+CREATE INDEX IF NOT EXISTS idx_causal_counterfactuals_graph ON causal_counterfactuals(graph_id);
+
+-- Multi-criteria decisions; options and criteria are stored as JSON documents
+CREATE TABLE IF NOT EXISTS decisions (
+    id TEXT PRIMARY KEY,
+    question TEXT NOT NULL,
+    options TEXT NOT NULL,      -- JSON array of DecisionOption
+    criteria TEXT NOT NULL,     -- JSON array of DecisionCriterion
+    recommendation TEXT,
+    confidence REAL NOT NULL,
+    metadata TEXT,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    workspace TEXT NOT NULL DEFAULT 'default'
+);
+
+-- Bayesian beliefs and the evidence applied to them
+CREATE TABLE IF NOT EXISTS probabilistic_beliefs (
+    id TEXT PRIMARY KEY,
+    statement TEXT NOT NULL,
+    probability REAL NOT NULL,
+    prior_prob REAL NOT NULL,
+    evidence TEXT,              -- JSON array of evidence IDs
+    metadata TEXT,
+    updated_at INTEGER NOT NULL,
+    workspace TEXT NOT NULL DEFAULT 'default'
+);
+
+-- Problem decompositions; subproblems and dependencies are stored as JSON documents
+CREATE TABLE IF NOT EXISTS problem_decompositions (
+    id TEXT PRIMARY KEY,
+    problem TEXT NOT NULL,
+    subproblems TEXT NOT NULL,  -- JSON array of Subproblem
+    dependencies TEXT,          -- JSON array of Dependency
+    solution_path TEXT,         -- JSON array of subproblem IDs
+    metadata TEXT,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    workspace TEXT NOT NULL DEFAULT 'default'
+);
+
+CREATE INDEX IF NOT EXISTS idx_decisions_created ON decisions(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_beliefs_updated ON probabilistic_beliefs(updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_decompositions_created ON problem_decompositions(created_at DESC);
+
+-- Backtracking checkpoints; each points at a snapshot plus a number of deltas
+CREATE TABLE IF NOT EXISTS checkpoints (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT,
+    branch_id TEXT NOT NULL,
+    snapshot_id TEXT NOT NULL,
+    delta_count INTEGER NOT NULL DEFAULT 0,
+    metadata TEXT,
+    created_at INTEGER NOT NULL,
+    workspace TEXT NOT NULL DEFAULT 'default'
+);
+
+-- Full branch states captured for checkpoint restoration
+CREATE TABLE IF NOT EXISTS branch_snapshots (
+    id TEXT PRIMARY KEY,
+    branch_id TEXT NOT NULL,
+    branch TEXT NOT NULL,       -- JSON encoded Branch
+    thought_count INTEGER NOT NULL DEFAULT 0,
+    insight_count INTEGER NOT NULL DEFAULT 0,
+    cross_ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL
+);
+
+-- Changes recorded on top of a snapshot, replayed in position order
+CREATE TABLE IF NOT EXISTS branch_deltas (
+    id TEXT PRIMARY KEY,
+    snapshot_id TEXT NOT NULL,
+    branch_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    operation TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    entity TEXT,                -- JSON encoded Thought, Insight or CrossRef
+    created_at INTEGER NOT NULL,
+    FOREIGN KEY (snapshot_id) REFERENCES branch_snapshots(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkpoints_branch ON checkpoints(branch_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_branch_snapshots_branch ON branch_snapshots(branch_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_branch_deltas_snapshot ON branch_deltas(snapshot_id, position);
+
+-- Graph-of-Thoughts states; the full graph is stored as a JSON document
+CREATE TABLE IF NOT EXISTS graph_states (
+    id TEXT PRIMARY KEY,
+    workspace TEXT NOT NULL DEFAULT 'default',
+    initial_thought TEXT,
+    state TEXT NOT NULL,        -- JSON encoded GraphState
+    vertex_count INTEGER NOT NULL DEFAULT 0,
+    edge_count INTEGER NOT NULL DEFAULT 0,
+    active_count INTEGER NOT NULL DEFAULT 0,
+    terminal_count INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_graph_states_updated ON graph_states(updated_at DESC);
+
+-- Orchestration workflow definitions; every change is stored as a new version
+CREATE TABLE IF NOT EXISTS workflow_versions (
+    workflow_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    source TEXT NOT NULL,       -- "tool" or the file the definition was loaded from
+    checksum TEXT NOT NULL,     -- SHA-256 of the definition
+    definition TEXT NOT NULL,   -- JSON encoded Workflow
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (workflow_id, version)
+);
+
+-- Orchestration workflow runs; the full run is stored as a JSON document
+CREATE TABLE IF NOT EXISTS workflow_runs (
+    id TEXT PRIMARY KEY,
+    workspace TEXT NOT NULL DEFAULT 'default',
+    workflow_id TEXT NOT NULL,
+    workflow_version INTEGER NOT NULL DEFAULT 0,
+    trigger TEXT NOT NULL,      -- "execute", "resume" or "rerun"
+    parent_run_id TEXT,         -- Run resumed or re-run
+    status TEXT NOT NULL,
+    error TEXT,
+    run TEXT NOT NULL,          -- JSON encoded WorkflowRun
+    started_at INTEGER NOT NULL,
+    finished_at INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_runs_workflow ON workflow_runs(workflow_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_workflow_runs_started ON workflow_runs(started_at DESC);
+
 -- Performance indexes
 CREATE INDEX IF NOT EXISTS idx_thoughts_mode ON thoughts(mode);
 CREATE INDEX IF NOT EXISTS idx_thoughts_branch ON thoughts(branch_id) WHERE branch_id IS NOT NULL;
@@ -254,6 +461,34 @@ CREATE INDEX IF NOT EXISTS idx_crossrefs_from ON cross_refs(from_branch);
 CREATE INDEX IF NOT EXISTS idx_crossrefs_to ON cross_refs(to_branch);
 `
 
+// workspaceTables lists the tables partitioned by workspace (v9)
+var workspaceTables = []string{
+	"branches",
+	"thoughts",
+	"insights",
+	"context_signatures",
+	"trajectories",
+	"rl_strategy_outcomes",
+}
+
+// workspaceIndexes are created after migrations, since the workspace columns
+// do not exist on databases older than v9 when the base schema is executed
+const workspaceIndexes = `
+CREATE INDEX IF NOT EXISTS idx_branches_workspace ON branches(workspace, last_accessed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_thoughts_workspace ON thoughts(workspace, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_insights_workspace ON insights(workspace);
+CREATE INDEX IF NOT EXISTS idx_context_workspace ON context_signatures(workspace, domain);
+CREATE INDEX IF NOT EXISTS idx_trajectories_workspace ON trajectories(workspace, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_rl_outcomes_workspace ON rl_strategy_outcomes(workspace);
+CREATE INDEX IF NOT EXISTS idx_causal_graphs_workspace ON causal_graphs(workspace, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_decisions_workspace ON decisions(workspace, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_beliefs_workspace ON probabilistic_beliefs(workspace, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_decompositions_workspace ON problem_decompositions(workspace, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_checkpoints_workspace ON checkpoints(workspace, branch_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_workflow_runs_workspace ON workflow_runs(workspace, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_graph_states_workspace ON graph_states(workspace, updated_at DESC);
+`
+
 // seedData contains initial data that should only be inserted during first-time database creation
 const seedData = `
 -- Seed default Thompson Sampling RL strategies
@@ -310,6 +545,10 @@ func initializeSchema(db *sql.DB) error {
 		return fmt.Errorf("database version (%d) is newer than application version (%d)", currentVersion, schemaVersion)
 	}
 
+	if _, err := db.Exec(workspaceIndexes); err != nil {
+		return fmt.Errorf("failed to create workspace indexes: %w", err)
+	}
+
 	return nil
 }
 
@@ -547,6 +786,321 @@ func runMigrations(db *sql.DB, fromVersion, toVersion int) error {
 		}
 	}
 
+	// Migration from v8 to v9: Add workspace column to partitioned tables.
+	// Tables introduced by earlier migrations may already have been created
+	// from the current schema (with the column), so each column is added only
+	// when missing. Existing rows land in the default workspace.
+	if fromVersion < 9 && toVersion >= 9 {
+		for _, table := range workspaceTables {
+			if err := addColumnIfMissing(db, table, "workspace", "TEXT NOT NULL DEFAULT 'default'"); err != nil {
+				return fmt.Errorf("failed to apply v8->v9 migration: %w", err)
+			}
+		}
+	}
+
+	// Migration from v9 to v10: Persist causal graphs, interventions and counterfactuals
+	if fromVersion < 10 && toVersion >= 10 {
+		migration := `
+		-- Causal reasoning persistence (v10)
+		CREATE TABLE IF NOT EXISTS causal_graphs (
+			id TEXT PRIMARY KEY,
+			description TEXT NOT NULL,
+			metadata TEXT,
+			created_at INTEGER NOT NULL,
+			workspace TEXT NOT NULL DEFAULT 'default'
+		);
+
+		-- Causal variables (nodes), ordered by position within a graph
+		CREATE TABLE IF NOT EXISTS causal_variables (
+			graph_id TEXT NOT NULL,
+			id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			description TEXT,
+			type TEXT NOT NULL,
+			observable INTEGER NOT NULL DEFAULT 1,
+			metadata TEXT,
+			PRIMARY KEY (graph_id, id),
+			FOREIGN KEY (graph_id) REFERENCES causal_graphs(id) ON DELETE CASCADE
+		);
+
+		-- Causal links (edges), ordered by position within a graph
+		CREATE TABLE IF NOT EXISTS causal_links (
+			graph_id TEXT NOT NULL,
+			id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			from_variable TEXT NOT NULL,
+			to_variable TEXT NOT NULL,
+			strength REAL NOT NULL,
+			type TEXT NOT NULL,
+			confidence REAL NOT NULL,
+			evidence TEXT,          -- JSON array
+			metadata TEXT,
+			PRIMARY KEY (graph_id, id),
+			FOREIGN KEY (graph_id) REFERENCES causal_graphs(id) ON DELETE CASCADE
+		);
+
+		-- Simulated interventions on causal graphs
+		CREATE TABLE IF NOT EXISTS causal_interventions (
+			id TEXT PRIMARY KEY,
+			graph_id TEXT NOT NULL,
+			variable TEXT NOT NULL,
+			intervention_type TEXT NOT NULL,
+			intervention_value TEXT,
+			predicted_effects TEXT, -- JSON array of PredictedEffect
+			confidence REAL NOT NULL,
+			metadata TEXT,
+			created_at INTEGER NOT NULL,
+			FOREIGN KEY (graph_id) REFERENCES causal_graphs(id) ON DELETE CASCADE
+		);
+
+		-- Counterfactual scenarios generated from causal graphs
+		CREATE TABLE IF NOT EXISTS causal_counterfactuals (
+			id TEXT PRIMARY KEY,
+			graph_id TEXT NOT NULL,
+			scenario TEXT NOT NULL,
+			changes TEXT,           -- JSON object
+			outcomes TEXT,          -- JSON object
+			plausibility REAL NOT NULL,
+			metadata TEXT,
+			created_at INTEGER NOT NULL,
+			FOREIGN KEY (graph_id) REFERENCES causal_graphs(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_causal_graphs_created ON causal_graphs(created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_causal_interventions_graph ON causal_interventions(graph_id);
+		CREATE INDEX IF NOT EXISTS idx_causal_counterfactuals_graph ON causal_counterfactuals(graph_id);
+		`
+
+		if _, err := db.Exec(migration); err != nil {
+			return fmt.Errorf("failed to apply v9->v10 migration: %w", err)
+		}
+	}
+
+	// Migration from v10 to v11: Persist decisions, beliefs and problem decompositions
+	if fromVersion < 11 && toVersion >= 11 {
+		migration := `
+		-- Decision, belief and decomposition persistence (v11)
+		-- Multi-criteria decisions; options and criteria are stored as JSON documents
+		CREATE TABLE IF NOT EXISTS decisions (
+			id TEXT PRIMARY KEY,
+			question TEXT NOT NULL,
+			options TEXT NOT NULL,      -- JSON array of DecisionOption
+			criteria TEXT NOT NULL,     -- JSON array of DecisionCriterion
+			recommendation TEXT,
+			confidence REAL NOT NULL,
+			metadata TEXT,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			workspace TEXT NOT NULL DEFAULT 'default'
+		);
+
+		-- Bayesian beliefs and the evidence applied to them
+		CREATE TABLE IF NOT EXISTS probabilistic_beliefs (
+			id TEXT PRIMARY KEY,
+			statement TEXT NOT NULL,
+			probability REAL NOT NULL,
+			prior_prob REAL NOT NULL,
+			evidence TEXT,              -- JSON array of evidence IDs
+			metadata TEXT,
+			updated_at INTEGER NOT NULL,
+			workspace TEXT NOT NULL DEFAULT 'default'
+		);
+
+		-- Problem decompositions; subproblems and dependencies are stored as JSON documents
+		CREATE TABLE IF NOT EXISTS problem_decompositions (
+			id TEXT PRIMARY KEY,
+			problem TEXT NOT NULL,
+			subproblems TEXT NOT NULL,  -- JSON array of Subproblem
+			dependencies TEXT,          -- JSON array of Dependency
+			solution_path TEXT,         -- JSON array of subproblem IDs
+			metadata TEXT,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			workspace TEXT NOT NULL DEFAULT 'default'
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_decisions_created ON decisions(created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_beliefs_updated ON probabilistic_beliefs(updated_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_decompositions_created ON problem_decompositions(created_at DESC);
+		`
+
+		if _, err := db.Exec(migration); err != nil {
+			return fmt.Errorf("failed to apply v10->v11 migration: %w", err)
+		}
+	}
+
+	if fromVersion < 12 && toVersion >= 12 {
+		migration := `
+		-- Checkpoint persistence (v12)
+		-- Backtracking checkpoints; each points at a snapshot plus a number of deltas
+		CREATE TABLE IF NOT EXISTS checkpoints (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT,
+			branch_id TEXT NOT NULL,
+			snapshot_id TEXT NOT NULL,
+			delta_count INTEGER NOT NULL DEFAULT 0,
+			metadata TEXT,
+			created_at INTEGER NOT NULL,
+			workspace TEXT NOT NULL DEFAULT 'default'
+		);
+
+		-- Full branch states captured for checkpoint restoration
+		CREATE TABLE IF NOT EXISTS branch_snapshots (
+			id TEXT PRIMARY KEY,
+			branch_id TEXT NOT NULL,
+			branch TEXT NOT NULL,       -- JSON encoded Branch
+			thought_count INTEGER NOT NULL DEFAULT 0,
+			insight_count INTEGER NOT NULL DEFAULT 0,
+			cross_ref_count INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL
+		);
+
+		-- Changes recorded on top of a snapshot, replayed in position order
+		CREATE TABLE IF NOT EXISTS branch_deltas (
+			id TEXT PRIMARY KEY,
+			snapshot_id TEXT NOT NULL,
+			branch_id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			operation TEXT NOT NULL,
+			entity_type TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			entity TEXT,                -- JSON encoded Thought, Insight or CrossRef
+			created_at INTEGER NOT NULL,
+			FOREIGN KEY (snapshot_id) REFERENCES branch_snapshots(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_checkpoints_branch ON checkpoints(branch_id, created_at);
+		CREATE INDEX IF NOT EXISTS idx_branch_snapshots_branch ON branch_snapshots(branch_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_branch_deltas_snapshot ON branch_deltas(snapshot_id, position);
+		`
+
+		if _, err := db.Exec(migration); err != nil {
+			return fmt.Errorf("failed to apply v11->v12 migration: %w", err)
+		}
+	}
+
+	if fromVersion < 13 && toVersion >= 13 {
+		migration := `
+		-- Graph-of-Thoughts persistence (v13)
+		-- Graph-of-Thoughts states; the full graph is stored as a JSON document
+		CREATE TABLE IF NOT EXISTS graph_states (
+			id TEXT PRIMARY KEY,
+			initial_thought TEXT,
+			state TEXT NOT NULL,        -- JSON encoded GraphState
+			vertex_count INTEGER NOT NULL DEFAULT 0,
+			edge_count INTEGER NOT NULL DEFAULT 0,
+			active_count INTEGER NOT NULL DEFAULT 0,
+			terminal_count INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_graph_states_updated ON graph_states(updated_at DESC);
+		`
+
+		if _, err := db.Exec(migration); err != nil {
+			return fmt.Errorf("failed to apply v12->v13 migration: %w", err)
+		}
+	}
+
+	if fromVersion < 14 && toVersion >= 14 {
+		migration := `
+		-- Workflow registry persistence (v14)
+		-- Orchestration workflow definitions; every change is stored as a new version
+		CREATE TABLE IF NOT EXISTS workflow_versions (
+			workflow_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			source TEXT NOT NULL,       -- "tool" or the file the definition was loaded from
+			checksum TEXT NOT NULL,     -- SHA-256 of the definition
+			definition TEXT NOT NULL,   -- JSON encoded Workflow
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY (workflow_id, version)
+		);
+		`
+
+		if _, err := db.Exec(migration); err != nil {
+			return fmt.Errorf("failed to apply v13->v14 migration: %w", err)
+		}
+	}
+
+	if fromVersion < 15 && toVersion >= 15 {
+		migration := `
+		-- Workflow run history (v15)
+		-- Orchestration workflow runs; the full run is stored as a JSON document
+		CREATE TABLE IF NOT EXISTS workflow_runs (
+			id TEXT PRIMARY KEY,
+			workflow_id TEXT NOT NULL,
+			workflow_version INTEGER NOT NULL DEFAULT 0,
+			trigger TEXT NOT NULL,      -- "execute", "resume" or "rerun"
+			parent_run_id TEXT,         -- Run resumed or re-run
+			status TEXT NOT NULL,
+			error TEXT,
+			run TEXT NOT NULL,          -- JSON encoded WorkflowRun
+			started_at INTEGER NOT NULL,
+			finished_at INTEGER
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_workflow_runs_workflow ON workflow_runs(workflow_id, started_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_workflow_runs_started ON workflow_runs(started_at DESC);
+		`
+
+		if _, err := db.Exec(migration); err != nil {
+			return fmt.Errorf("failed to apply v14->v15 migration: %w", err)
+		}
+	}
+
+	// Migration from v15 to v16: Partition workflow runs by workspace.
+	// Existing runs land in the default workspace.
+	if fromVersion < 16 && toVersion >= 16 {
+		if err := addColumnIfMissing(db, "workflow_runs", "workspace", "TEXT NOT NULL DEFAULT 'default'"); err != nil {
+			return fmt.Errorf("failed to apply v15->v16 migration: %w", err)
+		}
+	}
+
+	// Migration from v16 to v17: Partition Graph-of-Thoughts states by
+	// workspace. Existing graphs land in the default workspace.
+	if fromVersion < 17 && toVersion >= 17 {
+		if err := addColumnIfMissing(db, "graph_states", "workspace", "TEXT NOT NULL DEFAULT 'default'"); err != nil {
+			return fmt.Errorf("failed to apply v16->v17 migration: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// addColumnIfMissing adds a column to a table unless it already exists
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+
+	exists := false
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan table info for %s: %w", table, err)
+		}
+		if name == column {
+			exists = true
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
 	return nil
 }
 
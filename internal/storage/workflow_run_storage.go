@@ -0,0 +1,128 @@
+// Package storage provides persistence for orchestration workflow runs.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// StoreWorkflowRun inserts or replaces a workflow run in the record's
+// workspace, or the default workspace when it has none
+func (s *SQLiteStorage) StoreWorkflowRun(record *types.WorkflowRunRecord) error {
+	if record == nil || record.ID == "" {
+		return fmt.Errorf("run ID is required")
+	}
+	if len(record.Run) == 0 {
+		return fmt.Errorf("run %s has no data", record.ID)
+	}
+
+	var finishedAt interface{}
+	if record.FinishedAt != nil {
+		finishedAt = record.FinishedAt.Unix()
+	}
+	workspace := NormalizeWorkspace(record.Workspace)
+	result, err := s.db.Exec(`
+		INSERT INTO workflow_runs
+		(id, workspace, workflow_id, workflow_version, trigger, parent_run_id, status, error, run, started_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			workflow_id = excluded.workflow_id,
+			workflow_version = excluded.workflow_version,
+			trigger = excluded.trigger,
+			parent_run_id = excluded.parent_run_id,
+			status = excluded.status,
+			error = excluded.error,
+			run = excluded.run,
+			started_at = excluded.started_at,
+			finished_at = excluded.finished_at
+		WHERE workflow_runs.workspace = excluded.workspace
+	`, record.ID, workspace, record.WorkflowID, record.WorkflowVersion, record.Trigger,
+		record.ParentRunID, record.Status, record.Error,
+		string(record.Run), record.StartedAt.Unix(), finishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store workflow run: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("workflow run %s already exists in another workspace", record.ID)
+	}
+	return nil
+}
+
+// GetWorkflowRun loads a workflow run recorded in workspace
+func (s *SQLiteStorage) GetWorkflowRun(workspace, id string) (*types.WorkflowRunRecord, error) {
+	row := s.db.QueryRow(`
+		SELECT id, workspace, workflow_id, workflow_version, trigger, parent_run_id, status, error, started_at, finished_at, run
+		FROM workflow_runs
+		WHERE id = ? AND workspace = ?
+	`, id, NormalizeWorkspace(workspace))
+
+	record := &types.WorkflowRunRecord{}
+	var run string
+	summary, err := scanWorkflowRunSummary(row, &run)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("workflow run not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	record.WorkflowRunSummary = *summary
+	record.Run = []byte(run)
+	return record, nil
+}
+
+// ListWorkflowRuns returns the summaries of the runs recorded in workspace,
+// most recent first. Empty workflowID or status match every run.
+func (s *SQLiteStorage) ListWorkflowRuns(workspace, workflowID, status string, limit, offset int) ([]*types.WorkflowRunSummary, error) {
+	rows, err := s.db.Query(`
+		SELECT id, workspace, workflow_id, workflow_version, trigger, parent_run_id, status, error, started_at, finished_at
+		FROM workflow_runs
+		WHERE workspace = ? AND (? = '' OR workflow_id = ?) AND (? = '' OR status = ?)
+		ORDER BY started_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, NormalizeWorkspace(workspace), workflowID, workflowID, status, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflow runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	summaries := make([]*types.WorkflowRunSummary, 0)
+	for rows.Next() {
+		summary, err := scanWorkflowRunSummary(rows, nil)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// scanWorkflowRunSummary scans a workflow run row, followed by the run
+// document when run is not nil
+func scanWorkflowRunSummary(row rowScanner, run *string) (*types.WorkflowRunSummary, error) {
+	summary := &types.WorkflowRunSummary{}
+	var parentRunID, runError sql.NullString
+	var startedAt int64
+	var finishedAt sql.NullInt64
+	dest := []interface{}{&summary.ID, &summary.Workspace, &summary.WorkflowID, &summary.WorkflowVersion, &summary.Trigger,
+		&parentRunID, &summary.Status, &runError, &startedAt, &finishedAt}
+	if run != nil {
+		dest = append(dest, run)
+	}
+	if err := row.Scan(dest...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan workflow run: %w", err)
+	}
+	summary.ParentRunID = parentRunID.String
+	summary.Error = runError.String
+	summary.StartedAt = time.Unix(startedAt, 0)
+	if finishedAt.Valid {
+		finished := time.Unix(finishedAt.Int64, 0)
+		summary.FinishedAt = &finished
+	}
+	return summary, nil
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+func newTestWorkflowRun(id, workflowID, status string, startedAt time.Time) *types.WorkflowRunRecord {
+	return &types.WorkflowRunRecord{
+		WorkflowRunSummary: types.WorkflowRunSummary{
+			ID:         id,
+			WorkflowID: workflowID,
+			Trigger:    "execute",
+			Status:     status,
+			StartedAt:  startedAt,
+		},
+		Run: []byte(`{"id":"` + id + `"}`),
+	}
+}
+
+func TestSQLiteStorage_WorkflowRuns(t *testing.T) {
+	store, _ := newTestSQLiteStorage(t)
+	defer func() { _ = store.Close() }()
+
+	start := time.Now().Add(-time.Hour)
+	runs := []*types.WorkflowRunRecord{
+		newTestWorkflowRun("run-1", "review", "running", start),
+		newTestWorkflowRun("run-2", "review", "success", start.Add(time.Minute)),
+		newTestWorkflowRun("run-3", "triage", "failed", start.Add(2*time.Minute)),
+	}
+	for _, run := range runs {
+		if err := store.StoreWorkflowRun(run); err != nil {
+			t.Fatalf("StoreWorkflowRun(%s) error = %v", run.ID, err)
+		}
+	}
+
+	// Storing a run again replaces it
+	finished := start.Add(30 * time.Second)
+	failed := newTestWorkflowRun("run-1", "review", "failed", start)
+	failed.Error, failed.FinishedAt, failed.ParentRunID = "step b failed", &finished, "run-0"
+	failed.Run = []byte(`{"id":"run-1","status":"failed"}`)
+	if err := store.StoreWorkflowRun(failed); err != nil {
+		t.Fatalf("StoreWorkflowRun() error = %v", err)
+	}
+
+	got, err := store.GetWorkflowRun(DefaultWorkspace, "run-1")
+	if err != nil {
+		t.Fatalf("GetWorkflowRun() error = %v", err)
+	}
+	if got.Status != "failed" || got.Error != "step b failed" || got.ParentRunID != "run-0" ||
+		got.FinishedAt == nil || got.FinishedAt.Unix() != finished.Unix() || string(got.Run) != `{"id":"run-1","status":"failed"}` {
+		t.Errorf("run = %+v", got)
+	}
+	if _, err := store.GetWorkflowRun(DefaultWorkspace, "missing"); err == nil {
+		t.Error("expected error for missing run")
+	}
+
+	all, err := store.ListWorkflowRuns(DefaultWorkspace, "", "", 10, 0)
+	if err != nil || len(all) != 3 || all[0].ID != "run-3" || all[2].ID != "run-1" {
+		t.Fatalf("ListWorkflowRuns() = %+v, err %v", all, err)
+	}
+	if review, _ := store.ListWorkflowRuns(DefaultWorkspace, "review", "", 10, 0); len(review) != 2 {
+		t.Errorf("review runs = %+v", review)
+	}
+	if failedRuns, _ := store.ListWorkflowRuns(DefaultWorkspace, "", "failed", 10, 0); len(failedRuns) != 2 {
+		t.Errorf("failed runs = %+v", failedRuns)
+	}
+	if page, _ := store.ListWorkflowRuns(DefaultWorkspace, "", "", 1, 1); len(page) != 1 || page[0].ID != "run-2" {
+		t.Errorf("page = %+v", page)
+	}
+}
+
+func TestSQLiteStorage_WorkflowRunsWorkspaces(t *testing.T) {
+	store, _ := newTestSQLiteStorage(t)
+	defer func() { _ = store.Close() }()
+
+	run := newTestWorkflowRun("run-1", "review", "failed", time.Now())
+	run.Workspace = "alpha"
+	if err := store.StoreWorkflowRun(run); err != nil {
+		t.Fatalf("StoreWorkflowRun() error = %v", err)
+	}
+
+	if got, err := store.GetWorkflowRun("alpha", "run-1"); err != nil || got.Workspace != "alpha" {
+		t.Fatalf("GetWorkflowRun(alpha) = %+v, err %v", got, err)
+	}
+	if _, err := store.GetWorkflowRun(DefaultWorkspace, "run-1"); err == nil {
+		t.Error("expected run to be hidden from the default workspace")
+	}
+	if runs, _ := store.ListWorkflowRuns(DefaultWorkspace, "", "", 10, 0); len(runs) != 0 {
+		t.Errorf("default runs = %+v", runs)
+	}
+	if runs, _ := store.ListWorkflowRuns("alpha", "", "", 10, 0); len(runs) != 1 {
+		t.Errorf("alpha runs = %+v", runs)
+	}
+
+	// A run ID cannot be taken over from another workspace
+	if err := store.StoreWorkflowRun(newTestWorkflowRun("run-1", "review", "success", time.Now())); err == nil {
+		t.Error("expected error storing a run that exists in another workspace")
+	}
+}
+
+func TestSQLiteStorage_WorkflowRunsMigration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v15.db")
+
+	// Create a v15 database whose runs predate the workspace column
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE schema_metadata (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+		INSERT INTO schema_metadata (key, value) VALUES ('version', '15');
+		CREATE TABLE workflow_runs (
+			id TEXT PRIMARY KEY, workflow_id TEXT NOT NULL, workflow_version INTEGER NOT NULL DEFAULT 0,
+			trigger TEXT NOT NULL, parent_run_id TEXT, status TEXT NOT NULL, error TEXT,
+			run TEXT NOT NULL, started_at INTEGER NOT NULL, finished_at INTEGER
+		);
+		INSERT INTO workflow_runs (id, workflow_id, trigger, status, run, started_at)
+		VALUES ('legacy-run', 'review', 'execute', 'success', '{}', 1700000000);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create v15 schema: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage() on v15 database error = %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	run, err := store.GetWorkflowRun(DefaultWorkspace, "legacy-run")
+	if err != nil {
+		t.Fatalf("legacy run should be in the default workspace: %v", err)
+	}
+	if run.Workspace != DefaultWorkspace {
+		t.Errorf("legacy run workspace = %q, want %q", run.Workspace, DefaultWorkspace)
+	}
+}
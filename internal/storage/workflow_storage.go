@@ -0,0 +1,167 @@
+// Package storage provides persistence for versioned orchestration workflows.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+// StoreWorkflowVersion stores a workflow definition as its next version and
+// sets record.Version. When the latest stored version has the same checksum
+// nothing is written and record takes that version's number and time.
+func (s *SQLiteStorage) StoreWorkflowVersion(record *types.WorkflowVersionRecord) error {
+	if record == nil || record.WorkflowID == "" {
+		return fmt.Errorf("workflow ID is required")
+	}
+	if len(record.Definition) == 0 {
+		return fmt.Errorf("workflow %s has no definition", record.WorkflowID)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var latest int
+	var checksum string
+	var createdAt int64
+	err = tx.QueryRow(`
+		SELECT version, checksum, created_at
+		FROM workflow_versions
+		WHERE workflow_id = ?
+		ORDER BY version DESC
+		LIMIT 1
+	`, record.WorkflowID).Scan(&latest, &checksum, &createdAt)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to query workflow versions: %w", err)
+	}
+	if latest > 0 && checksum == record.Checksum {
+		record.Version = latest
+		record.CreatedAt = time.Unix(createdAt, 0)
+		return nil
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	_, err = tx.Exec(`
+		INSERT INTO workflow_versions (workflow_id, version, name, source, checksum, definition, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, record.WorkflowID, latest+1, record.Name, record.Source, record.Checksum,
+		string(record.Definition), record.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to store workflow version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit workflow version: %w", err)
+	}
+	record.Version = latest + 1
+	return nil
+}
+
+// GetWorkflowVersion loads a workflow version. Version 0 loads the latest.
+func (s *SQLiteStorage) GetWorkflowVersion(workflowID string, version int) (*types.WorkflowVersionRecord, error) {
+	row := s.db.QueryRow(`
+		SELECT workflow_id, version, name, source, checksum, definition, created_at
+		FROM workflow_versions
+		WHERE workflow_id = ? AND (? = 0 OR version = ?)
+		ORDER BY version DESC
+		LIMIT 1
+	`, workflowID, version, version)
+
+	record, err := scanWorkflowVersion(row, true)
+	if err == sql.ErrNoRows {
+		if version == 0 {
+			return nil, fmt.Errorf("workflow not found: %s", workflowID)
+		}
+		return nil, fmt.Errorf("workflow version not found: %s v%d", workflowID, version)
+	}
+	return record, err
+}
+
+// ListWorkflowVersions returns the versions of a workflow, oldest first,
+// without their definitions
+func (s *SQLiteStorage) ListWorkflowVersions(workflowID string) ([]*types.WorkflowVersionRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT workflow_id, version, name, source, checksum, '', created_at
+		FROM workflow_versions
+		WHERE workflow_id = ?
+		ORDER BY version
+	`, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflow versions: %w", err)
+	}
+	return scanWorkflowVersions(rows, false)
+}
+
+// ListLatestWorkflowVersions returns the latest version of every stored
+// workflow, with definitions, ordered by workflow ID
+func (s *SQLiteStorage) ListLatestWorkflowVersions() ([]*types.WorkflowVersionRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT w.workflow_id, w.version, w.name, w.source, w.checksum, w.definition, w.created_at
+		FROM workflow_versions w
+		JOIN (
+			SELECT workflow_id, MAX(version) AS version
+			FROM workflow_versions
+			GROUP BY workflow_id
+		) latest ON latest.workflow_id = w.workflow_id AND latest.version = w.version
+		ORDER BY w.workflow_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflows: %w", err)
+	}
+	return scanWorkflowVersions(rows, true)
+}
+
+// DeleteWorkflowVersions removes one version of a workflow, or every version
+// when version is 0, and returns how many were removed
+func (s *SQLiteStorage) DeleteWorkflowVersions(workflowID string, version int) (int, error) {
+	result, err := s.db.Exec(`
+		DELETE FROM workflow_versions
+		WHERE workflow_id = ? AND (? = 0 OR version = ?)
+	`, workflowID, version, version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete workflow versions: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// scanWorkflowVersions scans workflow version rows and closes them
+func scanWorkflowVersions(rows *sql.Rows, withDefinition bool) ([]*types.WorkflowVersionRecord, error) {
+	defer func() { _ = rows.Close() }()
+
+	records := make([]*types.WorkflowVersionRecord, 0)
+	for rows.Next() {
+		record, err := scanWorkflowVersion(rows, withDefinition)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// scanWorkflowVersion scans a single workflow version row
+func scanWorkflowVersion(row rowScanner, withDefinition bool) (*types.WorkflowVersionRecord, error) {
+	record := &types.WorkflowVersionRecord{}
+	var definition string
+	var createdAt int64
+	err := row.Scan(&record.WorkflowID, &record.Version, &record.Name, &record.Source,
+		&record.Checksum, &definition, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan workflow version: %w", err)
+	}
+	if withDefinition {
+		record.Definition = []byte(definition)
+	}
+	record.CreatedAt = time.Unix(createdAt, 0)
+	return record, nil
+}
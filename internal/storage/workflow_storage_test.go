@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"testing"
+
+	"unified-thinking/internal/types"
+)
+
+func newTestWorkflowVersion(id, checksum string) *types.WorkflowVersionRecord {
+	return &types.WorkflowVersionRecord{
+		WorkflowID: id,
+		Name:       "Review",
+		Source:     "tool",
+		Checksum:   checksum,
+		Definition: []byte(`{"id":"` + id + `","checksum":"` + checksum + `"}`),
+	}
+}
+
+func TestSQLiteStorage_WorkflowVersions(t *testing.T) {
+	store, dbPath := newTestSQLiteStorage(t)
+
+	for i, checksum := range []string{"a", "b", "b", "c"} {
+		record := newTestWorkflowVersion("review", checksum)
+		if err := store.StoreWorkflowVersion(record); err != nil {
+			t.Fatalf("StoreWorkflowVersion(%d) error = %v", i, err)
+		}
+		// An unchanged definition keeps the latest version
+		if want := map[int]int{0: 1, 1: 2, 2: 2, 3: 3}[i]; record.Version != want {
+			t.Errorf("store %d: version = %d, want %d", i, record.Version, want)
+		}
+	}
+	if err := store.StoreWorkflowVersion(newTestWorkflowVersion("triage", "x")); err != nil {
+		t.Fatalf("StoreWorkflowVersion() error = %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	reopened, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage() error = %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+
+	versions, err := reopened.ListWorkflowVersions("review")
+	if err != nil {
+		t.Fatalf("ListWorkflowVersions() error = %v", err)
+	}
+	if len(versions) != 3 || versions[0].Version != 1 || versions[2].Checksum != "c" || versions[0].Definition != nil {
+		t.Errorf("versions = %+v", versions)
+	}
+
+	latest, err := reopened.GetWorkflowVersion("review", 0)
+	if err != nil || latest.Version != 3 || string(latest.Definition) != `{"id":"review","checksum":"c"}` {
+		t.Errorf("latest = %+v, err %v", latest, err)
+	}
+	if first, err := reopened.GetWorkflowVersion("review", 1); err != nil || first.Checksum != "a" {
+		t.Errorf("v1 = %+v, err %v", first, err)
+	}
+	if _, err := reopened.GetWorkflowVersion("review", 9); err == nil {
+		t.Error("expected error for a missing version")
+	}
+
+	all, err := reopened.ListLatestWorkflowVersions()
+	if err != nil || len(all) != 2 || all[0].WorkflowID != "review" || all[0].Version != 3 || all[1].WorkflowID != "triage" {
+		t.Errorf("latest versions = %+v, err %v", all, err)
+	}
+
+	if n, err := reopened.DeleteWorkflowVersions("review", 3); err != nil || n != 1 {
+		t.Errorf("DeleteWorkflowVersions(v3) = %d, %v", n, err)
+	}
+	if latest, _ := reopened.GetWorkflowVersion("review", 0); latest == nil || latest.Version != 2 {
+		t.Errorf("latest after delete = %+v", latest)
+	}
+	if n, err := reopened.DeleteWorkflowVersions("review", 0); err != nil || n != 2 {
+		t.Errorf("DeleteWorkflowVersions(all) = %d, %v", n, err)
+	}
+	if _, err := reopened.GetWorkflowVersion("review", 0); err == nil {
+		t.Error("expected deleted workflow to be gone")
+	}
+}
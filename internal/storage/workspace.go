@@ -0,0 +1,73 @@
+// Package storage provides workspace scoping for storage backends.
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultWorkspace is used for data written without an explicit workspace
+	DefaultWorkspace = "default"
+	// MaxWorkspaceLength bounds workspace identifiers
+	MaxWorkspaceLength = 64
+)
+
+// WorkspaceScoped is implemented by backends that partition thoughts, branches,
+// insights and the active branch by workspace. A scoped view stamps its workspace
+// on every write and only returns data from that workspace.
+type WorkspaceScoped interface {
+	// Workspace returns the workspace this view is bound to
+	Workspace() string
+	// ForWorkspace returns a view of the same backend bound to workspace
+	ForWorkspace(workspace string) Storage
+}
+
+// NormalizeWorkspace trims and lower-cases a workspace identifier,
+// mapping the empty string to DefaultWorkspace
+func NormalizeWorkspace(workspace string) string {
+	workspace = strings.ToLower(strings.TrimSpace(workspace))
+	if workspace == "" {
+		return DefaultWorkspace
+	}
+	return workspace
+}
+
+// ValidateWorkspace checks that a workspace identifier is safe to use as a
+// partition key: letters, digits, '-', '_', '.' and '/' only
+func ValidateWorkspace(workspace string) error {
+	workspace = strings.TrimSpace(workspace)
+	if workspace == "" {
+		return nil
+	}
+	if len(workspace) > MaxWorkspaceLength {
+		return fmt.Errorf("workspace exceeds maximum length of %d characters", MaxWorkspaceLength)
+	}
+	for _, r := range workspace {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == '/':
+		default:
+			return fmt.Errorf("workspace contains invalid character %q", r)
+		}
+	}
+	return nil
+}
+
+// ForWorkspace returns a view of store bound to workspace. Backends that do not
+// implement WorkspaceScoped are returned unchanged and behave as a single
+// shared workspace.
+func ForWorkspace(store Storage, workspace string) Storage {
+	if scoped, ok := store.(WorkspaceScoped); ok {
+		return scoped.ForWorkspace(workspace)
+	}
+	return store
+}
+
+// WorkspaceOf returns the workspace a storage view is bound to
+func WorkspaceOf(store Storage) string {
+	if scoped, ok := store.(WorkspaceScoped); ok {
+		return scoped.Workspace()
+	}
+	return DefaultWorkspace
+}
@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"unified-thinking/internal/types"
+)
+
+func TestNormalizeWorkspace(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", DefaultWorkspace},
+		{"   ", DefaultWorkspace},
+		{"Project-A", "project-a"},
+		{"  team/api  ", "team/api"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeWorkspace(tt.input); got != tt.want {
+			t.Errorf("NormalizeWorkspace(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestValidateWorkspace(t *testing.T) {
+	valid := []string{"", "default", "project-a", "team/api_v2.1"}
+	for _, ws := range valid {
+		if err := ValidateWorkspace(ws); err != nil {
+			t.Errorf("ValidateWorkspace(%q) unexpected error: %v", ws, err)
+		}
+	}
+
+	invalid := []string{"has space", "semi;colon", "quote'", strings.Repeat("a", MaxWorkspaceLength+1)}
+	for _, ws := range invalid {
+		if err := ValidateWorkspace(ws); err == nil {
+			t.Errorf("ValidateWorkspace(%q) expected error", ws)
+		}
+	}
+}
+
+func TestMemoryStorage_WorkspaceIsolation(t *testing.T) {
+	root := NewMemoryStorage()
+	alpha := ForWorkspace(root, "alpha")
+	beta := ForWorkspace(root, "beta")
+
+	if WorkspaceOf(root) != DefaultWorkspace {
+		t.Errorf("root workspace = %q, want %q", WorkspaceOf(root), DefaultWorkspace)
+	}
+	if ForWorkspace(root, "Alpha") != alpha {
+		t.Error("ForWorkspace should return the same partition for equivalent names")
+	}
+	if ForWorkspace(alpha, "") != root {
+		t.Error("ForWorkspace(\"\") should return the default partition")
+	}
+
+	thought := &types.Thought{Content: "alpha database indexing", Mode: types.ModeLinear, Confidence: 0.8, Timestamp: time.Now()}
+	if err := alpha.StoreThought(thought); err != nil {
+		t.Fatalf("StoreThought() error = %v", err)
+	}
+	if thought.Workspace != "alpha" {
+		t.Errorf("thought.Workspace = %q, want alpha", thought.Workspace)
+	}
+
+	if _, err := beta.GetThought(thought.ID); err == nil {
+		t.Error("thought from alpha should not be visible in beta")
+	}
+	if _, err := root.GetThought(thought.ID); err == nil {
+		t.Error("thought from alpha should not be visible in default workspace")
+	}
+	if got := beta.SearchThoughts("database", "", 10, 0); len(got) != 0 {
+		t.Errorf("beta search returned %d thoughts, want 0", len(got))
+	}
+	if got := alpha.SearchThoughts("database", "", 10, 0); len(got) != 1 {
+		t.Errorf("alpha search returned %d thoughts, want 1", len(got))
+	}
+
+	branch := &types.Branch{ID: "shared-name", State: types.StateActive}
+	if err := alpha.StoreBranch(branch); err != nil {
+		t.Fatalf("StoreBranch() error = %v", err)
+	}
+	if err := alpha.SetActiveBranch("shared-name"); err != nil {
+		t.Fatalf("SetActiveBranch() error = %v", err)
+	}
+
+	if active, _ := beta.GetActiveBranch(); active != nil {
+		t.Errorf("beta should have no active branch, got %s", active.ID)
+	}
+	if branches := beta.ListBranches(); len(branches) != 0 {
+		t.Errorf("beta ListBranches() returned %d, want 0", len(branches))
+	}
+	if active, err := alpha.GetActiveBranch(); err != nil || active.ID != "shared-name" {
+		t.Errorf("alpha active branch = %v, %v; want shared-name", active, err)
+	}
+}
+
+func TestSQLiteStorage_WorkspaceIsolation(t *testing.T) {
+	store, dbPath := newTestSQLiteStorage(t)
+
+	alpha := store.WorkspaceView("alpha")
+	beta := store.WorkspaceView("beta")
+
+	if store.WorkspaceView("default") != store {
+		t.Error("default view should be the root storage")
+	}
+	if store.WorkspaceView("ALPHA") != alpha {
+		t.Error("WorkspaceView should reuse views for equivalent names")
+	}
+
+	thought := &types.Thought{Content: "alpha caching strategy", Mode: types.ModeLinear, Confidence: 0.7, Timestamp: time.Now()}
+	if err := alpha.StoreThought(thought); err != nil {
+		t.Fatalf("StoreThought() error = %v", err)
+	}
+	branch := &types.Branch{ID: "branch-alpha", State: types.StateActive, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := alpha.StoreBranch(branch); err != nil {
+		t.Fatalf("StoreBranch() error = %v", err)
+	}
+
+	if _, err := beta.GetThought(thought.ID); err == nil {
+		t.Error("thought from alpha should not be visible in beta")
+	}
+	if got := beta.SearchThoughts("caching", "", 10, 0); len(got) != 0 {
+		t.Errorf("beta search returned %d thoughts, want 0", len(got))
+	}
+	if got, _ := beta.GetRecentBranches(); len(got) != 0 {
+		t.Errorf("beta recent branches = %d, want 0", len(got))
+	}
+
+	// A branch ID already used by another workspace must not be overwritten
+	if err := beta.StoreBranch(&types.Branch{ID: "branch-alpha", State: types.StateActive}); err == nil {
+		t.Error("expected error storing branch with an ID owned by another workspace")
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Reopen and verify data stays in its workspace
+	reopened, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("failed to reopen storage: %v", err)
+	}
+	defer reopened.Close()
+
+	if _, err := reopened.GetThought(thought.ID); err == nil {
+		t.Error("alpha thought should not be in the default workspace after reopen")
+	}
+	got, err := reopened.WorkspaceView("alpha").GetThought(thought.ID)
+	if err != nil {
+		t.Fatalf("alpha thought missing after reopen: %v", err)
+	}
+	if got.Workspace != "alpha" {
+		t.Errorf("Workspace = %q, want alpha", got.Workspace)
+	}
+	if got := reopened.WorkspaceView("alpha").SearchThoughts("caching", "", 10, 0); len(got) != 1 {
+		t.Errorf("alpha search after reopen returned %d thoughts, want 1", len(got))
+	}
+}
+
+func TestSQLiteStorage_WorkspaceMigration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v8.db")
+
+	// Create a v8 database whose core tables predate the workspace column
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE schema_metadata (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+		INSERT INTO schema_metadata (key, value) VALUES ('version', '8');
+		CREATE TABLE branches (
+			id TEXT PRIMARY KEY, parent_branch_id TEXT, state TEXT NOT NULL,
+			priority REAL NOT NULL DEFAULT 0.0, confidence REAL NOT NULL DEFAULT 0.0,
+			created_at INTEGER NOT NULL, updated_at INTEGER NOT NULL, last_accessed_at INTEGER NOT NULL
+		);
+		CREATE TABLE thoughts (
+			id TEXT PRIMARY KEY, content TEXT NOT NULL, mode TEXT NOT NULL, branch_id TEXT,
+			parent_id TEXT, type TEXT NOT NULL DEFAULT '', confidence REAL NOT NULL,
+			timestamp INTEGER NOT NULL, key_points TEXT, metadata TEXT,
+			is_rebellion INTEGER DEFAULT 0, challenges_assumption INTEGER DEFAULT 0
+		);
+		INSERT INTO thoughts (id, content, mode, confidence, timestamp)
+		VALUES ('legacy-thought', 'legacy content', 'linear', 0.9, 1700000000);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create v8 schema: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := NewSQLiteStorage(dbPath, 5000)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage() on v8 database error = %v", err)
+	}
+	defer store.Close()
+
+	var version int
+	if err := store.db.QueryRow("SELECT value FROM schema_metadata WHERE key = 'version'").Scan(&version); err != nil {
+		t.Fatalf("failed to read version: %v", err)
+	}
+	if version != schemaVersion {
+		t.Errorf("version = %d, want %d", version, schemaVersion)
+	}
+
+	thought, err := store.GetThought("legacy-thought")
+	if err != nil {
+		t.Fatalf("legacy thought should be in the default workspace: %v", err)
+	}
+	if thought.Workspace != DefaultWorkspace {
+		t.Errorf("legacy thought workspace = %q, want %q", thought.Workspace, DefaultWorkspace)
+	}
+}
@@ -107,13 +107,19 @@ func (r *NotifyingReporter) ReportStep(step int, totalSteps int, stepName string
 
 // ReportPartialResult sends intermediate results if enabled.
 // Note: MCP progress notifications don't have a dedicated field for partial data,
-// so we include it in the message as JSON or skip if not configured.
+// so TextDelta text is sent as the message and other results are announced by name.
+// Text deltas bypass rate limiting, since dropping one would corrupt the output.
 func (r *NotifyingReporter) ReportPartialResult(stepName string, data any) error {
 	if !r.config.SendPartialData {
 		return nil
 	}
 
-	if !r.shouldSend() {
+	delta, isDelta := data.(TextDelta)
+	if isDelta {
+		if !r.IsEnabled() {
+			return nil
+		}
+	} else if !r.shouldSend() {
 		return nil
 	}
 
@@ -134,6 +140,9 @@ func (r *NotifyingReporter) ReportPartialResult(stepName string, data any) error
 	}
 
 	message := stepName + " (partial result available)"
+	if isDelta {
+		message = stepName + ": " + delta.Text
+	}
 
 	params := &mcp.ProgressNotificationParams{
 		ProgressToken: r.progressToken,
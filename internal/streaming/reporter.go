@@ -116,6 +116,7 @@ func (r *RateLimitedReporter) ReportStep(step int, totalSteps int, stepName stri
 }
 
 // ReportPartialResult sends partial data if enabled and rate limit allows.
+// Text deltas are always forwarded.
 func (r *RateLimitedReporter) ReportPartialResult(stepName string, data any) error {
 	if !r.config.SendPartialData {
 		return nil
@@ -124,7 +125,7 @@ func (r *RateLimitedReporter) ReportPartialResult(stepName string, data any) err
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if !r.shouldReport() {
+	if _, isDelta := data.(TextDelta); !isDelta && !r.shouldReport() {
 		return nil
 	}
 
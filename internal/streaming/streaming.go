@@ -77,6 +77,19 @@
 //   - analyze-perspectives: Stakeholder count steps
 //   - build-causal-graph: Variable + link count steps
 //   - evaluate-hypotheses: Hypothesis count steps
+//
+// LLM Tools (stream model output as it is generated):
+//   - research-with-search, run-agent, decompose-problem, got-generate
+//
+// # Streamed LLM Output
+//
+// When the reporter in the context is enabled, the Anthropic client requests
+// a server-sent event stream and forwards the model's output as TextDelta
+// partial results: step "text" for prose and "tool_input" for structured
+// output. Deltas are coalesced to at most one notification per MinInterval
+// and are never dropped, so concatenating the messages of a step yields the
+// full output. Cancelling the request with notifications/cancelled aborts
+// the stream.
 package streaming
 
 // Version is the streaming package version.
@@ -99,4 +112,9 @@ var StreamingEnabledTools = []string{
 	"analyze-perspectives",
 	"build-causal-graph",
 	"evaluate-hypotheses",
+
+	// LLM - Streamed model output
+	"research-with-search",
+	"run-agent",
+	"decompose-problem",
 }
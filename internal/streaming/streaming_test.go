@@ -185,6 +185,23 @@ func TestRateLimitedReporterPartialData(t *testing.T) {
 	assert.Equal(t, 1, mock.partialCalls, "partial data should be sent")
 }
 
+func TestRateLimitedReporterKeepsTextDeltas(t *testing.T) {
+	mock := &mockReporter{}
+	r := NewRateLimitedReporter(mock, StreamingConfig{
+		Enabled:         true,
+		MinInterval:     time.Hour,
+		SendPartialData: true,
+	})
+
+	require.NoError(t, r.ReportPartialResult("vertex", "data"))
+	require.NoError(t, r.ReportPartialResult("vertex", "data"))
+	assert.Equal(t, 1, mock.partialCalls, "second result should be rate limited")
+
+	require.NoError(t, r.ReportPartialResult(TextStep, TextDelta{Text: "Hel"}))
+	require.NoError(t, r.ReportPartialResult(TextStep, TextDelta{Text: "lo"}))
+	assert.Equal(t, 3, mock.partialCalls, "text deltas should never be dropped")
+}
+
 // === StepReporter Tests ===
 
 func TestStepReporter(t *testing.T) {
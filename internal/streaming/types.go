@@ -41,6 +41,21 @@ type ProgressUpdate struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// Steps of streamed LLM output reported via ReportPartialResult
+const (
+	// TextStep carries prose as the model writes it
+	TextStep = "text"
+	// ToolInputStep carries the JSON of a structured response or tool call
+	ToolInputStep = "tool_input"
+)
+
+// TextDelta is a piece of model output reported as a partial result while
+// an LLM response streams. Unlike other partial results, deltas are never
+// dropped by rate limiting; the sender coalesces them instead.
+type TextDelta struct {
+	Text string `json:"text"`
+}
+
 // StreamingConfig provides per-tool configuration for streaming behavior.
 type StreamingConfig struct {
 	// Enabled indicates whether streaming is enabled for this tool
@@ -168,6 +183,26 @@ var ToolConfigs = map[string]StreamingConfig{
 		SendPartialData: true,
 		AutoProgress:    true,
 	},
+
+	// LLM - Streamed model output
+	"research-with-search": {
+		Enabled:         true,
+		MinInterval:     100 * time.Millisecond,
+		SendPartialData: true,
+		AutoProgress:    false,
+	},
+	"run-agent": {
+		Enabled:         true,
+		MinInterval:     100 * time.Millisecond,
+		SendPartialData: true,
+		AutoProgress:    false,
+	},
+	"decompose-problem": {
+		Enabled:         true,
+		MinInterval:     100 * time.Millisecond,
+		SendPartialData: true,
+		AutoProgress:    false,
+	},
 }
 
 // GetToolConfig returns the streaming configuration for a tool.
@@ -13,7 +13,10 @@
 //   - Validation: Represents logical consistency checks
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ThinkingMode represents the type of thinking
 type ThinkingMode string
@@ -82,6 +85,7 @@ type Thought struct {
 	Timestamp  time.Time    `json:"timestamp"`
 	KeyPoints  []string     `json:"key_points,omitempty"`
 	Metadata   Metadata     `json:"metadata,omitempty"`
+	Workspace  string       `json:"workspace,omitempty"`
 
 	// Flags
 	IsRebellion          bool `json:"is_rebellion"`
@@ -101,6 +105,7 @@ type Branch struct {
 	CreatedAt      time.Time    `json:"created_at"`
 	UpdatedAt      time.Time    `json:"updated_at"`
 	LastAccessedAt time.Time    `json:"last_accessed_at"`
+	Workspace      string       `json:"workspace,omitempty"`
 }
 
 // Insight represents a derived insight
@@ -114,6 +119,7 @@ type Insight struct {
 	SupportingEvidence Metadata      `json:"supporting_evidence"`
 	Validations        []*Validation `json:"validations,omitempty"`
 	CreatedAt          time.Time     `json:"created_at"`
+	Workspace          string        `json:"workspace,omitempty"`
 }
 
 // CrossRef represents a cross-reference between branches
@@ -135,6 +141,94 @@ type TouchPoint struct {
 	Connection  string `json:"connection"`
 }
 
+// CheckpointRecord is the persisted form of a backtracking checkpoint
+type CheckpointRecord struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	BranchID    string                 `json:"branch_id"`
+	SnapshotID  string                 `json:"snapshot_id"`
+	DeltaCount  int                    `json:"delta_count"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// BranchSnapshotRecord is the persisted full state of a branch at a point in time
+type BranchSnapshotRecord struct {
+	ID            string    `json:"id"`
+	BranchID      string    `json:"branch_id"`
+	Branch        *Branch   `json:"branch"`
+	ThoughtCount  int       `json:"thought_count"`
+	InsightCount  int       `json:"insight_count"`
+	CrossRefCount int       `json:"cross_ref_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BranchDeltaRecord is a persisted change applied on top of a branch snapshot.
+// Entity holds the JSON encoding of the thought, insight or cross-reference.
+type BranchDeltaRecord struct {
+	ID         string          `json:"id"`
+	SnapshotID string          `json:"snapshot_id"`
+	BranchID   string          `json:"branch_id"`
+	Operation  string          `json:"operation"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Entity     json.RawMessage `json:"entity,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// GraphStateSummary describes a stored Graph-of-Thoughts state without its vertices
+type GraphStateSummary struct {
+	ID             string    `json:"id"`
+	InitialThought string    `json:"initial_thought"`
+	VertexCount    int       `json:"vertex_count"`
+	EdgeCount      int       `json:"edge_count"`
+	ActiveCount    int       `json:"active_count"`
+	TerminalCount  int       `json:"terminal_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// GraphStateRecord is a persisted Graph-of-Thoughts state. State holds the
+// JSON encoding of the full graph.
+type GraphStateRecord struct {
+	GraphStateSummary
+	State json.RawMessage `json:"state"`
+}
+
+// WorkflowVersionRecord is a stored version of an orchestration workflow.
+// Definition holds the JSON encoding of the workflow.
+type WorkflowVersionRecord struct {
+	WorkflowID string          `json:"workflow_id"`
+	Version    int             `json:"version"`
+	Name       string          `json:"name"`
+	Source     string          `json:"source"`   // "tool" or the file it was loaded from
+	Checksum   string          `json:"checksum"` // SHA-256 of Definition
+	Definition json.RawMessage `json:"definition,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// WorkflowRunSummary describes a recorded orchestration workflow run
+type WorkflowRunSummary struct {
+	ID              string     `json:"id"`
+	Workspace       string     `json:"workspace,omitempty"`
+	WorkflowID      string     `json:"workflow_id"`
+	WorkflowVersion int        `json:"workflow_version,omitempty"`
+	Trigger         string     `json:"trigger"` // "execute", "resume" or "rerun"
+	ParentRunID     string     `json:"parent_run_id,omitempty"`
+	Status          string     `json:"status"` // "running", "success", "partial" or "failed"
+	Error           string     `json:"error,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// WorkflowRunRecord is a persisted workflow run. Run holds the JSON encoding
+// of the full run, including inputs and step outputs.
+type WorkflowRunRecord struct {
+	WorkflowRunSummary
+	Run json.RawMessage `json:"run"`
+}
+
 // Validation represents logical validation results
 type Validation struct {
 	ID             string    `json:"id"`
@@ -340,6 +434,17 @@ type CausalGraph struct {
 	CreatedAt   time.Time         `json:"created_at"`
 }
 
+// CausalGraphSummary describes a stored causal graph without its full structure
+type CausalGraphSummary struct {
+	ID                  string    `json:"id"`
+	Description         string    `json:"description"`
+	VariableCount       int       `json:"variable_count"`
+	LinkCount           int       `json:"link_count"`
+	InterventionCount   int       `json:"intervention_count"`
+	CounterfactualCount int       `json:"counterfactual_count"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
 // CausalVariable represents a variable in a causal model
 type CausalVariable struct {
 	ID          string   `json:"id"`
@@ -0,0 +1,47 @@
+package usage
+
+import (
+	"context"
+	"sync"
+
+	ccerrors "unified-thinking/internal/claudecode/errors"
+)
+
+type callKey struct{}
+
+// Call identifies the tool call and episodic session that API usage is
+// attributed to
+type Call struct {
+	Tool      string
+	SessionID string
+
+	mu       sync.Mutex
+	rejected *ccerrors.StructuredError
+}
+
+// WithCall returns a context attributing usage to a tool and session
+func WithCall(ctx context.Context, tool, sessionID string) (context.Context, *Call) {
+	call := &Call{Tool: tool, SessionID: sessionID}
+	return context.WithValue(ctx, callKey{}, call), call
+}
+
+// CallFromContext returns the call in ctx, or nil
+func CallFromContext(ctx context.Context) *Call {
+	call, _ := ctx.Value(callKey{}).(*Call)
+	return call
+}
+
+// BudgetError returns the budget error raised during the call, if any
+func (c *Call) BudgetError() *ccerrors.StructuredError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rejected
+}
+
+func (c *Call) reject(err *ccerrors.StructuredError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rejected == nil {
+		c.rejected = err
+	}
+}
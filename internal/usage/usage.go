@@ -0,0 +1,278 @@
+// Package usage records the tokens consumed by LLM and embedding API calls,
+// attributes them to the calling tool, episodic session and model, and
+// enforces configurable token and cost budgets.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	ccerrors "unified-thinking/internal/claudecode/errors"
+)
+
+// Unattributed is the tool name recorded for calls made outside a tool call
+const Unattributed = "unattributed"
+
+// Price is the cost of a model in USD per million tokens
+type Price struct {
+	InputPerMTok  float64 `json:"input_per_mtok"`
+	OutputPerMTok float64 `json:"output_per_mtok"`
+}
+
+// DefaultPrices returns list prices for the models the server uses by
+// default. Keys are model ID prefixes, so dated snapshots match their base
+// model. Models without a price, such as local ones, cost nothing.
+func DefaultPrices() map[string]Price {
+	return map[string]Price{
+		"claude-opus-4":       {InputPerMTok: 15, OutputPerMTok: 75},
+		"claude-opus-4-5":     {InputPerMTok: 5, OutputPerMTok: 25},
+		"claude-sonnet-4":     {InputPerMTok: 3, OutputPerMTok: 15},
+		"claude-haiku-4-5":    {InputPerMTok: 1, OutputPerMTok: 5},
+		"claude-3-5-haiku":    {InputPerMTok: 0.8, OutputPerMTok: 4},
+		"gpt-4o":              {InputPerMTok: 2.5, OutputPerMTok: 10},
+		"gpt-4o-mini":         {InputPerMTok: 0.15, OutputPerMTok: 0.6},
+		"voyage-3":            {InputPerMTok: 0.06},
+		"voyage-3-lite":       {InputPerMTok: 0.02},
+		"voyage-3-large":      {InputPerMTok: 0.18},
+		"voyage-code-3":       {InputPerMTok: 0.18},
+		"voyage-multimodal-3": {InputPerMTok: 0.12},
+		"rerank-2":            {InputPerMTok: 0.05},
+		"rerank-2-lite":       {InputPerMTok: 0.02},
+	}
+}
+
+// Budget limits token usage. Zero fields are unlimited.
+type Budget struct {
+	MaxTokens         int64   `json:"max_tokens,omitempty"`
+	MaxCostUSD        float64 `json:"max_cost_usd,omitempty"`
+	MaxSessionTokens  int64   `json:"max_session_tokens,omitempty"`
+	MaxSessionCostUSD float64 `json:"max_session_cost_usd,omitempty"`
+}
+
+// IsZero reports whether the budget has no limits
+func (b Budget) IsZero() bool {
+	return b == Budget{}
+}
+
+// BudgetFromEnv reads USAGE_BUDGET_TOKENS, USAGE_BUDGET_COST_USD,
+// USAGE_BUDGET_SESSION_TOKENS and USAGE_BUDGET_SESSION_COST_USD
+func BudgetFromEnv() (Budget, error) {
+	var budget Budget
+	for _, v := range []struct {
+		name string
+		dst  *int64
+	}{
+		{"USAGE_BUDGET_TOKENS", &budget.MaxTokens},
+		{"USAGE_BUDGET_SESSION_TOKENS", &budget.MaxSessionTokens},
+	} {
+		if s := os.Getenv(v.name); s != "" {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil || n < 0 {
+				return Budget{}, fmt.Errorf("invalid %s %q: must be a non-negative integer", v.name, s)
+			}
+			*v.dst = n
+		}
+	}
+	for _, v := range []struct {
+		name string
+		dst  *float64
+	}{
+		{"USAGE_BUDGET_COST_USD", &budget.MaxCostUSD},
+		{"USAGE_BUDGET_SESSION_COST_USD", &budget.MaxSessionCostUSD},
+	} {
+		if s := os.Getenv(v.name); s != "" {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil || f < 0 {
+				return Budget{}, fmt.Errorf("invalid %s %q: must be a non-negative number", v.name, s)
+			}
+			*v.dst = f
+		}
+	}
+	return budget, nil
+}
+
+// Totals aggregates the usage of a group of calls
+type Totals struct {
+	Calls        int64   `json:"calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalTokens  int64   `json:"total_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+func (t *Totals) add(input, output int64, cost float64) {
+	t.Calls++
+	t.InputTokens += input
+	t.OutputTokens += output
+	t.TotalTokens += input + output
+	t.CostUSD += cost
+}
+
+// Summary is a snapshot of recorded usage
+type Summary struct {
+	Total     Totals            `json:"total"`
+	ByTool    map[string]Totals `json:"by_tool"`
+	BySession map[string]Totals `json:"by_session,omitempty"`
+	ByModel   map[string]Totals `json:"by_model"`
+	Budget    *Budget           `json:"budget,omitempty"`
+	Rejected  int64             `json:"rejected"`
+}
+
+// Tracker records usage and enforces a budget. A nil *Tracker records
+// nothing and never rejects, so clients can hold one unconditionally.
+type Tracker struct {
+	mu        sync.Mutex
+	budget    Budget
+	prices    map[string]Price
+	total     Totals
+	byTool    map[string]*Totals
+	bySession map[string]*Totals
+	byModel   map[string]*Totals
+	rejected  int64
+}
+
+// NewTracker creates a tracker with the default prices
+func NewTracker(budget Budget) *Tracker {
+	return &Tracker{
+		budget:    budget,
+		prices:    DefaultPrices(),
+		byTool:    make(map[string]*Totals),
+		bySession: make(map[string]*Totals),
+		byModel:   make(map[string]*Totals),
+	}
+}
+
+// SetPrice sets the price of a model ID prefix
+func (t *Tracker) SetPrice(model string, price Price) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prices[model] = price
+}
+
+// cost returns the cost of a call, using the longest matching price prefix
+func (t *Tracker) cost(model string, input, output int64) float64 {
+	var best string
+	for prefix := range t.prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return 0
+	}
+	price := t.prices[best]
+	return (float64(input)*price.InputPerMTok + float64(output)*price.OutputPerMTok) / 1e6
+}
+
+// Record attributes a call's tokens to the tool and session in ctx
+func (t *Tracker) Record(ctx context.Context, model string, inputTokens, outputTokens int) {
+	if t == nil {
+		return
+	}
+	call := CallFromContext(ctx)
+	tool := Unattributed
+	if call != nil && call.Tool != "" {
+		tool = call.Tool
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	input, output := int64(inputTokens), int64(outputTokens)
+	cost := t.cost(model, input, output)
+	t.total.add(input, output, cost)
+	group(t.byTool, tool).add(input, output, cost)
+	group(t.byModel, model).add(input, output, cost)
+	if call != nil && call.SessionID != "" {
+		group(t.bySession, call.SessionID).add(input, output, cost)
+	}
+}
+
+func group(groups map[string]*Totals, key string) *Totals {
+	totals, ok := groups[key]
+	if !ok {
+		totals = &Totals{}
+		groups[key] = totals
+	}
+	return totals
+}
+
+// Check returns an ERR_5007_BUDGET_EXHAUSTED structured error when the
+// global budget or the budget of the session in ctx is used up. The error
+// is also remembered on the call, so the tool can report it even if the
+// caller wraps or swallows it.
+func (t *Tracker) Check(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	call := CallFromContext(ctx)
+
+	t.mu.Lock()
+	var details string
+	switch {
+	case t.budget.MaxTokens > 0 && t.total.TotalTokens >= t.budget.MaxTokens:
+		details = fmt.Sprintf("%d of %d tokens used", t.total.TotalTokens, t.budget.MaxTokens)
+	case t.budget.MaxCostUSD > 0 && t.total.CostUSD >= t.budget.MaxCostUSD:
+		details = fmt.Sprintf("$%.4f of $%.4f used", t.total.CostUSD, t.budget.MaxCostUSD)
+	case call != nil && call.SessionID != "":
+		if session, ok := t.bySession[call.SessionID]; ok {
+			switch {
+			case t.budget.MaxSessionTokens > 0 && session.TotalTokens >= t.budget.MaxSessionTokens:
+				details = fmt.Sprintf("session %s used %d of %d tokens", call.SessionID, session.TotalTokens, t.budget.MaxSessionTokens)
+			case t.budget.MaxSessionCostUSD > 0 && session.CostUSD >= t.budget.MaxSessionCostUSD:
+				details = fmt.Sprintf("session %s used $%.4f of $%.4f", call.SessionID, session.CostUSD, t.budget.MaxSessionCostUSD)
+			}
+		}
+	}
+	if details != "" {
+		t.rejected++
+	}
+	t.mu.Unlock()
+
+	if details == "" {
+		return nil
+	}
+	err := ccerrors.EnhanceError(
+		ccerrors.NewStructuredError(ccerrors.ErrBudgetExhausted, "usage budget exhausted, the API was not called").
+			WithDetails(details))
+	if call != nil {
+		call.reject(err)
+	}
+	return err
+}
+
+// Summary returns a snapshot of the recorded usage
+func (t *Tracker) Summary() Summary {
+	if t == nil {
+		return Summary{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	summary := Summary{
+		Total:    t.total,
+		ByTool:   snapshot(t.byTool),
+		ByModel:  snapshot(t.byModel),
+		Rejected: t.rejected,
+	}
+	if len(t.bySession) > 0 {
+		summary.BySession = snapshot(t.bySession)
+	}
+	if !t.budget.IsZero() {
+		budget := t.budget
+		summary.Budget = &budget
+	}
+	return summary
+}
+
+func snapshot(groups map[string]*Totals) map[string]Totals {
+	result := make(map[string]Totals, len(groups))
+	for key, totals := range groups {
+		result[key] = *totals
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	ccerrors "unified-thinking/internal/claudecode/errors"
+)
+
+func TestTracker_RecordAttributesUsage(t *testing.T) {
+	tracker := NewTracker(Budget{})
+	ctx, _ := WithCall(context.Background(), "got-generate", "session-1")
+
+	tracker.Record(ctx, "claude-sonnet-4-5-20250929", 1000, 200)
+	tracker.Record(ctx, "voyage-3-lite", 500, 0)
+	tracker.Record(context.Background(), "llama3.1", 10, 5)
+
+	summary := tracker.Summary()
+	if summary.Total.Calls != 3 || summary.Total.TotalTokens != 1715 {
+		t.Errorf("total = %+v", summary.Total)
+	}
+	if got := summary.ByTool["got-generate"]; got.Calls != 2 || got.InputTokens != 1500 || got.OutputTokens != 200 {
+		t.Errorf("by_tool[got-generate] = %+v", got)
+	}
+	if got := summary.ByTool[Unattributed]; got.TotalTokens != 15 || got.CostUSD != 0 {
+		t.Errorf("unattributed = %+v, want 15 free tokens", got)
+	}
+	if got := summary.BySession["session-1"]; got.Calls != 2 {
+		t.Errorf("by_session = %+v", summary.BySession)
+	}
+
+	// Dated snapshots use the price of their base model
+	wantCost := (1000*3.0 + 200*15.0) / 1e6
+	if got := summary.ByModel["claude-sonnet-4-5-20250929"].CostUSD; math.Abs(got-wantCost) > 1e-12 {
+		t.Errorf("sonnet cost = %v, want %v", got, wantCost)
+	}
+	if summary.Budget != nil {
+		t.Errorf("budget = %+v, want none", summary.Budget)
+	}
+}
+
+func TestTracker_LongestPricePrefixWins(t *testing.T) {
+	tracker := NewTracker(Budget{})
+	if got := tracker.cost("gpt-4o-mini-2024-07-18", 1e6, 0); got != 0.15 {
+		t.Errorf("gpt-4o-mini cost = %v, want 0.15", got)
+	}
+	tracker.SetPrice("llama", Price{InputPerMTok: 1})
+	if got := tracker.cost("llama3.1", 2e6, 0); got != 2 {
+		t.Errorf("custom price cost = %v, want 2", got)
+	}
+}
+
+func TestTracker_GlobalBudget(t *testing.T) {
+	tracker := NewTracker(Budget{MaxTokens: 100})
+	ctx, call := WithCall(context.Background(), "think", "")
+
+	if err := tracker.Check(ctx); err != nil {
+		t.Fatalf("Check before usage: %v", err)
+	}
+	tracker.Record(ctx, "m", 80, 20)
+
+	err := tracker.Check(ctx)
+	var structured *ccerrors.StructuredError
+	if !errors.As(err, &structured) || structured.Code != ccerrors.ErrBudgetExhausted {
+		t.Fatalf("err = %v, want %s", err, ccerrors.ErrBudgetExhausted)
+	}
+	if len(structured.RecoverySuggestions) == 0 || len(structured.RelatedTools) == 0 {
+		t.Errorf("budget error lacks recovery information: %+v", structured)
+	}
+	if call.BudgetError() != structured {
+		t.Error("budget error should be remembered on the call")
+	}
+	if got := tracker.Summary().Rejected; got != 1 {
+		t.Errorf("rejected = %d, want 1", got)
+	}
+}
+
+func TestTracker_SessionBudget(t *testing.T) {
+	tracker := NewTracker(Budget{MaxSessionCostUSD: 0.01})
+	first, _ := WithCall(context.Background(), "think", "first")
+	second, _ := WithCall(context.Background(), "think", "second")
+
+	tracker.Record(first, "claude-opus-4-1", 1000, 0) // $0.015
+	if err := tracker.Check(first); err == nil {
+		t.Error("expected the first session to be over budget")
+	}
+	if err := tracker.Check(second); err != nil {
+		t.Errorf("other sessions should be unaffected: %v", err)
+	}
+	if err := tracker.Check(context.Background()); err != nil {
+		t.Errorf("calls without a session should be unaffected: %v", err)
+	}
+}
+
+func TestTracker_NilIsNoop(t *testing.T) {
+	var tracker *Tracker
+	tracker.Record(context.Background(), "m", 1, 1)
+	if err := tracker.Check(context.Background()); err != nil {
+		t.Errorf("Check on nil tracker = %v", err)
+	}
+	if summary := tracker.Summary(); summary.Total.Calls != 0 {
+		t.Errorf("Summary on nil tracker = %+v", summary)
+	}
+}
+
+func TestBudgetFromEnv(t *testing.T) {
+	t.Setenv("USAGE_BUDGET_TOKENS", "100000")
+	t.Setenv("USAGE_BUDGET_SESSION_TOKENS", "")
+	t.Setenv("USAGE_BUDGET_COST_USD", "2.5")
+	t.Setenv("USAGE_BUDGET_SESSION_COST_USD", "0.5")
+
+	budget, err := BudgetFromEnv()
+	if err != nil {
+		t.Fatalf("BudgetFromEnv: %v", err)
+	}
+	want := Budget{MaxTokens: 100000, MaxCostUSD: 2.5, MaxSessionCostUSD: 0.5}
+	if budget != want {
+		t.Errorf("budget = %+v, want %+v", budget, want)
+	}
+
+	t.Setenv("USAGE_BUDGET_SESSION_TOKENS", "-1")
+	if _, err := BudgetFromEnv(); err == nil {
+		t.Error("expected error for negative budget")
+	}
+}
@@ -0,0 +1,719 @@
+// Package validation - Loadable fallacy rule packs.
+//
+// A rule pack is a YAML or JSON document naming a set of rules. Each rule
+// combines phrase and pattern matches (optionally within a window of a few
+// sentences) with built-in structural checks, and carries the severity,
+// explanation and fix suggestion reported when it fires. Labelled examples
+// in the rule double as its test set: they are evaluated when the pack is
+// added to give the rule's precision and recall.
+package validation
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed fallacy_rules/core.yaml
+var coreFallacyRules []byte
+
+// DefaultRuleWorkspace is the workspace whose rule settings apply when no
+// workspace is given. It matches the storage layer's default workspace.
+const DefaultRuleWorkspace = "default"
+
+// Rule severities
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// Excerpt window around the highlighted phrase, in bytes
+const (
+	defaultExcerptBefore = 30
+	defaultExcerptAfter  = 70
+)
+
+// fallacyChecks are the structural checks rules can reference by name. They
+// receive the lower-cased text in the rule's scope.
+var fallacyChecks = map[string]func(fd *FallacyDetector, text string) bool{
+	"affirming_consequent": (*FallacyDetector).detectAffirmingConsequent,
+	"denying_antecedent":   (*FallacyDetector).detectDenyingAntecedent,
+	"circular_reasoning":   (*FallacyDetector).detectCircularReasoning,
+}
+
+var ruleIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// FallacyRulePack is a named set of fallacy rules
+type FallacyRulePack struct {
+	Name        string         `json:"name"` // Defaults to the file name when loaded from a directory
+	Description string         `json:"description,omitempty"`
+	Workspaces  []string       `json:"workspaces,omitempty"` // Workspaces the rules are enabled in (default: all)
+	Rules       []*FallacyRule `json:"rules"`
+}
+
+// FallacyRule describes one fallacy and how to detect it
+type FallacyRule struct {
+	ID          string          `json:"id"`                   // Reported as the fallacy type
+	Name        string          `json:"name,omitempty"`       // Human-readable name
+	Category    FallacyType     `json:"category"`             // formal, informal, statistical or a custom category
+	Severity    string          `json:"severity,omitempty"`   // low, medium (default), high
+	Confidence  float64         `json:"confidence"`           // Confidence reported for detections, 0.0-1.0
+	Location    string          `json:"location,omitempty"`   // Where the problem lies, e.g. "causal reasoning"
+	Explanation string          `json:"explanation"`          // What's wrong
+	Correction  string          `json:"correction,omitempty"` // How to fix it
+	Match       *FallacyMatch   `json:"match"`                // When the rule fires
+	Context     *FallacyContext `json:"context,omitempty"`    // Matching scope and excerpt size
+	Highlight   []string        `json:"highlight,omitempty"`  // Phrases the excerpt is centered on (default: the matched terms)
+	Examples    FallacyExamples `json:"examples,omitempty"`   // Labelled texts used for precision stats
+	Enabled     *bool           `json:"enabled,omitempty"`    // Default state in every workspace (default true)
+
+	pack       string
+	workspaces []string
+	stats      FallacyRuleStats
+}
+
+// FallacyMatch is a matching condition. All of its parts must hold: the
+// phrase test on terms and patterns, the structural check, every all
+// condition, at least one any condition and no none condition.
+type FallacyMatch struct {
+	Terms       []string        `json:"terms,omitempty"`       // Case-insensitive phrases, found anywhere in the scope
+	Patterns    []string        `json:"patterns,omitempty"`    // Case-insensitive regular expressions
+	Min         int             `json:"min,omitempty"`         // Distinct terms and patterns that must match (default 1)
+	Occurrences int             `json:"occurrences,omitempty"` // Total matches of the terms and patterns required instead of min
+	Check       string          `json:"check,omitempty"`       // Structural check: affirming_consequent, denying_antecedent, circular_reasoning
+	All         []*FallacyMatch `json:"all,omitempty"`
+	Any         []*FallacyMatch `json:"any,omitempty"`
+	None        []*FallacyMatch `json:"none,omitempty"`
+
+	compiled []*regexp.Regexp
+}
+
+// FallacyContext sets the window a rule is matched in and the size of the
+// excerpt reported with a detection
+type FallacyContext struct {
+	Sentences int `json:"sentences,omitempty"` // Match within this many consecutive sentences (default: whole text)
+	Before    int `json:"before,omitempty"`    // Excerpt characters before the highlight (default 30)
+	After     int `json:"after,omitempty"`     // Excerpt characters after the highlight (default 70)
+}
+
+// FallacyExamples are labelled texts for a rule
+type FallacyExamples struct {
+	Positive []string `json:"positive,omitempty"` // Texts the rule should flag
+	Negative []string `json:"negative,omitempty"` // Texts it should not flag
+}
+
+// FallacyRuleStats reports how a rule does on its examples and in use
+type FallacyRuleStats struct {
+	Positives      int      `json:"positives"`              // Positive examples
+	Negatives      int      `json:"negatives"`              // Negative examples
+	TruePositives  int      `json:"true_positives"`         // Positive examples flagged
+	FalsePositives int      `json:"false_positives"`        // Negative examples flagged
+	Precision      *float64 `json:"precision,omitempty"`    // Omitted when no example is flagged
+	Recall         *float64 `json:"recall,omitempty"`       // Omitted without positive examples
+	Missed         []string `json:"missed,omitempty"`       // Positive examples not flagged
+	FalseAlarms    []string `json:"false_alarms,omitempty"` // Negative examples flagged
+	Detections     int      `json:"detections"`             // Detections since the server started
+}
+
+// FallacyRuleInfo describes a rule and its state in a workspace
+type FallacyRuleInfo struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name,omitempty"`
+	Pack        string           `json:"pack"`
+	Category    FallacyType      `json:"category"`
+	Severity    string           `json:"severity"`
+	Confidence  float64          `json:"confidence"`
+	Explanation string           `json:"explanation"`
+	Correction  string           `json:"correction,omitempty"`
+	Enabled     bool             `json:"enabled"`
+	Stats       FallacyRuleStats `json:"stats"`
+}
+
+// FallacyRulePackInfo summarizes a loaded rule pack
+type FallacyRulePackInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Workspaces  []string `json:"workspaces,omitempty"`
+	Rules       int      `json:"rules"`
+}
+
+// ParseFallacyRulePack decodes a JSON or YAML (".yaml", ".yml") rule pack.
+// Unknown fields are rejected so that typos do not go unnoticed.
+func ParseFallacyRulePack(data []byte, ext string) (*FallacyRulePack, error) {
+	if ext == ".yaml" || ext == ".yml" {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		data = converted
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var pack FallacyRulePack
+	if err := decoder.Decode(&pack); err != nil {
+		return nil, fmt.Errorf("invalid rule pack: %w", err)
+	}
+	return &pack, nil
+}
+
+// LoadRulePackDir adds the rule packs (*.json, *.yaml, *.yml) in a
+// directory. Packs without a name are named after their file.
+func (fd *FallacyDetector) LoadRulePackDir(dir string) ([]*FallacyRulePack, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule pack directory: %w", err)
+	}
+
+	var loaded []*FallacyRulePack
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return loaded, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		pack, err := ParseFallacyRulePack(data, ext)
+		if err != nil {
+			return loaded, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if pack.Name == "" {
+			pack.Name = strings.ToLower(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+		}
+		if err := fd.AddRulePack(pack); err != nil {
+			return loaded, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		loaded = append(loaded, pack)
+	}
+	return loaded, nil
+}
+
+// AddRulePack validates and adds a rule pack. A pack replaces an earlier
+// pack of the same name, and a rule replaces an earlier rule with the same
+// ID (including core rules), keeping its position.
+func (fd *FallacyDetector) AddRulePack(pack *FallacyRulePack) error {
+	if err := pack.validate(); err != nil {
+		return err
+	}
+	for _, rule := range pack.Rules {
+		rule.pack = pack.Name
+		rule.workspaces = pack.Workspaces
+		rule.stats = rule.evaluate(fd)
+	}
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	rules := append([]*FallacyRule(nil), fd.rules...)
+	index := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		index[rule.ID] = i
+	}
+	added := make(map[string]bool, len(pack.Rules))
+	for _, rule := range pack.Rules {
+		added[rule.ID] = true
+		if i, ok := index[rule.ID]; ok {
+			rules[i] = rule
+		} else {
+			rules = append(rules, rule)
+		}
+	}
+
+	fd.rules = rules[:0]
+	for _, rule := range rules {
+		if rule.pack != pack.Name || added[rule.ID] {
+			fd.rules = append(fd.rules, rule)
+		}
+	}
+
+	for i, existing := range fd.packs {
+		if existing.Name == pack.Name {
+			fd.packs[i] = pack
+			return nil
+		}
+	}
+	fd.packs = append(fd.packs, pack)
+	return nil
+}
+
+// ConfigureRules enables and disables rules in a workspace. With reset the
+// workspace's earlier settings are dropped first, restoring the pack
+// defaults. Unknown rule IDs are rejected without changing anything.
+// Settings are kept in memory only and are lost when the detector is.
+func (fd *FallacyDetector) ConfigureRules(workspace string, enable, disable []string, reset bool) error {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	known := make(map[string]bool, len(fd.rules))
+	for _, rule := range fd.rules {
+		known[rule.ID] = true
+	}
+	enabling := make(map[string]bool, len(enable))
+	for _, id := range enable {
+		if !known[id] {
+			return fmt.Errorf("unknown fallacy rule %q", id)
+		}
+		enabling[id] = true
+	}
+	for _, id := range disable {
+		if !known[id] {
+			return fmt.Errorf("unknown fallacy rule %q", id)
+		}
+		if enabling[id] {
+			return fmt.Errorf("fallacy rule %q is both enabled and disabled", id)
+		}
+	}
+
+	workspace = ruleWorkspace(workspace)
+	if reset {
+		delete(fd.workspaces, workspace)
+	}
+	if len(enable)+len(disable) == 0 {
+		return nil
+	}
+	settings := fd.workspaces[workspace]
+	if settings == nil {
+		settings = make(map[string]bool)
+		fd.workspaces[workspace] = settings
+	}
+	for _, id := range enable {
+		settings[id] = true
+	}
+	for _, id := range disable {
+		settings[id] = false
+	}
+	return nil
+}
+
+// ListRules describes every rule, in detection order, with its state in a
+// workspace
+func (fd *FallacyDetector) ListRules(workspace string) []*FallacyRuleInfo {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	settings := fd.workspaces[ruleWorkspace(workspace)]
+	infos := make([]*FallacyRuleInfo, 0, len(fd.rules))
+	for _, rule := range fd.rules {
+		stats := rule.stats
+		stats.Detections = fd.detections[rule.ID]
+		infos = append(infos, &FallacyRuleInfo{
+			ID:          rule.ID,
+			Name:        rule.Name,
+			Pack:        rule.pack,
+			Category:    rule.Category,
+			Severity:    rule.Severity,
+			Confidence:  rule.Confidence,
+			Explanation: rule.Explanation,
+			Correction:  rule.Correction,
+			Enabled:     rule.enabledIn(workspace, settings),
+			Stats:       stats,
+		})
+	}
+	return infos
+}
+
+// RulePacks summarizes the loaded rule packs in load order
+func (fd *FallacyDetector) RulePacks() []*FallacyRulePackInfo {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	counts := make(map[string]int, len(fd.packs))
+	for _, rule := range fd.rules {
+		counts[rule.pack]++
+	}
+	infos := make([]*FallacyRulePackInfo, 0, len(fd.packs))
+	for _, pack := range fd.packs {
+		infos = append(infos, &FallacyRulePackInfo{
+			Name:        pack.Name,
+			Description: pack.Description,
+			Workspaces:  pack.Workspaces,
+			Rules:       counts[pack.Name],
+		})
+	}
+	return infos
+}
+
+// enabledRules returns the rules enabled in a workspace, in detection order
+func (fd *FallacyDetector) enabledRules(workspace string) []*FallacyRule {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	settings := fd.workspaces[ruleWorkspace(workspace)]
+	rules := make([]*FallacyRule, 0, len(fd.rules))
+	for _, rule := range fd.rules {
+		if rule.enabledIn(workspace, settings) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// ruleWorkspace normalizes a workspace name the way the storage layer does
+func ruleWorkspace(workspace string) string {
+	workspace = strings.ToLower(strings.TrimSpace(workspace))
+	if workspace == "" {
+		return DefaultRuleWorkspace
+	}
+	return workspace
+}
+
+// enabledIn reports whether the rule is enabled in a workspace: an explicit
+// workspace setting wins, then the pack's workspace list, then the rule's
+// default
+func (r *FallacyRule) enabledIn(workspace string, settings map[string]bool) bool {
+	if enabled, ok := settings[r.ID]; ok {
+		return enabled
+	}
+	if len(r.workspaces) > 0 {
+		workspace = ruleWorkspace(workspace)
+		found := false
+		for _, w := range r.workspaces {
+			if w == workspace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return r.Enabled == nil || *r.Enabled
+}
+
+// detect matches the rule against content, returning the excerpt of the
+// first matching scope
+func (r *FallacyRule) detect(fd *FallacyDetector, content string) (string, bool) {
+	for _, scope := range r.Context.scopes(content) {
+		lower := strings.ToLower(scope)
+		if !r.Match.matches(fd, lower) {
+			continue
+		}
+		idx := -1
+		if len(r.Highlight) > 0 {
+			idx = keywordIndex(scope, r.Highlight)
+		} else {
+			idx = r.Match.index(lower)
+		}
+		before, after := defaultExcerptBefore, defaultExcerptAfter
+		if r.Context != nil && r.Context.Before > 0 {
+			before = r.Context.Before
+		}
+		if r.Context != nil && r.Context.After > 0 {
+			after = r.Context.After
+		}
+		return excerpt(scope, idx, before, after), true
+	}
+	return "", false
+}
+
+// fallacy reports a detection of the rule
+func (r *FallacyRule) fallacy(example string) *DetectedFallacy {
+	return &DetectedFallacy{
+		Type:        r.ID,
+		Category:    r.Category,
+		Severity:    r.Severity,
+		Location:    r.Location,
+		Explanation: r.Explanation,
+		Example:     example,
+		Correction:  r.Correction,
+		Confidence:  r.Confidence,
+	}
+}
+
+// evaluate runs the rule on its examples
+func (r *FallacyRule) evaluate(fd *FallacyDetector) FallacyRuleStats {
+	stats := FallacyRuleStats{
+		Positives: len(r.Examples.Positive),
+		Negatives: len(r.Examples.Negative),
+	}
+	for _, example := range r.Examples.Positive {
+		if _, ok := r.detect(fd, example); ok {
+			stats.TruePositives++
+		} else {
+			stats.Missed = append(stats.Missed, example)
+		}
+	}
+	for _, example := range r.Examples.Negative {
+		if _, ok := r.detect(fd, example); ok {
+			stats.FalsePositives++
+			stats.FalseAlarms = append(stats.FalseAlarms, example)
+		}
+	}
+
+	if flagged := stats.TruePositives + stats.FalsePositives; flagged > 0 {
+		precision := float64(stats.TruePositives) / float64(flagged)
+		stats.Precision = &precision
+	}
+	if stats.Positives > 0 {
+		recall := float64(stats.TruePositives) / float64(stats.Positives)
+		stats.Recall = &recall
+	}
+	return stats
+}
+
+// scopes splits content into the windows a rule is matched in
+func (c *FallacyContext) scopes(content string) []string {
+	if c == nil || c.Sentences <= 0 {
+		return []string{content}
+	}
+	spans := sentenceSpans(content)
+	if len(spans) <= c.Sentences {
+		return []string{content}
+	}
+	scopes := make([]string, 0, len(spans)-c.Sentences+1)
+	for i := 0; i+c.Sentences <= len(spans); i++ {
+		scopes = append(scopes, content[spans[i][0]:spans[i+c.Sentences-1][1]])
+	}
+	return scopes
+}
+
+// sentenceSpans returns the byte ranges of the sentences in content. A
+// sentence ends at '.', '!' or '?' followed by whitespace or the end of the
+// text, so numbers such as "1.5x" do not split.
+func sentenceSpans(content string) [][2]int {
+	var spans [][2]int
+	start := 0
+	for i := 0; i <= len(content); i++ {
+		if i < len(content) {
+			if c := content[i]; c != '.' && c != '!' && c != '?' {
+				continue
+			}
+			if i+1 < len(content) && !unicode.IsSpace(rune(content[i+1])) {
+				continue
+			}
+		}
+		end := min(i+1, len(content))
+		for start < end && unicode.IsSpace(rune(content[start])) {
+			start++
+		}
+		if start < end {
+			spans = append(spans, [2]int{start, end})
+		}
+		start = end
+	}
+	return spans
+}
+
+// matches evaluates the condition on lower-cased text
+func (m *FallacyMatch) matches(fd *FallacyDetector, text string) bool {
+	if len(m.Terms)+len(m.compiled) > 0 && !m.matchesPhrases(text) {
+		return false
+	}
+	if m.Check != "" && !fallacyChecks[m.Check](fd, text) {
+		return false
+	}
+	for _, sub := range m.All {
+		if !sub.matches(fd, text) {
+			return false
+		}
+	}
+	if len(m.Any) > 0 {
+		found := false
+		for _, sub := range m.Any {
+			if sub.matches(fd, text) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, sub := range m.None {
+		if sub.matches(fd, text) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesPhrases applies the min or occurrences test to terms and patterns
+func (m *FallacyMatch) matchesPhrases(text string) bool {
+	if m.Occurrences > 0 {
+		count := 0
+		for _, term := range m.Terms {
+			count += strings.Count(text, term)
+		}
+		for _, re := range m.compiled {
+			count += len(re.FindAllStringIndex(text, -1))
+		}
+		return count >= m.Occurrences
+	}
+
+	need := max(m.Min, 1)
+	found := 0
+	for _, term := range m.Terms {
+		if strings.Contains(text, term) {
+			found++
+		}
+	}
+	for _, re := range m.compiled {
+		if re.MatchString(text) {
+			found++
+		}
+	}
+	return found >= need
+}
+
+// index returns the earliest position of a term or pattern of the
+// condition (ignoring none conditions), or -1
+func (m *FallacyMatch) index(text string) int {
+	best := -1
+	consider := func(idx int) {
+		if idx != -1 && (best == -1 || idx < best) {
+			best = idx
+		}
+	}
+	for _, term := range m.Terms {
+		consider(strings.Index(text, term))
+	}
+	for _, re := range m.compiled {
+		if loc := re.FindStringIndex(text); loc != nil {
+			consider(loc[0])
+		}
+	}
+	for _, sub := range append(append([]*FallacyMatch(nil), m.All...), m.Any...) {
+		consider(sub.index(text))
+	}
+	return best
+}
+
+// validate checks a rule pack and prepares its rules for matching
+func (p *FallacyRulePack) validate() error {
+	if !ruleIDPattern.MatchString(p.Name) {
+		return fmt.Errorf("rule pack name %q must be lower-case letters, digits, '-' and '_'", p.Name)
+	}
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("rule pack %s has no rules", p.Name)
+	}
+	for i, workspace := range p.Workspaces {
+		p.Workspaces[i] = ruleWorkspace(workspace)
+	}
+
+	seen := make(map[string]bool, len(p.Rules))
+	for i, rule := range p.Rules {
+		if rule == nil {
+			return fmt.Errorf("rule pack %s: rule %d is empty", p.Name, i)
+		}
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule pack %s: %w", p.Name, err)
+		}
+		if seen[rule.ID] {
+			return fmt.Errorf("rule pack %s: duplicate rule id %q", p.Name, rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+	return nil
+}
+
+// validate checks a rule, applying defaults
+func (r *FallacyRule) validate() error {
+	if !ruleIDPattern.MatchString(r.ID) {
+		return fmt.Errorf("rule id %q must be lower-case letters, digits, '-' and '_'", r.ID)
+	}
+	r.Category = FallacyType(strings.ToLower(strings.TrimSpace(string(r.Category))))
+	if r.Category == "" {
+		return fmt.Errorf("rule %s: category is required", r.ID)
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = SeverityMedium
+	case SeverityLow, SeverityMedium, SeverityHigh:
+	default:
+		return fmt.Errorf("rule %s: severity must be low, medium or high", r.ID)
+	}
+	if r.Confidence <= 0 || r.Confidence > 1 {
+		return fmt.Errorf("rule %s: confidence must be in (0, 1]", r.ID)
+	}
+	if r.Explanation == "" {
+		return fmt.Errorf("rule %s: explanation is required", r.ID)
+	}
+	if r.Context != nil && (r.Context.Sentences < 0 || r.Context.Before < 0 || r.Context.After < 0) {
+		return fmt.Errorf("rule %s: context values must not be negative", r.ID)
+	}
+	for i, phrase := range r.Highlight {
+		r.Highlight[i] = strings.ToLower(phrase)
+	}
+	if r.Match == nil {
+		return fmt.Errorf("rule %s: match is required", r.ID)
+	}
+	if err := r.Match.compile("match"); err != nil {
+		return fmt.Errorf("rule %s: %w", r.ID, err)
+	}
+	return nil
+}
+
+// compile checks a condition, lower-casing its terms and compiling its
+// patterns
+func (m *FallacyMatch) compile(path string) error {
+	phrases := len(m.Terms) + len(m.Patterns)
+	if phrases == 0 && m.Check == "" && len(m.All)+len(m.Any)+len(m.None) == 0 {
+		return fmt.Errorf("%s: condition is empty", path)
+	}
+	for i, term := range m.Terms {
+		if strings.TrimSpace(term) == "" {
+			return fmt.Errorf("%s: empty term", path)
+		}
+		m.Terms[i] = strings.ToLower(term)
+	}
+	m.compiled = make([]*regexp.Regexp, 0, len(m.Patterns))
+	for _, pattern := range m.Patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern %q: %w", path, pattern, err)
+		}
+		m.compiled = append(m.compiled, re)
+	}
+
+	switch {
+	case m.Min < 0 || m.Occurrences < 0:
+		return fmt.Errorf("%s: min and occurrences must not be negative", path)
+	case m.Min > 0 && m.Occurrences > 0:
+		return fmt.Errorf("%s: set min or occurrences, not both", path)
+	case m.Min > phrases:
+		return fmt.Errorf("%s: min %d exceeds the %d terms and patterns", path, m.Min, phrases)
+	case m.Occurrences > 0 && phrases == 0:
+		return fmt.Errorf("%s: occurrences requires terms or patterns", path)
+	}
+
+	if m.Check != "" && fallacyChecks[m.Check] == nil {
+		checks := make([]string, 0, len(fallacyChecks))
+		for name := range fallacyChecks {
+			checks = append(checks, name)
+		}
+		sort.Strings(checks)
+		return fmt.Errorf("%s: unknown check %q (available: %s)", path, m.Check, strings.Join(checks, ", "))
+	}
+
+	for _, group := range []struct {
+		name  string
+		conds []*FallacyMatch
+	}{{"all", m.All}, {"any", m.Any}, {"none", m.None}} {
+		for i, sub := range group.conds {
+			subPath := fmt.Sprintf("%s.%s[%d]", path, group.name, i)
+			if sub == nil {
+				return fmt.Errorf("%s: condition is empty", subPath)
+			}
+			if err := sub.compile(subPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const performanceReviewPack = `
+name: performance-reviews
+description: Reasoning errors in performance reviews and benchmark reports
+workspaces: [perf-reviews]
+rules:
+  - id: benchmark_cherry_picking
+    name: Benchmark cherry-picking
+    category: performance
+    severity: high
+    confidence: 0.7
+    location: benchmark evidence
+    explanation: Reporting the best run or a favourable subset of benchmarks as representative
+    correction: Report all runs and benchmarks with their variance, or explain why some were excluded
+    match:
+      all:
+        - patterns: ['\b(best|fastest|peak|top)\s+(run|result|score)s?\b', '\b(only|just)\s+(ran|reported|measured|kept)\b']
+        - patterns: ['\b(faster|slower|speedup|throughput|latency)\b', '\b[0-9]+(\.[0-9]+)?x\b']
+      none:
+        - terms: [median, all runs, confidence interval, variance, standard deviation]
+    context:
+      sentences: 2
+    examples:
+      positive:
+        - Our best run finished in 1.2s, so the new allocator is 3x faster.
+        - We only reported the two benchmarks where the cache helps. Throughput improved 40%.
+      negative:
+        - Across all runs the median latency dropped from 14ms to 11ms.
+        - The best run was 1.2s. The median over 30 runs was 1.9s, so the speedup is modest.
+`
+
+func TestCoreRulePack_Examples(t *testing.T) {
+	fd := NewFallacyDetector()
+
+	rules := fd.ListRules("")
+	if len(rules) != 21 {
+		t.Fatalf("core pack has %d rules, want 21", len(rules))
+	}
+	for _, rule := range rules {
+		stats := rule.Stats
+		if rule.Pack != "core" || !rule.Enabled {
+			t.Errorf("%s: pack %q, enabled %v", rule.ID, rule.Pack, rule.Enabled)
+		}
+		if stats.Positives == 0 || stats.Negatives == 0 {
+			t.Errorf("%s: needs positive and negative examples", rule.ID)
+		}
+		if len(stats.Missed) > 0 || len(stats.FalseAlarms) > 0 {
+			t.Errorf("%s: missed %q, false alarms %q", rule.ID, stats.Missed, stats.FalseAlarms)
+		}
+		if stats.Precision == nil || *stats.Precision != 1 || stats.Recall == nil || *stats.Recall != 1 {
+			t.Errorf("%s: precision %v, recall %v", rule.ID, stats.Precision, stats.Recall)
+		}
+	}
+}
+
+func TestFallacyDetector_CustomRulePack(t *testing.T) {
+	fd := NewFallacyDetector()
+	pack, err := ParseFallacyRulePack([]byte(performanceReviewPack), ".yaml")
+	if err != nil {
+		t.Fatalf("ParseFallacyRulePack: %v", err)
+	}
+	if err := fd.AddRulePack(pack); err != nil {
+		t.Fatalf("AddRulePack: %v", err)
+	}
+
+	content := "Our best run of the new build finished in 1.2s. That makes it 3x faster than the old one."
+	if found := ruleIDs(fd.DetectFallaciesIn("", content, true, true)); found["benchmark_cherry_picking"] {
+		t.Error("pack restricted to perf-reviews should not run in the default workspace")
+	}
+	detected := fd.DetectFallaciesIn("Perf-Reviews", content, false, true)
+	var fallacy *DetectedFallacy
+	for _, f := range detected {
+		if f.Type == "benchmark_cherry_picking" {
+			fallacy = f
+		}
+	}
+	if fallacy == nil {
+		t.Fatalf("benchmark_cherry_picking not detected in perf-reviews: %+v", detected)
+	}
+	if fallacy.Category != "performance" || fallacy.Severity != SeverityHigh || !strings.Contains(fallacy.Example, "best run") {
+		t.Errorf("unexpected detection: %+v", fallacy)
+	}
+	if found := ruleIDs(fd.DetectFallaciesIn("perf-reviews", content, true, false)); found["benchmark_cherry_picking"] {
+		t.Error("custom categories should be skipped when only formal fallacies are checked")
+	}
+
+	// The claim and the evidence are more than two sentences apart
+	distant := "Our best run was on Tuesday. The team reviewed the design. Then we shipped it. It is 3x faster."
+	if found := ruleIDs(fd.DetectFallaciesIn("perf-reviews", distant, false, true)); found["benchmark_cherry_picking"] {
+		t.Error("matches outside the context window should not combine")
+	}
+
+	var info *FallacyRuleInfo
+	for _, rule := range fd.ListRules("perf-reviews") {
+		if rule.ID == "benchmark_cherry_picking" {
+			info = rule
+		}
+	}
+	if info == nil || !info.Enabled || info.Pack != "performance-reviews" {
+		t.Fatalf("unexpected rule info: %+v", info)
+	}
+	if info.Stats.TruePositives != 2 || info.Stats.FalsePositives != 0 || info.Stats.Detections != 1 {
+		t.Errorf("unexpected stats: %+v", info.Stats)
+	}
+	if packs := fd.RulePacks(); len(packs) != 2 || packs[1].Name != "performance-reviews" || packs[1].Rules != 1 {
+		t.Errorf("unexpected packs: %+v", packs)
+	}
+}
+
+func TestFallacyDetector_ConfigureRules(t *testing.T) {
+	fd := NewFallacyDetector()
+	content := "You can't trust his argument because he's an idiot."
+
+	if err := fd.ConfigureRules("team", nil, []string{"ad_hominem"}, false); err != nil {
+		t.Fatalf("ConfigureRules: %v", err)
+	}
+	if ruleIDs(fd.DetectFallaciesIn("team", content, true, true))["ad_hominem"] {
+		t.Error("ad_hominem should be disabled in team")
+	}
+	if !ruleIDs(fd.DetectFallacies(content, true, true))["ad_hominem"] {
+		t.Error("ad_hominem should stay enabled in the default workspace")
+	}
+
+	if err := fd.ConfigureRules("team", []string{"no_such_rule"}, nil, true); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+	if err := fd.ConfigureRules("team", []string{"ad_hominem"}, []string{"ad_hominem"}, false); err == nil {
+		t.Error("expected error for a rule both enabled and disabled")
+	}
+	if ruleIDs(fd.DetectFallaciesIn("team", content, true, true))["ad_hominem"] {
+		t.Error("rejected configuration should not change settings")
+	}
+
+	if err := fd.ConfigureRules("team", nil, nil, true); err != nil {
+		t.Fatalf("ConfigureRules reset: %v", err)
+	}
+	if !ruleIDs(fd.DetectFallaciesIn("team", content, true, true))["ad_hominem"] {
+		t.Error("reset should restore the pack default")
+	}
+}
+
+func TestFallacyDetector_AddRulePackReplaces(t *testing.T) {
+	fd := NewFallacyDetector()
+	override := `{"name": "house", "rules": [
+		{"id": "ad_hominem", "category": "informal", "confidence": 0.9, "explanation": "Personal attack", "match": {"patterns": ["\\bclown\\b"]}},
+		{"id": "hedging", "category": "style", "confidence": 0.3, "explanation": "Hedged claim", "enabled": false, "match": {"terms": ["arguably"]}}
+	]}`
+	pack, err := ParseFallacyRulePack([]byte(override), ".json")
+	if err != nil {
+		t.Fatalf("ParseFallacyRulePack: %v", err)
+	}
+	if err := fd.AddRulePack(pack); err != nil {
+		t.Fatalf("AddRulePack: %v", err)
+	}
+
+	rules := fd.ListRules("")
+	if len(rules) != 22 || rules[4].ID != "ad_hominem" || rules[4].Pack != "house" || rules[4].Severity != SeverityMedium {
+		t.Fatalf("override should replace the core rule in place: %+v", rules[4])
+	}
+	if rules[21].ID != "hedging" || rules[21].Enabled {
+		t.Errorf("hedging should be appended and disabled by default: %+v", rules[21])
+	}
+	detected := ruleIDs(fd.DetectFallacies("He is an idiot and a clown, arguably.", false, true))
+	if !detected["ad_hominem"] || detected["hedging"] {
+		t.Errorf("unexpected detections: %v", detected)
+	}
+	if ruleIDs(fd.DetectFallacies("He is an idiot.", false, true))["ad_hominem"] {
+		t.Error("core ad_hominem terms should no longer apply")
+	}
+
+	// Reloading a pack drops the rules it no longer contains
+	pack, err = ParseFallacyRulePack([]byte(`{"name": "house", "rules": [{"id": "ad_hominem", "category": "informal", "confidence": 0.9, "explanation": "Personal attack", "match": {"terms": ["clown"]}}]}`), ".json")
+	if err != nil {
+		t.Fatalf("ParseFallacyRulePack: %v", err)
+	}
+	if err := fd.AddRulePack(pack); err != nil {
+		t.Fatalf("AddRulePack: %v", err)
+	}
+	if rules := fd.ListRules(""); len(rules) != 21 {
+		t.Errorf("got %d rules after reload, want 21", len(rules))
+	}
+}
+
+func TestParseFallacyRulePack_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":   `{"name": "p", "rules": [{"id": "r", "category": "c", "confidence": 0.5, "explanation": "e", "match": {"terms": ["x"]}, "severty": "high"}]}`,
+		"no rules":        `{"name": "p", "rules": []}`,
+		"bad name":        `{"name": "My Pack", "rules": [{"id": "r", "category": "c", "confidence": 0.5, "explanation": "e", "match": {"terms": ["x"]}}]}`,
+		"duplicate id":    `{"name": "p", "rules": [{"id": "r", "category": "c", "confidence": 0.5, "explanation": "e", "match": {"terms": ["x"]}}, {"id": "r", "category": "c", "confidence": 0.5, "explanation": "e", "match": {"terms": ["y"]}}]}`,
+		"no match":        `{"name": "p", "rules": [{"id": "r", "category": "c", "confidence": 0.5, "explanation": "e"}]}`,
+		"empty condition": `{"name": "p", "rules": [{"id": "r", "category": "c", "confidence": 0.5, "explanation": "e", "match": {"all": [{}]}}]}`,
+		"unknown check":   `{"name": "p", "rules": [{"id": "r", "category": "c", "confidence": 0.5, "explanation": "e", "match": {"check": "vibes"}}]}`,
+		"bad pattern":     `{"name": "p", "rules": [{"id": "r", "category": "c", "confidence": 0.5, "explanation": "e", "match": {"patterns": ["(unclosed"]}}]}`,
+		"min too large":   `{"name": "p", "rules": [{"id": "r", "category": "c", "confidence": 0.5, "explanation": "e", "match": {"terms": ["x"], "min": 2}}]}`,
+		"bad severity":    `{"name": "p", "rules": [{"id": "r", "category": "c", "severity": "critical", "confidence": 0.5, "explanation": "e", "match": {"terms": ["x"]}}]}`,
+		"bad confidence":  `{"name": "p", "rules": [{"id": "r", "category": "c", "confidence": 1.5, "explanation": "e", "match": {"terms": ["x"]}}]}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			pack, err := ParseFallacyRulePack([]byte(data), ".json")
+			if err == nil {
+				err = NewFallacyDetector().AddRulePack(pack)
+			}
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestFallacyDetector_LoadRulePackDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "perf.yaml"), []byte(strings.Replace(performanceReviewPack, "name: performance-reviews\n", "", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a pack"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fd := NewFallacyDetector()
+	loaded, err := fd.LoadRulePackDir(dir)
+	if err != nil {
+		t.Fatalf("LoadRulePackDir: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Name != "perf" {
+		t.Fatalf("unexpected packs: %+v", loaded)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"name": "broken", "rules": [{"id": "r"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.LoadRulePackDir(dir); err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("expected error naming broken.json, got %v", err)
+	}
+}
+
+func TestSentenceSpans(t *testing.T) {
+	content := "The build is 1.5x faster. Is it stable?  Yes!Trailing text"
+	var sentences []string
+	for _, span := range sentenceSpans(content) {
+		sentences = append(sentences, content[span[0]:span[1]])
+	}
+	want := []string{"The build is 1.5x faster.", "Is it stable?", "Yes!Trailing text"}
+	if strings.Join(sentences, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", sentences, want)
+	}
+}
+
+func ruleIDs(fallacies []*DetectedFallacy) map[string]bool {
+	ids := make(map[string]bool, len(fallacies))
+	for _, f := range fallacies {
+		ids[f.Type] = true
+	}
+	return ids
+}
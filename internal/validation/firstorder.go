@@ -0,0 +1,1170 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProverLimits bound the first-order resolution search, which need not
+// terminate when the conclusion does not follow. Zero fields take the
+// defaults.
+type ProverLimits struct {
+	MaxDepth   int           // Longest chain of inferences from an input clause
+	MaxClauses int           // Clauses kept before the search gives up
+	Timeout    time.Duration // Time budget of one search
+}
+
+// DefaultProverLimits are the bounds used unless a caller sets others
+var DefaultProverLimits = ProverLimits{MaxDepth: 12, MaxClauses: 5000, Timeout: 2 * time.Second}
+
+func (l ProverLimits) withDefaults() ProverLimits {
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = DefaultProverLimits.MaxDepth
+	}
+	if l.MaxClauses <= 0 {
+		l.MaxClauses = DefaultProverLimits.MaxClauses
+	}
+	if l.Timeout <= 0 {
+		l.Timeout = DefaultProverLimits.Timeout
+	}
+	return l
+}
+
+func (l ProverLimits) String() string {
+	return fmt.Sprintf("depth %d, %d clauses, %s", l.MaxDepth, l.MaxClauses, l.Timeout)
+}
+
+// firstOrderProof is the outcome of a resolution search
+type firstOrderProof struct {
+	formulas  []*Formula // Formalized premises, then the conclusion
+	proved    bool
+	saturated bool // No refutation exists, so the conclusion does not follow
+	steps     []resolutionStep
+	limits    ProverLimits
+}
+
+// resolutionStep is one clause of a refutation
+type resolutionStep struct {
+	clause   foClause
+	source   int   // Statement an input clause comes from (the conclusion is negated); -1 when derived
+	parents  []int // Positions of the parent steps
+	rule     string
+	bindings []string // Per parent, the bindings of the most general unifier, as "x1 = socrates"
+}
+
+// justification describes how a step of a trace was obtained, with number
+// giving the displayed number of a step
+func (s resolutionStep) justification(premises int, number func(int) int) string {
+	switch s.rule {
+	case "clausification":
+		if s.source == premises {
+			return "negated conclusion"
+		}
+		return fmt.Sprintf("premise %d", s.source+1)
+	case "factoring":
+		return fmt.Sprintf("factor %d", number(s.parents[0])) + s.unifier(number)
+	}
+	return fmt.Sprintf("resolve %d and %d", number(s.parents[0]), number(s.parents[1])) + s.unifier(number)
+}
+
+// unifier describes the bindings, naming the parent step they apply to when
+// both parents have some
+func (s resolutionStep) unifier(number func(int) int) string {
+	both := len(s.bindings) == 2 && s.bindings[0] != "" && s.bindings[1] != ""
+	var parts []string
+	for i, bindings := range s.bindings {
+		switch {
+		case both:
+			parts = append(parts, fmt.Sprintf("%s in %d", bindings, number(s.parents[i])))
+		case bindings != "":
+			parts = append(parts, bindings)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " with " + strings.Join(parts, "; ")
+}
+
+// proveFirstOrder decides whether conclusion follows from premises by
+// refuting the premises together with the negated conclusion. It returns nil
+// when a statement cannot be formalized.
+func proveFirstOrder(premises []string, conclusion string, limits ProverLimits) *firstOrderProof {
+	if strings.TrimSpace(conclusion) == "" {
+		return nil
+	}
+	statements := append(append([]string(nil), premises...), conclusion)
+	parsed := make([]*Formula, len(statements))
+	for i, statement := range statements {
+		formula, err := ParseFormula(statement)
+		if err != nil {
+			return nil
+		}
+		parsed[i] = formula
+	}
+	formulas, err := formalize(parsed)
+	if err != nil {
+		return nil
+	}
+
+	limits = limits.withDefaults()
+	search := &resolutionSearch{limits: limits, seen: map[string]bool{}, deadline: time.Now().Add(limits.Timeout)}
+	skolem := &skolemizer{}
+	var usable, support []int
+	for i, formula := range formulas {
+		if i == len(premises) {
+			formula = &Formula{Op: FormulaNot, Left: formula}
+		}
+		normal, err := negationNormalForm(formula, false)
+		if err != nil {
+			return nil
+		}
+		clauses, err := foClauses(skolem.skolemize(normal, nil, nil))
+		if err != nil {
+			return nil
+		}
+		for _, c := range clauses {
+			index := search.add(foNode{clause: c, source: i, rule: "clausification"})
+			if index < 0 {
+				continue
+			}
+			// The set of support: only clauses descending from the negated
+			// conclusion are resolved with each other
+			if i == len(premises) {
+				support = append(support, index)
+			} else {
+				usable = append(usable, index)
+			}
+		}
+	}
+
+	result := &firstOrderProof{formulas: formulas, limits: limits}
+	if empty := search.run(usable, support); empty >= 0 {
+		result.proved = true
+		result.steps = search.trace(empty)
+	} else {
+		result.saturated = !search.bounded
+	}
+	return result
+}
+
+// proveQuantified decides arguments with quantified statements by
+// first-order resolution. It returns nil when a statement cannot be
+// formalized or the search stops at a limit, leaving the argument to the
+// inference patterns.
+func (v *LogicValidator) proveQuantified(premises []string, conclusion string) *ProofResult {
+	search := proveFirstOrder(premises, conclusion, DefaultProverLimits)
+	if search == nil || (!search.proved && !search.saturated) {
+		return nil
+	}
+
+	steps := make([]string, 0, 2*len(premises)+len(search.steps)+4)
+	for i, p := range premises {
+		steps = append(steps, fmt.Sprintf("Premise %d: %s", i+1, p))
+	}
+	for i, formula := range search.formulas[:len(premises)] {
+		steps = append(steps, fmt.Sprintf("Formalized premise %d: %s", i+1, formula))
+	}
+	steps = append(steps, fmt.Sprintf("Formalized conclusion: %s", search.formulas[len(premises)]))
+
+	result := &ProofResult{
+		Premises:   premises,
+		Conclusion: conclusion,
+		Method:     ProofMethodFirstOrder,
+	}
+	if !search.proved {
+		result.Steps = append(steps,
+			"Resolution on the premises and the negated conclusion saturates without a contradiction",
+			"Cannot prove conclusion from given premises")
+		return result
+	}
+
+	result.IsProvable = true
+	steps = append(steps, "Refutation by first-order resolution on the premises and the negated conclusion:")
+	number := func(i int) int { return i + 1 }
+	for i, step := range search.steps {
+		steps = append(steps, fmt.Sprintf("  %d. %s  [%s]", i+1, step.clause, step.justification(len(premises), number)))
+	}
+	result.Steps = append(steps, fmt.Sprintf("Therefore: %s", conclusion))
+	return result
+}
+
+// Translation of English statements
+
+// englishTranslator rewrites English propositions as first-order atoms:
+// "All men are mortal" is ∀x (man(x) → mortal(x)), "Socrates is a man" is
+// man(socrates) and "Every service that calls auth must use TLS" is
+// ∀x (service(x) ∧ call_auth(x) → use_tls(x))
+type englishTranslator struct {
+	verbPhrases [][]string // Stemmed verb phrases of the quantified statements, longest first
+	variables   int
+}
+
+// formalize translates parsed statements to first-order formulas
+func formalize(parsed []*Formula) ([]*Formula, error) {
+	t := &englishTranslator{}
+	for _, formula := range parsed {
+		t.collectVerbPhrases(formula)
+	}
+	sort.SliceStable(t.verbPhrases, func(i, j int) bool { return len(t.verbPhrases[i]) > len(t.verbPhrases[j]) })
+
+	formulas := make([]*Formula, len(parsed))
+	for i, formula := range parsed {
+		translated, err := t.translate(formula, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		formulas[i] = translated
+	}
+	return formulas, nil
+}
+
+var (
+	copulas          = map[string]bool{"is": true, "are": true, "was": true, "were": true, "am": true}
+	modals           = map[string]bool{"must": true, "should": true, "shall": true, "will": true, "can": true, "may": true, "could": true, "would": true, "might": true}
+	relativePronouns = map[string]bool{"that": true, "who": true, "which": true, "whom": true}
+
+	// Nouns that add nothing to a subject ("all rich people" are the rich)
+	genericNouns = map[string]bool{
+		"thing": true, "things": true, "people": true, "person": true, "persons": true, "one": true, "ones": true,
+		"individual": true, "individuals": true, "entity": true, "entities": true,
+	}
+	irregularPlurals = map[string]string{
+		"men": "man", "women": "woman", "children": "child", "mice": "mouse", "geese": "goose", "feet": "foot", "teeth": "tooth",
+	}
+)
+
+type phraseKind int
+
+const (
+	nounPhrase phraseKind = iota // After a copula: "is a man", "are cold-blooded"
+	verbPhrase                   // "writes code", "must use TLS"
+)
+
+// predicateWord normalizes a word of a predicate name
+func predicateWord(word string) string {
+	if singular, ok := irregularPlurals[word]; ok {
+		return singular
+	}
+	return stemWord(word)
+}
+
+func (t *englishTranslator) translate(f *Formula, bound map[string]bool) (*Formula, error) {
+	switch f.Op {
+	case FormulaAtom:
+		switch {
+		case f.words == nil:
+			return &Formula{Op: FormulaAtom, Name: f.Name, Args: bindTerms(f.Args, bound)}, nil
+		case f.Quantified:
+			return t.quantified(f.words, bound)
+		}
+		return t.sentence(f.words, bound)
+	case FormulaTrue, FormulaFalse:
+		return f, nil
+	case FormulaNot:
+		operand, err := t.translate(f.Left, bound)
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaNot, Left: operand}, nil
+	case FormulaForAll, FormulaExists:
+		inner := make(map[string]bool, len(bound)+1)
+		for name := range bound {
+			inner[name] = true
+		}
+		inner[f.Var] = true
+		body, err := t.translate(f.Left, inner)
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: f.Op, Var: f.Var, Left: body}, nil
+	}
+	left, err := t.translate(f.Left, bound)
+	if err != nil {
+		return nil, err
+	}
+	right, err := t.translate(f.Right, bound)
+	if err != nil {
+		return nil, err
+	}
+	return &Formula{Op: f.Op, Left: left, Right: right}, nil
+}
+
+// bindTerms marks the names bound by quantifiers as variables
+func bindTerms(terms []*Term, bound map[string]bool) []*Term {
+	if terms == nil {
+		return nil
+	}
+	result := make([]*Term, len(terms))
+	for i, term := range terms {
+		result[i] = &Term{Name: term.Name, Args: bindTerms(term.Args, bound), Variable: len(term.Args) == 0 && bound[term.Name]}
+	}
+	return result
+}
+
+// collectVerbPhrases records the verb phrases of quantified statements, so
+// that "the payments service calls auth" splits before "calls auth" when a
+// policy mentions services that call auth
+func (t *englishTranslator) collectVerbPhrases(f *Formula) {
+	switch f.Op {
+	case FormulaAtom:
+		if !f.Quantified || len(f.words) < 2 {
+			return
+		}
+		for _, part := range t.splitQuantified(f.words[1:]) {
+			if part.kind == verbPhrase && len(part.words) > 0 {
+				t.verbPhrases = append(t.verbPhrases, stemAll(withoutNegation(part.words)))
+			}
+		}
+	case FormulaTrue, FormulaFalse:
+	case FormulaNot, FormulaForAll, FormulaExists:
+		t.collectVerbPhrases(f.Left)
+	default:
+		t.collectVerbPhrases(f.Left)
+		t.collectVerbPhrases(f.Right)
+	}
+}
+
+// quantifiedPart is the subject, relative clause or predicate of a quantified statement
+type quantifiedPart struct {
+	words []string
+	kind  phraseKind
+}
+
+// splitQuantified splits the words after a quantifier into the subject noun
+// phrase, an optional relative clause ("that call auth") and the predicate.
+// It returns nil when the words have no recognizable predicate.
+func (t *englishTranslator) splitQuantified(words []string) []quantifiedPart {
+	relative := -1
+	for i, word := range words {
+		if copulas[word] || modals[word] {
+			break
+		}
+		if relativePronouns[word] {
+			relative = i
+			break
+		}
+	}
+	if relative < 0 {
+		if len(words) > 0 && (copulas[words[0]] || modals[words[0]]) {
+			// "everyone is mortal", "everything must be logged"
+			kind, predicate := predicateAfter(words, 0)
+			return []quantifiedPart{{kind: nounPhrase}, {words: predicate, kind: kind}}
+		}
+		subject, predicate, kind := t.splitSentence(words)
+		if !t.splitsAt(words, len(subject)) && !agreesInNumber(subject, predicate) {
+			// "no sunny weather" has no verb to split at
+			return nil
+		}
+		return []quantifiedPart{{words: subject, kind: nounPhrase}, {words: predicate, kind: kind}}
+	}
+
+	subject, clause := words[:relative], words[relative+1:]
+	if len(clause) == 0 {
+		return nil
+	}
+	relativeKind, start := verbPhrase, 0
+	if copulas[clause[0]] {
+		relativeKind, start = nounPhrase, 1
+	}
+	for i := start + 1; i < len(clause); i++ {
+		if copulas[clause[i]] || modals[clause[i]] {
+			kind, predicate := predicateAfter(clause, i)
+			return []quantifiedPart{{words: subject, kind: nounPhrase}, {words: clause[start:i], kind: relativeKind}, {words: predicate, kind: kind}}
+		}
+	}
+	if n := t.knownVerbPhrase(clause[start:]); n > 0 && n < len(clause)-start {
+		split := len(clause) - n
+		return []quantifiedPart{{words: subject, kind: nounPhrase}, {words: clause[start:split], kind: relativeKind}, {words: clause[split:], kind: verbPhrase}}
+	}
+	return nil
+}
+
+// predicateAfter returns the predicate following the copula or modal at i:
+// "is mortal" is a noun phrase, "must use TLS" a verb phrase and "must not
+// be public" a negated noun phrase
+func predicateAfter(words []string, i int) (phraseKind, []string) {
+	if copulas[words[i]] {
+		return nounPhrase, words[i+1:]
+	}
+	rest := words[i+1:]
+	for j, word := range rest {
+		if word == "be" {
+			return nounPhrase, append(append([]string(nil), rest[:j]...), rest[j+1:]...)
+		}
+		if word != "not" {
+			break
+		}
+	}
+	return verbPhrase, rest
+}
+
+// splitSentence splits words into a subject and a predicate at the first
+// copula or modal after the subject, otherwise before the longest known verb
+// phrase ending the words, otherwise after the first word
+func (t *englishTranslator) splitSentence(words []string) ([]string, []string, phraseKind) {
+	for i := 1; i < len(words); i++ {
+		if copulas[words[i]] || modals[words[i]] {
+			kind, predicate := predicateAfter(words, i)
+			return words[:i], predicate, kind
+		}
+	}
+	if n := t.knownVerbPhrase(words); n > 0 && n < len(words) {
+		return words[:len(words)-n], words[len(words)-n:], verbPhrase
+	}
+	return words[:1], words[1:], verbPhrase
+}
+
+// splitsAt reports whether words have a copula, modal or known verb phrase at i
+func (t *englishTranslator) splitsAt(words []string, i int) bool {
+	if i <= 0 || i >= len(words) {
+		return false
+	}
+	return copulas[words[i]] || modals[words[i]] || t.knownVerbPhrase(words[i:]) == len(words)-i
+}
+
+// agreesInNumber reports whether a one-word subject and the verb that
+// follows it agree, as in "programmers write" and "every programmer writes",
+// which marks the word after the subject as a verb
+func agreesInNumber(subject, predicate []string) bool {
+	if len(subject) != 1 || len(predicate) == 0 {
+		return false
+	}
+	plural := irregularPlurals[subject[0]] != "" || stemWord(subject[0]) != subject[0]
+	thirdPerson := stemWord(predicate[0]) != predicate[0]
+	return plural != thirdPerson
+}
+
+// knownVerbPhrase returns the length of the longest known verb phrase that
+// ends words
+func (t *englishTranslator) knownVerbPhrase(words []string) int {
+	stemmed := stemAll(withoutNegation(words))
+	for _, phrase := range t.verbPhrases {
+		if len(phrase) > len(stemmed) || len(phrase) == 0 {
+			continue
+		}
+		if strings.Join(stemmed[len(stemmed)-len(phrase):], " ") != strings.Join(phrase, " ") {
+			continue
+		}
+		// Count the original words, including any "not" among them
+		n, matched := 0, 0
+		for i := len(words) - 1; i >= 0 && matched < len(phrase); i-- {
+			n++
+			if words[i] != "not" {
+				matched++
+			}
+		}
+		return n
+	}
+	return 0
+}
+
+// quantified translates a statement that starts with a quantifier
+func (t *englishTranslator) quantified(words []string, bound map[string]bool) (*Formula, error) {
+	op, negative := FormulaForAll, false
+	switch words[0] {
+	case "all", "every", "each", "any", "everyone", "everybody", "everything", "anyone", "anybody", "anything":
+	case "no", "nobody", "nothing", "none":
+		negative = true
+	case "some", "someone", "somebody", "something":
+		op = FormulaExists
+	default:
+		return nil, fmt.Errorf("cannot formalize %q", strings.Join(words, " "))
+	}
+	parts := t.splitQuantified(words[1:])
+	if parts == nil || len(parts[len(parts)-1].words) == 0 {
+		return nil, fmt.Errorf("cannot formalize %q: no predicate", strings.Join(words, " "))
+	}
+	t.variables++
+	name := fmt.Sprintf("v%d", t.variables)
+	variable := &Term{Name: name, Variable: true}
+
+	var conditions []*Formula
+	for _, part := range parts[:len(parts)-1] {
+		if condition := phrase(part.words, part.kind, variable, bound); condition.Op != FormulaTrue {
+			conditions = append(conditions, condition)
+		}
+	}
+	last := parts[len(parts)-1]
+	predicate := phrase(last.words, last.kind, variable, bound)
+	if negative {
+		predicate = &Formula{Op: FormulaNot, Left: predicate}
+	}
+
+	body := predicate
+	if condition := conjunction(conditions); condition != nil {
+		if op == FormulaExists {
+			body = &Formula{Op: FormulaAnd, Left: condition, Right: predicate}
+		} else {
+			body = &Formula{Op: FormulaImplies, Left: condition, Right: predicate}
+		}
+	}
+	return &Formula{Op: op, Var: name, Left: body}, nil
+}
+
+// sentence translates a statement about an individual
+func (t *englishTranslator) sentence(words []string, bound map[string]bool) (*Formula, error) {
+	if len(words) == 1 {
+		if bound[words[0]] {
+			return nil, fmt.Errorf("cannot formalize the bare variable %q", words[0])
+		}
+		return &Formula{Op: FormulaAtom, Name: predicateWord(words[0])}, nil
+	}
+	subject, predicate, kind := t.splitSentence(words)
+	if len(predicate) == 0 {
+		return nil, fmt.Errorf("cannot formalize %q: no predicate", strings.Join(words, " "))
+	}
+	var term *Term
+	if len(subject) == 1 && bound[subject[0]] {
+		term = &Term{Name: subject[0], Variable: true}
+	} else {
+		term = &Term{Name: strings.Join(subject, "_")}
+	}
+	return phrase(predicate, kind, term, bound), nil
+}
+
+// phrase formalizes a predicate phrase about subject. A noun phrase is a
+// conjunction of one predicate per word ("cold-blooded animal"), a verb phrase
+// is one predicate ("write_code"), and bound variables in the phrase become
+// further arguments ("x loves y" is love(x, y)).
+func phrase(words []string, kind phraseKind, subject *Term, bound map[string]bool) *Formula {
+	negated := false
+	var names []string
+	args := []*Term{subject}
+	for _, word := range words {
+		switch {
+		case word == "not":
+			negated = !negated
+		case bound[word]:
+			args = append(args, &Term{Name: word, Variable: true})
+		case kind == nounPhrase && len(words) > 1 && genericNouns[word]:
+		default:
+			names = append(names, predicateWord(word))
+		}
+	}
+
+	var formula *Formula
+	switch {
+	case len(names) == 0:
+		formula = &Formula{Op: FormulaTrue}
+	case kind == nounPhrase && len(args) == 1:
+		atoms := make([]*Formula, len(names))
+		for i, name := range names {
+			atoms[i] = &Formula{Op: FormulaAtom, Name: name, Args: args}
+		}
+		formula = conjunction(atoms)
+	default:
+		formula = &Formula{Op: FormulaAtom, Name: strings.Join(names, "_"), Args: args}
+	}
+	if negated {
+		return &Formula{Op: FormulaNot, Left: formula}
+	}
+	return formula
+}
+
+func conjunction(formulas []*Formula) *Formula {
+	if len(formulas) == 0 {
+		return nil
+	}
+	result := formulas[0]
+	for _, f := range formulas[1:] {
+		result = &Formula{Op: FormulaAnd, Left: result, Right: f}
+	}
+	return result
+}
+
+func withoutNegation(words []string) []string {
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		if word != "not" {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+func stemAll(words []string) []string {
+	stemmed := make([]string, len(words))
+	for i, word := range words {
+		stemmed[i] = predicateWord(word)
+	}
+	return stemmed
+}
+
+// Clause form
+
+// foLiteral is a possibly negated first-order atom
+type foLiteral struct {
+	negated bool
+	pred    string
+	args    []*Term
+}
+
+func (l foLiteral) String() string {
+	atom := l.pred
+	if len(l.args) > 0 {
+		atom += termList(l.args)
+	}
+	if l.negated {
+		return "¬" + atom
+	}
+	return atom
+}
+
+// key identifies a literal, telling variables apart from constants
+func (l foLiteral) key() string {
+	var b strings.Builder
+	if l.negated {
+		b.WriteString("~")
+	}
+	b.WriteString(l.pred)
+	for _, arg := range l.args {
+		b.WriteString("|")
+		writeTermKey(&b, arg)
+	}
+	return b.String()
+}
+
+func writeTermKey(b *strings.Builder, t *Term) {
+	if t.Variable {
+		b.WriteString("?")
+	}
+	b.WriteString(t.Name)
+	if len(t.Args) > 0 {
+		b.WriteString("(")
+		for i, arg := range t.Args {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeTermKey(b, arg)
+		}
+		b.WriteString(")")
+	}
+}
+
+// foClause is a disjunction of first-order literals whose variables are
+// numbered x1, x2, ... in order of appearance
+type foClause []foLiteral
+
+func (c foClause) String() string {
+	if len(c) == 0 {
+		return "⊥"
+	}
+	parts := make([]string, len(c))
+	for i, l := range c {
+		parts[i] = l.String()
+	}
+	return strings.Join(parts, " ∨ ")
+}
+
+func (c foClause) key() string {
+	parts := make([]string, len(c))
+	for i, l := range c {
+		parts[i] = l.key()
+	}
+	return strings.Join(parts, " ")
+}
+
+// weight counts the symbols of a clause; light clauses are resolved first
+func (c foClause) weight() int {
+	var size func(*Term) int
+	size = func(t *Term) int {
+		n := 1
+		for _, arg := range t.Args {
+			n += size(arg)
+		}
+		return n
+	}
+	total := 0
+	for _, l := range c {
+		total++
+		for _, arg := range l.args {
+			total += size(arg)
+		}
+	}
+	return total
+}
+
+// newFOClause sorts and deduplicates literals and renames variables in order
+// of appearance. It reports false for a tautology.
+func newFOClause(literals []foLiteral) (foClause, bool) {
+	// Sort on the literals with anonymous variables so that renaming does
+	// not change the order
+	anonymous := func(l foLiteral) string {
+		return anonymousVariables(l.key())
+	}
+	sort.SliceStable(literals, func(i, j int) bool { return anonymous(literals[i]) < anonymous(literals[j]) })
+
+	names := map[string]*Term{}
+	var rename func(*Term) *Term
+	rename = func(t *Term) *Term {
+		if t.Variable {
+			if renamed, ok := names[t.Name]; ok {
+				return renamed
+			}
+			names[t.Name] = &Term{Name: fmt.Sprintf("x%d", len(names)+1), Variable: true}
+			return names[t.Name]
+		}
+		args := make([]*Term, len(t.Args))
+		for i, arg := range t.Args {
+			args[i] = rename(arg)
+		}
+		return &Term{Name: t.Name, Args: args}
+	}
+
+	seen := map[string]bool{}
+	result := make(foClause, 0, len(literals))
+	for _, l := range literals {
+		args := make([]*Term, len(l.args))
+		for i, arg := range l.args {
+			args[i] = rename(arg)
+		}
+		renamed := foLiteral{negated: l.negated, pred: l.pred, args: args}
+		key := renamed.key()
+		if seen[key] {
+			continue
+		}
+		complement := renamed
+		complement.negated = !complement.negated
+		if seen[complement.key()] {
+			return nil, false
+		}
+		seen[key] = true
+		result = append(result, renamed)
+	}
+	return result, true
+}
+
+// anonymousVariables replaces variable names in a key with "?"
+func anonymousVariables(key string) string {
+	var b strings.Builder
+	skipping := false
+	for _, r := range key {
+		switch {
+		case r == '?':
+			skipping = true
+			b.WriteRune(r)
+		case skipping && (r == '|' || r == ',' || r == ')'):
+			skipping = false
+			b.WriteRune(r)
+		case !skipping:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// skolemizer replaces existential variables with Skolem functions of the
+// universal variables in scope and gives every universal variable a fresh name
+type skolemizer struct {
+	functions int
+	variables int
+}
+
+// skolemize returns the quantifier-free matrix of a formula in negation
+// normal form; its variables are implicitly universal
+func (s *skolemizer) skolemize(f *Formula, universals []*Term, bindings map[string]*Term) *Formula {
+	switch f.Op {
+	case FormulaAtom:
+		return &Formula{Op: FormulaAtom, Name: f.Name, Args: substituteBindings(f.Args, bindings)}
+	case FormulaTrue, FormulaFalse:
+		return f
+	case FormulaNot:
+		return &Formula{Op: FormulaNot, Left: s.skolemize(f.Left, universals, bindings)}
+	case FormulaForAll, FormulaExists:
+		inner := make(map[string]*Term, len(bindings)+1)
+		for name, term := range bindings {
+			inner[name] = term
+		}
+		if f.Op == FormulaForAll {
+			s.variables++
+			variable := &Term{Name: fmt.Sprintf("u%d", s.variables), Variable: true}
+			inner[f.Var] = variable
+			universals = append(append([]*Term(nil), universals...), variable)
+		} else {
+			s.functions++
+			inner[f.Var] = &Term{Name: fmt.Sprintf("sk%d", s.functions), Args: universals}
+		}
+		return s.skolemize(f.Left, universals, inner)
+	}
+	return &Formula{Op: f.Op, Left: s.skolemize(f.Left, universals, bindings), Right: s.skolemize(f.Right, universals, bindings)}
+}
+
+func substituteBindings(terms []*Term, bindings map[string]*Term) []*Term {
+	result := make([]*Term, len(terms))
+	for i, term := range terms {
+		if bound, ok := bindings[term.Name]; ok && term.Variable {
+			result[i] = bound
+		} else {
+			result[i] = &Term{Name: term.Name, Args: substituteBindings(term.Args, bindings), Variable: term.Variable}
+		}
+	}
+	return result
+}
+
+// foClauses distributes disjunction over conjunction in a quantifier-free
+// formula in negation normal form
+func foClauses(f *Formula) ([]foClause, error) {
+	switch f.Op {
+	case FormulaAtom:
+		return []foClause{{{pred: f.Name, args: f.Args}}}, nil
+	case FormulaNot:
+		return []foClause{{{negated: true, pred: f.Left.Name, args: f.Left.Args}}}, nil
+	case FormulaTrue:
+		return nil, nil
+	case FormulaFalse:
+		return []foClause{{}}, nil
+	}
+	left, err := foClauses(f.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := foClauses(f.Right)
+	if err != nil {
+		return nil, err
+	}
+	if f.Op == FormulaAnd {
+		return append(left, right...), nil
+	}
+	if len(left)*len(right) > MaxCNFClauses {
+		return nil, fmt.Errorf("clause form exceeds %d clauses", MaxCNFClauses)
+	}
+	var clauses []foClause
+	for _, l := range left {
+		for _, r := range right {
+			clauses = append(clauses, append(append(foClause(nil), l...), r...))
+		}
+	}
+	return clauses, nil
+}
+
+// Unification
+
+type substitution map[string]*Term
+
+// walk follows variable bindings
+func (s substitution) walk(t *Term) *Term {
+	for t.Variable {
+		next, ok := s[t.Name]
+		if !ok {
+			break
+		}
+		t = next
+	}
+	return t
+}
+
+func (s substitution) apply(t *Term) *Term {
+	t = s.walk(t)
+	if len(t.Args) == 0 {
+		return t
+	}
+	args := make([]*Term, len(t.Args))
+	for i, arg := range t.Args {
+		args[i] = s.apply(arg)
+	}
+	return &Term{Name: t.Name, Args: args}
+}
+
+func (s substitution) occurs(name string, t *Term) bool {
+	t = s.walk(t)
+	if t.Variable {
+		return t.Name == name
+	}
+	for _, arg := range t.Args {
+		if s.occurs(name, arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// unify extends s to a most general unifier of a and b
+func (s substitution) unify(a, b *Term) bool {
+	a, b = s.walk(a), s.walk(b)
+	switch {
+	case a.Variable && b.Variable && a.Name == b.Name:
+		return true
+	case a.Variable:
+		if s.occurs(a.Name, b) {
+			return false
+		}
+		s[a.Name] = b
+		return true
+	case b.Variable:
+		return s.unify(b, a)
+	case a.Name != b.Name || len(a.Args) != len(b.Args):
+		return false
+	}
+	for i := range a.Args {
+		if !s.unify(a.Args[i], b.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s substitution) unifyLiterals(a, b foLiteral) bool {
+	if a.pred != b.pred || len(a.args) != len(b.args) {
+		return false
+	}
+	for i := range a.args {
+		if !s.unify(a.args[i], b.args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s substitution) applyLiteral(l foLiteral) foLiteral {
+	args := make([]*Term, len(l.args))
+	for i, arg := range l.args {
+		args[i] = s.apply(arg)
+	}
+	return foLiteral{negated: l.negated, pred: l.pred, args: args}
+}
+
+// describe lists the bindings of the variables of clause c to non-variable
+// terms, naming variables as the clause was numbered before renameApart
+func (s substitution) describe(c foClause) string {
+	names := map[string]bool{}
+	var collect func(*Term)
+	collect = func(t *Term) {
+		if t.Variable {
+			names[t.Name] = true
+		}
+		for _, arg := range t.Args {
+			collect(arg)
+		}
+	}
+	for _, l := range c {
+		for _, arg := range l.args {
+			collect(arg)
+		}
+	}
+	var bindings []string
+	for name := range names {
+		if value := s.apply(&Term{Name: name, Variable: true}); !value.Variable {
+			bindings = append(bindings, fmt.Sprintf("x%s = %s", name[1:], value))
+		}
+	}
+	sort.Strings(bindings)
+	return strings.Join(bindings, ", ")
+}
+
+// renameApart gives the variables of c names that cannot clash with the
+// x1, x2, ... of another clause
+func renameApart(c foClause) foClause {
+	var rename func(*Term) *Term
+	rename = func(t *Term) *Term {
+		if t.Variable {
+			return &Term{Name: "y" + strings.TrimPrefix(t.Name, "x"), Variable: true}
+		}
+		args := make([]*Term, len(t.Args))
+		for i, arg := range t.Args {
+			args[i] = rename(arg)
+		}
+		return &Term{Name: t.Name, Args: args}
+	}
+	renamed := make(foClause, len(c))
+	for i, l := range c {
+		args := make([]*Term, len(l.args))
+		for j, arg := range l.args {
+			args[j] = rename(arg)
+		}
+		renamed[i] = foLiteral{negated: l.negated, pred: l.pred, args: args}
+	}
+	return renamed
+}
+
+// Resolution
+
+// foNode is a clause of the search with its derivation
+type foNode struct {
+	clause   foClause
+	source   int
+	parents  []int
+	rule     string
+	bindings []string // Per parent
+	depth    int
+}
+
+type resolutionSearch struct {
+	limits   ProverLimits
+	nodes    []foNode
+	seen     map[string]bool
+	deadline time.Time
+	bounded  bool // A clause was dropped or the search stopped at a limit
+}
+
+// add keeps a clause that is not a variant of a kept one and returns its
+// index, or -1
+func (s *resolutionSearch) add(node foNode) int {
+	if node.source >= 0 {
+		normalized, ok := newFOClause(node.clause)
+		if !ok {
+			return -1
+		}
+		node.clause = normalized
+	}
+	key := node.clause.key()
+	if s.seen[key] {
+		return -1
+	}
+	s.seen[key] = true
+	s.nodes = append(s.nodes, node)
+	return len(s.nodes) - 1
+}
+
+// run saturates the set of support with resolution and factoring, lightest
+// clause first, and returns the index of the empty clause or -1
+func (s *resolutionSearch) run(usable, support []int) int {
+	for _, index := range append(append([]int(nil), usable...), support...) {
+		if len(s.nodes[index].clause) == 0 {
+			return index
+		}
+	}
+	for _, index := range usable {
+		for _, factor := range s.factors(index) {
+			if added := s.add(factor); added >= 0 {
+				usable = append(usable, added)
+			}
+		}
+	}
+
+	for len(support) > 0 {
+		if time.Now().After(s.deadline) || len(s.nodes) >= s.limits.MaxClauses {
+			s.bounded = true
+			return -1
+		}
+		lightest := 0
+		for i, index := range support {
+			if s.nodes[index].clause.weight() < s.nodes[support[lightest]].clause.weight() {
+				lightest = i
+			}
+		}
+		given := support[lightest]
+		support = append(support[:lightest], support[lightest+1:]...)
+		usable = append(usable, given)
+
+		candidates := s.factors(given)
+		for _, other := range usable {
+			candidates = append(candidates, s.resolvents(given, other)...)
+		}
+		for _, candidate := range candidates {
+			if candidate.depth > s.limits.MaxDepth {
+				s.bounded = true
+				continue
+			}
+			index := s.add(candidate)
+			if index < 0 {
+				continue
+			}
+			if len(candidate.clause) == 0 {
+				return index
+			}
+			support = append(support, index)
+		}
+	}
+	return -1
+}
+
+// resolvents resolves clause i with clause j on every complementary pair of
+// unifiable literals
+func (s *resolutionSearch) resolvents(i, j int) []foNode {
+	a, b := s.nodes[i].clause, renameApart(s.nodes[j].clause)
+	depth := max(s.nodes[i].depth, s.nodes[j].depth) + 1
+	var nodes []foNode
+	for x, la := range a {
+		for y, lb := range b {
+			if la.negated == lb.negated || la.pred != lb.pred {
+				continue
+			}
+			sub := substitution{}
+			if !sub.unifyLiterals(la, lb) {
+				continue
+			}
+			var literals []foLiteral
+			for k, l := range a {
+				if k != x {
+					literals = append(literals, sub.applyLiteral(l))
+				}
+			}
+			for k, l := range b {
+				if k != y {
+					literals = append(literals, sub.applyLiteral(l))
+				}
+			}
+			if merged, ok := newFOClause(literals); ok {
+				bindings := []string{sub.describe(b), sub.describe(a)}
+				nodes = append(nodes, foNode{clause: merged, source: -1, parents: []int{j, i}, rule: "resolution", bindings: bindings, depth: depth})
+			}
+		}
+	}
+	return nodes
+}
+
+// factors unifies pairs of literals of the same sign within clause i
+func (s *resolutionSearch) factors(i int) []foNode {
+	c := s.nodes[i].clause
+	var nodes []foNode
+	for x := 0; x < len(c); x++ {
+		for y := x + 1; y < len(c); y++ {
+			if c[x].negated != c[y].negated {
+				continue
+			}
+			sub := substitution{}
+			if !sub.unifyLiterals(c[x], c[y]) {
+				continue
+			}
+			literals := make([]foLiteral, len(c))
+			for k, l := range c {
+				literals[k] = sub.applyLiteral(l)
+			}
+			if factor, ok := newFOClause(literals); ok {
+				nodes = append(nodes, foNode{clause: factor, source: -1, parents: []int{i}, rule: "factoring", bindings: []string{sub.describe(c)}, depth: s.nodes[i].depth + 1})
+			}
+		}
+	}
+	return nodes
+}
+
+// trace numbers the clauses the empty clause was derived from
+func (s *resolutionSearch) trace(empty int) []resolutionStep {
+	used := map[int]bool{}
+	var visit func(int)
+	visit = func(i int) {
+		if used[i] {
+			return
+		}
+		used[i] = true
+		for _, parent := range s.nodes[i].parents {
+			visit(parent)
+		}
+	}
+	visit(empty)
+
+	indexes := make([]int, 0, len(used))
+	for i := range used {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	position := make(map[int]int, len(indexes))
+	steps := make([]resolutionStep, len(indexes))
+	for n, i := range indexes {
+		position[i] = n
+		node := s.nodes[i]
+		parents := make([]int, len(node.parents))
+		for k, parent := range node.parents {
+			parents[k] = position[parent]
+		}
+		source := node.source
+		if node.rule != "clausification" {
+			source = -1
+		}
+		steps[n] = resolutionStep{clause: node.clause, source: source, parents: parents, rule: node.rule, bindings: node.bindings}
+	}
+	return steps
+}
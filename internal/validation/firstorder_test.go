@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormalize(t *testing.T) {
+	tests := map[string]string{
+		"All men are mortal":                             "∀v1 (man(v1) → mortal(v1))",
+		"Socrates is a man":                              "man(socrates)",
+		"No reptiles are warm-blooded":                   "∀v1 (reptile(v1) → ¬warm-blooded(v1))",
+		"Some birds cannot fly":                          "∃v1 (bird(v1) ∧ ¬fly(v1))",
+		"All rich people are happy":                      "∀v1 (rich(v1) → happy(v1))",
+		"Every service that calls auth must use TLS":     "∀v1 ((service(v1) ∧ call_auth(v1)) → use_tls(v1))",
+		"Everything that is logged must be encrypted":    "∀v1 (logged(v1) → encrypted(v1))",
+		"For all x, if x is human then x is mortal":      "∀x (human(x) → mortal(x))",
+		"There exists an x such that x loves everything": "",
+		"for all x, there exists y such that x loves y":  "∀x ∃y love(x, y)",
+		"∀x (P(x) → ∃y R(x, f(y)))":                      "∀x (P(x) → ∃y R(x, f(y)))",
+		"no sunny weather":                               "",
+	}
+	for statement, want := range tests {
+		parsed, err := ParseFormula(statement)
+		if err != nil {
+			t.Errorf("ParseFormula(%q): %v", statement, err)
+			continue
+		}
+		formulas, err := formalize([]*Formula{parsed})
+		if want == "" {
+			if err == nil {
+				t.Errorf("formalize(%q) = %s, want an error", statement, formulas[0])
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("formalize(%q): %v", statement, err)
+		} else if got := formulas[0].String(); got != want {
+			t.Errorf("formalize(%q) = %s, want %s", statement, got, want)
+		}
+	}
+}
+
+func TestProve_FirstOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		premises   []string
+		conclusion string
+		want       bool
+	}{
+		{"barbara", []string{"All men are mortal", "Socrates is a man"}, "Socrates is mortal", true},
+		{"undistributed middle", []string{"All cats are mammals", "All dogs are mammals"}, "All cats are dogs", false},
+		{"celarent", []string{"No reptiles are warm-blooded", "All snakes are reptiles"}, "No snakes are warm-blooded", true},
+		{"darii", []string{"All rich people are happy", "Some doctors are rich"}, "Some doctors are happy", true},
+		{"illicit existential", []string{"Some birds cannot fly", "All penguins are birds"}, "Some birds are penguins", false},
+		{"relative clause", []string{"Everyone who studies hard passes the exam", "Some students study hard"}, "Some students pass the exam", true},
+		{"variables", []string{"For all x, if x is human then x is mortal", "Socrates is human"}, "Socrates is mortal", true},
+		{"skolem function", []string{"for all x, there exists y such that x loves y"}, "there exists z such that alice loves z", true},
+		{"quantifier swap", []string{"∃y ∀x loves(x, y)"}, "∀x ∃y loves(x, y)", true},
+		{"invalid quantifier swap", []string{"∀x ∃y loves(x, y)"}, "∃y ∀x loves(x, y)", false},
+		{"chain", []string{"∀x (P(x) → Q(x))", "∀x (Q(x) → R(x))", "P(a)"}, "R(a)", true},
+	}
+	v := NewLogicValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Prove(tt.premises, tt.conclusion)
+			if result.IsProvable != tt.want || result.Method != ProofMethodFirstOrder {
+				t.Fatalf("IsProvable = %v, Method = %s, want %v by %s\nSteps: %s",
+					result.IsProvable, result.Method, tt.want, ProofMethodFirstOrder, strings.Join(result.Steps, "\n"))
+			}
+			last := result.Steps[len(result.Steps)-1]
+			if tt.want && last != "Therefore: "+tt.conclusion {
+				t.Errorf("last step = %q", last)
+			}
+			if !tt.want && !strings.Contains(strings.Join(result.Steps, "\n"), "saturates without a contradiction") {
+				t.Errorf("steps = %v", result.Steps)
+			}
+		})
+	}
+}
+
+func TestProve_FirstOrderTrace(t *testing.T) {
+	result := NewLogicValidator().Prove([]string{"All men are mortal", "Socrates is a man"}, "Socrates is mortal")
+	want := []string{
+		"Formalized premise 1: ∀v1 (man(v1) → mortal(v1))",
+		"  1. mortal(x1) ∨ ¬man(x1)  [premise 1]",
+		"  3. ¬mortal(socrates)  [negated conclusion]",
+		"  4. ¬man(socrates)  [resolve 1 and 3 with x1 = socrates]",
+		"  5. ⊥  [resolve 2 and 4]",
+	}
+	steps := strings.Join(result.Steps, "\n")
+	for _, line := range want {
+		if !strings.Contains(steps, line) {
+			t.Errorf("steps missing %q:\n%s", line, steps)
+		}
+	}
+}
+
+func TestProveTheorem_Resolution(t *testing.T) {
+	theorem := &SymbolicTheorem{
+		Name: "TLS policy",
+		Premises: []string{
+			"Every service that calls auth must use TLS",
+			"Payments is a service",
+			"Payments calls auth",
+		},
+		Conclusion: "Payments uses TLS",
+	}
+	proof, err := NewSymbolicReasoner().ProveTheorem(theorem)
+	if err != nil {
+		t.Fatalf("ProveTheorem: %v", err)
+	}
+	if !proof.IsValid || theorem.Status != StatusProven || proof.Method != ProofMethodFirstOrder {
+		t.Fatalf("proof = %+v, status %s", proof, theorem.Status)
+	}
+	for i, step := range proof.Steps {
+		if step.StepNumber != i+1 {
+			t.Errorf("step %d numbered %d", i+1, step.StepNumber)
+		}
+		for _, dependency := range step.Dependencies {
+			if dependency < 1 || dependency >= step.StepNumber {
+				t.Errorf("step %d depends on step %d", step.StepNumber, dependency)
+			}
+		}
+	}
+	if step := proof.Steps[3]; step.Rule != "negated_conclusion" || step.Statement != "¬use_tls(payments)" {
+		t.Errorf("step 4 = %+v", step)
+	}
+	last := proof.Steps[len(proof.Steps)-1]
+	if last.Rule != "refutation" || last.Statement != theorem.Conclusion || proof.Steps[len(proof.Steps)-2].Statement != "⊥" {
+		t.Errorf("last steps = %+v, %+v", proof.Steps[len(proof.Steps)-2], last)
+	}
+
+	// Without the premise that payments is a service the policy does not apply
+	theorem.Premises = []string{theorem.Premises[0], theorem.Premises[2]}
+	proof, _ = NewSymbolicReasoner().ProveTheorem(theorem)
+	if proof.IsValid || theorem.Status != StatusUnproven || !strings.Contains(proof.Explanation, "saturates") {
+		t.Errorf("proof = %+v, status %s", proof, theorem.Status)
+	}
+}
+
+func TestProveTheorem_SearchLimits(t *testing.T) {
+	// Resolution keeps deriving ¬Q(f(x)), ¬Q(f(f(x))), ... and never saturates
+	theorem := &SymbolicTheorem{
+		Premises:   []string{"∀x (Q(f(x)) → Q(x))"},
+		Conclusion: "∃y Q(y)",
+	}
+	sr := NewSymbolicReasoner()
+	proof, err := sr.ProveTheoremWithLimits(theorem, ProverLimits{MaxDepth: 4, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("ProveTheoremWithLimits: %v", err)
+	}
+	if proof.IsValid || theorem.Status != StatusUndecidable || !strings.Contains(proof.Explanation, "depth 4") {
+		t.Errorf("proof = %+v, status %s", proof, theorem.Status)
+	}
+
+	sr.SetProverLimits(ProverLimits{MaxClauses: 3})
+	if _, err := sr.ProveTheorem(theorem); err != nil || theorem.Status != StatusUndecidable {
+		t.Errorf("status = %s, err %v", theorem.Status, err)
+	}
+}
+
+func TestUnify(t *testing.T) {
+	x := &Term{Name: "x", Variable: true}
+	y := &Term{Name: "y", Variable: true}
+	a := &Term{Name: "a"}
+	fx := &Term{Name: "f", Args: []*Term{x}}
+	fa := &Term{Name: "f", Args: []*Term{a}}
+
+	s := substitution{}
+	if !s.unify(fx, &Term{Name: "f", Args: []*Term{y}}) || !s.unify(y, a) || s.apply(fx).String() != "f(a)" {
+		t.Errorf("f(x) = f(y), y = a: %v", s)
+	}
+	if s := (substitution{}); s.unify(x, fx) {
+		t.Error("x unified with f(x) despite the occurs check")
+	}
+	if s := (substitution{}); s.unify(fa, &Term{Name: "g", Args: []*Term{a}}) {
+		t.Error("f(a) unified with g(a)")
+	}
+}
@@ -0,0 +1,932 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FormulaOp is the connective at the root of a formula
+type FormulaOp string
+
+const (
+	FormulaAtom    FormulaOp = "atom"
+	FormulaTrue    FormulaOp = "true"
+	FormulaFalse   FormulaOp = "false"
+	FormulaNot     FormulaOp = "not"
+	FormulaAnd     FormulaOp = "and"
+	FormulaOr      FormulaOp = "or"
+	FormulaImplies FormulaOp = "implies"
+	FormulaIff     FormulaOp = "iff"
+	FormulaForAll  FormulaOp = "forall"
+	FormulaExists  FormulaOp = "exists"
+)
+
+// Formula is the syntax tree of a logical statement.
+//
+// Statements may be written symbolically ("(P ∧ Q) → ¬R", "P -> Q",
+// "∀x P(x)") or in English ("if it rains, the ground is wet", "neither A nor
+// B", "A unless B", "for all x, if x is human then x is mortal"). A run of words between connectives is one proposition;
+// it is normalized so that "It does not rain" is the negation of "it rains".
+type Formula struct {
+	Op         FormulaOp
+	Name       string   // Atom: predicate or normalized proposition
+	Args       []*Term  // Atom: predicate arguments
+	Quantified bool     // Atom: English phrase with a quantifier ("all", "some", "no", ...)
+	Var        string   // ForAll, Exists: bound variable
+	Left       *Formula // Operand of Not and quantifiers, left operand of binary connectives
+	Right      *Formula
+
+	words []string // Atom: the English words of the proposition, before stemming
+}
+
+// Term is a predicate argument: a variable, a constant or a function application
+type Term struct {
+	Name     string
+	Args     []*Term
+	Variable bool
+}
+
+// SyntaxError reports a malformed statement at a character offset (0-based)
+type SyntaxError struct {
+	Pos     int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos)
+}
+
+// ParseFormula parses a statement into a formula
+func ParseFormula(statement string) (*Formula, error) {
+	tokens, end, err := lexFormula(statement)
+	if err != nil {
+		return nil, err
+	}
+	p := &formulaParser{tokens: tokens, end: end}
+	for p.isWord(p.peek(), "therefore", "thus", "hence", "so") {
+		p.next()
+	}
+	if p.peek().kind == tokEOF {
+		return nil, &SyntaxError{Pos: 0, Message: "Statement is empty"}
+	}
+	formula, err := p.parseIff()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.unexpected(tok)
+	}
+	return formula, nil
+}
+
+// String renders the formula with logical symbols
+func (f *Formula) String() string {
+	switch f.Op {
+	case FormulaAtom:
+		if len(f.Args) == 0 {
+			return f.Name
+		}
+		return f.Name + termList(f.Args)
+	case FormulaTrue:
+		return "⊤"
+	case FormulaFalse:
+		return "⊥"
+	case FormulaNot:
+		return "¬" + f.Left.operandString()
+	case FormulaForAll:
+		return "∀" + f.Var + " " + f.Left.operandString()
+	case FormulaExists:
+		return "∃" + f.Var + " " + f.Left.operandString()
+	}
+	left := f.Left.operandString()
+	if f.Left.openEnded() {
+		left = "(" + left + ")"
+	}
+	return left + " " + formulaSymbols[f.Op] + " " + f.Right.operandString()
+}
+
+// openEnded reports whether the rendering of f ends in a quantifier body,
+// which would extend over anything written after it
+func (f *Formula) openEnded() bool {
+	switch f.Op {
+	case FormulaForAll, FormulaExists:
+		return true
+	case FormulaNot:
+		return f.Left.openEnded()
+	}
+	return false
+}
+
+var formulaSymbols = map[FormulaOp]string{
+	FormulaAnd:     "∧",
+	FormulaOr:      "∨",
+	FormulaImplies: "→",
+	FormulaIff:     "↔",
+}
+
+// operandString parenthesizes binary formulas
+func (f *Formula) operandString() string {
+	if _, binary := formulaSymbols[f.Op]; binary {
+		return "(" + f.String() + ")"
+	}
+	return f.String()
+}
+
+func (t *Term) String() string {
+	if len(t.Args) == 0 {
+		return t.Name
+	}
+	return t.Name + termList(t.Args)
+}
+
+func termList(terms []*Term) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term.String()
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// isPropositional reports whether the formula has no quantifiers, so the
+// propositional prover decides it
+func (f *Formula) isPropositional() bool {
+	switch f.Op {
+	case FormulaForAll, FormulaExists:
+		return false
+	case FormulaAtom:
+		return !f.Quantified
+	case FormulaTrue, FormulaFalse:
+		return true
+	case FormulaNot:
+		return f.Left.isPropositional()
+	}
+	return f.Left.isPropositional() && f.Right.isPropositional()
+}
+
+// Lexer
+
+type formulaTokenKind int
+
+const (
+	tokEOF formulaTokenKind = iota
+	tokWord
+	tokOpen
+	tokClose
+	tokComma
+	tokNot
+	tokAnd
+	tokOr
+	tokXor
+	tokImplies
+	tokIff
+	tokTrue
+	tokFalse
+	tokForAll
+	tokExists
+)
+
+type formulaToken struct {
+	kind   formulaTokenKind
+	text   string
+	pos    int // Character offset of the first character
+	end    int // Character offset after the last character
+	quoted bool
+}
+
+// Operator symbols, longest first
+var formulaOperators = []struct {
+	text string
+	kind formulaTokenKind
+}{
+	{"<->", tokIff}, {"<=>", tokIff}, {"->", tokImplies}, {"=>", tokImplies},
+	{"&&", tokAnd}, {"||", tokOr}, {"!=", tokWord},
+	{"↔", tokIff}, {"⇔", tokIff}, {"≡", tokIff}, {"→", tokImplies}, {"⇒", tokImplies}, {"⊃", tokImplies},
+	{"∧", tokAnd}, {"&", tokAnd}, {"∨", tokOr}, {"|", tokOr}, {"⊕", tokXor},
+	{"¬", tokNot}, {"~", tokNot}, {"!", tokNot},
+	{"⊤", tokTrue}, {"⊥", tokFalse}, {"∀", tokForAll}, {"∃", tokExists}, {",", tokComma},
+}
+
+var closingQuotes = map[rune]rune{'"': '"', '\'': '\'', '“': '”', '‘': '’'}
+
+var closingBrackets = map[string]string{"(": ")", "[": "]", "{": "}"}
+
+// lexFormula splits a statement into tokens and returns its length in characters
+func lexFormula(statement string) ([]formulaToken, int, error) {
+	runes := []rune(statement)
+	var tokens []formulaToken
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r), r == '.', r == '?', r == ';', r == ':':
+			i++
+			continue
+		case r == '(' || r == '[' || r == '{':
+			tokens = append(tokens, formulaToken{kind: tokOpen, text: string(r), pos: i, end: i + 1})
+			i++
+			continue
+		case r == ')' || r == ']' || r == '}':
+			tokens = append(tokens, formulaToken{kind: tokClose, text: string(r), pos: i, end: i + 1})
+			i++
+			continue
+		}
+
+		if closing, isQuote := closingQuotes[r]; isQuote {
+			start := i
+			i++
+			for i < len(runes) && runes[i] != closing {
+				i++
+			}
+			if i == len(runes) {
+				return nil, 0, &SyntaxError{Pos: start, Message: "Mismatched quotation marks: unterminated quote"}
+			}
+			i++
+			tokens = append(tokens, formulaToken{kind: tokWord, text: string(runes[start+1 : i-1]), pos: start, end: i, quoted: true})
+			continue
+		}
+
+		if matched := matchOperator(runes[i:]); matched >= 0 {
+			op := formulaOperators[matched]
+			width := len([]rune(op.text))
+			tokens = append(tokens, formulaToken{kind: op.kind, text: op.text, pos: i, end: i + width})
+			i += width
+			continue
+		}
+
+		start := i
+		switch {
+		case isWordRune(r):
+			for i < len(runes) && (isWordRune(runes[i]) || joinsWord(runes, i)) {
+				i++
+			}
+		case strings.ContainsRune(comparisonRunes, r):
+			for i < len(runes) && strings.ContainsRune(comparisonRunes, runes[i]) && matchOperator(runes[i:]) < 0 {
+				i++
+			}
+		default:
+			return nil, 0, &SyntaxError{Pos: i, Message: fmt.Sprintf("Unexpected character %q", r)}
+		}
+		tokens = append(tokens, formulaToken{kind: tokWord, text: string(runes[start:i]), pos: start, end: i})
+	}
+	tokens = append(tokens, formulaToken{kind: tokEOF, pos: len(runes), end: len(runes)})
+	return tokens, len(runes), nil
+}
+
+// Comparison and arithmetic symbols are words of a proposition ("x > 2")
+const comparisonRunes = "<>=≤≥≠+*/%-"
+
+func matchOperator(rest []rune) int {
+	for i, op := range formulaOperators {
+		if strings.HasPrefix(string(rest[:min(len(rest), 3)]), op.text) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// joinsWord reports whether the punctuation at i continues a word, as in
+// "cold-blooded", "doesn't" and "2.5"
+func joinsWord(runes []rune, i int) bool {
+	if i == 0 || i+1 >= len(runes) || !isWordRune(runes[i-1]) {
+		return false
+	}
+	next := runes[i+1]
+	switch runes[i] {
+	case '-':
+		return isWordRune(next)
+	case '\'', '’':
+		return unicode.IsLetter(next)
+	case '.':
+		return unicode.IsDigit(runes[i-1]) && unicode.IsDigit(next)
+	}
+	return false
+}
+
+// Parser
+
+type formulaParser struct {
+	tokens      []formulaToken
+	pos         int
+	end         int // Length of the statement in characters
+	inCondition int // Depth of "if" conditions, where a comma ends the condition
+}
+
+// Words that connect propositions and so end a run of words
+var connectiveWords = map[string]bool{
+	"and": true, "or": true, "nor": true, "xor": true, "but": true,
+	"if": true, "then": true, "implies": true, "iff": true, "unless": true,
+	"therefore": true, "thus": true, "hence": true, "either": true, "neither": true,
+}
+
+// Quantifier words mark a phrase the propositional prover cannot decide
+var quantifierWords = map[string]bool{
+	"all": true, "every": true, "each": true, "some": true, "no": true, "none": true, "any": true,
+	"everyone": true, "everybody": true, "everything": true, "someone": true, "somebody": true,
+	"something": true, "nobody": true, "nothing": true, "anyone": true, "anybody": true, "anything": true,
+}
+
+// Quantifiers that need a following noun
+var emptyQuantifiers = map[string]bool{"all": true, "every": true, "each": true, "some": true, "no": true}
+
+var articles = map[string]bool{"a": true, "an": true, "the": true}
+
+func (p *formulaParser) peek() formulaToken {
+	return p.tokens[p.pos]
+}
+
+func (p *formulaParser) next() formulaToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isWord reports whether tok is an unquoted word, case-insensitively one of words
+func (p *formulaParser) isWord(tok formulaToken, words ...string) bool {
+	if tok.kind != tokWord || tok.quoted {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(tok.text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPhrase reports whether the next tokens are the words of phrase
+func (p *formulaParser) isPhrase(phrase ...string) bool {
+	for i, word := range phrase {
+		if p.pos+i >= len(p.tokens) || !p.isWord(p.tokens[p.pos+i], word) {
+			return false
+		}
+	}
+	return true
+}
+
+// isConnective reports whether tok joins two statements
+func (p *formulaParser) isConnective(tok formulaToken) bool {
+	switch tok.kind {
+	case tokAnd, tokOr, tokXor, tokImplies, tokIff, tokComma:
+		return true
+	case tokWord:
+		return !tok.quoted && (connectiveWords[strings.ToLower(tok.text)] || p.isWord(tok, "only"))
+	}
+	return false
+}
+
+// endsRun reports whether tok ends a run of words
+func (p *formulaParser) endsRun(tok formulaToken) bool {
+	if tok.kind != tokWord {
+		return true
+	}
+	if p.isWord(tok, "only") {
+		return p.pos+1 < len(p.tokens) && p.isWord(p.tokens[p.pos+1], "if")
+	}
+	return !tok.quoted && connectiveWords[strings.ToLower(tok.text)]
+}
+
+func (p *formulaParser) unexpected(tok formulaToken) error {
+	switch {
+	case tok.kind == tokEOF:
+		return p.incomplete()
+	case tok.kind == tokClose:
+		return &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("Unbalanced parentheses: unexpected %q", tok.text)}
+	case p.isWord(tok, "then"):
+		return &SyntaxError{Pos: tok.pos, Message: `Incomplete conditional: "then" without "if"`}
+	case p.isWord(tok, "nor"):
+		return &SyntaxError{Pos: tok.pos, Message: `Malformed logical operators: "nor" without "neither"`}
+	case p.isConnective(tok) || tok.kind == tokNot:
+		return &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("Malformed logical operators: unexpected %q", tok.text)}
+	}
+	return &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("Unexpected %q", tok.text)}
+}
+
+// incomplete reports a statement that ends where an operand is expected
+func (p *formulaParser) incomplete() error {
+	if p.pos == 0 {
+		return &SyntaxError{Pos: 0, Message: "Statement is empty"}
+	}
+	last := p.tokens[p.pos-1]
+	if p.isWord(last, "then") || (last.kind == tokComma && p.inCondition > 0) {
+		return &SyntaxError{Pos: p.end, Message: fmt.Sprintf("Incomplete conditional: expected a statement after %q", last.text)}
+	}
+	return &SyntaxError{Pos: p.end, Message: fmt.Sprintf("Incomplete statement: expected a statement after %q", last.text)}
+}
+
+// parseIff parses "A ↔ B", "A iff B" and "A if and only if B"
+func (p *formulaParser) parseIff() (*Formula, error) {
+	left, err := p.parseImplies()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch tok := p.peek(); {
+		case tok.kind == tokIff || p.isWord(tok, "iff"):
+			p.next()
+		case p.isPhrase("if", "and", "only", "if"):
+			p.pos += 4
+		default:
+			return left, nil
+		}
+		right, err := p.parseImplies()
+		if err != nil {
+			return nil, err
+		}
+		left = &Formula{Op: FormulaIff, Left: left, Right: right}
+	}
+}
+
+// parseImplies parses the right-associative conditionals "A → B",
+// "A implies B", "A therefore B", "A only if B", "A if B" and "A unless B"
+func (p *formulaParser) parseImplies() (*Formula, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	switch {
+	case tok.kind == tokImplies || p.isWord(tok, "implies", "therefore", "thus", "hence"):
+		p.next()
+		right, err := p.parseImplies()
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaImplies, Left: left, Right: right}, nil
+	case p.isPhrase("only", "if"):
+		p.pos += 2
+		right, err := p.parseImplies()
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaImplies, Left: left, Right: right}, nil
+	case p.isWord(tok, "if") && !p.isPhrase("if", "and", "only", "if"):
+		p.next()
+		condition, err := p.parseImplies()
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaImplies, Left: condition, Right: left}, nil
+	case p.isWord(tok, "unless"):
+		p.next()
+		right, err := p.parseImplies()
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaOr, Left: left, Right: right}, nil
+	}
+	return left, nil
+}
+
+// parseOr parses disjunctions and exclusive disjunctions
+func (p *formulaParser) parseOr() (*Formula, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		exclusive := tok.kind == tokXor || p.isWord(tok, "xor")
+		if !exclusive && tok.kind != tokOr && !p.isWord(tok, "or") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if exclusive {
+			left = &Formula{Op: FormulaNot, Left: &Formula{Op: FormulaIff, Left: left, Right: right}}
+		} else {
+			left = &Formula{Op: FormulaOr, Left: left, Right: right}
+		}
+	}
+}
+
+// parseAnd parses conjunctions, including "A but B"
+func (p *formulaParser) parseAnd() (*Formula, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokAnd && !p.isWord(tok, "and", "but") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Formula{Op: FormulaAnd, Left: left, Right: right}
+	}
+}
+
+// parseUnary parses negations
+func (p *formulaParser) parseUnary() (*Formula, error) {
+	switch tok := p.peek(); {
+	case tok.kind == tokNot || p.isWord(tok, "not"):
+		p.next()
+	case p.isPhrase("it", "is", "not", "the", "case", "that"):
+		p.pos += 6
+	default:
+		return p.parsePrimary()
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &Formula{Op: FormulaNot, Left: operand}, nil
+}
+
+func (p *formulaParser) parsePrimary() (*Formula, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokOpen:
+		p.next()
+		inner, err := p.parseIff()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectClose(tok); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tok.kind == tokTrue:
+		p.next()
+		return &Formula{Op: FormulaTrue}, nil
+	case tok.kind == tokFalse:
+		p.next()
+		return &Formula{Op: FormulaFalse}, nil
+	case tok.kind == tokForAll || tok.kind == tokExists:
+		return p.parseQuantifier()
+	case p.isWord(tok, "for", "there"):
+		if op, variable, width := p.englishQuantifier(); width > 0 {
+			p.pos += width
+			body, err := p.parseIff()
+			if err != nil {
+				return nil, err
+			}
+			return &Formula{Op: op, Var: variable, Left: body}, nil
+		}
+		return p.parseProposition()
+	case p.isWord(tok, "if"):
+		return p.parseConditional()
+	case p.isWord(tok, "either"):
+		p.next()
+		return p.parseOr()
+	case p.isWord(tok, "neither"):
+		return p.parseNeither()
+	case p.isWord(tok, "both"):
+		p.next()
+		return p.parseAnd()
+	case tok.kind == tokWord && !p.endsRun(tok):
+		if p.tokens[p.pos+1].kind == tokOpen && p.tokens[p.pos+1].pos == tok.end {
+			return p.parsePredicate()
+		}
+		return p.parseProposition()
+	}
+	return nil, p.unexpected(tok)
+}
+
+// expectClose consumes the bracket closing open
+func (p *formulaParser) expectClose(open formulaToken) error {
+	tok := p.peek()
+	if tok.kind == tokEOF {
+		return &SyntaxError{Pos: open.pos, Message: fmt.Sprintf("Unbalanced parentheses: unclosed %q", open.text)}
+	}
+	if tok.kind != tokClose {
+		return p.unexpected(tok)
+	}
+	if want := closingBrackets[open.text]; tok.text != want {
+		return &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("Unbalanced parentheses: %q closes %q", tok.text, open.text)}
+	}
+	p.next()
+	return nil
+}
+
+// parseConditional parses "if A then B" and "if A, B"
+func (p *formulaParser) parseConditional() (*Formula, error) {
+	p.next()
+	p.inCondition++
+	condition, err := p.parseIff()
+	p.inCondition--
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	switch {
+	case tok.kind == tokComma:
+		p.next()
+		if p.isWord(p.peek(), "then") {
+			p.next()
+		}
+	case p.isWord(tok, "then"):
+		p.next()
+	case tok.kind == tokEOF:
+		return nil, &SyntaxError{Pos: p.end, Message: `Incomplete conditional: expected "then" or "," after the condition`}
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf(`Incomplete conditional: expected "then" or "," before %q`, tok.text)}
+	}
+	consequent, err := p.parseImplies()
+	if err != nil {
+		return nil, err
+	}
+	return &Formula{Op: FormulaImplies, Left: condition, Right: consequent}, nil
+}
+
+// parseNeither parses "neither A nor B"
+func (p *formulaParser) parseNeither() (*Formula, error) {
+	p.next()
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isWord(p.peek(), "nor") {
+		return nil, &SyntaxError{Pos: p.peek().pos, Message: `Malformed logical operators: expected "nor" after "neither"`}
+	}
+	p.next()
+	right, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	return &Formula{Op: FormulaAnd, Left: &Formula{Op: FormulaNot, Left: left}, Right: &Formula{Op: FormulaNot, Left: right}}, nil
+}
+
+// parseQuantifier parses "∀x body" and "∃x body"; the body extends as far
+// right as possible
+func (p *formulaParser) parseQuantifier() (*Formula, error) {
+	quantifier := p.next()
+	variable := p.peek()
+	if variable.kind != tokWord || p.endsRun(variable) {
+		return nil, &SyntaxError{Pos: variable.pos, Message: fmt.Sprintf("Empty or incomplete quantifier: expected a variable after %q", quantifier.text)}
+	}
+	p.next()
+	if p.peek().kind == tokComma {
+		p.next()
+	}
+	body, err := p.parseIff()
+	if err != nil {
+		return nil, err
+	}
+	op := FormulaForAll
+	if quantifier.kind == tokExists {
+		op = FormulaExists
+	}
+	return &Formula{Op: op, Var: variable.text, Left: body}, nil
+}
+
+// englishQuantifier matches "for all x", "for every x", "there exists x such
+// that" and "there is an x such that", returning the quantifier, its variable
+// and the number of tokens it spans (0 when the next tokens are not a
+// quantifier). Variables are single letters, optionally numbered.
+func (p *formulaParser) englishQuantifier() (FormulaOp, string, int) {
+	at := func(i int) formulaToken {
+		return p.tokens[min(p.pos+i, len(p.tokens)-1)]
+	}
+	op, i := FormulaForAll, 2
+	switch {
+	case p.isWord(at(0), "for") && p.isWord(at(1), "all", "every", "each", "any"):
+	case p.isWord(at(0), "there") && p.isWord(at(1), "exists", "exist", "is", "are"):
+		op = FormulaExists
+		if p.isWord(at(i), "a", "an", "some") && isVariableName(at(i+1)) {
+			i++
+		}
+	default:
+		return "", "", 0
+	}
+	variable := at(i)
+	if !isVariableName(variable) {
+		return "", "", 0
+	}
+	i++
+	switch {
+	case p.isWord(at(i), "such") && p.isWord(at(i+1), "that"):
+		i += 2
+	case p.isWord(at(i), "where", "with"), at(i).kind == tokComma:
+		i++
+	}
+	return op, variable.text, i
+}
+
+// isVariableName reports whether tok is a variable such as x, y or x1
+func isVariableName(tok formulaToken) bool {
+	runes := []rune(tok.text)
+	if tok.kind != tokWord || tok.quoted || len(runes) == 0 || !unicode.IsLetter(runes[0]) {
+		return false
+	}
+	for _, r := range runes[1:] {
+		if !unicode.IsDigit(r) && r != '\'' {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePredicate parses "P(t1, ..., tn)"
+func (p *formulaParser) parsePredicate() (*Formula, error) {
+	term, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	return &Formula{Op: FormulaAtom, Name: term.Name, Args: term.Args}, nil
+}
+
+// parseTerm parses a name with an optional argument list written directly after it
+func (p *formulaParser) parseTerm() (*Term, error) {
+	tok := p.peek()
+	if tok.kind != tokWord {
+		return nil, &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("Expected a term but found %q", tok.text)}
+	}
+	p.next()
+	term := &Term{Name: tok.text}
+	open := p.peek()
+	if open.kind != tokOpen || open.pos != tok.end {
+		return term, nil
+	}
+	p.next()
+	for {
+		arg, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		term.Args = append(term.Args, arg)
+		if p.peek().kind != tokComma {
+			break
+		}
+		p.next()
+	}
+	if err := p.expectClose(open); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// parseProposition parses a run of words as one proposition. A "not" inside
+// the run negates the proposition, except in a quantified phrase, where it
+// stays a word: "some birds do not fly" is not the negation of "some birds
+// fly".
+func (p *formulaParser) parseProposition() (*Formula, error) {
+	var words []string
+	var last formulaToken
+	negated, quantified := false, false
+	negate := func() {
+		if quantified {
+			words = append(words, "not")
+		} else {
+			negated = !negated
+		}
+	}
+	for {
+		tok := p.peek()
+		if tok.kind == tokComma && p.inCondition == 0 {
+			p.next()
+			continue
+		}
+		if tok.kind == tokOpen && len(words) > 0 {
+			remark, err := p.parseParenthetical()
+			if err != nil {
+				return nil, err
+			}
+			words = append(words, remark...)
+			continue
+		}
+		if p.endsRun(tok) {
+			break
+		}
+		p.next()
+		word := strings.ToLower(tok.text)
+		if !tok.quoted {
+			switch {
+			case (word == "not" || word == "never") && len(words) > 0:
+				if n := len(words); words[n-1] == "do" || words[n-1] == "does" || words[n-1] == "did" {
+					words = words[:n-1]
+				}
+				negate()
+				continue
+			case word == "cannot":
+				words = append(words, "can")
+				negate()
+				last = tok
+				continue
+			case strings.HasSuffix(word, "n't") || strings.HasSuffix(word, "n’t"):
+				if base := contractionBase(word); base != "" {
+					words = append(words, base)
+				}
+				negate()
+				last = tok
+				continue
+			}
+			quantified = quantified || quantifierWords[word]
+		}
+		words = append(words, word)
+		last = tok
+	}
+	if len(words) == 0 {
+		return nil, p.unexpected(p.peek())
+	}
+	if !last.quoted && emptyQuantifiers[words[len(words)-1]] {
+		return nil, &SyntaxError{Pos: last.pos, Message: fmt.Sprintf("Empty or incomplete quantifier: %q has nothing to quantify", last.text)}
+	}
+	return proposition(words, negated, quantified), nil
+}
+
+// parseParenthetical reads a bracketed remark inside a run of words, as in
+// "the service (api) is down", as words of the proposition
+func (p *formulaParser) parseParenthetical() ([]string, error) {
+	var words []string
+	var open []formulaToken
+	for {
+		tok := p.next()
+		switch tok.kind {
+		case tokEOF:
+			return nil, &SyntaxError{Pos: open[0].pos, Message: fmt.Sprintf("Unbalanced parentheses: unclosed %q", open[0].text)}
+		case tokOpen:
+			open = append(open, tok)
+		case tokClose:
+			if want := closingBrackets[open[len(open)-1].text]; tok.text != want {
+				return nil, &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("Unbalanced parentheses: %q closes %q", tok.text, open[len(open)-1].text)}
+			}
+			if open = open[:len(open)-1]; len(open) == 0 {
+				return words, nil
+			}
+		default:
+			words = append(words, strings.ToLower(tok.text))
+		}
+	}
+}
+
+// contractionBase returns the verb of a negative contraction, or "" for an
+// auxiliary that only carries the negation ("doesn't")
+func contractionBase(word string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(word, "n't"), "n’t")
+	switch base {
+	case "do", "does", "did":
+		return ""
+	case "ca":
+		return "can"
+	case "wo":
+		return "will"
+	case "sha":
+		return "shall"
+	}
+	return base
+}
+
+// proposition normalizes a run of words so that phrasings of the same
+// proposition share a name: articles are dropped, "P is true" is P, "P is
+// false" is ¬P and plural or third-person "s" endings are removed
+func proposition(words []string, negated, quantified bool) *Formula {
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		if !articles[word] {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == 0 {
+		kept = words
+	}
+	if n := len(kept); n >= 3 && (kept[n-2] == "is" || kept[n-2] == "are") && (kept[n-1] == "true" || kept[n-1] == "false") {
+		negated = negated != (kept[n-1] == "false")
+		kept = kept[:n-2]
+	}
+
+	var formula *Formula
+	switch {
+	case len(kept) == 1 && kept[0] == "true":
+		formula = &Formula{Op: FormulaTrue}
+	case len(kept) == 1 && kept[0] == "false":
+		formula = &Formula{Op: FormulaFalse}
+	default:
+		stemmed := make([]string, len(kept))
+		for i, word := range kept {
+			stemmed[i] = stemWord(word)
+		}
+		formula = &Formula{Op: FormulaAtom, Name: strings.Join(stemmed, " "), Quantified: quantified, words: kept}
+	}
+	if negated {
+		return &Formula{Op: FormulaNot, Left: formula}
+	}
+	return formula
+}
+
+// stemWord removes a plural or third-person "s" ("rains" and "rain" match)
+func stemWord(word string) string {
+	switch {
+	case len(word) > 5 && (strings.HasSuffix(word, "sses") || strings.HasSuffix(word, "shes") || strings.HasSuffix(word, "xes")):
+		return word[:len(word)-2]
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") &&
+		!strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word[:len(word)-1]
+	}
+	return word
+}
@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseFormula(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{"P", "p"},
+		{"(P ∧ Q) → ¬R", "(p ∧ q) → ¬r"},
+		{"P -> Q -> R", "p → (q → r)"},
+		{"A <=> B || !C && D", "a ↔ (b ∨ (¬c ∧ d))"},
+		{"P xor Q", "¬(p ↔ q)"},
+		{"If it rains, the ground is wet", "it rain → ground is wet"},
+		{"If it rains then the ground is wet and slippery", "it rain → (ground is wet ∧ slippery)"},
+		{"The ground is wet if it rains", "it rain → ground is wet"},
+		{"We ship only if the tests pass", "we ship → test pass"},
+		{"A if and only if B", "a ↔ b"},
+		{"A unless B", "a ∨ b"},
+		{"Either A or B", "a ∨ b"},
+		{"Neither A nor B", "¬a ∧ ¬b"},
+		{"It is not the case that P", "¬p"},
+		{"The ground is not wet", "¬ground is wet"},
+		{"It does not rain", "¬it rain"},
+		{"The build doesn't pass", "¬build pass"},
+		{"P is false", "¬p"},
+		{"Therefore Q is true", "q"},
+		{"The service (api) is down but the cache is up", "service api is down ∧ cache is up"},
+		{"Latency > 200 implies an alert", "latency > 200 → alert"},
+		{"∀x (Human(x) → Mortal(x))", "∀x (Human(x) → Mortal(x))"},
+		{"∃y Loves(alice, f(y))", "∃y Loves(alice, f(y))"},
+		{"⊤ ∨ ⊥", "⊤ ∨ ⊥"},
+	}
+	for _, tt := range tests {
+		formula, err := ParseFormula(tt.statement)
+		if err != nil {
+			t.Errorf("ParseFormula(%q): %v", tt.statement, err)
+			continue
+		}
+		if got := formula.String(); got != tt.want {
+			t.Errorf("ParseFormula(%q) = %s, want %s", tt.statement, got, tt.want)
+		}
+	}
+
+	// Quantified English phrases are parsed but not propositional
+	formula, err := ParseFormula("All men are mortal")
+	if err != nil || formula.isPropositional() {
+		t.Errorf("formula = %v, err %v, want a quantified proposition", formula, err)
+	}
+}
+
+func TestParseFormula_Errors(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+		pos       int
+	}{
+		{"", "Statement is empty", 0},
+		{"A and and B", `Malformed logical operators: unexpected "and"`, 6},
+		{"A or", `Incomplete statement: expected a statement after "or"`, 4},
+		{"(P or Q", `Unbalanced parentheses: unclosed "("`, 0},
+		{"P or Q)", `Unbalanced parentheses: unexpected ")"`, 6},
+		{"P ∧ (Q ∨ R]", `Unbalanced parentheses: "]" closes "("`, 10},
+		{"Test (statement (nested", `Unbalanced parentheses: unclosed "("`, 5},
+		{"If the sky is blue we are happy", `Incomplete conditional: expected "then" or ","`, 31},
+		{"If A then", `Incomplete conditional: expected a statement after "then"`, 9},
+		{"Then we are happy", `Incomplete conditional: "then" without "if"`, 0},
+		{"Test 'statement without closing", "Mismatched quotation marks", 5},
+		{"Some things are all", `Empty or incomplete quantifier: "all"`, 16},
+		{"Neither A or B", `expected "nor" after "neither"`, 10},
+		{"∀ (P)", "expected a variable", 2},
+		{"x @ y", "Unexpected character '@'", 2},
+		{"¬¬", `Incomplete statement: expected a statement after "¬"`, 2},
+	}
+	for _, tt := range tests {
+		_, err := ParseFormula(tt.statement)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseFormula(%q): err = %v, want a syntax error", tt.statement, err)
+			continue
+		}
+		if !strings.Contains(syntaxErr.Message, tt.want) || syntaxErr.Pos != tt.pos {
+			t.Errorf("ParseFormula(%q): err = %v, want %q at position %d", tt.statement, err, tt.want, tt.pos)
+		}
+	}
+}
+
+func TestCheckWellFormed_Position(t *testing.T) {
+	checks := NewLogicValidator().CheckWellFormed([]string{"If A then B", "A and and B"})
+	if !checks[0].IsWellFormed || checks[0].Position != nil {
+		t.Errorf("check = %+v", checks[0])
+	}
+	if checks[1].IsWellFormed || checks[1].Position == nil || *checks[1].Position != 6 {
+		t.Errorf("check = %+v", checks[1])
+	}
+}
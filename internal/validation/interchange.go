@@ -0,0 +1,401 @@
+package validation
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Problem interchange with external provers.
+//
+// Theorems are read from TPTP (FOF and CNF) and SMT-LIB v2 into premises and
+// a conclusion in the formula syntax ProveTheorem accepts; constraint sets are
+// read from SMT-LIB arithmetic and TPTP TFF arithmetic into linear constraint
+// expressions. Exports formalize statements the same way the first-order
+// prover does, so an external prover sees the problem that was proved.
+
+// ConstraintProblem is a set of symbols and constraints read from a problem
+// file
+type ConstraintProblem struct {
+	Name        string
+	Symbols     []*Symbol
+	Constraints []*SymbolicConstraint // ID is the problem's name for the constraint, if any
+	Status      string                // Status the problem declares: "sat", "unsat" or ""
+}
+
+// ImportedTheorem is a theorem read from a problem file
+type ImportedTheorem struct {
+	Theorem *SymbolicTheorem
+	Status  string // Status the problem declares, e.g. "Theorem", "CounterSatisfiable", "unsat"
+}
+
+// ExpectsProof reports whether the declared status says the conclusion
+// follows from the premises
+func (t *ImportedTheorem) ExpectsProof() (expected, known bool) {
+	switch strings.ToLower(t.Status) {
+	case "theorem", "unsatisfiable", "contradictoryaxioms", "unsat":
+		return true, true
+	case "countersatisfiable", "satisfiable", "countertheorem", "sat":
+		return false, true
+	}
+	return false, false
+}
+
+// Words the formula and constraint parsers read as connectives, quantifiers
+// or articles
+var reservedImportNames = map[string]bool{
+	"not": true, "never": true, "cannot": true, "for": true, "there": true, "true": true, "false": true,
+	"both": true, "is": true, "are": true, "it": true, "so": true, "and": true, "or": true, "in": true, "between": true,
+}
+
+var unsafeNameRunes = regexp.MustCompile(`[^\p{L}\p{N}_]+`)
+
+// importName turns a TPTP or SMT-LIB name into one the formula parser reads
+// back as the same predicate, function or constant
+func importName(name string) string {
+	name = strings.Trim(unsafeNameRunes.ReplaceAllString(name, "_"), "_")
+	if name == "" {
+		return "_"
+	}
+	lower := strings.ToLower(name)
+	if connectiveWords[lower] || quantifierWords[lower] || articles[lower] || reservedImportNames[lower] {
+		return name + "_"
+	}
+	return name
+}
+
+// conjoin folds formulas with ∧; nil for none
+func conjoin(formulas []*Formula) *Formula {
+	var result *Formula
+	for _, f := range formulas {
+		if result == nil {
+			result = f
+		} else {
+			result = &Formula{Op: FormulaAnd, Left: result, Right: f}
+		}
+	}
+	return result
+}
+
+// universalClosure quantifies the free variables of a formula in order of
+// appearance
+func universalClosure(f *Formula, free []string) *Formula {
+	for i := len(free) - 1; i >= 0; i-- {
+		f = &Formula{Op: FormulaForAll, Var: free[i], Left: f}
+	}
+	return f
+}
+
+// formalizeStatements parses and formalizes statements together, as the
+// first-order prover does
+func formalizeStatements(statements []string) ([]*Formula, error) {
+	parsed := make([]*Formula, len(statements))
+	for i, statement := range statements {
+		formula, err := ParseFormula(statement)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q: %w", statement, err)
+		}
+		parsed[i] = formula
+	}
+	formulas, err := formalize(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot formalize the statements in first-order logic: %w", err)
+	}
+	return formulas, nil
+}
+
+// theoremFormulas formalizes the premises and conclusion of a theorem
+func theoremFormulas(theorem *SymbolicTheorem) ([]*Formula, *Formula, error) {
+	if strings.TrimSpace(theorem.Conclusion) == "" {
+		return nil, nil, fmt.Errorf("theorem has no conclusion")
+	}
+	formulas, err := formalizeStatements(append(append([]string(nil), theorem.Premises...), theorem.Conclusion))
+	if err != nil {
+		return nil, nil, err
+	}
+	return formulas[:len(formulas)-1], formulas[len(formulas)-1], nil
+}
+
+// signature records the arity of each predicate and function symbol
+type signature struct {
+	predicates map[string]int
+	functions  map[string]int
+}
+
+func newSignature() *signature {
+	return &signature{predicates: map[string]int{}, functions: map[string]int{}}
+}
+
+// add records the symbols of a formula; names in bound are variables
+func (s *signature) add(f *Formula, bound map[string]bool) error {
+	switch f.Op {
+	case FormulaAtom:
+		if err := s.addPredicate(f.Name, len(f.Args)); err != nil {
+			return err
+		}
+		for _, arg := range f.Args {
+			if err := s.addTerm(arg, bound); err != nil {
+				return err
+			}
+		}
+	case FormulaTrue, FormulaFalse:
+	case FormulaNot:
+		return s.add(f.Left, bound)
+	case FormulaForAll, FormulaExists:
+		inner := make(map[string]bool, len(bound)+1)
+		for name := range bound {
+			inner[name] = true
+		}
+		inner[f.Var] = true
+		return s.add(f.Left, inner)
+	default:
+		if err := s.add(f.Left, bound); err != nil {
+			return err
+		}
+		return s.add(f.Right, bound)
+	}
+	return nil
+}
+
+func (s *signature) addTerm(t *Term, bound map[string]bool) error {
+	if len(t.Args) == 0 && bound[t.Name] {
+		return nil
+	}
+	if err := s.addFunction(t.Name, len(t.Args)); err != nil {
+		return err
+	}
+	for _, arg := range t.Args {
+		if err := s.addTerm(arg, bound); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *signature) addPredicate(name string, arity int) error {
+	if _, clash := s.functions[name]; clash {
+		return fmt.Errorf("%s is used both as a predicate and as a function", name)
+	}
+	return recordArity(s.predicates, name, arity)
+}
+
+func (s *signature) addFunction(name string, arity int) error {
+	if _, clash := s.predicates[name]; clash {
+		return fmt.Errorf("%s is used both as a predicate and as a function", name)
+	}
+	return recordArity(s.functions, name, arity)
+}
+
+func recordArity(symbols map[string]int, name string, arity int) error {
+	if previous, ok := symbols[name]; ok && previous != arity {
+		return fmt.Errorf("%s is used with %d and %d arguments", name, previous, arity)
+	}
+	symbols[name] = arity
+	return nil
+}
+
+func sortedKeys(symbols map[string]int) []string {
+	names := make([]string, 0, len(symbols))
+	for name := range symbols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clauseVariables returns the variables of a clause printed by the
+// resolution prover, which names them x1, x2, ...
+func clauseVariables(f *Formula) []string {
+	seen := map[string]bool{}
+	var names []string
+	var visitTerm func(t *Term)
+	visitTerm = func(t *Term) {
+		if len(t.Args) == 0 && isClauseVariable(t.Name) && !seen[t.Name] {
+			seen[t.Name] = true
+			names = append(names, t.Name)
+		}
+		for _, arg := range t.Args {
+			visitTerm(arg)
+		}
+	}
+	var visit func(f *Formula)
+	visit = func(f *Formula) {
+		switch f.Op {
+		case FormulaAtom:
+			for _, arg := range f.Args {
+				visitTerm(arg)
+			}
+		case FormulaTrue, FormulaFalse:
+		case FormulaNot, FormulaForAll, FormulaExists:
+			visit(f.Left)
+		default:
+			visit(f.Left)
+			visit(f.Right)
+		}
+	}
+	visit(f)
+	return names
+}
+
+func isClauseVariable(name string) bool {
+	if len(name) < 2 || name[0] != 'x' {
+		return false
+	}
+	for _, r := range name[1:] {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Proof steps whose statements are clauses of the resolution prover
+var clauseRules = map[string]bool{"clausification": true, "resolution": true, "factoring": true}
+
+// proofFormulas formalizes the statements of a proof. Clause steps are read
+// as universally closed clauses; the other statements are formalized together
+// with the theorem. The returned formulas are indexed by step, followed by the
+// conclusion.
+func proofFormulas(theorem *SymbolicTheorem, proof *TheoremProof) ([]*Formula, *Formula, error) {
+	statements := []string{theorem.Conclusion}
+	index := make([]int, len(proof.Steps))
+	formulas := make([]*Formula, len(proof.Steps))
+	for i, step := range proof.Steps {
+		index[i] = -1
+		if clauseRules[step.Rule] || step.Rule == "negated_conclusion" {
+			clause, err := ParseFormula(step.Statement)
+			if err != nil {
+				return nil, nil, fmt.Errorf("step %d: %w", step.StepNumber, err)
+			}
+			if step.Rule == "negated_conclusion" {
+				formulas[i] = clause
+				index[i] = -2
+				continue
+			}
+			formulas[i] = universalClosure(bindClause(clause, clauseVariables(clause)), clauseVariables(clause))
+			continue
+		}
+		index[i] = len(statements)
+		statements = append(statements, step.Statement)
+	}
+
+	formalized, err := formalizeStatements(statements)
+	if err != nil {
+		return nil, nil, err
+	}
+	conclusion := formalized[0]
+	for i := range proof.Steps {
+		switch index[i] {
+		case -1:
+		case -2:
+			// The negated conclusion is stated in the prover's notation; use
+			// the formalized conclusion so the vocabulary matches
+			formulas[i] = &Formula{Op: FormulaNot, Left: conclusion}
+		default:
+			formulas[i] = formalized[index[i]]
+		}
+	}
+	return formulas, conclusion, nil
+}
+
+// bindClause marks the clause variables of a parsed clause
+func bindClause(f *Formula, variables []string) *Formula {
+	bound := make(map[string]bool, len(variables))
+	for _, name := range variables {
+		bound[name] = true
+	}
+	var rebind func(f *Formula) *Formula
+	rebind = func(f *Formula) *Formula {
+		switch f.Op {
+		case FormulaAtom:
+			return &Formula{Op: FormulaAtom, Name: f.Name, Args: bindTerms(f.Args, bound)}
+		case FormulaTrue, FormulaFalse:
+			return f
+		case FormulaNot, FormulaForAll, FormulaExists:
+			return &Formula{Op: f.Op, Var: f.Var, Left: rebind(f.Left)}
+		}
+		return &Formula{Op: f.Op, Left: rebind(f.Left), Right: rebind(f.Right)}
+	}
+	return rebind(f)
+}
+
+// linearExport is a constraint set prepared for export: the rows of each
+// linear constraint, the domains of their symbols and the constraints left out
+type linearExport struct {
+	constraints []*SymbolicConstraint
+	rows        [][]linearRow
+	integers    map[string]bool
+	symbols     []string
+	skipped     []*SymbolicConstraint
+}
+
+// prepareLinearExport parses the constraints in order; those outside linear
+// arithmetic are skipped
+func (sr *SymbolicReasoner) prepareLinearExport(constraintIDs []string) (*linearExport, error) {
+	export := &linearExport{integers: map[string]bool{}}
+	seen := map[string]bool{}
+	for _, id := range constraintIDs {
+		c, ok := sr.constraints[id]
+		if !ok {
+			return nil, fmt.Errorf("constraint %s not found", id)
+		}
+		rows, err := sr.linearRows(c, export.integers)
+		if err != nil {
+			export.skipped = append(export.skipped, c)
+			continue
+		}
+		export.constraints = append(export.constraints, c)
+		export.rows = append(export.rows, rows)
+		for _, row := range rows {
+			for name := range row.coeffs {
+				if !seen[name] {
+					seen[name] = true
+					export.symbols = append(export.symbols, name)
+				}
+			}
+		}
+	}
+	sort.Strings(export.symbols)
+	return export, nil
+}
+
+// exportRow scales a row for export. Rows over integer symbols only are
+// tightened to integer coefficients and bounds; false reports a row with no
+// integer solution. Other rows keep rational coefficients.
+func exportRow(row linearRow, integers map[string]bool) (linearRow, bool) {
+	if len(row.coeffs) > 0 && allIntegers(row.coeffs, integers) {
+		tight, core := tightenIntegerRows([]linearRow{row}, integers)
+		if core != nil {
+			return row, false
+		}
+		return tight[0], true
+	}
+	return row, true
+}
+
+// orientRow rewrites a row whose coefficients are all negative as
+// Σ -aᵢxᵢ ⋈ -b with the comparison reversed, so "x >= 6" is not written as
+// "-x <= -6"; true reports the reversal
+func orientRow(row linearRow) (linearRow, bool) {
+	if row.op == linearEQ || len(row.coeffs) == 0 {
+		return row, false
+	}
+	for _, a := range row.coeffs {
+		if a.Sign() > 0 {
+			return row, false
+		}
+	}
+	flipped := linearRow{coeffs: make(map[string]*big.Rat, len(row.coeffs)), op: row.op, bound: new(big.Rat).Neg(row.bound), source: row.source}
+	for name, a := range row.coeffs {
+		flipped.coeffs[name] = new(big.Rat).Neg(a)
+	}
+	return flipped, true
+}
+
+// ratParts splits a rational into its sign and absolute value
+func ratParts(r *big.Rat) (bool, *big.Rat) {
+	return r.Sign() < 0, new(big.Rat).Abs(r)
+}
@@ -0,0 +1,334 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTPTPTheorem(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem string
+		want    bool
+	}{
+		{"fof conjecture", `
+% File     : SOC001
+% Status   : Theorem
+fof(mortality, axiom, ! [X] : (human(X) => mortal(X))).
+fof(socrates, axiom, human(socrates)).
+fof(goal, conjecture, ? [Y] : mortal(Y)).`, true},
+		{"cnf refutation", `
+% Status   : Unsatisfiable
+cnf(c1, axiom, p(X) | q(X)).
+cnf(c2, axiom, ~ p(a)).
+cnf(c3, negated_conjecture, ~ q(a)).`, true},
+		{"contradictory axioms", `
+fof(a1, axiom, ! [X] : (p(X) <=> ~ q(X))).
+fof(a2, axiom, p(b) & q(b)).`, true},
+		{"equality is uninterpreted", `
+fof(a1, axiom, f(a) = b).
+fof(a2, axiom, p(b)).
+fof(goal, conjecture, p(f(a))).`, false},
+		{"connectives", `
+fof(a1, axiom, (p <~> q) & (r <= p) & ~ (q ~| r)).
+fof(goal, conjecture, r).`, true},
+		{"countersatisfiable", `
+% Status   : CounterSatisfiable
+fof(a1, axiom, ! [X] : (cat(X) => mammal(X))).
+fof(a2, axiom, ! [X] : (dog(X) => mammal(X))).
+fof(goal, conjecture, ! [X] : (cat(X) => dog(X))).`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imported, err := ParseTPTPTheorem(tt.problem)
+			if err != nil {
+				t.Fatalf("ParseTPTPTheorem: %v", err)
+			}
+			if expected, known := imported.ExpectsProof(); known && expected != tt.want {
+				t.Errorf("status %q expects %v", imported.Status, expected)
+			}
+			proof, err := NewSymbolicReasoner().ProveTheorem(imported.Theorem)
+			if err != nil {
+				t.Fatalf("ProveTheorem: %v", err)
+			}
+			if proof.IsValid != tt.want {
+				t.Errorf("IsValid = %v for %+v: %s", proof.IsValid, imported.Theorem, proof.Explanation)
+			}
+		})
+	}
+
+	imported, _ := ParseTPTPTheorem(tests[0].problem)
+	if imported.Theorem.Name != "SOC001" || imported.Status != "Theorem" || imported.Theorem.Conclusion != "∃Y mortal(Y)" {
+		t.Errorf("imported = %+v, status %q", imported.Theorem, imported.Status)
+	}
+
+	for problem, want := range map[string]string{
+		"include('Axioms/SET001-0.ax').":                      "include directives are not supported",
+		"fof(a, axiom, p(X)":                                  "expected",
+		"tff(t, type, x: $int).\ntff(a, axiom, $less(x, 1)).": "check-constraints",
+		"fof(a, axiom, $less(1, 2)).":                         "not supported",
+	} {
+		if _, err := ParseTPTPTheorem(problem); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseTPTPTheorem(%q) error = %v, want %q", problem, err, want)
+		}
+	}
+}
+
+func TestParseSMTLIBTheorem(t *testing.T) {
+	script := `
+(set-logic UF)
+(set-info :status unsat)
+(declare-sort Person 0)
+(declare-fun human (Person) Bool)
+(declare-fun mortal (Person) Bool)
+(declare-const socrates Person)
+(define-fun mortality () Bool (forall ((x Person)) (=> (human x) (mortal x))))
+(assert (! mortality :named policy))
+(assert (human socrates))
+(assert (let ((m (mortal socrates))) (not m)))
+(check-sat)`
+	imported, err := ParseSMTLIBTheorem(script)
+	if err != nil {
+		t.Fatalf("ParseSMTLIBTheorem: %v", err)
+	}
+	theorem := imported.Theorem
+	if strings.Join(theorem.Premises, "; ") != "∀x (human(x) → mortal(x)); human(socrates)" || theorem.Conclusion != "mortal(socrates)" {
+		t.Errorf("theorem = %+v", theorem)
+	}
+	if expected, known := imported.ExpectsProof(); !expected || !known {
+		t.Errorf("status %q", imported.Status)
+	}
+	if proof, err := NewSymbolicReasoner().ProveTheorem(theorem); err != nil || !proof.IsValid {
+		t.Errorf("proof = %+v, err %v", proof, err)
+	}
+
+	// Equivalence of formulas, equality of individuals and ite
+	imported, err = ParseSMTLIBTheorem(`
+(declare-sort U 0)
+(declare-const c U)
+(declare-const d U)
+(declare-fun p () Bool)
+(declare-fun q (U) Bool)
+(assert (= p (q c)))
+(assert (ite p (distinct c d) (q d)))
+(assert (not (or (q d) (not (= c d)))))`)
+	if err != nil {
+		t.Fatalf("ParseSMTLIBTheorem: %v", err)
+	}
+	if got := imported.Theorem.Premises[0]; got != "p ↔ q(c)" {
+		t.Errorf("premise = %s", got)
+	}
+	if got := imported.Theorem.Conclusion; got != "q(d) ∨ ¬equal(c, d)" {
+		t.Errorf("conclusion = %s", got)
+	}
+
+	for script, want := range map[string]string{
+		"(set-logic QF_LIA)(declare-const x Int)(assert (< x 1))": "check-constraints",
+		"(declare-const p Bool)(push 1)(assert p)":                "push is not supported",
+		"(declare-const p Bool)":                                  "no assertions",
+		"(assert (p)":                                             "unbalanced",
+	} {
+		if _, err := ParseSMTLIBTheorem(script); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseSMTLIBTheorem(%q) error = %v, want %q", script, err, want)
+		}
+	}
+}
+
+func TestExportTheorem_RoundTrip(t *testing.T) {
+	formats := map[string]struct {
+		export func(*SymbolicTheorem) (string, error)
+		parse  func(string) (*ImportedTheorem, error)
+	}{
+		"tptp":   {ExportTheoremTPTP, ParseTPTPTheorem},
+		"smtlib": {ExportTheoremSMTLIB, ParseSMTLIBTheorem},
+	}
+	for name, format := range formats {
+		t.Run(name, func(t *testing.T) {
+			theorem := &SymbolicTheorem{
+				Name: "TLS policy",
+				Premises: []string{
+					"Every service that calls auth must use TLS",
+					"Payments is a service",
+					"Payments calls auth",
+				},
+				Conclusion: "Payments uses TLS",
+			}
+			if _, err := NewSymbolicReasoner().ProveTheorem(theorem); err != nil {
+				t.Fatalf("ProveTheorem: %v", err)
+			}
+			exported, err := format.export(theorem)
+			if err != nil {
+				t.Fatalf("export: %v", err)
+			}
+			imported, err := format.parse(exported)
+			if err != nil {
+				t.Fatalf("parse: %v\n%s", err, exported)
+			}
+			if expected, known := imported.ExpectsProof(); !expected || !known {
+				t.Errorf("status %q in\n%s", imported.Status, exported)
+			}
+			proof, err := NewSymbolicReasoner().ProveTheorem(imported.Theorem)
+			if err != nil || !proof.IsValid {
+				t.Errorf("proof of %+v = %+v, err %v", imported.Theorem, proof, err)
+			}
+		})
+	}
+
+	exported, _ := ExportTheoremTPTP(&SymbolicTheorem{Premises: []string{"All men are mortal", "Socrates is a man"}, Conclusion: "Socrates is mortal"})
+	want := "fof(premise_1, axiom, ! [V1] : (man(V1) => mortal(V1))).\n" +
+		"fof(premise_2, axiom, man(socrates)).\n" +
+		"fof(conclusion, conjecture, mortal(socrates)).\n"
+	if exported != want {
+		t.Errorf("ExportTheoremTPTP =\n%s\nwant\n%s", exported, want)
+	}
+}
+
+func TestExportProof(t *testing.T) {
+	theorem := &SymbolicTheorem{
+		Name:       "barbara",
+		Premises:   []string{"All men are mortal", "Socrates is a man"},
+		Conclusion: "Socrates is mortal",
+	}
+	proof, err := NewSymbolicReasoner().ProveTheorem(theorem)
+	if err != nil || !proof.IsValid {
+		t.Fatalf("proof = %+v, err %v", proof, err)
+	}
+
+	tstp, err := ExportProofTPTP(theorem, proof)
+	if err != nil {
+		t.Fatalf("ExportProofTPTP: %v", err)
+	}
+	for _, line := range []string{
+		"% SZS status Theorem for barbara",
+		"fof(3, negated_conjecture, ~ mortal(socrates), inference(negate_conjecture, [status(cth)], [conclusion])).",
+		"cnf(4, plain, mortal(X1) | ~ man(X1), inference(clausification, [status(esa)], [1])).",
+		"cnf(8, plain, $false, inference(resolution, [status(thm)], [5,7])).",
+		"% SZS output end Proof for barbara",
+	} {
+		if !strings.Contains(tstp, line) {
+			t.Errorf("TSTP missing %q:\n%s", line, tstp)
+		}
+	}
+
+	script, err := ExportProofSMTLIB(theorem, proof)
+	if err != nil {
+		t.Fatalf("ExportProofSMTLIB: %v", err)
+	}
+	check := "(push 1)\n(assert (forall ((x1 U)) (or (mortal x1) (not (man x1)))))\n(assert (not (mortal socrates)))\n" +
+		"(assert (not (not (man socrates))))\n(check-sat) ; expect unsat\n(pop 1)\n"
+	if !strings.Contains(script, check) || strings.Count(script, "(check-sat)") != 6 {
+		t.Errorf("script:\n%s", script)
+	}
+}
+
+func TestConstraintInterchange(t *testing.T) {
+	script := `
+(set-logic QF_LIA)
+(set-info :status unsat)
+(declare-const x Int)
+(declare-const y Int)
+(assert (! (<= 0 x 10) :named bounds))
+(assert (! (> (+ x (* 2 y)) 30) :named demand))
+(assert (not (>= y 10)))
+(check-sat)`
+	problem, err := ParseSMTLIBConstraints(script)
+	if err != nil {
+		t.Fatalf("ParseSMTLIBConstraints: %v", err)
+	}
+	if problem.Status != "unsat" || len(problem.Symbols) != 2 || problem.Symbols[0].Domain != "integer" {
+		t.Errorf("problem = %+v", problem)
+	}
+	expressions := []string{"0 <= x and x <= 10", "(x + (2 * y)) > 30", "y < 10"}
+	for i, c := range problem.Constraints {
+		if c.Expression != expressions[i] {
+			t.Errorf("constraint %d = %q, want %q", i, c.Expression, expressions[i])
+		}
+	}
+	if problem.Constraints[0].ID != "bounds" || problem.Constraints[2].ID != "" {
+		t.Errorf("IDs = %q, %q", problem.Constraints[0].ID, problem.Constraints[2].ID)
+	}
+
+	sr := NewSymbolicReasoner()
+	for _, symbol := range problem.Symbols {
+		sr.AddSymbol(symbol.Name, symbol.Type, symbol.Domain)
+	}
+	var ids []string
+	for _, c := range problem.Constraints {
+		added, err := sr.AddConstraint(c.Type, c.Expression, c.Symbols)
+		if err != nil {
+			t.Fatalf("AddConstraint: %v", err)
+		}
+		ids = append(ids, added.ID)
+	}
+	result, err := sr.CheckConstraintConsistency(ids)
+	if err != nil || result.IsConsistent {
+		t.Fatalf("result = %+v, err %v", result, err)
+	}
+
+	exported, err := sr.ExportConstraintsSMTLIB(ids, result)
+	if err != nil {
+		t.Fatalf("ExportConstraintsSMTLIB: %v", err)
+	}
+	for _, line := range []string{
+		"(set-logic QF_LIA)",
+		"(set-info :status unsat)",
+		"(assert (! (and (>= x 0) (<= x 10)) :named constraint-1))",
+		"(assert (! (>= (+ x (* 2 y)) 31) :named constraint-2))",
+		"(get-unsat-core)",
+	} {
+		if !strings.Contains(exported, line) {
+			t.Errorf("SMT-LIB missing %q:\n%s", line, exported)
+		}
+	}
+	reimported, err := ParseSMTLIBConstraints(exported)
+	if err != nil || reimported.Status != "unsat" || len(reimported.Constraints) != 3 || reimported.Constraints[1].ID != "constraint-2" {
+		t.Errorf("reimported = %+v, err %v", reimported, err)
+	}
+
+	tff, err := sr.ExportConstraintsTPTP(ids, result)
+	if err != nil {
+		t.Fatalf("ExportConstraintsTPTP: %v", err)
+	}
+	if !strings.Contains(tff, "tff(x_type, type, x: $int).") || !strings.Contains(tff, "tff(constraint_3, axiom, $lesseq(y,9)).") {
+		t.Errorf("TFF:\n%s", tff)
+	}
+	reimported, err = ParseTPTPConstraints(tff)
+	if err != nil || reimported.Status != "unsat" || len(reimported.Constraints) != 3 {
+		t.Errorf("reimported = %+v, err %v", reimported, err)
+	}
+
+	if _, err := sr.ExportConstraintsSMTLIB([]string{"constraint-9"}, nil); err == nil {
+		t.Error("exported an unknown constraint")
+	}
+
+	for script, want := range map[string]string{
+		"(declare-const x Real)(assert (=))":       "= takes at least two arguments",
+		"(declare-const x Real)(assert (not (=)))": "= takes at least two arguments",
+		"(declare-const b Bool)(assert (= b b))":   "equivalence between Booleans",
+	} {
+		if _, err := ParseSMTLIBConstraints(script); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseSMTLIBConstraints(%q) error = %v, want %q", script, err, want)
+		}
+	}
+}
+
+func TestParseTPTPConstraints(t *testing.T) {
+	problem, err := ParseTPTPConstraints(`
+tff(n_type, type, n: $int).
+tff(r_type, type, r: $real).
+tff(range, axiom, $greatereq(n, 1) & $less(n, 5)).
+tff(ratio, hypothesis, r = $quotient($to_real(n), 2.0)).
+tff(goal, conjecture, $lesseq(r, 2.0)).`)
+	if err != nil {
+		t.Fatalf("ParseTPTPConstraints: %v", err)
+	}
+	want := []string{"n >= 1 and n < 5", "r = (n / 2.0)", "r > 2.0"}
+	for i, c := range problem.Constraints {
+		if c.Expression != want[i] {
+			t.Errorf("constraint %s = %q, want %q", c.ID, c.Expression, want[i])
+		}
+	}
+	if problem.Symbols[1].Domain != "real" || problem.Constraints[2].Type != ConstraintInequality {
+		t.Errorf("problem = %+v", problem)
+	}
+}
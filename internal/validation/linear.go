@@ -0,0 +1,957 @@
+package validation
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Linear arithmetic over the numeric symbols of a constraint set.
+//
+// Constraint expressions that are conjunctions of linear comparisons
+// ("2x + y <= 10", "0 <= x < y", "x in [1, 5]") become rows Σ aᵢxᵢ ⋈ b with
+// exact rational coefficients. Satisfiability is decided by bounds
+// propagation followed by Fourier–Motzkin elimination; integer symbols are
+// handled by tightening rows to integer bounds and branching on fractional
+// values. Every derived bound and inequality remembers which constraints it
+// came from, so a contradiction names the constraints responsible.
+
+const (
+	// maxLinearInequalities bounds the inequalities Fourier–Motzkin
+	// elimination may hold at once before the problem is left undecided
+	maxLinearInequalities = 4000
+
+	// maxBranchNodes bounds the branch and bound search over integer symbols
+	maxBranchNodes = 256
+
+	// maxPropagationRounds bounds bounds propagation, which need not reach a
+	// fixpoint over the reals
+	maxPropagationRounds = 32
+)
+
+// linearOp is the comparison of a row against its bound
+type linearOp int
+
+const (
+	linearLE linearOp = iota // Σ aᵢxᵢ <= b
+	linearLT                 // Σ aᵢxᵢ < b
+	linearEQ                 // Σ aᵢxᵢ = b
+)
+
+// linearOutcome is the verdict of the solver
+type linearOutcome int
+
+const (
+	linearUnknown linearOutcome = iota
+	linearSat
+	linearUnsat
+)
+
+// linearRow is one linear comparison Σ aᵢxᵢ ⋈ b taken from the constraint
+// with index source; branching bounds have source -1
+type linearRow struct {
+	coeffs map[string]*big.Rat
+	op     linearOp
+	bound  *big.Rat
+	source int
+}
+
+// linearExpr is Σ aᵢxᵢ + c
+type linearExpr struct {
+	coeffs   map[string]*big.Rat
+	constant *big.Rat
+}
+
+func constantExpr(value *big.Rat) linearExpr {
+	return linearExpr{coeffs: map[string]*big.Rat{}, constant: value}
+}
+
+func (e linearExpr) isConstant() bool {
+	return len(e.coeffs) == 0
+}
+
+// plus returns e + k·other
+func (e linearExpr) plus(other linearExpr, k *big.Rat) linearExpr {
+	result := e.scale(big.NewRat(1, 1))
+	for name, a := range other.coeffs {
+		sum := new(big.Rat).Mul(a, k)
+		if current, ok := result.coeffs[name]; ok {
+			sum.Add(sum, current)
+		}
+		if sum.Sign() == 0 {
+			delete(result.coeffs, name)
+		} else {
+			result.coeffs[name] = sum
+		}
+	}
+	result.constant.Add(result.constant, new(big.Rat).Mul(other.constant, k))
+	return result
+}
+
+// scale returns k·e
+func (e linearExpr) scale(k *big.Rat) linearExpr {
+	result := constantExpr(new(big.Rat).Mul(e.constant, k))
+	if k.Sign() == 0 {
+		return result
+	}
+	for name, a := range e.coeffs {
+		result.coeffs[name] = new(big.Rat).Mul(a, k)
+	}
+	return result
+}
+
+var (
+	linearRangePattern   = regexp.MustCompile(`(?i)^(.+?)\s+(?:in|∈)\s*([\[(])\s*(.+?)\s*,\s*(.+?)\s*([\])])$`)
+	linearBetweenPattern = regexp.MustCompile(`(?i)^(.+?)\s+between\s+(.+?)\s+and\s+(.+)$`)
+	linearAndPattern     = regexp.MustCompile(`(?i)\s+and\s+|&&|∧`)
+)
+
+// parseLinearConstraint parses a conjunction of linear comparisons. Chained
+// comparisons ("0 <= x < 10"), closed or open ranges ("x in [0, 10)") and
+// "x between 0 and 10" are accepted. Disequalities, products of symbols and
+// anything else that is not a conjunction of linear comparisons is an error.
+func parseLinearConstraint(expression string) ([]linearRow, error) {
+	expression = strings.TrimSpace(expression)
+	if m := linearBetweenPattern.FindStringSubmatch(expression); m != nil {
+		expression = fmt.Sprintf("%s <= %s <= %s", m[2], m[1], m[3])
+	}
+
+	var rows []linearRow
+	for _, part := range linearAndPattern.Split(expression, -1) {
+		part = strings.TrimSpace(part)
+		if m := linearRangePattern.FindStringSubmatch(part); m != nil {
+			lower, upper := "<=", "<="
+			if m[2] == "(" {
+				lower = "<"
+			}
+			if m[5] == ")" {
+				upper = "<"
+			}
+			part = fmt.Sprintf("%s %s %s %s %s", m[3], lower, m[1], upper, m[4])
+		}
+		partRows, err := parseLinearComparison(part)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, partRows...)
+	}
+	return rows, nil
+}
+
+// parseLinearComparison parses a possibly chained comparison of linear
+// expressions
+func parseLinearComparison(text string) ([]linearRow, error) {
+	tokens, err := tokenizeLinear(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var sides [][]linearToken
+	var ops []string
+	start := 0
+	for i, tok := range tokens {
+		if tok.kind == linearComparison {
+			sides = append(sides, tokens[start:i])
+			ops = append(ops, tok.text)
+			start = i + 1
+		}
+	}
+	sides = append(sides, tokens[start:])
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("%q is not a comparison", text)
+	}
+
+	exprs := make([]linearExpr, len(sides))
+	for i, side := range sides {
+		p := &linearParser{tokens: side}
+		if exprs[i], err = p.parse(); err != nil {
+			return nil, fmt.Errorf("%q: %w", text, err)
+		}
+	}
+
+	rows := make([]linearRow, 0, len(ops))
+	for i, op := range ops {
+		// left - right ⋈ 0, or right - left ⋈ 0 for > and >=
+		left, right := exprs[i], exprs[i+1]
+		var row linearRow
+		switch op {
+		case "<=":
+			row.op = linearLE
+		case "<":
+			row.op = linearLT
+		case ">=":
+			row.op = linearLE
+			left, right = right, left
+		case ">":
+			row.op = linearLT
+			left, right = right, left
+		case "=", "==":
+			row.op = linearEQ
+		default:
+			return nil, fmt.Errorf("%q: %s is a disjunction, not a linear comparison", text, op)
+		}
+		diff := left.plus(right, big.NewRat(-1, 1))
+		row.coeffs = diff.coeffs
+		row.bound = diff.constant.Neg(diff.constant)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+type linearTokenKind int
+
+const (
+	linearNumber linearTokenKind = iota
+	linearIdent
+	linearOperator
+	linearComparison
+)
+
+type linearToken struct {
+	kind linearTokenKind
+	text string
+}
+
+// tokenizeLinear splits a comparison into numbers, identifiers, arithmetic
+// operators and comparison operators
+func tokenizeLinear(text string) ([]linearToken, error) {
+	replacer := strings.NewReplacer("≤", "<=", "≥", ">=", "≠", "!=", "−", "-", "·", "*", "×", "*")
+	runes := []rune(replacer.Replace(text))
+
+	var tokens []linearToken
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, linearToken{linearNumber, string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, linearToken{linearIdent, string(runes[i:j])})
+			i = j
+		case strings.ContainsRune("<>=!", r):
+			j := i + 1
+			if j < len(runes) && runes[j] == '=' {
+				j++
+			}
+			op := string(runes[i:j])
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected character '!' in %q", text)
+			}
+			tokens = append(tokens, linearToken{linearComparison, op})
+			i = j
+		case strings.ContainsRune("+-*/()", r):
+			tokens = append(tokens, linearToken{linearOperator, string(r)})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q in %q", r, text)
+		}
+	}
+	return tokens, nil
+}
+
+// linearParser parses one side of a comparison:
+//
+//	expr   = ["+"|"-"] term {("+"|"-") term}
+//	term   = factor {["*"|"/"] factor}
+//	factor = "-" factor | number | identifier | "(" expr ")"
+//
+// A product needs a constant operand and a divisor must be a nonzero
+// constant, so every expression stays linear.
+type linearParser struct {
+	tokens []linearToken
+	pos    int
+}
+
+func (p *linearParser) parse() (linearExpr, error) {
+	if len(p.tokens) == 0 {
+		return linearExpr{}, fmt.Errorf("missing expression")
+	}
+	e, err := p.expr()
+	if err != nil {
+		return linearExpr{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return linearExpr{}, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return e, nil
+}
+
+func (p *linearParser) peek(text string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == linearOperator && p.tokens[p.pos].text == text
+}
+
+func (p *linearParser) expr() (linearExpr, error) {
+	e, err := p.term()
+	if err != nil {
+		return e, err
+	}
+	for p.peek("+") || p.peek("-") {
+		sign := big.NewRat(1, 1)
+		if p.tokens[p.pos].text == "-" {
+			sign.SetInt64(-1)
+		}
+		p.pos++
+		t, err := p.term()
+		if err != nil {
+			return e, err
+		}
+		e = e.plus(t, sign)
+	}
+	return e, nil
+}
+
+func (p *linearParser) term() (linearExpr, error) {
+	e, err := p.factor()
+	if err != nil {
+		return e, err
+	}
+	for p.pos < len(p.tokens) {
+		divide := p.peek("/")
+		switch {
+		case p.peek("*") || divide:
+			p.pos++
+		case p.peek("(") || p.tokens[p.pos].kind == linearIdent || p.tokens[p.pos].kind == linearNumber:
+			// Implicit product, as in 2x or 3(x + y)
+		default:
+			return e, nil
+		}
+		f, err := p.factor()
+		if err != nil {
+			return e, err
+		}
+		switch {
+		case divide && (!f.isConstant() || f.constant.Sign() == 0):
+			return e, fmt.Errorf("divisor must be a nonzero constant")
+		case divide:
+			e = e.scale(new(big.Rat).Inv(f.constant))
+		case e.isConstant():
+			e = f.scale(e.constant)
+		case f.isConstant():
+			e = e.scale(f.constant)
+		default:
+			return e, fmt.Errorf("product of symbols is not linear")
+		}
+	}
+	return e, nil
+}
+
+func (p *linearParser) factor() (linearExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return linearExpr{}, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch {
+	case tok.kind == linearNumber:
+		value, ok := new(big.Rat).SetString(tok.text)
+		if !ok {
+			return linearExpr{}, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return constantExpr(value), nil
+	case tok.kind == linearIdent:
+		e := constantExpr(new(big.Rat))
+		e.coeffs[tok.text] = big.NewRat(1, 1)
+		return e, nil
+	case tok.text == "-" || tok.text == "+":
+		f, err := p.factor()
+		if err != nil || tok.text == "+" {
+			return f, err
+		}
+		return f.scale(big.NewRat(-1, 1)), nil
+	case tok.text == "(":
+		e, err := p.expr()
+		if err != nil {
+			return e, err
+		}
+		if !p.peek(")") {
+			return e, fmt.Errorf("expected \")\"")
+		}
+		p.pos++
+		return e, nil
+	}
+	return linearExpr{}, fmt.Errorf("unexpected %q", tok.text)
+}
+
+// solveLinear decides a conjunction of rows, where symbols in integers take
+// integer values. A satisfiable problem comes with a witness assignment; an
+// unsatisfiable one with the sources of the rows used in the refutation.
+func solveLinear(rows []linearRow, integers map[string]bool) (linearOutcome, map[string]*big.Rat, []int) {
+	nodes := 0
+	outcome, witness, core := branchAndBound(rows, integers, &nodes)
+	if outcome == linearSat && !satisfiesRows(rows, witness) {
+		return linearUnknown, nil, nil
+	}
+	sources := make([]int, 0, len(core))
+	for _, source := range core {
+		if source >= 0 {
+			sources = append(sources, source)
+		}
+	}
+	return outcome, witness, sources
+}
+
+// minimalLinearCore shrinks an unsatisfiable set of sources until dropping
+// any one of them makes the remaining rows satisfiable (or undecided)
+func minimalLinearCore(rows []linearRow, integers map[string]bool, core []int) []int {
+	core = append([]int(nil), core...)
+	for i := 0; i < len(core); {
+		trial := append(append([]int(nil), core[:i]...), core[i+1:]...)
+		keep := make(map[int]bool, len(trial))
+		for _, source := range trial {
+			keep[source] = true
+		}
+		subset := make([]linearRow, 0, len(rows))
+		for _, row := range rows {
+			if keep[row.source] {
+				subset = append(subset, row)
+			}
+		}
+		if outcome, _, _ := solveLinear(subset, integers); outcome == linearUnsat {
+			core = trial
+		} else {
+			i++
+		}
+	}
+	return core
+}
+
+func branchAndBound(rows []linearRow, integers map[string]bool, nodes *int) (linearOutcome, map[string]*big.Rat, []int) {
+	*nodes++
+	if *nodes > maxBranchNodes {
+		return linearUnknown, nil, nil
+	}
+
+	rows, core := tightenIntegerRows(rows, integers)
+	if core != nil {
+		return linearUnsat, nil, core
+	}
+	if core := propagateBounds(rows, integers); core != nil {
+		return linearUnsat, nil, core
+	}
+	outcome, witness, core, branch, value := fourierMotzkin(rows, integers)
+	if outcome != linearSat || branch == "" {
+		return outcome, witness, core
+	}
+
+	// The relaxation puts branch strictly between two integers; split there
+	floor := ratFloor(value)
+	down := append(append([]linearRow(nil), rows...), linearRow{
+		coeffs: map[string]*big.Rat{branch: big.NewRat(1, 1)},
+		op:     linearLE,
+		bound:  floor,
+		source: -1,
+	})
+	up := append(append([]linearRow(nil), rows...), linearRow{
+		coeffs: map[string]*big.Rat{branch: big.NewRat(-1, 1)},
+		op:     linearLE,
+		bound:  new(big.Rat).Neg(new(big.Rat).Add(floor, big.NewRat(1, 1))),
+		source: -1,
+	})
+	downOutcome, witness, downCore := branchAndBound(down, integers, nodes)
+	if downOutcome == linearSat {
+		return linearSat, witness, nil
+	}
+	upOutcome, witness, upCore := branchAndBound(up, integers, nodes)
+	if upOutcome == linearSat {
+		return linearSat, witness, nil
+	}
+	if downOutcome == linearUnsat && upOutcome == linearUnsat {
+		return linearUnsat, nil, unionSources(downCore, upCore)
+	}
+	return linearUnknown, nil, nil
+}
+
+// tightenIntegerRows rewrites rows over integer symbols only as Σ aᵢxᵢ <= b
+// with coprime integer coefficients and an integer bound, so strict
+// inequalities disappear and the relaxation loses no integer solutions. An
+// equality whose bound is not a multiple of the coefficients' gcd has no
+// integer solution; its source is returned as the core.
+func tightenIntegerRows(rows []linearRow, integers map[string]bool) ([]linearRow, []int) {
+	result := make([]linearRow, 0, len(rows))
+	for _, row := range rows {
+		if len(row.coeffs) == 0 || !allIntegers(row.coeffs, integers) {
+			result = append(result, row)
+			continue
+		}
+
+		// Scale to integer coefficients, then divide by their gcd
+		denominators := big.NewInt(1)
+		for _, a := range row.coeffs {
+			denominators = lcm(denominators, a.Denom())
+		}
+		gcd := new(big.Int)
+		for _, a := range row.coeffs {
+			numerator := new(big.Int).Mul(a.Num(), new(big.Int).Quo(denominators, a.Denom()))
+			gcd.GCD(nil, nil, gcd, numerator.Abs(numerator))
+		}
+		k := new(big.Rat).SetFrac(denominators, gcd)
+
+		tight := linearRow{coeffs: make(map[string]*big.Rat, len(row.coeffs)), op: linearLE, source: row.source}
+		for name, a := range row.coeffs {
+			tight.coeffs[name] = new(big.Rat).Mul(a, k)
+		}
+		bound := new(big.Rat).Mul(row.bound, k)
+		switch row.op {
+		case linearLE:
+			tight.bound = ratFloor(bound)
+		case linearLT:
+			tight.bound = new(big.Rat).Sub(ratCeil(bound), big.NewRat(1, 1))
+		case linearEQ:
+			if !bound.IsInt() {
+				return nil, []int{row.source}
+			}
+			tight.op = linearEQ
+			tight.bound = bound
+		}
+		result = append(result, tight)
+	}
+	return result, nil
+}
+
+// linearBound is a bound on one symbol together with the sources of the rows
+// it was derived from
+type linearBound struct {
+	value   *big.Rat
+	strict  bool
+	sources []int
+}
+
+// propagateBounds derives bounds on each symbol from the bounds on the
+// others, rounding bounds on integer symbols. When a symbol's lower bound
+// passes its upper bound the union of their sources is returned.
+func propagateBounds(rows []linearRow, integers map[string]bool) []int {
+	// Each row as one or two inequalities Σ aᵢxᵢ <= b
+	var forms []fmInequality
+	for _, row := range rows {
+		forms = append(forms, inequalitiesOf(row)...)
+	}
+
+	lower := map[string]*linearBound{}
+	upper := map[string]*linearBound{}
+	for round := 0; round < maxPropagationRounds; round++ {
+		changed := false
+		for _, form := range forms {
+			for name, a := range form.coeffs {
+				// a·x <= b - Σ(others), bounded by the others' extremes
+				rest := new(big.Rat).Set(form.bound)
+				strict := form.strict
+				sources := form.sources
+				bounded := true
+				for other, c := range form.coeffs {
+					if other == name {
+						continue
+					}
+					extreme := lower[other]
+					if c.Sign() < 0 {
+						extreme = upper[other]
+					}
+					if extreme == nil {
+						bounded = false
+						break
+					}
+					rest.Sub(rest, new(big.Rat).Mul(c, extreme.value))
+					strict = strict || extreme.strict
+					sources = unionSources(sources, extreme.sources)
+				}
+				if !bounded {
+					continue
+				}
+
+				candidate := &linearBound{value: rest.Quo(rest, a), strict: strict, sources: sources}
+				isUpper := a.Sign() > 0
+				if integers[name] {
+					roundBound(candidate, isUpper)
+				}
+				if isUpper && tighter(candidate, upper[name], true) {
+					upper[name] = candidate
+					changed = true
+				} else if !isUpper && tighter(candidate, lower[name], false) {
+					lower[name] = candidate
+					changed = true
+				} else {
+					continue
+				}
+
+				if lo, hi := lower[name], upper[name]; lo != nil && hi != nil {
+					if cmp := lo.value.Cmp(hi.value); cmp > 0 || (cmp == 0 && (lo.strict || hi.strict)) {
+						return unionSources(lo.sources, hi.sources)
+					}
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return nil
+}
+
+// roundBound rounds a bound on an integer symbol to the nearest integer
+// inside it
+func roundBound(b *linearBound, isUpper bool) {
+	switch {
+	case b.value.IsInt() && b.strict && isUpper:
+		b.value.Sub(b.value, big.NewRat(1, 1))
+	case b.value.IsInt() && b.strict:
+		b.value.Add(b.value, big.NewRat(1, 1))
+	case isUpper:
+		b.value = ratFloor(b.value)
+	default:
+		b.value = ratCeil(b.value)
+	}
+	b.strict = false
+}
+
+// tighter reports whether candidate improves on current
+func tighter(candidate, current *linearBound, isUpper bool) bool {
+	if current == nil {
+		return true
+	}
+	cmp := candidate.value.Cmp(current.value)
+	if !isUpper {
+		cmp = -cmp
+	}
+	return cmp < 0 || (cmp == 0 && candidate.strict && !current.strict)
+}
+
+// fmInequality is Σ aᵢxᵢ <= b, or < b when strict
+type fmInequality struct {
+	coeffs  map[string]*big.Rat
+	bound   *big.Rat
+	strict  bool
+	sources []int
+}
+
+func inequalitiesOf(row linearRow) []fmInequality {
+	ineq := fmInequality{coeffs: row.coeffs, bound: row.bound, strict: row.op == linearLT, sources: []int{row.source}}
+	if row.op != linearEQ {
+		return []fmInequality{ineq}
+	}
+	negated := fmInequality{coeffs: make(map[string]*big.Rat, len(row.coeffs)), bound: new(big.Rat).Neg(row.bound), sources: ineq.sources}
+	for name, a := range row.coeffs {
+		negated.coeffs[name] = new(big.Rat).Neg(a)
+	}
+	return []fmInequality{ineq, negated}
+}
+
+// holds reports whether an inequality without symbols is true
+func (q fmInequality) holds() bool {
+	if q.strict {
+		return q.bound.Sign() > 0
+	}
+	return q.bound.Sign() >= 0
+}
+
+// key identifies the direction of the inequality, so of two inequalities
+// with the same key only the tighter needs to be kept
+func (q fmInequality) key() (string, *big.Rat) {
+	names := sortedNames(q.coeffs)
+	scale := new(big.Rat).Abs(q.coeffs[names[0]])
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s:%s ", name, new(big.Rat).Quo(q.coeffs[name], scale).RatString())
+	}
+	return b.String(), new(big.Rat).Quo(q.bound, scale)
+}
+
+// elimination records the inequalities that mentioned a symbol when it was
+// eliminated, for back-substitution
+type elimination struct {
+	name         string
+	inequalities []fmInequality
+}
+
+// fourierMotzkin eliminates the symbols one at a time, combining each
+// inequality bounding a symbol from above with each bounding it from below.
+// A violated inequality without symbols refutes the rows. Otherwise values
+// are chosen in reverse elimination order inside the bounds the recorded
+// inequalities leave; when no integer fits an integer symbol, that symbol and
+// the value chosen over the reals are returned for branching.
+func fourierMotzkin(rows []linearRow, integers map[string]bool) (linearOutcome, map[string]*big.Rat, []int, string, *big.Rat) {
+	var current []fmInequality
+	for _, row := range rows {
+		for _, q := range inequalitiesOf(row) {
+			if len(q.coeffs) == 0 {
+				if !q.holds() {
+					return linearUnsat, nil, q.sources, "", nil
+				}
+				continue
+			}
+			current = append(current, q)
+		}
+	}
+
+	var eliminated []elimination
+	for len(current) > 0 {
+		name := cheapestElimination(current)
+		var upper, lower, rest []fmInequality
+		for _, q := range current {
+			switch a := q.coeffs[name]; {
+			case a == nil:
+				rest = append(rest, q)
+			case a.Sign() > 0:
+				upper = append(upper, q)
+			default:
+				lower = append(lower, q)
+			}
+		}
+		eliminated = append(eliminated, elimination{name, append(append([]fmInequality(nil), upper...), lower...)})
+
+		kept := map[string]int{}
+		next := make([]fmInequality, 0, len(rest)+len(upper)*len(lower))
+		add := func(q fmInequality) bool {
+			if len(q.coeffs) == 0 {
+				return q.holds()
+			}
+			key, bound := q.key()
+			if i, ok := kept[key]; ok {
+				_, keptBound := next[i].key()
+				if cmp := bound.Cmp(keptBound); cmp < 0 || (cmp == 0 && q.strict && !next[i].strict) {
+					next[i] = q
+				}
+				return true
+			}
+			kept[key] = len(next)
+			next = append(next, q)
+			return true
+		}
+		for _, q := range rest {
+			add(q)
+		}
+		for _, u := range upper {
+			for _, l := range lower {
+				q := combine(u, l, name)
+				if !add(q) {
+					return linearUnsat, nil, q.sources, "", nil
+				}
+			}
+			if len(next) > maxLinearInequalities {
+				return linearUnknown, nil, nil, "", nil
+			}
+		}
+		current = next
+	}
+
+	witness := map[string]*big.Rat{}
+	for i := len(eliminated) - 1; i >= 0; i-- {
+		e := eliminated[i]
+		var lo, hi *big.Rat
+		var loStrict, hiStrict bool
+		for _, q := range e.inequalities {
+			// a·x <= b - Σ(others)
+			a := q.coeffs[e.name]
+			rest := new(big.Rat).Set(q.bound)
+			for other, c := range q.coeffs {
+				if other == e.name {
+					continue
+				}
+				if witness[other] == nil {
+					// Never eliminated: no inequality was left to bound it
+					witness[other] = new(big.Rat)
+				}
+				rest.Sub(rest, new(big.Rat).Mul(c, witness[other]))
+			}
+			limit := rest.Quo(rest, a)
+			if a.Sign() > 0 {
+				if hi == nil || limit.Cmp(hi) < 0 || (limit.Cmp(hi) == 0 && q.strict) {
+					hi, hiStrict = limit, q.strict
+				}
+			} else if lo == nil || limit.Cmp(lo) > 0 || (limit.Cmp(lo) == 0 && q.strict) {
+				lo, loStrict = limit, q.strict
+			}
+		}
+		value, isInteger := chooseValue(lo, loStrict, hi, hiStrict)
+		if integers[e.name] && !isInteger {
+			return linearSat, nil, nil, e.name, value
+		}
+		witness[e.name] = value
+	}
+	for _, row := range rows {
+		for name := range row.coeffs {
+			if witness[name] == nil {
+				witness[name] = new(big.Rat)
+			}
+		}
+	}
+	return linearSat, witness, nil, "", nil
+}
+
+// cheapestElimination picks the symbol whose elimination adds the fewest
+// inequalities
+func cheapestElimination(inequalities []fmInequality) string {
+	upper := map[string]int{}
+	lower := map[string]int{}
+	for _, q := range inequalities {
+		for name, a := range q.coeffs {
+			if a.Sign() > 0 {
+				upper[name]++
+			} else {
+				lower[name]++
+			}
+		}
+	}
+	names := make([]string, 0, len(upper)+len(lower))
+	for name := range upper {
+		names = append(names, name)
+	}
+	for name := range lower {
+		if upper[name] == 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	best, bestCost := "", 0
+	for _, name := range names {
+		cost := upper[name]*lower[name] - upper[name] - lower[name]
+		if best == "" || cost < bestCost {
+			best, bestCost = name, cost
+		}
+	}
+	return best
+}
+
+// combine adds positive multiples of u (bounding name from above) and l
+// (bounding it from below) so name cancels
+func combine(u, l fmInequality, name string) fmInequality {
+	uScale := new(big.Rat).Inv(u.coeffs[name])
+	lScale := new(big.Rat).Neg(new(big.Rat).Inv(l.coeffs[name]))
+	sum := linearExpr{coeffs: u.coeffs, constant: u.bound}.scale(uScale).
+		plus(linearExpr{coeffs: l.coeffs, constant: l.bound}, lScale)
+	delete(sum.coeffs, name)
+	return fmInequality{
+		coeffs:  sum.coeffs,
+		bound:   sum.constant,
+		strict:  u.strict || l.strict,
+		sources: unionSources(u.sources, l.sources),
+	}
+}
+
+// chooseValue picks a value inside the bounds, preferring the integer closest
+// to zero and otherwise the midpoint. Fourier–Motzkin guarantees the bounds
+// are consistent.
+func chooseValue(lo *big.Rat, loStrict bool, hi *big.Rat, hiStrict bool) (*big.Rat, bool) {
+	var loInt, hiInt *big.Rat
+	if lo != nil {
+		loInt = ratCeil(lo)
+		if loStrict && lo.IsInt() {
+			loInt.Add(loInt, big.NewRat(1, 1))
+		}
+	}
+	if hi != nil {
+		hiInt = ratFloor(hi)
+		if hiStrict && hi.IsInt() {
+			hiInt.Sub(hiInt, big.NewRat(1, 1))
+		}
+	}
+	if loInt == nil || hiInt == nil || loInt.Cmp(hiInt) <= 0 {
+		value := new(big.Rat)
+		if loInt != nil && loInt.Sign() > 0 {
+			value = loInt
+		}
+		if hiInt != nil && hiInt.Sign() < 0 {
+			value = hiInt
+		}
+		return value, true
+	}
+	if lo.Cmp(hi) == 0 {
+		return new(big.Rat).Set(lo), false
+	}
+	mid := new(big.Rat).Add(lo, hi)
+	return mid.Quo(mid, big.NewRat(2, 1)), false
+}
+
+// satisfiesRows checks a witness against the rows
+func satisfiesRows(rows []linearRow, witness map[string]*big.Rat) bool {
+	for _, row := range rows {
+		sum := new(big.Rat)
+		for name, a := range row.coeffs {
+			value := witness[name]
+			if value == nil {
+				return false
+			}
+			sum.Add(sum, new(big.Rat).Mul(a, value))
+		}
+		cmp := sum.Cmp(row.bound)
+		if (row.op == linearLE && cmp > 0) || (row.op == linearLT && cmp >= 0) || (row.op == linearEQ && cmp != 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func allIntegers(coeffs map[string]*big.Rat, integers map[string]bool) bool {
+	for name := range coeffs {
+		if !integers[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedNames(coeffs map[string]*big.Rat) []string {
+	names := make([]string, 0, len(coeffs))
+	for name := range coeffs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unionSources merges two sorted source lists
+func unionSources(a, b []int) []int {
+	result := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			result = append(result, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func ratFloor(r *big.Rat) *big.Rat {
+	// big.Int.Div rounds toward -∞ for the positive denominator
+	return new(big.Rat).SetInt(new(big.Int).Div(r.Num(), r.Denom()))
+}
+
+func ratCeil(r *big.Rat) *big.Rat {
+	floor := ratFloor(new(big.Rat).Neg(r))
+	return floor.Neg(floor)
+}
+
+func lcm(a, b *big.Int) *big.Int {
+	gcd := new(big.Int).GCD(nil, nil, a, b)
+	result := new(big.Int).Mul(a, b)
+	return result.Quo(result, gcd)
+}
+
+// formatRat renders a witness value, exactly when it is an integer
+func formatRat(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	f, _ := r.Float64()
+	return fmt.Sprintf("%g", f)
+}
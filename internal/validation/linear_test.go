@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLinearConstraint(t *testing.T) {
+	tests := map[string]int{
+		"2x + 3*y - z/2 <= 10":    1,
+		"0 <= x < 10":             2,
+		"x in [1, 5)":             2,
+		"x between 1 and 5":       2,
+		"x >= 0 and -(x - y) = 3": 2,
+		"x ≥ 1 ∧ y ≤ 2":           2,
+	}
+	for expression, want := range tests {
+		rows, err := parseLinearConstraint(expression)
+		if err != nil || len(rows) != want {
+			t.Errorf("parseLinearConstraint(%q) = %d rows, %v; want %d rows", expression, len(rows), err, want)
+		}
+	}
+
+	for _, expression := range []string{"x * y <= 1", "x != 3", "x / y = 2", "x / 0 = 2", "f(x) = x^2", "true and false", "x <"} {
+		if _, err := parseLinearConstraint(expression); err == nil {
+			t.Errorf("parseLinearConstraint(%q) succeeded, want an error", expression)
+		}
+	}
+}
+
+func TestCheckConstraintConsistency_Linear(t *testing.T) {
+	tests := []struct {
+		name        string
+		domain      string
+		expressions []string
+		core        []int // 1-based positions of the minimal core; nil when consistent
+	}{
+		{"three-way conflict", "integer", []string{"x + y <= 10", "z >= 0", "x >= 6", "y >= 5"}, []int{1, 3, 4}},
+		{"redundant bounds", "real", []string{"x <= 1", "x <= 2", "x >= 3"}, []int{1, 3}},
+		{"strict cycle", "real", []string{"x < y", "y < z", "z <= x"}, []int{1, 2, 3}},
+		{"strict gap over the reals", "real", []string{"x < y", "y < x + 1"}, nil},
+		{"strict gap over the integers", "integer", []string{"x < y", "y < x + 1"}, []int{1, 2}},
+		{"parity", "integer", []string{"x + y = 1", "x = y", "x >= -5"}, []int{1, 2}},
+		{"system of equations", "real", []string{"x + y = 10", "x - y = 4", "0 <= x <= 8"}, nil},
+		{"symbolic equalities", "integer", []string{"x = y", "x = 5"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := NewSymbolicReasoner()
+			for _, name := range []string{"x", "y", "z"} {
+				sr.AddSymbol(name, SymbolVariable, tt.domain)
+			}
+			var ids []string
+			for _, expression := range tt.expressions {
+				c, err := sr.AddConstraint(ConstraintInequality, expression, nil)
+				if err != nil {
+					t.Fatalf("AddConstraint(%q): %v", expression, err)
+				}
+				ids = append(ids, c.ID)
+			}
+
+			result, err := sr.CheckConstraintConsistency(ids)
+			if err != nil {
+				t.Fatalf("CheckConstraintConsistency: %v", err)
+			}
+			if tt.core == nil {
+				if !result.IsConsistent || len(result.Witness) == 0 {
+					t.Fatalf("result = %+v, want consistent with a witness", result)
+				}
+				return
+			}
+			var want []string
+			for _, position := range tt.core {
+				want = append(want, ids[position-1])
+			}
+			if result.IsConsistent || !reflect.DeepEqual(result.UnsatCore, want) {
+				t.Fatalf("core = %v, want %v (%s)", result.UnsatCore, want, result.Explanation)
+			}
+			if len(result.Conflicts) != 1 || !reflect.DeepEqual(result.Conflicts[0].Constraints, want) {
+				t.Errorf("conflicts = %+v", result.Conflicts)
+			}
+		})
+	}
+}
+
+func TestCheckConstraintConsistency_Witness(t *testing.T) {
+	sr := NewSymbolicReasoner()
+	sr.AddSymbol("x", SymbolVariable, "real")
+	sr.AddSymbol("y", SymbolVariable, "real")
+	sr.AddSymbol("n", SymbolVariable, "integer")
+	c1, _ := sr.AddConstraint(ConstraintEquality, "x + y = 10", []string{"x", "y"})
+	c2, _ := sr.AddConstraint(ConstraintEquality, "x - y = 3", []string{"x", "y"})
+	c3, _ := sr.AddConstraint(ConstraintRange, "n in (x, 2x]", []string{"n", "x"})
+
+	result, _ := sr.CheckConstraintConsistency([]string{c1.ID, c2.ID, c3.ID})
+	want := map[string]float64{"x": 6.5, "y": 3.5, "n": 7}
+	if !result.IsConsistent || !reflect.DeepEqual(result.Witness, want) {
+		t.Fatalf("witness = %v, want %v", result.Witness, want)
+	}
+	if !strings.Contains(result.Explanation, "e.g. n = 7, x = 6.5, y = 3.5") {
+		t.Errorf("explanation = %q", result.Explanation)
+	}
+}
+
+func TestCheckConstraintConsistency_Mixed(t *testing.T) {
+	sr := NewSymbolicReasoner()
+	sr.AddSymbol("x", SymbolVariable, "integer")
+	sr.AddSymbol("color", SymbolVariable, "string")
+	c1, _ := sr.AddConstraint(ConstraintEquality, "color = red", []string{"color"})
+	c2, _ := sr.AddConstraint(ConstraintEquality, "color = blue", []string{"color"})
+	c3, _ := sr.AddConstraint(ConstraintInequality, "2x > 3", []string{"x"})
+
+	result, _ := sr.CheckConstraintConsistency([]string{c1.ID, c2.ID, c3.ID})
+	if result.IsConsistent || len(result.Conflicts) != 1 || result.Conflicts[0].ConflictType != "equality_conflict" {
+		t.Fatalf("result = %+v", result)
+	}
+	if !reflect.DeepEqual(result.Unchecked, []string{c1.ID, c2.ID}) || result.Witness["x"] != 2 {
+		t.Errorf("unchecked = %v, witness = %v", result.Unchecked, result.Witness)
+	}
+}
+
+func TestCheckSatisfiability_Linear(t *testing.T) {
+	sr := NewSymbolicReasoner()
+	sr.AddSymbol("x", SymbolVariable, "integer")
+
+	constraint, _ := sr.AddConstraint(ConstraintInequality, "x > 5 and 2x < 12", []string{"x"})
+	if constraint.Satisfiable || !strings.Contains(constraint.Explanation, "Contradiction") {
+		t.Errorf("constraint = %+v", constraint)
+	}
+}
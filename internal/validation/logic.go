@@ -3,11 +3,14 @@
 // This package implements logical validation including:
 //   - Consistency checking with contradiction detection
 //   - Logical inference and proof validation
+//   - Propositional proofs by resolution, with counter-models for invalid arguments
+//   - First-order proofs by skolemization and resolution with unification
 //   - Syntax validation for logical statements
 //   - Modus ponens, modus tollens, and syllogism detection
 package validation
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -61,8 +64,25 @@ func (v *LogicValidator) ValidateThought(thought *types.Thought) (*types.Validat
 	return validation, nil
 }
 
-// Prove attempts to prove a conclusion from premises using logical inference rules
+// Prove attempts to prove a conclusion from premises. Arguments whose
+// statements parse as propositional formulas are decided by resolution: the
+// result carries either a refutation trace or a counter-model. Arguments with
+// quantifiers, such as categorical syllogisms, are formalized in first-order
+// logic and decided by resolution with unification when the search ends
+// within DefaultProverLimits. Other arguments fall back to inference
+// patterns.
 func (v *LogicValidator) Prove(premises []string, conclusion string) *ProofResult {
+	if result := v.proveFormally(premises, conclusion); result != nil {
+		return result
+	}
+	if result := v.proveQuantified(premises, conclusion); result != nil {
+		return result
+	}
+	return v.proveByPatterns(premises, conclusion)
+}
+
+// proveByPatterns matches premises and conclusion against inference rules
+func (v *LogicValidator) proveByPatterns(premises []string, conclusion string) *ProofResult {
 	steps := []string{}
 	isProvable := false
 
@@ -152,12 +172,14 @@ func (v *LogicValidator) Prove(premises []string, conclusion string) *ProofResul
 		Conclusion: conclusion,
 		IsProvable: isProvable,
 		Steps:      steps,
+		Method:     ProofMethodPatterns,
 	}
 
 	return result
 }
 
-// CheckWellFormed validates statement syntax
+// CheckWellFormed validates statement syntax with the formula parser used by
+// Prove. A syntax error reports the position of the offending token.
 func (v *LogicValidator) CheckWellFormed(statements []string) []StatementCheck {
 	checks := make([]StatementCheck, len(statements))
 	for i, stmt := range statements {
@@ -166,6 +188,11 @@ func (v *LogicValidator) CheckWellFormed(statements []string) []StatementCheck {
 			IsWellFormed: v.checkSyntax(stmt),
 			Issues:       v.getSyntaxIssues(stmt),
 		}
+		var syntaxErr *SyntaxError
+		if _, err := ParseFormula(stmt); errors.As(err, &syntaxErr) && strings.TrimSpace(stmt) != "" {
+			position := syntaxErr.Pos
+			checks[i].Position = &position
+		}
 	}
 	return checks
 }
@@ -1146,32 +1173,14 @@ func (v *LogicValidator) getSyntaxIssues(statement string) []string {
 		issues = append(issues, "Statement appears to be a single word")
 	}
 
-	// Check 4: Balanced parentheses
-	if !v.hasBalancedParentheses(trimmed) {
-		issues = append(issues, "Unbalanced parentheses")
-	}
-
-	// Check 5: Check for common malformed patterns
-	if v.hasMalformedLogicalOperators(trimmed) {
-		issues = append(issues, "Malformed logical operators (e.g., consecutive 'and and', 'or or')")
+	// Check 4: Parse with the formula grammar; errors name the category
+	// (unbalanced parentheses, malformed operators, incomplete conditional,
+	// mismatched quotes, empty quantifier) and the token position
+	if _, err := ParseFormula(statement); err != nil {
+		issues = append(issues, err.Error())
 	}
 
-	// Check 6: Check for incomplete conditionals
-	if v.hasIncompleteConditional(trimmed) {
-		issues = append(issues, "Incomplete conditional statement (e.g., 'if...then' without conclusion)")
-	}
-
-	// Check 7: Check for mismatched quotes
-	if v.hasMismatchedQuotes(trimmed) {
-		issues = append(issues, "Mismatched quotation marks")
-	}
-
-	// Check 8: Check for empty quantifiers
-	if v.hasEmptyQuantifier(trimmed) {
-		issues = append(issues, "Empty or incomplete quantifier (e.g., 'all' without subject)")
-	}
-
-	// Check 9: Proper sentence structure (relaxed - case sensitivity removed)
+	// Check 5: Proper sentence structure (relaxed - case sensitivity removed)
 	// Commented out as this is too strict for practical use
 	// if !v.hasProperStart(trimmed) {
 	// 	issues = append(issues, "Statement should start with a capital letter, quantifier, or logical symbol")
@@ -1180,107 +1189,6 @@ func (v *LogicValidator) getSyntaxIssues(statement string) []string {
 	return issues
 }
 
-// hasBalancedParentheses checks if all parentheses are properly balanced
-func (v *LogicValidator) hasBalancedParentheses(statement string) bool {
-	stack := 0
-	for _, ch := range statement {
-		switch ch {
-		case '(', '[', '{':
-			stack++
-		case ')', ']', '}':
-			stack--
-			if stack < 0 {
-				return false
-			}
-		}
-	}
-	return stack == 0
-}
-
-// hasMalformedLogicalOperators checks for consecutive duplicate operators
-func (v *LogicValidator) hasMalformedLogicalOperators(statement string) bool {
-	lower := strings.ToLower(statement)
-
-	malformedPatterns := []string{
-		" and and ", " or or ", " not not ", " if if ", " then then ",
-		" all all ", " some some ", " every every ", " no no ",
-	}
-
-	for _, pattern := range malformedPatterns {
-		if strings.Contains(lower, pattern) {
-			return true
-		}
-	}
-	return false
-}
-
-// hasIncompleteConditional checks for incomplete if-then statements
-func (v *LogicValidator) hasIncompleteConditional(statement string) bool {
-	lower := strings.ToLower(statement)
-
-	// If statement has "if" but no "then" or vice versa (for formal conditionals)
-	hasIf := strings.Contains(lower, " if ")
-	hasThen := strings.Contains(lower, " then ")
-
-	// Allow "if" at the start
-	if strings.HasPrefix(lower, "if ") {
-		hasIf = true
-	}
-
-	// If we have "if" but no "then", that's incomplete (unless it's a simple conditional)
-	if hasIf && !hasThen {
-		// Check if it's a simple "X if Y" pattern (which is valid)
-		ifIndex := strings.Index(lower, " if ")
-		if ifIndex == -1 {
-			ifIndex = strings.Index(lower, "if ")
-		}
-		// If "if" is near the end, it might be "X if Y" which is valid
-		if ifIndex < len(lower)-10 { // Arbitrary threshold
-			return true
-		}
-	}
-
-	// Having "then" without "if" is also suspicious
-	if hasThen && !hasIf && !strings.Contains(lower, " implies ") {
-		return true
-	}
-
-	return false
-}
-
-// hasMismatchedQuotes checks for unmatched quotation marks
-func (v *LogicValidator) hasMismatchedQuotes(statement string) bool {
-	singleQuotes := strings.Count(statement, "'")
-	doubleQuotes := strings.Count(statement, "\"")
-
-	return singleQuotes%2 != 0 || doubleQuotes%2 != 0
-}
-
-// hasEmptyQuantifier checks for quantifiers without proper subjects
-func (v *LogicValidator) hasEmptyQuantifier(statement string) bool {
-	lower := strings.ToLower(statement)
-
-	// Patterns like "all " at end, or "some " at end
-	if strings.HasSuffix(lower, " all") || strings.HasSuffix(lower, " some") ||
-		strings.HasSuffix(lower, " every") || strings.HasSuffix(lower, " no") {
-		return true
-	}
-
-	// Patterns like "all and", "some or" (quantifier immediately followed by operator)
-	emptyPatterns := []string{
-		"all and ", "all or ", "some and ", "some or ",
-		"every and ", "every or ", "no and ", "no or ",
-	}
-
-	for _, pattern := range emptyPatterns {
-		if strings.Contains(lower, pattern) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // hasProperStart checks if statement starts appropriately
 func (v *LogicValidator) hasProperStart(statement string) bool {
 	trimmed := strings.TrimSpace(statement)
@@ -1343,6 +1251,10 @@ type ProofResult struct {
 	Conclusion string   `json:"conclusion"`
 	IsProvable bool     `json:"is_provable"`
 	Steps      []string `json:"steps"`
+	Method     string   `json:"method,omitempty"` // ProofMethodResolution or ProofMethodPatterns
+	// CounterModel assigns the propositions so that every premise is true
+	// and the conclusion false, when resolution shows the argument invalid
+	CounterModel map[string]bool `json:"counter_model,omitempty"`
 }
 
 // StatementCheck represents syntax validation results
@@ -1350,4 +1262,5 @@ type StatementCheck struct {
 	Statement    string   `json:"statement"`
 	IsWellFormed bool     `json:"is_well_formed"`
 	Issues       []string `json:"issues,omitempty"`
+	Position     *int     `json:"position,omitempty"` // Character offset (0-based) of the syntax error
 }
@@ -175,12 +175,12 @@ func TestLogicValidator_Prove(t *testing.T) {
 			wantProvable: true,
 		},
 		{
-			name: "conclusion contains premise content",
+			name: "conclusion adds to premise content",
 			premises: []string{
 				"The sky is blue",
 			},
 			conclusion:   "The sky is blue today",
-			wantProvable: true,
+			wantProvable: false, // "today" is not stated by the premise
 		},
 		{
 			name: "valid premises and conclusion",
@@ -698,12 +698,12 @@ func TestModusPonens(t *testing.T) {
 			wantProvable: true,
 		},
 		{
-			name: "seemingly invalid - missing explicit antecedent",
+			name: "invalid - missing antecedent",
 			premises: []string{
 				"If P then Q",
 			},
 			conclusion:   "Q",
-			wantProvable: true, // Direct derivation finds "Q" in premise
+			wantProvable: false, // Counter-model: P and Q false
 		},
 		{
 			name: "invalid - conclusion doesn't match",
@@ -765,13 +765,13 @@ func TestModusTollens(t *testing.T) {
 			wantProvable: false,
 		},
 		{
-			name: "conclusion contains P from premise",
+			name: "invalid - conclusion is the denied antecedent",
 			premises: []string{
 				"If P then Q",
 				"not Q",
 			},
 			conclusion:   "P",
-			wantProvable: true, // Direct derivation finds "P" in premise
+			wantProvable: false, // The premises entail not P
 		},
 	}
 
@@ -816,22 +816,22 @@ func TestHypotheticalSyllogism(t *testing.T) {
 			wantProvable: true,
 		},
 		{
-			name: "Q doesn't match but direct derivation",
+			name: "invalid - chain broken",
 			premises: []string{
 				"If P then Q",
 				"If R then S",
 			},
 			conclusion:   "If P then S",
-			wantProvable: true, // Contains "if", "p", "s" from premises
+			wantProvable: false,
 		},
 		{
-			name: "conclusion contains parts from premises",
+			name: "invalid - converse",
 			premises: []string{
 				"If P then Q",
 				"If Q then R",
 			},
 			conclusion:   "If Q then P",
-			wantProvable: true, // Contains "if", "q", "p" from premises
+			wantProvable: false,
 		},
 	}
 
@@ -885,21 +885,21 @@ func TestDisjunctiveSyllogism(t *testing.T) {
 			wantProvable: true,
 		},
 		{
-			name: "no negation but direct derivation",
+			name: "invalid - no disjunct eliminated",
 			premises: []string{
 				"P or Q",
 			},
 			conclusion:   "P",
-			wantProvable: true, // "P" is in premise
+			wantProvable: false,
 		},
 		{
-			name: "conclusion contains letters from premises",
+			name: "invalid - unrelated conclusion",
 			premises: []string{
 				"P or Q",
 				"not P",
 			},
 			conclusion:   "R",
-			wantProvable: true, // Direct derivation is very permissive
+			wantProvable: false,
 		},
 	}
 
@@ -965,14 +965,14 @@ func TestSyntaxValidation_EdgeCases(t *testing.T) {
 			expectIssues: []string{"Incomplete conditional"},
 		},
 		{
-			name:         "then without if but starts with capital",
+			name:         "then without if",
 			statement:    "Then we are happy",
-			expectIssues: []string{}, // Starts with "Then" which is capitalized, no " then " in middle
+			expectIssues: []string{`"then" without "if" at position 0`},
 		},
 		{
-			name:         "conditional X if Y - might be incomplete",
+			name:         "conditional X if Y",
 			statement:    "We are happy if sky is blue",
-			expectIssues: []string{"Incomplete conditional"}, // "if" near end but > 10 chars from end
+			expectIssues: []string{},
 		},
 		{
 			name:         "valid if-then",
@@ -1067,39 +1067,6 @@ func TestSyntaxValidation_EdgeCases(t *testing.T) {
 	}
 }
 
-// TestHasBalancedParentheses tests parenthesis balancing logic
-func TestHasBalancedParentheses(t *testing.T) {
-	validator := NewLogicValidator()
-
-	tests := []struct {
-		name         string
-		statement    string
-		wantBalanced bool
-	}{
-		{"balanced round", "(test)", true},
-		{"balanced square", "[test]", true},
-		{"balanced curly", "{test}", true},
-		{"balanced nested", "((test))", true},
-		{"balanced mixed", "({[test]})", true},
-		{"unbalanced - more open", "((test)", false},
-		{"unbalanced - more close", "test))", false},
-		{"unbalanced - wrong order", ")(", false},
-		{"no parentheses", "test", true},
-		{"empty string", "", true},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := validator.hasBalancedParentheses(tt.statement)
-
-			if result != tt.wantBalanced {
-				t.Errorf("hasBalancedParentheses(%q) = %v, want %v",
-					tt.statement, result, tt.wantBalanced)
-			}
-		})
-	}
-}
-
 // TestHasProperStart tests statement start validation
 func TestHasProperStart(t *testing.T) {
 	validator := NewLogicValidator()
@@ -1139,33 +1106,3 @@ func TestHasProperStart(t *testing.T) {
 		})
 	}
 }
-
-// TestHasIncompleteConditional tests conditional validation
-func TestHasIncompleteConditional(t *testing.T) {
-	validator := NewLogicValidator()
-
-	tests := []struct {
-		name           string
-		statement      string
-		wantIncomplete bool
-	}{
-		{"valid if-then", "If A then B", false},
-		{"valid with implies", "A implies B", false},
-		{"incomplete - if without then (long)", "If the sky is very blue we are happy", true},
-		{"valid - X if Y pattern", "Happy if blue", false},
-		{"incomplete - then without if", "Then we succeed", false}, // "Then" at start is actually allowed
-		{"valid - then with implies", "A implies B then C", false},
-		{"no conditional keywords", "Normal statement", false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := validator.hasIncompleteConditional(tt.statement)
-
-			if result != tt.wantIncomplete {
-				t.Errorf("hasIncompleteConditional(%q) = %v, want %v",
-					tt.statement, result, tt.wantIncomplete)
-			}
-		})
-	}
-}
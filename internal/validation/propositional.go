@@ -0,0 +1,466 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Ways Prove can establish a result
+const (
+	ProofMethodResolution = "resolution"             // Propositional refutation, or a counter-model
+	ProofMethodFirstOrder = "first_order_resolution" // Refutation with unification, or a saturated search
+	ProofMethodPatterns   = "patterns"               // Inference patterns over statements the parser cannot formalize
+)
+
+// MaxCNFClauses bounds the clauses produced when converting one statement to CNF
+const MaxCNFClauses = 4096
+
+// MaxNormalFormNodes bounds the nodes built when rewriting one statement in
+// negation normal form
+const MaxNormalFormNodes = 1 << 16
+
+// MaxResolutionClauses bounds the clauses kept while searching for a
+// resolution refutation
+const MaxResolutionClauses = 2000
+
+// literal is a possibly negated proposition
+type literal struct {
+	atom    string
+	negated bool
+}
+
+func (l literal) String() string {
+	if l.negated {
+		return "¬" + l.atom
+	}
+	return l.atom
+}
+
+// clause is a disjunction of literals, sorted and without duplicates
+type clause []literal
+
+func (c clause) String() string {
+	if len(c) == 0 {
+		return "⊥"
+	}
+	parts := make([]string, len(c))
+	for i, l := range c {
+		parts[i] = l.String()
+	}
+	return strings.Join(parts, " ∨ ")
+}
+
+// subsumes reports whether every literal of c is in other
+func (c clause) subsumes(other clause) bool {
+	if len(c) > len(other) {
+		return false
+	}
+	j := 0
+	for _, l := range c {
+		for j < len(other) && other[j] != l {
+			j++
+		}
+		if j == len(other) {
+			return false
+		}
+	}
+	return true
+}
+
+// newClause sorts and deduplicates literals. It reports false for a
+// tautology, which contains a literal and its negation.
+func newClause(literals []literal) (clause, bool) {
+	sorted := append(clause(nil), literals...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].atom != sorted[j].atom {
+			return sorted[i].atom < sorted[j].atom
+		}
+		return !sorted[i].negated && sorted[j].negated
+	})
+	result := sorted[:0]
+	for i, l := range sorted {
+		if i > 0 && sorted[i-1].atom == l.atom {
+			if sorted[i-1].negated != l.negated {
+				return nil, false
+			}
+			continue
+		}
+		result = append(result, l)
+	}
+	return result, true
+}
+
+// toCNF converts a quantifier-free formula to clauses
+func toCNF(f *Formula) ([]clause, error) {
+	normal, err := negationNormalForm(f, false)
+	if err != nil {
+		return nil, err
+	}
+	return cnfClauses(normal)
+}
+
+// negationNormalForm rewrites f, negated when negate is set, using only
+// quantifiers, conjunction, disjunction and negated atoms. Each biconditional
+// copies both its sides, so nested ones grow the result exponentially; the
+// rewrite fails once it has built MaxNormalFormNodes nodes.
+func negationNormalForm(f *Formula, negate bool) (*Formula, error) {
+	return (&normalizer{}).rewrite(f, negate)
+}
+
+// normalizer counts the nodes built by one negation normal form rewrite
+type normalizer struct {
+	nodes int
+}
+
+func (n *normalizer) rewrite(f *Formula, negate bool) (*Formula, error) {
+	n.nodes++
+	if n.nodes > MaxNormalFormNodes {
+		return nil, fmt.Errorf("statement needs more than %d nodes in negation normal form", MaxNormalFormNodes)
+	}
+	switch f.Op {
+	case FormulaAtom:
+		if negate {
+			return &Formula{Op: FormulaNot, Left: f}, nil
+		}
+		return f, nil
+	case FormulaTrue, FormulaFalse:
+		if negate == (f.Op == FormulaTrue) {
+			return &Formula{Op: FormulaFalse}, nil
+		}
+		return &Formula{Op: FormulaTrue}, nil
+	case FormulaNot:
+		return n.rewrite(f.Left, !negate)
+	case FormulaAnd, FormulaOr:
+		op := f.Op
+		if negate {
+			op = map[FormulaOp]FormulaOp{FormulaAnd: FormulaOr, FormulaOr: FormulaAnd}[op]
+		}
+		left, err := n.rewrite(f.Left, negate)
+		if err != nil {
+			return nil, err
+		}
+		right, err := n.rewrite(f.Right, negate)
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: op, Left: left, Right: right}, nil
+	case FormulaImplies:
+		return n.rewrite(&Formula{Op: FormulaOr, Left: &Formula{Op: FormulaNot, Left: f.Left}, Right: f.Right}, negate)
+	case FormulaIff:
+		forward := &Formula{Op: FormulaOr, Left: &Formula{Op: FormulaNot, Left: f.Left}, Right: f.Right}
+		backward := &Formula{Op: FormulaOr, Left: f.Left, Right: &Formula{Op: FormulaNot, Left: f.Right}}
+		return n.rewrite(&Formula{Op: FormulaAnd, Left: forward, Right: backward}, negate)
+	case FormulaForAll, FormulaExists:
+		op := f.Op
+		if negate {
+			op = map[FormulaOp]FormulaOp{FormulaForAll: FormulaExists, FormulaExists: FormulaForAll}[op]
+		}
+		body, err := n.rewrite(f.Left, negate)
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: op, Var: f.Var, Left: body}, nil
+	}
+	return f, nil
+}
+
+// cnfClauses distributes disjunction over conjunction in a formula in
+// negation normal form
+func cnfClauses(f *Formula) ([]clause, error) {
+	switch f.Op {
+	case FormulaAtom:
+		return []clause{{{atom: f.String()}}}, nil
+	case FormulaNot:
+		return []clause{{{atom: f.Left.String(), negated: true}}}, nil
+	case FormulaTrue:
+		return nil, nil
+	case FormulaFalse:
+		return []clause{{}}, nil
+	case FormulaAnd, FormulaOr:
+		left, err := cnfClauses(f.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := cnfClauses(f.Right)
+		if err != nil {
+			return nil, err
+		}
+		if f.Op == FormulaAnd {
+			if len(left)+len(right) > MaxCNFClauses {
+				return nil, fmt.Errorf("statement needs more than %d clauses in conjunctive normal form", MaxCNFClauses)
+			}
+			return append(left, right...), nil
+		}
+		if len(left)*len(right) > MaxCNFClauses {
+			return nil, fmt.Errorf("statement needs more than %d clauses in conjunctive normal form", MaxCNFClauses)
+		}
+		var clauses []clause
+		for _, a := range left {
+			for _, b := range right {
+				if merged, ok := newClause(append(append(clause(nil), a...), b...)); ok {
+					clauses = append(clauses, merged)
+				}
+			}
+		}
+		return clauses, nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to conjunctive normal form", f.Op)
+}
+
+// dpll searches for an assignment satisfying every clause, extending
+// assignment, and returns nil when there is none
+func dpll(clauses []clause, assignment map[string]bool) map[string]bool {
+	for {
+		var remaining []clause
+		var unit *literal
+		for _, c := range clauses {
+			satisfied := false
+			var open clause
+			for _, l := range c {
+				value, assigned := assignment[l.atom]
+				if !assigned {
+					open = append(open, l)
+				} else if value != l.negated {
+					satisfied = true
+					break
+				}
+			}
+			if satisfied {
+				continue
+			}
+			if len(open) == 0 {
+				return nil
+			}
+			if len(open) == 1 && unit == nil {
+				unit = &open[0]
+			}
+			remaining = append(remaining, open)
+		}
+		if len(remaining) == 0 {
+			return assignment
+		}
+		clauses = remaining
+		if unit == nil {
+			break
+		}
+		assignment[unit.atom] = !unit.negated
+	}
+
+	atom := clauses[0][0].atom
+	for _, value := range []bool{true, false} {
+		branch := make(map[string]bool, len(assignment)+1)
+		for k, v := range assignment {
+			branch[k] = v
+		}
+		branch[atom] = value
+		if model := dpll(clauses, branch); model != nil {
+			return model
+		}
+	}
+	return nil
+}
+
+// derivedClause is a clause of a resolution proof
+type derivedClause struct {
+	clause  clause
+	origin  string // Statement an input clause comes from
+	parents []int  // Resolved clauses, nil for input clauses
+	pivot   string // Atom resolved on
+}
+
+// refute searches for a resolution refutation of the input clauses and
+// returns the steps deriving the empty clause, or nil when none is found
+// within MaxResolutionClauses
+func refute(inputs []derivedClause) []string {
+	var derived []derivedClause
+	add := func(d derivedClause) bool {
+		for _, existing := range derived {
+			if existing.clause.subsumes(d.clause) {
+				return false
+			}
+		}
+		derived = append(derived, d)
+		return true
+	}
+	for _, input := range inputs {
+		add(input)
+		if len(input.clause) == 0 {
+			return proofTrace(derived, len(derived)-1)
+		}
+	}
+
+	// Pair each clause with every earlier one, preferring short resolvents
+	for i := 0; i < len(derived); i++ {
+		for j := 0; j < i; j++ {
+			for _, resolvent := range resolve(derived[i], derived[j], i, j) {
+				if !add(resolvent) {
+					continue
+				}
+				if len(resolvent.clause) == 0 {
+					return proofTrace(derived, len(derived)-1)
+				}
+				if len(derived) >= MaxResolutionClauses {
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the resolvents of two clauses
+func resolve(a, b derivedClause, i, j int) []derivedClause {
+	var resolvents []derivedClause
+	for _, la := range a.clause {
+		for _, lb := range b.clause {
+			if la.atom != lb.atom || la.negated == lb.negated {
+				continue
+			}
+			var literals []literal
+			for _, l := range a.clause {
+				if l != la {
+					literals = append(literals, l)
+				}
+			}
+			for _, l := range b.clause {
+				if l != lb {
+					literals = append(literals, l)
+				}
+			}
+			if merged, ok := newClause(literals); ok {
+				resolvents = append(resolvents, derivedClause{clause: merged, parents: []int{j, i}, pivot: la.atom})
+			}
+		}
+	}
+	sort.SliceStable(resolvents, func(x, y int) bool { return len(resolvents[x].clause) < len(resolvents[y].clause) })
+	return resolvents
+}
+
+// proofTrace numbers the clauses the empty clause was derived from
+func proofTrace(derived []derivedClause, empty int) []string {
+	used := map[int]bool{}
+	var visit func(int)
+	visit = func(i int) {
+		if used[i] {
+			return
+		}
+		used[i] = true
+		for _, parent := range derived[i].parents {
+			visit(parent)
+		}
+	}
+	visit(empty)
+
+	indexes := make([]int, 0, len(used))
+	for i := range used {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	numbers := make(map[int]int, len(indexes))
+	steps := make([]string, 0, len(indexes))
+	for n, i := range indexes {
+		numbers[i] = n + 1
+		d := derived[i]
+		justification := d.origin
+		if d.parents != nil {
+			justification = fmt.Sprintf("resolve %d and %d on %s", numbers[d.parents[0]], numbers[d.parents[1]], d.pivot)
+		}
+		steps = append(steps, fmt.Sprintf("  %d. %s  [%s]", n+1, d.clause, justification))
+	}
+	return steps
+}
+
+// proveFormally decides an argument whose statements all parse as
+// propositional formulas. It returns nil for arguments outside that fragment.
+func (v *LogicValidator) proveFormally(premises []string, conclusion string) *ProofResult {
+	if strings.TrimSpace(conclusion) == "" {
+		return nil
+	}
+	statements := append(append([]string(nil), premises...), conclusion)
+	formulas := make([]*Formula, len(statements))
+	for i, statement := range statements {
+		formula, err := ParseFormula(statement)
+		if err != nil || !formula.isPropositional() {
+			return nil
+		}
+		formulas[i] = formula
+	}
+	goal := formulas[len(premises)]
+
+	var inputs []derivedClause
+	var clauses []clause
+	for i, formula := range formulas {
+		origin := fmt.Sprintf("premise %d", i+1)
+		if i == len(premises) {
+			formula = &Formula{Op: FormulaNot, Left: goal}
+			origin = "negated conclusion"
+		}
+		cnf, err := toCNF(formula)
+		if err != nil {
+			return nil
+		}
+		for _, c := range cnf {
+			inputs = append(inputs, derivedClause{clause: c, origin: origin})
+			clauses = append(clauses, c)
+		}
+	}
+
+	steps := make([]string, 0, 2*len(statements)+4)
+	for i, p := range premises {
+		steps = append(steps, fmt.Sprintf("Premise %d: %s", i+1, p))
+	}
+	for i, formula := range formulas[:len(premises)] {
+		steps = append(steps, fmt.Sprintf("Formalized premise %d: %s", i+1, formula))
+	}
+	steps = append(steps, fmt.Sprintf("Formalized conclusion: %s", goal))
+
+	result := &ProofResult{
+		Premises:   premises,
+		Conclusion: conclusion,
+		Method:     ProofMethodResolution,
+	}
+
+	if model := dpll(clauses, map[string]bool{}); model != nil {
+		counterModel := make(map[string]bool)
+		for _, formula := range formulas {
+			collectAtoms(formula, counterModel)
+		}
+		assignments := make([]string, 0, len(counterModel))
+		for atom := range counterModel {
+			counterModel[atom] = model[atom]
+			assignments = append(assignments, fmt.Sprintf("%s = %t", atom, model[atom]))
+		}
+		sort.Strings(assignments)
+		result.CounterModel = counterModel
+		result.Steps = append(steps,
+			fmt.Sprintf("Counter-model: %s", strings.Join(assignments, ", ")),
+			"Under this assignment every premise is true and the conclusion is false",
+			"Cannot prove conclusion from given premises")
+		return result
+	}
+
+	result.IsProvable = true
+	if trace := refute(inputs); trace != nil {
+		steps = append(steps, "Refutation by resolution on the premises and the negated conclusion:")
+		steps = append(steps, trace...)
+	} else {
+		steps = append(steps, "No assignment makes the premises true and the conclusion false (DPLL)")
+	}
+	result.Steps = append(steps, fmt.Sprintf("Therefore: %s", conclusion))
+	return result
+}
+
+// collectAtoms adds the propositions of a quantifier-free formula to atoms
+func collectAtoms(f *Formula, atoms map[string]bool) {
+	switch f.Op {
+	case FormulaAtom:
+		atoms[f.String()] = false
+	case FormulaTrue, FormulaFalse:
+	case FormulaNot:
+		collectAtoms(f.Left, atoms)
+	default:
+		collectAtoms(f.Left, atoms)
+		collectAtoms(f.Right, atoms)
+	}
+}
@@ -0,0 +1,176 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProve_Resolution(t *testing.T) {
+	validator := NewLogicValidator()
+
+	tests := []struct {
+		name       string
+		premises   []string
+		conclusion string
+	}{
+		{"modus ponens", []string{"If it rains, the ground is wet", "It rains"}, "The ground is wet"},
+		{"modus tollens", []string{"If it rains, the ground is wet", "The ground isn't wet"}, "It does not rain"},
+		{"hypothetical syllogism", []string{"P → Q", "Q → R"}, "P → R"},
+		{"constructive dilemma", []string{"(P → Q) ∧ (R → S)", "P ∨ R"}, "Q ∨ S"},
+		{"biconditional", []string{"(A AND B) OR (NOT A AND NOT B)", "A"}, "B"},
+		{"exclusive or", []string{"beach XOR home", "beach"}, "NOT home"},
+		{"De Morgan", []string{"neither the cache is slow nor the database is slow"}, "not (the cache is slow or the database is slow)"},
+		{"tautology", nil, "P or not P"},
+		{"inconsistent premises", []string{"P", "not P"}, "Q"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validator.Prove(tt.premises, tt.conclusion)
+			if !result.IsProvable || result.Method != ProofMethodResolution || result.CounterModel != nil {
+				t.Fatalf("result = %+v", result)
+			}
+			joined := strings.Join(result.Steps, "\n")
+			if !strings.Contains(joined, "Refutation by resolution") || !strings.Contains(joined, "⊥  [resolve") {
+				t.Errorf("steps = %s", joined)
+			}
+			if last := result.Steps[len(result.Steps)-1]; last != "Therefore: "+tt.conclusion {
+				t.Errorf("last step = %q", last)
+			}
+		})
+	}
+}
+
+func TestProve_CounterModel(t *testing.T) {
+	validator := NewLogicValidator()
+
+	tests := []struct {
+		name       string
+		premises   []string
+		conclusion string
+	}{
+		{"affirming the consequent", []string{"If it rains, the ground is wet", "The ground is wet"}, "It rains"},
+		{"denying the antecedent", []string{"If it rains, the ground is wet", "It does not rain"}, "The ground is not wet"},
+		{"missing premise", []string{"P implies Q"}, "Q"},
+		{"converse", []string{"P → Q"}, "Q → P"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validator.Prove(tt.premises, tt.conclusion)
+			if result.IsProvable || result.Method != ProofMethodResolution || len(result.CounterModel) == 0 {
+				t.Fatalf("result = %+v", result)
+			}
+
+			// The counter-model makes every premise true and the conclusion false
+			for i, statement := range append(append([]string(nil), tt.premises...), tt.conclusion) {
+				formula, err := ParseFormula(statement)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := i < len(tt.premises)
+				if got := evaluate(t, formula, result.CounterModel); got != want {
+					t.Errorf("%q is %v under %v", statement, got, result.CounterModel)
+				}
+			}
+		})
+	}
+
+	result := validator.Prove([]string{"If it rains, the ground is wet", "The ground is wet"}, "It rains")
+	if result.CounterModel["it rain"] || !result.CounterModel["ground is wet"] {
+		t.Errorf("counter-model = %v", result.CounterModel)
+	}
+}
+
+// evaluate computes the truth value of a propositional formula
+func evaluate(t *testing.T, f *Formula, model map[string]bool) bool {
+	switch f.Op {
+	case FormulaAtom:
+		value, exists := model[f.String()]
+		if !exists {
+			t.Fatalf("counter-model has no value for %s", f)
+		}
+		return value
+	case FormulaTrue:
+		return true
+	case FormulaFalse:
+		return false
+	case FormulaNot:
+		return !evaluate(t, f.Left, model)
+	case FormulaAnd:
+		return evaluate(t, f.Left, model) && evaluate(t, f.Right, model)
+	case FormulaOr:
+		return evaluate(t, f.Left, model) || evaluate(t, f.Right, model)
+	case FormulaImplies:
+		return !evaluate(t, f.Left, model) || evaluate(t, f.Right, model)
+	case FormulaIff:
+		return evaluate(t, f.Left, model) == evaluate(t, f.Right, model)
+	}
+	t.Fatalf("cannot evaluate %s", f)
+	return false
+}
+
+func TestProve_FallsBackToPatterns(t *testing.T) {
+	result := NewLogicValidator().Prove([]string{"raining or sunny", "no sunny weather"}, "raining")
+	if !result.IsProvable || result.Method != ProofMethodPatterns {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestToCNF(t *testing.T) {
+	tests := map[string]string{
+		"P → Q":             "¬p ∨ q",
+		"P ↔ Q":             "¬p ∨ q; p ∨ ¬q",
+		"¬(P ∧ Q)":          "¬p ∨ ¬q",
+		"(P ∧ Q) ∨ R":       "p ∨ r; q ∨ r",
+		"P ∨ ¬P":            "",
+		"P ∧ ⊥":             "p; ⊥",
+		"(P ∨ Q) ∨ (Q ∨ P)": "p ∨ q",
+	}
+	for statement, want := range tests {
+		formula, err := ParseFormula(statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clauses, err := toCNF(formula)
+		if err != nil {
+			t.Fatalf("toCNF(%s): %v", statement, err)
+		}
+		parts := make([]string, len(clauses))
+		for i, c := range clauses {
+			parts[i] = c.String()
+		}
+		if got := strings.Join(parts, "; "); got != want {
+			t.Errorf("toCNF(%s) = %s, want %s", statement, got, want)
+		}
+	}
+}
+
+func TestToCNF_LongBiconditionalChain(t *testing.T) {
+	statement := strings.Repeat("P ↔ ", 30) + "Q"
+	formula, err := ParseFormula(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := toCNF(formula); err == nil || !strings.Contains(err.Error(), "negation normal form") {
+		t.Errorf("toCNF(%s) error = %v, want the normal form limit", statement, err)
+	}
+	NewLogicValidator().Prove([]string{statement}, "Q")
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("a %d-link chain took %v", 30, elapsed)
+	}
+}
+
+func TestDPLL(t *testing.T) {
+	p, q := literal{atom: "p"}, literal{atom: "q"}
+	notP, notQ := literal{atom: "p", negated: true}, literal{atom: "q", negated: true}
+
+	model := dpll([]clause{{p, q}, {notP, q}, {notP, notQ}}, map[string]bool{})
+	if model == nil || model["p"] || !model["q"] {
+		t.Errorf("model = %v", model)
+	}
+	if model := dpll([]clause{{p, q}, {notP, q}, {p, notQ}, {notP, notQ}}, map[string]bool{}); model != nil {
+		t.Errorf("model = %v, want unsatisfiable", model)
+	}
+}
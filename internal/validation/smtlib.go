@@ -0,0 +1,1121 @@
+package validation
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// SMT-LIB v2 scripts (https://smtlib.org). Scripts over uninterpreted sorts
+// and functions (UF, QF_UF) become theorems; scripts over linear integer and
+// real arithmetic (QF_LIA, QF_LRA, QF_LIRA) become constraint sets. Both
+// expand define-fun macros and let bindings, and read (! term :named n)
+// annotations.
+
+// sexpr is an S-expression: an atom or a list
+type sexpr struct {
+	atom   string
+	list   []*sexpr
+	isList bool
+	line   int
+}
+
+func (e *sexpr) String() string {
+	if !e.isList {
+		return e.atom
+	}
+	parts := make([]string, len(e.list))
+	for i, child := range e.list {
+		parts[i] = child.String()
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// head returns the operator of an application, or "" for atoms
+func (e *sexpr) head() string {
+	if !e.isList || len(e.list) == 0 || e.list[0].isList {
+		return ""
+	}
+	return e.list[0].atom
+}
+
+func (e *sexpr) args() []*sexpr {
+	if !e.isList || len(e.list) == 0 {
+		return nil
+	}
+	return e.list[1:]
+}
+
+// parseSExprs reads the top-level S-expressions of a script
+func parseSExprs(source string) ([]*sexpr, error) {
+	runes := []rune(source)
+	var stack [][]*sexpr
+	var starts []int
+	var top []*sexpr
+	line := 1
+	emit := func(e *sexpr) {
+		if len(stack) == 0 {
+			top = append(top, e)
+		} else {
+			stack[len(stack)-1] = append(stack[len(stack)-1], e)
+		}
+	}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == ';':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '(':
+			stack = append(stack, []*sexpr{})
+			starts = append(starts, line)
+			i++
+		case r == ')':
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced ')'", line)
+			}
+			list := &sexpr{list: stack[len(stack)-1], isList: true, line: starts[len(starts)-1]}
+			stack, starts = stack[:len(stack)-1], starts[:len(starts)-1]
+			emit(list)
+			i++
+		case r == '|' || r == '"':
+			j := i + 1
+			var b strings.Builder
+			for ; j < len(runes); j++ {
+				if runes[j] == r {
+					// "" escapes a quote inside a string literal
+					if r == '"' && j+1 < len(runes) && runes[j+1] == '"' {
+						b.WriteRune('"')
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated %c", line, r)
+			}
+			text := b.String()
+			line += strings.Count(text, "\n")
+			emit(&sexpr{atom: text, line: line})
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune("()|\";", runes[j]) {
+				j++
+			}
+			emit(&sexpr{atom: string(runes[i:j]), line: line})
+			i = j
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("line %d: unbalanced '('", starts[len(starts)-1])
+	}
+	return top, nil
+}
+
+// smtDeclaration is the signature of a declared function or constant
+type smtDeclaration struct {
+	args   []string
+	result string
+}
+
+// smtMacro is a function defined with define-fun
+type smtMacro struct {
+	params []string
+	body   *sexpr
+}
+
+type smtAssertion struct {
+	expr *sexpr
+	name string
+	line int
+}
+
+// smtScript is the declarations and assertions of a script, with macros and
+// let bindings expanded
+type smtScript struct {
+	logic     string
+	status    string
+	sorts     map[string]bool
+	functions map[string]smtDeclaration
+	order     []string // Declared functions in order of declaration
+	asserts   []*smtAssertion
+}
+
+// readSMTLIB runs the commands of a script
+func readSMTLIB(source string) (*smtScript, error) {
+	commands, err := parseSExprs(source)
+	if err != nil {
+		return nil, err
+	}
+	script := &smtScript{sorts: map[string]bool{}, functions: map[string]smtDeclaration{}}
+	macros := map[string]*smtMacro{}
+	for _, command := range commands {
+		args := command.args()
+		switch command.head() {
+		case "set-logic":
+			if len(args) == 1 {
+				script.logic = args[0].atom
+			}
+		case "set-info":
+			if len(args) == 2 && args[0].atom == ":status" {
+				script.status = args[1].atom
+			}
+		case "set-option", "check-sat", "check-sat-assuming", "get-model", "get-value", "get-unsat-core",
+			"get-proof", "get-info", "get-option", "get-assertions", "echo", "exit":
+		case "declare-sort":
+			if len(args) < 1 || args[0].isList {
+				return nil, fmt.Errorf("line %d: malformed declare-sort", command.line)
+			}
+			if len(args) == 2 && args[1].atom != "0" {
+				return nil, fmt.Errorf("line %d: sort %s has parameters, which are not supported", command.line, args[0].atom)
+			}
+			script.sorts[args[0].atom] = true
+		case "declare-const":
+			if len(args) != 2 || args[0].isList {
+				return nil, fmt.Errorf("line %d: malformed declare-const", command.line)
+			}
+			script.declare(args[0].atom, smtDeclaration{result: args[1].String()})
+		case "declare-fun":
+			if len(args) != 3 || args[0].isList || !args[1].isList {
+				return nil, fmt.Errorf("line %d: malformed declare-fun", command.line)
+			}
+			declaration := smtDeclaration{result: args[2].String()}
+			for _, sort := range args[1].list {
+				declaration.args = append(declaration.args, sort.String())
+			}
+			script.declare(args[0].atom, declaration)
+		case "define-fun":
+			if len(args) != 4 || args[0].isList || !args[1].isList {
+				return nil, fmt.Errorf("line %d: malformed define-fun", command.line)
+			}
+			macro := &smtMacro{}
+			for _, param := range args[1].list {
+				if !param.isList || len(param.list) != 2 {
+					return nil, fmt.Errorf("line %d: malformed parameter of %s", command.line, args[0].atom)
+				}
+				macro.params = append(macro.params, param.list[0].atom)
+			}
+			if macro.body, err = expandSMT(args[3], nil, macros); err != nil {
+				return nil, fmt.Errorf("line %d: %w", command.line, err)
+			}
+			macros[args[0].atom] = macro
+		case "assert":
+			if len(args) != 1 {
+				return nil, fmt.Errorf("line %d: malformed assert", command.line)
+			}
+			assertion := &smtAssertion{line: command.line}
+			expr := args[0]
+			if expr.head() == "!" {
+				for i, attribute := range expr.list {
+					if attribute.atom == ":named" && i+1 < len(expr.list) {
+						assertion.name = expr.list[i+1].atom
+					}
+				}
+			}
+			if assertion.expr, err = expandSMT(expr, nil, macros); err != nil {
+				return nil, fmt.Errorf("line %d: %w", command.line, err)
+			}
+			script.asserts = append(script.asserts, assertion)
+		case "push", "pop", "reset", "reset-assertions":
+			return nil, fmt.Errorf("line %d: %s is not supported; write the script as a single assertion stack", command.line, command.head())
+		default:
+			return nil, fmt.Errorf("line %d: unsupported command %s", command.line, command.String())
+		}
+	}
+	if len(script.asserts) == 0 {
+		return nil, fmt.Errorf("script has no assertions")
+	}
+	return script, nil
+}
+
+func (s *smtScript) declare(name string, declaration smtDeclaration) {
+	if _, ok := s.functions[name]; !ok {
+		s.order = append(s.order, name)
+	}
+	s.functions[name] = declaration
+}
+
+// expandSMT substitutes let bindings, macro parameters (env) and macro
+// applications, and drops annotations
+func expandSMT(e *sexpr, env map[string]*sexpr, macros map[string]*smtMacro) (*sexpr, error) {
+	if !e.isList {
+		if value, ok := env[e.atom]; ok {
+			return value, nil
+		}
+		if macro, ok := macros[e.atom]; ok && len(macro.params) == 0 {
+			return macro.body, nil
+		}
+		return e, nil
+	}
+	args := e.args()
+	switch e.head() {
+	case "!":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("empty annotation")
+		}
+		return expandSMT(args[0], env, macros)
+	case "let":
+		if len(args) != 2 || !args[0].isList {
+			return nil, fmt.Errorf("malformed let")
+		}
+		inner := make(map[string]*sexpr, len(env)+len(args[0].list))
+		for name, value := range env {
+			inner[name] = value
+		}
+		for _, binding := range args[0].list {
+			if !binding.isList || len(binding.list) != 2 {
+				return nil, fmt.Errorf("malformed let binding %s", binding)
+			}
+			value, err := expandSMT(binding.list[1], env, macros)
+			if err != nil {
+				return nil, err
+			}
+			inner[binding.list[0].atom] = value
+		}
+		return expandSMT(args[1], inner, macros)
+	case "forall", "exists":
+		if len(args) != 2 || !args[0].isList {
+			return nil, fmt.Errorf("malformed %s", e.head())
+		}
+		inner := make(map[string]*sexpr, len(env))
+		for name, value := range env {
+			inner[name] = value
+		}
+		for _, binding := range args[0].list {
+			if binding.isList && len(binding.list) > 0 {
+				delete(inner, binding.list[0].atom)
+			}
+		}
+		body, err := expandSMT(args[1], inner, macros)
+		if err != nil {
+			return nil, err
+		}
+		return &sexpr{list: []*sexpr{e.list[0], args[0], body}, isList: true, line: e.line}, nil
+	}
+
+	expanded := make([]*sexpr, len(e.list))
+	for i, child := range e.list {
+		var err error
+		if i == 0 && !child.isList {
+			expanded[i] = child
+			continue
+		}
+		if expanded[i], err = expandSMT(child, env, macros); err != nil {
+			return nil, err
+		}
+	}
+	if macro, ok := macros[e.head()]; ok {
+		if len(expanded)-1 != len(macro.params) {
+			return nil, fmt.Errorf("%s takes %d arguments, not %d", e.head(), len(macro.params), len(expanded)-1)
+		}
+		bindings := make(map[string]*sexpr, len(macro.params))
+		for i, param := range macro.params {
+			bindings[param] = expanded[i+1]
+		}
+		return expandSMT(macro.body, bindings, nil)
+	}
+	return &sexpr{list: expanded, isList: true, line: e.line}, nil
+}
+
+// ParseSMTLIBTheorem reads a script over uninterpreted sorts and functions as
+// a theorem. A script asserts a problem's premises and the negation of its
+// conclusion, and is unsatisfiable exactly when the conclusion follows: the
+// last assertion is negated into the conclusion and the others are the
+// premises. Equality between individuals is the predicate equal/2. The
+// status declared with (set-info :status ...) is kept.
+func ParseSMTLIBTheorem(source string) (*ImportedTheorem, error) {
+	script, err := readSMTLIB(source)
+	if err != nil {
+		return nil, err
+	}
+	if smtArithmeticLogic.MatchString(script.logic) {
+		return nil, fmt.Errorf("logic %s is arithmetic; check the script with check-constraints", script.logic)
+	}
+	formulas := make([]*Formula, len(script.asserts))
+	for i, assertion := range script.asserts {
+		if formulas[i], err = script.formula(assertion.expr, map[string]string{}); err != nil {
+			return nil, fmt.Errorf("line %d: %w", assertion.line, err)
+		}
+	}
+
+	last := formulas[len(formulas)-1]
+	conclusion := &Formula{Op: FormulaNot, Left: last}
+	if last.Op == FormulaNot {
+		conclusion = last.Left
+	}
+	theorem := &SymbolicTheorem{Conclusion: conclusion.String(), Status: StatusUnproven}
+	for _, premise := range formulas[:len(formulas)-1] {
+		theorem.Premises = append(theorem.Premises, premise.String())
+	}
+	return &ImportedTheorem{Theorem: theorem, Status: script.status}, nil
+}
+
+var smtArithmeticLogic = regexp.MustCompile(`(LIA|LRA|LIRA|NIA|NRA|IDL|RDL)$`)
+
+// Sorts that are theories rather than uninterpreted
+var smtTheorySorts = map[string]bool{"Int": true, "Real": true}
+
+// formula converts a Boolean term; bound maps quantified variables to sorts
+func (s *smtScript) formula(e *sexpr, bound map[string]string) (*Formula, error) {
+	if !e.isList {
+		switch {
+		case e.atom == "true":
+			return &Formula{Op: FormulaTrue}, nil
+		case e.atom == "false":
+			return &Formula{Op: FormulaFalse}, nil
+		case bound[e.atom] != "":
+			return nil, fmt.Errorf("quantified Boolean variable %s is not supported", e.atom)
+		}
+		declaration, ok := s.functions[e.atom]
+		if !ok || declaration.result != "Bool" || len(declaration.args) > 0 {
+			return nil, fmt.Errorf("%s is not a Boolean constant", e.atom)
+		}
+		return &Formula{Op: FormulaAtom, Name: importName(e.atom)}, nil
+	}
+
+	args := e.args()
+	operands := func() ([]*Formula, error) {
+		formulas := make([]*Formula, len(args))
+		for i, arg := range args {
+			var err error
+			if formulas[i], err = s.formula(arg, bound); err != nil {
+				return nil, err
+			}
+		}
+		return formulas, nil
+	}
+	switch op := e.head(); op {
+	case "not":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("not takes one argument")
+		}
+		operand, err := s.formula(args[0], bound)
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaNot, Left: operand}, nil
+	case "and", "or":
+		formulas, err := operands()
+		if err != nil {
+			return nil, err
+		}
+		if len(formulas) == 0 {
+			return &Formula{Op: map[string]FormulaOp{"and": FormulaTrue, "or": FormulaFalse}[op]}, nil
+		}
+		result := formulas[0]
+		for _, f := range formulas[1:] {
+			result = &Formula{Op: map[string]FormulaOp{"and": FormulaAnd, "or": FormulaOr}[op], Left: result, Right: f}
+		}
+		return result, nil
+	case "=>":
+		formulas, err := operands()
+		if err != nil {
+			return nil, err
+		}
+		if len(formulas) < 2 {
+			return nil, fmt.Errorf("=> takes at least two arguments")
+		}
+		result := formulas[len(formulas)-1]
+		for i := len(formulas) - 2; i >= 0; i-- {
+			result = &Formula{Op: FormulaImplies, Left: formulas[i], Right: result}
+		}
+		return result, nil
+	case "xor":
+		formulas, err := operands()
+		if err != nil {
+			return nil, err
+		}
+		if len(formulas) < 2 {
+			return nil, fmt.Errorf("xor takes at least two arguments")
+		}
+		result := formulas[0]
+		for _, f := range formulas[1:] {
+			result = &Formula{Op: FormulaNot, Left: &Formula{Op: FormulaIff, Left: result, Right: f}}
+		}
+		return result, nil
+	case "=", "distinct":
+		return s.equality(op, args, bound)
+	case "ite":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("ite takes three arguments")
+		}
+		formulas, err := operands()
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaAnd,
+			Left:  &Formula{Op: FormulaImplies, Left: formulas[0], Right: formulas[1]},
+			Right: &Formula{Op: FormulaImplies, Left: &Formula{Op: FormulaNot, Left: formulas[0]}, Right: formulas[2]},
+		}, nil
+	case "forall", "exists":
+		inner := make(map[string]string, len(bound)+len(args[0].list))
+		for name, sort := range bound {
+			inner[name] = sort
+		}
+		var variables []string
+		for _, binding := range args[0].list {
+			if !binding.isList || len(binding.list) != 2 {
+				return nil, fmt.Errorf("malformed binding %s", binding)
+			}
+			sort := binding.list[1].String()
+			if !s.sorts[sort] {
+				return nil, fmt.Errorf("quantifying over %s is not supported; only declared sorts are", sort)
+			}
+			inner[binding.list[0].atom] = sort
+			variables = append(variables, binding.list[0].atom)
+		}
+		body, err := s.formula(args[1], inner)
+		if err != nil {
+			return nil, err
+		}
+		quantifier := FormulaForAll
+		if op == "exists" {
+			quantifier = FormulaExists
+		}
+		for i := len(variables) - 1; i >= 0; i-- {
+			body = &Formula{Op: quantifier, Var: importName(variables[i]), Left: body}
+		}
+		return body, nil
+	case "":
+		return nil, fmt.Errorf("unsupported term %s", e)
+	}
+
+	declaration, ok := s.functions[e.head()]
+	if !ok || declaration.result != "Bool" {
+		return nil, fmt.Errorf("%s is not a predicate; arithmetic belongs in check-constraints", e)
+	}
+	if len(args) != len(declaration.args) {
+		return nil, fmt.Errorf("%s takes %d arguments, not %d", e.head(), len(declaration.args), len(args))
+	}
+	terms, err := s.terms(args, bound)
+	if err != nil {
+		return nil, err
+	}
+	return &Formula{Op: FormulaAtom, Name: importName(e.head()), Args: terms}, nil
+}
+
+// equality converts = and distinct: equivalence between formulas, the
+// predicate equal/2 between individuals
+func (s *smtScript) equality(op string, args []*sexpr, bound map[string]string) (*Formula, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%s takes at least two arguments", op)
+	}
+	boolean := s.isBool(args[0], bound)
+	equal := func(a, b *sexpr) (*Formula, error) {
+		if boolean {
+			left, err := s.formula(a, bound)
+			if err != nil {
+				return nil, err
+			}
+			right, err := s.formula(b, bound)
+			if err != nil {
+				return nil, err
+			}
+			return &Formula{Op: FormulaIff, Left: left, Right: right}, nil
+		}
+		terms, err := s.terms([]*sexpr{a, b}, bound)
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaAtom, Name: "equal", Args: terms}, nil
+	}
+
+	var parts []*Formula
+	for i := 0; i+1 < len(args); i++ {
+		if op == "=" {
+			f, err := equal(args[i], args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, f)
+			continue
+		}
+		for j := i + 1; j < len(args); j++ {
+			f, err := equal(args[i], args[j])
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, &Formula{Op: FormulaNot, Left: f})
+		}
+	}
+	return conjoin(parts), nil
+}
+
+func (s *smtScript) isBool(e *sexpr, bound map[string]string) bool {
+	if !e.isList {
+		if sort, ok := bound[e.atom]; ok {
+			return sort == "Bool"
+		}
+		return e.atom == "true" || e.atom == "false" || s.functions[e.atom].result == "Bool"
+	}
+	switch e.head() {
+	case "not", "and", "or", "=>", "xor", "=", "distinct", "forall", "exists":
+		return true
+	case "ite":
+		return len(e.list) == 4 && s.isBool(e.list[2], bound)
+	}
+	return s.functions[e.head()].result == "Bool"
+}
+
+func (s *smtScript) terms(args []*sexpr, bound map[string]string) ([]*Term, error) {
+	terms := make([]*Term, len(args))
+	for i, arg := range args {
+		if !arg.isList {
+			if _, ok := bound[arg.atom]; ok {
+				terms[i] = &Term{Name: importName(arg.atom), Variable: true}
+				continue
+			}
+		}
+		name := arg.head()
+		if !arg.isList {
+			name = arg.atom
+		}
+		declaration, ok := s.functions[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%s is not a declared function or constant", arg)
+		case declaration.result == "Bool" || smtTheorySorts[declaration.result]:
+			return nil, fmt.Errorf("%s has sort %s; only individuals of declared sorts can be arguments", arg, declaration.result)
+		case len(arg.args()) != len(declaration.args):
+			return nil, fmt.Errorf("%s takes %d arguments, not %d", name, len(declaration.args), len(arg.args()))
+		}
+		nested, err := s.terms(arg.args(), bound)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = &Term{Name: importName(name), Args: nested}
+	}
+	return terms, nil
+}
+
+// ParseSMTLIBConstraints reads a script over linear integer and real
+// arithmetic as a constraint set: a symbol per declared constant and a
+// constraint per assertion, identified by its :named annotation if it has
+// one
+func ParseSMTLIBConstraints(source string) (*ConstraintProblem, error) {
+	script, err := readSMTLIB(source)
+	if err != nil {
+		return nil, err
+	}
+	problem := &ConstraintProblem{Status: script.status}
+	if problem.Status == "unknown" {
+		problem.Status = ""
+	}
+	declared := map[string]bool{}
+	for _, name := range script.order {
+		declaration := script.functions[name]
+		domain := map[string]string{"Int": "integer", "Real": "real", "Bool": "boolean"}[declaration.result]
+		if len(declaration.args) > 0 || domain == "" {
+			return nil, fmt.Errorf("%s is not an Int, Real or Bool constant; only constants are supported in constraint problems", name)
+		}
+		declared[name] = true
+		problem.Symbols = append(problem.Symbols, &Symbol{Name: importName(name), Type: SymbolVariable, Domain: domain})
+	}
+
+	for _, assertion := range script.asserts {
+		expression, constraintType, err := script.constraint(assertion.expr, true)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", assertion.line, err)
+		}
+		problem.Constraints = append(problem.Constraints, &SymbolicConstraint{
+			ID:         assertion.name,
+			Type:       constraintType,
+			Expression: expression,
+			Symbols:    smtConstants(assertion.expr, declared),
+		})
+	}
+	return problem, nil
+}
+
+// Arithmetic comparisons and their negations
+var smtComparisons = map[string]string{"<": ">=", "<=": ">", ">": "<=", ">=": "<", "=": "!=", "!=": "="}
+
+// constraint renders an assertion as a constraint expression
+func (s *smtScript) constraint(e *sexpr, positive bool) (string, ConstraintType, error) {
+	if !e.isList {
+		switch {
+		case e.atom == "true" || e.atom == "false":
+			if (e.atom == "true") == positive {
+				return "true", ConstraintConjunction, nil
+			}
+			return "false", ConstraintConjunction, nil
+		case s.functions[e.atom].result == "Bool":
+			if positive {
+				return importName(e.atom), ConstraintConjunction, nil
+			}
+			return "not " + importName(e.atom), ConstraintNegation, nil
+		}
+		return "", "", fmt.Errorf("%s is not a Boolean", e.atom)
+	}
+
+	args := e.args()
+	op := e.head()
+	switch op {
+	case "not":
+		if len(args) != 1 {
+			return "", "", fmt.Errorf("not takes one argument")
+		}
+		return s.constraint(args[0], !positive)
+	case "and", "or", "=>":
+		if len(args) < 2 && op == "=>" {
+			return "", "", fmt.Errorf("=> takes at least two arguments")
+		}
+		parts := make([]string, len(args))
+		for i, arg := range args {
+			// a => b => c is ¬a ∨ ¬b ∨ c
+			argPositive := positive
+			if op == "=>" && i < len(args)-1 {
+				argPositive = !positive
+			}
+			part, _, err := s.constraint(arg, argPositive)
+			if err != nil {
+				return "", "", err
+			}
+			parts[i] = part
+		}
+		if len(parts) == 1 {
+			return parts[0], ConstraintConjunction, nil
+		}
+		if (op == "and") == positive {
+			return strings.Join(parts, " and "), ConstraintConjunction, nil
+		}
+		return "(" + strings.Join(parts, ") or (") + ")", ConstraintDisjunction, nil
+	case "<", "<=", ">", ">=", "=", "distinct":
+		if op == "=" && len(args) >= 2 && s.isBool(args[0], nil) {
+			return "", "", fmt.Errorf("equivalence between Booleans is not supported in constraint problems")
+		}
+		return s.comparison(op, args, positive)
+	}
+	return "", "", fmt.Errorf("%s is not supported in constraint problems", e)
+}
+
+// comparison renders a chained comparison such as (<= 0 x 10)
+func (s *smtScript) comparison(op string, args []*sexpr, positive bool) (string, ConstraintType, error) {
+	if len(args) < 2 {
+		return "", "", fmt.Errorf("%s takes at least two arguments", op)
+	}
+	terms := make([]string, len(args))
+	for i, arg := range args {
+		var err error
+		if terms[i], err = s.arithmetic(arg); err != nil {
+			return "", "", err
+		}
+	}
+
+	var parts []string
+	for i := 0; i+1 < len(terms); i++ {
+		if op == "distinct" {
+			for j := i + 1; j < len(terms); j++ {
+				comparison := "!="
+				if !positive {
+					comparison = "="
+				}
+				parts = append(parts, fmt.Sprintf("%s %s %s", terms[i], comparison, terms[j]))
+			}
+			continue
+		}
+		comparison := op
+		if !positive {
+			comparison = smtComparisons[op]
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", terms[i], comparison, terms[i+1]))
+	}
+
+	switch {
+	case len(parts) == 1 && strings.Contains(parts[0], " = "):
+		return parts[0], ConstraintEquality, nil
+	case len(parts) == 1:
+		return parts[0], ConstraintInequality, nil
+	case positive == (op != "distinct"):
+		return strings.Join(parts, " and "), ConstraintConjunction, nil
+	}
+	return "(" + strings.Join(parts, ") or (") + ")", ConstraintDisjunction, nil
+}
+
+// arithmetic renders an arithmetic term in infix notation
+func (s *smtScript) arithmetic(e *sexpr) (string, error) {
+	if !e.isList {
+		if _, err := parseDecimal(e.atom); err == nil {
+			return e.atom, nil
+		}
+		declaration, ok := s.functions[e.atom]
+		if !ok || !smtTheorySorts[declaration.result] || len(declaration.args) > 0 {
+			return "", fmt.Errorf("%s is not a numeric constant", e.atom)
+		}
+		return importName(e.atom), nil
+	}
+
+	args := e.args()
+	operands := make([]string, len(args))
+	for i, arg := range args {
+		var err error
+		if operands[i], err = s.arithmetic(arg); err != nil {
+			return "", err
+		}
+	}
+	switch op := e.head(); {
+	case op == "-" && len(operands) == 1:
+		return "-(" + operands[0] + ")", nil
+	case (op == "+" || op == "-" || op == "*" || op == "/") && len(operands) >= 2:
+		return "(" + strings.Join(operands, " "+op+" ") + ")", nil
+	case op == "to_real" && len(operands) == 1:
+		return operands[0], nil
+	}
+	return "", fmt.Errorf("%s is not supported in constraint problems", e)
+}
+
+// parseDecimal reads an SMT-LIB numeral or decimal
+func parseDecimal(text string) (*big.Rat, error) {
+	if text == "" || !unicode.IsDigit(rune(text[0])) {
+		return nil, fmt.Errorf("%s is not a number", text)
+	}
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a number", text)
+	}
+	return r, nil
+}
+
+// smtConstants lists the declared constants an assertion mentions
+func smtConstants(e *sexpr, declared map[string]bool) []string {
+	seen := map[string]bool{}
+	var names []string
+	var visit func(e *sexpr)
+	visit = func(e *sexpr) {
+		if !e.isList {
+			if declared[e.atom] && !seen[e.atom] {
+				seen[e.atom] = true
+				names = append(names, importName(e.atom))
+			}
+			return
+		}
+		for _, child := range e.list {
+			visit(child)
+		}
+	}
+	visit(e)
+	return names
+}
+
+// Writing SMT-LIB
+
+var smtSimpleSymbol = regexp.MustCompile(`^[A-Za-z~!@$%^&*_+=<>.?/-][A-Za-z0-9~!@$%^&*_+=<>.?/-]*$`)
+
+var smtReserved = map[string]bool{
+	"true": true, "false": true, "not": true, "and": true, "or": true, "xor": true, "=>": true, "=": true,
+	"distinct": true, "ite": true, "let": true, "forall": true, "exists": true, "match": true, "as": true, "par": true, "_": true, "!": true,
+}
+
+// smtName renders a symbol, quoting it between bars when needed
+func smtName(name string) string {
+	if smtSimpleSymbol.MatchString(name) && !smtReserved[name] {
+		return name
+	}
+	return "|" + strings.NewReplacer("|", "_", `\`, "_").Replace(name) + "|"
+}
+
+func smtFormula(f *Formula) string {
+	switch f.Op {
+	case FormulaAtom:
+		if len(f.Args) == 0 {
+			return smtName(f.Name)
+		}
+		return "(" + smtName(f.Name) + " " + smtTerms(f.Args) + ")"
+	case FormulaTrue:
+		return "true"
+	case FormulaFalse:
+		return "false"
+	case FormulaNot:
+		return "(not " + smtFormula(f.Left) + ")"
+	case FormulaForAll, FormulaExists:
+		return fmt.Sprintf("(%s ((%s U)) %s)", f.Op, smtName(f.Var), smtFormula(f.Left))
+	}
+	operator := map[FormulaOp]string{FormulaAnd: "and", FormulaOr: "or", FormulaImplies: "=>", FormulaIff: "="}[f.Op]
+	return fmt.Sprintf("(%s %s %s)", operator, smtFormula(f.Left), smtFormula(f.Right))
+}
+
+func smtTerms(terms []*Term) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		if len(t.Args) == 0 {
+			parts[i] = smtName(t.Name)
+		} else {
+			parts[i] = "(" + smtName(t.Name) + " " + smtTerms(t.Args) + ")"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// writeSMTLIBHeader sets the logic and status and declares the sort of
+// individuals and the predicates and functions of formulas
+func writeSMTLIBHeader(b *strings.Builder, theorem *SymbolicTheorem, status string, formulas []*Formula) error {
+	sig := newSignature()
+	quantified := false
+	for _, f := range formulas {
+		if err := sig.add(f, map[string]bool{}); err != nil {
+			return err
+		}
+		quantified = quantified || hasQuantifier(f)
+	}
+	logic := "QF_UF"
+	if quantified {
+		logic = "UF"
+	}
+	fmt.Fprintf(b, "(set-info :smt-lib-version 2.6)\n(set-logic %s)\n", logic)
+	if theorem.Name != "" {
+		fmt.Fprintf(b, "(set-info :source %s)\n", smtName(theorem.Name))
+	}
+	if status != "" {
+		fmt.Fprintf(b, "(set-info :status %s)\n", status)
+	}
+	if quantified || len(sig.functions) > 0 {
+		b.WriteString("(declare-sort U 0)\n")
+	}
+	for _, name := range sortedKeys(sig.functions) {
+		fmt.Fprintf(b, "(declare-fun %s (%s) U)\n", smtName(name), strings.TrimSpace(strings.Repeat("U ", sig.functions[name])))
+	}
+	for _, name := range sortedKeys(sig.predicates) {
+		fmt.Fprintf(b, "(declare-fun %s (%s) Bool)\n", smtName(name), strings.TrimSpace(strings.Repeat("U ", sig.predicates[name])))
+	}
+	return nil
+}
+
+func hasQuantifier(f *Formula) bool {
+	switch f.Op {
+	case FormulaForAll, FormulaExists:
+		return true
+	case FormulaAtom, FormulaTrue, FormulaFalse:
+		return false
+	case FormulaNot:
+		return hasQuantifier(f.Left)
+	}
+	return hasQuantifier(f.Left) || hasQuantifier(f.Right)
+}
+
+// ExportTheoremSMTLIB writes a theorem as a script asserting the premises and
+// the negated conclusion, which is unsatisfiable exactly when the theorem
+// holds. Individuals have the sort U.
+func ExportTheoremSMTLIB(theorem *SymbolicTheorem) (string, error) {
+	premises, conclusion, err := theoremFormulas(theorem)
+	if err != nil {
+		return "", err
+	}
+	status := "unknown"
+	switch theorem.Status {
+	case StatusProven:
+		status = "unsat"
+	case StatusRefuted:
+		status = "sat"
+	}
+	var b strings.Builder
+	if err := writeSMTLIBHeader(&b, theorem, status, append(append([]*Formula(nil), premises...), conclusion)); err != nil {
+		return "", err
+	}
+	for _, premise := range premises {
+		fmt.Fprintf(&b, "(assert %s)\n", smtFormula(premise))
+	}
+	fmt.Fprintf(&b, "(assert (not %s))\n(check-sat)\n(exit)\n", smtFormula(conclusion))
+	return b.String(), nil
+}
+
+var skolemName = regexp.MustCompile(`^sk\d+$`)
+
+// ExportProofSMTLIB writes a proof as a script that checks each inference:
+// the steps it depends on are asserted with the negation of its statement,
+// and every check-sat is expected to answer unsat. Clausification steps that
+// introduce Skolem functions are only equisatisfiable with their parents and
+// are not checked.
+func ExportProofSMTLIB(theorem *SymbolicTheorem, proof *TheoremProof) (string, error) {
+	formulas, conclusion, err := proofFormulas(theorem, proof)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := writeSMTLIBHeader(&b, theorem, "", append(append([]*Formula(nil), formulas...), conclusion)); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "; Conclusion: %s\n", strings.ReplaceAll(theorem.Conclusion, "\n", " "))
+
+	byNumber := make(map[int]*Formula, len(proof.Steps))
+	for i, step := range proof.Steps {
+		byNumber[step.StepNumber] = formulas[i]
+	}
+	for i, step := range proof.Steps {
+		fmt.Fprintf(&b, "; Step %d (%s): %s\n", step.StepNumber, step.Rule, strings.ReplaceAll(step.Statement, "\n", " "))
+		switch {
+		case len(step.Dependencies) == 0 || step.Rule == "negated_conclusion":
+			b.WriteString("; assumed\n")
+			continue
+		case step.Rule == "clausification" && hasSkolemFunction(formulas[i]):
+			b.WriteString("; introduces Skolem functions; not checked\n")
+			continue
+		}
+		b.WriteString("(push 1)\n")
+		for _, dependency := range step.Dependencies {
+			if parent, ok := byNumber[dependency]; ok {
+				fmt.Fprintf(&b, "(assert %s)\n", smtFormula(parent))
+			}
+		}
+		fmt.Fprintf(&b, "(assert (not %s))\n(check-sat) ; expect unsat\n(pop 1)\n", smtFormula(formulas[i]))
+	}
+	b.WriteString("(exit)\n")
+	return b.String(), nil
+}
+
+func hasSkolemFunction(f *Formula) bool {
+	var inTerms func(terms []*Term) bool
+	inTerms = func(terms []*Term) bool {
+		for _, t := range terms {
+			if skolemName.MatchString(t.Name) || inTerms(t.Args) {
+				return true
+			}
+		}
+		return false
+	}
+	switch f.Op {
+	case FormulaAtom:
+		return inTerms(f.Args)
+	case FormulaTrue, FormulaFalse:
+		return false
+	case FormulaNot, FormulaForAll, FormulaExists:
+		return hasSkolemFunction(f.Left)
+	}
+	return hasSkolemFunction(f.Left) || hasSkolemFunction(f.Right)
+}
+
+// ExportConstraintsSMTLIB writes the linear constraints among constraintIDs
+// as a QF_LIA, QF_LRA or QF_LIRA script with one named assertion per
+// constraint, so a solver's unsat core names the same constraints.
+// Constraints outside linear arithmetic are listed in comments. When result
+// decided every constraint, its verdict is the declared status.
+func (sr *SymbolicReasoner) ExportConstraintsSMTLIB(constraintIDs []string, result *ConsistencyResult) (string, error) {
+	export, err := sr.prepareLinearExport(constraintIDs)
+	if err != nil {
+		return "", err
+	}
+
+	hasInt, hasReal := false, false
+	for _, name := range export.symbols {
+		if export.integers[name] {
+			hasInt = true
+		} else {
+			hasReal = true
+		}
+	}
+	logic := "QF_LIA"
+	switch {
+	case hasInt && hasReal:
+		logic = "QF_LIRA"
+	case hasReal:
+		logic = "QF_LRA"
+	}
+
+	var b strings.Builder
+	b.WriteString("(set-option :produce-models true)\n(set-option :produce-unsat-cores true)\n")
+	fmt.Fprintf(&b, "(set-logic %s)\n", logic)
+	status := "unknown"
+	if result != nil && len(result.Unchecked) == 0 && len(export.skipped) == 0 {
+		status = "sat"
+		if !result.IsConsistent {
+			status = "unsat"
+		}
+	}
+	fmt.Fprintf(&b, "(set-info :status %s)\n", status)
+	for _, c := range export.skipped {
+		fmt.Fprintf(&b, "; %s is outside linear arithmetic and not exported: %s\n", c.ID, strings.ReplaceAll(c.Expression, "\n", " "))
+	}
+	for _, name := range export.symbols {
+		sort := "Real"
+		if export.integers[name] {
+			sort = "Int"
+		}
+		fmt.Fprintf(&b, "(declare-const %s %s)\n", smtName(name), sort)
+	}
+	for i, c := range export.constraints {
+		parts := make([]string, 0, len(export.rows[i]))
+		for _, row := range export.rows[i] {
+			parts = append(parts, smtRow(row, export.integers))
+		}
+		formula := "true"
+		switch {
+		case len(parts) == 1:
+			formula = parts[0]
+		case len(parts) > 1:
+			formula = "(and " + strings.Join(parts, " ") + ")"
+		}
+		fmt.Fprintf(&b, "(assert (! %s :named %s))\n", formula, smtName(c.ID))
+	}
+	b.WriteString("(check-sat)\n")
+	if status == "unsat" {
+		b.WriteString("(get-unsat-core)\n")
+	} else {
+		b.WriteString("(get-model)\n")
+	}
+	b.WriteString("(exit)\n")
+	return b.String(), nil
+}
+
+// smtRow renders Σ aᵢxᵢ ⋈ b. Rows over integers use Int numerals; other rows
+// use Real decimals with integer symbols converted by to_real.
+func smtRow(row linearRow, integers map[string]bool) string {
+	row, ok := exportRow(row, integers)
+	if !ok {
+		return "false"
+	}
+	row, reversed := orientRow(row)
+	integral := len(row.coeffs) > 0 && allIntegers(row.coeffs, integers)
+	number := func(r *big.Rat) string {
+		negative, abs := ratParts(r)
+		var text string
+		switch {
+		case integral:
+			text = abs.Num().String()
+		case abs.IsInt():
+			text = abs.Num().String() + ".0"
+		default:
+			text = fmt.Sprintf("(/ %s.0 %s.0)", abs.Num(), abs.Denom())
+		}
+		if negative {
+			return "(- " + text + ")"
+		}
+		return text
+	}
+
+	var terms []string
+	for _, name := range sortedNames(row.coeffs) {
+		variable := smtName(name)
+		if !integral && integers[name] {
+			variable = "(to_real " + variable + ")"
+		}
+		switch a := row.coeffs[name]; {
+		case a.Cmp(big.NewRat(-1, 1)) == 0:
+			variable = "(- " + variable + ")"
+		case a.Cmp(big.NewRat(1, 1)) != 0:
+			variable = fmt.Sprintf("(* %s %s)", number(a), variable)
+		}
+		terms = append(terms, variable)
+	}
+	sum := number(new(big.Rat))
+	switch {
+	case len(terms) == 1:
+		sum = terms[0]
+	case len(terms) > 1:
+		sum = "(+ " + strings.Join(terms, " ") + ")"
+	}
+	operator := map[linearOp]string{linearLE: "<=", linearLT: "<", linearEQ: "="}[row.op]
+	if reversed {
+		operator = map[linearOp]string{linearLE: ">=", linearLT: ">"}[row.op]
+	}
+	return fmt.Sprintf("(%s %s %s)", operator, sum, number(row.bound))
+}
@@ -5,6 +5,7 @@ package validation
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"unified-thinking/internal/types"
@@ -14,6 +15,7 @@ import (
 type SymbolicReasoner struct {
 	constraints map[string]*SymbolicConstraint
 	symbols     map[string]*Symbol
+	limits      ProverLimits
 }
 
 // NewSymbolicReasoner creates a new symbolic reasoner
@@ -21,9 +23,16 @@ func NewSymbolicReasoner() *SymbolicReasoner {
 	return &SymbolicReasoner{
 		constraints: make(map[string]*SymbolicConstraint),
 		symbols:     make(map[string]*Symbol),
+		limits:      DefaultProverLimits,
 	}
 }
 
+// SetProverLimits sets the bounds ProveTheorem gives the first-order
+// resolution search
+func (sr *SymbolicReasoner) SetProverLimits(limits ProverLimits) {
+	sr.limits = limits.withDefaults()
+}
+
 // Symbol represents a symbolic variable or constant
 type Symbol struct {
 	Name     string
@@ -192,12 +201,31 @@ func (sr *SymbolicReasoner) checkSatisfiability(constraint *SymbolicConstraint)
 		}
 	}
 
+	// Decide conjunctions of linear comparisons exactly
+	integers := make(map[string]bool)
+	if rows, err := sr.linearRows(constraint, integers); err == nil {
+		if outcome, _, _ := solveLinear(rows, integers); outcome == linearUnsat {
+			constraint.Explanation = "Contradiction: no assignment satisfies the linear comparisons"
+			return false
+		}
+	}
+
 	// Default: assume satisfiable
 	return true
 }
 
 // ProveTheorem attempts to prove a theorem symbolically
 func (sr *SymbolicReasoner) ProveTheorem(theorem *SymbolicTheorem) (*TheoremProof, error) {
+	return sr.ProveTheoremWithLimits(theorem, sr.limits)
+}
+
+// ProveTheoremWithLimits attempts to prove a theorem, bounding the
+// first-order search by limits. Single-step rules (modus ponens,
+// simplification, conjunction) are tried first. Otherwise the premises and
+// conclusion are formalized in first-order logic and the conclusion is proved
+// by refuting its negation with resolution; the refutation becomes numbered
+// steps. When the search stops at a limit the theorem is undecidable.
+func (sr *SymbolicReasoner) ProveTheoremWithLimits(theorem *SymbolicTheorem, limits ProverLimits) (*TheoremProof, error) {
 	proof := &TheoremProof{
 		Steps:      make([]*ProofStep, 0),
 		Method:     "natural_deduction",
@@ -222,12 +250,41 @@ func (sr *SymbolicReasoner) ProveTheorem(theorem *SymbolicTheorem) (*TheoremProo
 	// Try to derive conclusion using inference rules
 	derived := sr.attemptDerivation(theorem.Premises, theorem.Conclusion, proof, &stepNum)
 
-	if derived {
+	var search *firstOrderProof
+	if !derived {
+		search = proveFirstOrder(theorem.Premises, theorem.Conclusion, limits)
+		switch {
+		case search != nil && search.proved:
+			sr.addRefutation(theorem, search, proof, &stepNum)
+			derived = true
+		case search == nil || !search.saturated:
+			// Chaining matches statements the search could not settle
+			derived = sr.tryModusPonensChaining(theorem.Premises, theorem.Conclusion, proof, &stepNum)
+		}
+	}
+
+	switch {
+	case derived && proof.Method == ProofMethodFirstOrder:
+		proof.IsValid = true
+		proof.Confidence = 0.95
+		proof.Explanation = "The negated conclusion contradicts the premises (first-order resolution)"
+		theorem.Status = StatusProven
+	case derived:
 		proof.IsValid = true
 		proof.Confidence = 0.9
 		proof.Explanation = "Conclusion successfully derived from premises"
 		theorem.Status = StatusProven
-	} else {
+	case search != nil && search.saturated:
+		proof.Method = ProofMethodFirstOrder
+		proof.Confidence = 0.1
+		proof.Explanation = "Unable to derive conclusion from premises: resolution on the premises and the negated conclusion saturates without a contradiction, so the conclusion does not follow"
+		theorem.Status = StatusUnproven
+	case search != nil:
+		proof.Method = ProofMethodFirstOrder
+		proof.Confidence = 0.1
+		proof.Explanation = fmt.Sprintf("Unable to derive conclusion from premises within the search limits (%s)", search.limits)
+		theorem.Status = StatusUndecidable
+	default:
 		proof.IsValid = false
 		proof.Confidence = 0.1
 		proof.Explanation = "Unable to derive conclusion from premises with available rules"
@@ -274,12 +331,64 @@ func (sr *SymbolicReasoner) attemptDerivation(premises []string, conclusion stri
 		return true
 	}
 
-	// If single-step rules fail, try modus ponens chaining (multi-step)
-	if sr.tryModusPonensChaining(premises, conclusion, proof, stepNum) {
-		return true
+	return false
+}
+
+// addRefutation appends a first-order refutation to the proof: the negated
+// conclusion, the input clauses it uses, each resolvent and finally the
+// conclusion
+func (sr *SymbolicReasoner) addRefutation(theorem *SymbolicTheorem, search *firstOrderProof, proof *TheoremProof, stepNum *int) {
+	premises := len(theorem.Premises)
+	negated := *stepNum
+	proof.Steps = append(proof.Steps, &ProofStep{
+		StepNumber:    negated,
+		Statement:     (&Formula{Op: FormulaNot, Left: search.formulas[premises]}).String(),
+		Justification: "Assume the conclusion is false",
+		Rule:          "negated_conclusion",
+		Dependencies:  []int{},
+	})
+	*stepNum++
+
+	numbers := make([]int, len(search.steps))
+	number := func(i int) int { return numbers[i] }
+	for i, step := range search.steps {
+		numbers[i] = *stepNum
+		var justification string
+		var dependencies []int
+		switch {
+		case step.rule == "clausification" && step.source == premises:
+			justification = fmt.Sprintf("Clause of step %d", negated)
+			dependencies = []int{negated}
+		case step.rule == "clausification":
+			// Premises are the first steps of the proof
+			justification = fmt.Sprintf("Clause of premise %d: %s", step.source+1, search.formulas[step.source])
+			dependencies = []int{step.source + 1}
+		case step.rule == "factoring":
+			justification = fmt.Sprintf("Factor step %d", numbers[step.parents[0]]) + step.unifier(number)
+			dependencies = []int{numbers[step.parents[0]]}
+		default:
+			justification = fmt.Sprintf("Resolve steps %d and %d", numbers[step.parents[0]], numbers[step.parents[1]]) + step.unifier(number)
+			dependencies = []int{numbers[step.parents[0]], numbers[step.parents[1]]}
+		}
+		proof.Steps = append(proof.Steps, &ProofStep{
+			StepNumber:    *stepNum,
+			Statement:     step.clause.String(),
+			Justification: justification,
+			Rule:          step.rule,
+			Dependencies:  dependencies,
+		})
+		*stepNum++
 	}
 
-	return false
+	proof.Steps = append(proof.Steps, &ProofStep{
+		StepNumber:    *stepNum,
+		Statement:     theorem.Conclusion,
+		Justification: fmt.Sprintf("The negated conclusion leads to a contradiction (step %d)", *stepNum-1),
+		Rule:          "refutation",
+		Dependencies:  []int{*stepNum - 1},
+	})
+	*stepNum++
+	proof.Method = ProofMethodFirstOrder
 }
 
 // tryModusPonensSymbolic attempts modus ponens inference
@@ -583,7 +692,13 @@ func (sr *SymbolicReasoner) statementsMatch(s1, s2 string) bool {
 	return s1Norm == s2Norm || strings.Contains(s1Norm, s2Norm) || strings.Contains(s2Norm, s1Norm)
 }
 
-// CheckConstraintConsistency checks if a set of constraints are mutually consistent
+// CheckConstraintConsistency checks if a set of constraints are mutually
+// consistent. Constraints that are conjunctions of linear comparisons over
+// integer or real symbols are decided together by the linear arithmetic
+// solver: a consistent set comes with a witness assignment and an
+// inconsistent one with a minimal unsatisfiable core. Other constraints, and
+// linear ones the solver cannot decide within its bounds, are compared
+// pairwise.
 func (sr *SymbolicReasoner) CheckConstraintConsistency(constraintIDs []string) (*ConsistencyResult, error) {
 	result := &ConsistencyResult{
 		IsConsistent: true,
@@ -599,11 +714,65 @@ func (sr *SymbolicReasoner) CheckConstraintConsistency(constraintIDs []string) (
 		}
 	}
 
-	// Check pairwise consistency
+	// Decide the linear constraints together
+	var linear []*SymbolicConstraint
+	var rows []linearRow
+	integers := make(map[string]bool)
+	decided := make(map[string]bool)
+	example := ""
+	for _, c := range constraints {
+		constraintRows, err := sr.linearRows(c, integers)
+		if err != nil {
+			result.Unchecked = append(result.Unchecked, c.ID)
+			continue
+		}
+		for _, row := range constraintRows {
+			row.source = len(linear)
+			rows = append(rows, row)
+		}
+		linear = append(linear, c)
+	}
+	if len(linear) > 0 {
+		outcome, witness, core := solveLinear(rows, integers)
+		switch outcome {
+		case linearSat:
+			result.Witness = make(map[string]float64, len(witness))
+			assignments := make([]string, 0, len(witness))
+			for name, value := range witness {
+				result.Witness[name], _ = value.Float64()
+				assignments = append(assignments, fmt.Sprintf("%s = %s", name, formatRat(value)))
+			}
+			sort.Strings(assignments)
+			example = strings.Join(assignments, ", ")
+		case linearUnsat:
+			core = minimalLinearCore(rows, integers, core)
+			coreConstraints := make([]*SymbolicConstraint, len(core))
+			for i, source := range core {
+				coreConstraints[i] = linear[source]
+				result.UnsatCore = append(result.UnsatCore, linear[source].ID)
+			}
+			result.IsConsistent = false
+			result.Conflicts = append(result.Conflicts, coreConflict(coreConstraints))
+		default:
+			for _, c := range linear {
+				result.Unchecked = append(result.Unchecked, c.ID)
+			}
+		}
+		if outcome != linearUnknown {
+			for _, c := range linear {
+				decided[c.ID] = true
+			}
+		}
+	}
+
+	// Check the remaining pairs pairwise
 	for i := 0; i < len(constraints); i++ {
 		for j := i + 1; j < len(constraints); j++ {
 			c1 := constraints[i]
 			c2 := constraints[j]
+			if decided[c1.ID] && decided[c2.ID] {
+				continue
+			}
 
 			if conflict := sr.detectConflict(c1, c2); conflict != nil {
 				result.IsConsistent = false
@@ -614,11 +783,72 @@ func (sr *SymbolicReasoner) CheckConstraintConsistency(constraintIDs []string) (
 
 	if !result.IsConsistent {
 		result.Explanation = fmt.Sprintf("Found %d conflicts between constraints", len(result.Conflicts))
+		if len(result.UnsatCore) > 0 {
+			result.Explanation += fmt.Sprintf("; minimal unsatisfiable core: %s", strings.Join(result.UnsatCore, ", "))
+		}
+	} else if example != "" {
+		result.Explanation += ", e.g. " + example
+	}
+	if len(result.Unchecked) > 0 {
+		result.Explanation += fmt.Sprintf(" (%d constraints outside linear arithmetic were only compared pairwise)", len(result.Unchecked))
 	}
 
 	return result, nil
 }
 
+// linearRows parses a constraint as linear comparisons over registered
+// integer or real symbols, marking the integer ones in integers
+func (sr *SymbolicReasoner) linearRows(c *SymbolicConstraint, integers map[string]bool) ([]linearRow, error) {
+	rows, err := parseLinearConstraint(c.Expression)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for name := range row.coeffs {
+			symbol, exists := sr.symbols[name]
+			if !exists {
+				return nil, fmt.Errorf("%s is not a registered symbol", name)
+			}
+			switch strings.ToLower(symbol.Domain) {
+			case "integer", "int":
+				integers[name] = true
+			case "real", "float", "number", "rational", "":
+			default:
+				return nil, fmt.Errorf("symbol %s has non-numeric domain %s", name, symbol.Domain)
+			}
+		}
+	}
+	return rows, nil
+}
+
+// coreConflict describes a minimal unsatisfiable core
+func coreConflict(core []*SymbolicConstraint) *ConstraintConflict {
+	conflict := &ConstraintConflict{ConflictType: "unsatisfiable_core"}
+	described := make([]string, len(core))
+	for i, c := range core {
+		conflict.Constraints = append(conflict.Constraints, c.ID)
+		described[i] = fmt.Sprintf("%s (%s)", c.ID, c.Expression)
+	}
+	conflict.Constraint1 = core[0].ID
+
+	switch len(core) {
+	case 1:
+		conflict.Explanation = fmt.Sprintf("Constraint %s has no solution", described[0])
+		return conflict
+	case 2:
+		conflict.Constraint2 = core[1].ID
+		if core[0].Type == core[1].Type && (core[0].Type == ConstraintEquality || core[0].Type == ConstraintInequality) {
+			conflict.ConflictType = string(core[0].Type) + "_conflict"
+		}
+		conflict.Explanation = fmt.Sprintf("Constraints %s and %s cannot both hold", described[0], described[1])
+		return conflict
+	}
+	conflict.Constraint2 = core[1].ID
+	conflict.Explanation = fmt.Sprintf("Constraints %s and %s cannot all hold",
+		strings.Join(described[:len(described)-1], ", "), described[len(described)-1])
+	return conflict
+}
+
 // detectConflict checks if two constraints conflict
 func (sr *SymbolicReasoner) detectConflict(c1, c2 *SymbolicConstraint) *ConstraintConflict {
 	// Check if constraints share symbols
@@ -828,12 +1058,16 @@ type ConsistencyResult struct {
 	IsConsistent bool
 	Conflicts    []*ConstraintConflict
 	Explanation  string
+	Witness      map[string]float64 // Satisfying assignment of the linear symbols
+	UnsatCore    []string           // Minimal set of linear constraints that cannot hold together
+	Unchecked    []string           // Constraints only compared pairwise
 }
 
 // ConstraintConflict represents a conflict between constraints
 type ConstraintConflict struct {
 	Constraint1  string
 	Constraint2  string
+	Constraints  []string // Every constraint involved, for conflicts of more than two
 	ConflictType string
 	Explanation  string
 }
@@ -0,0 +1,1137 @@
+package validation
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TPTP problems (https://www.tptp.org): annotated formulas
+// lang(name, role, formula[, annotations]). FOF and CNF formulas become
+// theorems; TFF formulas over $int, $rat and $real become linear constraint
+// sets. Equality is read as the uninterpreted predicate equal/2, since the
+// resolution prover has no equality reasoning.
+
+type tptpTokenKind int
+
+const (
+	tptpEOF tptpTokenKind = iota
+	tptpLower
+	tptpUpper
+	tptpDollar
+	tptpQuoted
+	tptpDistinct
+	tptpNumber
+	tptpPunct
+)
+
+type tptpToken struct {
+	kind tptpTokenKind
+	text string
+	line int
+}
+
+// Punctuation and connectives, longest first
+var tptpOperators = []string{"<=>", "<~>", "=>", "<=", "~|", "~&", "!=", "(", ")", "[", "]", ",", ".", ":", "~", "&", "|", "=", "!", "?", "*", ">"}
+
+func lexTPTP(source string) ([]tptpToken, error) {
+	runes := []rune(source)
+	var tokens []tptpToken
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '%':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := line
+			for i += 2; i+1 < len(runes) && (runes[i] != '*' || runes[i+1] != '/'); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+			i += 2
+		case r == '\'' || r == '"':
+			j := i + 1
+			var b strings.Builder
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+				j++
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated quote", line)
+			}
+			kind := tptpQuoted
+			if r == '"' {
+				kind = tptpDistinct
+			}
+			tokens = append(tokens, tptpToken{kind, b.String(), line})
+			i = j + 1
+		case unicode.IsDigit(r) || ((r == '-' || r == '+') && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || strings.ContainsRune("./Ee", runes[j]) ||
+				((runes[j] == '-' || runes[j] == '+') && (runes[j-1] == 'E' || runes[j-1] == 'e'))) {
+				j++
+			}
+			tokens = append(tokens, tptpToken{tptpNumber, string(runes[i:j]), line})
+			i = j
+		case unicode.IsLetter(r) || r == '$' || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '$') {
+				j++
+			}
+			kind := tptpLower
+			switch {
+			case r == '$':
+				kind = tptpDollar
+			case unicode.IsUpper(r) || r == '_':
+				kind = tptpUpper
+			}
+			tokens = append(tokens, tptpToken{kind, string(runes[i:j]), line})
+			i = j
+		default:
+			matched := ""
+			for _, op := range tptpOperators {
+				if strings.HasPrefix(string(runes[i:min(i+3, len(runes))]), op) {
+					matched = op
+					break
+				}
+			}
+			if matched == "" {
+				return nil, fmt.Errorf("line %d: unexpected character %q", line, r)
+			}
+			tokens = append(tokens, tptpToken{tptpPunct, matched, line})
+			i += len([]rune(matched))
+		}
+	}
+	return append(tokens, tptpToken{kind: tptpEOF, line: line}), nil
+}
+
+// tptpNode is a TPTP formula or term
+type tptpNode struct {
+	op       string // "atom", "var", "number", "~", a binary connective, "!", "?", "=" or "!="
+	name     string // Functor, variable or number
+	args     []*tptpNode
+	vars     []string // Quantified variables
+	distinct bool     // "distinct object"
+}
+
+// tptpAnnotated is one annotated formula; type declarations set symbol and
+// symbolType instead of formula
+type tptpAnnotated struct {
+	language   string
+	name       string
+	role       string
+	formula    *tptpNode
+	symbol     string
+	symbolType string
+	line       int
+}
+
+type tptpParser struct {
+	tokens []tptpToken
+	pos    int
+}
+
+// parseTPTP reads the annotated formulas of a TPTP problem
+func parseTPTP(source string) ([]*tptpAnnotated, error) {
+	tokens, err := lexTPTP(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &tptpParser{tokens: tokens}
+	var formulas []*tptpAnnotated
+	for p.peek().kind != tptpEOF {
+		annotated, err := p.annotated()
+		if err != nil {
+			return nil, err
+		}
+		formulas = append(formulas, annotated)
+	}
+	if len(formulas) == 0 {
+		return nil, fmt.Errorf("problem has no formulas")
+	}
+	return formulas, nil
+}
+
+func (p *tptpParser) peek() tptpToken {
+	return p.tokens[p.pos]
+}
+
+func (p *tptpParser) next() tptpToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tptpEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *tptpParser) is(text string) bool {
+	tok := p.peek()
+	return tok.kind == tptpPunct && tok.text == text
+}
+
+func (p *tptpParser) expect(text string) error {
+	if !p.is(text) {
+		return p.unexpected(fmt.Sprintf("%q", text))
+	}
+	p.next()
+	return nil
+}
+
+func (p *tptpParser) unexpected(want string) error {
+	tok := p.peek()
+	if tok.kind == tptpEOF {
+		return fmt.Errorf("line %d: expected %s but the problem ended", tok.line, want)
+	}
+	return fmt.Errorf("line %d: expected %s but found %q", tok.line, want, tok.text)
+}
+
+func (p *tptpParser) annotated() (*tptpAnnotated, error) {
+	start := p.next()
+	switch {
+	case start.kind == tptpLower && start.text == "include":
+		return nil, fmt.Errorf("line %d: include directives are not supported; inline the axioms", start.line)
+	case start.kind != tptpLower || (start.text != "fof" && start.text != "cnf" && start.text != "tff"):
+		return nil, fmt.Errorf("line %d: expected fof, cnf or tff but found %q", start.line, start.text)
+	}
+	a := &tptpAnnotated{language: start.text, line: start.line}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	name := p.next()
+	if name.kind != tptpLower && name.kind != tptpQuoted && name.kind != tptpNumber {
+		return nil, fmt.Errorf("line %d: expected a formula name but found %q", name.line, name.text)
+	}
+	a.name = name.text
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	role := p.next()
+	if role.kind != tptpLower {
+		return nil, fmt.Errorf("line %d: expected a role but found %q", role.line, role.text)
+	}
+	a.role = role.text
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if a.role == "type" {
+		err = p.typeDeclaration(a)
+	} else {
+		a.formula, err = p.logicFormula()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip annotations
+	if p.is(",") {
+		depth := 0
+		for {
+			tok := p.next()
+			switch {
+			case tok.kind == tptpEOF:
+				return nil, fmt.Errorf("line %d: unterminated annotations", tok.line)
+			case tok.kind == tptpPunct && (tok.text == "(" || tok.text == "["):
+				depth++
+			case tok.kind == tptpPunct && (tok.text == ")" || tok.text == "]"):
+				depth--
+			}
+			if depth < 0 {
+				p.pos--
+				break
+			}
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return a, p.expect(".")
+}
+
+// typeDeclaration reads "name: type" of a TFF type declaration; function
+// types are accepted and recorded by their result type
+func (p *tptpParser) typeDeclaration(a *tptpAnnotated) error {
+	parens := 0
+	for p.is("(") {
+		p.next()
+		parens++
+	}
+	name := p.next()
+	if name.kind != tptpLower && name.kind != tptpQuoted {
+		return fmt.Errorf("line %d: expected a symbol in the type declaration but found %q", name.line, name.text)
+	}
+	a.symbol = name.text
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+	depth := 0
+	for {
+		tok := p.peek()
+		if tok.kind == tptpEOF || (depth == 0 && parens == 0 && tok.kind == tptpPunct && (tok.text == ")" || tok.text == ",")) {
+			break
+		}
+		p.next()
+		switch {
+		case tok.kind == tptpPunct && tok.text == "(":
+			depth++
+		case tok.kind == tptpPunct && tok.text == ")" && depth > 0:
+			depth--
+		case tok.kind == tptpPunct && tok.text == ")":
+			parens--
+		case tok.kind == tptpPunct && tok.text == ">":
+			a.symbolType = ""
+		case tok.kind == tptpDollar || tok.kind == tptpLower:
+			a.symbolType = tok.text
+		}
+	}
+	return nil
+}
+
+// Binary connectives that do not associate
+var tptpBinary = map[string]bool{"<=>": true, "<~>": true, "=>": true, "<=": true, "~|": true, "~&": true}
+
+func (p *tptpParser) logicFormula() (*tptpNode, error) {
+	left, err := p.unitary()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	switch {
+	case tok.kind == tptpPunct && tptpBinary[tok.text]:
+		p.next()
+		right, err := p.unitary()
+		if err != nil {
+			return nil, err
+		}
+		return &tptpNode{op: tok.text, args: []*tptpNode{left, right}}, nil
+	case tok.kind == tptpPunct && (tok.text == "&" || tok.text == "|"):
+		for p.is(tok.text) {
+			p.next()
+			right, err := p.unitary()
+			if err != nil {
+				return nil, err
+			}
+			left = &tptpNode{op: tok.text, args: []*tptpNode{left, right}}
+		}
+	}
+	return left, nil
+}
+
+func (p *tptpParser) unitary() (*tptpNode, error) {
+	switch {
+	case p.is("!") || p.is("?"):
+		quantifier := p.next().text
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		node := &tptpNode{op: quantifier}
+		for {
+			variable := p.next()
+			if variable.kind != tptpUpper {
+				return nil, fmt.Errorf("line %d: expected a variable but found %q", variable.line, variable.text)
+			}
+			node.vars = append(node.vars, variable.text)
+			if p.is(":") {
+				// Typed variable in TFF
+				p.next()
+				p.next()
+			}
+			if !p.is(",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		body, err := p.unitary()
+		if err != nil {
+			return nil, err
+		}
+		node.args = []*tptpNode{body}
+		return node, nil
+	case p.is("~"):
+		p.next()
+		operand, err := p.unitary()
+		if err != nil {
+			return nil, err
+		}
+		return &tptpNode{op: "~", args: []*tptpNode{operand}}, nil
+	case p.is("("):
+		p.next()
+		inner, err := p.logicFormula()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	if p.is("=") || p.is("!=") {
+		op := p.next().text
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		return &tptpNode{op: op, args: []*tptpNode{left, right}}, nil
+	}
+	return left, nil
+}
+
+func (p *tptpParser) term() (*tptpNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tptpUpper:
+		return &tptpNode{op: "var", name: tok.text}, nil
+	case tptpNumber:
+		return &tptpNode{op: "number", name: tok.text}, nil
+	case tptpDistinct:
+		return &tptpNode{op: "atom", name: tok.text, distinct: true}, nil
+	case tptpLower, tptpQuoted, tptpDollar:
+		node := &tptpNode{op: "atom", name: tok.text}
+		if !p.is("(") {
+			return node, nil
+		}
+		p.next()
+		for {
+			arg, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			node.args = append(node.args, arg)
+			if !p.is(",") {
+				break
+			}
+			p.next()
+		}
+		return node, p.expect(")")
+	}
+	p.pos--
+	return nil, p.unexpected("a term")
+}
+
+var tptpStatusPattern = regexp.MustCompile(`(?m)^%\s*Status\s*:\s*(\w+)`)
+var tptpFilePattern = regexp.MustCompile(`(?m)^%\s*File\s*:\s*(\S+)`)
+
+// ParseTPTPTheorem reads a FOF or CNF problem as a theorem. Conjectures are
+// conjoined into the conclusion; negated conjectures (CNF refutations) are
+// read as the negation of the conclusion. A problem without either asks
+// whether its axioms are contradictory: the last axiom is negated into the
+// conclusion, which is provable exactly when the axioms are unsatisfiable.
+// The status declared in a "% Status :" header line is kept.
+func ParseTPTPTheorem(source string) (*ImportedTheorem, error) {
+	annotated, err := parseTPTP(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var premises, conjectures, negated []*Formula
+	for _, a := range annotated {
+		if a.language == "tff" {
+			return nil, fmt.Errorf("line %d: tff formulas describe arithmetic constraints; check them with check-constraints", a.line)
+		}
+		formula, err := tptpToFormula(a.formula, map[string]bool{})
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", a.line, err)
+		}
+		if free := tptpFreeVariables(a.formula); len(free) > 0 {
+			formula = universalClosure(formula, free)
+		}
+		switch a.role {
+		case "conjecture":
+			conjectures = append(conjectures, formula)
+		case "negated_conjecture":
+			negated = append(negated, formula)
+		default:
+			premises = append(premises, formula)
+		}
+	}
+
+	var conclusion *Formula
+	switch {
+	case len(conjectures) > 0:
+		conclusion = conjoin(conjectures)
+		premises = append(premises, negated...)
+	case len(negated) > 0:
+		conclusion = &Formula{Op: FormulaNot, Left: conjoin(negated)}
+	case len(premises) > 0:
+		conclusion = &Formula{Op: FormulaNot, Left: premises[len(premises)-1]}
+		premises = premises[:len(premises)-1]
+	}
+
+	theorem := &SymbolicTheorem{Conclusion: conclusion.String(), Status: StatusUnproven}
+	for _, premise := range premises {
+		theorem.Premises = append(theorem.Premises, premise.String())
+	}
+	imported := &ImportedTheorem{Theorem: theorem}
+	if m := tptpFilePattern.FindStringSubmatch(source); m != nil {
+		theorem.Name = m[1]
+	}
+	if m := tptpStatusPattern.FindStringSubmatch(source); m != nil {
+		imported.Status = m[1]
+	}
+	return imported, nil
+}
+
+// tptpToFormula converts a FOF or CNF formula
+func tptpToFormula(n *tptpNode, bound map[string]bool) (*Formula, error) {
+	switch n.op {
+	case "atom":
+		switch n.name {
+		case "$true":
+			return &Formula{Op: FormulaTrue}, nil
+		case "$false":
+			return &Formula{Op: FormulaFalse}, nil
+		}
+		if strings.HasPrefix(n.name, "$") {
+			return nil, fmt.Errorf("%s is not supported in first-order problems", n.name)
+		}
+		args, err := tptpTerms(n.args)
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaAtom, Name: importName(n.name), Args: bindTerms(args, bound)}, nil
+	case "=", "!=":
+		args, err := tptpTerms(n.args)
+		if err != nil {
+			return nil, err
+		}
+		equal := &Formula{Op: FormulaAtom, Name: "equal", Args: bindTerms(args, bound)}
+		if n.op == "!=" {
+			return &Formula{Op: FormulaNot, Left: equal}, nil
+		}
+		return equal, nil
+	case "~":
+		operand, err := tptpToFormula(n.args[0], bound)
+		if err != nil {
+			return nil, err
+		}
+		return &Formula{Op: FormulaNot, Left: operand}, nil
+	case "!", "?":
+		inner := make(map[string]bool, len(bound)+len(n.vars))
+		for name := range bound {
+			inner[name] = true
+		}
+		for _, name := range n.vars {
+			inner[name] = true
+		}
+		body, err := tptpToFormula(n.args[0], inner)
+		if err != nil {
+			return nil, err
+		}
+		op := FormulaForAll
+		if n.op == "?" {
+			op = FormulaExists
+		}
+		for i := len(n.vars) - 1; i >= 0; i-- {
+			body = &Formula{Op: op, Var: n.vars[i], Left: body}
+		}
+		return body, nil
+	case "var", "number":
+		return nil, fmt.Errorf("%s is a term, not a formula", n.name)
+	}
+
+	left, err := tptpToFormula(n.args[0], bound)
+	if err != nil {
+		return nil, err
+	}
+	right, err := tptpToFormula(n.args[1], bound)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "&":
+		return &Formula{Op: FormulaAnd, Left: left, Right: right}, nil
+	case "|":
+		return &Formula{Op: FormulaOr, Left: left, Right: right}, nil
+	case "=>":
+		return &Formula{Op: FormulaImplies, Left: left, Right: right}, nil
+	case "<=":
+		return &Formula{Op: FormulaImplies, Left: right, Right: left}, nil
+	case "<=>":
+		return &Formula{Op: FormulaIff, Left: left, Right: right}, nil
+	case "<~>":
+		return &Formula{Op: FormulaNot, Left: &Formula{Op: FormulaIff, Left: left, Right: right}}, nil
+	case "~|":
+		return &Formula{Op: FormulaNot, Left: &Formula{Op: FormulaOr, Left: left, Right: right}}, nil
+	}
+	return &Formula{Op: FormulaNot, Left: &Formula{Op: FormulaAnd, Left: left, Right: right}}, nil
+}
+
+func tptpTerms(nodes []*tptpNode) ([]*Term, error) {
+	terms := make([]*Term, len(nodes))
+	for i, n := range nodes {
+		switch n.op {
+		case "var":
+			terms[i] = &Term{Name: n.name}
+		case "number", "atom":
+			if strings.HasPrefix(n.name, "$") {
+				return nil, fmt.Errorf("%s is not supported in first-order problems", n.name)
+			}
+			args, err := tptpTerms(n.args)
+			if err != nil {
+				return nil, err
+			}
+			terms[i] = &Term{Name: importName(n.name), Args: args}
+		default:
+			return nil, fmt.Errorf("expected a term but found a formula")
+		}
+	}
+	return terms, nil
+}
+
+// tptpFreeVariables lists the variables a formula does not quantify, in
+// order of appearance; CNF clauses quantify none
+func tptpFreeVariables(n *tptpNode) []string {
+	seen := map[string]bool{}
+	var free []string
+	var visit func(n *tptpNode, bound map[string]bool)
+	visit = func(n *tptpNode, bound map[string]bool) {
+		if n.op == "var" && !bound[n.name] && !seen[n.name] {
+			seen[n.name] = true
+			free = append(free, n.name)
+		}
+		if n.op == "!" || n.op == "?" {
+			inner := make(map[string]bool, len(bound)+len(n.vars))
+			for name := range bound {
+				inner[name] = true
+			}
+			for _, name := range n.vars {
+				inner[name] = true
+			}
+			bound = inner
+		}
+		for _, arg := range n.args {
+			visit(arg, bound)
+		}
+	}
+	visit(n, map[string]bool{})
+	return free
+}
+
+// ParseTPTPConstraints reads a TFF problem over $int, $rat and $real as a
+// constraint set. Declared constants become symbols; each axiom or
+// hypothesis becomes a constraint and a conjecture is negated, so the set is
+// unsatisfiable exactly when the conjecture is a theorem.
+func ParseTPTPConstraints(source string) (*ConstraintProblem, error) {
+	annotated, err := parseTPTP(source)
+	if err != nil {
+		return nil, err
+	}
+	problem := &ConstraintProblem{}
+	if m := tptpFilePattern.FindStringSubmatch(source); m != nil {
+		problem.Name = m[1]
+	}
+	if m := tptpStatusPattern.FindStringSubmatch(source); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "theorem", "unsatisfiable":
+			problem.Status = "unsat"
+		case "countersatisfiable", "satisfiable":
+			problem.Status = "sat"
+		}
+	}
+
+	declared := map[string]bool{}
+	for _, a := range annotated {
+		if a.role != "type" {
+			continue
+		}
+		domain := ""
+		switch a.symbolType {
+		case "$int":
+			domain = "integer"
+		case "$rat", "$real":
+			domain = "real"
+		case "$o":
+			domain = "boolean"
+		case "$tType", "":
+			continue
+		default:
+			domain = strings.TrimPrefix(a.symbolType, "$")
+		}
+		declared[a.symbol] = true
+		problem.Symbols = append(problem.Symbols, &Symbol{Name: importName(a.symbol), Type: SymbolVariable, Domain: domain})
+	}
+
+	for _, a := range annotated {
+		if a.role == "type" {
+			continue
+		}
+		formula := a.formula
+		if a.role == "conjecture" {
+			formula = &tptpNode{op: "~", args: []*tptpNode{formula}}
+		}
+		expression, constraintType, err := tptpConstraint(formula, true)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", a.line, err)
+		}
+		problem.Constraints = append(problem.Constraints, &SymbolicConstraint{
+			ID:         a.name,
+			Type:       constraintType,
+			Expression: expression,
+			Symbols:    tptpConstants(formula, declared),
+		})
+	}
+	if len(problem.Constraints) == 0 {
+		return nil, fmt.Errorf("problem has no constraints")
+	}
+	return problem, nil
+}
+
+// Arithmetic comparisons of TFF and their negations
+var tptpComparisons = map[string][2]string{
+	"$less":      {"<", ">="},
+	"$lesseq":    {"<=", ">"},
+	"$greater":   {">", "<="},
+	"$greatereq": {">=", "<"},
+	"=":          {"=", "!="},
+	"!=":         {"!=", "="},
+}
+
+// tptpConstraint renders a TFF formula as a constraint expression
+func tptpConstraint(n *tptpNode, positive bool) (string, ConstraintType, error) {
+	comparison, isComparison := tptpComparisons[n.name]
+	if n.op == "=" || n.op == "!=" {
+		comparison, isComparison = tptpComparisons[n.op]
+	}
+	switch {
+	case isComparison && len(n.args) == 2:
+		left, err := tptpArithmetic(n.args[0])
+		if err != nil {
+			return "", "", err
+		}
+		right, err := tptpArithmetic(n.args[1])
+		if err != nil {
+			return "", "", err
+		}
+		op := comparison[0]
+		if !positive {
+			op = comparison[1]
+		}
+		constraintType := ConstraintInequality
+		if op == "=" {
+			constraintType = ConstraintEquality
+		}
+		return fmt.Sprintf("%s %s %s", left, op, right), constraintType, nil
+	case n.op == "~":
+		return tptpConstraint(n.args[0], !positive)
+	case n.op == "&" || n.op == "|":
+		left, _, err := tptpConstraint(n.args[0], positive)
+		if err != nil {
+			return "", "", err
+		}
+		right, _, err := tptpConstraint(n.args[1], positive)
+		if err != nil {
+			return "", "", err
+		}
+		// De Morgan: a negated conjunction is a disjunction
+		if (n.op == "&") == positive {
+			return left + " and " + right, ConstraintConjunction, nil
+		}
+		return fmt.Sprintf("(%s) or (%s)", left, right), ConstraintDisjunction, nil
+	case n.op == "atom" && len(n.args) == 0 && (n.name == "$true" || n.name == "$false"):
+		if (n.name == "$true") == positive {
+			return "true", ConstraintConjunction, nil
+		}
+		return "false", ConstraintConjunction, nil
+	case n.op == "atom" && len(n.args) == 0:
+		if positive {
+			return importName(n.name), ConstraintConjunction, nil
+		}
+		return "not " + importName(n.name), ConstraintNegation, nil
+	case n.op == "!" || n.op == "?":
+		return "", "", fmt.Errorf("quantified arithmetic is not supported")
+	}
+	return "", "", fmt.Errorf("%s is not supported in constraint problems", tptpDescribe(n))
+}
+
+// TFF arithmetic functions and the operators they render as
+var tptpArithmeticOperators = map[string]string{
+	"$sum":        "+",
+	"$difference": "-",
+	"$product":    "*",
+	"$quotient":   "/",
+}
+
+// tptpArithmetic renders a TFF arithmetic term
+func tptpArithmetic(n *tptpNode) (string, error) {
+	switch {
+	case n.op == "number":
+		return n.name, nil
+	case n.op == "atom" && len(n.args) == 0 && !strings.HasPrefix(n.name, "$"):
+		return importName(n.name), nil
+	case n.op == "atom" && len(n.args) == 1 && (n.name == "$to_rat" || n.name == "$to_real"):
+		return tptpArithmetic(n.args[0])
+	case n.op == "atom" && len(n.args) == 1 && n.name == "$uminus":
+		operand, err := tptpArithmetic(n.args[0])
+		return "-(" + operand + ")", err
+	case n.op == "atom" && len(n.args) == 2 && tptpArithmeticOperators[n.name] != "":
+		left, err := tptpArithmetic(n.args[0])
+		if err != nil {
+			return "", err
+		}
+		right, err := tptpArithmetic(n.args[1])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s %s %s)", left, tptpArithmeticOperators[n.name], right), nil
+	}
+	return "", fmt.Errorf("%s is not supported in constraint problems", tptpDescribe(n))
+}
+
+func tptpDescribe(n *tptpNode) string {
+	switch n.op {
+	case "atom", "var", "number":
+		return n.name
+	}
+	return fmt.Sprintf("the connective %q", n.op)
+}
+
+// tptpConstants lists the declared constants a formula mentions
+func tptpConstants(n *tptpNode, declared map[string]bool) []string {
+	seen := map[string]bool{}
+	var names []string
+	var visit func(n *tptpNode)
+	visit = func(n *tptpNode) {
+		if n.op == "atom" && len(n.args) == 0 && declared[n.name] && !seen[n.name] {
+			seen[n.name] = true
+			names = append(names, importName(n.name))
+		}
+		for _, arg := range n.args {
+			visit(arg)
+		}
+	}
+	visit(n)
+	return names
+}
+
+// Writing TPTP
+
+var tptpLowerWord = regexp.MustCompile(`^[a-z][A-Za-z0-9_]*$`)
+
+// tptpName renders a functor, quoting names that are not lower words
+func tptpName(name string) string {
+	if tptpLowerWord.MatchString(name) {
+		return name
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name) + "'"
+}
+
+// tptpWriter renders formulas, giving bound variables upper-case names
+type tptpWriter struct {
+	variables map[string]string
+}
+
+func (w *tptpWriter) variable(name string) string {
+	if v, ok := w.variables[name]; ok {
+		return v
+	}
+	base := []rune(unsafeNameRunes.ReplaceAllString(name, "_"))
+	if len(base) == 0 || !unicode.IsLetter(base[0]) {
+		base = append([]rune("X"), base...)
+	}
+	base[0] = unicode.ToUpper(base[0])
+	candidate := string(base)
+	for i := 1; w.taken(candidate); i++ {
+		candidate = fmt.Sprintf("%s_%d", string(base), i)
+	}
+	w.variables[name] = candidate
+	return candidate
+}
+
+func (w *tptpWriter) taken(candidate string) bool {
+	for _, v := range w.variables {
+		if v == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *tptpWriter) formula(f *Formula, bound map[string]bool) string {
+	switch f.Op {
+	case FormulaAtom:
+		return tptpName(f.Name) + w.terms(f.Args, bound)
+	case FormulaTrue:
+		return "$true"
+	case FormulaFalse:
+		return "$false"
+	case FormulaNot:
+		return "~ " + w.operand(f.Left, bound)
+	case FormulaForAll, FormulaExists:
+		inner := make(map[string]bool, len(bound)+1)
+		for name := range bound {
+			inner[name] = true
+		}
+		inner[f.Var] = true
+		quantifier := "!"
+		if f.Op == FormulaExists {
+			quantifier = "?"
+		}
+		return fmt.Sprintf("%s [%s] : %s", quantifier, w.variable(f.Var), w.operand(f.Left, inner))
+	}
+	connective := map[FormulaOp]string{FormulaAnd: "&", FormulaOr: "|", FormulaImplies: "=>", FormulaIff: "<=>"}[f.Op]
+	return w.operand(f.Left, bound) + " " + connective + " " + w.operand(f.Right, bound)
+}
+
+// operand parenthesizes binary formulas
+func (w *tptpWriter) operand(f *Formula, bound map[string]bool) string {
+	if _, binary := formulaSymbols[f.Op]; binary {
+		return "(" + w.formula(f, bound) + ")"
+	}
+	return w.formula(f, bound)
+}
+
+func (w *tptpWriter) terms(terms []*Term, bound map[string]bool) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		if len(t.Args) == 0 && bound[t.Name] {
+			parts[i] = w.variable(t.Name)
+		} else {
+			parts[i] = tptpName(t.Name) + w.terms(t.Args, bound)
+		}
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+// tptpStatus is the SZS status for a theorem's proof status
+func tptpStatus(status TheoremStatus) string {
+	switch status {
+	case StatusProven:
+		return "Theorem"
+	case StatusRefuted:
+		return "CounterSatisfiable"
+	case StatusUndecidable:
+		return "ResourceOut"
+	}
+	return "Unknown"
+}
+
+// ExportTheoremTPTP writes a theorem as a FOF problem: one axiom per premise
+// and the conclusion as the conjecture, formalized as the first-order prover
+// reads them
+func ExportTheoremTPTP(theorem *SymbolicTheorem) (string, error) {
+	premises, conclusion, err := theoremFormulas(theorem)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	writeTPTPHeader(&b, theorem)
+	w := &tptpWriter{variables: map[string]string{}}
+	for i, premise := range premises {
+		fmt.Fprintf(&b, "fof(premise_%d, axiom, %s).\n", i+1, w.formula(premise, nil))
+	}
+	fmt.Fprintf(&b, "fof(conclusion, conjecture, %s).\n", w.formula(conclusion, nil))
+	return b.String(), nil
+}
+
+func writeTPTPHeader(b *strings.Builder, theorem *SymbolicTheorem) {
+	if theorem.Name != "" {
+		fmt.Fprintf(b, "%% File     : %s\n", strings.ReplaceAll(theorem.Name, " ", "_"))
+	}
+	if theorem.Status == StatusProven || theorem.Status == StatusRefuted {
+		fmt.Fprintf(b, "%% Status   : %s\n", tptpStatus(theorem.Status))
+	}
+}
+
+// ExportProofTPTP writes a proof as a TSTP derivation. Premises are axioms
+// and the conclusion the conjecture; every other step is an inference from
+// the steps it depends on, named by step number. Clause steps of a
+// refutation are CNF clauses.
+func ExportProofTPTP(theorem *SymbolicTheorem, proof *TheoremProof) (string, error) {
+	formulas, conclusion, err := proofFormulas(theorem, proof)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	name := strings.ReplaceAll(theorem.Name, " ", "_")
+	if name == "" {
+		name = "theorem"
+	}
+	fmt.Fprintf(&b, "%% SZS status %s for %s\n", tptpStatus(theorem.Status), name)
+	if proof.Explanation != "" {
+		fmt.Fprintf(&b, "%% %s\n", proof.Explanation)
+	}
+	fmt.Fprintf(&b, "%% SZS output start Proof for %s\n", name)
+	w := &tptpWriter{variables: map[string]string{}}
+	fmt.Fprintf(&b, "fof(conclusion, conjecture, %s).\n", w.formula(conclusion, nil))
+	for i, step := range proof.Steps {
+		w.variables = map[string]string{}
+		formula := formulas[i]
+		parents := make([]string, len(step.Dependencies))
+		for j, dependency := range step.Dependencies {
+			parents[j] = fmt.Sprint(dependency)
+		}
+		switch {
+		case step.Rule == "assumption":
+			fmt.Fprintf(&b, "fof(%d, axiom, %s).\n", step.StepNumber, w.formula(formula, nil))
+		case step.Rule == "negated_conclusion":
+			fmt.Fprintf(&b, "fof(%d, negated_conjecture, %s, inference(negate_conjecture, [status(cth)], [conclusion])).\n",
+				step.StepNumber, w.formula(formula, nil))
+		case clauseRules[step.Rule]:
+			status := "thm"
+			if step.Rule == "clausification" {
+				status = "esa"
+			}
+			fmt.Fprintf(&b, "cnf(%d, plain, %s, inference(%s, [status(%s)], [%s])).\n",
+				step.StepNumber, w.clause(formula), step.Rule, status, strings.Join(parents, ","))
+		default:
+			role := "plain"
+			if i == len(proof.Steps)-1 && proof.IsValid {
+				role = "theorem"
+			}
+			fmt.Fprintf(&b, "fof(%d, %s, %s, inference(%s, [status(thm)], [%s])).\n",
+				step.StepNumber, role, w.formula(formula, nil), tptpName(step.Rule), strings.Join(parents, ","))
+		}
+	}
+	fmt.Fprintf(&b, "%% SZS output end Proof for %s\n", name)
+	return b.String(), nil
+}
+
+// clause renders a universally closed clause without its quantifiers
+func (w *tptpWriter) clause(f *Formula) string {
+	bound := map[string]bool{}
+	for f.Op == FormulaForAll {
+		bound[f.Var] = true
+		f = f.Left
+	}
+	if f.Op == FormulaFalse {
+		return "$false"
+	}
+	var literals []string
+	var collect func(f *Formula)
+	collect = func(f *Formula) {
+		if f.Op == FormulaOr {
+			collect(f.Left)
+			collect(f.Right)
+			return
+		}
+		literals = append(literals, w.formula(f, bound))
+	}
+	collect(f)
+	return strings.Join(literals, " | ")
+}
+
+// ExportConstraintsTPTP writes the linear constraints among constraintIDs as
+// a TFF problem: a type declaration per symbol ($int for integers, $rat for
+// reals, which agree on linear satisfiability) and an axiom per constraint.
+// Constraints outside linear arithmetic are listed in comments. When result
+// decided every constraint, its verdict is the declared status.
+func (sr *SymbolicReasoner) ExportConstraintsTPTP(constraintIDs []string, result *ConsistencyResult) (string, error) {
+	export, err := sr.prepareLinearExport(constraintIDs)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if result != nil && len(result.Unchecked) == 0 && len(export.skipped) == 0 {
+		status := "Satisfiable"
+		if !result.IsConsistent {
+			status = "Unsatisfiable"
+		}
+		fmt.Fprintf(&b, "%% Status   : %s\n", status)
+	}
+	for _, c := range export.skipped {
+		fmt.Fprintf(&b, "%% %s is outside linear arithmetic and not exported: %s\n", c.ID, c.Expression)
+	}
+	for _, name := range export.symbols {
+		sort := "$rat"
+		if export.integers[name] {
+			sort = "$int"
+		}
+		fmt.Fprintf(&b, "tff(%s, type, %s: %s).\n", tptpName(name+"_type"), tptpName(name), sort)
+	}
+	for i, c := range export.constraints {
+		parts := make([]string, 0, len(export.rows[i]))
+		for _, row := range export.rows[i] {
+			parts = append(parts, tptpRow(row, export.integers))
+		}
+		formula := "$true"
+		if len(parts) > 0 {
+			formula = strings.Join(parts, " & ")
+		}
+		fmt.Fprintf(&b, "tff(%s, axiom, %s).\n", tptpName(strings.ReplaceAll(c.ID, "-", "_")), formula)
+	}
+	return b.String(), nil
+}
+
+// tptpRow renders Σ aᵢxᵢ ⋈ b. Rows over integers are $int arithmetic; other
+// rows are $rat arithmetic with integer symbols converted by $to_rat.
+func tptpRow(row linearRow, integers map[string]bool) string {
+	row, ok := exportRow(row, integers)
+	if !ok {
+		return "$false"
+	}
+	row, reversed := orientRow(row)
+	integral := len(row.coeffs) > 0 && allIntegers(row.coeffs, integers)
+	number := func(r *big.Rat) string {
+		negative, abs := ratParts(r)
+		text := abs.RatString()
+		if !integral && abs.IsInt() {
+			text += "/1"
+		}
+		if negative {
+			return "-" + text
+		}
+		return text
+	}
+
+	var sum string
+	for _, name := range sortedNames(row.coeffs) {
+		variable := tptpName(name)
+		if !integral && integers[name] {
+			variable = "$to_rat(" + variable + ")"
+		}
+		term := variable
+		switch a := row.coeffs[name]; {
+		case a.Cmp(big.NewRat(-1, 1)) == 0:
+			term = "$uminus(" + variable + ")"
+		case a.Cmp(big.NewRat(1, 1)) != 0:
+			term = fmt.Sprintf("$product(%s,%s)", number(a), variable)
+		}
+		if sum == "" {
+			sum = term
+		} else {
+			sum = fmt.Sprintf("$sum(%s,%s)", sum, term)
+		}
+	}
+	if sum == "" {
+		sum = number(new(big.Rat))
+	}
+	predicate := map[linearOp]string{linearLE: "$lesseq", linearLT: "$less"}[row.op]
+	if reversed {
+		predicate = map[linearOp]string{linearLE: "$greatereq", linearLT: "$greater"}[row.op]
+	}
+	if row.op == linearEQ {
+		return fmt.Sprintf("%s = %s", sum, number(row.bound))
+	}
+	return fmt.Sprintf("%s(%s,%s)", predicate, sum, number(row.bound))
+}